@@ -0,0 +1,478 @@
+// Package adr syncs decisions with Architecture Decision Records: numbered
+// markdown files in the Nygard (adr-tools) or MADR layout.
+package adr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Record is one ADR file. Fields that map to a decision are parsed out;
+// everything else (front matter, extra header lines, sections such as
+// "Consequences" or "Considered Options") is kept so rewriting the file
+// leaves it intact.
+type Record struct {
+	Number       int
+	Title        string
+	Path         string // file name inside the ADR directory
+	Status       string // as written, e.g. "Accepted"
+	Date         string
+	Scope        string // from a "Scope:" line; empty when not stated
+	ScopePath    string
+	Context      string
+	Decision     string
+	Rationale    string
+	Outcome      string // successful, failed, mixed, or "" when not stated
+	OutcomeNote  string
+	Supersedes   []int
+	SupersededBy []int
+
+	numberedTitle bool // title reads "# 7. Title" rather than "# Title"
+	frontMatter   []string
+	header        []string
+	sections      []section
+}
+
+type section struct {
+	heading string
+	lead    string // text before the first "###" subsection
+	rest    string // subsections, kept verbatim
+}
+
+// Section keys for the parts of an ADR that map to decision fields, in the
+// order new sections are written.
+const (
+	keyStatus    = "status"
+	keyContext   = "context"
+	keyDecision  = "decision"
+	keyRationale = "rationale"
+	keyOutcome   = "outcome"
+)
+
+var sectionOrder = []string{keyStatus, keyContext, keyDecision, keyRationale, keyOutcome}
+
+var sectionHeadings = map[string]string{
+	keyStatus:    "Status",
+	keyContext:   "Context",
+	keyDecision:  "Decision",
+	keyRationale: "Rationale",
+	keyOutcome:   "Outcome",
+}
+
+// sectionKeys maps the headings used by adr-tools and MADR to section keys.
+var sectionKeys = map[string]string{
+	"status":                        keyStatus,
+	"context":                       keyContext,
+	"context and problem statement": keyContext,
+	"decision":                      keyDecision,
+	"decision outcome":              keyDecision,
+	"rationale":                     keyRationale,
+	"outcome":                       keyOutcome,
+}
+
+var (
+	fileNameRe    = regexp.MustCompile(`^(\d+)-.+\.md$`)
+	titleNumberRe = regexp.MustCompile(`^(?:(?i:ADR)[- ]?)?0*(\d+)[.:]\s+(.+)$`)
+	headerFieldRe = regexp.MustCompile(`^(?:[*-]\s+)?(Status|Date|Scope):\s*(.*)$`)
+	scopeValueRe  = regexp.MustCompile("^(file|room)\\s+`?([^`]+?)`?$")
+	supersedeRe   = regexp.MustCompile(`(?i)\b(supersedes|superseded by)\b\D*?(\d+)`)
+)
+
+// Parse reads an ADR from markdown. name is the file name, which supplies
+// the ADR number when the title does not.
+func Parse(name string, data []byte) (*Record, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	r := &Record{Path: name}
+
+	i := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i = 1; i < len(lines) && strings.TrimSpace(lines[i]) != "---"; i++ {
+			r.frontMatter = append(r.frontMatter, lines[i])
+			key, value, ok := strings.Cut(lines[i], ":")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			switch strings.TrimSpace(key) {
+			case "status":
+				r.parseStatus(value)
+			case "date":
+				r.Date = value
+			}
+		}
+		i++
+	}
+
+	for ; i < len(lines); i++ {
+		if title, ok := strings.CutPrefix(lines[i], "# "); ok {
+			r.setTitle(strings.TrimSpace(title))
+			i++
+			break
+		}
+	}
+	if r.Title == "" {
+		return nil, errors.New("no title heading")
+	}
+	if m := fileNameRe.FindStringSubmatch(name); m != nil {
+		r.Number, _ = strconv.Atoi(m[1])
+	}
+
+	// Header lines sit between the title and the first section.
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "## "); i++ {
+		m := headerFieldRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			r.header = append(r.header, lines[i])
+			continue
+		}
+		switch m[1] {
+		case "Status":
+			r.parseStatus(m[2])
+		case "Date":
+			r.Date = m[2]
+		case "Scope":
+			if sm := scopeValueRe.FindStringSubmatch(m[2]); sm != nil {
+				r.Scope, r.ScopePath = sm[1], sm[2]
+			} else if m[2] == "palace" {
+				r.Scope = m[2]
+			}
+		}
+	}
+	r.header = trimBlankLines(r.header)
+
+	var cur *section
+	var body []string
+	inFence := false
+	flush := func() {
+		if cur != nil {
+			cur.lead, cur.rest = splitSubsections(body)
+			r.sections = append(r.sections, *cur)
+		}
+	}
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if heading, ok := strings.CutPrefix(line, "## "); ok && !inFence {
+			flush()
+			cur = &section{heading: strings.TrimSpace(heading)}
+			body = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	for _, s := range r.sections {
+		switch sectionKeys[strings.ToLower(s.heading)] {
+		case keyStatus:
+			r.parseStatus(s.lead)
+		case keyContext:
+			r.Context = s.lead
+		case keyDecision:
+			r.Decision = s.lead
+		case keyRationale:
+			r.Rationale = s.lead
+		case keyOutcome:
+			r.parseOutcome(s.lead)
+		}
+	}
+	return r, nil
+}
+
+// ParseFile reads an ADR file.
+func ParseFile(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Parse(filepath.Base(path), data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// List reads the numbered ADR files in dir, ordered by number. Files that
+// do not parse are skipped. A missing directory has no records.
+func List(dir string) ([]*Record, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, e := range entries {
+		if e.IsDir() || !fileNameRe.MatchString(e.Name()) {
+			continue
+		}
+		r, err := ParseFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Number < records[j].Number })
+	return records, nil
+}
+
+// New returns an empty adr-tools style record.
+func New(number int, title string) *Record {
+	return &Record{
+		Number:        number,
+		Title:         title,
+		Path:          FileName(number, title),
+		numberedTitle: true,
+	}
+}
+
+// FileName returns the adr-tools file name for an ADR, e.g.
+// "0007-use-sqlite-for-memory.md".
+func FileName(number int, title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+		if sb.Len() >= 60 {
+			break
+		}
+	}
+	return fmt.Sprintf("%04d-%s.md", number, strings.Trim(sb.String(), "-"))
+}
+
+// Render writes the record as markdown. link formats a reference to another
+// ADR by number, e.g. "[3. Use Redis](0003-use-redis.md)".
+func (r *Record) Render(link func(number int) string) []byte {
+	var b bytes.Buffer
+
+	if len(r.frontMatter) > 0 {
+		b.WriteString("---\n")
+		for _, line := range r.frontMatter {
+			if key, _, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "status" {
+				line = "status: " + r.frontMatterStatus()
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("---\n\n")
+	}
+
+	if r.numberedTitle {
+		fmt.Fprintf(&b, "# %d. %s\n\n", r.Number, r.Title)
+	} else {
+		fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	}
+
+	var header []string
+	if r.Date != "" && len(r.frontMatter) == 0 {
+		header = append(header, "Date: "+r.Date)
+	}
+	if r.Scope != "" && r.Scope != "palace" {
+		header = append(header, fmt.Sprintf("Scope: %s `%s`", r.Scope, r.ScopePath))
+	}
+	if len(header) > 0 && len(r.header) > 0 {
+		header = append(header, "")
+	}
+	header = append(header, r.header...)
+	if len(header) > 0 {
+		b.WriteString(strings.Join(header, "\n") + "\n\n")
+	}
+
+	for i, s := range r.renderSections(link) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + s.heading + "\n")
+		if s.lead != "" {
+			b.WriteString("\n" + s.lead + "\n")
+		}
+		if s.rest != "" {
+			b.WriteString("\n" + s.rest + "\n")
+		}
+	}
+	return b.Bytes()
+}
+
+// renderSections replaces the managed sections' text with the record's
+// fields, dropping managed sections that became empty and adding missing
+// ones after the closest managed section that precedes them.
+func (r *Record) renderSections(link func(int) string) []section {
+	values := map[string]string{
+		keyContext:   r.Context,
+		keyDecision:  r.Decision,
+		keyRationale: r.Rationale,
+		keyOutcome:   r.outcomeText(),
+	}
+	if len(r.frontMatter) == 0 {
+		values[keyStatus] = r.statusText(link)
+	}
+
+	var out []section
+	seen := map[string]bool{}
+	for _, s := range r.sections {
+		key := sectionKeys[strings.ToLower(s.heading)]
+		if key == "" || (key == keyStatus && len(r.frontMatter) > 0) {
+			out = append(out, s)
+			continue
+		}
+		seen[key] = true
+		s.lead = values[key]
+		if s.lead != "" || s.rest != "" {
+			out = append(out, s)
+		}
+	}
+
+	for rank, key := range sectionOrder {
+		if seen[key] || values[key] == "" {
+			continue
+		}
+		pos := 0
+		for i, s := range out {
+			if k := sectionKeys[strings.ToLower(s.heading)]; k != "" && rankOf(k) < rank {
+				pos = i + 1
+			}
+		}
+		s := section{heading: sectionHeadings[key], lead: values[key]}
+		out = append(out[:pos], append([]section{s}, out[pos:]...)...)
+	}
+	return out
+}
+
+func rankOf(key string) int {
+	for i, k := range sectionOrder {
+		if k == key {
+			return i
+		}
+	}
+	return len(sectionOrder)
+}
+
+func (r *Record) statusText(link func(int) string) string {
+	lines := []string{r.Status}
+	if r.Status == "" {
+		lines = nil
+	}
+	for _, n := range r.Supersedes {
+		lines = append(lines, "Supersedes "+link(n))
+	}
+	for _, n := range r.SupersededBy {
+		lines = append(lines, "Superseded by "+link(n))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// frontMatterStatus follows MADR, which writes "superseded by ADR-0005" as
+// the status value.
+func (r *Record) frontMatterStatus() string {
+	if len(r.SupersededBy) > 0 {
+		return fmt.Sprintf(`"superseded by ADR-%04d"`, r.SupersededBy[0])
+	}
+	return strings.ToLower(r.Status)
+}
+
+func (r *Record) outcomeText() string {
+	if r.Outcome == "" {
+		return r.OutcomeNote
+	}
+	text := strings.ToUpper(r.Outcome[:1]) + r.Outcome[1:]
+	if r.OutcomeNote != "" {
+		text += "\n\n" + r.OutcomeNote
+	}
+	return text
+}
+
+func (r *Record) setTitle(title string) {
+	if m := titleNumberRe.FindStringSubmatch(title); m != nil {
+		r.Number, _ = strconv.Atoi(m[1])
+		r.Title = m[2]
+		r.numberedTitle = true
+		return
+	}
+	r.Title = title
+}
+
+// parseStatus reads a status value or section. adr-tools replaces the
+// status with "Superseded by [...]" when an ADR is superseded.
+func (r *Record) parseStatus(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m := supersedeRe.FindStringSubmatch(line)
+		if m == nil || !strings.EqualFold(line[:len(m[1])], m[1]) {
+			if r.Status == "" {
+				r.Status = strings.Trim(strings.Fields(line)[0], "*_.,")
+			}
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		if strings.EqualFold(m[1], "supersedes") {
+			r.Supersedes = appendUnique(r.Supersedes, n)
+			continue
+		}
+		r.SupersededBy = appendUnique(r.SupersededBy, n)
+		if r.Status == "" {
+			r.Status = "Superseded"
+		}
+	}
+}
+
+func (r *Record) parseOutcome(text string) {
+	first, rest, _ := strings.Cut(text, "\n")
+	word := strings.ToLower(strings.Trim(strings.TrimSpace(first), "*_.:"))
+	switch word {
+	case "successful", "failed", "mixed":
+		r.Outcome = word
+		r.OutcomeNote = strings.TrimSpace(rest)
+	default:
+		r.OutcomeNote = text
+	}
+}
+
+func splitSubsections(lines []string) (lead, rest string) {
+	for i, line := range lines {
+		if strings.HasPrefix(line, "### ") {
+			return joinTrimmed(lines[:i]), joinTrimmed(lines[i:])
+		}
+	}
+	return joinTrimmed(lines), ""
+}
+
+func joinTrimmed(lines []string) string {
+	return strings.Join(trimBlankLines(lines), "\n")
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func appendUnique(list []int, n int) []int {
+	for _, v := range list {
+		if v == n {
+			return list
+		}
+	}
+	return append(list, n)
+}
@@ -0,0 +1,148 @@
+package adr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const nygardADR = `# 2. Use SQLite for the memory store
+
+Date: 2024-03-01
+
+## Status
+
+Accepted
+
+Supersedes [1. Use BoltDB](0001-use-boltdb.md)
+
+## Context
+
+The store lives in ` + "`memory/memory.go`" + ` and needs full-text search.
+
+## Decision
+
+We will use SQLite with FTS5.
+
+## Consequences
+
+CGO-free builds need modernc.org/sqlite.
+
+## Outcome
+
+Successful
+
+Search latency dropped.
+`
+
+const madrADR = `---
+status: "superseded by ADR-0005"
+date: 2024-04-02
+deciders: alice, bob
+---
+
+# Use slog for logging
+
+## Context and Problem Statement
+
+We log with three different libraries.
+
+## Considered Options
+
+* logrus
+* slog
+
+## Decision Outcome
+
+Chosen option: "slog", because it is in the standard library.
+
+### Consequences
+
+* Good, because there is one less dependency.
+`
+
+func TestParseNygard(t *testing.T) {
+	r, err := Parse("0002-use-sqlite-for-the-memory-store.md", []byte(nygardADR))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if r.Number != 2 || r.Title != "Use SQLite for the memory store" || r.Date != "2024-03-01" {
+		t.Errorf("header = %d %q %q", r.Number, r.Title, r.Date)
+	}
+	if r.Status != "Accepted" || !reflect.DeepEqual(r.Supersedes, []int{1}) || r.SupersededBy != nil {
+		t.Errorf("status = %q, supersedes %v, superseded by %v", r.Status, r.Supersedes, r.SupersededBy)
+	}
+	if r.Decision != "We will use SQLite with FTS5." || !strings.HasPrefix(r.Context, "The store lives") {
+		t.Errorf("decision = %q, context = %q", r.Decision, r.Context)
+	}
+	if r.Outcome != "successful" || r.OutcomeNote != "Search latency dropped." {
+		t.Errorf("outcome = %q %q", r.Outcome, r.OutcomeNote)
+	}
+}
+
+func TestParseMADR(t *testing.T) {
+	r, err := Parse("0003-use-slog.md", []byte(madrADR))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if r.Number != 3 || r.Title != "Use slog for logging" || r.Date != "2024-04-02" {
+		t.Errorf("header = %d %q %q", r.Number, r.Title, r.Date)
+	}
+	if r.Status != "Superseded" || !reflect.DeepEqual(r.SupersededBy, []int{5}) {
+		t.Errorf("status = %q, superseded by %v", r.Status, r.SupersededBy)
+	}
+	if !strings.HasPrefix(r.Decision, `Chosen option: "slog"`) || strings.Contains(r.Decision, "Consequences") {
+		t.Errorf("decision = %q", r.Decision)
+	}
+	if r.Context != "We log with three different libraries." {
+		t.Errorf("context = %q", r.Context)
+	}
+}
+
+func TestParseNoTitle(t *testing.T) {
+	if _, err := Parse("0001-x.md", []byte("Just text\n")); err == nil {
+		t.Error("expected error for a file without a title")
+	}
+}
+
+func TestRenderUnchanged(t *testing.T) {
+	link := func(n int) string { return "[1. Use BoltDB](0001-use-boltdb.md)" }
+	for name, text := range map[string]string{"0002-a.md": nygardADR, "0003-b.md": madrADR} {
+		r, err := Parse(name, []byte(text))
+		if err != nil {
+			t.Fatalf("Parse(%s) error: %v", name, err)
+		}
+		if got := string(r.Render(link)); got != text {
+			t.Errorf("Render(%s) changed the file:\n%s", name, got)
+		}
+	}
+}
+
+func TestRenderKeepsUnmanagedSections(t *testing.T) {
+	r, _ := Parse("0003-use-slog.md", []byte(madrADR))
+	r.Decision = "Use slog everywhere."
+	r.Rationale = "It is in the standard library."
+	r.Outcome = "mixed"
+
+	out := string(r.Render(func(n int) string { return "" }))
+	for _, want := range []string{
+		"deciders: alice, bob",
+		"## Considered Options\n\n* logrus",
+		"## Decision Outcome\n\nUse slog everywhere.\n\n### Consequences",
+		"## Rationale\n\nIt is in the standard library.",
+		"## Outcome\n\nMixed\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## Rationale") < strings.Index(out, "## Decision Outcome") {
+		t.Errorf("Rationale placed before the decision:\n%s", out)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if got := FileName(7, "Use SQLite (with FTS5) for memory!"); got != "0007-use-sqlite-with-fts5-for-memory.md" {
+		t.Errorf("FileName() = %q", got)
+	}
+}
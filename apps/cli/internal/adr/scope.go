@@ -0,0 +1,107 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+)
+
+// roomMentionRe finds "the auth room" and "`auth` room".
+var roomMentionRe = regexp.MustCompile("(?i)`?([\\w-]+)`? room\\b")
+
+// InferScope guesses a decision's scope from the workspace paths and rooms
+// an ADR mentions. A single file gives file scope; paths and rooms that all
+// belong to one room give room scope; anything else stays palace-wide.
+func InferScope(root, text string, rooms []model.Room) (scope, scopePath string) {
+	files, dirs := mentionedPaths(root, text)
+	if len(files) == 1 && len(dirs) == 0 {
+		return string(memory.ScopeFile), files[0]
+	}
+
+	found := map[string]bool{}
+	for _, m := range roomMentionRe.FindAllStringSubmatch(text, -1) {
+		for i := range rooms {
+			if strings.EqualFold(rooms[i].Name, m[1]) {
+				found[rooms[i].Name] = true
+			}
+		}
+	}
+	for _, p := range append(files, dirs...) {
+		room := roomFor(p, rooms)
+		if room == "" {
+			return string(memory.ScopePalace), ""
+		}
+		found[room] = true
+	}
+	if len(found) == 1 {
+		for name := range found {
+			return string(memory.ScopeRoom), name
+		}
+	}
+	return string(memory.ScopePalace), ""
+}
+
+// mentionedPaths returns the workspace-relative files and directories that
+// appear in text, in the order first mentioned.
+func mentionedPaths(root, text string) (files, dirs []string) {
+	seen := map[string]bool{}
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\n' || r == '\t' || strings.ContainsRune("()[]{}<>\"'`,;", r)
+	}) {
+		p := strings.TrimRight(token, ".:!?*_")
+		p = strings.TrimLeft(strings.TrimPrefix(p, "./"), "*_")
+		if i := strings.Index(p, ":"); i > 0 {
+			p = p[:i] // drop ":42" line suffixes
+		}
+		if !strings.ContainsAny(p, "/.") {
+			continue
+		}
+		p = strings.TrimSuffix(p, "/")
+		if p == "" || seen[p] || strings.Contains(p, "..") || filepath.IsAbs(p) || strings.HasPrefix(p, ".") {
+			continue
+		}
+		seen[p] = true
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			dirs = append(dirs, p)
+		} else {
+			files = append(files, p)
+		}
+	}
+	return files, dirs
+}
+
+// roomFor returns the room whose entry points a path leads to or contains.
+// When several rooms match, the one with the most specific entry point wins.
+func roomFor(path string, rooms []model.Room) string {
+	type match struct {
+		room  string
+		depth int
+	}
+	var matches []match
+	for i := range rooms {
+		for _, ep := range rooms[i].EntryPoints {
+			ep = strings.TrimSuffix(filepath.ToSlash(ep), "/")
+			dir := filepath.ToSlash(filepath.Dir(ep))
+			switch {
+			case path == ep,
+				dir != "." && (path == dir || strings.HasPrefix(path, dir+"/")),
+				strings.HasPrefix(ep, path+"/"):
+				matches = append(matches, match{rooms[i].Name, strings.Count(ep, "/")})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].depth > matches[j].depth })
+	return matches[0].room
+}
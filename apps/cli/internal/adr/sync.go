@@ -0,0 +1,458 @@
+package adr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+)
+
+// DefaultDir is where ADRs live when the workspace has no .adr-dir file.
+const DefaultDir = "docs/adr"
+
+// Source marks decisions created from ADR files.
+const Source = "adr"
+
+// Action describes what a sync did with one ADR.
+type Action string
+
+// Sync actions.
+const (
+	ActionImported  Action = "imported"  // new ADR file became a decision
+	ActionUpdated   Action = "updated"   // decision updated from its changed file
+	ActionExported  Action = "exported"  // new decision written as an ADR file
+	ActionRewritten Action = "rewritten" // file rewritten from its changed decision
+)
+
+// Change is one ADR that a sync touched.
+type Change struct {
+	Action     Action
+	Number     int
+	Title      string
+	Path       string // relative to the workspace root
+	DecisionID string
+	Conflict   bool // both sides changed; the file won
+}
+
+// Result summarizes a sync.
+type Result struct {
+	Dir       string // ADR directory relative to the workspace root
+	Changes   []Change
+	Unchanged int
+	Links     int // supersedes links added from ADR files
+}
+
+// Options configures a sync.
+type Options struct {
+	Dir   string       // ADR directory, relative to the workspace root; see ResolveDir
+	Rooms []model.Room // used to infer room scope for imported ADRs
+}
+
+// ResolveDir returns the ADR directory for a workspace: dir when set, else
+// the adr-tools .adr-dir file, else DefaultDir.
+func ResolveDir(root, dir string) string {
+	if dir != "" {
+		return dir
+	}
+	if data, err := os.ReadFile(filepath.Join(root, ".adr-dir")); err == nil {
+		if d := strings.TrimSpace(string(data)); d != "" {
+			return d
+		}
+	}
+	return DefaultDir
+}
+
+// entry is one decision-to-file pair during a sync.
+type entry struct {
+	rec      *Record
+	dec      *memory.Decision
+	path     string // relative to the workspace root
+	fromFile bool   // decision was created or updated from the file
+	render   bool   // file must be written from the decision
+}
+
+// Sync reconciles the decisions in mem with the ADR files in opts.Dir.
+//
+// New ADR files become decisions and new decisions become numbered ADR
+// files. For pairs synced before, the side that changed since the last
+// sync overwrites the other; when both changed, the file wins. Status,
+// outcome and supersedes links are mapped both ways. A second sync with
+// nothing changed in between does nothing.
+func Sync(root string, mem *memory.Memory, opts Options) (*Result, error) {
+	dirRel := filepath.ToSlash(filepath.Clean(ResolveDir(root, opts.Dir)))
+	dir := filepath.Join(root, filepath.FromSlash(dirRel))
+	result := &Result{Dir: dirRel}
+
+	mappings, err := mem.GetADRRecords()
+	if err != nil {
+		return nil, err
+	}
+	byPath := map[string]memory.ADRRecord{}
+	for _, m := range mappings {
+		byPath[m.Path] = m
+	}
+
+	records, err := List(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dirRel, err)
+	}
+
+	var entries []*entry
+	byNumber := map[int]*entry{}
+	byDecision := map[string]*entry{}
+	next := 1
+	add := func(e *entry) {
+		entries = append(entries, e)
+		byNumber[e.rec.Number] = e
+		byDecision[e.dec.ID] = e
+		if e.rec.Number >= next {
+			next = e.rec.Number + 1
+		}
+	}
+
+	// Files first: import new ones and apply edits made since the last sync.
+	for _, rec := range records {
+		rel := dirRel + "/" + rec.Path
+		data, err := os.ReadFile(filepath.Join(dir, rec.Path))
+		if err != nil {
+			return nil, err
+		}
+		e := &entry{rec: rec, path: rel}
+		m, mapped := byPath[rel]
+		delete(byPath, rel)
+		if mapped {
+			if e.dec, err = mem.GetDecision(m.DecisionID); err != nil {
+				// The decision was deleted; the file brings it back.
+				if err := mem.DeleteADRRecord(m.DecisionID); err != nil {
+					return nil, err
+				}
+				mapped = false
+			}
+		}
+
+		switch {
+		case !mapped:
+			dec := toDecision(root, rec, opts.Rooms)
+			if dec.ID, err = mem.AddDecision(dec); err != nil {
+				return nil, fmt.Errorf("import %s: %w", rel, err)
+			}
+			e.dec, e.fromFile = &dec, true
+			result.add(ActionImported, e, false)
+		case hashBytes(data) != m.FileHash:
+			dec := toDecision(root, rec, opts.Rooms)
+			dec.ID = e.dec.ID
+			if rec.Scope == "" && e.dec.Scope != string(memory.ScopePalace) && dec.Scope == string(memory.ScopePalace) {
+				dec.Scope, dec.ScopePath = e.dec.Scope, e.dec.ScopePath // nothing inferred; keep what we had
+			}
+			conflict := decisionHash(mem, e.dec) != m.DecisionHash
+			if err := mem.UpdateDecisionFromADR(dec); err != nil {
+				return nil, fmt.Errorf("update from %s: %w", rel, err)
+			}
+			e.dec, e.fromFile = &dec, true
+			result.add(ActionUpdated, e, conflict)
+		case decisionHash(mem, e.dec) != m.DecisionHash:
+			e.render = true
+			result.add(ActionRewritten, e, false)
+		default:
+			result.Unchanged++
+		}
+		add(e)
+	}
+
+	// Mapped decisions whose files were removed are written again.
+	for _, m := range mappings {
+		if _, ok := byPath[m.Path]; !ok {
+			continue
+		}
+		dec, err := mem.GetDecision(m.DecisionID)
+		if err != nil {
+			if err := mem.DeleteADRRecord(m.DecisionID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rec := New(m.Number, titleFor(dec.Content))
+		rec.Path = filepath.Base(m.Path)
+		e := &entry{rec: rec, dec: dec, path: m.Path, render: true}
+		result.add(ActionRewritten, e, false)
+		add(e)
+	}
+
+	// Supersedes links written in files. Links are only added here; removing
+	// one is done in palace and flows back to the files.
+	for _, e := range entries {
+		if !e.fromFile {
+			continue
+		}
+		for _, n := range e.rec.Supersedes {
+			if old := byNumber[n]; old != nil && old != e {
+				added, err := ensureSupersedes(mem, e.dec.ID, old.dec.ID)
+				if err != nil {
+					return nil, err
+				}
+				result.Links += added
+			}
+		}
+		for _, n := range e.rec.SupersededBy {
+			if newer := byNumber[n]; newer != nil && newer != e {
+				added, err := ensureSupersedes(mem, newer.dec.ID, e.dec.ID)
+				if err != nil {
+					return nil, err
+				}
+				result.Links += added
+			}
+		}
+	}
+
+	// Decisions without a file become new ADRs, oldest first.
+	decisions, err := mem.GetDecisionsWithAuthority("", "", "", "", 0, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(decisions, func(i, j int) bool { return decisions[i].CreatedAt.Before(decisions[j].CreatedAt) })
+	for i := range decisions {
+		dec := &decisions[i]
+		if byDecision[dec.ID] != nil {
+			continue
+		}
+		rec := New(next, titleFor(dec.Content))
+		e := &entry{rec: rec, dec: dec, path: dirRel + "/" + rec.Path, render: true}
+		result.add(ActionExported, e, false)
+		add(e)
+	}
+
+	link := func(n int) string {
+		if e := byNumber[n]; e != nil {
+			return fmt.Sprintf("[%d. %s](%s)", n, e.rec.Title, filepath.Base(e.path))
+		}
+		return fmt.Sprintf("ADR-%04d", n)
+	}
+	for _, e := range entries {
+		if !e.render {
+			continue
+		}
+		supersedes, supersededBy := supersedeNumbers(mem, e.dec.ID, byDecision)
+		fromDecision(e.rec, e.dec, supersedes, supersededBy)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(e.path)), e.rec.Render(link), 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record both sides as they are now, so the next sync starts clean.
+	now := time.Now().UTC()
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(e.path)))
+		if err != nil {
+			return nil, err
+		}
+		dec, err := mem.GetDecision(e.dec.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := mem.SaveADRRecord(memory.ADRRecord{
+			DecisionID:   dec.ID,
+			Path:         e.path,
+			Number:       e.rec.Number,
+			FileHash:     hashBytes(data),
+			DecisionHash: decisionHash(mem, dec),
+			SyncedAt:     now,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *Result) add(action Action, e *entry, conflict bool) {
+	r.Changes = append(r.Changes, Change{
+		Action:     action,
+		Number:     e.rec.Number,
+		Title:      e.rec.Title,
+		Path:       e.path,
+		DecisionID: e.dec.ID,
+		Conflict:   conflict,
+	})
+}
+
+// toDecision maps an ADR to a decision. Accepted ADRs are approved
+// decisions; proposed ones stay proposed.
+func toDecision(root string, rec *Record, rooms []model.Room) memory.Decision {
+	status, authority := decisionStatus(rec.Status)
+	dec := memory.Decision{
+		Content:     rec.Decision,
+		Rationale:   rec.Rationale,
+		Context:     rec.Context,
+		Status:      status,
+		Authority:   authority,
+		Outcome:     rec.Outcome,
+		OutcomeNote: rec.OutcomeNote,
+		Scope:       rec.Scope,
+		ScopePath:   rec.ScopePath,
+		Source:      Source,
+	}
+	if dec.Content == "" {
+		dec.Content = rec.Title
+	}
+	if dec.Outcome == "" {
+		dec.Outcome = memory.DecisionOutcomeUnknown
+	}
+	if dec.Scope == "" {
+		text := strings.Join([]string{rec.Title, rec.Context, rec.Decision, rec.Rationale}, "\n")
+		dec.Scope, dec.ScopePath = InferScope(root, text, rooms)
+	}
+	if t, err := time.Parse("2006-01-02", rec.Date); err == nil {
+		dec.CreatedAt = t
+	}
+	return dec
+}
+
+// fromDecision copies a decision onto an ADR, keeping the title, date and
+// status wording already in the file when they still fit.
+func fromDecision(rec *Record, dec *memory.Decision, supersedes, supersededBy []int) {
+	if rec.Title == "" {
+		rec.Title = titleFor(dec.Content)
+	}
+	if rec.Date == "" && len(rec.frontMatter) == 0 {
+		rec.Date = dec.CreatedAt.Format("2006-01-02")
+	}
+	if status, authority := decisionStatus(rec.Status); rec.Status == "" || status != dec.Status || authority != dec.Authority {
+		rec.Status = adrStatus(dec)
+	}
+	rec.Context = dec.Context
+	rec.Decision = dec.Content
+	rec.Rationale = dec.Rationale
+	rec.Outcome = dec.Outcome
+	if rec.Outcome == memory.DecisionOutcomeUnknown {
+		rec.Outcome = ""
+	}
+	rec.OutcomeNote = dec.OutcomeNote
+	rec.Scope, rec.ScopePath = dec.Scope, dec.ScopePath
+	rec.Supersedes, rec.SupersededBy = supersedes, supersededBy
+}
+
+// decisionStatus maps an ADR status to a decision status and authority.
+func decisionStatus(status string) (string, string) {
+	approved := string(memory.AuthorityApproved)
+	switch strings.ToLower(status) {
+	case "accepted", "approved":
+		return memory.DecisionStatusActive, approved
+	case "superseded":
+		return memory.DecisionStatusSuperseded, approved
+	case "deprecated", "rejected", "reversed":
+		return memory.DecisionStatusReversed, approved
+	default: // proposed, draft, or unknown
+		return memory.DecisionStatusActive, string(memory.AuthorityProposed)
+	}
+}
+
+// adrStatus maps a decision's status and authority to an ADR status.
+func adrStatus(dec *memory.Decision) string {
+	switch {
+	case dec.Status == memory.DecisionStatusSuperseded:
+		return "Superseded"
+	case dec.Status == memory.DecisionStatusReversed:
+		return "Deprecated"
+	case memory.IsAuthoritative(memory.Authority(dec.Authority)):
+		return "Accepted"
+	default:
+		return "Proposed"
+	}
+}
+
+// titleFor shortens a decision's content to an ADR title: its first line,
+// cut at a word boundary.
+func titleFor(content string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	title = strings.TrimRight(strings.TrimSpace(title), ".")
+	if runes := []rune(title); len(runes) > 72 {
+		title = string(runes[:72])
+		if i := strings.LastIndex(title, " "); i > 40 {
+			title = title[:i]
+		}
+	}
+	return title
+}
+
+// ensureSupersedes links newer to older unless the link exists.
+func ensureSupersedes(mem *memory.Memory, newer, older string) (int, error) {
+	links, err := mem.GetLinksForSource(newer)
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range links {
+		if l.Relation == memory.RelationSupersedes && l.TargetID == older {
+			return 0, nil
+		}
+	}
+	if _, err := mem.AddLink(memory.Link{
+		SourceID:   newer,
+		SourceKind: memory.TargetKindDecision,
+		TargetID:   older,
+		TargetKind: memory.TargetKindDecision,
+		Relation:   memory.RelationSupersedes,
+	}); err != nil {
+		return 0, fmt.Errorf("link %s supersedes %s: %w", newer, older, err)
+	}
+	return 1, nil
+}
+
+// supersedeLinks returns the decisions id supersedes and is superseded by.
+func supersedeLinks(mem *memory.Memory, id string) (supersedes, supersededBy []string) {
+	links, _ := mem.GetAllLinksFor(id)
+	for _, l := range links {
+		if l.Relation != memory.RelationSupersedes || l.SourceKind != memory.TargetKindDecision || l.TargetKind != memory.TargetKindDecision {
+			continue
+		}
+		if l.SourceID == id {
+			supersedes = append(supersedes, l.TargetID)
+		} else {
+			supersededBy = append(supersededBy, l.SourceID)
+		}
+	}
+	sort.Strings(supersedes)
+	sort.Strings(supersededBy)
+	return supersedes, supersededBy
+}
+
+// supersedeNumbers is supersedeLinks as ADR numbers, skipping decisions
+// that have no ADR.
+func supersedeNumbers(mem *memory.Memory, id string, byDecision map[string]*entry) (supersedes, supersededBy []int) {
+	toNumbers := func(ids []string) []int {
+		var numbers []int
+		for _, id := range ids {
+			if e := byDecision[id]; e != nil {
+				numbers = append(numbers, e.rec.Number)
+			}
+		}
+		sort.Ints(numbers)
+		return numbers
+	}
+	s, sb := supersedeLinks(mem, id)
+	return toNumbers(s), toNumbers(sb)
+}
+
+// decisionHash covers every decision field an ADR carries, including its
+// supersedes links in both directions.
+func decisionHash(mem *memory.Memory, dec *memory.Decision) string {
+	supersedes, supersededBy := supersedeLinks(mem, dec.ID)
+	return hashBytes([]byte(strings.Join([]string{
+		dec.Content, dec.Rationale, dec.Context, dec.Status, dec.Authority,
+		dec.Outcome, dec.OutcomeNote, dec.Scope, dec.ScopePath,
+		strings.Join(supersedes, ","), strings.Join(supersededBy, ","),
+	}, "\x1f")))
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
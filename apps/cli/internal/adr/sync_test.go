@@ -0,0 +1,186 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+)
+
+func writeFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, e := range entries {
+		data, _ := os.ReadFile(filepath.Join(dir, e.Name()))
+		files[e.Name()] = string(data)
+	}
+	return files
+}
+
+func actions(r *Result) map[Action]int {
+	counts := map[Action]int{}
+	for _, c := range r.Changes {
+		counts[c.Action]++
+	}
+	return counts
+}
+
+func TestSync(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "memory/memory.go", "package memory\n")
+	writeFile(t, root, "api/handler.go", "package api\n")
+	writeFile(t, root, "docs/adr/0001-use-boltdb.md", "# 1. Use BoltDB\n\nDate: 2023-01-10\n\n## Status\n\nAccepted\n\n## Decision\n\nStore memory in BoltDB.\n")
+	writeFile(t, root, "docs/adr/0002-use-sqlite-for-the-memory-store.md", nygardADR)
+	writeFile(t, root, "docs/adr/0003-use-slog.md", strings.Replace(madrADR, `"superseded by ADR-0005"`, "proposed", 1))
+	writeFile(t, root, "docs/adr/README.md", "# Decisions\n")
+	rooms := []model.Room{{Name: "api", EntryPoints: []string{"api/handler.go"}}}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	jwt, _ := mem.AddDecision(memory.Decision{
+		Content:   "Use JWT for API auth",
+		Rationale: "Stateless servers.",
+		Scope:     "room",
+		ScopePath: "api",
+		Authority: string(memory.AuthorityApproved),
+	})
+
+	result, err := Sync(root, mem, Options{Rooms: rooms})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if got := actions(result); got[ActionImported] != 3 || got[ActionExported] != 1 || result.Links != 1 {
+		t.Fatalf("first sync = %v, %d links", got, result.Links)
+	}
+
+	decisions := map[int]*memory.Decision{}
+	for _, c := range result.Changes {
+		decisions[c.Number], _ = mem.GetDecision(c.DecisionID)
+	}
+	if d := decisions[2]; d.Scope != "file" || d.ScopePath != "memory/memory.go" || d.Outcome != "successful" || d.Source != Source {
+		t.Errorf("ADR 2 decision = %+v", d)
+	}
+	if d := decisions[1]; d.Status != memory.DecisionStatusActive || d.Authority != string(memory.AuthorityApproved) {
+		t.Errorf("ADR 1 decision = %s/%s", d.Status, d.Authority)
+	}
+	if d := decisions[3]; d.Authority != string(memory.AuthorityProposed) {
+		t.Errorf("ADR 3 authority = %s, want proposed", d.Authority)
+	}
+	chain, _ := mem.GetLinksForSource(decisions[2].ID)
+	if len(chain) != 1 || chain[0].TargetID != decisions[1].ID || chain[0].Relation != memory.RelationSupersedes {
+		t.Errorf("ADR 2 links = %+v", chain)
+	}
+
+	dir := filepath.Join(root, "docs", "adr")
+	exported := readDir(t, dir)["0004-use-jwt-for-api-auth.md"]
+	for _, want := range []string{"# 4. Use JWT for API auth", "Scope: room `api`", "## Status\n\nAccepted", "## Rationale\n\nStateless servers."} {
+		if !strings.Contains(exported, want) {
+			t.Errorf("exported ADR missing %q:\n%s", want, exported)
+		}
+	}
+
+	// Syncing again changes nothing.
+	before := readDir(t, dir)
+	again, err := Sync(root, mem, Options{Rooms: rooms})
+	if err != nil {
+		t.Fatalf("second Sync() error: %v", err)
+	}
+	if len(again.Changes) != 0 || again.Unchanged != 4 || again.Links != 0 {
+		t.Errorf("second sync = %+v", again)
+	}
+	if after := readDir(t, dir); len(after) != len(before) {
+		t.Errorf("second sync changed the directory: %d -> %d files", len(before), len(after))
+	} else {
+		for name, content := range before {
+			if after[name] != content {
+				t.Errorf("second sync rewrote %s", name)
+			}
+		}
+	}
+
+	// Palace-side changes flow to the files.
+	if err := mem.UpdateDecisionStatus(jwt, memory.DecisionStatusReversed); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.RecordDecisionOutcome(jwt, memory.DecisionOutcomeFailed, "Token revocation was painful."); err != nil {
+		t.Fatal(err)
+	}
+	result, err = Sync(root, mem, Options{Rooms: rooms})
+	if err != nil {
+		t.Fatalf("Sync() after palace edit error: %v", err)
+	}
+	if got := actions(result); got[ActionRewritten] != 1 || len(result.Changes) != 1 {
+		t.Errorf("sync after palace edit = %v", got)
+	}
+	exported = readDir(t, dir)["0004-use-jwt-for-api-auth.md"]
+	if !strings.Contains(exported, "## Status\n\nDeprecated") || !strings.Contains(exported, "## Outcome\n\nFailed\n\nToken revocation was painful.") {
+		t.Errorf("rewritten ADR:\n%s", exported)
+	}
+
+	// File-side changes flow to the decisions.
+	adr1 := filepath.Join(dir, "0001-use-boltdb.md")
+	data, _ := os.ReadFile(adr1)
+	os.WriteFile(adr1, []byte(strings.Replace(string(data), "Accepted", "Superseded", 1)), 0o644)
+	result, err = Sync(root, mem, Options{Rooms: rooms})
+	if err != nil {
+		t.Fatalf("Sync() after file edit error: %v", err)
+	}
+	if got := actions(result); got[ActionUpdated] != 1 || len(result.Changes) != 1 || result.Changes[0].Conflict {
+		t.Errorf("sync after file edit = %+v", result.Changes)
+	}
+	if d, _ := mem.GetDecision(decisions[1].ID); d.Status != memory.DecisionStatusSuperseded {
+		t.Errorf("ADR 1 status = %s, want superseded", d.Status)
+	}
+
+	if again, _ := Sync(root, mem, Options{Rooms: rooms}); len(again.Changes) != 0 {
+		t.Errorf("final sync = %+v", again.Changes)
+	}
+}
+
+func TestInferScope(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "api/handler.go", "")
+	writeFile(t, root, "api/routes.go", "")
+	writeFile(t, root, "cmd/main.go", "")
+	rooms := []model.Room{{Name: "api", EntryPoints: []string{"api/handler.go"}}}
+
+	tests := []struct {
+		text, scope, path string
+	}{
+		{"Change `api/handler.go:42` to stream.", "file", "api/handler.go"},
+		{"Both api/handler.go and api/routes.go.", "room", "api"},
+		{"Everything under `api/`.", "room", "api"},
+		{"Applies to the api room.", "room", "api"},
+		{"Touches api/handler.go and cmd/main.go.", "palace", ""},
+		{"See https://example.com/x.go for details.", "palace", ""},
+		{"No paths at all.", "palace", ""},
+	}
+	for _, tt := range tests {
+		scope, path := InferScope(root, tt.text, rooms)
+		if scope != tt.scope || path != tt.path {
+			t.Errorf("InferScope(%q) = %s %q, want %s %q", tt.text, scope, path, tt.scope, tt.path)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package analysis
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// maxCallReceiverLen bounds how much receiver text is kept in a call target.
+const maxCallReceiverLen = 64
+
+var memberAccessReplacer = strings.NewReplacer("?->", ".", "->", ".", "?.", ".", "$", "")
+
+// callTarget builds the target symbol of a call relationship. Simple receivers
+// are kept as a qualifier ("repo.findById", "Foo::bar"); receivers that are
+// themselves expressions (chained calls, indexing, literals) are dropped so the
+// target stays matchable against indexed symbol names. Pointer and nullsafe
+// member access ("->", "?->", "?.") is normalized to "." and PHP variable
+// sigils are dropped.
+func callTarget(receiver, sep, name string) string {
+	receiver = strings.TrimSpace(receiver)
+	receiver = memberAccessReplacer.Replace(receiver)
+	if receiver == "" || len(receiver) > maxCallReceiverLen || strings.ContainsAny(receiver, "()[]{}\"'` \t\n") {
+		return name
+	}
+	return receiver + sep + name
+}
+
+// callRelationship builds a call relationship positioned at the called name.
+func callRelationship(target string, nameNode *sitter.Node) Relationship {
+	pos := nameNode.StartPoint()
+	return Relationship{
+		TargetSymbol: target,
+		Kind:         RelCall,
+		Line:         int(pos.Row) + 1,
+		Column:       int(pos.Column),
+	}
+}
+
+// firstNamedChildOfType returns the first direct named child with the given type.
+func firstNamedChildOfType(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child != nil && child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
@@ -1522,3 +1522,169 @@ func TestParserLanguageMethod(t *testing.T) {
 		})
 	}
 }
+
+// TestCallRelationships tests call extraction with positions across languages
+func TestCallRelationships(t *testing.T) {
+	type call struct {
+		target       string
+		line, column int
+	}
+
+	tests := []struct {
+		name   string
+		parser Parser
+		file   string
+		code   string
+		want   []call
+	}{
+		{
+			name:   "java",
+			parser: NewJavaParser(),
+			file:   "UserService.java",
+			code: `public class UserService {
+    public User load(Long id) {
+        validate(id);
+        User user = repository.findById(id);
+        this.audit(user);
+        return new User(id);
+    }
+}
+`,
+			want: []call{{"validate", 3, 8}, {"repository.findById", 4, 31}, {"this.audit", 5, 13}, {"User", 6, 19}},
+		},
+		{
+			name:   "csharp",
+			parser: NewCSharpParser(),
+			file:   "UserService.cs",
+			code: `public class UserService {
+    public User Load(int id) {
+        Validate(id);
+        var user = _repository.FindById(id);
+        logger?.Log(user);
+        return new User(id);
+    }
+}
+`,
+			want: []call{{"Validate", 3, 8}, {"_repository.FindById", 4, 31}, {"logger.Log", 5, 16}, {"User", 6, 19}},
+		},
+		{
+			name:   "kotlin",
+			parser: NewKotlinParser(),
+			file:   "UserService.kt",
+			code: `class UserService {
+    fun load(id: Long): User {
+        validate(id)
+        val user = repository.findById(id)
+        return User(id)
+    }
+}
+`,
+			want: []call{{"validate", 3, 8}, {"repository.findById", 4, 30}, {"User", 5, 15}},
+		},
+		{
+			name:   "swift",
+			parser: NewSwiftParser(),
+			file:   "UserService.swift",
+			code: `class UserService {
+    func load(id: Int) -> User {
+        validate(id)
+        let user = repository.findById(id)
+        return User(id: id)
+    }
+}
+`,
+			want: []call{{"validate", 3, 8}, {"repository.findById", 4, 30}, {"User", 5, 15}},
+		},
+		{
+			name:   "php",
+			parser: NewPHPParser(),
+			file:   "UserService.php",
+			code: `<?php
+class UserService {
+    public function load($id) {
+        validate($id);
+        $user = $this->repository->findById($id);
+        $this?->cache->clear();
+        Logger::info($user);
+        return new User($id);
+    }
+}
+`,
+			want: []call{{"validate", 4, 8}, {"this.repository.findById", 5, 35}, {"this.cache.clear", 6, 23}, {"Logger::info", 7, 16}, {"User", 8, 19}},
+		},
+		{
+			name:   "ruby",
+			parser: NewRubyParser(),
+			file:   "user_service.rb",
+			code: `require 'json'
+
+class UserService
+  def load(id)
+    validate(id)
+    user = repository.find_by_id(id)
+    User.new(id)
+  end
+end
+`,
+			want: []call{{"validate", 5, 4}, {"repository.find_by_id", 6, 22}, {"User.new", 7, 9}},
+		},
+		{
+			name:   "c",
+			parser: NewCParser(),
+			file:   "service.c",
+			code: `#include <stdio.h>
+
+int load(struct service *svc, int id) {
+    validate(id);
+    svc->ops->find(id);
+    return printf("%d", id);
+}
+`,
+			want: []call{{"validate", 4, 4}, {"svc.ops.find", 5, 14}, {"printf", 6, 11}},
+		},
+		{
+			name:   "cpp",
+			parser: NewCPPParser(),
+			file:   "service.cpp",
+			code: `#include <memory>
+
+User* UserService::load(int id) {
+    validate(id);
+    auto user = repository_.findById(id);
+    std::make_shared<User>(id);
+    return new User(id);
+}
+`,
+			want: []call{{"validate", 4, 4}, {"repository_.findById", 5, 28}, {"std::make_shared", 6, 9}, {"User", 7, 15}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.parser.Parse([]byte(tt.code), tt.file)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			var got []call
+			for _, rel := range result.Relationships {
+				if rel.Kind == RelCall {
+					got = append(got, call{rel.TargetSymbol, rel.Line, rel.Column})
+				}
+			}
+
+			for _, want := range tt.want {
+				found := false
+				for _, c := range got {
+					if c == want {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("missing call %+v in %+v", want, got)
+				}
+			}
+		})
+	}
+}
@@ -287,6 +287,10 @@ func (p *CParser) extractRelationships(node *sitter.Node, content []byte, analys
 			}
 		}
 
+		if child.Type() == "call_expression" {
+			p.parseCall(child, content, analysis)
+		}
+
 		p.extractRelationships(child, content, analysis)
 	}
 }
@@ -323,3 +327,33 @@ func (p *CParser) extractPrecedingComment(node *sitter.Node, content []byte) str
 
 	return ""
 }
+
+func (p *CParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	funcNode := node.ChildByFieldName("function")
+	if funcNode == nil {
+		return
+	}
+
+	var receiver string
+	var nameNode *sitter.Node
+
+	switch funcNode.Type() {
+	case "identifier":
+		// foo()
+		nameNode = funcNode
+
+	case "field_expression":
+		// s.fn(), s->fn() through a function pointer
+		nameNode = funcNode.ChildByFieldName("field")
+		if argNode := funcNode.ChildByFieldName("argument"); argNode != nil {
+			receiver = argNode.Content(content)
+		}
+	}
+
+	if nameNode == nil {
+		return
+	}
+
+	target := callTarget(receiver, ".", nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
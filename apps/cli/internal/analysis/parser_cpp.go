@@ -366,8 +366,17 @@ func (p *CPPParser) extractRelationships(node *sitter.Node, content []byte, anal
 			}
 		}
 
-		if child.Type() == "base_class_clause" {
+		switch child.Type() {
+		case "base_class_clause":
 			p.parseBaseClasses(child, content, analysis)
+
+		case "call_expression":
+			p.parseCall(child, content, analysis)
+
+		case "new_expression":
+			if typeNode := child.ChildByFieldName("type"); typeNode != nil {
+				p.appendCall("", "", typeNode, content, analysis)
+			}
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -423,3 +432,54 @@ func (p *CPPParser) extractPrecedingComment(node *sitter.Node, content []byte) s
 
 	return ""
 }
+
+func (p *CPPParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	funcNode := node.ChildByFieldName("function")
+	if funcNode == nil {
+		return
+	}
+
+	switch funcNode.Type() {
+	case "identifier", "template_function":
+		// foo(), foo<T>()
+		p.appendCall("", "", funcNode, content, analysis)
+
+	case "field_expression":
+		// obj.method(), ptr->method()
+		receiver := ""
+		if argNode := funcNode.ChildByFieldName("argument"); argNode != nil {
+			receiver = argNode.Content(content)
+		}
+		if fieldNode := funcNode.ChildByFieldName("field"); fieldNode != nil {
+			p.appendCall(receiver, ".", fieldNode, content, analysis)
+		}
+
+	case "qualified_identifier":
+		// ns::foo(), Class::method()
+		receiver := ""
+		if scopeNode := funcNode.ChildByFieldName("scope"); scopeNode != nil {
+			receiver = scopeNode.Content(content)
+		}
+		if nameNode := funcNode.ChildByFieldName("name"); nameNode != nil {
+			p.appendCall(receiver, "::", nameNode, content, analysis)
+		}
+	}
+}
+
+func (p *CPPParser) appendCall(receiver, sep string, nameNode *sitter.Node, content []byte, analysis *FileAnalysis) {
+	// Strip template arguments: foo<int> -> foo
+	if nameNode.Type() == "template_function" || nameNode.Type() == "template_type" {
+		if inner := nameNode.ChildByFieldName("name"); inner != nil {
+			nameNode = inner
+		}
+	}
+
+	switch nameNode.Type() {
+	case "identifier", "field_identifier", "type_identifier", "destructor_name":
+	default:
+		return
+	}
+
+	target := callTarget(receiver, sep, nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
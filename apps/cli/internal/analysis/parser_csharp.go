@@ -408,6 +408,12 @@ func (p *CSharpParser) extractRelationships(node *sitter.Node, content []byte, a
 
 		case "base_list":
 			p.parseBaseList(child, content, analysis)
+
+		case "invocation_expression":
+			p.parseInvocation(child, content, analysis)
+
+		case "object_creation_expression":
+			p.parseObjectCreation(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -499,3 +505,68 @@ func (p *CSharpParser) extractXmlDoc(node *sitter.Node, content []byte) string {
 
 	return ""
 }
+
+func (p *CSharpParser) parseInvocation(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	funcNode := node.ChildByFieldName("function")
+	if funcNode == nil {
+		return
+	}
+
+	var receiver string
+	var nameNode *sitter.Node
+
+	switch funcNode.Type() {
+	case "identifier":
+		// Foo()
+		nameNode = funcNode
+
+	case "generic_name":
+		// Foo<T>()
+		nameNode = firstNamedChildOfType(funcNode, "identifier")
+
+	case "member_access_expression":
+		// obj.Foo(), this.Foo(), Type.Foo()
+		nameNode = funcNode.ChildByFieldName("name")
+		if exprNode := funcNode.ChildByFieldName("expression"); exprNode != nil {
+			receiver = exprNode.Content(content)
+		} else if nameNode != nil {
+			receiver = strings.TrimSuffix(strings.TrimSpace(string(content[funcNode.StartByte():nameNode.StartByte()])), ".")
+		}
+
+	case "conditional_access_expression":
+		// obj?.Foo()
+		if binding := firstNamedChildOfType(funcNode, "member_binding_expression"); binding != nil {
+			nameNode = binding.ChildByFieldName("name")
+		}
+		if condNode := funcNode.ChildByFieldName("condition"); condNode != nil {
+			receiver = condNode.Content(content)
+		}
+	}
+
+	if nameNode != nil && nameNode.Type() == "generic_name" {
+		nameNode = firstNamedChildOfType(nameNode, "identifier")
+	}
+	if nameNode == nil {
+		return
+	}
+
+	target := callTarget(receiver, ".", nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
+
+func (p *CSharpParser) parseObjectCreation(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return
+	}
+
+	// new Foo<T>() calls the Foo constructor
+	if typeNode.Type() == "generic_name" {
+		typeNode = firstNamedChildOfType(typeNode, "identifier")
+		if typeNode == nil {
+			return
+		}
+	}
+
+	analysis.Relationships = append(analysis.Relationships, callRelationship(typeNode.Content(content), typeNode))
+}
@@ -353,8 +353,15 @@ func (p *JavaParser) extractRelationships(node *sitter.Node, content []byte, ana
 			continue
 		}
 
-		if child.Type() == "import_declaration" {
+		switch child.Type() {
+		case "import_declaration":
 			p.parseImport(child, content, analysis)
+
+		case "method_invocation":
+			p.parseMethodInvocation(child, content, analysis)
+
+		case "object_creation_expression":
+			p.parseObjectCreation(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -378,3 +385,33 @@ func (p *JavaParser) parseImport(node *sitter.Node, content []byte, analysis *Fi
 		}
 	}
 }
+
+func (p *JavaParser) parseMethodInvocation(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+
+	// foo(), obj.foo(), this.foo(), Util.foo()
+	receiver := ""
+	if objectNode := node.ChildByFieldName("object"); objectNode != nil {
+		receiver = objectNode.Content(content)
+	}
+
+	target := callTarget(receiver, ".", nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
+
+func (p *JavaParser) parseObjectCreation(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return
+	}
+
+	// new Foo<T>() calls the Foo constructor
+	if typeNode.Type() == "generic_type" && typeNode.NamedChildCount() > 0 {
+		typeNode = typeNode.NamedChild(0)
+	}
+
+	analysis.Relationships = append(analysis.Relationships, callRelationship(typeNode.Content(content), typeNode))
+}
@@ -310,6 +310,9 @@ func (p *KotlinParser) extractRelationships(node *sitter.Node, content []byte, a
 
 		case "class_declaration":
 			p.parseInheritance(child, content, analysis)
+
+		case "call_expression":
+			p.parseCall(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -429,3 +432,37 @@ func (p *KotlinParser) extractKDoc(node *sitter.Node, content []byte) string {
 
 	return ""
 }
+
+func (p *KotlinParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if node.NamedChildCount() == 0 {
+		return
+	}
+	callee := node.NamedChild(0)
+
+	var receiver string
+	var nameNode *sitter.Node
+
+	switch callee.Type() {
+	case "simple_identifier":
+		// foo(), Foo()
+		nameNode = callee
+
+	case "navigation_expression":
+		// obj.foo(), obj?.foo()
+		suffix := firstNamedChildOfType(callee, "navigation_suffix")
+		if suffix == nil {
+			return
+		}
+		nameNode = firstNamedChildOfType(suffix, "simple_identifier")
+		if callee.NamedChildCount() > 0 {
+			receiver = callee.NamedChild(0).Content(content)
+		}
+	}
+
+	if nameNode == nil {
+		return
+	}
+
+	target := callTarget(receiver, ".", nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
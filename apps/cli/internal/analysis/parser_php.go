@@ -332,6 +332,24 @@ func (p *PHPParser) extractRelationships(node *sitter.Node, content []byte, anal
 
 		case "interface_declaration":
 			p.parseInterfaceExtends(child, content, analysis)
+
+		case "function_call_expression":
+			if funcNode := child.ChildByFieldName("function"); funcNode != nil {
+				p.appendCall("", "", funcNode, content, analysis)
+			}
+
+		case "member_call_expression", "nullsafe_member_call_expression":
+			p.parseMemberCall(child, "object", ".", content, analysis)
+
+		case "scoped_call_expression":
+			p.parseMemberCall(child, "scope", "::", content, analysis)
+
+		case "object_creation_expression":
+			if nameNode := firstNamedChildOfType(child, "name"); nameNode != nil {
+				p.appendCall("", "", nameNode, content, analysis)
+			} else if nameNode := firstNamedChildOfType(child, "qualified_name"); nameNode != nil {
+				p.appendCall("", "", nameNode, content, analysis)
+			}
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -469,3 +487,29 @@ func (p *PHPParser) extractDocComment(node *sitter.Node, content []byte) string
 
 	return ""
 }
+
+// parseMemberCall handles $obj->foo(), $obj?->foo() and Foo::bar().
+func (p *PHPParser) parseMemberCall(node *sitter.Node, receiverField, sep string, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+
+	receiver := ""
+	if receiverNode := node.ChildByFieldName(receiverField); receiverNode != nil {
+		receiver = receiverNode.Content(content)
+	}
+	p.appendCall(receiver, sep, nameNode, content, analysis)
+}
+
+func (p *PHPParser) appendCall(receiver, sep string, nameNode *sitter.Node, content []byte, analysis *FileAnalysis) {
+	// Dynamic calls ($fn(), $obj->$method()) have no static target.
+	switch nameNode.Type() {
+	case "name", "qualified_name":
+	default:
+		return
+	}
+
+	target := callTarget(receiver, sep, nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
@@ -260,6 +260,7 @@ func (p *RubyParser) extractRelationships(node *sitter.Node, content []byte, ana
 		switch child.Type() {
 		case "call":
 			p.parseRequire(child, content, analysis)
+			p.parseCall(child, content, analysis)
 
 		case "class":
 			p.parseInheritance(child, content, analysis)
@@ -337,3 +338,24 @@ func (p *RubyParser) extractPrecedingComment(node *sitter.Node, content []byte)
 
 	return ""
 }
+
+func (p *RubyParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	methodNode := node.ChildByFieldName("method")
+	if methodNode == nil || methodNode.Type() == "argument_list" {
+		return
+	}
+
+	method := methodNode.Content(content)
+	if method == "require" || method == "require_relative" {
+		return
+	}
+
+	// obj.foo, Foo.new, Foo::bar
+	receiver := ""
+	if receiverNode := node.ChildByFieldName("receiver"); receiverNode != nil {
+		receiver = receiverNode.Content(content)
+	}
+
+	target := callTarget(receiver, ".", method)
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, methodNode))
+}
@@ -435,6 +435,9 @@ func (p *SwiftParser) extractRelationships(node *sitter.Node, content []byte, an
 
 		case "class_declaration", "struct_declaration":
 			p.parseInheritance(child, content, analysis)
+
+		case "call_expression":
+			p.parseCall(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -548,3 +551,35 @@ func (p *SwiftParser) extractDocComment(node *sitter.Node, content []byte) strin
 
 	return ""
 }
+
+func (p *SwiftParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if node.NamedChildCount() == 0 {
+		return
+	}
+	callee := node.NamedChild(0)
+
+	var receiver string
+	var nameNode *sitter.Node
+
+	switch callee.Type() {
+	case "simple_identifier":
+		// foo(), Foo()
+		nameNode = callee
+
+	case "navigation_expression":
+		// obj.foo(), self.foo()
+		if suffix := callee.ChildByFieldName("suffix"); suffix != nil {
+			nameNode = suffix.ChildByFieldName("suffix")
+		}
+		if targetNode := callee.ChildByFieldName("target"); targetNode != nil {
+			receiver = targetNode.Content(content)
+		}
+	}
+
+	if nameNode == nil || nameNode.Type() != "simple_identifier" {
+		return
+	}
+
+	target := callTarget(receiver, ".", nameNode.Content(content))
+	analysis.Relationships = append(analysis.Relationships, callRelationship(target, nameNode))
+}
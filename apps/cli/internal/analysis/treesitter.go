@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/bash"
+	"github.com/smacker/go-tree-sitter/c"
+	"github.com/smacker/go-tree-sitter/cpp"
+	"github.com/smacker/go-tree-sitter/csharp"
+	"github.com/smacker/go-tree-sitter/css"
+	"github.com/smacker/go-tree-sitter/dockerfile"
+	"github.com/smacker/go-tree-sitter/elixir"
+	"github.com/smacker/go-tree-sitter/elm"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/groovy"
+	"github.com/smacker/go-tree-sitter/hcl"
+	"github.com/smacker/go-tree-sitter/html"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"github.com/smacker/go-tree-sitter/lua"
+	tree_sitter_markdown "github.com/smacker/go-tree-sitter/markdown/tree-sitter-markdown"
+	"github.com/smacker/go-tree-sitter/ocaml"
+	"github.com/smacker/go-tree-sitter/php"
+	"github.com/smacker/go-tree-sitter/protobuf"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/ruby"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/scala"
+	"github.com/smacker/go-tree-sitter/sql"
+	"github.com/smacker/go-tree-sitter/svelte"
+	"github.com/smacker/go-tree-sitter/swift"
+	"github.com/smacker/go-tree-sitter/toml"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+	"github.com/smacker/go-tree-sitter/yaml"
+)
+
+// TreeSitterLanguage returns the tree-sitter grammar used to parse lang, or
+// nil when lang is not parsed with tree-sitter.
+func TreeSitterLanguage(lang Language) *sitter.Language {
+	switch lang {
+	case LangGo:
+		return golang.GetLanguage()
+	case LangJavaScript:
+		return javascript.GetLanguage()
+	case LangTypeScript:
+		return typescript.GetLanguage()
+	case LangPython:
+		return python.GetLanguage()
+	case LangRust:
+		return rust.GetLanguage()
+	case LangJava:
+		return java.GetLanguage()
+	case LangC:
+		return c.GetLanguage()
+	case LangCPP:
+		return cpp.GetLanguage()
+	case LangCSharp:
+		return csharp.GetLanguage()
+	case LangRuby:
+		return ruby.GetLanguage()
+	case LangSwift:
+		return swift.GetLanguage()
+	case LangKotlin:
+		return kotlin.GetLanguage()
+	case LangScala:
+		return scala.GetLanguage()
+	case LangPHP:
+		return php.GetLanguage()
+	case LangBash:
+		return bash.GetLanguage()
+	case LangSQL:
+		return sql.GetLanguage()
+	case LangHTML:
+		return html.GetLanguage()
+	case LangCSS:
+		return css.GetLanguage()
+	case LangYAML:
+		return yaml.GetLanguage()
+	case LangTOML:
+		return toml.GetLanguage()
+	case LangMarkdown:
+		return tree_sitter_markdown.GetLanguage()
+	case LangDockerfile:
+		return dockerfile.GetLanguage()
+	case LangHCL:
+		return hcl.GetLanguage()
+	case LangProtobuf:
+		return protobuf.GetLanguage()
+	case LangLua:
+		return lua.GetLanguage()
+	case LangElixir:
+		return elixir.GetLanguage()
+	case LangGroovy:
+		return groovy.GetLanguage()
+	case LangSvelte:
+		return svelte.GetLanguage()
+	case LangOCaml:
+		return ocaml.GetLanguage()
+	case LangElm:
+		return elm.GetLanguage()
+	}
+	return nil
+}
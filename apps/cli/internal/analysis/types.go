@@ -50,7 +50,7 @@ type Relationship struct {
 	TargetSymbol string
 	Kind         RelationshipKind
 	Line         int
-	Column       int
+	Column       int // Column where the target appears (0-based)
 }
 
 // FileAnalysis stores the results of analyzing a single file.
@@ -0,0 +1,516 @@
+// Package architecture checks the import graph against the layering rules
+// declared in palace.jsonc: which components may or must not depend on
+// which, who may import a component, and where import cycles are banned.
+// Components are rooms, monorepo projects, path globs, or named layers
+// grouping them.
+package architecture
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/depgraph"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/project"
+)
+
+// Kinds of violation.
+const (
+	KindForbidden  = "forbidden"   // import matched a deny list
+	KindNotAllowed = "not-allowed" // import missing from an allow list
+	KindRestricted = "restricted"  // importer missing from an onlyFrom list
+	KindCycle      = "cycle"       // import is part of a banned cycle
+)
+
+// Severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Selector prefixes for rooms and monorepo projects.
+const (
+	roomPrefix    = "room:"
+	projectPrefix = "project:"
+)
+
+// component is a named set of workspace-relative files.
+type component struct {
+	name  string
+	match func(file string) bool
+}
+
+func (c *component) contains(file string) bool { return c.match(file) }
+
+// rule is a compiled config.ArchitectureRule.
+type rule struct {
+	id       string
+	severity string
+	message  string
+	from, to *component
+	deny     []*component
+	allow    []*component
+	onlyFrom []*component
+	noCycles []*component
+}
+
+// Checker evaluates compiled rules against import edges.
+type Checker struct {
+	rules []rule
+}
+
+// Load compiles the architecture rules of the workspace at root. It returns
+// nil when no rules are configured.
+func Load(root string, cfg *config.PalaceConfig) (*Checker, error) {
+	if cfg == nil || cfg.Architecture == nil || len(cfg.Architecture.Rules) == 0 {
+		return nil, nil
+	}
+	r := &resolver{root: root, cfg: cfg}
+	return r.compile(cfg.Architecture)
+}
+
+// Check loads the rules of the workspace at root and evaluates them against
+// the import graph in db. The checker is nil when palace.jsonc is missing or
+// configures no rules.
+func Check(root string, db *sql.DB) (*Checker, []index.ArchitectureViolation, error) {
+	cfg, err := config.LoadPalaceConfig(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	checker, err := Load(root, cfg)
+	if err != nil || checker == nil {
+		return nil, nil, err
+	}
+	edges, err := index.LoadImportGraph(db, root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load import graph: %w", err)
+	}
+	return checker, checker.Evaluate(edges), nil
+}
+
+// Rules returns the IDs of the compiled rules, in config order.
+func (c *Checker) Rules() []string {
+	ids := make([]string, 0, len(c.rules))
+	for i := range c.rules {
+		ids = append(ids, c.rules[i].id)
+	}
+	return ids
+}
+
+// resolver turns component selectors into file matchers. Rooms and
+// monorepo projects are loaded on first use.
+type resolver struct {
+	root     string
+	cfg      *config.PalaceConfig
+	rooms    map[string]model.Room
+	projects []project.ProjectInfo
+	layers   map[string]*component
+}
+
+func (r *resolver) compile(arch *config.ArchitectureConfig) (*Checker, error) {
+	r.layers = make(map[string]*component, len(arch.Layers))
+	layerNames := make([]string, 0, len(arch.Layers))
+	for name := range arch.Layers {
+		layerNames = append(layerNames, name)
+	}
+	sort.Strings(layerNames)
+	for _, name := range layerNames {
+		var parts []*component
+		for _, sel := range arch.Layers[name] {
+			c, err := r.selector(sel)
+			if err != nil {
+				return nil, fmt.Errorf("architecture layer %q: %w", name, err)
+			}
+			parts = append(parts, c)
+		}
+		r.layers[name] = &component{name: name, match: anyOf(parts)}
+	}
+
+	checker := &Checker{}
+	seen := make(map[string]bool)
+	var problems []error
+	for i := range arch.Rules {
+		cr, err := r.rule(i, &arch.Rules[i])
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if seen[cr.id] {
+			problems = append(problems, fmt.Errorf("architecture rule %q: duplicate id", cr.id))
+			continue
+		}
+		seen[cr.id] = true
+		checker.rules = append(checker.rules, cr)
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+	return checker, nil
+}
+
+func (r *resolver) rule(i int, cfg *config.ArchitectureRule) (rule, error) {
+	cr := rule{id: cfg.ID, severity: cfg.Severity, message: cfg.Message}
+	if cr.id == "" {
+		cr.id = "rule-" + strconv.Itoa(i+1)
+	}
+	if cr.severity == "" {
+		cr.severity = SeverityError
+	}
+	fail := func(format string, args ...any) (rule, error) {
+		return rule{}, fmt.Errorf("architecture rule %q: %s", cr.id, fmt.Sprintf(format, args...))
+	}
+	if cr.severity != SeverityError && cr.severity != SeverityWarning {
+		return fail("severity must be error or warning")
+	}
+
+	kinds := 0
+	for _, set := range []bool{len(cfg.Deny) > 0, len(cfg.Allow) > 0, len(cfg.OnlyFrom) > 0, len(cfg.NoCycles) > 0} {
+		if set {
+			kinds++
+		}
+	}
+	switch {
+	case kinds != 1:
+		return fail("set exactly one of deny, allow, onlyFrom or noCycles")
+	case (len(cfg.Deny) > 0 || len(cfg.Allow) > 0) && (cfg.From == "" || cfg.To != ""):
+		return fail("deny and allow need from, and no to")
+	case len(cfg.OnlyFrom) > 0 && (cfg.To == "" || cfg.From != ""):
+		return fail("onlyFrom needs to, and no from")
+	case len(cfg.NoCycles) > 0 && (cfg.From != "" || cfg.To != ""):
+		return fail("noCycles takes no from or to")
+	}
+
+	var err error
+	if cfg.From != "" {
+		if cr.from, err = r.component(cfg.From); err != nil {
+			return fail("%v", err)
+		}
+	}
+	if cfg.To != "" {
+		if cr.to, err = r.component(cfg.To); err != nil {
+			return fail("%v", err)
+		}
+	}
+	for _, list := range []struct {
+		in  []string
+		out *[]*component
+	}{{cfg.Deny, &cr.deny}, {cfg.Allow, &cr.allow}, {cfg.OnlyFrom, &cr.onlyFrom}, {cfg.NoCycles, &cr.noCycles}} {
+		for _, name := range list.in {
+			c, err := r.component(name)
+			if err != nil {
+				return fail("%v", err)
+			}
+			*list.out = append(*list.out, c)
+		}
+	}
+	return cr, nil
+}
+
+// component resolves a rule operand: a layer name or a selector.
+func (r *resolver) component(name string) (*component, error) {
+	if c, ok := r.layers[name]; ok {
+		return c, nil
+	}
+	return r.selector(name)
+}
+
+// selector resolves "room:<name>", "project:<name>" or a path glob. A path
+// without glob characters matches the file or directory it names.
+func (r *resolver) selector(sel string) (*component, error) {
+	if name, ok := strings.CutPrefix(sel, roomPrefix); ok {
+		room, ok := r.room(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown room %q", name)
+		}
+		return &component{name: sel, match: r.roomMatcher(room)}, nil
+	}
+	if name, ok := strings.CutPrefix(sel, projectPrefix); ok {
+		p, ok := r.project(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown monorepo project %q", name)
+		}
+		return &component{name: sel, match: under(p.Path)}, nil
+	}
+
+	glob := strings.TrimPrefix(filepath.ToSlash(sel), "./")
+	if !strings.ContainsAny(glob, "*?[{") {
+		return &component{name: sel, match: under(glob)}, nil
+	}
+	if !doublestar.ValidatePattern(glob) {
+		return nil, fmt.Errorf("invalid glob %q", sel)
+	}
+	return &component{name: sel, match: func(file string) bool {
+		ok, _ := doublestar.Match(glob, file)
+		return ok
+	}}, nil
+}
+
+func (r *resolver) room(name string) (model.Room, bool) {
+	if r.rooms == nil {
+		r.rooms = make(map[string]model.Room)
+		for _, room := range playbook.LoadRooms(r.root) {
+			r.rooms[room.Name] = room
+		}
+	}
+	room, ok := r.rooms[name]
+	return room, ok
+}
+
+// roomMatcher matches the files under a room's entry points. An entry point
+// that is a directory covers the directory; one that is a file covers the
+// file's directory, as it does for ADR scopes.
+func (r *resolver) roomMatcher(room model.Room) func(string) bool {
+	var parts []*component
+	for _, ep := range room.EntryPoints {
+		ep = strings.TrimPrefix(filepath.ToSlash(ep), "./")
+		if strings.ContainsAny(ep, "*?[{") {
+			glob := ep
+			parts = append(parts, &component{match: func(file string) bool {
+				ok, _ := doublestar.Match(glob, file)
+				return ok
+			}})
+			continue
+		}
+		ep = strings.TrimSuffix(ep, "/")
+		if info, err := os.Stat(filepath.Join(r.root, filepath.FromSlash(ep))); err == nil && !info.IsDir() {
+			ep = path.Dir(ep)
+		}
+		parts = append(parts, &component{match: under(ep)})
+	}
+	return anyOf(parts)
+}
+
+func (r *resolver) project(name string) (project.ProjectInfo, bool) {
+	if r.projects == nil {
+		var opts project.MonorepoConfig
+		if m := r.cfg.Monorepo; m != nil {
+			opts = project.MonorepoConfig{
+				Patterns:          m.Patterns,
+				ExcludePatterns:   m.ExcludePatterns,
+				PreferredManager:  m.PreferredManager,
+				DisableAutoDetect: m.DisableAutoDetect,
+			}
+		}
+		r.projects = project.DetectMonorepo(r.root, &opts).Projects
+	}
+	for _, p := range r.projects {
+		if p.Name == name || filepath.ToSlash(p.Path) == name {
+			return p, true
+		}
+	}
+	return project.ProjectInfo{}, false
+}
+
+// under matches dir itself and everything below it; "." or "" match all.
+func under(dir string) func(string) bool {
+	dir = strings.TrimSuffix(filepath.ToSlash(dir), "/")
+	if dir == "" || dir == "." {
+		return func(string) bool { return true }
+	}
+	return func(file string) bool {
+		return file == dir || strings.HasPrefix(file, dir+"/")
+	}
+}
+
+func anyOf(parts []*component) func(string) bool {
+	return func(file string) bool {
+		for _, c := range parts {
+			if c.contains(file) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func names(list []*component) string {
+	out := make([]string, 0, len(list))
+	for _, c := range list {
+		out = append(out, c.name)
+	}
+	return strings.Join(out, ", ")
+}
+
+func first(list []*component, file string) *component {
+	for _, c := range list {
+		if c.contains(file) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the violations of every rule, sorted by file and line.
+// Each rule reports an importing file and imported file pair once, at the
+// first import line.
+func (c *Checker) Evaluate(edges []index.ImportEdge) []index.ArchitectureViolation {
+	var list []index.ArchitectureViolation
+	for i := range c.rules {
+		list = append(list, c.rules[i].evaluate(edges)...)
+	}
+	seen := make(map[string]bool, len(list))
+	index.SortArchitectureViolations(list)
+	kept := list[:0]
+	for i := range list {
+		if key := list[i].Key(); !seen[key] {
+			seen[key] = true
+			kept = append(kept, list[i])
+		}
+	}
+	return kept
+}
+
+func (r *rule) evaluate(edges []index.ImportEdge) []index.ArchitectureViolation {
+	if len(r.noCycles) > 0 {
+		return r.cycles(edges)
+	}
+
+	from := []*component{r.from}
+	var list []index.ArchitectureViolation
+	for _, imp := range imports(edges) {
+		source := imp[0].Source
+		switch {
+		case len(r.deny) > 0:
+			if !r.from.contains(source) || anyTarget(from, imp) {
+				continue
+			}
+			if e, target := firstTarget(r.deny, imp); target != nil {
+				list = append(list, r.violation(e, KindForbidden, r.from.name, target.name,
+					fmt.Sprintf("%s must not import %s", r.from.name, target.name)))
+			}
+		case len(r.allow) > 0:
+			if !r.from.contains(source) || anyTarget(from, imp) || anyTarget(r.allow, imp) {
+				continue
+			}
+			list = append(list, r.violation(imp[0], KindNotAllowed, r.from.name, "",
+				fmt.Sprintf("%s may only import %s", r.from.name, names(r.allow))))
+		case len(r.onlyFrom) > 0:
+			e, _ := firstTarget([]*component{r.to}, imp)
+			if e == nil || r.to.contains(source) || first(r.onlyFrom, source) != nil {
+				continue
+			}
+			list = append(list, r.violation(e, KindRestricted, "", r.to.name,
+				fmt.Sprintf("only %s may import %s", names(r.onlyFrom), r.to.name)))
+		}
+	}
+	return list
+}
+
+// imports groups edges by import statement. An import of a Go package has
+// an edge to each file of the package, and it matches a component when any
+// of them does.
+func imports(edges []index.ImportEdge) [][]*index.ImportEdge {
+	var groups [][]*index.ImportEdge
+	at := make(map[string]int)
+	for i := range edges {
+		e := &edges[i]
+		key := e.Source + "\x00" + e.Import + "\x00" + strconv.Itoa(e.Line)
+		j, ok := at[key]
+		if !ok {
+			j = len(groups)
+			at[key] = j
+			groups = append(groups, nil)
+		}
+		groups[j] = append(groups[j], e)
+	}
+	return groups
+}
+
+// firstTarget returns the first edge of imp whose target a component of
+// list contains, and that component.
+func firstTarget(list []*component, imp []*index.ImportEdge) (*index.ImportEdge, *component) {
+	for _, e := range imp {
+		if c := first(list, e.Target); c != nil {
+			return e, c
+		}
+	}
+	return nil, nil
+}
+
+// anyTarget reports whether a component of list contains a target of imp.
+func anyTarget(list []*component, imp []*index.ImportEdge) bool {
+	e, _ := firstTarget(list, imp)
+	return e != nil
+}
+
+// cycles reports the imports inside strongly connected components: between
+// the listed components, or between the files of a single one.
+func (r *rule) cycles(edges []index.ImportEdge) []index.ArchitectureViolation {
+	node := func(file string) string {
+		if c := first(r.noCycles, file); c != nil {
+			return c.name
+		}
+		return ""
+	}
+	if len(r.noCycles) == 1 {
+		scope := r.noCycles[0]
+		node = func(file string) string {
+			if scope.contains(file) {
+				return file
+			}
+			return ""
+		}
+	}
+
+	graph := make(map[string][]string)
+	for i := range edges {
+		from, to := node(edges[i].Source), node(edges[i].Target)
+		if from != "" && to != "" && from != to {
+			graph[from] = append(graph[from], to)
+		}
+	}
+	cycleOf, members := depgraph.StronglyConnected(graph)
+
+	var list []index.ArchitectureViolation
+	for i := range edges {
+		e := &edges[i]
+		from, to := node(e.Source), node(e.Target)
+		id, ok := cycleOf[from]
+		if target, inCycle := cycleOf[to]; from == "" || from == to || !ok || !inCycle || target != id {
+			continue
+		}
+		var message string
+		if len(r.noCycles) == 1 {
+			message = fmt.Sprintf("import cycle among %d files in %s", len(members[id]), r.noCycles[0].name)
+			from, to = r.noCycles[0].name, r.noCycles[0].name
+		} else {
+			message = "import cycle between " + strings.Join(members[id], ", ")
+		}
+		list = append(list, r.violation(e, KindCycle, from, to, message))
+	}
+	return list
+}
+
+func (r *rule) violation(e *index.ImportEdge, kind, source, target, message string) index.ArchitectureViolation {
+	if r.message != "" {
+		message = r.message
+	}
+	return index.ArchitectureViolation{
+		Rule:            r.id,
+		Kind:            kind,
+		SourceFile:      e.Source,
+		TargetFile:      e.Target,
+		Import:          e.Import,
+		Line:            e.Line,
+		SourceComponent: source,
+		TargetComponent: target,
+		Severity:        r.severity,
+		Message:         message,
+	}
+}
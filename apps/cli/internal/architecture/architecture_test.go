@@ -0,0 +1,179 @@
+package architecture
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+func edge(source, target string, line int) index.ImportEdge {
+	return index.ImportEdge{Source: source, Target: target, Import: target, Line: line}
+}
+
+func load(t *testing.T, root string, arch config.ArchitectureConfig) *Checker {
+	t.Helper()
+	checker, err := Load(root, &config.PalaceConfig{Architecture: &arch})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return checker
+}
+
+func TestEvaluate(t *testing.T) {
+	root := t.TempDir()
+	rooms := filepath.Join(root, ".palace", "rooms")
+	if err := os.MkdirAll(rooms, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	room := `{"schemaVersion": "1.0.0", "kind": "palace/room", "name": "billing", "summary": "Billing", "entryPoints": ["internal/billing/"]}`
+	if err := os.WriteFile(filepath.Join(rooms, "billing.jsonc"), []byte(room), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := load(t, root, config.ArchitectureConfig{
+		Layers: map[string][]string{
+			"domain": {"room:billing", "internal/domain/**"},
+			"ui":     {"web"},
+		},
+		Rules: []config.ArchitectureRule{
+			{ID: "domain-is-pure", From: "domain", Deny: []string{"ui", "internal/db/**"}},
+			{ID: "ui-deps", From: "ui", Allow: []string{"domain"}, Severity: SeverityWarning},
+			{ID: "db-access", To: "internal/db", OnlyFrom: []string{"internal/repo/**"}, Message: "go through the repository"},
+		},
+	})
+	if got := checker.Rules(); len(got) != 3 {
+		t.Fatalf("rules = %v", got)
+	}
+
+	list := checker.Evaluate([]index.ImportEdge{
+		edge("internal/billing/invoice.go", "web/format.ts", 3),
+		edge("internal/billing/invoice.go", "web/format.ts", 9), // same pair, reported once
+		edge("internal/domain/user.go", "internal/db/conn.go", 5),
+		edge("internal/domain/user.go", "internal/billing/invoice.go", 6),
+		edge("internal/repo/users.go", "internal/db/conn.go", 2),
+		edge("web/app.ts", "internal/domain/user.go", 1),
+		edge("web/app.ts", "web/format.ts", 2),
+		edge("web/app.ts", "vendor/lodash.js", 3),
+	})
+
+	type key struct{ rule, source, target string }
+	got := make(map[key]index.ArchitectureViolation)
+	for _, v := range list {
+		got[key{v.Rule, v.SourceFile, v.TargetFile}] = v
+	}
+	if len(list) != 4 {
+		t.Errorf("violations = %+v", list)
+	}
+	if v, ok := got[key{"domain-is-pure", "internal/billing/invoice.go", "web/format.ts"}]; !ok ||
+		v.Kind != KindForbidden || v.Line != 3 || v.TargetComponent != "ui" || v.Message != "domain must not import ui" {
+		t.Errorf("billing -> ui = %+v", v)
+	}
+	if v, ok := got[key{"domain-is-pure", "internal/domain/user.go", "internal/db/conn.go"}]; !ok || v.Severity != SeverityError {
+		t.Errorf("domain -> db = %+v", v)
+	}
+	if v, ok := got[key{"ui-deps", "web/app.ts", "vendor/lodash.js"}]; !ok || v.Kind != KindNotAllowed || v.Severity != SeverityWarning {
+		t.Errorf("ui -> vendor = %+v", v)
+	}
+	if v, ok := got[key{"db-access", "internal/domain/user.go", "internal/db/conn.go"}]; !ok ||
+		v.Kind != KindRestricted || v.Message != "go through the repository" {
+		t.Errorf("domain -> db restricted = %+v", v)
+	}
+
+	findings := Findings(list)
+	if len(findings) != 4 || !strings.HasPrefix(findings[0].RuleID, "architecture/") {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestEvaluate_GoPackages(t *testing.T) {
+	checker := load(t, t.TempDir(), config.ArchitectureConfig{
+		Rules: []config.ArchitectureRule{
+			{ID: "no-sql", From: "internal/domain/**", Deny: []string{"internal/db/*_sql.go"}},
+			{ID: "cmd-deps", From: "cmd/**", Allow: []string{"internal/api/handlers.go"}},
+		},
+	})
+
+	// A Go import names a package, so it has an edge to each of its files.
+	pkg := func(source, importPath string, line int, targets ...string) []index.ImportEdge {
+		var edges []index.ImportEdge
+		for _, target := range targets {
+			edges = append(edges, index.ImportEdge{Source: source, Target: target, Import: importPath, Line: line})
+		}
+		return edges
+	}
+	var edges []index.ImportEdge
+	edges = append(edges, pkg("internal/domain/user.go", "example.com/app/internal/db", 3,
+		"internal/db/conn.go", "internal/db/users_sql.go")...)
+	edges = append(edges, pkg("cmd/main.go", "example.com/app/internal/api", 4,
+		"internal/api/handlers.go", "internal/api/routes.go")...)
+
+	list := checker.Evaluate(edges)
+	if len(list) != 1 {
+		t.Fatalf("violations = %+v, want only domain -> db", list)
+	}
+	if v := list[0]; v.Rule != "no-sql" || v.TargetFile != "internal/db/users_sql.go" || v.Line != 3 {
+		t.Errorf("violation = %+v", v)
+	}
+}
+
+func TestEvaluate_Cycles(t *testing.T) {
+	checker := load(t, t.TempDir(), config.ArchitectureConfig{
+		Rules: []config.ArchitectureRule{
+			{ID: "layers", NoCycles: []string{"api", "core", "store"}},
+			{ID: "core-files", NoCycles: []string{"core"}},
+		},
+	})
+
+	list := checker.Evaluate([]index.ImportEdge{
+		edge("api/handler.go", "core/service.go", 1),
+		edge("core/service.go", "store/db.go", 1),
+		edge("store/db.go", "api/types.go", 4), // closes api -> core -> store -> api
+		edge("core/a.go", "core/b.go", 2),
+		edge("core/b.go", "core/a.go", 2),
+		edge("core/b.go", "core/c.go", 3), // c does not import back
+	})
+
+	var layers, files int
+	for _, v := range list {
+		switch v.Rule {
+		case "layers":
+			layers++
+			if v.Kind != KindCycle || v.Message != "import cycle between api, core, store" {
+				t.Errorf("layer cycle = %+v", v)
+			}
+		case "core-files":
+			files++
+			if v.Message != "import cycle among 2 files in core" {
+				t.Errorf("file cycle = %+v", v)
+			}
+		}
+	}
+	if layers != 3 || files != 2 {
+		t.Errorf("got %d layer and %d file cycle violations: %+v", layers, files, list)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]config.ArchitectureRule{
+		"no constraint":     {From: "a"},
+		"deny without from": {Deny: []string{"b"}},
+		"two constraints":   {From: "a", Deny: []string{"b"}, Allow: []string{"c"}},
+		"unknown room":      {From: "room:nope", Deny: []string{"b"}},
+		"bad severity":      {From: "a", Deny: []string{"b"}, Severity: "fatal"},
+		"bad glob":          {To: "a/[", OnlyFrom: []string{"b"}},
+	}
+	for name, r := range tests {
+		arch := config.ArchitectureConfig{Rules: []config.ArchitectureRule{r}}
+		if _, err := Load(t.TempDir(), &config.PalaceConfig{Architecture: &arch}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if c, err := Load(t.TempDir(), &config.PalaceConfig{}); c != nil || err != nil {
+		t.Errorf("no rules: Load() = %v, %v", c, err)
+	}
+}
@@ -0,0 +1,50 @@
+package architecture
+
+import (
+	"fmt"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+// RuleID is the finding rule ID for a violation kind, e.g.
+// "architecture/forbidden".
+func RuleID(kind string) string {
+	return "architecture/" + kind
+}
+
+// RuleIDs lists the rule IDs Findings can report.
+func RuleIDs() []string {
+	return []string{RuleID(KindForbidden), RuleID(KindNotAllowed), RuleID(KindRestricted), RuleID(KindCycle)}
+}
+
+var ruleInfo = map[string]struct{ name, description string }{
+	KindForbidden:  {"ForbiddenDependency", "Import between components that an architecture rule forbids"},
+	KindNotAllowed: {"UnlistedDependency", "Import of a component missing from an architecture rule's allow list"},
+	KindRestricted: {"RestrictedImport", "Import of a component by a file its onlyFrom list does not name"},
+	KindCycle:      {"DependencyCycle", "Import that is part of a cycle an architecture rule bans"},
+}
+
+// Findings reports each violation on its import line, with the imported
+// file as related location.
+func Findings(list []index.ArchitectureViolation) []findings.Finding {
+	out := make([]findings.Finding, 0, len(list))
+	for i := range list {
+		v := &list[i]
+		level := findings.LevelError
+		if v.Severity == SeverityWarning {
+			level = findings.LevelWarning
+		}
+		out = append(out, findings.Finding{
+			RuleID:      RuleID(v.Kind),
+			RuleName:    ruleInfo[v.Kind].name,
+			Description: ruleInfo[v.Kind].description,
+			Level:       level,
+			Message:     fmt.Sprintf("%s (imports %s)", v.Message, v.TargetFile),
+			Location:    findings.Location{Path: v.SourceFile, LineStart: v.Line},
+			Related:     []findings.Location{{Path: v.TargetFile}},
+			Properties:  map[string]any{"rule": v.Rule},
+		})
+	}
+	return out
+}
@@ -6,9 +6,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/llm"
@@ -24,6 +27,12 @@ type Butler struct {
 	entryPoints map[string]string     // path -> room name for entry points
 	config      *config.PalaceConfig
 	memory      *memory.Memory // session memory (optional, may be nil)
+
+	localEmbedderOnce sync.Once
+	localEmbedder     *memory.LocalEmbedder // fitted lazily for embeddingBackend "local"
+
+	listenersMu sync.Mutex
+	listeners   map[*MCPServer]struct{} // connected MCP servers receiving resource notifications
 }
 
 // New creates a new Butler instance.
@@ -90,12 +99,44 @@ func (b *Butler) GetEmbedder() memory.Embedder {
 	if b.config == nil {
 		return nil
 	}
+	if b.config.EmbeddingBackend == "local" {
+		if e := b.getLocalEmbedder(); e != nil {
+			return e
+		}
+		return nil
+	}
+	return newRemoteEmbedder(b.config)
+}
+
+// NewEmbedder returns the embedder configured for the workspace at root
+// without the rest of a Butler: no rooms are loaded and no embedding
+// pipeline is started. db supplies the code chunks a local embedder is
+// fitted on. Returns nil if embeddings are not configured or disabled.
+func NewEmbedder(db *sql.DB, root string, cfg *config.PalaceConfig) memory.Embedder {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.EmbeddingBackend != "local" {
+		return newRemoteEmbedder(cfg)
+	}
+	var mem *memory.Memory
+	if m, err := memory.Open(root); err == nil {
+		defer m.Close()
+		mem = m
+	}
+	if e := loadLocalEmbedder(db, mem, root); e != nil {
+		return e
+	}
+	return nil
+}
 
+// newRemoteEmbedder builds an Ollama or OpenAI embedder from cfg.
+func newRemoteEmbedder(cfg *config.PalaceConfig) memory.Embedder {
 	embCfg := memory.EmbeddingConfig{
-		Backend: b.config.EmbeddingBackend,
-		Model:   b.config.EmbeddingModel,
-		URL:     b.config.EmbeddingURL,
-		APIKey:  b.config.EmbeddingAPIKey,
+		Backend: cfg.EmbeddingBackend,
+		Model:   cfg.EmbeddingModel,
+		URL:     cfg.EmbeddingURL,
+		APIKey:  cfg.EmbeddingAPIKey,
 	}
 
 	embedder, err := memory.NewEmbedder(embCfg)
@@ -106,6 +147,87 @@ func (b *Butler) GetEmbedder() memory.Embedder {
 	return embedder
 }
 
+// getLocalEmbedder returns the workspace's local embedder, loading it once.
+func (b *Butler) getLocalEmbedder() *memory.LocalEmbedder {
+	b.localEmbedderOnce.Do(func() {
+		b.localEmbedder = loadLocalEmbedder(b.db, b.memory, b.root)
+	})
+	return b.localEmbedder
+}
+
+// loadLocalEmbedder loads the workspace's fitted local embedder, fitting it
+// on the indexed code chunks and memory records the first time. The fitted
+// model is kept fixed so stored embeddings stay comparable, until the corpus
+// has grown enough to warrant a refit. A refit changes the model name, so
+// code embeddings are redone on the next scan and memory embeddings by
+// embedding_sync. db and mem may be nil.
+func loadLocalEmbedder(db *sql.DB, mem *memory.Memory, root string) *memory.LocalEmbedder {
+	path := memory.LocalEmbedderPath(root)
+	e, err := memory.LoadLocalEmbedder(path)
+	if err == nil {
+		corpus, cerr := localCorpusSize(db, mem)
+		if cerr != nil || !e.NeedsRefit(corpus) {
+			return e
+		}
+	} else if !os.IsNotExist(err) {
+		log.Printf("local embedder unreadable, refitting: %v", err)
+	}
+
+	e = memory.NewLocalEmbedder()
+	if err := fitLocalEmbedder(e, db, mem); err != nil {
+		log.Printf("fit local embedder: %v", err)
+	}
+	if e.Documents() > 0 {
+		if err := e.Save(path); err != nil {
+			log.Printf("save local embedder: %v", err)
+		}
+	}
+	return e
+}
+
+// localCorpusSize counts the documents fitLocalEmbedder would fit on.
+func localCorpusSize(db *sql.DB, mem *memory.Memory) (int, error) {
+	n := 0
+	if db != nil {
+		if err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM chunks`).Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	if mem != nil {
+		m, err := mem.LocalCorpusSize()
+		if err != nil {
+			return 0, err
+		}
+		n += m
+	}
+	return n, nil
+}
+
+// fitLocalEmbedder fits e on the index's chunks and the memory records.
+func fitLocalEmbedder(e *memory.LocalEmbedder, db *sql.DB, mem *memory.Memory) error {
+	if db != nil {
+		rows, err := db.QueryContext(context.Background(), `SELECT content FROM chunks`)
+		if err != nil {
+			return fmt.Errorf("query chunks: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var content string
+			if err := rows.Scan(&content); err != nil {
+				return err
+			}
+			e.Fit(content)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	if mem != nil {
+		return mem.FitLocalEmbedder(e)
+	}
+	return nil
+}
+
 // GetLLMClient returns an LLM client based on the palace configuration.
 // Returns nil and an error if LLM is not configured or disabled.
 func (b *Butler) GetLLMClient() (llm.Client, error) {
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/deadcode"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/depgraph"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
@@ -21,6 +23,7 @@ func (b *Butler) GetContextForTaskWithOptions(query string, limit, maxTokens int
 	opts := &index.ContextOptions{
 		MaxTokens:    maxTokens,
 		IncludeTests: includeTests,
+		Embedder:     b.codeEmbedder(),
 	}
 	return index.GetContextForTaskWithOptions(b.db, query, limit, opts)
 }
@@ -56,6 +59,7 @@ func (b *Butler) GetEnhancedContext(opts EnhancedContextOptions) (*EnhancedConte
 		IncludeTests: opts.IncludeTests,
 		SmartContext: smartOpts,
 		EditHistory:  editHistory,
+		Embedder:     b.codeEmbedder(),
 	}
 	codeContext, err := index.GetContextForTaskWithOptions(b.db, opts.Query, opts.Limit, codeOpts)
 	if err != nil {
@@ -550,6 +554,28 @@ func (b *Butler) GetImpact(target string) (*index.ImpactResult, error) {
 	return index.GetImpact(b.db, target)
 }
 
+// GetFileOwnership returns blame-based ownership for a file, or nil when the
+// ownership pass has not analyzed it.
+func (b *Butler) GetFileOwnership(path string) (*index.FileOwnership, error) {
+	return index.GetFileOwnership(b.db, path)
+}
+
+// GetArchitectureViolations returns the open architecture violations the
+// last scan recorded, for the imports of path or for every file.
+func (b *Butler) GetArchitectureViolations(path string) ([]index.ArchitectureViolation, error) {
+	return index.GetArchitectureViolations(b.db, path)
+}
+
+// GetArchitectureDiff returns the architecture violations the last scan
+// found or resolved.
+func (b *Butler) GetArchitectureDiff() (*index.ArchitectureDiff, error) {
+	scan, err := index.LatestScan(b.db)
+	if err != nil {
+		return nil, err
+	}
+	return index.GetArchitectureDiff(b.db, scan.ID)
+}
+
 // ListSymbols lists all symbols of a given kind.
 func (b *Butler) ListSymbols(kind string, limit int) ([]index.SymbolInfo, error) {
 	return index.SearchSymbolsByKind(b.db, kind, limit)
@@ -570,9 +596,10 @@ func (b *Butler) GetDependencyGraph(rootFiles []string) ([]index.DependencyNode,
 	return index.GetDependencyGraph(b.db, rootFiles)
 }
 
-// GetIncomingCalls returns all locations that call the given symbol.
-func (b *Butler) GetIncomingCalls(symbolName string) ([]index.CallSite, error) {
-	return index.GetIncomingCalls(b.db, symbolName)
+// GetIncomingCalls returns all locations that call the given symbol. When
+// filePath is set, the symbol is the definition in that file.
+func (b *Butler) GetIncomingCalls(symbolName, filePath string) ([]index.CallSite, error) {
+	return index.GetIncomingCallsIn(b.db, symbolName, filePath)
 }
 
 // GetOutgoingCalls returns all functions called by the given symbol.
@@ -753,3 +780,13 @@ func (b *Butler) GetBoundedAuthoritativeContext(filePath string, cfg *BoundedCon
 
 	return result, nil
 }
+
+// GetGraphReport analyzes the workspace's import graph.
+func (b *Butler) GetGraphReport() (*depgraph.Report, error) {
+	return depgraph.Analyze(b.root, b.db)
+}
+
+// GetDeadCode reports the workspace's unused symbols and files.
+func (b *Butler) GetDeadCode() (*deadcode.Report, error) {
+	return deadcode.Analyze(b.root, b.db, b.memory)
+}
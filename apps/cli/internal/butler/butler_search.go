@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 )
 
 // Search performs a full-text search across the codebase.
@@ -32,6 +34,8 @@ func (b *Butler) Search(query string, opts SearchOptions) ([]GroupedResults, err
 			c.start_line,
 			c.end_line,
 			c.content,
+			COALESCE(c.symbol, ''),
+			COALESCE(c.symbol_kind, ''),
 			bm25(chunks_fts) as base_score
 		FROM chunks_fts
 		JOIN chunks c ON c.path = chunks_fts.path AND c.chunk_index = chunks_fts.chunk_index
@@ -49,18 +53,12 @@ func (b *Butler) Search(query string, opts SearchOptions) ([]GroupedResults, err
 	for rows.Next() {
 		var r SearchResult
 		var baseScore float64
-		if err := rows.Scan(&r.Path, &r.ChunkIndex, &r.StartLine, &r.EndLine, &r.Snippet, &baseScore); err != nil {
+		if err := rows.Scan(&r.Path, &r.ChunkIndex, &r.StartLine, &r.EndLine, &r.Snippet, &r.Symbol, &r.SymbolKind, &baseScore); err != nil {
 			return nil, fmt.Errorf("scan result: %w", err)
 		}
 
 		r.Score = b.calculateScore(baseScore, r.Path, query)
-
-		if roomName, ok := b.entryPoints[r.Path]; ok {
-			r.Room = roomName
-			r.IsEntry = true
-		} else {
-			r.Room = b.inferRoom(r.Path)
-		}
+		r.Room, r.IsEntry = b.resultRoom(r.Path)
 
 		if opts.RoomFilter != "" && r.Room != opts.RoomFilter {
 			continue
@@ -77,6 +75,8 @@ func (b *Butler) Search(query string, opts SearchOptions) ([]GroupedResults, err
 		return results[i].Score > results[j].Score
 	})
 
+	results = b.fuseCodeVectors(query, results, opts)
+
 	if len(results) > opts.Limit {
 		results = results[:opts.Limit]
 	}
@@ -84,6 +84,103 @@ func (b *Butler) Search(query string, opts SearchOptions) ([]GroupedResults, err
 	return b.groupByRoom(results), nil
 }
 
+// codeVectorWeight is the share of a fused search score that comes from
+// vector similarity; the rest is the full-text score relative to the best
+// full-text match.
+const codeVectorWeight = 0.4
+
+// fuseCodeVectors blends vector similarity into ranked full-text results and
+// adds similar chunks the full-text search missed. Each score becomes a
+// weighted sum of the normalized full-text score and the similarity, so both
+// signals count. Results are returned unchanged when no embedder is
+// configured or no code has been embedded with its model.
+func (b *Butler) fuseCodeVectors(query string, results []SearchResult, opts SearchOptions) []SearchResult {
+	embedder := b.codeEmbedder()
+	if embedder == nil || !index.HasCodeEmbeddings(b.db, embedder.Model()) {
+		return results
+	}
+	vec, err := embedder.Embed(query)
+	if err != nil {
+		return results
+	}
+	keep := func(path string) bool {
+		room, _ := b.resultRoom(path)
+		return opts.RoomFilter == "" || room == opts.RoomFilter
+	}
+	hits, err := index.SearchCodeVectors(b.db, vec, embedder.Model(), opts.Limit*3, keep)
+	if err != nil || len(hits) == 0 {
+		return results
+	}
+
+	similarity := make(map[string]float32, len(hits))
+	for _, h := range hits {
+		similarity[fmt.Sprintf("%s#%d", h.Path, h.ChunkIndex)] = h.Similarity
+	}
+
+	var best float64
+	for _, r := range results {
+		best = max(best, r.Score)
+	}
+
+	fused := make([]SearchResult, 0, len(results)+len(hits))
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		key := fmt.Sprintf("%s#%d", r.Path, r.ChunkIndex)
+		seen[key] = true
+		lexical := 0.0
+		if best > 0 {
+			lexical = r.Score / best
+		}
+		r.Score = (1-codeVectorWeight)*lexical + codeVectorWeight*float64(similarity[key])
+		fused = append(fused, r)
+	}
+	for _, h := range hits {
+		key := fmt.Sprintf("%s#%d", h.Path, h.ChunkIndex)
+		if seen[key] {
+			continue
+		}
+		chunk, err := index.GetChunk(b.db, h.Path, h.ChunkIndex)
+		if err != nil {
+			continue // embedding outlived its chunk
+		}
+		r := SearchResult{
+			Path:       h.Path,
+			ChunkIndex: chunk.ChunkIndex,
+			StartLine:  chunk.StartLine,
+			EndLine:    chunk.EndLine,
+			Snippet:    chunk.Content,
+			Symbol:     chunk.Symbol,
+			SymbolKind: chunk.SymbolKind,
+			Score:      codeVectorWeight * float64(h.Similarity),
+		}
+		r.Room, r.IsEntry = b.resultRoom(h.Path)
+		fused = append(fused, r)
+		seen[key] = true
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+	return fused
+}
+
+// resultRoom returns the room a result path belongs to and whether it is
+// that room's entry point.
+func (b *Butler) resultRoom(path string) (string, bool) {
+	if roomName, ok := b.entryPoints[path]; ok {
+		return roomName, true
+	}
+	return b.inferRoom(path), false
+}
+
+// codeEmbedder returns the configured embedder for code retrieval, or nil.
+func (b *Butler) codeEmbedder() index.Embedder {
+	if embedder := b.GetEmbedder(); embedder != nil {
+		return embedder
+	}
+	return nil
+}
+
 // calculateScore computes the final relevance score for a search result.
 func (b *Butler) calculateScore(baseScore float64, path, query string) float64 {
 	score := -baseScore
@@ -9,7 +9,9 @@ import (
 
 	_ "modernc.org/sqlite"
 
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/fsutil"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
@@ -512,7 +514,7 @@ func TestButlerIntegrated(t *testing.T) {
 	// Initialize schema manually for testing (mimicking index.indexMigrateV0)
 	stmts := []string{
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
-		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
+		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT, symbol TEXT DEFAULT '', symbol_kind TEXT DEFAULT '');`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
 		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
@@ -528,7 +530,7 @@ func TestButlerIntegrated(t *testing.T) {
 	// Insert test data
 	now := time.Now().UTC().Format(time.RFC3339)
 	db.ExecContext(context.Background(), `INSERT INTO files VALUES (?, ?, ?, ?, ?, ?);`, "auth.go", "h1", 100, now, now, "go")
-	db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?);`, 1, "auth.go", 0, 1, 10, "func HandleAuth() {}")
+	db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", 0, 1, 10, "func HandleAuth() {}", "HandleAuth", "function")
 	db.ExecContext(context.Background(), `INSERT INTO chunks_fts VALUES (?, ?, ?);`, "auth.go", "func HandleAuth() {}", 0)
 	db.ExecContext(context.Background(), `INSERT INTO symbols VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", "HandleAuth", "function", 1, 10, "()", "Auth handler", nil, 1)
 	db.ExecContext(context.Background(), `INSERT INTO symbols_fts VALUES (?, ?, ?, ?);`, "HandleAuth", "auth.go", "function", "Auth handler")
@@ -1001,7 +1003,7 @@ func TestGetContextForTask(t *testing.T) {
 	// Initialize schema
 	stmts := []string{
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
-		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
+		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT, symbol TEXT DEFAULT '', symbol_kind TEXT DEFAULT '');`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
 		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
@@ -1015,7 +1017,7 @@ func TestGetContextForTask(t *testing.T) {
 	// Insert test data
 	now := time.Now().UTC().Format(time.RFC3339)
 	db.ExecContext(context.Background(), `INSERT INTO files VALUES (?, ?, ?, ?, ?, ?);`, "auth.go", "h1", 100, now, now, "go")
-	db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?);`, 1, "auth.go", 0, 1, 10, "func HandleAuth() {}")
+	db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", 0, 1, 10, "func HandleAuth() {}", "HandleAuth", "function")
 	db.ExecContext(context.Background(), `INSERT INTO chunks_fts VALUES (?, ?, ?);`, "auth.go", "func HandleAuth() {}", 0)
 
 	b := &Butler{
@@ -1044,7 +1046,7 @@ func TestGetContextForTaskWithOptions(t *testing.T) {
 	// Initialize schema
 	stmts := []string{
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
-		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
+		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT, symbol TEXT DEFAULT '', symbol_kind TEXT DEFAULT '');`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
 		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
@@ -1080,7 +1082,7 @@ func TestGetEnhancedContext(t *testing.T) {
 	// Initialize schema
 	stmts := []string{
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
-		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
+		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT, symbol TEXT DEFAULT '', symbol_kind TEXT DEFAULT '');`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
 		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
@@ -1621,3 +1623,86 @@ func TestConversationMethods(t *testing.T) {
 		_ = convs
 	})
 }
+
+func TestSearchFusesCodeVectors(t *testing.T) {
+	root := t.TempDir()
+	db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	files := map[string]string{
+		"auth/session.go": "package auth\n\n// Refresh renews the session token before it expires.\nfunc Refresh() {}\n",
+		"auth/login.go":   "package auth\n\n// Login checks the user's password.\nfunc Login() {}\n",
+		"web/page.go":     "package web\n\n// Render draws the page template.\nfunc Render() {}\n",
+	}
+	var records []index.FileRecord
+	for path, src := range files {
+		fa, err := analysis.Analyze([]byte(src), path)
+		if err != nil {
+			t.Fatalf("Analyze(%s) error = %v", path, err)
+		}
+		records = append(records, index.FileRecord{
+			Path: path, Hash: path, Size: int64(len(src)), ModTime: time.Now(), Language: "go",
+			Chunks: fsutil.ChunkContent(src, 120, 8000), Analysis: fa,
+		})
+	}
+	if _, err := index.WriteScan(db, root, records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	b := &Butler{
+		db:          db,
+		root:        root,
+		config:      &config.PalaceConfig{EmbeddingBackend: "local"},
+		rooms:       map[string]model.Room{"auth": {Name: "auth", EntryPoints: []string{"auth/login.go"}}},
+		entryPoints: map[string]string{"auth/login.go": "auth"},
+	}
+	if _, err := index.EmbedCode(db, b.codeEmbedder()); err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+
+	flatten := func(groups []GroupedResults) []SearchResult {
+		var out []SearchResult
+		for _, g := range groups {
+			out = append(out, g.Results...)
+		}
+		return out
+	}
+
+	groups, err := b.Search("session token", SearchOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	results := flatten(groups)
+	paths := map[string]SearchResult{}
+	for _, r := range results {
+		paths[r.Path] = r
+	}
+	top, ok := paths["auth/session.go"]
+	if !ok {
+		t.Fatalf("results = %+v, want auth/session.go", results)
+	}
+	// The full-text match keeps the weight of its full-text score, so it
+	// outscores chunks found by similarity alone.
+	for _, r := range results {
+		if r.Path != top.Path && r.Score >= top.Score {
+			t.Errorf("%s scored %.3f, not below the full-text match %.3f", r.Path, r.Score, top.Score)
+		}
+	}
+	if _, ok := paths["web/page.go"]; !ok {
+		t.Errorf("results = %+v, want vector-only web/page.go", results)
+	}
+
+	// Vector hits honour the room filter.
+	groups, err = b.Search("session token", SearchOptions{Limit: 10, RoomFilter: "auth"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	for _, r := range flatten(groups) {
+		if r.Room != "auth" {
+			t.Errorf("room filter leaked %s (room %q)", r.Path, r.Room)
+		}
+	}
+}
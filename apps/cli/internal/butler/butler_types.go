@@ -15,6 +15,8 @@ type SearchResult struct {
 	StartLine  int     `json:"startLine"`
 	EndLine    int     `json:"endLine"`
 	Snippet    string  `json:"snippet"`
+	Symbol     string  `json:"symbol,omitempty"` // symbol the chunk belongs to
+	SymbolKind string  `json:"symbolKind,omitempty"`
 	Score      float64 `json:"score"`
 	IsEntry    bool    `json:"isEntry,omitempty"`
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
@@ -76,9 +77,12 @@ type MCPServer struct {
 	writer io.Writer
 	mode   MCPMode // Operational mode (agent or human)
 
+	writeMu sync.Mutex // Serializes writes to writer
+
 	// Session tracking for autonomy features
 	currentSessionID string // Active session ID for this connection
 	autoSessionUsed  bool   // True if current session was auto-created
+	connectionID     string // Owns playbook runs started without a session
 
 	// Session timeout tracking
 	lastActivity        time.Time // Time of last tool call
@@ -91,6 +95,11 @@ type MCPServer struct {
 	// Proactive intelligence: conflict monitoring
 	trackedFiles map[string]time.Time // Files accessed by this session with last access time
 
+	// Resource notifications
+	resourceMu    sync.Mutex      // Guards trackedFiles and subscriptions, read by the file watcher
+	subscriptions map[string]bool // Resource URIs subscribed via resources/subscribe
+	notify        func([]byte)    // Delivers server-initiated messages; nil writes to writer
+
 	// Proactive intelligence: briefing updates
 	lastBriefingTime time.Time // Time of last briefing update shown
 
@@ -130,6 +139,7 @@ type mcpServerInfo struct {
 type mcpCapabilities struct {
 	Tools     *mcpToolsCap     `json:"tools,omitempty"`
 	Resources *mcpResourcesCap `json:"resources,omitempty"`
+	Prompts   *mcpPromptsCap   `json:"prompts,omitempty"`
 }
 
 type mcpToolsCap struct {
@@ -317,6 +327,8 @@ func (s *MCPServer) trackFileAccess(filePath string) {
 	if filePath == "" {
 		return
 	}
+	s.resourceMu.Lock()
+	defer s.resourceMu.Unlock()
 	if s.trackedFiles == nil {
 		s.trackedFiles = make(map[string]time.Time)
 	}
@@ -332,14 +344,21 @@ func (s *MCPServer) checkFileConflicts() []string {
 		return nil
 	}
 
-	if len(s.trackedFiles) == 0 || s.currentSessionID == "" {
+	s.resourceMu.Lock()
+	tracked := make(map[string]time.Time, len(s.trackedFiles))
+	for filePath, lastAccess := range s.trackedFiles {
+		tracked[filePath] = lastAccess
+	}
+	s.resourceMu.Unlock()
+
+	if len(tracked) == 0 || s.currentSessionID == "" {
 		return nil
 	}
 
 	var warnings []string
 	mem := s.butler.Memory()
 
-	for filePath, lastAccess := range s.trackedFiles {
+	for filePath, lastAccess := range tracked {
 		// Check if another agent has modified this file since we last accessed it
 		conflict, err := mem.CheckConflict(filePath, s.currentSessionID)
 		if err != nil || conflict == nil {
@@ -484,6 +503,10 @@ func (s *MCPServer) ensureSession(agentName string) (string, bool, error) {
 
 // Serve runs the MCP server, reading JSON-RPC requests from stdin.
 func (s *MCPServer) Serve() error {
+	// Receive resource notifications while connected
+	s.butler.addResourceListener(s)
+	defer s.butler.removeResourceListener(s)
+
 	// Start session timeout checker (if configured)
 	s.startSessionTimeoutChecker()
 	defer s.stopSessionTimeoutChecker()
@@ -532,6 +555,14 @@ func (s *MCPServer) handleRequest(req jsonRPCRequest) jsonRPCResponse {
 		return s.handleResourcesList(req)
 	case "resources/read":
 		return s.handleResourcesRead(req)
+	case "resources/subscribe":
+		return s.handleResourcesSubscribe(req)
+	case "resources/unsubscribe":
+		return s.handleResourcesUnsubscribe(req)
+	case "prompts/list":
+		return s.handlePromptsList(req)
+	case "prompts/get":
+		return s.handlePromptsGet(req)
 	case "ping":
 		return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]string{}}
 	default:
@@ -549,7 +580,8 @@ func (s *MCPServer) handleInitialize(req jsonRPCRequest) jsonRPCResponse {
 		ProtocolVersion: "2024-11-05",
 		Capabilities: mcpCapabilities{
 			Tools:     &mcpToolsCap{},
-			Resources: &mcpResourcesCap{},
+			Resources: &mcpResourcesCap{Subscribe: true},
+			Prompts:   &mcpPromptsCap{},
 		},
 		ServerInfo: mcpServerInfo{
 			Name:    "mind-palace",
@@ -1171,7 +1203,14 @@ func (s *MCPServer) writeResponse(resp jsonRPCResponse) error {
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprintf(s.writer, "%s\n", data)
+	return s.writeLine(data)
+}
+
+// writeLine writes a single newline-delimited JSON message to the writer.
+func (s *MCPServer) writeLine(data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, err := fmt.Fprintf(s.writer, "%s\n", data)
 	return err
 }
 
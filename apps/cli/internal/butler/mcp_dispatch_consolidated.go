@@ -362,6 +362,10 @@ func (s *MCPServer) dispatchExplore(id any, args map[string]interface{}, action
 		return s.toolExploreCallees(id, args)
 	case "graph":
 		return s.toolExploreGraph(id, args)
+	case "architecture":
+		return s.toolExploreArchitecture(id, args)
+	case "deadcode":
+		return s.toolExploreDeadcode(id, args)
 	default:
 		return consolidatedToolError(id, "explore", "action", action)
 	}
@@ -0,0 +1,531 @@
+package butler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCPSessionHeader is the HTTP header carrying the MCP session identifier
+// for the Streamable HTTP transport.
+const MCPSessionHeader = "Mcp-Session-Id"
+
+// DefaultMCPHTTPPath is the endpoint path served by the HTTP transport.
+const DefaultMCPHTTPPath = "/mcp"
+
+// maxMCPRequestBody limits the size of a single POSTed JSON-RPC payload.
+const maxMCPRequestBody = 8 << 20 // 8 MiB
+
+// sseKeepAliveInterval controls how often comment frames are sent on idle SSE streams
+// so that proxies do not close the connection.
+const sseKeepAliveInterval = 25 * time.Second
+
+// mcpSessionIdleTimeout closes sessions that have sent no request and held
+// no event stream for this long. Clients that disconnect without a DELETE
+// would otherwise keep their MCPServer and resource listener forever.
+const mcpSessionIdleTimeout = 30 * time.Minute
+
+// sseEventBuffer is the number of server-initiated messages queued per session
+// while no SSE stream is reading them.
+const sseEventBuffer = 64
+
+// MCPHTTPServer serves the MCP protocol over the Streamable HTTP transport.
+// Each MCP session gets its own MCPServer so per-connection state
+// (current session, tracked files, task focus, pins) is never shared.
+type MCPHTTPServer struct {
+	butler *Butler
+	mode   MCPMode
+	path   string
+
+	// idleTimeout is how long a session may stay unused before it is closed.
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*mcpHTTPSession
+}
+
+// mcpHTTPSession holds the state of one Streamable HTTP client session.
+type mcpHTTPSession struct {
+	id     string
+	server *MCPServer
+
+	// mu serializes request handling so MCPServer state is only touched by one request at a time.
+	mu sync.Mutex
+
+	// events queues server-initiated messages for the SSE stream.
+	events chan []byte
+
+	closed chan struct{}
+	once   sync.Once
+
+	// lastActive and streams are guarded by MCPHTTPServer.mu.
+	lastActive time.Time
+	streams    int // open SSE streams
+}
+
+// NewMCPHTTPServer creates a Streamable HTTP MCP server backed by the given Butler.
+func NewMCPHTTPServer(butler *Butler, mode MCPMode) *MCPHTTPServer {
+	return &MCPHTTPServer{
+		butler:      butler,
+		mode:        mode,
+		path:        DefaultMCPHTTPPath,
+		idleTimeout: mcpSessionIdleTimeout,
+		sessions:    make(map[string]*mcpHTTPSession),
+	}
+}
+
+// Path returns the endpoint path the server handles.
+func (h *MCPHTTPServer) Path() string {
+	return h.path
+}
+
+// SessionCount returns the number of active sessions.
+func (h *MCPHTTPServer) SessionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+// ListenAndServe serves the MCP endpoint on addr until ctx is canceled.
+func (h *MCPHTTPServer) ListenAndServe(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(h.path, h)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go h.evictIdleSessions(ctx)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		h.closeAll()
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		h.closeAll()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// ServeHTTP implements http.Handler for the MCP endpoint.
+func (h *MCPHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !validOrigin(r) {
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleGet(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost processes one JSON-RPC message or a batch of messages.
+func (h *MCPHTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMCPRequestBody))
+	if err != nil {
+		writeHTTPRPCError(w, http.StatusBadRequest, -32700, "Parse error", err.Error())
+		return
+	}
+
+	reqs, batch, err := parseJSONRPCPayload(body)
+	if err != nil {
+		writeHTTPRPCError(w, http.StatusBadRequest, -32700, "Parse error", err.Error())
+		return
+	}
+
+	sess, status, msg := h.resolveSession(r, reqs)
+	if sess == nil {
+		writeHTTPRPCError(w, status, -32600, msg, "")
+		return
+	}
+
+	// Requests without an ID are notifications (or client responses); they get no reply.
+	var responses []jsonRPCResponse
+	sess.mu.Lock()
+	for _, req := range reqs {
+		if req.Method == "" {
+			continue
+		}
+		resp := sess.server.handleRequest(req)
+		if req.ID != nil {
+			responses = append(responses, resp)
+		}
+	}
+	sess.mu.Unlock()
+
+	w.Header().Set(MCPSessionHeader, sess.id)
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if acceptsEventStream(r) && !acceptsJSON(r) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		setSSEHeaders(w)
+		w.WriteHeader(http.StatusOK)
+		for _, resp := range responses {
+			data, err := json.Marshal(resp)
+			if err != nil {
+				continue
+			}
+			writeSSEEvent(w, data)
+		}
+		flusher.Flush()
+		return
+	}
+
+	var payload any = responses[0]
+	if batch {
+		payload = responses
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// handleGet opens the SSE stream for server-initiated messages on an existing session.
+func (h *MCPHTTPServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "client must accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	sess := h.lookupSession(r.Header.Get(MCPSessionHeader))
+	if sess == nil {
+		http.Error(w, "unknown or missing session", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	h.mu.Lock()
+	sess.streams++
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		sess.streams--
+		sess.lastActive = time.Now()
+		h.mu.Unlock()
+	}()
+
+	setSSEHeaders(w)
+	w.Header().Set(MCPSessionHeader, sess.id)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sess.closed:
+			return
+		case data := <-sess.events:
+			writeSSEEvent(w, data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// handleDelete terminates a session at the client's request.
+func (h *MCPHTTPServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(MCPSessionHeader)
+	h.mu.Lock()
+	sess, ok := h.sessions[id]
+	if ok {
+		delete(h.sessions, id)
+	}
+	h.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown or missing session", http.StatusNotFound)
+		return
+	}
+	sess.close()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveSession returns the session for a POST, creating one for an initialize request.
+// When no session can be used it returns nil with an HTTP status and message.
+func (h *MCPHTTPServer) resolveSession(r *http.Request, reqs []jsonRPCRequest) (*mcpHTTPSession, int, string) {
+	id := r.Header.Get(MCPSessionHeader)
+
+	isInit := false
+	for _, req := range reqs {
+		if req.Method == "initialize" {
+			isInit = true
+			break
+		}
+	}
+
+	if isInit {
+		if len(reqs) > 1 {
+			return nil, http.StatusBadRequest, "initialize must not be batched"
+		}
+		if id != "" {
+			if sess := h.lookupSession(id); sess != nil {
+				return sess, 0, ""
+			}
+		}
+		return h.newSession(), 0, ""
+	}
+
+	if id == "" {
+		return nil, http.StatusBadRequest, "missing " + MCPSessionHeader + " header"
+	}
+	sess := h.lookupSession(id)
+	if sess == nil {
+		return nil, http.StatusNotFound, "session not found"
+	}
+	return sess, 0, ""
+}
+
+// newSession creates and registers a new session with its own MCPServer.
+func (h *MCPHTTPServer) newSession() *mcpHTTPSession {
+	sess := &mcpHTTPSession{
+		id:         newMCPSessionID(),
+		events:     make(chan []byte, sseEventBuffer),
+		closed:     make(chan struct{}),
+		lastActive: time.Now(),
+	}
+	sess.server = &MCPServer{
+		butler: h.butler,
+		writer: io.Discard,
+		mode:   h.mode,
+		notify: sess.push,
+	}
+	sess.server.startSessionTimeoutChecker()
+	h.butler.addResourceListener(sess.server)
+
+	h.mu.Lock()
+	h.sessions[sess.id] = sess
+	h.mu.Unlock()
+	return sess
+}
+
+// lookupSession returns the session with the given ID or nil, and marks
+// it as active.
+func (h *MCPHTTPServer) lookupSession(id string) *mcpHTTPSession {
+	if id == "" {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sess := h.sessions[id]
+	if sess != nil {
+		sess.lastActive = time.Now()
+	}
+	return sess
+}
+
+// evictIdleSessions closes idle sessions until ctx is canceled.
+func (h *MCPHTTPServer) evictIdleSessions(ctx context.Context) {
+	checkInterval := h.idleTimeout / 6
+	if checkInterval < time.Minute {
+		checkInterval = time.Minute
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the sessions without an open event stream that have
+// been unused for longer than the idle timeout, and returns how many it
+// closed.
+func (h *MCPHTTPServer) evictIdle(now time.Time) int {
+	var idle []*mcpHTTPSession
+	h.mu.Lock()
+	for id, sess := range h.sessions {
+		if sess.streams == 0 && now.Sub(sess.lastActive) > h.idleTimeout {
+			idle = append(idle, sess)
+			delete(h.sessions, id)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, sess := range idle {
+		sess.close()
+	}
+	return len(idle)
+}
+
+// closeAll terminates every open session.
+func (h *MCPHTTPServer) closeAll() {
+	h.mu.Lock()
+	sessions := h.sessions
+	h.sessions = make(map[string]*mcpHTTPSession)
+	h.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.close()
+	}
+}
+
+// close ends the agent session and releases the SSE stream.
+func (sess *mcpHTTPSession) close() {
+	sess.once.Do(func() {
+		sess.server.butler.removeResourceListener(sess.server)
+		sess.mu.Lock()
+		sess.server.stopSessionTimeoutChecker()
+		sess.server.cleanupOnDisconnect()
+		sess.mu.Unlock()
+		close(sess.closed)
+	})
+}
+
+// push queues a server-initiated message for the session's SSE stream.
+// Messages are dropped when the session is closed or the queue is full,
+// e.g. because the client never opened the stream.
+func (sess *mcpHTTPSession) push(data []byte) {
+	select {
+	case <-sess.closed:
+	case sess.events <- data:
+	default:
+	}
+}
+
+// parseJSONRPCPayload decodes a single JSON-RPC message or a batch array.
+func parseJSONRPCPayload(body []byte) ([]jsonRPCRequest, bool, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, false, errors.New("empty body")
+	}
+	if trimmed[0] == '[' {
+		var reqs []jsonRPCRequest
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			return nil, true, err
+		}
+		if len(reqs) == 0 {
+			return nil, true, errors.New("empty batch")
+		}
+		return reqs, true, nil
+	}
+	var req jsonRPCRequest
+	if err := json.Unmarshal(trimmed, &req); err != nil {
+		return nil, false, err
+	}
+	return []jsonRPCRequest{req}, false, nil
+}
+
+// newMCPSessionID returns a random, URL-safe session identifier.
+func newMCPSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("mcp-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// validOrigin rejects browser requests from non-local origins to prevent DNS rebinding.
+// Requests without an Origin header (non-browser clients) are allowed.
+func validOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	// Same-origin requests are fine regardless of the bind address.
+	reqHost, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		reqHost = r.Host
+	}
+	return strings.EqualFold(host, reqHost)
+}
+
+// acceptsEventStream reports whether the client accepts SSE responses.
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// acceptsJSON reports whether the client accepts plain JSON responses.
+func acceptsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return accept == "" || strings.Contains(accept, "application/json") || strings.Contains(accept, "*/*")
+}
+
+func setSSEHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+}
+
+func writeSSEEvent(w io.Writer, data []byte) {
+	_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+}
+
+// writeHTTPRPCError writes a JSON-RPC error body with the given HTTP status.
+func writeHTTPRPCError(w http.ResponseWriter, status, code int, message, data string) {
+	resp := jsonRPCResponse{
+		JSONRPC: "2.0",
+		Error:   &rpcError{Code: code, Message: message},
+	}
+	if data != "" {
+		resp.Error.Data = data
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write MCP error response: %v\n", err)
+	}
+}
@@ -0,0 +1,245 @@
+package butler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postMCP(t *testing.T, url, sessionID, accept string, body any) (*http.Response, []byte) {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if accept == "" {
+		accept = "application/json, text/event-stream"
+	}
+	req.Header.Set("Accept", accept)
+	if sessionID != "" {
+		req.Header.Set(MCPSessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("read body error = %v", err)
+	}
+	return resp, buf.Bytes()
+}
+
+func initializeMCPSession(t *testing.T, url string) string {
+	t.Helper()
+	resp, body := postMCP(t, url, "", "", map[string]any{"jsonrpc": "2.0", "id": 1, "method": "initialize"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize status = %d, body = %s", resp.StatusCode, body)
+	}
+	id := resp.Header.Get(MCPSessionHeader)
+	if id == "" {
+		t.Fatal("initialize response missing session header")
+	}
+	return id
+}
+
+func TestMCPHTTPSessionsAreIsolated(t *testing.T) {
+	_, b := setupMCPServer(t)
+	h := NewMCPHTTPServer(b, MCPModeAgent)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	sessA := initializeMCPSession(t, ts.URL)
+	sessB := initializeMCPSession(t, ts.URL)
+	if sessA == sessB {
+		t.Fatal("expected distinct session IDs per connection")
+	}
+	if got := h.SessionCount(); got != 2 {
+		t.Fatalf("SessionCount() = %d, want 2", got)
+	}
+
+	resp, body := postMCP(t, ts.URL, sessA, "", map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "context",
+			"arguments": map[string]any{"action": "focus", "task": "refactor auth handler", "pin": []string{"d_123"}},
+		},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("tools/call status = %d, body = %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(string(body), "Task Focus Set") {
+		t.Fatalf("unexpected tools/call body: %s", body)
+	}
+
+	a := h.lookupSession(sessA).server
+	bSrv := h.lookupSession(sessB).server
+	if a.currentTaskFocus != "refactor auth handler" || len(a.contextPriorityUp) != 1 {
+		t.Fatalf("session A focus = %q pins = %v", a.currentTaskFocus, a.contextPriorityUp)
+	}
+	if bSrv.currentTaskFocus != "" || len(bSrv.contextPriorityUp) != 0 {
+		t.Fatalf("session B should not share focus, got %q pins = %v", bSrv.currentTaskFocus, bSrv.contextPriorityUp)
+	}
+}
+
+func TestMCPHTTPRequestHandling(t *testing.T) {
+	_, b := setupMCPServer(t)
+	h := NewMCPHTTPServer(b, MCPModeAgent)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	// Requests other than initialize need a session.
+	resp, _ := postMCP(t, ts.URL, "", "", map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing session status = %d, want 400", resp.StatusCode)
+	}
+	resp, _ = postMCP(t, ts.URL, "nope", "", map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown session status = %d, want 404", resp.StatusCode)
+	}
+
+	sess := initializeMCPSession(t, ts.URL)
+
+	// Notifications are accepted without a body.
+	resp, _ = postMCP(t, ts.URL, sess, "", map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("notification status = %d, want 202", resp.StatusCode)
+	}
+
+	// Batches return an array of responses.
+	resp, body := postMCP(t, ts.URL, sess, "", []map[string]any{
+		{"jsonrpc": "2.0", "id": 2, "method": "ping"},
+		{"jsonrpc": "2.0", "id": 3, "method": "tools/list"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("batch status = %d", resp.StatusCode)
+	}
+	var batch []map[string]any
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) != 2 {
+		t.Fatalf("batch response = %s (err %v)", body, err)
+	}
+
+	// SSE-only clients get the response as an event stream.
+	resp, body = postMCP(t, ts.URL, sess, "text/event-stream", map[string]any{"jsonrpc": "2.0", "id": 4, "method": "ping"})
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if !strings.Contains(string(body), "data: {") {
+		t.Fatalf("SSE body = %s", body)
+	}
+
+	// Cross-origin browser requests are rejected.
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, ts.URL, strings.NewReader(`{}`))
+	req.Header.Set("Origin", "https://evil.example.com")
+	cross, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	cross.Body.Close()
+	if cross.StatusCode != http.StatusForbidden {
+		t.Fatalf("cross-origin status = %d, want 403", cross.StatusCode)
+	}
+
+	// DELETE terminates the session.
+	del, _ := http.NewRequestWithContext(context.Background(), http.MethodDelete, ts.URL, nil)
+	del.Header.Set(MCPSessionHeader, sess)
+	delResp, err := http.DefaultClient.Do(del)
+	if err != nil {
+		t.Fatalf("DELETE error = %v", err)
+	}
+	delResp.Body.Close()
+	if delResp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want 204", delResp.StatusCode)
+	}
+	if h.SessionCount() != 0 {
+		t.Fatalf("SessionCount() = %d after DELETE", h.SessionCount())
+	}
+}
+
+func TestMCPHTTPEventStream(t *testing.T) {
+	_, b := setupMCPServer(t)
+	h := NewMCPHTTPServer(b, MCPModeAgent)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	sess := initializeMCPSession(t, ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(MCPSessionHeader, sess)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET status = %d", resp.StatusCode)
+	}
+
+	// Closing the server session ends the stream before the client deadline.
+	h.closeAll()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("stream did not end cleanly: %v", err)
+	}
+}
+
+func TestMCPHTTPIdleSessionsAreEvicted(t *testing.T) {
+	_, b := setupMCPServer(t)
+	h := NewMCPHTTPServer(b, MCPModeAgent)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	idle := initializeMCPSession(t, ts.URL)
+	streaming := initializeMCPSession(t, ts.URL)
+	idleServer := h.lookupSession(idle).server
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(MCPSessionHeader, streaming)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if n := h.evictIdle(time.Now()); n != 0 {
+		t.Fatalf("evictIdle(now) = %d, want 0", n)
+	}
+
+	// Only the session without an open stream is evicted.
+	if n := h.evictIdle(time.Now().Add(mcpSessionIdleTimeout + time.Minute)); n != 1 {
+		t.Fatalf("evictIdle(later) = %d, want 1", n)
+	}
+	if h.lookupSession(idle) != nil || h.lookupSession(streaming) == nil {
+		t.Fatal("expected only the idle session to be removed")
+	}
+	b.listenersMu.Lock()
+	_, listening := b.listeners[idleServer]
+	b.listenersMu.Unlock()
+	if listening {
+		t.Error("evicted session still receives resource notifications")
+	}
+
+	resp, _ = postMCP(t, ts.URL, idle, "", map[string]any{"jsonrpc": "2.0", "id": 2, "method": "ping"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("request on evicted session status = %d, want 404", resp.StatusCode)
+	}
+}
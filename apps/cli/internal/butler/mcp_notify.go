@@ -0,0 +1,174 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileResourcePrefix is the URI prefix of file resources.
+const fileResourcePrefix = "palace://files/"
+
+// jsonRPCNotification is a server-initiated JSON-RPC message without an ID.
+type jsonRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type mcpResourceSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// addResourceListener registers a connected MCP server for resource notifications.
+func (b *Butler) addResourceListener(s *MCPServer) {
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+	if b.listeners == nil {
+		b.listeners = make(map[*MCPServer]struct{})
+	}
+	b.listeners[s] = struct{}{}
+}
+
+// removeResourceListener unregisters an MCP server when its connection ends.
+func (b *Butler) removeResourceListener(s *MCPServer) {
+	b.listenersMu.Lock()
+	defer b.listenersMu.Unlock()
+	delete(b.listeners, s)
+}
+
+// NotifyFilesChanged sends notifications/resources/updated to every connected
+// client that subscribed to, or has worked with, one of the given files.
+// Paths are relative to the workspace root. Returns the number of
+// notifications sent.
+func (b *Butler) NotifyFilesChanged(paths []string) int {
+	b.listenersMu.Lock()
+	servers := make([]*MCPServer, 0, len(b.listeners))
+	for s := range b.listeners {
+		servers = append(servers, s)
+	}
+	b.listenersMu.Unlock()
+
+	sent := 0
+	for _, s := range servers {
+		sent += s.notifyFilesChanged(paths)
+	}
+	return sent
+}
+
+// notifyFilesChanged notifies this client about changed files it subscribed
+// to or accessed through file tools during the connection.
+func (s *MCPServer) notifyFilesChanged(paths []string) int {
+	s.resourceMu.Lock()
+	tracked := make(map[string]bool, len(s.trackedFiles))
+	for p := range s.trackedFiles {
+		tracked[s.butler.workspaceRelPath(p)] = true
+	}
+	var uris []string
+	for _, p := range paths {
+		p = s.butler.workspaceRelPath(p)
+		uri := fileResourcePrefix + p
+		if tracked[p] || s.subscriptions[uri] {
+			uris = append(uris, uri)
+		}
+	}
+	s.resourceMu.Unlock()
+
+	for _, uri := range uris {
+		data, err := json.Marshal(jsonRPCNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/resources/updated",
+			Params:  map[string]string{"uri": uri},
+		})
+		if err != nil {
+			continue
+		}
+		s.sendNotification(data)
+	}
+	return len(uris)
+}
+
+// sendNotification delivers a server-initiated message to the client.
+func (s *MCPServer) sendNotification(data []byte) {
+	if s.notify != nil {
+		s.notify(data)
+		return
+	}
+	if err := s.writeLine(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send MCP notification: %v\n", err)
+	}
+}
+
+// workspaceRelPath normalizes a file path to the slash-separated form used in
+// the index, relative to the workspace root.
+func (b *Butler) workspaceRelPath(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(b.root, path); err == nil {
+			path = rel
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+}
+
+// handleResourcesSubscribe subscribes the client to updates of a resource.
+func (s *MCPServer) handleResourcesSubscribe(req jsonRPCRequest) jsonRPCResponse {
+	uri, errResp := s.subscriptionURI(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	s.resourceMu.Lock()
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[string]bool)
+	}
+	s.subscriptions[uri] = true
+	s.resourceMu.Unlock()
+
+	return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]string{}}
+}
+
+// handleResourcesUnsubscribe removes a resource subscription.
+func (s *MCPServer) handleResourcesUnsubscribe(req jsonRPCRequest) jsonRPCResponse {
+	uri, errResp := s.subscriptionURI(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	s.resourceMu.Lock()
+	delete(s.subscriptions, uri)
+	s.resourceMu.Unlock()
+
+	return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]string{}}
+}
+
+// subscriptionURI parses and normalizes the URI of a subscribe request.
+func (s *MCPServer) subscriptionURI(req jsonRPCRequest) (string, *jsonRPCResponse) {
+	var params mcpResourceSubscribeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		data := "missing uri"
+		if err != nil {
+			data = err.Error()
+		}
+		return "", &jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &rpcError{Code: -32602, Message: "Invalid params", Data: data},
+		}
+	}
+
+	uri := params.URI
+	if strings.HasPrefix(uri, fileResourcePrefix) {
+		path := sanitizePath(strings.TrimPrefix(uri, fileResourcePrefix))
+		if path == "" {
+			return "", &jsonRPCResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error:   &rpcError{Code: -32602, Message: "Invalid file path"},
+			}
+		}
+		uri = fileResourcePrefix + s.butler.workspaceRelPath(path)
+	}
+	return uri, nil
+}
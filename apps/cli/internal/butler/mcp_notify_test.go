@@ -0,0 +1,111 @@
+package butler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyFilesChanged(t *testing.T) {
+	server, b := setupMCPServer(t)
+	out := server.writer.(*bytes.Buffer)
+	b.addResourceListener(server)
+
+	init := server.handleRequest(jsonRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	if res, ok := init.Result.(mcpInitializeResult); !ok || !res.Capabilities.Resources.Subscribe {
+		t.Fatalf("initialize result = %+v, want resources.subscribe", init.Result)
+	}
+
+	// Files touched through tools are tracked however the agent spelled them.
+	server.trackFileAccess("./main.go")
+	server.trackFileAccess(filepath.Join(b.root, "pkg", "util.go"))
+
+	resp := server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0", ID: 2, Method: "resources/subscribe",
+		Params: json.RawMessage(`{"uri":"palace://files/./other.go"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("resources/subscribe error = %+v", resp.Error)
+	}
+
+	if sent := b.NotifyFilesChanged([]string{"main.go", "other.go", "pkg/util.go", "unrelated.go"}); sent != 3 {
+		t.Fatalf("NotifyFilesChanged() = %d, want 3", sent)
+	}
+	for _, uri := range []string{"palace://files/main.go", "palace://files/other.go", "palace://files/pkg/util.go"} {
+		want := `{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"` + uri + `"}}`
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %s:\n%s", want, out.String())
+		}
+	}
+
+	// Unsubscribing and disconnecting stop notifications.
+	server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0", ID: 3, Method: "resources/unsubscribe",
+		Params: json.RawMessage(`{"uri":"palace://files/other.go"}`),
+	})
+	if sent := b.NotifyFilesChanged([]string{"other.go"}); sent != 0 {
+		t.Errorf("after unsubscribe NotifyFilesChanged() = %d, want 0", sent)
+	}
+	b.removeResourceListener(server)
+	if sent := b.NotifyFilesChanged([]string{"main.go"}); sent != 0 {
+		t.Errorf("after disconnect NotifyFilesChanged() = %d, want 0", sent)
+	}
+
+	resp = server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0", ID: 4, Method: "resources/subscribe",
+		Params: json.RawMessage(`{"uri":"palace://files/../etc/passwd"}`),
+	})
+	if resp.Error == nil {
+		t.Error("subscribing outside the workspace should fail")
+	}
+}
+
+func TestMCPHTTPResourceNotifications(t *testing.T) {
+	_, b := setupMCPServer(t)
+	h := NewMCPHTTPServer(b, MCPModeAgent)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	sess := initializeMCPSession(t, ts.URL)
+	resp, body := postMCP(t, ts.URL, sess, "", map[string]any{
+		"jsonrpc": "2.0", "id": 2, "method": "resources/subscribe",
+		"params": map[string]any{"uri": "palace://files/main.go"},
+	})
+	if resp.StatusCode != http.StatusOK || strings.Contains(string(body), `"error"`) {
+		t.Fatalf("resources/subscribe status = %d, body = %s", resp.StatusCode, body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(MCPSessionHeader, sess)
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer stream.Body.Close()
+
+	if sent := b.NotifyFilesChanged([]string{"main.go"}); sent != 1 {
+		t.Fatalf("NotifyFilesChanged() = %d, want 1", sent)
+	}
+
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data: ") {
+			if !strings.Contains(line, `"notifications/resources/updated"`) || !strings.Contains(line, "palace://files/main.go") {
+				t.Fatalf("unexpected event %s", line)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without a notification: %v", scanner.Err())
+}
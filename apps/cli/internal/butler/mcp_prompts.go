@@ -0,0 +1,359 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+)
+
+// Prompt name prefixes. Rooms and playbooks may share names (e.g. "bug-fix"),
+// so the prefix keeps prompt names unique.
+const (
+	roomPromptPrefix     = "room-"
+	playbookPromptPrefix = "playbook-"
+)
+
+// maxPromptDecisions bounds the number of authoritative decisions rendered per scope.
+const maxPromptDecisions = 10
+
+type mcpPromptsCap struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+type mcpPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []mcpPromptArgument `json:"arguments,omitempty"`
+}
+
+type mcpPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type mcpPromptGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+type mcpPromptMessage struct {
+	Role    string     `json:"role"`
+	Content mcpContent `json:"content"`
+}
+
+type mcpPromptGetResult struct {
+	Description string             `json:"description,omitempty"`
+	Messages    []mcpPromptMessage `json:"messages"`
+}
+
+// taskPromptArgument is shared by every prompt so clients can pass the goal inline.
+var taskPromptArgument = mcpPromptArgument{
+	Name:        "task",
+	Description: "What you are trying to accomplish",
+}
+
+// handlePromptsList returns a prompt for every room and playbook.
+func (s *MCPServer) handlePromptsList(req jsonRPCRequest) jsonRPCResponse {
+	rooms := s.butler.ListRooms()
+	prompts := make([]mcpPrompt, 0, len(rooms))
+	for i := range rooms {
+		prompts = append(prompts, roomPrompt(&rooms[i]))
+	}
+
+	if playbooks, err := s.getPlaybookExecutor().ListPlaybooks(); err == nil {
+		sort.Slice(playbooks, func(i, j int) bool { return playbooks[i].Name < playbooks[j].Name })
+		for i := range playbooks {
+			prompts = append(prompts, playbookPrompt(&playbooks[i]))
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{"prompts": prompts},
+	}
+}
+
+// handlePromptsGet renders a room or playbook prompt with the supplied arguments.
+func (s *MCPServer) handlePromptsGet(req jsonRPCRequest) jsonRPCResponse {
+	var params mcpPromptGetParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &rpcError{Code: -32602, Message: "Invalid params", Data: err.Error()},
+		}
+	}
+
+	var (
+		result *mcpPromptGetResult
+		err    error
+	)
+	switch {
+	case strings.HasPrefix(params.Name, roomPromptPrefix):
+		result, err = s.renderRoomPrompt(strings.TrimPrefix(params.Name, roomPromptPrefix), params.Arguments)
+	case strings.HasPrefix(params.Name, playbookPromptPrefix):
+		result, err = s.renderPlaybookPrompt(strings.TrimPrefix(params.Name, playbookPromptPrefix), params.Arguments)
+	default:
+		err = fmt.Errorf("unknown prompt: %s", params.Name)
+	}
+	if err != nil {
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &rpcError{Code: -32602, Message: err.Error()},
+		}
+	}
+
+	return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// roomPrompt describes a room as a prompt. Each step becomes an optional
+// argument so the user can supply notes or findings for that step.
+func roomPrompt(room *model.Room) mcpPrompt {
+	args := []mcpPromptArgument{taskPromptArgument}
+	seen := map[string]bool{taskPromptArgument.Name: true}
+	for _, step := range room.Steps {
+		name := uniqueArgName(promptArgName(step.Name), seen)
+		desc := step.Name
+		if step.Description != "" {
+			desc = step.Name + ": " + step.Description
+		}
+		args = append(args, mcpPromptArgument{Name: name, Description: desc})
+	}
+
+	return mcpPrompt{
+		Name:        roomPromptPrefix + room.Name,
+		Description: room.Summary,
+		Arguments:   args,
+	}
+}
+
+// playbookPrompt describes a playbook as a prompt. Each required evidence
+// item becomes an optional argument for evidence that is already available.
+func playbookPrompt(pb *model.Playbook) mcpPrompt {
+	args := []mcpPromptArgument{taskPromptArgument}
+	seen := map[string]bool{taskPromptArgument.Name: true}
+	for _, ev := range pb.RequiredEvidence {
+		name := uniqueArgName(promptArgName(ev.ID), seen)
+		args = append(args, mcpPromptArgument{Name: name, Description: ev.Description})
+	}
+
+	return mcpPrompt{
+		Name:        playbookPromptPrefix + pb.Name,
+		Description: pb.Summary,
+		Arguments:   args,
+	}
+}
+
+// renderRoomPrompt builds the messages for a room prompt.
+func (s *MCPServer) renderRoomPrompt(name string, args map[string]string) (*mcpPromptGetResult, error) {
+	room, err := s.butler.ReadRoom(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Room: %s\n\n", room.Name)
+	if room.Summary != "" {
+		fmt.Fprintf(&sb, "%s\n\n", room.Summary)
+	}
+	if task := strings.TrimSpace(args["task"]); task != "" {
+		fmt.Fprintf(&sb, "**Task:** %s\n\n", task)
+	}
+
+	writeRoomBody(&sb, room, args, "##")
+	s.writeScopeDecisions(&sb, []string{room.Name})
+
+	sb.WriteString("Work through the steps in order. Use `explore` on the entry points and `store` to record what you learn.\n")
+
+	return &mcpPromptGetResult{
+		Description: room.Summary,
+		Messages:    []mcpPromptMessage{{Role: "user", Content: mcpContent{Type: "text", Text: sb.String()}}},
+	}, nil
+}
+
+// renderPlaybookPrompt builds the messages for a playbook prompt.
+func (s *MCPServer) renderPlaybookPrompt(name string, args map[string]string) (*mcpPromptGetResult, error) {
+	pb, err := s.getPlaybookExecutor().LoadPlaybook(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Playbook: %s\n\n", pb.Name)
+	if pb.Summary != "" {
+		fmt.Fprintf(&sb, "%s\n\n", pb.Summary)
+	}
+	if task := strings.TrimSpace(args["task"]); task != "" {
+		fmt.Fprintf(&sb, "**Task:** %s\n\n", task)
+	}
+
+	for i, roomName := range pb.Rooms {
+		fmt.Fprintf(&sb, "## %d. %s\n\n", i+1, roomName)
+		room, err := s.butler.ReadRoom(roomName)
+		if err != nil {
+			sb.WriteString("_Room not found in this palace._\n\n")
+			continue
+		}
+		if room.Summary != "" {
+			fmt.Fprintf(&sb, "%s\n\n", room.Summary)
+		}
+		writeRoomBody(&sb, room, nil, "###")
+	}
+
+	if len(pb.RequiredEvidence) > 0 {
+		sb.WriteString("## Required Evidence\n\n")
+		seen := map[string]bool{taskPromptArgument.Name: true}
+		for _, ev := range pb.RequiredEvidence {
+			argName := uniqueArgName(promptArgName(ev.ID), seen)
+			fmt.Fprintf(&sb, "- **%s**: %s", ev.ID, ev.Description)
+			if ev.Room != "" {
+				fmt.Fprintf(&sb, " (from: %s)", ev.Room)
+			}
+			if v := strings.TrimSpace(args[argName]); v != "" {
+				fmt.Fprintf(&sb, "\n  - Provided: %s", v)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(pb.Verification) > 0 {
+		sb.WriteString("## Verification\n\n")
+		for _, v := range pb.Verification {
+			fmt.Fprintf(&sb, "- **%s**: %s\n", v.Name, v.Expectation)
+		}
+		sb.WriteString("\n")
+	}
+
+	s.writeScopeDecisions(&sb, pb.Rooms)
+
+	fmt.Fprintf(&sb, "Start with `playbook` action=start name=%s to track progress and evidence.\n", pb.Name)
+
+	return &mcpPromptGetResult{
+		Description: pb.Summary,
+		Messages:    []mcpPromptMessage{{Role: "user", Content: mcpContent{Type: "text", Text: sb.String()}}},
+	}, nil
+}
+
+// writeRoomBody renders a room's entry points and steps. Step arguments, when
+// provided, are shown under the step they belong to.
+func writeRoomBody(sb *strings.Builder, room *model.Room, args map[string]string, heading string) {
+	if len(room.EntryPoints) > 0 {
+		fmt.Fprintf(sb, "%s Entry Points\n\n", heading)
+		for _, ep := range room.EntryPoints {
+			fmt.Fprintf(sb, "- `%s`\n", ep)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(room.Steps) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "%s Steps\n\n", heading)
+	seen := map[string]bool{taskPromptArgument.Name: true}
+	for i, step := range room.Steps {
+		argName := uniqueArgName(promptArgName(step.Name), seen)
+		fmt.Fprintf(sb, "%d. **%s**", i+1, step.Name)
+		if step.Description != "" {
+			fmt.Fprintf(sb, " - %s", step.Description)
+		}
+		if step.Evidence != "" {
+			fmt.Fprintf(sb, " (evidence: `%s`)", step.Evidence)
+		}
+		sb.WriteString("\n")
+		if v := strings.TrimSpace(args[argName]); v != "" {
+			fmt.Fprintf(sb, "   - Notes: %s\n", v)
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writeScopeDecisions renders authoritative decisions for the given rooms,
+// followed by palace-wide decisions. Each decision is listed once.
+func (s *MCPServer) writeScopeDecisions(sb *strings.Builder, rooms []string) {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return
+	}
+
+	cfg := &memory.AuthoritativeQueryConfig{
+		MaxDecisions:      maxPromptDecisions,
+		MaxLearnings:      0,
+		MaxContentLen:     500,
+		AuthoritativeOnly: true,
+	}
+
+	seen := make(map[string]bool)
+	var lines []string
+	for _, room := range rooms {
+		state, err := mem.GetAuthoritativeState(memory.ScopeRoom, room, s.butler.resolveRoom, cfg)
+		if err != nil {
+			continue
+		}
+		for _, sd := range state.Decisions {
+			if seen[sd.Decision.ID] {
+				continue
+			}
+			seen[sd.Decision.ID] = true
+			scope := string(sd.SourceScope.Scope)
+			if sd.SourceScope.Path != "" {
+				scope += ":" + sd.SourceScope.Path
+			}
+			line := fmt.Sprintf("- [%s] %s _(%s)_", sd.Decision.ID, sd.Decision.Content, scope)
+			if sd.Decision.Rationale != "" {
+				line += "\n  - Rationale: " + cfg.TruncateContent(sd.Decision.Rationale)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString("## Authoritative Decisions\n\n")
+	sb.WriteString("These decisions are approved for this scope. Follow them unless the task explicitly revisits them.\n\n")
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// promptArgName converts a step name or evidence ID into an argument name.
+func promptArgName(s string) string {
+	var b strings.Builder
+	lastUnderscore := true
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastUnderscore = false
+			continue
+		}
+		if !lastUnderscore {
+			b.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	if name == "" {
+		name = "arg"
+	}
+	return name
+}
+
+// uniqueArgName returns name, or name with a numeric suffix if it is already taken.
+func uniqueArgName(name string, seen map[string]bool) string {
+	candidate := name
+	for i := 2; seen[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	seen[candidate] = true
+	return candidate
+}
@@ -0,0 +1,178 @@
+package butler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func setupPromptServer(t *testing.T) (*MCPServer, *Butler) {
+	t.Helper()
+	server, b := setupMCPServer(t)
+
+	room := `{
+  "name": "api",
+  "summary": "API design room",
+  "entryPoints": ["api/"],
+  "steps": [
+    {"name": "Review existing APIs", "description": "Understand current contracts"},
+    {"name": "Design endpoints", "evidence": "api-spec"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(b.root, ".palace", "rooms", "api.jsonc"), []byte(room), 0o644); err != nil {
+		t.Fatalf("WriteFile(room) error = %v", err)
+	}
+	b.reloadRooms()
+
+	playbooksDir := filepath.Join(b.root, ".palace", "playbooks")
+	if err := os.MkdirAll(playbooksDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	pb := `{
+  "kind": "palace/playbook",
+  "name": "api-change",
+  "summary": "Change an API safely",
+  "rooms": ["core", "api"],
+  "requiredEvidence": [{"id": "api-spec", "description": "API specification", "room": "api"}]
+}`
+	if err := os.WriteFile(filepath.Join(playbooksDir, "api-change.jsonc"), []byte(pb), 0o644); err != nil {
+		t.Fatalf("WriteFile(playbook) error = %v", err)
+	}
+
+	if _, err := b.Memory().AddDecision(memory.Decision{
+		Content:   "All endpoints return RFC 7807 problem details",
+		Scope:     "room",
+		ScopePath: "api",
+		Authority: string(memory.AuthorityApproved),
+	}); err != nil {
+		t.Fatalf("AddDecision() error = %v", err)
+	}
+	if _, err := b.Memory().AddDecision(memory.Decision{
+		Content:   "Unreviewed idea",
+		Scope:     "room",
+		ScopePath: "api",
+	}); err != nil {
+		t.Fatalf("AddDecision() error = %v", err)
+	}
+
+	return server, b
+}
+
+func TestMCPPromptsList(t *testing.T) {
+	server, _ := setupPromptServer(t)
+
+	resp := server.handleRequest(jsonRPCRequest{JSONRPC: "2.0", ID: 1, Method: "prompts/list"})
+	if resp.Error != nil {
+		t.Fatalf("prompts/list error = %v", resp.Error)
+	}
+	prompts, ok := resp.Result.(map[string]interface{})["prompts"].([]mcpPrompt)
+	if !ok {
+		t.Fatalf("prompts type = %T", resp.Result)
+	}
+
+	byName := make(map[string]mcpPrompt)
+	for _, p := range prompts {
+		byName[p.Name] = p
+	}
+
+	room, ok := byName["room-api"]
+	if !ok {
+		t.Fatalf("missing room-api prompt, got %v", prompts)
+	}
+	var argNames []string
+	for _, a := range room.Arguments {
+		argNames = append(argNames, a.Name)
+	}
+	if got := strings.Join(argNames, ","); got != "task,review_existing_apis,design_endpoints" {
+		t.Errorf("room arguments = %s", got)
+	}
+
+	pb, ok := byName["playbook-api-change"]
+	if !ok {
+		t.Fatalf("missing playbook-api-change prompt")
+	}
+	if len(pb.Arguments) != 2 || pb.Arguments[1].Name != "api_spec" {
+		t.Errorf("playbook arguments = %+v", pb.Arguments)
+	}
+}
+
+func TestMCPPromptsGet(t *testing.T) {
+	server, _ := setupPromptServer(t)
+
+	resp := server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "prompts/get",
+		Params: mustMarshal(t, map[string]any{
+			"name":      "room-api",
+			"arguments": map[string]string{"task": "add pagination", "design_endpoints": "cursor based"},
+		}),
+	})
+	if resp.Error != nil {
+		t.Fatalf("prompts/get error = %v", resp.Error)
+	}
+	result, ok := resp.Result.(*mcpPromptGetResult)
+	if !ok || len(result.Messages) != 1 {
+		t.Fatalf("prompts/get result = %#v", resp.Result)
+	}
+	text := result.Messages[0].Content.Text
+	for _, want := range []string{"add pagination", "`api/`", "Notes: cursor based", "RFC 7807"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("room prompt missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Unreviewed idea") {
+		t.Errorf("room prompt should only include authoritative decisions:\n%s", text)
+	}
+
+	resp = server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "prompts/get",
+		Params: mustMarshal(t, map[string]any{
+			"name":      "playbook-api-change",
+			"arguments": map[string]string{"api_spec": "docs/api.md"},
+		}),
+	})
+	if resp.Error != nil {
+		t.Fatalf("prompts/get playbook error = %v", resp.Error)
+	}
+	text = resp.Result.(*mcpPromptGetResult).Messages[0].Content.Text
+	for _, want := range []string{"## 1. core", "## 2. api", "`main.go`", "Provided: docs/api.md", "RFC 7807"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("playbook prompt missing %q:\n%s", want, text)
+		}
+	}
+
+	resp = server.handleRequest(jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      3,
+		Method:  "prompts/get",
+		Params:  mustMarshal(t, map[string]any{"name": "room-missing"}),
+	})
+	if resp.Error == nil {
+		t.Fatal("expected error for unknown room prompt")
+	}
+}
+
+func TestPromptArgName(t *testing.T) {
+	tests := map[string]string{
+		"Review existing APIs": "review_existing_apis",
+		"api-spec":             "api_spec",
+		"  --  ":               "arg",
+		"Step 2: Fix it!":      "step_2_fix_it",
+	}
+	for in, want := range tests {
+		if got := promptArgName(in); got != want {
+			t.Errorf("promptArgName(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	seen := map[string]bool{"task": true}
+	if got := uniqueArgName("task", seen); got != "task_2" {
+		t.Errorf("uniqueArgName() = %q, want task_2", got)
+	}
+}
@@ -1153,6 +1153,52 @@ func TestMCPToolFileContextMissingPath(t *testing.T) {
 	}
 }
 
+func TestMCPToolOwnership(t *testing.T) {
+	server, b := setupMCPServer(t)
+
+	alice := index.AuthorShare{Author: "alice", Email: "alice@example.com", Lines: 8, Commits: 3, Share: 0.8}
+	bob := index.AuthorShare{Author: "bob", Email: "bob@example.com", Lines: 2, Commits: 1, Share: 0.2}
+	err := index.ReplaceOwnership(b.db, []index.FileOwnership{{
+		Path:        "main.go",
+		BlamedLines: 10,
+		Authors:     []index.AuthorShare{alice, bob},
+		LastAuthor:  "bob",
+		LastCommit:  "0123456789abcdef",
+		LastTouched: time.Now(),
+		Churn:       index.ChurnWindows{Days30: 1, Days90: 2, Days365: 4},
+		CodeOwners:  []string{"@core"},
+		Symbols: []index.SymbolOwnership{{
+			FilePath: "main.go", Symbol: "DoWork", Kind: "function", LineStart: 3, LineEnd: 5,
+			Authors: []index.AuthorShare{{Author: "alice", Email: "alice@example.com", Lines: 3, Share: 1}},
+		}},
+		AnalyzedAt: time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("ReplaceOwnership() error = %v", err)
+	}
+
+	text := toolText(t, server.toolFileContext(1, map[string]interface{}{"file_path": "main.go"}))
+	for _, want := range []string{"## Ownership", "@core, alice, bob", "alice 80%, bob 20%", "by bob (`01234567`)", "1 commits in 30 days"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("file_context missing %q: %s", want, text)
+		}
+	}
+
+	text = toolText(t, server.toolBriefFile(2, map[string]interface{}{"path": "main.go"}))
+	if !strings.Contains(text, "## Ownership") {
+		t.Errorf("brief_file missing ownership: %s", text)
+	}
+
+	text = toolText(t, server.toolExploreImpact(3, map[string]interface{}{"target": "main.go"}))
+	if !strings.Contains(text, "## Suggested Reviewers\n\n- @core\n- alice\n- bob") {
+		t.Errorf("impact missing reviewers: %s", text)
+	}
+	text = toolText(t, server.toolExploreImpact(4, map[string]interface{}{"target": "DoWork"}))
+	if !strings.Contains(text, "`DoWork` (main.go:3-5): alice 100%") {
+		t.Errorf("impact missing symbol owners: %s", text)
+	}
+}
+
 func TestMCPToolFileContextConflictDetection(t *testing.T) {
 	server, b := setupMCPServer(t)
 
@@ -1256,3 +1302,75 @@ func TestMCPToolAutonomyMetadata(t *testing.T) {
 		t.Error("file_context tool not found in tools list")
 	}
 }
+
+func TestMCPToolExploreArchitecture(t *testing.T) {
+	server, b := setupMCPServer(t)
+
+	scan, err := index.LatestScan(b.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violation := index.ArchitectureViolation{Rule: "core-is-leaf", Kind: "forbidden", SourceFile: "main.go",
+		TargetFile: "other.go", Line: 3, Severity: "error", Message: "core must not import other"}
+	if _, err := index.ReplaceArchitectureViolations(b.db, scan.ID, []index.ArchitectureViolation{violation}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := server.dispatchExplore(1, map[string]interface{}{}, "architecture")
+	text := toolText(t, resp)
+	for _, want := range []string{"New Since Last Scan", "Open Violations", "`main.go:3` → `other.go`", "core-is-leaf"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+
+	resp = server.dispatchExplore(2, map[string]interface{}{"file": "other.go"}, "architecture")
+	if text := toolText(t, resp); !strings.Contains(text, "No open violations") {
+		t.Errorf("other.go output unexpected: %s", text)
+	}
+}
+
+func TestMCPToolExploreGraphReport(t *testing.T) {
+	server, _ := setupMCPServer(t)
+
+	resp := server.dispatchExplore(1, map[string]interface{}{}, "graph")
+	if text := toolText(t, resp); !strings.Contains(text, "# Import Graph Report") || !strings.Contains(text, "## Bridges") {
+		t.Errorf("markdown report unexpected:\n%s", text)
+	}
+
+	resp = server.dispatchExplore(2, map[string]interface{}{"format": "dot"}, "graph")
+	if text := toolText(t, resp); !strings.HasPrefix(text, "digraph palace {") {
+		t.Errorf("dot report unexpected:\n%s", text)
+	}
+
+	resp = server.dispatchExplore(3, map[string]interface{}{"format": "svg"}, "graph")
+	if resp.Error == nil && !strings.Contains(toolText(t, resp), "unsupported format") {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestMCPToolExploreDeadcode(t *testing.T) {
+	server, _ := setupMCPServer(t)
+
+	// DoWork is in the core room's entry point; nothing calls Caller.
+	resp := server.dispatchExplore(1, map[string]interface{}{}, "deadcode")
+	text := toolText(t, resp)
+	for _, want := range []string{"# Unused Code", "`Caller` function at `caller.go:1` (medium)", "`caller.go` (medium)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "DoWork") {
+		t.Errorf("entry point reported as unused:\n%s", text)
+	}
+
+	resp = server.dispatchExplore(2, map[string]interface{}{"minConfidence": "high"}, "deadcode")
+	if text := toolText(t, resp); !strings.Contains(text, "Nothing unused found") {
+		t.Errorf("high-confidence output unexpected:\n%s", text)
+	}
+
+	resp = server.dispatchExplore(3, map[string]interface{}{"propose": true}, "deadcode")
+	if text := toolText(t, resp); !strings.Contains(text, "**Proposed:** 1 |") {
+		t.Errorf("propose output unexpected:\n%s", text)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
 
 // toolSessionAnalytics provides aggregate statistics about sessions.
@@ -105,18 +107,16 @@ func (s *MCPServer) toolSessionAnalytics(id any, args map[string]interface{}) js
 	}
 
 	// Handoff stats
-	handoffMu.RLock()
 	pendingHandoffs := 0
 	completedHandoffs := 0
-	for _, h := range handoffStore {
+	for _, h := range s.allHandoffs() {
 		switch h.Status {
-		case "pending":
+		case memory.HandoffStatusPending:
 			pendingHandoffs++
-		case "completed":
+		case memory.HandoffStatusCompleted:
 			completedHandoffs++
 		}
 	}
-	handoffMu.RUnlock()
 
 	if pendingHandoffs > 0 || completedHandoffs > 0 {
 		output.WriteString("## Handoffs\n\n")
@@ -355,24 +355,20 @@ func (s *MCPServer) toolWorkspaceHealth(id any, _ map[string]interface{}) jsonRP
 	output.WriteString("\n")
 
 	// Handoff health
-	handoffMu.RLock()
 	pendingHandoffs := 0
 	urgentHandoffs := 0
 	expiredHandoffs := 0
-	now := time.Now()
-	for _, h := range handoffStore {
-		if h.Status == "pending" {
-			if h.ExpiresAt.Before(now) {
-				expiredHandoffs++
-			} else {
-				pendingHandoffs++
-				if h.Priority == "urgent" {
-					urgentHandoffs++
-				}
+	for _, h := range s.allHandoffs() {
+		switch h.Status {
+		case memory.HandoffStatusExpired:
+			expiredHandoffs++
+		case memory.HandoffStatusPending:
+			pendingHandoffs++
+			if h.Priority == "urgent" {
+				urgentHandoffs++
 			}
 		}
 	}
-	handoffMu.RUnlock()
 
 	output.WriteString("## Handoff Health\n\n")
 	if pendingHandoffs == 0 && expiredHandoffs == 0 {
@@ -0,0 +1,79 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+// toolExploreArchitecture reports the architecture violations the last scan
+// recorded and what changed since the scan before it. With a file argument
+// only that file's imports are listed.
+func (s *MCPServer) toolExploreArchitecture(id any, args map[string]interface{}) jsonRPCResponse {
+	file, _ := args["file"].(string)
+	limit := 50
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	violations, err := s.butler.GetArchitectureViolations(file)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("get architecture violations failed: %v", err))
+	}
+	diff, err := s.butler.GetArchitectureDiff()
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("get architecture diff failed: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Architecture Violations\n\n")
+	if file != "" {
+		fmt.Fprintf(&output, "**File:** `%s`\n\n", file)
+	}
+
+	if file == "" {
+		fmt.Fprintf(&output, "**Open:** %d | **New since last scan:** %d | **Resolved:** %d\n\n",
+			diff.Open, len(diff.New), len(diff.Resolved))
+		if len(diff.New) > 0 {
+			output.WriteString("## New Since Last Scan\n\n")
+			writeArchitectureViolations(&output, diff.New, limit)
+		}
+		if len(diff.Resolved) > 0 {
+			output.WriteString("## Resolved Since Last Scan\n\n")
+			writeArchitectureViolations(&output, diff.Resolved, limit)
+		}
+	}
+
+	if len(violations) == 0 {
+		output.WriteString("No open violations.\n\n")
+		output.WriteString("Rules live under `architecture` in `.palace/palace.jsonc` and are evaluated by `palace scan` and `palace check`.\n")
+	} else {
+		output.WriteString("## Open Violations\n\n")
+		writeArchitectureViolations(&output, violations, limit)
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+func writeArchitectureViolations(output *strings.Builder, list []index.ArchitectureViolation, limit int) {
+	for i := range list {
+		if i == limit {
+			fmt.Fprintf(output, "_... and %d more_\n", len(list)-i)
+			break
+		}
+		v := &list[i]
+		icon := "🔴"
+		if v.Severity == "warning" {
+			icon = "🟡"
+		}
+		fmt.Fprintf(output, "- %s `%s:%d` → `%s`: %s (rule `%s`)\n", icon, v.SourceFile, v.Line, v.TargetFile, v.Message, v.Rule)
+	}
+	output.WriteString("\n")
+}
@@ -135,7 +135,7 @@ func (s *MCPServer) toolBriefingSmart(id any, args map[string]interface{}) jsonR
 		}
 
 		// Get pending handoffs
-		pendingHandoffs := getPendingHandoffsForAgent("any")
+		pendingHandoffs := s.pendingHandoffsForAgent("any")
 		if len(pendingHandoffs) > 0 {
 			contextData.WriteString(fmt.Sprintf("\n## Pending Handoffs: %d\n", len(pendingHandoffs)))
 			for _, h := range pendingHandoffs {
@@ -261,7 +261,7 @@ func (s *MCPServer) generateNonLLMBriefing(id any, contextType, contextPath stri
 		}
 
 		// Pending handoffs
-		pendingHandoffs := getPendingHandoffsForAgent("any")
+		pendingHandoffs := s.pendingHandoffsForAgent("any")
 		if len(pendingHandoffs) > 0 {
 			output.WriteString("\n### Pending Handoffs\n\n")
 			for _, h := range pendingHandoffs {
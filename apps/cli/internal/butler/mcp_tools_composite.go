@@ -51,7 +51,7 @@ func (s *MCPServer) toolSessionInit(id any, args map[string]interface{}) jsonRPC
 	fmt.Fprintf(&output, "- **Started:** %s\n\n", session.StartedAt.Format(time.RFC3339))
 
 	// 1.5. Check for pending handoffs
-	pendingHandoffs := getPendingHandoffsForAgent(agentType)
+	pendingHandoffs := s.pendingHandoffsForAgent(agentType)
 	if len(pendingHandoffs) > 0 {
 		output.WriteString("## 📬 Pending Handoffs\n\n")
 		urgentCount := 0
@@ -309,6 +309,9 @@ func (s *MCPServer) toolFileContext(id any, args map[string]interface{}) jsonRPC
 			output.WriteString("\n")
 		}
 	}
+	if own, err := s.butler.GetFileOwnership(filePath); err == nil && own != nil {
+		writeOwnership(&output, own)
+	}
 
 	// Add next steps
 	output.WriteString("---\n\n")
@@ -177,13 +177,15 @@ Actions:
 - deps: Get dependency graph
 - callers: Find function callers
 - callees: Find function callees
-- graph: Get complete call graph`,
+- graph: Get complete call graph for a file, or without file an import graph report (cycles, coupling, dead files, bridges)
+- architecture: Architecture rule violations and changes since the last scan
+- deadcode: Unused symbols and files with a confidence level; propose=true files cleanup learnings for review`,
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"action": map[string]interface{}{
 					"type":        "string",
-					"enum":        []string{"search", "rooms", "context", "impact", "symbols", "symbol", "file", "deps", "callers", "callees", "graph"},
+					"enum":        []string{"search", "rooms", "context", "impact", "symbols", "symbol", "file", "deps", "callers", "callees", "graph", "architecture", "deadcode"},
 					"description": "Explore action (default: search)",
 					"default":     "search",
 				},
@@ -210,11 +212,17 @@ Actions:
 				},
 				"symbol": map[string]interface{}{
 					"type":        "string",
-					"description": "Symbol name (for action=callers/callees)",
+					"description": "Symbol name (for action=callers/callees); qualify same-named methods by type, e.g. Store.Close",
 				},
 				"file": map[string]interface{}{
 					"type":        "string",
-					"description": "File path (for action=file/deps/graph/callees)",
+					"description": "File path (for action=file/deps/graph/callees/architecture/deadcode; optional for callers to pick a definition)",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"markdown", "json", "dot", "mermaid"},
+					"description": "Output format of the import graph report (for action=graph without file)",
+					"default":     "markdown",
 				},
 				"files": map[string]interface{}{
 					"type":        "array",
@@ -240,6 +248,17 @@ Actions:
 					"description": "Include test files (for action=context)",
 					"default":     false,
 				},
+				"minConfidence": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"high", "medium", "low"},
+					"description": "Lowest confidence to report (for action=deadcode)",
+					"default":     "medium",
+				},
+				"propose": map[string]interface{}{
+					"type":        "boolean",
+					"description": "File a cleanup learning proposal per file (for action=deadcode)",
+					"default":     false,
+				},
 			},
 		},
 		Autonomy: &mcpToolAutonomy{
@@ -1172,12 +1191,16 @@ Actions:
 - list: List all available playbooks (default)
 - show: Show playbook details
 - start: Start playbook execution
+- runs: List stored runs across sessions
 - status: Get current execution status
 - guidance: Get guidance for current step
 - advance: Mark current step complete, move to next
 - evidence: Record evidence collected
 - verify: Run verification checks
-- complete: Mark playbook complete, clear state
+- complete: Mark playbook complete
+
+Runs are stored in memory.db per session, so they survive restarts and several sessions can run playbooks at once.
+Actions use this session's running playbook unless run_id is given.
 
 Playbooks guide agents through structured workflows with rooms, steps, and evidence collection.`,
 		InputSchema: map[string]interface{}{
@@ -1185,7 +1208,7 @@ Playbooks guide agents through structured workflows with rooms, steps, and evide
 			"properties": map[string]interface{}{
 				"action": map[string]interface{}{
 					"type":        "string",
-					"enum":        []string{"list", "show", "start", "status", "guidance", "advance", "evidence", "verify", "complete"},
+					"enum":        []string{"list", "show", "start", "runs", "status", "guidance", "advance", "evidence", "verify", "complete"},
 					"description": "Playbook action (default: list)",
 					"default":     "list",
 				},
@@ -1193,6 +1216,14 @@ Playbooks guide agents through structured workflows with rooms, steps, and evide
 					"type":        "string",
 					"description": "Playbook name (for show/start)",
 				},
+				"run_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Playbook run ID (default: this session's running playbook)",
+				},
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "Filter runs by status (for runs action)",
+				},
 				"evidence_id": map[string]interface{}{
 					"type":        "string",
 					"description": "Evidence ID to record (for evidence action)",
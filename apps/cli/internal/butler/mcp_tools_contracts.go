@@ -183,6 +183,21 @@ func (s *MCPServer) toolContractShow(id any, args map[string]interface{}) jsonRP
 		fmt.Fprintf(&output, "**Response Schema:** %s\n\n", contract.Backend.ResponseSchema.String())
 	}
 
+	// Spec info
+	if spec := contract.Spec; spec != nil {
+		output.WriteString("## Spec\n\n")
+		fmt.Fprintf(&output, "| Field | Value |\n")
+		fmt.Fprintf(&output, "|-------|-------|\n")
+		fmt.Fprintf(&output, "| File | `%s:%d` |\n", spec.File, spec.Line)
+		if spec.OperationID != "" {
+			fmt.Fprintf(&output, "| Operation | %s |\n", spec.OperationID)
+		}
+		output.WriteString("\n")
+		if spec.ResponseSchema != nil {
+			fmt.Fprintf(&output, "**Response Schema:** %s\n\n", spec.ResponseSchema.String())
+		}
+	}
+
 	// Frontend calls
 	if len(contract.FrontendCalls) > 0 {
 		fmt.Fprintf(&output, "## Frontend Calls (%d)\n\n", len(contract.FrontendCalls))
@@ -209,7 +224,7 @@ func (s *MCPServer) toolContractShow(id any, args map[string]interface{}) jsonRP
 				severityIcon = "❌"
 			}
 			fmt.Fprintf(&output, "%s **%s:** %s\n", severityIcon, m.FieldPath, m.Description)
-			if m.BackendType != "" && m.FrontendType != "" {
+			if m.Scope == contracts.ScopeBackendFrontend && m.BackendType != "" && m.FrontendType != "" {
 				fmt.Fprintf(&output, "   Backend: `%s`, Frontend: `%s`\n", m.BackendType, m.FrontendType)
 			}
 			output.WriteString("\n")
@@ -454,7 +469,7 @@ func (s *MCPServer) toolContractMismatches(id any, _ map[string]interface{}) jso
 					severityIcon = "❌"
 				}
 				fmt.Fprintf(&output, "- %s **%s:** %s\n", severityIcon, m.FieldPath, m.Description)
-				if m.BackendType != "" && m.FrontendType != "" {
+				if m.Scope == contracts.ScopeBackendFrontend && m.BackendType != "" && m.FrontendType != "" {
 					fmt.Fprintf(&output, "  - Backend: `%s`, Frontend: `%s`\n", m.BackendType, m.FrontendType)
 				}
 			}
@@ -0,0 +1,86 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/deadcode"
+)
+
+// toolExploreDeadcode reports unused symbols and files, and with
+// propose=true files a cleanup learning proposal per file.
+func (s *MCPServer) toolExploreDeadcode(id any, args map[string]interface{}) jsonRPCResponse {
+	file, _ := args["file"].(string)
+	propose, _ := args["propose"].(bool)
+	limit := 50
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	minConfidence, err := deadcode.ParseConfidence(getStringArg(args, "minConfidence", string(deadcode.ConfidenceMedium)))
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+
+	full, err := s.butler.GetDeadCode()
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("dead code analysis failed: %v", err))
+	}
+	report := full.Filter(minConfidence, file)
+
+	var output strings.Builder
+	output.WriteString("# Unused Code\n\n")
+	if file != "" {
+		fmt.Fprintf(&output, "**File:** `%s`\n\n", file)
+	}
+	fmt.Fprintf(&output, "**Symbols:** %d | **Files:** %d | **High:** %d | **Medium:** %d | **Low:** %d\n\n",
+		len(report.Symbols), len(report.Files), report.Counts.High, report.Counts.Medium, report.Counts.Low)
+
+	if len(report.Files) > 0 {
+		output.WriteString("## Files\n\n")
+		for i := range report.Files {
+			f := &report.Files[i]
+			fmt.Fprintf(&output, "- `%s` (%s): %s\n", f.Path, f.Confidence, f.Reason)
+		}
+		output.WriteString("\n")
+	}
+	if len(report.Symbols) > 0 {
+		output.WriteString("## Symbols\n\n")
+		for i := range report.Symbols {
+			if i == limit {
+				fmt.Fprintf(&output, "_... and %d more_\n", len(report.Symbols)-i)
+				break
+			}
+			sym := &report.Symbols[i]
+			fmt.Fprintf(&output, "- `%s` %s at `%s:%d` (%s): %s\n", sym.Name, sym.Kind, sym.File, sym.LineStart, sym.Confidence, sym.Reason)
+		}
+		output.WriteString("\n")
+	}
+	if len(report.Files) == 0 && len(report.Symbols) == 0 {
+		output.WriteString("Nothing unused found.\n\n")
+	}
+
+	if propose {
+		mem := s.butler.Memory()
+		if mem == nil {
+			return s.toolError(id, "memory not available")
+		}
+		ids, duplicates, err := deadcode.Propose(mem, report, minConfidence)
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("propose cleanup failed: %v", err))
+		}
+		fmt.Fprintf(&output, "**Proposed:** %d | **Duplicates skipped:** %d\n\n", len(ids), duplicates)
+		if len(ids) > 0 {
+			output.WriteString("Cleanup learnings are pending human review in `palace proposals`.\n")
+		}
+	} else if len(report.Files) > 0 || len(report.Symbols) > 0 {
+		output.WriteString("Call again with `propose: true` to file cleanup learnings for human review.\n")
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
@@ -1,10 +1,22 @@
 package butler
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/depgraph"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 )
 
+// heuristicNote flags call sites matched by name rather than resolved to a symbol.
+func heuristicNote(call index.CallSite) string {
+	if call.Resolution == index.ResolutionHeuristic {
+		return " _(heuristic)_"
+	}
+	return ""
+}
+
 // toolExploreCallers finds all locations that call a function or method.
 func (s *MCPServer) toolExploreCallers(id any, args map[string]interface{}) jsonRPCResponse {
 	symbol, _ := args["symbol"].(string)
@@ -12,13 +24,18 @@ func (s *MCPServer) toolExploreCallers(id any, args map[string]interface{}) json
 		return s.toolError(id, "symbol is required")
 	}
 
-	calls, err := s.butler.GetIncomingCalls(symbol)
+	file, _ := args["file"].(string)
+
+	calls, err := s.butler.GetIncomingCalls(symbol, file)
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("get callers failed: %v", err))
 	}
 
 	var output strings.Builder
 	fmt.Fprintf(&output, "# Callers of `%s`\n\n", symbol)
+	if file != "" {
+		fmt.Fprintf(&output, "File: `%s`\n\n", file)
+	}
 
 	if len(calls) == 0 {
 		output.WriteString("No callers found. This symbol may not be called anywhere, or call tracking may not be available for this language.\n")
@@ -29,7 +46,7 @@ func (s *MCPServer) toolExploreCallers(id any, args map[string]interface{}) json
 			if call.CallerSymbol != "" {
 				fmt.Fprintf(&output, " (in function `%s`)", call.CallerSymbol)
 			}
-			output.WriteString("\n")
+			output.WriteString(heuristicNote(call) + "\n")
 		}
 	}
 
@@ -68,7 +85,7 @@ func (s *MCPServer) toolExploreCallees(id any, args map[string]interface{}) json
 	} else {
 		fmt.Fprintf(&output, "Found %d function calls:\n\n", len(calls))
 		for _, call := range calls {
-			fmt.Fprintf(&output, "- `%s` (line %d)\n", call.CalleeSymbol, call.Line)
+			fmt.Fprintf(&output, "- `%s` (line %d)%s\n", call.CalleeSymbol, call.Line, heuristicNote(call))
 		}
 	}
 
@@ -85,7 +102,7 @@ func (s *MCPServer) toolExploreCallees(id any, args map[string]interface{}) json
 func (s *MCPServer) toolExploreGraph(id any, args map[string]interface{}) jsonRPCResponse {
 	file, _ := args["file"].(string)
 	if file == "" {
-		return s.toolError(id, "file is required")
+		return s.toolExploreGraphReport(id, args)
 	}
 
 	graph, err := s.butler.GetCallGraph(file)
@@ -105,7 +122,7 @@ func (s *MCPServer) toolExploreGraph(id any, args map[string]interface{}) jsonRP
 			if call.CallerSymbol != "" {
 				fmt.Fprintf(&output, " (in `%s`)", call.CallerSymbol)
 			}
-			output.WriteString("\n")
+			output.WriteString(heuristicNote(call) + "\n")
 		}
 		output.WriteString("\n")
 	}
@@ -119,7 +136,7 @@ func (s *MCPServer) toolExploreGraph(id any, args map[string]interface{}) jsonRP
 			if call.CallerSymbol != "" {
 				fmt.Fprintf(&output, " (from `%s`)", call.CallerSymbol)
 			}
-			output.WriteString("\n")
+			output.WriteString(heuristicNote(call) + "\n")
 		}
 	}
 
@@ -131,3 +148,111 @@ func (s *MCPServer) toolExploreGraph(id any, args map[string]interface{}) jsonRP
 		},
 	}
 }
+
+// toolExploreGraphReport analyzes the whole import graph: cycles, package
+// coupling, dead files and bridges, as markdown, JSON, DOT or Mermaid.
+func (s *MCPServer) toolExploreGraphReport(id any, args map[string]interface{}) jsonRPCResponse {
+	format := getStringArg(args, "format", "markdown")
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	report, err := s.butler.GetGraphReport()
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("analyze import graph failed: %v", err))
+	}
+
+	var text string
+	switch format {
+	case "markdown":
+		text = graphReportMarkdown(report, limit)
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("encode report failed: %v", err))
+		}
+		text = string(data)
+	case "dot":
+		text = report.DOT()
+	case "mermaid":
+		text = "```mermaid\n" + report.Mermaid() + "```\n"
+	default:
+		return s.toolError(id, fmt.Sprintf("unsupported format %q (supported: markdown, json, dot, mermaid)", format))
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: text}},
+		},
+	}
+}
+
+func graphReportMarkdown(r *depgraph.Report, limit int) string {
+	var output strings.Builder
+	output.WriteString("# Import Graph Report\n\n")
+	fmt.Fprintf(&output, "**Files:** %d | **Imports:** %d | **Packages:** %d | **Cycles:** %d | **Dead files:** %d\n\n",
+		r.Files, r.Imports, len(r.Packages), len(r.Cycles), len(r.DeadFiles))
+	more := func(n int) {
+		if n > limit {
+			fmt.Fprintf(&output, "_... and %d more_\n", n-limit)
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("## Import Cycles\n\n")
+	if len(r.Cycles) == 0 && len(r.PackageCycles) == 0 {
+		output.WriteString("No import cycles.\n\n")
+	}
+	for i, c := range r.Cycles {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(&output, "- %d files: `%s`\n", len(c.Files), strings.Join(c.Files, "`, `"))
+	}
+	for _, c := range r.PackageCycles {
+		fmt.Fprintf(&output, "- Package cycle: `%s`\n", strings.Join(c, "` → `"))
+	}
+	if len(r.Cycles) > 0 || len(r.PackageCycles) > 0 {
+		more(len(r.Cycles))
+	}
+
+	output.WriteString("## Package Coupling\n\n")
+	output.WriteString("Ca: packages importing it, Ce: packages it imports, I: instability Ce / (Ca + Ce).\n\n")
+	output.WriteString("| Package | Files | Ca | Ce | I |\n|---------|-------|----|----|---|\n")
+	for i := range r.Packages {
+		if i == limit {
+			break
+		}
+		p := &r.Packages[i]
+		fmt.Fprintf(&output, "| `%s` | %d | %d | %d | %.2f |\n", p.Name, p.Files, p.Afferent, p.Efferent, p.Instability)
+	}
+	more(len(r.Packages))
+
+	output.WriteString("## Dead Files\n\n")
+	if len(r.DeadFiles) == 0 {
+		output.WriteString("Every file is imported or an entry point.\n\n")
+	} else {
+		for i, f := range r.DeadFiles {
+			if i == limit {
+				break
+			}
+			fmt.Fprintf(&output, "- `%s`\n", f)
+		}
+		more(len(r.DeadFiles))
+	}
+
+	output.WriteString("## Bridges\n\n")
+	if len(r.Bridges) == 0 {
+		output.WriteString("No single file holds the graph together.\n")
+	}
+	for i, b := range r.Bridges {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(&output, "- `%s` cuts off %d files\n", b.Path, b.Cuts)
+	}
+	return output.String()
+}
@@ -3,34 +3,16 @@ package butler
 import (
 	"fmt"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
 
-// Handoff represents a task handoff between agents.
-type Handoff struct {
-	ID            string    `json:"id"`
-	FromAgent     string    `json:"fromAgent"`     // Agent creating the handoff
-	FromSessionID string    `json:"fromSessionId"` // Session creating the handoff
-	ToAgentType   string    `json:"toAgentType"`   // Target agent type (or "any")
-	Task          string    `json:"task"`          // Task description
-	Context       string    `json:"context"`       // Relevant context
-	PendingWork   []string  `json:"pendingWork"`   // List of pending items
-	PinnedRecords []string  `json:"pinnedRecords"` // Record IDs to include
-	Priority      string    `json:"priority"`      // "low", "normal", "high", "urgent"
-	Status        string    `json:"status"`        // "pending", "accepted", "completed", "expired"
-	AcceptedBy    string    `json:"acceptedBy"`    // Session that accepted
-	Summary       string    `json:"summary"`       // Completion summary
-	CreatedAt     time.Time `json:"createdAt"`
-	ExpiresAt     time.Time `json:"expiresAt"`
+// handoffActor identifies the current session for handoff audit entries.
+func (s *MCPServer) handoffActor() memory.HandoffActor {
+	return memory.HandoffActor{Type: memory.AuditActorAgent, ID: s.currentSessionID}
 }
 
-// In-memory handoff storage (simple implementation - can be migrated to DB later)
-var (
-	handoffStore = make(map[string]*Handoff)
-	handoffMu    sync.RWMutex
-)
-
 // toolHandoffCreate creates a handoff request for another agent.
 func (s *MCPServer) toolHandoffCreate(id any, args map[string]interface{}) jsonRPCResponse {
 	task, _ := args["task"].(string)
@@ -38,6 +20,11 @@ func (s *MCPServer) toolHandoffCreate(id any, args map[string]interface{}) jsonR
 		return s.toolError(id, "task is required")
 	}
 
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
+	}
+
 	toAgentType, _ := args["to"].(string)
 	if toAgentType == "" {
 		toAgentType = "any" // Any agent can pick up
@@ -72,11 +59,8 @@ func (s *MCPServer) toolHandoffCreate(id any, args map[string]interface{}) jsonR
 	// Include current context pins
 	pinnedRecords = append(pinnedRecords, s.contextPriorityUp...)
 
-	// Generate handoff ID
-	handoffID := fmt.Sprintf("hoff_%d", time.Now().UnixNano()%100000000)
-
 	// Determine expiration (default 24 hours)
-	expiresIn := 24 * time.Hour
+	expiresIn := memory.DefaultHandoffTTL
 	if exp, ok := args["expires_in_hours"].(float64); ok && exp > 0 {
 		expiresIn = time.Duration(exp) * time.Hour
 	}
@@ -84,13 +68,13 @@ func (s *MCPServer) toolHandoffCreate(id any, args map[string]interface{}) jsonR
 	// Get current agent type
 	fromAgent := "unknown"
 	if s.currentSessionID != "" {
-		if session, err := s.butler.Memory().GetSession(s.currentSessionID); err == nil && session != nil {
+		if session, err := mem.GetSession(s.currentSessionID); err == nil && session != nil {
 			fromAgent = session.AgentType
 		}
 	}
 
-	handoff := &Handoff{
-		ID:            handoffID,
+	now := time.Now()
+	handoff, err := mem.CreateHandoff(memory.Handoff{
 		FromAgent:     fromAgent,
 		FromSessionID: s.currentSessionID,
 		ToAgentType:   toAgentType,
@@ -99,19 +83,16 @@ func (s *MCPServer) toolHandoffCreate(id any, args map[string]interface{}) jsonR
 		PendingWork:   pendingWork,
 		PinnedRecords: pinnedRecords,
 		Priority:      priority,
-		Status:        "pending",
-		CreatedAt:     time.Now(),
-		ExpiresAt:     time.Now().Add(expiresIn),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(expiresIn),
+	}, s.handoffActor())
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("create handoff failed: %v", err))
 	}
 
-	// Store handoff
-	handoffMu.Lock()
-	handoffStore[handoffID] = handoff
-	handoffMu.Unlock()
-
 	var output strings.Builder
 	output.WriteString("# Handoff Created\n\n")
-	fmt.Fprintf(&output, "**Handoff ID:** `%s`\n", handoffID)
+	fmt.Fprintf(&output, "**Handoff ID:** `%s`\n", handoff.ID)
 	fmt.Fprintf(&output, "**To:** %s\n", toAgentType)
 	fmt.Fprintf(&output, "**Priority:** %s\n", priority)
 	fmt.Fprintf(&output, "**Expires:** %s\n\n", handoff.ExpiresAt.Format(time.RFC3339))
@@ -152,19 +133,15 @@ func (s *MCPServer) toolHandoffList(id any, args map[string]interface{}) jsonRPC
 		status = "pending"
 	}
 
-	handoffMu.RLock()
-	var handoffs []*Handoff
-	now := time.Now()
-	for _, h := range handoffStore {
-		// Skip expired
-		if h.ExpiresAt.Before(now) && h.Status == "pending" {
-			continue
-		}
-		if status == "" || h.Status == status {
-			handoffs = append(handoffs, h)
-		}
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
+	}
+
+	handoffs, err := mem.GetHandoffs(status, "", 50)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("list handoffs failed: %v", err))
 	}
-	handoffMu.RUnlock()
 
 	var output strings.Builder
 	output.WriteString("# Available Handoffs\n\n")
@@ -172,7 +149,8 @@ func (s *MCPServer) toolHandoffList(id any, args map[string]interface{}) jsonRPC
 	if len(handoffs) == 0 {
 		output.WriteString("No handoffs available.\n")
 	} else {
-		for _, h := range handoffs {
+		for i := range handoffs {
+			h := &handoffs[i]
 			priorityIcon := "🔵"
 			switch h.Priority {
 			case "high":
@@ -212,23 +190,16 @@ func (s *MCPServer) toolHandoffAccept(id any, args map[string]interface{}) jsonR
 		return s.toolError(id, "handoff id is required")
 	}
 
-	handoffMu.Lock()
-	handoff, exists := handoffStore[handoffID]
-	if !exists {
-		handoffMu.Unlock()
-		return s.toolError(id, "handoff not found")
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
 	}
 
-	if handoff.Status != "pending" {
-		handoffMu.Unlock()
-		return s.toolError(id, fmt.Sprintf("handoff is already %s", handoff.Status))
+	handoff, err := mem.AcceptHandoff(handoffID, s.currentSessionID, s.handoffActor())
+	if err != nil {
+		return s.toolError(id, err.Error())
 	}
 
-	// Mark as accepted
-	handoff.Status = "accepted"
-	handoff.AcceptedBy = s.currentSessionID
-	handoffMu.Unlock()
-
 	// Set context focus from handoff
 	s.currentTaskFocus = handoff.Task
 	s.focusKeywords = extractKeywords(handoff.Task)
@@ -256,8 +227,7 @@ func (s *MCPServer) toolHandoffAccept(id any, args map[string]interface{}) jsonR
 	}
 
 	// Get pinned learnings
-	mem := s.butler.Memory()
-	if len(handoff.PinnedRecords) > 0 && mem != nil {
+	if len(handoff.PinnedRecords) > 0 {
 		output.WriteString("\n## Pinned Knowledge\n\n")
 		for _, pid := range handoff.PinnedRecords {
 			if strings.HasPrefix(pid, "l_") {
@@ -285,38 +255,37 @@ func (s *MCPServer) toolHandoffAccept(id any, args map[string]interface{}) jsonR
 	}
 }
 
-// getPendingHandoffsForAgent returns pending handoffs that match the given agent type.
-func getPendingHandoffsForAgent(agentType string) []*Handoff {
-	handoffMu.RLock()
-	defer handoffMu.RUnlock()
-
-	var handoffs []*Handoff
-	now := time.Now()
-
-	for _, h := range handoffStore {
-		// Skip non-pending or expired
-		if h.Status != "pending" || h.ExpiresAt.Before(now) {
-			continue
-		}
-		// Match if target is "any" or matches the agent type
-		if h.ToAgentType == "any" || h.ToAgentType == agentType {
-			handoffs = append(handoffs, h)
-		}
+// pendingHandoffsForAgent returns pending handoffs that match the given agent type,
+// most urgent first.
+func (s *MCPServer) pendingHandoffsForAgent(agentType string) []memory.Handoff {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return nil
 	}
+	handoffs, _ := mem.GetPendingHandoffsForAgent(agentType)
+	return handoffs
+}
 
-	// Sort by priority (urgent > high > normal > low)
-	priorityOrder := map[string]int{"urgent": 0, "high": 1, "normal": 2, "low": 3}
-	for i := 0; i < len(handoffs)-1; i++ {
-		for j := i + 1; j < len(handoffs); j++ {
-			if priorityOrder[handoffs[i].Priority] > priorityOrder[handoffs[j].Priority] {
-				handoffs[i], handoffs[j] = handoffs[j], handoffs[i]
-			}
-		}
+// allHandoffs returns every stored handoff regardless of status.
+func (s *MCPServer) allHandoffs() []memory.Handoff {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return nil
 	}
-
+	handoffs, _ := mem.GetHandoffs("", "", 0)
 	return handoffs
 }
 
+// activeHandoffForSession returns the handoff accepted by the given session, if any.
+func (s *MCPServer) activeHandoffForSession(sessionID string) *memory.Handoff {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return nil
+	}
+	h, _ := mem.GetAcceptedHandoffForSession(sessionID)
+	return h
+}
+
 // toolHandoffComplete marks a handoff as completed.
 func (s *MCPServer) toolHandoffComplete(id any, args map[string]interface{}) jsonRPCResponse {
 	handoffID, _ := args["id"].(string)
@@ -326,16 +295,14 @@ func (s *MCPServer) toolHandoffComplete(id any, args map[string]interface{}) jso
 
 	summary, _ := args["summary"].(string)
 
-	handoffMu.Lock()
-	handoff, exists := handoffStore[handoffID]
-	if !exists {
-		handoffMu.Unlock()
-		return s.toolError(id, "handoff not found")
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
 	}
 
-	handoff.Status = "completed"
-	handoff.Summary = summary
-	handoffMu.Unlock()
+	if _, err := mem.CompleteHandoff(handoffID, summary, s.handoffActor()); err != nil {
+		return s.toolError(id, err.Error())
+	}
 
 	return jsonRPCResponse{
 		JSONRPC: "2.0",
@@ -85,6 +85,7 @@ Call this automatically before ANY file modification. Do not skip this step even
 - Active decisions that apply to this file
 - Known failures and their severity
 - File edit history and failure rate
+- Code ownership: who to ask, blame shares, last touch, churn
 - Next steps guidance`,
 			InputSchema: map[string]interface{}{
 				"type": "object",
@@ -256,7 +257,7 @@ Assembling complete context for a specific goal. This is more comprehensive than
 Agents should call this before making changes to unfamiliar code, especially in shared modules. Use proactively for safety.
 
 **BEST FOR:**
-Impact analysis. Shows what depends on a target (dependents) and what it depends on (dependencies). Critical for preventing breaking changes.`,
+Impact analysis. Shows what depends on a target (dependents) and what it depends on (dependencies), plus suggested reviewers from blame and CODEOWNERS. Critical for preventing breaking changes.`,
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -403,7 +404,11 @@ Upstream impact analysis. Shows all call sites for a function - essential before
 				"properties": map[string]interface{}{
 					"symbol": map[string]interface{}{
 						"type":        "string",
-						"description": "Function or method name to find callers of (e.g., 'handleAuth', 'config.Parse', 'User.Save').",
+						"description": "Function or method name to find callers of (e.g., 'handleAuth', 'config.Parse', 'User.Save'). Qualify a name with several definitions by its type or package.",
+					},
+					"file": map[string]interface{}{
+						"type":        "string",
+						"description": "File defining the symbol, to pick one of several same-named definitions.",
 					},
 				},
 				"required": []string{"symbol"},
@@ -453,16 +458,26 @@ Downstream call analysis. Shows what a function invokes - useful for understandi
 Use when explicitly needed for comprehensive analysis. This is a heavy operation - use sparingly.
 
 **BEST FOR:**
-Complete relationship mapping. Combines callers and callees into one comprehensive view of a file's call relationships.`,
+Complete relationship mapping. Combines callers and callees into one comprehensive view of a file's call relationships.
+
+Without a file, reports on the whole import graph instead: import cycles, package coupling and instability, files nothing imports, and the files the graph falls apart without.`,
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"file": map[string]interface{}{
 						"type":        "string",
-						"description": "File path to analyze.",
+						"description": "File path to analyze. Omit for the import graph report.",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"markdown", "json", "dot", "mermaid"},
+						"description": "Output format of the import graph report (default: markdown).",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum entries per report section (default: 20).",
 					},
 				},
-				"required": []string{"file"},
 			},
 		},
 		{
@@ -1003,7 +1018,7 @@ Briefings prevent redundant work, surface relevant learnings and decisions, warn
 		},
 		{
 			Name: "brief_file",
-			Description: `🟡 **IMPORTANT** Get intelligence about a file: edit history, failure rate, associated learnings.
+			Description: `🟡 **IMPORTANT** Get intelligence about a file: edit history, failure rate, associated learnings, code ownership.
 
 **WHEN TO USE:**
 - Before editing a file you haven't touched before in this session
@@ -1550,7 +1565,7 @@ Discovering conceptually similar records. Like 'related articles' feature.`,
 		// ============================================================
 		{
 			Name: "embedding_sync",
-			Description: `🟢 **RECOMMENDED** Generate embeddings for records that don't have them. Useful for backfilling after enabling embeddings or after bulk imports.
+			Description: `🟢 **RECOMMENDED** Generate embeddings for records that don't have them, then rebuild the on-disk vector index. Useful for backfilling after enabling embeddings or after bulk imports.
 
 **WHEN TO USE:**
 - When semantic search returns incomplete results
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 )
 
 // toolExploreContext gets complete context for a task (the ORACLE query).
@@ -211,7 +212,11 @@ func (s *MCPServer) toolExploreContext(id any, args map[string]interface{}) json
 			}
 			fmt.Fprintf(&output, "### `%s` (%s)%s\n", f.Path, f.Language, fileWarning)
 			if f.Snippet != "" {
-				fmt.Fprintf(&output, "Lines %d-%d:\n```\n%s\n```\n\n", f.ChunkStart, f.ChunkEnd, f.Snippet)
+				if f.ChunkSymbol != "" {
+					fmt.Fprintf(&output, "Lines %d-%d, from `%s`:\n```\n%s\n```\n\n", f.ChunkStart, f.ChunkEnd, f.ChunkSymbol, f.Snippet)
+				} else {
+					fmt.Fprintf(&output, "Lines %d-%d:\n```\n%s\n```\n\n", f.ChunkStart, f.ChunkEnd, f.Snippet)
+				}
 			}
 			if len(f.Symbols) > 0 {
 				output.WriteString("**Symbols in file:**\n")
@@ -340,6 +345,26 @@ func (s *MCPServer) toolExploreImpact(id any, args map[string]interface{}) jsonR
 			}
 			output.WriteString("\n")
 		}
+		output.WriteString("\n")
+	}
+
+	if len(result.Reviewers) > 0 {
+		output.WriteString("## Suggested Reviewers\n\n")
+		for _, r := range result.Reviewers {
+			fmt.Fprintf(&output, "- %s\n", r)
+		}
+		output.WriteString("\n")
+	}
+	if result.Ownership != nil {
+		writeOwnership(&output, result.Ownership)
+	}
+	if len(result.SymbolOwners) > 0 {
+		output.WriteString("## Symbol Owners\n\n")
+		for i := range result.SymbolOwners {
+			so := &result.SymbolOwners[i]
+			fmt.Fprintf(&output, "- `%s` (%s:%d-%d): %s\n", so.Symbol, so.FilePath, so.LineStart, so.LineEnd, formatAuthorShares(so.Authors))
+		}
+		output.WriteString("\n")
 	}
 
 	return jsonRPCResponse{
@@ -351,6 +376,48 @@ func (s *MCPServer) toolExploreImpact(id any, args map[string]interface{}) jsonR
 	}
 }
 
+// ownershipTopAuthors is how many authors writeOwnership lists.
+const ownershipTopAuthors = 5
+
+// writeOwnership renders a file's blame, churn and CODEOWNERS data as an
+// "## Ownership" section.
+func writeOwnership(output *strings.Builder, o *index.FileOwnership) {
+	output.WriteString("## Ownership\n\n")
+	if reviewers := o.Reviewers(3); len(reviewers) > 0 {
+		fmt.Fprintf(output, "- **Ask:** %s\n", strings.Join(reviewers, ", "))
+	}
+	if len(o.CodeOwners) > 0 {
+		fmt.Fprintf(output, "- **CODEOWNERS:** %s\n", strings.Join(o.CodeOwners, ", "))
+	}
+	if len(o.Authors) > 0 {
+		authors := o.Authors
+		if len(authors) > ownershipTopAuthors {
+			authors = authors[:ownershipTopAuthors]
+		}
+		fmt.Fprintf(output, "- **Authors (%d lines):** %s\n", o.BlamedLines, formatAuthorShares(authors))
+	}
+	if o.LastAuthor != "" {
+		fmt.Fprintf(output, "- **Last touched:** %s by %s (`%s`)\n", o.LastTouched.Format("2006-01-02"), o.LastAuthor, shortSHA(o.LastCommit))
+	}
+	fmt.Fprintf(output, "- **Churn:** %d commits in 30 days, %d in 90, %d in 365\n\n", o.Churn.Days30, o.Churn.Days90, o.Churn.Days365)
+}
+
+// formatAuthorShares renders "alice 60%, bob 40%".
+func formatAuthorShares(authors []index.AuthorShare) string {
+	parts := make([]string, len(authors))
+	for i, a := range authors {
+		parts[i] = fmt.Sprintf("%s %.0f%%", a.Author, a.Share*100)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
 // toolExploreSymbols lists symbols of a specific kind in the codebase.
 func (s *MCPServer) toolExploreSymbols(id any, args map[string]interface{}) jsonRPCResponse {
 	kind, _ := args["kind"].(string)
@@ -3,6 +3,7 @@ package butler
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
 )
@@ -11,9 +12,6 @@ import (
 // PLAYBOOK TOOL - Guided task execution with rooms and steps
 // ============================================================
 
-// playbookExecutor caches the playbook executor instance.
-var playbookState *playbook.ExecutionState
-
 // dispatchPlaybook handles the playbook tool with action parameter.
 func (s *MCPServer) dispatchPlaybook(id any, args map[string]interface{}, action string) jsonRPCResponse {
 	if action == "" {
@@ -27,18 +25,20 @@ func (s *MCPServer) dispatchPlaybook(id any, args map[string]interface{}, action
 		return s.toolPlaybookShow(id, args)
 	case "start":
 		return s.toolPlaybookStart(id, args)
+	case "runs":
+		return s.toolPlaybookRuns(id, args)
 	case "status":
-		return s.toolPlaybookStatus(id)
+		return s.toolPlaybookStatus(id, args)
 	case "guidance":
-		return s.toolPlaybookGuidance(id)
+		return s.toolPlaybookGuidance(id, args)
 	case "advance":
-		return s.toolPlaybookAdvance(id)
+		return s.toolPlaybookAdvance(id, args)
 	case "evidence":
 		return s.toolPlaybookEvidence(id, args)
 	case "verify":
-		return s.toolPlaybookVerify(id)
+		return s.toolPlaybookVerify(id, args)
 	case "complete":
-		return s.toolPlaybookComplete(id)
+		return s.toolPlaybookComplete(id, args)
 	default:
 		return consolidatedToolError(id, "playbook", "action", action)
 	}
@@ -50,6 +50,53 @@ func (s *MCPServer) getPlaybookExecutor() *playbook.Executor {
 	return playbook.NewExecutor(s.butler.root, rooms)
 }
 
+// playbookRunStore returns the store for playbook runs in memory.db.
+func (s *MCPServer) playbookRunStore() (*playbook.Store, error) {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return nil, fmt.Errorf("memory not available")
+	}
+	return playbook.NewStore(mem.DB()), nil
+}
+
+// playbookSession returns the session that owns this connection's playbook
+// runs. Without an active session, runs belong to an ID unique to the
+// connection, so agents without sessions never share runs.
+func (s *MCPServer) playbookSession() string {
+	if s.currentSessionID != "" {
+		return s.currentSessionID
+	}
+	if s.connectionID == "" {
+		s.connectionID = fmt.Sprintf("mcp-%d", time.Now().UnixNano())
+	}
+	return s.connectionID
+}
+
+// loadPlaybookRun returns the run named by run_id, or else this session's
+// running playbook, or else its most recent run.
+func (s *MCPServer) loadPlaybookRun(executor *playbook.Executor, args map[string]interface{}) (*playbook.Store, *playbook.ExecutionState, error) {
+	store, err := s.playbookRunStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var state *playbook.ExecutionState
+	if runID := getStringArg(args, "run_id", ""); runID != "" {
+		if state, err = store.Get(runID); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if state, err = store.LatestForSession(s.playbookSession()); err != nil {
+			return nil, nil, err
+		}
+		if state == nil {
+			return nil, nil, fmt.Errorf("no playbook execution in progress. Use action=start to begin")
+		}
+	}
+	executor.Resume(state)
+	return store, state, nil
+}
+
 // toolPlaybookList lists all available playbooks.
 func (s *MCPServer) toolPlaybookList(id any) jsonRPCResponse {
 	executor := s.getPlaybookExecutor()
@@ -159,25 +206,32 @@ func (s *MCPServer) toolPlaybookStart(id any, args map[string]interface{}) jsonR
 	}
 
 	executor := s.getPlaybookExecutor()
+	store, err := s.playbookRunStore()
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
 
-	// Check for existing execution
-	if playbookState != nil && playbookState.Status == "running" {
-		return s.toolError(id, fmt.Sprintf("playbook '%s' is already running. Use action=status or action=complete first.", playbookState.PlaybookName))
+	// Each session runs one playbook at a time; other sessions are unaffected.
+	active, err := store.ActiveForSession(s.playbookSession())
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+	if active != nil {
+		return s.toolError(id, fmt.Sprintf("playbook '%s' is already running (run %s). Use action=status or action=complete first.", active.PlaybookName, active.ID))
 	}
 
 	state, err := executor.Start(name)
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("start playbook: %v", err))
 	}
-
-	// Save state
-	playbookState = state
-	if err := executor.SaveState(state); err != nil {
-		// Non-fatal, log but continue
+	state.SessionID = s.playbookSession()
+	if err := store.Save(state); err != nil {
+		return s.toolError(id, err.Error())
 	}
 
 	var output strings.Builder
 	fmt.Fprintf(&output, "# ✅ Playbook Started: %s\n\n", name)
+	fmt.Fprintf(&output, "**Run ID:** `%s`\n", state.ID)
 	fmt.Fprintf(&output, "**Summary:** %s\n\n", state.Playbook.Summary)
 	fmt.Fprintf(&output, "**Rooms to visit:** %d\n", len(state.Playbook.Rooms))
 	fmt.Fprintf(&output, "**Status:** %s\n\n", state.Status)
@@ -210,22 +264,17 @@ func (s *MCPServer) toolPlaybookStart(id any, args map[string]interface{}) jsonR
 }
 
 // toolPlaybookStatus shows current execution status.
-func (s *MCPServer) toolPlaybookStatus(id any) jsonRPCResponse {
-	if playbookState == nil {
-		// Try to load from disk
-		executor := s.getPlaybookExecutor()
-		state, err := executor.LoadState()
-		if err != nil {
-			return s.toolError(id, "no playbook execution in progress. Use action=start to begin.")
-		}
-		playbookState = state
-	}
-
+func (s *MCPServer) toolPlaybookStatus(id any, args map[string]interface{}) jsonRPCResponse {
 	executor := s.getPlaybookExecutor()
+	_, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
 	progress := executor.GetProgress(playbookState)
 
 	var output strings.Builder
 	fmt.Fprintf(&output, "# Playbook Status: %s\n\n", playbookState.PlaybookName)
+	fmt.Fprintf(&output, "**Run ID:** `%s`\n", playbookState.ID)
 	fmt.Fprintf(&output, "**Status:** %s\n", playbookState.Status)
 	fmt.Fprintf(&output, "**Progress:** %.1f%%\n", progress)
 	fmt.Fprintf(&output, "**Started:** %s\n", playbookState.StartedAt.Format("2006-01-02 15:04:05"))
@@ -263,12 +312,13 @@ func (s *MCPServer) toolPlaybookStatus(id any) jsonRPCResponse {
 }
 
 // toolPlaybookGuidance shows guidance for the current step.
-func (s *MCPServer) toolPlaybookGuidance(id any) jsonRPCResponse {
-	if playbookState == nil || playbookState.Status != "running" {
+func (s *MCPServer) toolPlaybookGuidance(id any, args map[string]interface{}) jsonRPCResponse {
+	executor := s.getPlaybookExecutor()
+	_, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil || playbookState.Status != "running" {
 		return s.toolError(id, "no playbook running. Use action=start first.")
 	}
 
-	executor := s.getPlaybookExecutor()
 	guidance, err := executor.GetCurrentGuidance(playbookState)
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("get guidance: %v", err))
@@ -311,12 +361,13 @@ func (s *MCPServer) toolPlaybookGuidance(id any) jsonRPCResponse {
 }
 
 // toolPlaybookAdvance advances to the next step.
-func (s *MCPServer) toolPlaybookAdvance(id any) jsonRPCResponse {
-	if playbookState == nil || playbookState.Status != "running" {
+func (s *MCPServer) toolPlaybookAdvance(id any, args map[string]interface{}) jsonRPCResponse {
+	executor := s.getPlaybookExecutor()
+	store, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil || playbookState.Status != "running" {
 		return s.toolError(id, "no playbook running. Use action=start first.")
 	}
 
-	executor := s.getPlaybookExecutor()
 	prevRoom := ""
 	if playbookState.CurrentRoom != nil {
 		prevRoom = playbookState.CurrentRoom.Name
@@ -327,8 +378,9 @@ func (s *MCPServer) toolPlaybookAdvance(id any) jsonRPCResponse {
 		return s.toolError(id, fmt.Sprintf("advance: %v", err))
 	}
 
-	// Save state
-	_ = executor.SaveState(playbookState)
+	if err := store.Save(playbookState); err != nil {
+		return s.toolError(id, err.Error())
+	}
 
 	var output strings.Builder
 
@@ -376,8 +428,10 @@ func (s *MCPServer) toolPlaybookAdvance(id any) jsonRPCResponse {
 
 // toolPlaybookEvidence records evidence.
 func (s *MCPServer) toolPlaybookEvidence(id any, args map[string]interface{}) jsonRPCResponse {
-	if playbookState == nil {
-		return s.toolError(id, "no playbook execution in progress")
+	executor := s.getPlaybookExecutor()
+	store, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil {
+		return s.toolError(id, err.Error())
 	}
 
 	evidenceID := getStringArg(args, "evidence_id", "")
@@ -390,12 +444,12 @@ func (s *MCPServer) toolPlaybookEvidence(id any, args map[string]interface{}) js
 		data = getStringArg(args, "value", "collected")
 	}
 
-	executor := s.getPlaybookExecutor()
 	if err := executor.CollectEvidence(playbookState, evidenceID, data); err != nil {
 		return s.toolError(id, fmt.Sprintf("collect evidence: %v", err))
 	}
-
-	_ = executor.SaveState(playbookState)
+	if err := store.Save(playbookState); err != nil {
+		return s.toolError(id, err.Error())
+	}
 
 	var output strings.Builder
 	fmt.Fprintf(&output, "# ✅ Evidence Collected: %s\n\n", evidenceID)
@@ -423,16 +477,21 @@ func (s *MCPServer) toolPlaybookEvidence(id any, args map[string]interface{}) js
 }
 
 // toolPlaybookVerify runs verification checks.
-func (s *MCPServer) toolPlaybookVerify(id any) jsonRPCResponse {
-	if playbookState == nil {
-		return s.toolError(id, "no playbook execution to verify")
+func (s *MCPServer) toolPlaybookVerify(id any, args map[string]interface{}) jsonRPCResponse {
+	executor := s.getPlaybookExecutor()
+	store, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil {
+		return s.toolError(id, err.Error())
 	}
 
-	executor := s.getPlaybookExecutor()
-	results, err := executor.RunVerification(playbookState)
+	// Capability commands run project code, so agents only get the checklist.
+	results, err := executor.RunVerification(playbookState, playbook.VerifyOptions{Execute: s.mode == MCPModeHuman})
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("verify: %v", err))
 	}
+	if err := store.Save(playbookState); err != nil {
+		return s.toolError(id, err.Error())
+	}
 
 	var output strings.Builder
 	output.WriteString("# Verification Results\n\n")
@@ -458,6 +517,13 @@ func (s *MCPServer) toolPlaybookVerify(id any) jsonRPCResponse {
 			}
 			fmt.Fprintf(&output, "| %s | %s %s | %s |\n", r.Name, statusIcon, r.Status, detail)
 		}
+
+		for _, r := range results {
+			if r.Status != "failed" || r.Output == "" {
+				continue
+			}
+			fmt.Fprintf(&output, "\n## %s output\n\n```\n%s\n```\n", r.Name, strings.TrimRight(r.Output, "\n"))
+		}
 	}
 
 	return jsonRPCResponse{
@@ -469,27 +535,70 @@ func (s *MCPServer) toolPlaybookVerify(id any) jsonRPCResponse {
 	}
 }
 
-// toolPlaybookComplete marks the playbook as complete and clears state.
-func (s *MCPServer) toolPlaybookComplete(id any) jsonRPCResponse {
-	if playbookState == nil {
-		return s.toolError(id, "no playbook execution to complete")
+// toolPlaybookComplete marks the run as complete. The run stays in memory.db
+// so its evidence and verification results remain available.
+func (s *MCPServer) toolPlaybookComplete(id any, args map[string]interface{}) jsonRPCResponse {
+	executor := s.getPlaybookExecutor()
+	store, playbookState, err := s.loadPlaybookRun(executor, args)
+	if err != nil {
+		return s.toolError(id, err.Error())
 	}
 
-	executor := s.getPlaybookExecutor()
-	playbookName := playbookState.PlaybookName
 	progress := executor.GetProgress(playbookState)
-	evidenceCount := len(playbookState.Evidence)
-
-	// Clear state
-	_ = executor.ClearState()
-	playbookState = nil
+	executor.Finish(playbookState)
+	if err := store.Save(playbookState); err != nil {
+		return s.toolError(id, err.Error())
+	}
 
 	var output strings.Builder
 	output.WriteString("# Playbook Execution Completed\n\n")
-	fmt.Fprintf(&output, "**Playbook:** %s\n", playbookName)
+	fmt.Fprintf(&output, "**Playbook:** %s\n", playbookState.PlaybookName)
+	fmt.Fprintf(&output, "**Run ID:** `%s`\n", playbookState.ID)
 	fmt.Fprintf(&output, "**Final Progress:** %.1f%%\n", progress)
-	fmt.Fprintf(&output, "**Evidence Collected:** %d items\n\n", evidenceCount)
-	output.WriteString("Execution state has been cleared. You can start a new playbook.\n")
+	fmt.Fprintf(&output, "**Evidence Collected:** %d items\n\n", len(playbookState.Evidence))
+	output.WriteString("The run is kept in history (`palace playbook list`). You can start a new playbook.\n")
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// toolPlaybookRuns lists stored playbook runs across sessions.
+func (s *MCPServer) toolPlaybookRuns(id any, args map[string]interface{}) jsonRPCResponse {
+	store, err := s.playbookRunStore()
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+	runs, err := store.List(playbook.RunFilter{Status: getStringArg(args, "status", ""), Limit: 20})
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+
+	var output strings.Builder
+	output.WriteString("# Playbook Runs\n\n")
+	if len(runs) == 0 {
+		output.WriteString("No playbook runs yet. Use `action=start` with `name=<playbook>` to begin.\n")
+	} else {
+		executor := s.getPlaybookExecutor()
+		output.WriteString("| Run | Playbook | Status | Progress | Session | Updated |\n")
+		output.WriteString("|-----|----------|--------|----------|---------|---------|\n")
+		for _, run := range runs {
+			session := run.SessionID
+			if session == "" {
+				session = "-"
+			} else if session == s.playbookSession() {
+				session += " (you)"
+			}
+			fmt.Fprintf(&output, "| `%s` | %s | %s | %.0f%% | %s | %s |\n",
+				run.ID, run.PlaybookName, run.Status, executor.GetProgress(run), session,
+				run.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+		output.WriteString("\nPass `run_id` to other actions to work on a specific run.\n")
+	}
 
 	return jsonRPCResponse{
 		JSONRPC: "2.0",
@@ -0,0 +1,126 @@
+package butler
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+func TestPlaybookRunsPerSession(t *testing.T) {
+	serverA, b := setupMCPServer(t)
+	if b.Memory() == nil {
+		t.Skip("memory not available")
+	}
+	serverA.currentSessionID = "sess_a"
+	serverB := &MCPServer{butler: b, reader: bufio.NewReader(strings.NewReader("")), writer: &bytes.Buffer{}, currentSessionID: "sess_b"}
+
+	pbDir := filepath.Join(b.root, ".palace", "playbooks")
+	if err := os.MkdirAll(pbDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pb := `{"name":"review","summary":"Review core","rooms":["core"],"requiredEvidence":[{"id":"notes","description":"Review notes"}]}`
+	if err := os.WriteFile(filepath.Join(pbDir, "review.jsonc"), []byte(pb), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(s *MCPServer, args map[string]interface{}) (string, bool) {
+		resp := s.dispatchPlaybook(1, args, args["action"].(string))
+		return toolText(t, resp), resp.Result.(mcpToolResult).IsError
+	}
+
+	// Both sessions can run the same playbook at once.
+	for _, s := range []*MCPServer{serverA, serverB} {
+		if text, isErr := call(s, map[string]interface{}{"action": "start", "name": "review"}); isErr {
+			t.Fatalf("start for %s: %s", s.currentSessionID, text)
+		}
+	}
+	if text, isErr := call(serverA, map[string]interface{}{"action": "start", "name": "review"}); !isErr {
+		t.Errorf("second start in the same session should fail: %s", text)
+	}
+
+	if text, isErr := call(serverA, map[string]interface{}{"action": "evidence", "evidence_id": "notes", "value": "looks fine"}); isErr {
+		t.Fatalf("evidence: %s", text)
+	}
+
+	// Runs are read back from memory.db, so a fresh connection for the same
+	// session picks up where the previous one stopped.
+	restarted := &MCPServer{butler: b, reader: bufio.NewReader(strings.NewReader("")), writer: &bytes.Buffer{}, currentSessionID: "sess_a"}
+	text, isErr := call(restarted, map[string]interface{}{"action": "status"})
+	if isErr || !strings.Contains(text, "review") {
+		t.Fatalf("status after restart: %s", text)
+	}
+
+	store := playbook.NewStore(b.Memory().DB())
+	runA, _ := store.ActiveForSession("sess_a")
+	runB, _ := store.ActiveForSession("sess_b")
+	if runA == nil || runB == nil || runA.ID == runB.ID {
+		t.Fatalf("runs = %+v, %+v; want one per session", runA, runB)
+	}
+	if runA.Evidence["notes"] != "looks fine" || len(runB.Evidence) != 0 {
+		t.Errorf("evidence A = %v, B = %v", runA.Evidence, runB.Evidence)
+	}
+
+	// Agent mode never executes capability commands, but results are stored.
+	if text, isErr := call(restarted, map[string]interface{}{"action": "verify"}); isErr {
+		t.Fatalf("verify: %s", text)
+	}
+	if text, isErr := call(restarted, map[string]interface{}{"action": "complete"}); isErr {
+		t.Fatalf("complete: %s", text)
+	}
+	if active, _ := store.ActiveForSession("sess_a"); active != nil {
+		t.Errorf("session A still has a running playbook after complete: %+v", active)
+	}
+
+	// Session B can still address session A's finished run explicitly.
+	text, isErr = call(serverB, map[string]interface{}{"action": "status", "run_id": runA.ID})
+	if isErr || !strings.Contains(text, "completed") {
+		t.Errorf("status by run_id: %s", text)
+	}
+	text, _ = call(serverB, map[string]interface{}{"action": "runs"})
+	if !strings.Contains(text, runA.ID) || !strings.Contains(text, runB.ID) {
+		t.Errorf("runs output missing runs:\n%s", text)
+	}
+}
+
+func TestPlaybookRunsWithoutSession(t *testing.T) {
+	serverA, b := setupMCPServer(t)
+	if b.Memory() == nil {
+		t.Skip("memory not available")
+	}
+	serverB := &MCPServer{butler: b, reader: bufio.NewReader(strings.NewReader("")), writer: &bytes.Buffer{}}
+
+	pbDir := filepath.Join(b.root, ".palace", "playbooks")
+	if err := os.MkdirAll(pbDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pb := `{"name":"review","summary":"Review core","rooms":["core"],"requiredEvidence":[{"id":"notes","description":"Review notes"}]}`
+	if err := os.WriteFile(filepath.Join(pbDir, "review.jsonc"), []byte(pb), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Connections without a session must not share or resume each other's runs.
+	for _, s := range []*MCPServer{serverA, serverB} {
+		resp := s.dispatchPlaybook(1, map[string]interface{}{"action": "start", "name": "review"}, "start")
+		if text := toolText(t, resp); resp.Result.(mcpToolResult).IsError {
+			t.Fatalf("start: %s", text)
+		}
+	}
+	if serverA.playbookSession() == serverB.playbookSession() {
+		t.Fatalf("both connections use playbook session %q", serverA.playbookSession())
+	}
+
+	store := playbook.NewStore(b.Memory().DB())
+	if run, _ := store.ActiveForSession(""); run != nil {
+		t.Errorf("run stored without a session: %+v", run)
+	}
+	runA, _ := store.ActiveForSession(serverA.playbookSession())
+	runB, _ := store.ActiveForSession(serverB.playbookSession())
+	if runA == nil || runB == nil || runA.ID == runB.ID {
+		t.Fatalf("runs = %+v, %+v; want one per connection", runA, runB)
+	}
+}
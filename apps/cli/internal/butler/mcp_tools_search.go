@@ -3,6 +3,8 @@ package butler
 import (
 	"fmt"
 	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 )
 
 // toolExplore searches the codebase by intent or keywords.
@@ -47,7 +49,11 @@ func (s *MCPServer) toolExplore(id any, args map[string]interface{}) jsonRPCResp
 				entryMark = " ⭐ (entry point)"
 			}
 			fmt.Fprintf(&output, "### %s%s\n", r.Path, entryMark)
-			fmt.Fprintf(&output, "Lines %d-%d (score: %.2f)\n", r.StartLine, r.EndLine, r.Score)
+			if label := index.SymbolLabel(r.SymbolKind, r.Symbol); label != "" {
+				fmt.Fprintf(&output, "Lines %d-%d in `%s` (score: %.3f)\n", r.StartLine, r.EndLine, label, r.Score)
+			} else {
+				fmt.Fprintf(&output, "Lines %d-%d (score: %.3f)\n", r.StartLine, r.EndLine, r.Score)
+			}
 			fmt.Fprintf(&output, "```\n%s\n```\n\n", truncateSnippet(r.Snippet, 500))
 		}
 	}
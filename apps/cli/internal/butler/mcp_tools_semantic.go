@@ -18,7 +18,7 @@ func (s *MCPServer) toolSearchSemantic(id any, args map[string]interface{}) json
 	// Get embedder
 	embedder := s.butler.GetEmbedder()
 	if embedder == nil {
-		return s.toolError(id, "semantic search requires an embedding backend to be configured. See 'palace config' to set up Ollama or OpenAI embeddings.")
+		return s.toolError(id, "semantic search requires an embedding backend to be configured. See 'palace config' to set up Ollama, OpenAI or local embeddings.")
 	}
 
 	// Parse options
@@ -297,7 +297,7 @@ func (s *MCPServer) toolEmbeddingSync(id any, args map[string]interface{}) jsonR
 	// Get embedder
 	embedder := s.butler.GetEmbedder()
 	if embedder == nil {
-		return s.toolError(id, "embedding sync requires an embedding backend to be configured. See 'palace config' to set up Ollama or OpenAI embeddings.")
+		return s.toolError(id, "embedding sync requires an embedding backend to be configured. See 'palace config' to set up Ollama, OpenAI or local embeddings.")
 	}
 
 	// Parse options
@@ -336,6 +336,9 @@ func (s *MCPServer) toolEmbeddingSync(id any, args map[string]interface{}) jsonR
 		stats = &memory.EmbeddingStats{}
 	}
 
+	// Rebuild the ANN index so it matches the embeddings table
+	indexStats, indexErr := mem.RebuildVectorIndex()
+
 	// Format output
 	var output strings.Builder
 	output.WriteString("Embedding Sync Complete\n\n")
@@ -353,10 +356,21 @@ func (s *MCPServer) toolEmbeddingSync(id any, args map[string]interface{}) jsonR
 		output.WriteString("\nPipeline: not running\n")
 	}
 
+	if indexErr != nil {
+		output.WriteString(fmt.Sprintf("\nVector index: rebuild failed: %v\n", indexErr))
+	} else {
+		indexed := 0
+		for _, n := range indexStats.Indexed {
+			indexed += n
+		}
+		output.WriteString(fmt.Sprintf("\nVector index: rebuilt with %d vectors\n", indexed))
+	}
+
 	// JSON data
 	jsonData, _ := json.MarshalIndent(map[string]interface{}{
-		"processed": processed,
-		"stats":     stats,
+		"processed":   processed,
+		"stats":       stats,
+		"vectorIndex": indexStats,
 	}, "", "  ")
 	output.WriteString("\n---\nJSON Data:\n")
 	output.WriteString(string(jsonData))
@@ -404,6 +404,11 @@ func (s *MCPServer) toolBriefFile(id any, args map[string]interface{}) jsonRPCRe
 		}
 	}
 
+	if own, err := s.butler.GetFileOwnership(path); err == nil && own != nil {
+		output.WriteString("\n")
+		writeOwnership(&output, own)
+	}
+
 	return jsonRPCResponse{
 		JSONRPC: "2.0",
 		ID:      id,
@@ -691,15 +696,7 @@ func (s *MCPServer) toolSessionResume(id any, args map[string]interface{}) jsonR
 	}
 
 	// Check for accepted handoff
-	handoffMu.RLock()
-	var acceptedHandoff *Handoff
-	for _, h := range handoffStore {
-		if h.AcceptedBy == sessionID && h.Status == "accepted" {
-			acceptedHandoff = h
-			break
-		}
-	}
-	handoffMu.RUnlock()
+	acceptedHandoff := s.activeHandoffForSession(sessionID)
 
 	if acceptedHandoff != nil {
 		output.WriteString("## Active Handoff\n\n")
@@ -809,15 +806,7 @@ func (s *MCPServer) toolSessionStatus(id any, _ map[string]interface{}) jsonRPCR
 	}
 
 	// Active handoff
-	handoffMu.RLock()
-	var activeHandoff *Handoff
-	for _, h := range handoffStore {
-		if h.AcceptedBy == s.currentSessionID && h.Status == "accepted" {
-			activeHandoff = h
-			break
-		}
-	}
-	handoffMu.RUnlock()
+	activeHandoff := s.activeHandoffForSession(s.currentSessionID)
 
 	if activeHandoff != nil {
 		output.WriteString("## Active Handoff\n\n")
@@ -65,6 +65,16 @@ func Run(args []string) error {
 		return cmdInit(args[1:])
 	case "index":
 		return cmdIndex(args[1:])
+	case "history":
+		return cmdHistory(args[1:])
+	case "adr":
+		return cmdADR(args[1:])
+	case "deadcode":
+		return cmdDeadcode(args[1:])
+	case "patterns":
+		return cmdPatterns(args[1:])
+	case "contracts":
+		return cmdContracts(args[1:])
 	case "scan":
 		// Redirect to index scan for backward compatibility
 		return cmdIndex(append([]string{"scan"}, args[1:]...))
@@ -86,6 +96,12 @@ func Run(args []string) error {
 	// Agents & Sessions
 	case "session":
 		return cmdSession(args[1:])
+	case "handoff":
+		return cmdHandoff(args[1:])
+	case "playbook":
+		return cmdPlaybook(args[1:])
+	case "conversation":
+		return cmdConversation(args[1:])
 
 	// Cross-workspace
 	case "corridor":
@@ -182,6 +198,46 @@ func cmdIndex(args []string) error {
 	return commands.RunIndex(args)
 }
 
+// cmdHistory delegates to commands.RunHistory
+func cmdHistory(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("history")
+	}
+	return commands.RunHistory(args)
+}
+
+// cmdADR delegates to commands.RunADR
+func cmdADR(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("adr")
+	}
+	return commands.RunADR(args)
+}
+
+// cmdDeadcode delegates to commands.RunDeadcode
+func cmdDeadcode(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("deadcode")
+	}
+	return commands.RunDeadcode(args)
+}
+
+// cmdPatterns delegates to commands.RunPatterns
+func cmdPatterns(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("patterns")
+	}
+	return commands.RunPatterns(args)
+}
+
+// cmdContracts delegates to commands.RunContracts
+func cmdContracts(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("contracts")
+	}
+	return commands.RunContracts(args)
+}
+
 // ============================================================================
 // Service Commands - delegating to commands package
 // ============================================================================
@@ -222,6 +278,30 @@ func cmdSession(args []string) error {
 	return commands.RunSession(args)
 }
 
+// cmdHandoff delegates to commands.RunHandoff
+func cmdHandoff(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("handoff")
+	}
+	return commands.RunHandoff(args)
+}
+
+// cmdPlaybook delegates to commands.RunPlaybook
+func cmdPlaybook(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("playbook")
+	}
+	return commands.RunPlaybook(args)
+}
+
+// cmdConversation delegates to commands.RunConversation
+func cmdConversation(args []string) error {
+	if wantsHelp(args) {
+		return commands.ShowHelpTopic("conversation")
+	}
+	return commands.RunConversation(args)
+}
+
 // cmdCorridor delegates to commands.RunCorridor
 func cmdCorridor(args []string) error {
 	if wantsHelp(args) {
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/commands"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
@@ -468,7 +470,7 @@ func TestCmdCleanInvalidFlag(t *testing.T) {
 func TestCmdHelpKnownCommands(t *testing.T) {
 	// Only test commands that have help topics defined in cmdHelp
 	commandNames := []string{"init", "scan", "check", "explore", "store", "recall",
-		"brief", "serve", "session", "corridor", "dashboard", "clean"}
+		"brief", "serve", "session", "corridor", "dashboard", "clean", "patterns", "contracts"}
 
 	for _, cmd := range commandNames {
 		t.Run(cmd, func(t *testing.T) {
@@ -606,7 +608,7 @@ func TestCmdCorridorPromoteNoArgs(t *testing.T) {
 }
 
 func TestCmdSessionEndNoID(t *testing.T) {
-	err := commands.RunSessionEnd([]string{})
+	err := commands.RunSessionEnd([]string{"--root", t.TempDir()})
 	if err == nil {
 		t.Error("expected error for missing session ID")
 	}
@@ -839,3 +841,122 @@ func TestCmdStoreRecallBrief(t *testing.T) {
 		t.Fatalf("cmdStatus() error: %v", err)
 	}
 }
+
+func TestRunListFormats(t *testing.T) {
+	root := t.TempDir()
+	if err := cmdInit([]string{"--root", root}); err != nil {
+		t.Fatalf("cmdInit() error: %v", err)
+	}
+
+	for _, command := range []string{"patterns", "contracts"} {
+		t.Run(command, func(t *testing.T) {
+			if err := Run([]string{command, "list", "--root", root, "--format", "json"}); err != nil {
+				t.Errorf("Run(%s list --format json) error: %v", command, err)
+			}
+			err := Run([]string{command, "list", "--root", root, "--format", "xml"})
+			if err == nil || strings.Contains(err.Error(), "unknown command") {
+				t.Errorf("Run(%s list --format xml) error = %v, want an unknown format error", command, err)
+			}
+		})
+	}
+}
+
+func TestRunContractsExport(t *testing.T) {
+	root := t.TempDir()
+	if err := cmdInit([]string{"--root", root}); err != nil {
+		t.Fatalf("cmdInit() error: %v", err)
+	}
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	result := contracts.NewAnalyzer().Analyze(&contracts.AnalysisInput{
+		Endpoints: []contracts.EndpointInput{{Method: "GET", Path: "/api/users", File: filepath.Join(root, "server.go"), Line: 3}},
+		Calls:     []contracts.CallInput{{Method: "GET", URL: "/api/users", File: filepath.Join(root, "app.ts"), Line: 1}},
+	})
+	for _, c := range result.Contracts {
+		if err := contracts.NewStore(mem.DB()).UpsertContract(c); err != nil {
+			t.Fatalf("UpsertContract() error: %v", err)
+		}
+	}
+	mem.Close()
+
+	out := filepath.Join(t.TempDir(), "openapi.json")
+	if err := Run([]string{"contracts", "export", "--root", root, "--format", "openapi", "--output", out}); err != nil {
+		t.Fatalf("Run(contracts export) error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"openapi": "3.1`) || !strings.Contains(string(data), "/api/users") {
+		t.Errorf("exported document:\n%s", data)
+	}
+}
+
+func TestRunPatternsScanAndLint(t *testing.T) {
+	root := t.TempDir()
+	if err := cmdInit([]string{"--root", root}); err != nil {
+		t.Fatalf("cmdInit() error: %v", err)
+	}
+	detectors := filepath.Join(root, ".palace", "patterns")
+	if err := os.MkdirAll(detectors, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(detectors, "todo.jsonc"), `{
+  "schemaVersion": "1.0.0",
+  "kind": "palace/pattern-detector",
+  "id": "custom/todo-owner",
+  "name": "TODOs have owners",
+  "category": "documentation",
+  "match": { "regex": "TODO[^\\n]*" },
+  "require": { "regex": "TODO\\(\\w+\\)" }
+}`)
+	for i := 0; i < 5; i++ {
+		write(filepath.Join(root, fmt.Sprintf("f%d.go", i)), "package main\n\n// TODO(ana): tidy\n")
+	}
+	write(filepath.Join(root, "main.go"), "package main\n\n// TODO tidy\nfunc main() {}\n")
+
+	if err := Run([]string{"patterns", "lint", "--root", root}); err != nil {
+		t.Fatalf("Run(patterns lint) error: %v", err)
+	}
+	if err := Run([]string{"patterns", "scan", "--root", root}); err != nil {
+		t.Fatalf("Run(patterns scan) error: %v", err)
+	}
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	found, err := mem.GetPatterns(memory.PatternFilters{})
+	mem.Close()
+	if err != nil {
+		t.Fatalf("GetPatterns() error: %v", err)
+	}
+	custom := false
+	for _, p := range found {
+		custom = custom || p.DetectorID == "custom/todo-owner"
+	}
+	if !custom {
+		t.Errorf("scan stored no custom/todo-owner pattern: %+v", found)
+	}
+
+	write(filepath.Join(detectors, "bad-query.jsonc"), `{
+  "schemaVersion": "1.0.0",
+  "kind": "palace/pattern-detector",
+  "id": "custom/bad-query",
+  "name": "Bad query",
+  "category": "documentation",
+  "languages": ["go"],
+  "match": { "query": "(function_declaration" }
+}`)
+	err = Run([]string{"patterns", "lint", "--root", root})
+	if err == nil || !strings.Contains(err.Error(), "bad-query.jsonc") {
+		t.Errorf("Run(patterns lint) error = %v, want bad-query.jsonc reported", err)
+	}
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/adr"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "adr",
+		Description: "Sync decisions with Architecture Decision Records",
+		Run:         RunADR,
+	})
+}
+
+// RunADR dispatches to the appropriate adr subcommand.
+func RunADR(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace adr <command>
+
+Commands:
+  sync      Import ADR files as decisions and export decisions as ADR files
+
+Examples:
+  palace adr sync
+  palace adr sync --dir doc/architecture/decisions`)
+	}
+
+	switch args[0] {
+	case "sync":
+		return RunADRSync(args[1:])
+	default:
+		return fmt.Errorf("unknown adr command: %s\nRun 'palace help adr' for usage", args[0])
+	}
+}
+
+// ADRSyncOptions contains the configuration for adr sync.
+type ADRSyncOptions struct {
+	Root string
+	Dir  string // ADR directory relative to the root; .adr-dir or docs/adr when empty
+}
+
+// RunADRSync executes the adr sync subcommand.
+func RunADRSync(args []string) error {
+	fs := flag.NewFlagSet("adr sync", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	dir := fs.String("dir", "", "ADR directory (default: .adr-dir, else "+adr.DefaultDir+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return ExecuteADRSync(ADRSyncOptions{
+		Root: *root,
+		Dir:  *dir,
+	})
+}
+
+// ExecuteADRSync syncs decisions with the workspace's ADR directory.
+func ExecuteADRSync(opts ADRSyncOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+	dir := opts.Dir
+	if filepath.IsAbs(dir) {
+		if dir, err = filepath.Rel(rootPath, dir); err != nil || strings.HasPrefix(dir, "..") {
+			return fmt.Errorf("--dir must be inside the workspace: %s", opts.Dir)
+		}
+	}
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	result, err := adr.Sync(rootPath, mem, adr.Options{
+		Dir:   dir,
+		Rooms: playbook.LoadRooms(rootPath),
+	})
+	if err != nil {
+		return fmt.Errorf("sync ADRs: %w", err)
+	}
+
+	fmt.Printf("\n📜 Syncing decisions with %s\n", result.Dir)
+	fmt.Println(strings.Repeat("─", 60))
+
+	counts := map[adr.Action]int{}
+	for _, c := range result.Changes {
+		counts[c.Action]++
+		fmt.Printf("%s %04d %s %s\n", adrActionIcon(c.Action), c.Number, c.DecisionID, c.Action)
+		fmt.Printf("   %s\n", c.Path)
+		if c.Conflict {
+			fmt.Println("   ⚠️  Decision also changed in palace; the ADR file won")
+		}
+	}
+	if len(result.Changes) > 0 {
+		fmt.Println(strings.Repeat("─", 60))
+	}
+
+	fmt.Printf("Imported %d, exported %d, updated %d decision(s), rewrote %d file(s), %d unchanged\n",
+		counts[adr.ActionImported], counts[adr.ActionExported], counts[adr.ActionUpdated], counts[adr.ActionRewritten], result.Unchanged)
+	if result.Links > 0 {
+		fmt.Printf("Linked %d superseded decision(s)\n", result.Links)
+	}
+	return nil
+}
+
+func adrActionIcon(action adr.Action) string {
+	switch action {
+	case adr.ActionImported:
+		return "📥"
+	case adr.ActionExported:
+		return "📤"
+	default:
+		return "🔄"
+	}
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestRunADRNoArgs(t *testing.T) {
+	if err := RunADR([]string{}); err == nil {
+		t.Error("expected error for missing subcommand")
+	}
+	if err := RunADR([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
+
+func TestExecuteADRSyncAdrDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".adr-dir"), []byte("doc/decisions\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	if _, err := mem.AddDecision(memory.Decision{Content: "Use Go modules"}); err != nil {
+		t.Fatal(err)
+	}
+	mem.Close()
+
+	if err := ExecuteADRSync(ADRSyncOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteADRSync() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "doc", "decisions", "0001-use-go-modules.md")); err != nil {
+		t.Errorf("ADR not exported to .adr-dir: %v", err)
+	}
+
+	if err := ExecuteADRSync(ADRSyncOptions{Root: root, Dir: t.TempDir()}); err == nil {
+		t.Error("expected error for --dir outside the workspace")
+	}
+}
@@ -61,14 +61,14 @@ func TestRunRecallLinkInvalidFlag(t *testing.T) {
 }
 
 func TestRunRecallLinkMissingSourceID(t *testing.T) {
-	err := RunRecall([]string{"link"})
+	err := RunRecall([]string{"link", "--root", t.TempDir()})
 	if err == nil {
 		t.Error("expected error for missing source ID")
 	}
 }
 
 func TestRunRecallLinkMissingRelation(t *testing.T) {
-	err := RunRecall([]string{"link", "d_123"})
+	err := RunRecall([]string{"link", "--root", t.TempDir(), "d_123"})
 	if err == nil {
 		t.Error("expected error for missing relation")
 	}
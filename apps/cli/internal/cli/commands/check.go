@@ -1,18 +1,28 @@
 package commands
 
 import (
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/architecture"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/collect"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/lint"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/patterns"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/risk"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/signal"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/verify"
 )
@@ -30,11 +40,17 @@ type CheckOptions struct {
 	Root       string
 	DiffRange  string
 	Strict     bool
-	Collect    bool // Generate context pack
-	Signal     bool // Generate change signal
-	AllowStale bool // For collect: allow even if index is stale
+	Collect    bool   // Generate context pack
+	Signal     bool   // Generate change signal
+	AllowStale bool   // For collect: allow even if index is stale
+	Risk       bool   // Score the risk of the diff
+	FailOnRisk string // Fail when a file reaches this risk level (low, medium, high)
+	Format     string // text, json, sarif or junit
 }
 
+// ruleStaleFile is the finding rule for files changed since the last scan.
+const ruleStaleFile = "index/stale-file"
+
 // RunCheck executes the check command with parsed arguments.
 func RunCheck(args []string) error {
 	fs := flag.NewFlagSet("check", flag.ContinueOnError)
@@ -45,6 +61,9 @@ func RunCheck(args []string) error {
 	collectFlag := fs.Bool("collect", false, "also generate context pack from diff")
 	signalFlag := fs.Bool("signal", false, "also generate change signal from diff")
 	allowStale := fs.Bool("allow-stale", false, "for --collect: allow even if index is stale")
+	riskFlag := fs.Bool("risk", false, "also score the change risk of the diff")
+	failOnRisk := fs.String("fail-on-risk", "", "with --risk: fail when a file reaches this level (medium, high)")
+	format := fs.String("format", "text", "output format: text, json, sarif, junit")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -56,6 +75,9 @@ func RunCheck(args []string) error {
 		Collect:    *collectFlag,
 		Signal:     *signalFlag,
 		AllowStale: *allowStale,
+		Risk:       *riskFlag || *failOnRisk != "",
+		FailOnRisk: *failOnRisk,
+		Format:     *format,
 	})
 }
 
@@ -65,6 +87,20 @@ func ExecuteCheck(opts CheckOptions) error {
 	if err != nil {
 		return err
 	}
+	format, err := findings.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
+
+	// With a report format, stdout carries the report and the human-readable
+	// progress moves to stderr.
+	var out io.Writer = os.Stdout
+	var report *findings.Report
+	if format != findings.FormatText {
+		out = os.Stderr
+		report = &findings.Report{Tool: "palace check", Checks: []string{ruleStaleFile}}
+		report.Checks = append(report.Checks, contracts.MismatchRuleIDs()...)
+	}
 
 	// Run lint first
 	if err := lint.Run(rootPath); err != nil {
@@ -99,24 +135,51 @@ func ExecuteCheck(opts CheckOptions) error {
 		return err
 	}
 
-	util.PrintScope("check", fullScope, source, opts.DiffRange, candidateCount, rootPath)
+	if format == findings.FormatText {
+		util.PrintScope("check", fullScope, source, opts.DiffRange, candidateCount, rootPath)
+	}
+
+	if report != nil {
+		for _, s := range staleList {
+			report.Findings = append(report.Findings, findings.Finding{
+				RuleID:      ruleStaleFile,
+				RuleName:    "StaleIndexEntry",
+				Description: "File changed since the last scan",
+				Level:       findings.LevelError,
+				Message:     "file changed since the last scan; run 'palace scan'",
+				Location:    findings.Location{Path: s},
+			})
+		}
+		conventions, err := conventionFindings(rootPath, opts.DiffRange)
+		if err != nil {
+			return err
+		}
+		report.Findings = append(report.Findings, conventions...)
+	}
 
 	if len(staleList) > 0 {
-		fmt.Println("stale files detected:")
+		fmt.Fprintln(out, "stale files detected:")
 		preview := staleList
 		if len(preview) > 20 {
 			preview = preview[:20]
 		}
 		for _, s := range preview {
-			fmt.Printf("- %s\n", s)
+			fmt.Fprintf(out, "- %s\n", s)
 		}
 		if len(staleList) > len(preview) {
-			fmt.Printf("... and %d more\n", len(staleList)-len(preview))
+			fmt.Fprintf(out, "... and %d more\n", len(staleList)-len(preview))
+		}
+		if err := writeCheckReport(report, format); err != nil {
+			return err
 		}
 		return errors.New("index is stale; run 'palace scan'")
 	}
 
-	fmt.Printf("check ok; latest scan %s at %s\n", summary.ScanHash, summary.CompletedAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "check ok; latest scan %s at %s\n", summary.ScanHash, summary.CompletedAt.Format(time.RFC3339))
+
+	// Architecture violations fail the check once the optional outputs
+	// below have been generated.
+	archErr := checkArchitecture(out, rootPath, db, opts.DiffRange, report)
 
 	// Generate context pack if requested
 	if opts.Collect {
@@ -128,7 +191,7 @@ func ExecuteCheck(opts CheckOptions) error {
 		for _, warning := range result.CorridorWarnings {
 			fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
 		}
-		fmt.Printf("context pack updated from scan %s\n", cp.ScanHash)
+		fmt.Fprintf(out, "context pack updated from scan %s\n", cp.ScanHash)
 	}
 
 	// Generate change signal if requested
@@ -139,8 +202,248 @@ func ExecuteCheck(opts CheckOptions) error {
 		if _, err := signal.Generate(opts.Root, opts.DiffRange); err != nil {
 			return fmt.Errorf("signal generation failed: %w", err)
 		}
-		fmt.Println("change signal written to .palace/outputs/change-signal.json")
+		fmt.Fprintln(out, "change signal written to .palace/outputs/change-signal.json")
+	}
+
+	// Score change risk if requested
+	if opts.Risk {
+		if opts.DiffRange == "" {
+			return errors.New("--risk requires --diff range")
+		}
+		riskErr := checkRisk(out, rootPath, db, opts)
+		if err := writeCheckReport(report, format); err != nil {
+			return err
+		}
+		return errors.Join(archErr, riskErr)
+	}
+
+	if err := writeCheckReport(report, format); err != nil {
+		return err
+	}
+	return archErr
+}
+
+// conventionFindings reports the outliers of approved and discovered
+// patterns and the mismatches of every contract not ignored. With a diff
+// range only findings in the changed files are kept.
+func conventionFindings(rootPath, diffRange string) ([]findings.Finding, error) {
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	var list []findings.Finding
+	patternList, err := mem.GetPatterns(memory.PatternFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("get patterns: %w", err)
+	}
+	for i := range patternList {
+		outliers, err := mem.GetPatternOutliers(patternList[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		p := patterns.FromMemory(&patternList[i], outliers)
+		list = append(list, patterns.OutlierFindings(&p)...)
+	}
+
+	store := contracts.NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		return nil, fmt.Errorf("create contract tables: %w", err)
+	}
+	contractList, err := store.ListContracts(contracts.ContractFilter{HasMismatches: true})
+	if err != nil {
+		return nil, fmt.Errorf("list contracts: %w", err)
+	}
+	for _, c := range contractList {
+		list = append(list, contracts.MismatchFindings(rootPath, c)...)
+	}
+
+	if strings.TrimSpace(diffRange) != "" {
+		changed, err := changedPaths(rootPath, diffRange)
+		if err != nil {
+			return nil, err
+		}
+		kept := list[:0]
+		for i := range list {
+			if changed[list[i].Location.Path] || relatedChanged(list[i].Related, changed) {
+				kept = append(kept, list[i])
+			}
+		}
+		list = kept
 	}
 
+	findings.Sort(list)
+	return list, nil
+}
+
+// changedPaths returns the workspace-relative paths changed in diffRange.
+func changedPaths(rootPath, diffRange string) (map[string]bool, error) {
+	paths, _, err := signal.Paths(rootPath, diffRange, config.LoadGuardrails(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("diff unavailable for %q: %w", diffRange, err)
+	}
+	changed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		changed[p] = true
+	}
+	return changed, nil
+}
+
+func relatedChanged(related []findings.Location, changed map[string]bool) bool {
+	for _, loc := range related {
+		if changed[loc.Path] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkArchitecture evaluates the architecture rules of palace.jsonc against
+// the index and prints the violations, marking those the last scan did not
+// record. With a diff range only imports from changed files are kept.
+// Violations are added to report when it is not nil; any of error severity
+// fail the check.
+func checkArchitecture(out io.Writer, rootPath string, db *sql.DB, diffRange string, report *findings.Report) error {
+	checker, violations, err := architecture.Check(rootPath, db)
+	if err != nil {
+		return fmt.Errorf("architecture rules: %w", err)
+	}
+	if checker == nil {
+		return nil
+	}
+	if strings.TrimSpace(diffRange) != "" {
+		changed, err := changedPaths(rootPath, diffRange)
+		if err != nil {
+			return err
+		}
+		kept := violations[:0]
+		for i := range violations {
+			if changed[violations[i].SourceFile] {
+				kept = append(kept, violations[i])
+			}
+		}
+		violations = kept
+	}
+	if report != nil {
+		report.Checks = append(report.Checks, architecture.RuleIDs()...)
+		list := architecture.Findings(violations)
+		findings.Sort(list)
+		report.Findings = append(report.Findings, list...)
+	}
+
+	known, err := index.GetArchitectureViolations(db, "")
+	if err != nil {
+		return err
+	}
+	recorded := make(map[string]bool, len(known))
+	for i := range known {
+		recorded[known[i].Key()] = true
+	}
+
+	fmt.Fprintf(out, "\n🏛️  Architecture: %d rules\n", len(checker.Rules()))
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	if len(violations) == 0 {
+		fmt.Fprintln(out, "No violations.")
+		return nil
+	}
+	failures := 0
+	for i := range violations {
+		v := &violations[i]
+		if v.Severity == architecture.SeverityError {
+			failures++
+		}
+		suffix := ""
+		if !recorded[v.Key()] {
+			suffix = " (new since the last scan)"
+		}
+		fmt.Fprintf(out, "%-7s %s:%d %s (imports %s) [%s]%s\n", v.Severity, v.SourceFile, v.Line, v.Message, v.TargetFile, v.Rule, suffix)
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	fmt.Fprintf(out, "%d errors, %d warnings\n", failures, len(violations)-failures)
+	if failures > 0 {
+		return fmt.Errorf("%d architecture violation(s)", failures)
+	}
 	return nil
 }
+
+// writeCheckReport prints the findings report to stdout; it does nothing
+// for text output.
+func writeCheckReport(report *findings.Report, format findings.Format) error {
+	if report == nil {
+		return nil
+	}
+	return report.Write(os.Stdout, format, BuildVersion)
+}
+
+// checkRisk prints the diff's risk table, writes the JSON and SARIF reports
+// and applies the --fail-on-risk gate.
+func checkRisk(out io.Writer, rootPath string, db *sql.DB, opts CheckOptions) error {
+	var failOn risk.Level
+	if opts.FailOnRisk != "" {
+		level, err := risk.ParseLevel(opts.FailOnRisk)
+		if err != nil {
+			return err
+		}
+		failOn = level
+	}
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	report, err := risk.Analyze(rootPath, db, mem, opts.DiffRange)
+	if err != nil {
+		return fmt.Errorf("risk analysis failed: %w", err)
+	}
+
+	printRiskReport(out, report)
+
+	outputs := filepath.Join(rootPath, ".palace", "outputs")
+	if err := os.MkdirAll(outputs, 0o755); err != nil {
+		return err
+	}
+	if err := report.WriteJSON(filepath.Join(outputs, "risk-report.json")); err != nil {
+		return err
+	}
+	if err := report.SARIF(BuildVersion).WriteFile(filepath.Join(outputs, "risk-report.sarif")); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "risk report written to .palace/outputs/risk-report.json and risk-report.sarif")
+
+	if failOn != "" && len(report.Files) > 0 && report.Level.AtLeast(failOn) {
+		n := 0
+		for i := range report.Files {
+			if report.Files[i].Level.AtLeast(failOn) {
+				n++
+			}
+		}
+		return fmt.Errorf("%d file(s) at %s risk or above", n, failOn)
+	}
+	return nil
+}
+
+func printRiskReport(out io.Writer, report *risk.Report) {
+	fmt.Fprintf(out, "\n⚖️  Change risk for %s\n", report.DiffRange)
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	if len(report.Files) == 0 {
+		fmt.Fprintln(out, "No changed files.")
+		return
+	}
+	fmt.Fprintf(out, "%-6s %5s %6s %5s %4s %5s %5s %5s  %s\n", "RISK", "SCORE", "FAN-IN", "FAILS", "PMS", "OUTL", "CONTR", "CHURN", "FILE")
+	for i := range report.Files {
+		f := &report.Files[i]
+		fmt.Fprintf(out, "%-6s %5.0f %6.2f %5d %4d %5d %5d %5d  %s\n", f.Level, f.Score,
+			f.FanIn, f.Failures, len(f.Postmortems), f.Outliers, f.ContractMismatches, f.Churn90, f.Path)
+		for _, s := range f.Symbols {
+			if s.Level == risk.LevelLow {
+				continue
+			}
+			fmt.Fprintf(out, "%-6s %5.0f %37s└ %s %s (lines %d-%d)\n", s.Level, s.Score, "", s.Kind, s.Name, s.LineStart, s.LineEnd)
+		}
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 60))
+	fmt.Fprintf(out, "%d high, %d medium, %d low\n", report.Counts.High, report.Counts.Medium, report.Counts.Low)
+}
@@ -1,9 +1,17 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
 
 func TestRunCheckInvalidFlag(t *testing.T) {
@@ -75,3 +83,117 @@ func TestExecuteCheckStrictMode(t *testing.T) {
 		t.Fatalf("ExecuteCheck(Strict) error: %v", err)
 	}
 }
+
+func TestExecuteCheckRiskRequiresDiff(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+
+	if err := ExecuteCheck(CheckOptions{Root: root, Risk: true}); err == nil {
+		t.Error("expected error for --risk without --diff")
+	}
+	if err := RunCheck([]string{"--root", root, "--diff", "HEAD~1", "--fail-on-risk", "severe"}); err == nil {
+		t.Error("expected error for unknown --fail-on-risk level")
+	}
+}
+
+func TestExecuteCheckFormat(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	goFile := filepath.Join(root, "main.go")
+	if err := os.WriteFile(goFile, []byte("package main\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patternID, err := mem.AddPattern(memory.Pattern{Name: "Error wrapping", DetectorID: "error-wrapping", Status: "approved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mem.AddPatternLocation(memory.PatternLocation{PatternID: patternID, FilePath: "main.go", LineStart: 2, LineEnd: 2, IsOutlier: true}); err != nil {
+		t.Fatal(err)
+	}
+	store := contracts.NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveContract(&contracts.Contract{
+		ID: "ctr_1", Method: "GET", Endpoint: "/api/users", Status: contracts.ContractMismatch,
+		FirstSeen: time.Now(), LastSeen: time.Now(),
+		Backend:    contracts.BackendEndpoint{File: goFile, Line: 2},
+		Mismatches: []contracts.FieldMismatch{{ID: "fm_1", FieldPath: "id", Type: contracts.MismatchTypeMismatch, Severity: contracts.SeverityError}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	mem.Close()
+
+	if err := ExecuteCheck(CheckOptions{Root: root, Format: "xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	// Make main.go stale; the report is still printed.
+	if err := os.WriteFile(goFile, []byte("package main\n\nfunc main() { println() }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var checkErr error
+	out := captureStdout(t, func() {
+		checkErr = ExecuteCheck(CheckOptions{Root: root, Format: "json"})
+	})
+	if checkErr == nil {
+		t.Error("expected error for stale index")
+	}
+
+	var report struct {
+		Counts   map[string]int     `json:"counts"`
+		Findings []findings.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v (check: %v)\n%s", err, checkErr, out)
+	}
+	rules := map[string]string{}
+	for _, f := range report.Findings {
+		rules[f.RuleID] = f.Level
+		if f.RuleID == "type_mismatch" && f.Location.Path != "main.go" {
+			t.Errorf("contract finding path = %q, want main.go", f.Location.Path)
+		}
+	}
+	if rules[ruleStaleFile] != findings.LevelError || rules["error-wrapping"] != findings.LevelWarning || rules["type_mismatch"] != findings.LevelError {
+		t.Errorf("findings = %+v", report.Findings)
+	}
+	if report.Counts[findings.LevelError] != 2 {
+		t.Errorf("counts = %v", report.Counts)
+	}
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.Bytes()
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	return <-done
+}
@@ -1,16 +1,23 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts/extractors"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
 
@@ -35,6 +42,9 @@ type ContractsOptions struct {
 	ContractID   string
 	BackendDir   string
 	FrontendDir  string
+	SpecFiles    []string // OpenAPI documents besides those found by name
+	Format       string   // list output: text, json, sarif or junit; export: openapi
+	Output       string   // export destination; stdout when empty
 }
 
 // RunContracts executes the contracts command.
@@ -57,6 +67,8 @@ func RunContracts(args []string) error {
 		return RunContractsVerify(subArgs)
 	case "ignore":
 		return RunContractsIgnore(subArgs)
+	case "export":
+		return RunContractsExport(subArgs)
 	default:
 		return fmt.Errorf("unknown subcommand: %s\nRun 'palace contracts' for usage", subcommand)
 	}
@@ -73,15 +85,19 @@ Subcommands:
   show      Show contract details
   verify    Mark a contract as verified
   ignore    Ignore a contract
+  export    Export contracts as an OpenAPI 3.1 document
 
 Examples:
   palace contracts scan
   palace contracts scan --backend ./api --frontend ./web
+  palace contracts scan --spec api/openapi.yaml
   palace contracts list --status mismatch
   palace contracts list --method GET
+  palace contracts list --mismatches --format junit
   palace contracts show ctr_abc123
   palace contracts verify ctr_abc123
-  palace contracts ignore ctr_xyz789`)
+  palace contracts ignore ctr_xyz789
+  palace contracts export --format openapi --output openapi.yaml`)
 	return nil
 }
 
@@ -91,14 +107,23 @@ func RunContractsScan(args []string) error {
 	root := flags.AddRootFlag(fs)
 	backendDir := fs.String("backend", "", "backend source directory (defaults to project root)")
 	frontendDir := fs.String("frontend", "", "frontend source directory (defaults to project root)")
+	spec := fs.String("spec", "", "comma-separated OpenAPI/Swagger files (openapi.* and swagger.* are found automatically)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
+	var specFiles []string
+	for _, f := range strings.Split(*spec, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			specFiles = append(specFiles, f)
+		}
+	}
+
 	return ExecuteContractsScan(ContractsOptions{
 		Root:        *root,
 		BackendDir:  *backendDir,
 		FrontendDir: *frontendDir,
+		SpecFiles:   specFiles,
 	})
 }
 
@@ -169,27 +194,60 @@ func ExecuteContractsScan(opts ContractsOptions) error {
 	}
 	fmt.Printf("Found %d frontend API calls\n", len(calls))
 
+	// Extract endpoints declared in OpenAPI specs
+	specFiles, err := collectSpecFiles(backendDir)
+	if err != nil {
+		return fmt.Errorf("collect spec files: %w", err)
+	}
+	for _, f := range opts.SpecFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(rootPath, f)
+		}
+		if !slices.Contains(specFiles, f) {
+			specFiles = append(specFiles, f)
+		}
+	}
+	specEndpoints, err := extractSpecEndpoints(specFiles)
+	if err != nil {
+		return fmt.Errorf("extract spec endpoints: %w", err)
+	}
+	if len(specFiles) > 0 {
+		fmt.Printf("Found %d spec endpoints in %d OpenAPI file(s)\n", len(specEndpoints), len(specFiles))
+	}
+
 	// Analyze contracts
 	analyzer := contracts.NewAnalyzer()
 	input := &contracts.AnalysisInput{
 		Endpoints: endpoints,
 		Calls:     calls,
+		Spec:      specEndpoints,
 	}
 	result := analyzer.Analyze(input)
 
-	// Save contracts
+	// Save contracts, dropping those whose endpoint no longer exists
 	for _, contract := range result.Contracts {
-		if err := store.SaveContract(contract); err != nil {
+		if err := store.UpsertContract(contract); err != nil {
 			return fmt.Errorf("save contract: %w", err)
 		}
 	}
+	removed, err := store.RemoveMissing(result.Contracts, backendDir)
+	if err != nil {
+		return fmt.Errorf("remove stale contracts: %w", err)
+	}
 
 	// Print summary
 	fmt.Println()
 	fmt.Println("Scan complete")
 	fmt.Printf("  Contracts discovered: %d\n", len(result.Contracts))
+	if removed > 0 {
+		fmt.Printf("  Contracts removed (endpoint gone): %d\n", removed)
+	}
 	fmt.Printf("  Unmatched backend endpoints: %d\n", len(result.UnmatchedBackend))
 	fmt.Printf("  Unmatched frontend calls: %d\n", len(result.UnmatchedFrontend))
+	if len(specEndpoints) > 0 {
+		fmt.Printf("  Undocumented backend endpoints: %d\n", len(result.Undocumented))
+		fmt.Printf("  Unimplemented spec endpoints: %d\n", len(result.Unimplemented))
+	}
 
 	// Count mismatches
 	mismatchCount := 0
@@ -234,6 +292,9 @@ func ExecuteContractsScan(opts ContractsOptions) error {
 		}
 	}
 
+	printEndpointSample("Backend endpoints missing from the spec:", result.Undocumented)
+	printEndpointSample("Spec endpoints with no backend code:", result.Unimplemented)
+
 	// Show unmatched frontend calls
 	if len(result.UnmatchedFrontend) > 0 {
 		fmt.Println()
@@ -261,6 +322,7 @@ func RunContractsList(args []string) error {
 	endpoint := fs.String("endpoint", "", "filter by endpoint pattern")
 	hasMismatches := fs.Bool("mismatches", false, "only show contracts with mismatches")
 	limit := fs.Int("limit", 50, "maximum contracts to show")
+	format := fs.String("format", "text", "output format: text, json, sarif, junit (mismatches as findings)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -272,6 +334,7 @@ func RunContractsList(args []string) error {
 		Endpoint:      *endpoint,
 		HasMismatches: *hasMismatches,
 		Limit:         *limit,
+		Format:        *format,
 	})
 }
 
@@ -281,6 +344,10 @@ func ExecuteContractsList(opts ContractsOptions) error {
 	if err != nil {
 		return err
 	}
+	format, err := findings.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
 
 	mem, err := memory.Open(rootPath)
 	if err != nil {
@@ -301,6 +368,15 @@ func ExecuteContractsList(opts ContractsOptions) error {
 		return fmt.Errorf("list contracts: %w", err)
 	}
 
+	if format != findings.FormatText {
+		report := findings.Report{Tool: "palace contracts", Checks: contracts.MismatchRuleIDs()}
+		for _, c := range contractList {
+			report.Findings = append(report.Findings, contracts.MismatchFindings(rootPath, c)...)
+		}
+		findings.Sort(report.Findings)
+		return report.Write(os.Stdout, format, BuildVersion)
+	}
+
 	if len(contractList) == 0 {
 		fmt.Println("No contracts found.")
 		fmt.Println("Run 'palace contracts scan' to detect contracts.")
@@ -444,6 +520,19 @@ func ExecuteContractsShow(opts ContractsOptions) error {
 		printSchema(contract.Backend.ResponseSchema, "    ")
 	}
 
+	if spec := contract.Spec; spec != nil {
+		fmt.Println()
+		fmt.Println("Spec:")
+		fmt.Printf("  File:      %s:%d\n", spec.File, spec.Line)
+		if spec.OperationID != "" {
+			fmt.Printf("  Operation: %s\n", spec.OperationID)
+		}
+		if spec.ResponseSchema != nil {
+			fmt.Println("  Response Schema:")
+			printSchema(spec.ResponseSchema, "    ")
+		}
+	}
+
 	// Frontend calls
 	fmt.Println()
 	fmt.Printf("Frontend Calls: %d\n", len(contract.FrontendCalls))
@@ -465,8 +554,12 @@ func ExecuteContractsShow(opts ContractsOptions) error {
 			if m.Severity == contracts.SeverityError {
 				severity = "ERROR"
 			}
-			fmt.Printf("  [%s] %s: %s\n", severity, m.FieldPath, m.Description)
-			if m.BackendType != "" && m.FrontendType != "" {
+			scope := ""
+			if m.Scope != contracts.ScopeBackendFrontend {
+				scope = " (" + strings.ReplaceAll(string(m.Scope), "_", " vs ") + ")"
+			}
+			fmt.Printf("  [%s] %s: %s%s\n", severity, m.FieldPath, m.Description, scope)
+			if m.Scope == contracts.ScopeBackendFrontend && m.BackendType != "" && m.FrontendType != "" {
 				fmt.Printf("    Backend: %s, Frontend: %s\n", m.BackendType, m.FrontendType)
 			}
 		}
@@ -623,6 +716,85 @@ func ExecuteContractsIgnore(opts ContractsOptions) error {
 	return nil
 }
 
+// RunContractsExport exports contracts in another format.
+func RunContractsExport(args []string) error {
+	fs := flag.NewFlagSet("contracts export", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	format := fs.String("format", "openapi", "export format: openapi")
+	output := fs.String("output", "", "write to file instead of stdout (.yaml/.yml writes YAML, otherwise JSON)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return ExecuteContractsExport(ContractsOptions{
+		Root:   *root,
+		Format: *format,
+		Output: *output,
+	})
+}
+
+// ExecuteContractsExport writes all contracts except ignored ones as an
+// OpenAPI 3.1 document.
+func ExecuteContractsExport(opts ContractsOptions) error {
+	if opts.Format != "openapi" {
+		return fmt.Errorf("unsupported export format %q (supported: openapi)", opts.Format)
+	}
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	store := contracts.NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		return fmt.Errorf("create contract tables: %w", err)
+	}
+	contractList, err := store.ListContracts(contracts.ContractFilter{})
+	if err != nil {
+		return fmt.Errorf("list contracts: %w", err)
+	}
+
+	info := contracts.OpenAPIInfo{
+		Title:       filepath.Base(rootPath),
+		Version:     "1.0.0",
+		Description: fmt.Sprintf("Generated by Mind Palace from %d API contracts.", len(contractList)),
+	}
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil && cfg.Project.Name != "" {
+		info.Title = cfg.Project.Name
+	}
+	doc := contracts.ExportOpenAPI(contractList, info)
+
+	var buf bytes.Buffer
+	switch strings.ToLower(filepath.Ext(opts.Output)) {
+	case ".yaml", ".yml":
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(doc)
+	default:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(doc)
+	}
+	if err != nil {
+		return fmt.Errorf("encode openapi: %w", err)
+	}
+
+	if opts.Output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(opts.Output, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", opts.Output, err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d paths to %s\n", len(doc.Paths), opts.Output)
+	return nil
+}
+
 // Helper functions for file collection and extraction
 
 func collectBackendFiles(dir string) ([]string, error) {
@@ -679,6 +851,29 @@ func collectFrontendFiles(dir string) ([]string, error) {
 	return files, err
 }
 
+// collectSpecFiles finds OpenAPI documents by name, such as openapi.yaml or
+// petstore.swagger.json.
+func collectSpecFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if name == "node_modules" || name == "vendor" || name == ".git" || name == "dist" || name == "build" || name == ".palace" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if extractors.IsOpenAPIFileName(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
 func isLikelyBackendJS(path string) bool {
 	// Check if the file path suggests backend
 	lowerPath := strings.ToLower(path)
@@ -742,6 +937,52 @@ func extractEndpoints(files []string) ([]contracts.EndpointInput, error) {
 	return endpoints, nil
 }
 
+// extractSpecEndpoints reads OpenAPI documents. Unlike source files, a spec
+// that fails to parse is an error: it is meant to be the source of truth.
+func extractSpecEndpoints(files []string) ([]contracts.EndpointInput, error) {
+	var endpoints []contracts.EndpointInput
+	extractor := extractors.NewOpenAPIExtractor()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		extracted, err := extractor.ExtractEndpointsFromContent(content, file)
+		if err != nil {
+			return nil, err
+		}
+		for i := range extracted {
+			ep := &extracted[i]
+			endpoints = append(endpoints, contracts.EndpointInput{
+				Method:         ep.Method,
+				Path:           ep.Path,
+				File:           ep.File,
+				Line:           ep.Line,
+				Handler:        ep.Handler,
+				Framework:      ep.Framework,
+				RequestSchema:  ep.RequestSchema,
+				ResponseSchema: ep.ResponseSchema,
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+func printEndpointSample(title string, endpoints []contracts.UnmatchedEndpoint) {
+	if len(endpoints) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(title)
+	for i, ep := range endpoints {
+		if i >= 5 {
+			fmt.Printf("  ... and %d more\n", len(endpoints)-i)
+			break
+		}
+		fmt.Printf("  - %s %s (%s:%d)\n", ep.Method, ep.Path, ep.File, ep.Line)
+	}
+}
+
 //nolint:unparam // error return kept for future error handling
 func extractCalls(files []string) ([]contracts.CallInput, error) {
 	var calls []contracts.CallInput
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/llm"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/transcript"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "conversation",
+		Description: "Import agent conversation transcripts",
+		Run:         RunConversation,
+	})
+}
+
+// RunConversation dispatches to the appropriate conversation subcommand.
+func RunConversation(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace conversation <command>
+
+Commands:
+  import    Import transcripts from an agent's logs
+
+Examples:
+  palace conversation import --from claude-code auto
+  palace conversation import --from codex ~/.codex/sessions/2025/06/01
+  palace conversation import --from cursor auto --extract`)
+	}
+
+	switch args[0] {
+	case "import":
+		return RunConversationImport(args[1:])
+	default:
+		return fmt.Errorf("unknown conversation command: %s\nRun 'palace help conversation' for usage", args[0])
+	}
+}
+
+// ConversationImportOptions contains the configuration for conversation import.
+type ConversationImportOptions struct {
+	Root    string
+	From    string // agent type: claude-code, cursor, or codex
+	Path    string // transcript file or directory, or "auto"
+	Extract bool   // run LLM extraction on new and updated conversations
+}
+
+// RunConversationImport executes the conversation import subcommand.
+func RunConversationImport(args []string) error {
+	fs := flag.NewFlagSet("conversation import", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	from := fs.String("from", "", "agent that wrote the transcripts ("+strings.Join(transcript.Agents(), ", ")+")")
+	extract := fs.Bool("extract", false, "extract ideas, decisions and learnings with the configured LLM")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	remaining := fs.Args()
+	if *from == "" || len(remaining) == 0 {
+		return errors.New("usage: palace conversation import --from <agent> <path|auto> [--extract]")
+	}
+	if _, err := transcript.Lookup(*from); err != nil {
+		return err
+	}
+
+	return ExecuteConversationImport(ConversationImportOptions{
+		Root:    *root,
+		From:    *from,
+		Path:    remaining[0],
+		Extract: *extract,
+	})
+}
+
+// ExecuteConversationImport imports transcripts into memory, linking each to
+// the session that was active while it was recorded.
+func ExecuteConversationImport(opts ConversationImportOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+
+	// Resolve the LLM before importing so a missing configuration fails fast.
+	var client llm.Client
+	if opts.Extract {
+		if client, err = newLLMClient(rootPath); err != nil {
+			return fmt.Errorf("--extract needs an LLM (set llmBackend in palace.jsonc): %w", err)
+		}
+	}
+
+	transcripts, err := transcript.Load(opts.From, rootPath, opts.Path)
+	if err != nil {
+		return fmt.Errorf("read %s transcripts: %w", opts.From, err)
+	}
+	if len(transcripts) == 0 {
+		fmt.Printf("No %s transcripts found.\n", opts.From)
+		return nil
+	}
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	var extractor *memory.LLMExtractor
+	if client != nil {
+		extractor = memory.NewLLMExtractor(client, mem)
+	}
+
+	fmt.Printf("\n💬 Importing %s transcripts\n", opts.From)
+	fmt.Println(strings.Repeat("─", 60))
+
+	counts := map[memory.ConversationImportStatus]int{}
+	extracted := 0
+	for i := range transcripts {
+		t := &transcripts[i]
+		conv := memory.Conversation{
+			AgentType: t.Agent,
+			Summary:   t.Summary,
+			Messages:  t.Messages,
+		}
+		session, err := mem.FindSessionForWindow(t.Agent, t.StartedAt, t.EndedAt, memory.DefaultSessionMatchWindow)
+		if err != nil {
+			return err
+		}
+		if session != nil {
+			conv.SessionID = session.ID
+		}
+
+		res, err := mem.ImportConversation(conv, t.Source())
+		if err != nil {
+			return fmt.Errorf("import %s: %w", t.Path, err)
+		}
+		counts[res.Status]++
+
+		fmt.Printf("%s %s %s (%d messages)\n", conversationImportIcon(res.Status), res.ID, res.Status, len(t.Messages))
+		fmt.Printf("   %s\n", util.TruncateLine(t.Summary, 56))
+		if conv.SessionID != "" {
+			fmt.Printf("   Session: %s\n", conv.SessionID)
+		}
+
+		// Only messages not seen by an earlier import are extracted, so
+		// re-importing a grown transcript doesn't duplicate its records.
+		if extractor != nil && len(res.NewMessages) > 0 {
+			conv.ID = res.ID
+			conv.Messages = res.NewMessages
+			if stored, err := mem.GetConversation(res.ID); err == nil {
+				conv.Extracted = stored.Extracted
+			}
+			recordIDs, err := extractor.ExtractFromConversation(conv)
+			if err != nil {
+				fmt.Printf("   ⚠️  Extraction failed: %v\n", err)
+				continue
+			}
+			extracted += len(recordIDs)
+			fmt.Printf("   Extracted: %d record(s)\n", len(recordIDs))
+		}
+	}
+
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Added %d, updated %d, skipped %d duplicate(s)\n",
+		counts[memory.ConversationImportAdded], counts[memory.ConversationImportUpdated], counts[memory.ConversationImportDuplicate])
+	if extractor != nil {
+		fmt.Printf("Extracted %d record(s); review decisions and learnings with 'palace proposals'\n", extracted)
+	}
+	return nil
+}
+
+// newLLMClient creates an LLM client from the workspace's palace.jsonc.
+func newLLMClient(rootPath string) (llm.Client, error) {
+	cfg, err := config.LoadPalaceConfig(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("load palace config: %w", err)
+	}
+	return llm.NewClient(llm.Config{
+		Backend: cfg.LLMBackend,
+		Model:   cfg.LLMModel,
+		URL:     cfg.LLMURL,
+		APIKey:  cfg.LLMAPIKey,
+	})
+}
+
+func conversationImportIcon(status memory.ConversationImportStatus) string {
+	switch status {
+	case memory.ConversationImportAdded:
+		return "➕"
+	case memory.ConversationImportUpdated:
+		return "🔄"
+	default:
+		return "⏭️ "
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestRunConversationNoArgs(t *testing.T) {
+	if err := RunConversation([]string{}); err == nil {
+		t.Error("expected error for missing subcommand")
+	}
+	if err := RunConversation([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
+
+func TestRunConversationImportValidation(t *testing.T) {
+	if err := RunConversationImport([]string{"auto"}); err == nil {
+		t.Error("expected error for missing --from")
+	}
+	if err := RunConversationImport([]string{"--from", "claude-code"}); err == nil {
+		t.Error("expected error for missing path")
+	}
+	if err := RunConversationImport([]string{"--from", "copilot", "auto"}); err == nil {
+		t.Error("expected error for unknown agent")
+	}
+}
+
+func TestExecuteConversationImport(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	lines := `{"type":"user","sessionId":"s1","timestamp":"2025-06-01T12:00:00Z","message":{"role":"user","content":"Use sqlite for the cache"}}
+{"type":"assistant","sessionId":"s1","timestamp":"2025-06-01T12:01:00Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"Agreed."}]}}
+`
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ConversationImportOptions{Root: root, From: "claude-code", Path: path}
+	for i := 0; i < 2; i++ {
+		if err := ExecuteConversationImport(opts); err != nil {
+			t.Fatalf("ExecuteConversationImport() run %d error: %v", i+1, err)
+		}
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	convs, err := mem.GetConversations("", "claude-code", 10)
+	if err != nil {
+		t.Fatalf("GetConversations() error: %v", err)
+	}
+	if len(convs) != 1 {
+		t.Fatalf("got %d conversations after importing twice, want 1", len(convs))
+	}
+	if convs[0].Summary != "Use sqlite for the cache" || len(convs[0].Messages) != 2 {
+		t.Errorf("conversation = %+v", convs[0])
+	}
+}
+
+func TestExecuteConversationImportExtractNeedsLLM(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	err := ExecuteConversationImport(ConversationImportOptions{Root: root, From: "codex", Path: t.TempDir(), Extract: true})
+	if err == nil {
+		t.Error("expected error when --extract is used without an LLM backend")
+	}
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/deadcode"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "deadcode",
+		Description: "Find symbols and files nothing uses",
+		Run:         RunDeadcode,
+	})
+}
+
+// DeadcodeOptions contains the configuration for the deadcode command.
+type DeadcodeOptions struct {
+	Root          string
+	MinConfidence string // high, medium or low
+	File          string // only this file
+	Format        string // text, json, sarif or junit
+	Limit         int    // symbols to print in text output; 0 for all
+	Propose       bool   // file cleanup learning proposals
+}
+
+// RunDeadcode executes the deadcode command with parsed arguments.
+func RunDeadcode(args []string) error {
+	fs := flag.NewFlagSet("deadcode", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	minConfidence := fs.String("min-confidence", string(deadcode.ConfidenceMedium), "lowest confidence to report: high, medium, low")
+	file := fs.String("file", "", "only report this file")
+	format := fs.String("format", "text", "output format: text, json, sarif, junit")
+	limit := flags.AddLimitFlag(fs, 50)
+	propose := fs.Bool("propose", false, "file a cleanup learning proposal per file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := flags.ValidateLimit(*limit); err != nil {
+		return err
+	}
+
+	return ExecuteDeadcode(DeadcodeOptions{
+		Root:          *root,
+		MinConfidence: *minConfidence,
+		File:          *file,
+		Format:        *format,
+		Limit:         *limit,
+		Propose:       *propose,
+	})
+}
+
+// ExecuteDeadcode reports unused code and optionally proposes its cleanup.
+func ExecuteDeadcode(opts DeadcodeOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+	minConfidence, err := deadcode.ParseConfidence(opts.MinConfidence)
+	if err != nil {
+		return err
+	}
+	format, err := findings.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
+	}
+	db, err := index.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	full, err := deadcode.Analyze(rootPath, db, mem)
+	if err != nil {
+		return fmt.Errorf("analyze dead code: %w", err)
+	}
+	report := full.Filter(minConfidence, filepath.ToSlash(opts.File))
+
+	// With a report format, stdout carries the report and the proposal
+	// summary moves to stderr.
+	var out io.Writer = os.Stdout
+	if format != findings.FormatText {
+		out = os.Stderr
+		list := report.Findings()
+		findings.Sort(list)
+		structured := &findings.Report{
+			Tool:     "palace deadcode",
+			Findings: list,
+			Checks:   []string{deadcode.RuleFile, deadcode.RuleSymbol},
+		}
+		if err := structured.Write(os.Stdout, format, BuildVersion); err != nil {
+			return err
+		}
+	} else {
+		printDeadcode(out, report, opts.Limit)
+	}
+
+	if opts.Propose {
+		ids, duplicates, err := deadcode.Propose(mem, report, minConfidence)
+		if err != nil {
+			return fmt.Errorf("propose cleanup: %w", err)
+		}
+		fmt.Fprintf(out, "\nProposed %d, skipped %d duplicate(s)\n", len(ids), duplicates)
+		if len(ids) > 0 {
+			fmt.Fprintln(out, "Review them with 'palace proposals --type learning'")
+		}
+	}
+	return nil
+}
+
+func printDeadcode(w io.Writer, r *deadcode.Report, limit int) {
+	fmt.Fprintf(w, "\n🪦 Unused code\n")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	fmt.Fprintf(w, "%d symbol(s), %d file(s): %d high, %d medium, %d low confidence\n",
+		len(r.Symbols), len(r.Files), r.Counts.High, r.Counts.Medium, r.Counts.Low)
+
+	if len(r.Files) > 0 {
+		fmt.Fprintln(w, "\nFiles:")
+		for _, f := range r.Files {
+			fmt.Fprintf(w, "  [%-6s] %s\n           %s\n", f.Confidence, f.Path, f.Reason)
+		}
+	}
+	if len(r.Symbols) > 0 {
+		fmt.Fprintln(w, "\nSymbols:")
+		for i, s := range r.Symbols {
+			if limit > 0 && i == limit {
+				fmt.Fprintf(w, "  ... %d more (raise --limit)\n", len(r.Symbols)-limit)
+				break
+			}
+			fmt.Fprintf(w, "  [%-6s] %s:%d %s %s\n           %s\n", s.Confidence, s.File, s.LineStart, s.Kind, s.Name, s.Reason)
+		}
+	}
+	if len(r.Files) == 0 && len(r.Symbols) == 0 {
+		fmt.Fprintln(w, "\nNothing unused found.")
+	}
+}
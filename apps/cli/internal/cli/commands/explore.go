@@ -1,10 +1,13 @@
 package commands
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +17,7 @@ import (
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/depgraph"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/jsonc"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
@@ -44,6 +48,9 @@ type ExploreOptions struct {
 	File      string // --file: file path for map mode
 	Depth     int    // --depth: recursion depth for call chain tracing
 	Direction string // --direction: up, down, or both
+	// Graph report
+	Format string // --format: text, json, dot or mermaid
+	Output string // --output: file to write the report to
 }
 
 // reorderArgsForFlags moves flags to the front so Go's flag package can parse them.
@@ -61,7 +68,7 @@ func reorderArgsForFlags(args []string) []string {
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") && !strings.Contains(arg, "=") {
 				// Could be a flag with value, check if it's a boolean flag
 				// Boolean flags: -full, -fuzzy, -rooms
-				boolFlags := map[string]bool{"-full": true, "--full": true, "-fuzzy": true, "--fuzzy": true, "-rooms": true, "--rooms": true}
+				boolFlags := map[string]bool{"-full": true, "--full": true, "-fuzzy": true, "--fuzzy": true, "-rooms": true, "--rooms": true, "-graph-report": true, "--graph-report": true}
 				if !boolFlags[arg] {
 					i++
 					flags = append(flags, args[i])
@@ -92,6 +99,9 @@ func RunExplore(args []string) error {
 	depth := fs.Int("depth", 0, "recursion depth for call chain tracing (1-10)")
 	direction := fs.String("direction", "up", "trace direction: up (callers), down (callees), or both")
 	listRooms := fs.Bool("rooms", false, "list all configured rooms")
+	graphReport := fs.Bool("graph-report", false, "analyze the import graph: cycles, coupling, dead files and bridges")
+	format := fs.String("format", "text", "output format for --graph-report: text, json, dot, mermaid")
+	output := fs.String("output", "", "for --graph-report: write to file instead of stdout")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -106,6 +116,10 @@ func RunExplore(args []string) error {
 		return runExploreListRooms(*root)
 	}
 
+	if *graphReport {
+		return runExploreGraphReport(ExploreOptions{Root: *root, Limit: *limit, Format: *format, Output: *output})
+	}
+
 	remaining := fs.Args()
 
 	// Determine mode based on flags
@@ -133,6 +147,12 @@ Get full context for a task:
 List configured rooms:
   palace explore --rooms
 
+Analyze the import graph:
+  palace explore --graph-report                          # Cycles, coupling, dead files
+  palace explore --graph-report --format json
+  palace explore --graph-report --format dot --output deps.dot
+  palace explore --graph-report --format mermaid
+
 Trace call relationships (direct):
   palace explore --map handleAuth              # Who calls handleAuth?
   palace explore --map Search --file butler.go # What does Search call?
@@ -218,7 +238,11 @@ func runExploreSearch(opts ExploreOptions) error {
 				entryMark = " ⭐"
 			}
 			fmt.Printf("  📄 %s%s\n", r.Path, entryMark)
-			fmt.Printf("     Lines %d-%d  (score: %.2f)\n", r.StartLine, r.EndLine, r.Score)
+			if label := index.SymbolLabel(r.SymbolKind, r.Symbol); label != "" {
+				fmt.Printf("     Lines %d-%d in %s  (score: %.3f)\n", r.StartLine, r.EndLine, label, r.Score)
+			} else {
+				fmt.Printf("     Lines %d-%d  (score: %.3f)\n", r.StartLine, r.EndLine, r.Score)
+			}
 
 			snippet := r.Snippet
 			lines := strings.Split(snippet, "\n")
@@ -357,7 +381,7 @@ func runExploreMapCallers(db *sql.DB, symbol string) error {
 		if call.CallerSymbol != "" {
 			callerInfo = fmt.Sprintf(" (in %s)", call.CallerSymbol)
 		}
-		fmt.Printf("  📍 %s:%d%s\n", call.FilePath, call.Line, callerInfo)
+		fmt.Printf("  📍 %s:%d%s%s\n", call.FilePath, call.Line, callerInfo, heuristicLabel(call.Resolution))
 	}
 
 	return nil
@@ -381,7 +405,7 @@ func runExploreMapCallees(db *sql.DB, symbol, filePath string) error {
 	fmt.Printf("Found %d function calls:\n\n", len(calls))
 
 	for _, call := range calls {
-		fmt.Printf("  📞 %s (line %d)\n", call.CalleeSymbol, call.Line)
+		fmt.Printf("  📞 %s (line %d)%s\n", call.CalleeSymbol, call.Line, heuristicLabel(call.Resolution))
 	}
 
 	return nil
@@ -407,7 +431,7 @@ func runExploreMapFile(db *sql.DB, filePath string) error {
 			if call.CallerSymbol != "" {
 				callerInfo = fmt.Sprintf(" (from %s)", call.CallerSymbol)
 			}
-			fmt.Printf("  📍 %s called from %s:%d%s\n", call.CalleeSymbol, call.FilePath, call.Line, callerInfo)
+			fmt.Printf("  📍 %s called from %s:%d%s%s\n", call.CalleeSymbol, call.FilePath, call.Line, callerInfo, heuristicLabel(call.Resolution))
 		}
 	}
 
@@ -421,7 +445,7 @@ func runExploreMapFile(db *sql.DB, filePath string) error {
 			if call.CallerSymbol != "" {
 				callerInfo = fmt.Sprintf(" (from %s)", call.CallerSymbol)
 			}
-			fmt.Printf("  📞 %s at line %d%s\n", call.CalleeSymbol, call.Line, callerInfo)
+			fmt.Printf("  📞 %s at line %d%s%s\n", call.CalleeSymbol, call.Line, callerInfo, heuristicLabel(call.Resolution))
 		}
 	}
 
@@ -493,6 +517,14 @@ func runExploreCallChain(root, symbol, filePath string, depth int, direction str
 	return nil
 }
 
+// heuristicLabel flags call sites matched by name rather than resolved to a symbol.
+func heuristicLabel(resolution string) string {
+	if resolution == index.ResolutionHeuristic {
+		return " [heuristic]"
+	}
+	return ""
+}
+
 // printCallChainTree recursively prints the call chain as a tree.
 func printCallChainTree(nodes []*index.CallChainNode, prefix string) {
 	for i, node := range nodes {
@@ -509,7 +541,7 @@ func printCallChainTree(nodes []*index.CallChainNode, prefix string) {
 		if node.FilePath != "" {
 			location = fmt.Sprintf(" (%s:%d)", node.FilePath, node.Line)
 		}
-		fmt.Printf("%s%s %s%s\n", prefix, branch, node.Symbol, location)
+		fmt.Printf("%s%s %s%s%s\n", prefix, branch, node.Symbol, location, heuristicLabel(node.Resolution))
 
 		// Calculate prefix for children
 		childPrefix := prefix
@@ -576,3 +608,125 @@ func runExploreListRooms(root string) error {
 
 	return nil
 }
+
+// runExploreGraphReport analyzes the import graph of the index.
+func runExploreGraphReport(opts ExploreOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
+	}
+	db, err := index.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := depgraph.Analyze(rootPath, db)
+	if err != nil {
+		return fmt.Errorf("analyze import graph: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case "", "text":
+		printGraphReport(&buf, report, opts.Limit)
+	case "json":
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+	case "dot":
+		buf.WriteString(report.DOT())
+	case "mermaid":
+		buf.WriteString(report.Mermaid())
+	default:
+		return fmt.Errorf("unsupported format %q (supported: text, json, dot, mermaid)", opts.Format)
+	}
+
+	if opts.Output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(opts.Output, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", opts.Output, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote graph report to %s\n", opts.Output)
+	return nil
+}
+
+// printGraphReport writes the report as text, listing up to limit entries
+// per section.
+func printGraphReport(w io.Writer, r *depgraph.Report, limit int) {
+	more := func(n int) {
+		if n > limit {
+			fmt.Fprintf(w, "  ... and %d more\n", n-limit)
+		}
+	}
+
+	fmt.Fprintf(w, "\n🕸️  Import Graph\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "%d files, %d imports, %d packages\n", r.Files, r.Imports, len(r.Packages))
+
+	fmt.Fprintf(w, "\n🔁 Import Cycles (%d)\n", len(r.Cycles))
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	if len(r.Cycles) == 0 {
+		fmt.Fprintln(w, "  No import cycles.")
+	}
+	for i, c := range r.Cycles {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(w, "  %d files in %s\n", len(c.Files), strings.Join(c.Packages, ", "))
+		for _, f := range c.Files {
+			fmt.Fprintf(w, "    • %s\n", f)
+		}
+	}
+	more(len(r.Cycles))
+	for _, c := range r.PackageCycles {
+		fmt.Fprintf(w, "  Package cycle: %s\n", strings.Join(c, " → "))
+	}
+
+	fmt.Fprintf(w, "\n📦 Package Coupling\n")
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	fmt.Fprintf(w, "  %-40s %5s %5s %6s\n", "PACKAGE", "Ca", "Ce", "I")
+	for i := range r.Packages {
+		if i == limit {
+			break
+		}
+		p := &r.Packages[i]
+		fmt.Fprintf(w, "  %-40s %5d %5d %6.2f\n", util.TruncateLine(p.Name, 40), p.Afferent, p.Efferent, p.Instability)
+	}
+	more(len(r.Packages))
+
+	fmt.Fprintf(w, "\n🪦 Dead Files (%d)\n", len(r.DeadFiles))
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	if len(r.DeadFiles) == 0 {
+		fmt.Fprintln(w, "  Every file is imported or an entry point.")
+	}
+	for i, f := range r.DeadFiles {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(w, "  • %s\n", f)
+	}
+	more(len(r.DeadFiles))
+
+	fmt.Fprintf(w, "\n🌉 Bridges\n")
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+	if len(r.Bridges) == 0 {
+		fmt.Fprintln(w, "  No single file holds the graph together.")
+	}
+	for i, b := range r.Bridges {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(w, "  • %s (cuts off %d files)\n", b.Path, b.Cuts)
+	}
+	more(len(r.Bridges))
+}
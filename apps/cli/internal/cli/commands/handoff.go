@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "handoff",
+		Description: "Manage task handoffs between agents",
+		Run:         RunHandoff,
+	})
+}
+
+// RunHandoff dispatches to the appropriate handoff subcommand.
+func RunHandoff(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace handoff <command>
+
+Commands:
+  list      List handoffs
+  show      Show handoff details
+  accept    Accept a pending handoff
+  complete  Mark a handoff as completed
+
+Examples:
+  palace handoff list
+  palace handoff list --status all --agent claude
+  palace handoff show HANDOFF_ID
+  palace handoff accept HANDOFF_ID
+  palace handoff complete HANDOFF_ID --summary "migrated auth"`)
+	}
+
+	switch args[0] {
+	case "list":
+		return RunHandoffList(args[1:])
+	case "show":
+		return RunHandoffShow(args[1:])
+	case "accept":
+		return RunHandoffAccept(args[1:])
+	case "complete":
+		return RunHandoffComplete(args[1:])
+	default:
+		return fmt.Errorf("unknown handoff command: %s\nRun 'palace help handoff' for usage", args[0])
+	}
+}
+
+// HandoffListOptions contains the configuration for handoff list.
+type HandoffListOptions struct {
+	Root   string
+	Status string // "all" lists every status
+	Agent  string
+	Limit  int
+}
+
+// RunHandoffList executes the handoff list subcommand.
+func RunHandoffList(args []string) error {
+	fs := flag.NewFlagSet("handoff list", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	status := fs.String("status", memory.HandoffStatusPending, "filter by status (pending, accepted, completed, expired, all)")
+	agent := fs.String("agent", "", "only handoffs addressed to this agent type (or any)")
+	limit := flags.AddLimitFlag(fs, 20)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := flags.ValidateLimit(*limit); err != nil {
+		return err
+	}
+	switch *status {
+	case memory.HandoffStatusPending, memory.HandoffStatusAccepted, memory.HandoffStatusCompleted, memory.HandoffStatusExpired, "all":
+	default:
+		return fmt.Errorf("invalid status %q: must be pending, accepted, completed, expired, or all", *status)
+	}
+
+	return ExecuteHandoffList(HandoffListOptions{
+		Root:   *root,
+		Status: *status,
+		Agent:  *agent,
+		Limit:  *limit,
+	})
+}
+
+// ExecuteHandoffList lists handoffs.
+func ExecuteHandoffList(opts HandoffListOptions) error {
+	mem, err := openHandoffMemory(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+
+	status := opts.Status
+	if status == "all" {
+		status = ""
+	}
+	handoffs, err := mem.GetHandoffs(status, opts.Agent, opts.Limit)
+	if err != nil {
+		return fmt.Errorf("list handoffs: %w", err)
+	}
+
+	if len(handoffs) == 0 {
+		fmt.Println("No handoffs found.")
+		return nil
+	}
+
+	fmt.Printf("\n📬 Handoffs\n")
+	fmt.Println(strings.Repeat("─", 60))
+
+	for i := range handoffs {
+		h := &handoffs[i]
+		fmt.Printf("%s %s [%s] %s\n", handoffStatusIcon(h.Status), h.ID, h.Priority, h.Status)
+		fmt.Printf("   Task: %s\n", util.TruncateLine(h.Task, 50))
+		fmt.Printf("   From: %s → %s\n", h.FromAgent, h.ToAgentType)
+		if h.Status == memory.HandoffStatusPending {
+			fmt.Printf("   Expires: %s\n", h.ExpiresAt.Format(time.RFC3339))
+		} else {
+			fmt.Printf("   Created: %s\n", h.CreatedAt.Format(time.RFC3339))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// HandoffShowOptions contains the configuration for handoff show.
+type HandoffShowOptions struct {
+	Root      string
+	HandoffID string
+}
+
+// RunHandoffShow executes the handoff show subcommand.
+func RunHandoffShow(args []string) error {
+	fs := flag.NewFlagSet("handoff show", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	remaining := fs.Args()
+	if len(remaining) == 0 {
+		return errors.New("usage: palace handoff show HANDOFF_ID")
+	}
+
+	return ExecuteHandoffShow(HandoffShowOptions{
+		Root:      *root,
+		HandoffID: remaining[0],
+	})
+}
+
+// ExecuteHandoffShow shows handoff details.
+func ExecuteHandoffShow(opts HandoffShowOptions) error {
+	mem, err := openHandoffMemory(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+
+	h, err := mem.GetHandoff(opts.HandoffID)
+	if err != nil {
+		return fmt.Errorf("get handoff: %w", err)
+	}
+
+	printHandoff(h)
+	return nil
+}
+
+// HandoffAcceptOptions contains the configuration for handoff accept.
+type HandoffAcceptOptions struct {
+	Root      string
+	HandoffID string
+	By        string
+}
+
+// RunHandoffAccept executes the handoff accept subcommand.
+func RunHandoffAccept(args []string) error {
+	fs := flag.NewFlagSet("handoff accept", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	by := fs.String("by", "cli", "identifier of who is taking over (session ID or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	remaining := fs.Args()
+	if len(remaining) == 0 {
+		return errors.New("usage: palace handoff accept HANDOFF_ID [--by NAME]")
+	}
+
+	return ExecuteHandoffAccept(HandoffAcceptOptions{
+		Root:      *root,
+		HandoffID: remaining[0],
+		By:        *by,
+	})
+}
+
+// ExecuteHandoffAccept accepts a pending handoff.
+func ExecuteHandoffAccept(opts HandoffAcceptOptions) error {
+	mem, err := openHandoffMemory(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+
+	h, err := mem.AcceptHandoff(opts.HandoffID, opts.By, memory.HandoffActor{Type: memory.AuditActorHuman, ID: opts.By})
+	if err != nil {
+		return fmt.Errorf("accept handoff: %w", err)
+	}
+
+	fmt.Printf("Handoff %s accepted by %s\n", h.ID, h.AcceptedBy)
+	printHandoff(h)
+	return nil
+}
+
+// HandoffCompleteOptions contains the configuration for handoff complete.
+type HandoffCompleteOptions struct {
+	Root      string
+	HandoffID string
+	Summary   string
+	By        string
+}
+
+// RunHandoffComplete executes the handoff complete subcommand.
+func RunHandoffComplete(args []string) error {
+	fs := flag.NewFlagSet("handoff complete", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	summary := fs.String("summary", "", "completion summary")
+	by := fs.String("by", "cli", "identifier of who completed the work")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	remaining := fs.Args()
+	if len(remaining) == 0 {
+		return errors.New("usage: palace handoff complete HANDOFF_ID [--summary TEXT]")
+	}
+
+	return ExecuteHandoffComplete(HandoffCompleteOptions{
+		Root:      *root,
+		HandoffID: remaining[0],
+		Summary:   *summary,
+		By:        *by,
+	})
+}
+
+// ExecuteHandoffComplete marks a handoff as completed.
+func ExecuteHandoffComplete(opts HandoffCompleteOptions) error {
+	mem, err := openHandoffMemory(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+
+	h, err := mem.CompleteHandoff(opts.HandoffID, opts.Summary, memory.HandoffActor{Type: memory.AuditActorHuman, ID: opts.By})
+	if err != nil {
+		return fmt.Errorf("complete handoff: %w", err)
+	}
+
+	fmt.Printf("Handoff %s marked as completed\n", h.ID)
+	return nil
+}
+
+func openHandoffMemory(root string) (*memory.Memory, error) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("open memory: %w", err)
+	}
+	return mem, nil
+}
+
+func printHandoff(h *memory.Handoff) {
+	fmt.Printf("\n%s Handoff: %s\n", handoffStatusIcon(h.Status), h.ID)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Status:     %s\n", h.Status)
+	fmt.Printf("Priority:   %s\n", h.Priority)
+	fmt.Printf("From:       %s", h.FromAgent)
+	if h.FromSessionID != "" {
+		fmt.Printf(" (session: %s)", h.FromSessionID)
+	}
+	fmt.Println()
+	fmt.Printf("To:         %s\n", h.ToAgentType)
+	fmt.Printf("Created:    %s\n", h.CreatedAt.Format(time.RFC3339))
+	fmt.Printf("Expires:    %s\n", h.ExpiresAt.Format(time.RFC3339))
+	if h.AcceptedBy != "" {
+		fmt.Printf("Accepted:   %s by %s\n", h.AcceptedAt.Format(time.RFC3339), h.AcceptedBy)
+	}
+	if !h.CompletedAt.IsZero() {
+		fmt.Printf("Completed:  %s\n", h.CompletedAt.Format(time.RFC3339))
+	}
+
+	fmt.Printf("\nTask: %s\n", h.Task)
+	if h.Context != "" {
+		fmt.Printf("\nContext:\n  %s\n", h.Context)
+	}
+	if len(h.PendingWork) > 0 {
+		fmt.Printf("\nPending Work:\n")
+		for _, item := range h.PendingWork {
+			fmt.Printf("  [ ] %s\n", item)
+		}
+	}
+	if len(h.PinnedRecords) > 0 {
+		fmt.Printf("\nPinned Records: %s\n", strings.Join(h.PinnedRecords, ", "))
+	}
+	if h.Summary != "" {
+		fmt.Printf("\nSummary: %s\n", h.Summary)
+	}
+}
+
+func handoffStatusIcon(status string) string {
+	switch status {
+	case memory.HandoffStatusPending:
+		return "📬"
+	case memory.HandoffStatusAccepted:
+		return "🔄"
+	case memory.HandoffStatusExpired:
+		return "⌛"
+	default:
+		return "✅"
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestRunHandoffNoArgs(t *testing.T) {
+	if err := RunHandoff([]string{}); err == nil {
+		t.Error("expected error for missing subcommand")
+	}
+}
+
+func TestRunHandoffUnknownSubcommand(t *testing.T) {
+	if err := RunHandoff([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
+
+func TestRunHandoffListInvalidStatus(t *testing.T) {
+	if err := RunHandoffList([]string{"--status", "bogus"}); err == nil {
+		t.Error("expected error for invalid status")
+	}
+}
+
+func TestRunHandoffMissingID(t *testing.T) {
+	for name, run := range map[string]func([]string) error{
+		"show":     RunHandoffShow,
+		"accept":   RunHandoffAccept,
+		"complete": RunHandoffComplete,
+	} {
+		if err := run([]string{}); err == nil {
+			t.Errorf("%s: expected error for missing handoff ID", name)
+		}
+	}
+}
+
+func TestExecuteHandoffAcceptAndComplete(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	h, err := mem.CreateHandoff(memory.Handoff{Task: "finish docs"}, memory.HandoffActor{Type: memory.AuditActorAgent})
+	mem.Close()
+	if err != nil {
+		t.Fatalf("CreateHandoff() error: %v", err)
+	}
+
+	if err := ExecuteHandoffList(HandoffListOptions{Root: root, Status: "all", Limit: 10}); err != nil {
+		t.Fatalf("ExecuteHandoffList() error: %v", err)
+	}
+	if err := ExecuteHandoffAccept(HandoffAcceptOptions{Root: root, HandoffID: h.ID, By: "alice"}); err != nil {
+		t.Fatalf("ExecuteHandoffAccept() error: %v", err)
+	}
+	if err := ExecuteHandoffAccept(HandoffAcceptOptions{Root: root, HandoffID: h.ID, By: "bob"}); err == nil {
+		t.Error("expected error accepting an accepted handoff")
+	}
+	if err := ExecuteHandoffComplete(HandoffCompleteOptions{Root: root, HandoffID: h.ID, Summary: "done", By: "alice"}); err != nil {
+		t.Fatalf("ExecuteHandoffComplete() error: %v", err)
+	}
+	if err := ExecuteHandoffShow(HandoffShowOptions{Root: root, HandoffID: "hoff_missing"}); err == nil {
+		t.Error("expected error for unknown handoff")
+	}
+}
@@ -37,13 +37,21 @@ CORE COMMANDS
 SETUP & INDEX
   init      Initialize the palace in the current directory
   index     Manage code index (scan, check, stats)
+  history   Mine git history for decisions and learnings
+  adr       Sync decisions with Architecture Decision Records
+  deadcode  Find symbols and files nothing uses
+  patterns  Detect code conventions and govern them
+  contracts Check frontend-backend API contracts
 
 SERVICES
   serve     Start MCP server for AI agents
   lsp       Start Language Server Protocol server for editors
 
 AGENTS & SESSIONS
-  session   Manage agent sessions
+  session       Manage agent sessions
+  handoff       Manage task handoffs between agents
+  playbook      Run playbooks and inspect playbook runs
+  conversation  Import agent conversation transcripts
 
 CROSS-WORKSPACE
   corridor  Cross-workspace knowledge sharing
@@ -98,6 +106,7 @@ Options:
   --no-gitignore      Skip .gitignore updates
   --no-hooks          Skip git hooks installation
   --no-vscode         Skip VS Code integration (extensions.json)
+  --mine-history      Propose decisions and learnings from git history
 
 Agent Integration:
   The --with-agents flag installs MCP server configuration and agent rules
@@ -110,7 +119,114 @@ Examples:
   palace init --with-agents auto           # Auto-detect and configure agents
   palace init --with-agents cursor,vscode  # Configure specific agents
   palace init --no-gitignore --no-hooks    # Skip integrations
+  palace init --mine-history               # Bootstrap memory from git log
 `)
+	case "history":
+		fmt.Print(`palace history - Mine git history for decisions and learnings
+
+Usage: palace history mine [options]
+
+Walks recent commits and files pending proposals for review:
+  decision     Commits that record a choice ("switch to", "instead of",
+               "replace X with", "deprecate", "decided to", ...)
+  learning     Fix commits whose message explains the cause ("root cause",
+               "turns out", "the bug was", ...)
+  postmortem   Revert commits; approving one creates a postmortem
+
+Each proposal's evidence names the commit SHA and the files it changed.
+Every commit also adds to the edit counts of the files it touched in
+file intelligence; fix and revert commits add to failure counts.
+
+Mined commits are remembered, so running again only looks at new commits.
+
+Options:
+  --root <path>           Workspace root (default: current directory)
+  --since <date>          Only commits after this date (git --since syntax)
+  --limit <n>             Most recent commits to examine (default: 1000)
+  --min-confidence <n>    Confidence needed to propose, 0-1 (default: 0.6)
+  --llm                   Review candidates with the configured LLM, which
+                          may rewrite or drop them
+  --dry-run               Show candidates without storing anything
+
+Examples:
+  palace history mine --dry-run
+  palace history mine --since "1 year ago"
+  palace history mine --llm
+  palace proposals --type postmortem
+`)
+	case "adr":
+		fmt.Print(`palace adr - Sync decisions with Architecture Decision Records
+
+Usage: palace adr sync [options]
+
+Two-way sync between decisions and a directory of numbered ADR files
+(adr-tools/Nygard or MADR layout):
+  - ADR files without a decision are imported. Their scope is read from a
+    "Scope:" line, or inferred from the files, directories and rooms the
+    ADR mentions.
+  - Decisions without a file are exported as the next numbered ADR.
+  - For pairs synced before, the side that changed since the last sync
+    overwrites the other. When both changed, the ADR file wins.
+
+Mapping:
+  Accepted       active, approved
+  Proposed       active, proposed
+  Superseded     superseded
+  Deprecated     reversed (Rejected is read the same way)
+  ## Outcome     outcome and outcome note
+  Supersedes N   supersedes link to ADR N's decision
+
+Sections the sync does not manage, such as Consequences or Considered
+Options, are kept when a file is rewritten. Running sync twice in a row
+changes nothing.
+
+Options:
+  --root <path>    Workspace root (default: current directory)
+  --dir <path>     ADR directory (default: .adr-dir, else docs/adr)
+
+Examples:
+  palace adr sync
+  palace adr sync --dir doc/architecture/decisions
+`)
+	case "deadcode":
+		fmt.Print(`palace deadcode - Find symbols and files nothing uses
+
+Usage: palace deadcode [options]
+
+Combines the indexed symbols, their call and reference relationships and
+the import graph. Roots are never reported, and whatever they use is live:
+  - symbols in test files and room entry points
+  - main, init and constructor functions, and __dunder__ methods
+  - HTTP handlers found by contract extraction
+
+A symbol that only unused code calls is unused too. Only languages with
+call tracking are analyzed; local variables, properties and constructors
+are skipped.
+
+Confidence:
+  high     The name appears nowhere but its definition
+  medium   Exported, a method, or named elsewhere in its file; files whose
+           symbols are all unused but that something imports
+  low      The name appears in other files, e.g. passed as a value
+
+Options:
+  --root <path>             Workspace root (default: current directory)
+  --min-confidence <level>  Lowest confidence to report: high, medium, low
+                            (default: medium)
+  --file <path>             Only report this file
+  --format <fmt>            Output format: text, json, sarif, junit
+  --limit <n>               Symbols to print in text output (default: 50)
+  --propose                 File a cleanup learning proposal per file
+
+Examples:
+  palace deadcode
+  palace deadcode --min-confidence high --propose
+  palace deadcode --format sarif > deadcode.sarif
+`)
+	case "patterns":
+		return showPatternsHelp()
+	case "contracts":
+		return showContractsHelp()
 	case "index":
 		fmt.Print(`palace index - Manage the code index
 
@@ -146,6 +262,8 @@ Options:
   --workers <n>, -j   Number of parallel workers (0 = auto-detect based on CPU)
   --verbose, -v       Show detailed progress information
   --debug             Show debug information (LSP communication, etc.)
+  --no-ownership      Skip the git blame and CODEOWNERS ownership pass
+  --no-embed          Skip embedding code for semantic search
 
 The scan command parses your codebase using Tree-sitter and builds a structural index.
 Parallel workers are used by default to speed up scanning on multi-core machines.
@@ -155,6 +273,10 @@ to find changed files (faster). Otherwise, uses hash-based change detection.
 
 For Dart/Flutter projects, deep analysis runs automatically to extract accurate call graphs.
 
+In a git repository the scan also records code ownership: per-file and per-symbol
+blame shares, the last author, commit churn over 30/90/365 days and CODEOWNERS
+entries. Only files whose content changed are blamed again.
+
 Examples:
   palace index scan                  # Auto-detect: git-based if possible
   palace index scan --full           # Force full rescan
@@ -175,8 +297,26 @@ Options:
   --strict            Hash all files (slower but thorough)
   --collect           Also generate context pack from diff
   --signal            Also generate change signal from diff
+  --risk              Also score the change risk of each file and symbol in the diff
+  --fail-on-risk <l>  Fail when a file reaches risk level l (medium or high); implies --risk
+  --format <f>        Report format: text (default), json, sarif or junit
 
 The check command ensures the index is up-to-date and validates configuration.
+
+With --risk, each changed file is scored 0-100 from the fan-in of the symbols it
+touches, its recorded failures, linked postmortems, pattern outliers and contract
+mismatches in the changed lines, and 90-day churn. The table is printed and the
+report is written to .palace/outputs/risk-report.json and risk-report.sarif.
+
+With --format json, sarif or junit the findings are printed to stdout and the
+usual output moves to stderr. Findings are stale files, outliers of approved
+(warning) and discovered (note) patterns, contract mismatches and, with --risk,
+medium- and high-risk changes. With --diff only findings in changed files are kept.
+
+Examples:
+  palace check --diff main...HEAD --risk
+  palace check --diff origin/main...HEAD --fail-on-risk high
+  palace check --diff origin/main...HEAD --format sarif > palace.sarif
 `)
 	case "explore":
 		fmt.Print(`palace explore - Search code, get context, or trace calls
@@ -257,7 +397,32 @@ Examples:
 	case "serve":
 		fmt.Print(`palace serve - Start MCP server for AI agents
 
-Starts a Model Context Protocol server on stdio.
+Starts a Model Context Protocol server. By default it speaks JSON-RPC
+over stdio; use --transport http to run one long-lived server that
+several agents or dev containers can share over Streamable HTTP.
+
+Usage: palace serve [options]
+
+Options:
+  --root <path>         Workspace root (default: .)
+  --mode <mode>         'agent' (restricted, default) or 'human' (full access)
+  --transport <name>    'stdio' (default) or 'http'
+  --addr <host:port>    Listen address for http (default: 127.0.0.1:7337)
+  --watch               Re-index changed files while serving
+  --debounce <dur>      Debounce delay for --watch (default: 500ms)
+
+Each HTTP client gets its own MCP session (Mcp-Session-Id header) with
+separate session tracking, file conflict monitoring and context focus.
+
+With --watch the index, patterns and contracts are updated as files
+change, and clients receive notifications/resources/updated for files
+they subscribed to or opened through file tools.
+
+Examples:
+  palace serve                              # stdio, for a single agent
+  palace serve --watch                      # keep the index current
+  palace serve --transport http             # shared server on localhost
+  palace serve --transport http --addr 0.0.0.0:7337
 `)
 	case "proposals":
 		fmt.Print(`palace proposals - Manage proposals
@@ -273,7 +438,7 @@ Subcommands:
 
 Options for list:
   --status <status>  Filter: pending, approved, rejected, expired, all
-  --type <type>      Filter by type: decision, learning
+  --type <type>      Filter by type: decision, learning, postmortem
   --limit <n>        Maximum proposals to show (default: 20)
 
 Options for approve/reject:
@@ -318,6 +483,102 @@ Usage: palace session <command> [options]
 
 Commands:
   start, end, list, show
+`)
+	case "handoff":
+		fmt.Print(`palace handoff - Manage task handoffs between agents
+
+Usage: palace handoff <command> [options]
+
+Handoffs are created by agents through the MCP handoff tool and stored in
+.palace/memory.db. Pending handoffs expire after their deadline (24h by
+default). Every state change is recorded in the audit log.
+
+Commands:
+  list                List handoffs (pending by default)
+  show <id>           Show handoff details
+  accept <id>         Accept a pending handoff
+  complete <id>       Mark a handoff as completed
+
+Options:
+  --root <path>       Workspace root (default: current directory)
+  --status <status>   list: pending, accepted, completed, expired, or all
+  --agent <type>      list: only handoffs addressed to this agent type
+  --limit <n>         list: maximum results (default: 20)
+  --by <name>         accept/complete: who is acting (default: cli)
+  --summary <text>    complete: completion summary
+
+Examples:
+  palace handoff list --status all
+  palace handoff accept hoff_abc123 --by alice
+  palace handoff complete hoff_abc123 --summary "auth migration done"
+`)
+	case "playbook":
+		fmt.Print(`palace playbook - Run playbooks and inspect playbook runs
+
+Usage: palace playbook <command> [options]
+
+Playbooks are defined in .palace/playbooks/*.jsonc. Runs are stored in
+.palace/memory.db together with completed steps, evidence and verification
+results, and are shared with the MCP playbook tool. Each session has at most
+one running playbook; CLI runs belong to the "cli" session unless --session
+is given.
+
+Commands:
+  list                      List playbooks and recent runs
+  start <playbook>          Start a run
+  status [run-id]           Show progress, evidence and verification results
+  advance [run-id]          Complete the current step
+  evidence <id> [value]     Record evidence on a run
+  verify [run-id]           Run verification checks (runs lint.run and
+                            tests.run from .palace/project-profile.json)
+
+Without a run ID, commands use the session's running run, or its most
+recent one.
+
+Options:
+  --root <path>       Workspace root (default: current directory)
+  --session <id>      Session that owns the run (default: cli)
+  --run <id>          Run to act on (evidence, or instead of the argument)
+  --status <status>   list: running, completed, or all (default: all)
+  --limit <n>         list: maximum runs (default: 20)
+  --timeout <dur>     verify: timeout per command (default: 5m)
+
+Examples:
+  palace playbook start bug-fix
+  palace playbook advance
+  palace playbook evidence root-cause "nil map in cache warmup"
+  palace playbook verify
+  palace playbook list --status running
+`)
+	case "conversation":
+		fmt.Print(`palace conversation - Import agent conversation transcripts
+
+Usage: palace conversation import --from <agent> <path|auto> [options]
+
+Reads the transcripts coding agents keep on disk and stores them as
+conversations in .palace/memory.db. Each transcript is linked to the session
+that was active while it was recorded (within 10 minutes either side).
+Re-importing is safe: identical transcripts are skipped and transcripts that
+grew since the last import are updated.
+
+Agents:
+  claude-code   JSONL session logs in ~/.claude/projects/<workspace>/
+  codex         Rollout files in ~/.codex/sessions/ (or $CODEX_HOME)
+  cursor        state.vscdb chat stores in Cursor's workspaceStorage
+
+Pass "auto" to read the agent's default location for this workspace, or a
+transcript file or directory.
+
+Options:
+  --root <path>   Workspace root (default: current directory)
+  --from <agent>  Agent that wrote the transcripts (required)
+  --extract       Extract ideas, decisions and learnings with the configured
+                  LLM; decisions and learnings become proposals
+
+Examples:
+  palace conversation import --from claude-code auto
+  palace conversation import --from codex ~/.codex/sessions/2025/06/01
+  palace conversation import --from cursor auto --extract
 `)
 	case "brief", "status":
 		fmt.Print(`palace status - Show workspace status, index stats, and active agents
@@ -436,7 +697,7 @@ Generated: .palace/index/*, .palace/outputs/*
 	case "all":
 		fmt.Println(ExplainAll())
 	default:
-		return fmt.Errorf("unknown help topic: %s\n\nAvailable topics: explore, store, recall, status, init, index, scan, check, serve, lsp, session, proposals, corridor, dashboard, clean, mcp-config, artifacts", topic)
+		return fmt.Errorf("unknown help topic: %s\n\nAvailable topics: explore, store, recall, status, init, index, history, adr, deadcode, patterns, contracts, scan, check, serve, lsp, session, handoff, playbook, conversation, proposals, corridor, dashboard, clean, mcp-config, artifacts", topic)
 	}
 	return nil
 }
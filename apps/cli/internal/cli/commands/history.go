@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/history"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/llm"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "history",
+		Description: "Mine git history for decisions and learnings",
+		Run:         RunHistory,
+	})
+}
+
+// RunHistory dispatches to the appropriate history subcommand.
+func RunHistory(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace history <command>
+
+Commands:
+  mine      Propose decisions, learnings and postmortems from commit messages
+
+Examples:
+  palace history mine
+  palace history mine --since 2024-01-01 --dry-run
+  palace history mine --llm`)
+	}
+
+	switch args[0] {
+	case "mine":
+		return RunHistoryMine(args[1:])
+	default:
+		return fmt.Errorf("unknown history command: %s\nRun 'palace help history' for usage", args[0])
+	}
+}
+
+// HistoryMineOptions contains the configuration for history mine.
+type HistoryMineOptions struct {
+	Root          string
+	Since         string
+	Limit         int
+	MinConfidence float64
+	UseLLM        bool // review heuristic candidates with the configured LLM
+	DryRun        bool
+}
+
+// RunHistoryMine executes the history mine subcommand.
+func RunHistoryMine(args []string) error {
+	fs := flag.NewFlagSet("history mine", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	since := fs.String("since", "", "only commits after this date (e.g. 2024-01-01, \"6 months ago\")")
+	limit := flags.AddLimitFlag(fs, history.DefaultLimit)
+	minConfidence := fs.Float64("min-confidence", history.DefaultMinConfidence, "classification confidence needed to propose (0-1)")
+	useLLM := fs.Bool("llm", false, "review candidates with the configured LLM")
+	dryRun := fs.Bool("dry-run", false, "show candidates without storing anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := flags.ValidateLimit(*limit); err != nil {
+		return err
+	}
+	if *minConfidence <= 0 || *minConfidence > 1 {
+		return fmt.Errorf("invalid --min-confidence %v: must be between 0 and 1", *minConfidence)
+	}
+
+	return ExecuteHistoryMine(HistoryMineOptions{
+		Root:          *root,
+		Since:         *since,
+		Limit:         *limit,
+		MinConfidence: *minConfidence,
+		UseLLM:        *useLLM,
+		DryRun:        *dryRun,
+	})
+}
+
+// ExecuteHistoryMine mines git history into proposals and file intelligence.
+func ExecuteHistoryMine(opts HistoryMineOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+
+	var client llm.Client
+	if opts.UseLLM {
+		if client, err = newLLMClient(rootPath); err != nil {
+			return fmt.Errorf("--llm needs an LLM (set llmBackend in palace.jsonc): %w", err)
+		}
+	}
+
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return fmt.Errorf("open memory: %w", err)
+	}
+	defer mem.Close()
+
+	result, err := history.Mine(rootPath, mem, history.Options{
+		Since:         opts.Since,
+		Limit:         opts.Limit,
+		MinConfidence: opts.MinConfidence,
+		LLM:           client,
+		DryRun:        opts.DryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("mine history: %w", err)
+	}
+
+	title := "Mining git history"
+	if opts.DryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("\n⛏️  %s\n", title)
+	fmt.Println(strings.Repeat("─", 60))
+
+	for i := range result.Candidates {
+		c := &result.Candidates[i]
+		fmt.Printf("%s %s %s (%.0f%%)\n", historyKindIcon(c.Kind), c.Commit.SHA[:8], c.Kind, c.Confidence*100)
+		fmt.Printf("   %s\n", util.TruncateLine(c.Content, 56))
+	}
+	if len(result.Candidates) > 0 {
+		fmt.Println(strings.Repeat("─", 60))
+	}
+
+	fmt.Printf("Examined %d commit(s), skipped %d mined earlier\n", result.Examined, result.Skipped)
+	fmt.Printf("Fix commits: %d, reverts: %d, files seeded: %d\n", result.Fixes, result.Reverts, result.Files)
+	if result.Unreviewed > 0 {
+		fmt.Printf("LLM review failed for %d commit(s); they will be mined again next run\n", result.Unreviewed)
+	}
+	if opts.DryRun {
+		fmt.Printf("Found %d candidate(s); nothing was stored\n", len(result.Candidates))
+		return nil
+	}
+	fmt.Printf("Proposed %d, skipped %d duplicate(s)\n", len(result.Proposals), result.Duplicates)
+	if len(result.Proposals) > 0 {
+		fmt.Println("\nReview them with 'palace proposals --type decision|learning|postmortem'")
+	}
+	return nil
+}
+
+func historyKindIcon(kind string) string {
+	switch kind {
+	case memory.ProposedAsDecision:
+		return "📋"
+	case memory.ProposedAsLearning:
+		return "💡"
+	default:
+		return "🔥"
+	}
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestRunHistoryNoArgs(t *testing.T) {
+	if err := RunHistory([]string{}); err == nil {
+		t.Error("expected error for missing subcommand")
+	}
+	if err := RunHistory([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
+
+func TestRunHistoryMineInvalidConfidence(t *testing.T) {
+	if err := RunHistoryMine([]string{"--min-confidence", "2"}); err == nil {
+		t.Error("expected error for confidence above 1")
+	}
+}
+
+func TestExecuteInitMineHistory(t *testing.T) {
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", root}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "Dev")
+	if err := os.WriteFile(filepath.Join(root, "store.go"), []byte("package store\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "Switch from BoltDB to SQLite for the store")
+
+	if err := ExecuteInit(InitOptions{Root: root, NoHooks: true, NoVSCode: true, MineHistory: true}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	proposals, _ := mem.GetProposals(memory.ProposalStatusPending, memory.ProposedAsDecision, 10)
+	mem.Close()
+	if len(proposals) != 1 {
+		t.Fatalf("got %d decision proposals, want 1", len(proposals))
+	}
+
+	// A second run finds nothing new.
+	if err := ExecuteHistoryMine(HistoryMineOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteHistoryMine() error: %v", err)
+	}
+	mem, _ = memory.Open(root)
+	defer mem.Close()
+	if proposals, _ := mem.GetProposals("", "", 10); len(proposals) != 1 {
+		t.Errorf("got %d proposals after mining again, want 1", len(proposals))
+	}
+}
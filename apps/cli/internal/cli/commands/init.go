@@ -34,6 +34,7 @@ type InitOptions struct {
 	NoGitignore bool   // Skip .gitignore updates
 	NoHooks     bool   // Skip git hooks installation
 	NoVSCode    bool   // Skip VS Code integration
+	MineHistory bool   // Seed memory from git history
 }
 
 // DetectedAgent represents an auto-detected AI tool in the environment
@@ -56,6 +57,7 @@ func RunInit(args []string) error {
 	noGitignore := fs.Bool("no-gitignore", false, "skip .gitignore updates")
 	noHooks := fs.Bool("no-hooks", false, "skip git hooks installation")
 	noVSCode := fs.Bool("no-vscode", false, "skip VS Code integration (extensions.json)")
+	mineHistory := fs.Bool("mine-history", false, "propose decisions and learnings from git history (see 'palace history mine')")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -70,6 +72,7 @@ func RunInit(args []string) error {
 		NoGitignore: *noGitignore,
 		NoHooks:     *noHooks,
 		NoVSCode:    *noVSCode,
+		MineHistory: *mineHistory,
 	}
 
 	return ExecuteInit(opts)
@@ -210,6 +213,13 @@ func ExecuteInit(opts InitOptions) error {
 		}
 	}
 
+	// Mine git history if requested
+	if opts.MineHistory {
+		if err := ExecuteHistoryMine(HistoryMineOptions{Root: rootPath}); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not mine git history: %v\n", err)
+		}
+	}
+
 	// Auto-scan unless explicitly disabled
 	if !opts.NoScan {
 		fmt.Printf("\nbuilding code index...\n")
@@ -320,6 +330,7 @@ func updateGitignore(root string, _ bool) error {
 		".palace/outputs/",
 		".palace/cache/",
 		".palace/sessions/",
+		".palace/vectors/",
 	}
 
 	// Check if .gitignore exists
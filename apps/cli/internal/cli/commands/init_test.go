@@ -118,6 +118,7 @@ func TestUpdateGitignore(t *testing.T) {
 			".palace/outputs/",
 			".palace/cache/",
 			".palace/sessions/",
+			".palace/vectors/",
 		}
 
 		for _, entry := range expected {
@@ -11,6 +11,7 @@ import (
 	"syscall"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/lsp"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
@@ -80,10 +81,21 @@ func ExecuteLSP(opts LSPOptions) error {
 	server := lsp.NewServerWithIO(os.Stdin, os.Stdout)
 	server.SetLogger(logWriter)
 
-	// Set up diagnostics provider if we have memory
-	if mem != nil {
-		adapter := lsp.NewButlerAdapter(mem)
-		server.SetDiagnosticsProvider(adapter)
+	// Set up the diagnostics provider; without memory it only reports
+	// architecture violations from the index
+	adapter := lsp.NewButlerAdapter(mem)
+	server.SetDiagnosticsProvider(adapter)
+
+	// Open the code index for references, workspace symbols and call hierarchy
+	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	if _, statErr := os.Stat(dbPath); statErr != nil {
+		_, _ = fmt.Fprintf(logWriter, "Index not found at %s; run 'palace scan' to enable navigation\n", dbPath)
+	} else if db, err := index.Open(dbPath); err != nil {
+		_, _ = fmt.Fprintf(logWriter, "Index not available: %v; navigation disabled\n", err)
+	} else {
+		defer db.Close()
+		server.SetNavigationProvider(lsp.NewIndexAdapter(db, rootPath))
+		adapter.SetIndex(db, rootPath)
 	}
 
 	// Set up context with cancellation on signals
@@ -11,6 +11,7 @@ import (
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/patterns"
 
@@ -39,6 +40,7 @@ type PatternsOptions struct {
 	Bulk          bool
 	DryRun        bool
 	WithLearning  bool
+	Format        string // list output: text, json, sarif or junit
 }
 
 // RunPatterns executes the patterns command.
@@ -53,6 +55,8 @@ func RunPatterns(args []string) error {
 	switch subcommand {
 	case "scan":
 		return RunPatternsScan(subArgs)
+	case "lint":
+		return RunPatternsLint(subArgs)
 	case "list", "ls":
 		return RunPatternsList(subArgs)
 	case "approve":
@@ -73,6 +77,7 @@ Manage detected code patterns.
 
 Subcommands:
   scan      Scan codebase for patterns
+  lint      Check the custom detectors in .palace/patterns
   list      List detected patterns
   approve   Approve a pattern for enforcement
   ignore    Ignore a pattern
@@ -80,8 +85,10 @@ Subcommands:
 
 Examples:
   palace patterns scan
+  palace patterns lint
   palace patterns list --status discovered
   palace patterns list --min-confidence 0.85
+  palace patterns list --status approved --format sarif
   palace patterns approve pat_abc123
   palace patterns approve --bulk --min-confidence 0.95
   palace patterns ignore pat_xyz789`)
@@ -133,8 +140,12 @@ func ExecutePatternsScan(opts PatternsOptions) error {
 
 	fmt.Printf("Found %d files to analyze\n", len(files))
 
-	// Create engine
-	engine := patterns.NewEngine(patterns.DefaultRegistry, mem, rootPath)
+	// Create engine with the built-in and custom detectors
+	registry, err := patterns.WorkspaceRegistry(rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: custom detectors: %v\n", err)
+	}
+	engine := patterns.NewEngine(registry, mem, rootPath)
 
 	// Configure
 	cfg := patterns.DefaultEngineConfig()
@@ -193,6 +204,33 @@ func ExecutePatternsScan(opts PatternsOptions) error {
 	return nil
 }
 
+// RunPatternsLint checks the custom detectors of the workspace.
+func RunPatternsLint(args []string) error {
+	fs := flag.NewFlagSet("patterns lint", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return ExecutePatternsLint(PatternsOptions{Root: *root})
+}
+
+// ExecutePatternsLint loads the custom detectors the way a scan does and
+// fails with every file the scan would skip.
+func ExecutePatternsLint(opts PatternsOptions) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+
+	registry, err := patterns.WorkspaceRegistry(rootPath)
+	if err != nil {
+		return fmt.Errorf("custom detectors:\n%w", err)
+	}
+	fmt.Printf("%d custom detector(s) OK\n", registry.Count()-patterns.DefaultRegistry.Count())
+	return nil
+}
+
 // RunPatternsList lists detected patterns.
 func RunPatternsList(args []string) error {
 	fs := flag.NewFlagSet("patterns list", flag.ContinueOnError)
@@ -201,6 +239,7 @@ func RunPatternsList(args []string) error {
 	status := fs.String("status", "", "filter by status: discovered, approved, ignored")
 	minConfidence := fs.Float64("min-confidence", 0, "minimum confidence threshold (0-1)")
 	limit := fs.Int("limit", 50, "maximum patterns to show")
+	format := fs.String("format", "text", "output format: text, json, sarif, junit (outliers as findings)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -211,6 +250,7 @@ func RunPatternsList(args []string) error {
 		Status:        *status,
 		MinConfidence: *minConfidence,
 		Limit:         *limit,
+		Format:        *format,
 	})
 }
 
@@ -220,6 +260,10 @@ func ExecutePatternsList(opts PatternsOptions) error {
 	if err != nil {
 		return err
 	}
+	format, err := findings.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
 
 	mem, err := memory.Open(rootPath)
 	if err != nil {
@@ -237,6 +281,10 @@ func ExecutePatternsList(opts PatternsOptions) error {
 		return fmt.Errorf("get patterns: %w", err)
 	}
 
+	if format != findings.FormatText {
+		return writePatternFindings(mem, patternList, format)
+	}
+
 	if len(patternList) == 0 {
 		fmt.Println("No patterns found.")
 		fmt.Println("Run 'palace patterns scan' to detect patterns.")
@@ -308,6 +356,26 @@ func ExecutePatternsList(opts PatternsOptions) error {
 	return nil
 }
 
+// writePatternFindings reports the outliers of the listed patterns. Each
+// pattern's detector is a check, so conforming patterns show up as passing
+// JUnit test cases.
+func writePatternFindings(mem *memory.Memory, patternList []memory.Pattern, format findings.Format) error {
+	report := findings.Report{Tool: "palace patterns"}
+	for i := range patternList {
+		outliers, err := mem.GetPatternOutliers(patternList[i].ID)
+		if err != nil {
+			return err
+		}
+		p := patterns.FromMemory(&patternList[i], outliers)
+		if p.Status != patterns.StatusIgnored {
+			report.Checks = append(report.Checks, p.DetectorID)
+		}
+		report.Findings = append(report.Findings, patterns.OutlierFindings(&p)...)
+	}
+	findings.Sort(report.Findings)
+	return report.Write(os.Stdout, format, BuildVersion)
+}
+
 // RunPatternsApprove approves a pattern.
 func RunPatternsApprove(args []string) error {
 	fs := flag.NewFlagSet("patterns approve", flag.ContinueOnError)
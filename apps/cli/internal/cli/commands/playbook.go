@@ -0,0 +1,557 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/util"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "playbook",
+		Description: "Run playbooks and inspect playbook runs",
+		Run:         RunPlaybook,
+	})
+}
+
+// RunPlaybook dispatches to the appropriate playbook subcommand.
+func RunPlaybook(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace playbook <command>
+
+Commands:
+  list      List playbooks and recent runs
+  start     Start a playbook run
+  status    Show a run's progress
+  advance   Complete the current step
+  evidence  Record evidence on a run
+  verify    Run verification checks
+
+Examples:
+  palace playbook list
+  palace playbook start bug-fix
+  palace playbook advance
+  palace playbook evidence root-cause "nil map in cache warmup"
+  palace playbook verify exec_1736950000000000000`)
+	}
+
+	switch args[0] {
+	case "list":
+		return RunPlaybookList(args[1:])
+	case "start":
+		return RunPlaybookStart(args[1:])
+	case "status":
+		return RunPlaybookStatus(args[1:])
+	case "advance":
+		return RunPlaybookAdvance(args[1:])
+	case "evidence":
+		return RunPlaybookEvidence(args[1:])
+	case "verify":
+		return RunPlaybookVerify(args[1:])
+	default:
+		return fmt.Errorf("unknown playbook command: %s\nRun 'palace help playbook' for usage", args[0])
+	}
+}
+
+// PlaybookListOptions contains the configuration for playbook list.
+type PlaybookListOptions struct {
+	Root    string
+	Status  string // "all" lists every status
+	Session string
+	Limit   int
+}
+
+// RunPlaybookList executes the playbook list subcommand.
+func RunPlaybookList(args []string) error {
+	fs := flag.NewFlagSet("playbook list", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	status := fs.String("status", "all", "filter runs by status (running, completed, all)")
+	session := fs.String("session", "", "only runs owned by this session")
+	limit := flags.AddLimitFlag(fs, 20)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := flags.ValidateLimit(*limit); err != nil {
+		return err
+	}
+
+	return ExecutePlaybookList(PlaybookListOptions{
+		Root:    *root,
+		Status:  *status,
+		Session: *session,
+		Limit:   *limit,
+	})
+}
+
+// ExecutePlaybookList lists the available playbooks and stored runs.
+func ExecutePlaybookList(opts PlaybookListOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	playbooks, err := pc.executor.ListPlaybooks()
+	if err != nil {
+		return fmt.Errorf("list playbooks: %w", err)
+	}
+	fmt.Printf("\n📘 Playbooks\n")
+	fmt.Println(strings.Repeat("─", 60))
+	if len(playbooks) == 0 {
+		fmt.Println("No playbooks found. Create playbooks in .palace/playbooks/.")
+	}
+	for _, pb := range playbooks {
+		fmt.Printf("%-20s %s\n", pb.Name, util.TruncateLine(pb.Summary, 40))
+	}
+
+	status := opts.Status
+	if status == "all" {
+		status = ""
+	}
+	runs, err := pc.store.List(playbook.RunFilter{SessionID: opts.Session, Status: status, Limit: opts.Limit})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n▶️  Runs\n")
+	fmt.Println(strings.Repeat("─", 60))
+	if len(runs) == 0 {
+		fmt.Println("No playbook runs found.")
+		return nil
+	}
+	for _, run := range runs {
+		fmt.Printf("%s %s [%s] %s (%.0f%%)\n", playbookRunIcon(run.Status), run.ID, run.PlaybookName,
+			run.Status, pc.executor.GetProgress(run))
+		if run.SessionID != "" {
+			fmt.Printf("   Session: %s\n", run.SessionID)
+		}
+		fmt.Printf("   Updated: %s\n", run.UpdatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// PlaybookStartOptions contains the configuration for playbook start.
+type PlaybookStartOptions struct {
+	Root    string
+	Name    string
+	Session string
+}
+
+// RunPlaybookStart executes the playbook start subcommand.
+func RunPlaybookStart(args []string) error {
+	fs := flag.NewFlagSet("playbook start", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	session := fs.String("session", "", "session that owns the run (default: cli)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	remaining := fs.Args()
+	if len(remaining) == 0 {
+		return errors.New("usage: palace playbook start PLAYBOOK [--session ID]")
+	}
+
+	return ExecutePlaybookStart(PlaybookStartOptions{
+		Root:    *root,
+		Name:    remaining[0],
+		Session: *session,
+	})
+}
+
+// ExecutePlaybookStart starts a playbook run.
+func ExecutePlaybookStart(opts PlaybookStartOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	session := cliSession(opts.Session)
+	active, err := pc.store.ActiveForSession(session)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		return fmt.Errorf("playbook %q is already running as %s; finish it or use another --session", active.PlaybookName, active.ID)
+	}
+
+	state, err := pc.executor.Start(opts.Name)
+	if err != nil {
+		return fmt.Errorf("start playbook: %w", err)
+	}
+	state.SessionID = session
+	if err := pc.store.Save(state); err != nil {
+		return err
+	}
+
+	fmt.Printf("Playbook %s started: %s\n", state.PlaybookName, state.ID)
+	printPlaybookGuidance(pc.executor, state)
+	return nil
+}
+
+// PlaybookRunOptions selects a run for status, advance and verify.
+type PlaybookRunOptions struct {
+	Root    string
+	RunID   string // default: the session's running or most recent run
+	Session string
+}
+
+// parsePlaybookRunArgs parses the flags and optional RUN_ID shared by the
+// subcommands that act on a single run.
+func parsePlaybookRunArgs(name string, args []string, extra func(*flag.FlagSet)) (PlaybookRunOptions, []string, error) {
+	fs := flag.NewFlagSet("playbook "+name, flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	runID := fs.String("run", "", "run ID (default: the session's running or most recent run)")
+	session := fs.String("session", "", "session whose runs to use (default: cli)")
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return PlaybookRunOptions{}, nil, err
+	}
+	return PlaybookRunOptions{Root: *root, RunID: *runID, Session: *session}, fs.Args(), nil
+}
+
+// RunPlaybookStatus executes the playbook status subcommand.
+func RunPlaybookStatus(args []string) error {
+	opts, remaining, err := parsePlaybookRunArgs("status", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		opts.RunID = remaining[0]
+	}
+	return ExecutePlaybookStatus(opts)
+}
+
+// ExecutePlaybookStatus shows a run's progress, evidence and verification results.
+func ExecutePlaybookStatus(opts PlaybookRunOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	state, err := pc.loadRun(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s Playbook run: %s\n", playbookRunIcon(state.Status), state.ID)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Playbook:   %s\n", state.PlaybookName)
+	fmt.Printf("Status:     %s\n", state.Status)
+	fmt.Printf("Progress:   %.1f%%\n", pc.executor.GetProgress(state))
+	if state.SessionID != "" {
+		fmt.Printf("Session:    %s\n", state.SessionID)
+	}
+	fmt.Printf("Started:    %s\n", state.StartedAt.Format(time.RFC3339))
+	fmt.Printf("Updated:    %s\n", state.UpdatedAt.Format(time.RFC3339))
+
+	fmt.Printf("\nRooms:\n")
+	for i, room := range state.Playbook.Rooms {
+		mark := "[ ]"
+		if i < state.CurrentRoomIdx || state.Status == "completed" {
+			mark = "[x]"
+		} else if i == state.CurrentRoomIdx {
+			mark = "[>]"
+		}
+		fmt.Printf("  %s %s\n", mark, room)
+	}
+
+	if len(state.Playbook.RequiredEvidence) > 0 {
+		fmt.Printf("\nRequired Evidence:\n")
+		for _, req := range state.Playbook.RequiredEvidence {
+			mark := "[ ]"
+			if _, ok := state.Evidence[req.ID]; ok {
+				mark = "[x]"
+			}
+			fmt.Printf("  %s %s: %s\n", mark, req.ID, req.Description)
+		}
+	}
+
+	if len(state.Verification) > 0 {
+		fmt.Printf("\nVerification:\n")
+		printVerificationResults(state.Verification)
+	}
+
+	if state.Status == "running" {
+		printPlaybookGuidance(pc.executor, state)
+	}
+	return nil
+}
+
+// RunPlaybookAdvance executes the playbook advance subcommand.
+func RunPlaybookAdvance(args []string) error {
+	opts, remaining, err := parsePlaybookRunArgs("advance", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		opts.RunID = remaining[0]
+	}
+	return ExecutePlaybookAdvance(opts)
+}
+
+// ExecutePlaybookAdvance completes the current step of a run.
+func ExecutePlaybookAdvance(opts PlaybookRunOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	state, err := pc.loadRun(opts)
+	if err != nil {
+		return err
+	}
+	if state.Status != "running" {
+		return fmt.Errorf("playbook run %s is %s", state.ID, state.Status)
+	}
+
+	if err := pc.executor.AdvanceStep(state); err != nil {
+		return fmt.Errorf("advance: %w", err)
+	}
+	if err := pc.store.Save(state); err != nil {
+		return err
+	}
+
+	if state.Status == "completed" {
+		fmt.Printf("🎉 Playbook %s completed (%d rooms). Run 'palace playbook verify %s' to check it.\n",
+			state.PlaybookName, len(state.CompletedRooms), state.ID)
+		return nil
+	}
+	fmt.Printf("Step completed (%.0f%%)\n", pc.executor.GetProgress(state))
+	printPlaybookGuidance(pc.executor, state)
+	return nil
+}
+
+// PlaybookEvidenceOptions contains the configuration for playbook evidence.
+type PlaybookEvidenceOptions struct {
+	PlaybookRunOptions
+	EvidenceID string
+	Value      string
+}
+
+// RunPlaybookEvidence executes the playbook evidence subcommand.
+func RunPlaybookEvidence(args []string) error {
+	opts, remaining, err := parsePlaybookRunArgs("evidence", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(remaining) == 0 {
+		return errors.New("usage: palace playbook evidence EVIDENCE_ID [VALUE] [--run RUN_ID]")
+	}
+
+	value := "collected"
+	if len(remaining) > 1 {
+		value = strings.Join(remaining[1:], " ")
+	}
+	return ExecutePlaybookEvidence(PlaybookEvidenceOptions{
+		PlaybookRunOptions: opts,
+		EvidenceID:         remaining[0],
+		Value:              value,
+	})
+}
+
+// ExecutePlaybookEvidence records evidence on a run.
+func ExecutePlaybookEvidence(opts PlaybookEvidenceOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	state, err := pc.loadRun(opts.PlaybookRunOptions)
+	if err != nil {
+		return err
+	}
+	if err := pc.executor.CollectEvidence(state, opts.EvidenceID, opts.Value); err != nil {
+		return fmt.Errorf("collect evidence: %w", err)
+	}
+	if err := pc.store.Save(state); err != nil {
+		return err
+	}
+
+	fmt.Printf("Evidence %s recorded on %s (%d collected)\n", opts.EvidenceID, state.ID, len(state.Evidence))
+	return nil
+}
+
+// PlaybookVerifyOptions contains the configuration for playbook verify.
+type PlaybookVerifyOptions struct {
+	PlaybookRunOptions
+	Timeout time.Duration
+}
+
+// RunPlaybookVerify executes the playbook verify subcommand.
+func RunPlaybookVerify(args []string) error {
+	var timeout *time.Duration
+	opts, remaining, err := parsePlaybookRunArgs("verify", args, func(fs *flag.FlagSet) {
+		timeout = fs.Duration("timeout", playbook.DefaultVerifyTimeout, "timeout for each verification command")
+	})
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		opts.RunID = remaining[0]
+	}
+	return ExecutePlaybookVerify(PlaybookVerifyOptions{PlaybookRunOptions: opts, Timeout: *timeout})
+}
+
+// ExecutePlaybookVerify runs a run's verification checks, executing the
+// lint.run and tests.run capabilities from the project profile.
+func ExecutePlaybookVerify(opts PlaybookVerifyOptions) error {
+	pc, err := openPlaybookContext(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	state, err := pc.loadRun(opts.PlaybookRunOptions)
+	if err != nil {
+		return err
+	}
+
+	results, err := pc.executor.RunVerification(state, playbook.VerifyOptions{Execute: true, Timeout: opts.Timeout})
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if err := pc.store.Save(state); err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No verification checks defined for this playbook.")
+		return nil
+	}
+	printVerificationResults(results)
+
+	failed := 0
+	for _, r := range results {
+		if r.Status != "failed" {
+			continue
+		}
+		failed++
+		if r.Output != "" {
+			fmt.Printf("\n--- %s output ---\n%s\n", r.Name, strings.TrimRight(r.Output, "\n"))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d verification check(s) failed", failed)
+	}
+	return nil
+}
+
+// playbookContext bundles what the playbook subcommands need.
+type playbookContext struct {
+	mem      *memory.Memory
+	store    *playbook.Store
+	executor *playbook.Executor
+}
+
+func openPlaybookContext(root string) (*playbookContext, error) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("open memory: %w", err)
+	}
+	return &playbookContext{
+		mem:      mem,
+		store:    playbook.NewStore(mem.DB()),
+		executor: playbook.NewExecutor(rootPath, playbook.LoadRooms(rootPath)),
+	}, nil
+}
+
+func (pc *playbookContext) Close() {
+	pc.mem.Close()
+}
+
+// loadRun returns the run named by opts.RunID, or the session's running or
+// most recent run.
+func (pc *playbookContext) loadRun(opts PlaybookRunOptions) (*playbook.ExecutionState, error) {
+	var state *playbook.ExecutionState
+	var err error
+	if opts.RunID != "" {
+		state, err = pc.store.Get(opts.RunID)
+	} else {
+		state, err = pc.store.LatestForSession(cliSession(opts.Session))
+		if err == nil && state == nil {
+			err = errors.New("no playbook runs; start one with 'palace playbook start'")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	pc.executor.Resume(state)
+	return state, nil
+}
+
+// cliSession returns the session that owns CLI runs when none was given.
+func cliSession(session string) string {
+	if session == "" {
+		return playbook.CLISession
+	}
+	return session
+}
+
+func printPlaybookGuidance(executor *playbook.Executor, state *playbook.ExecutionState) {
+	guidance, err := executor.GetCurrentGuidance(state)
+	if err != nil {
+		return
+	}
+	fmt.Printf("\nRoom %s, step %d/%d: %s\n", guidance.RoomName, guidance.StepNumber, guidance.TotalSteps, guidance.StepName)
+	if guidance.StepDescription != "" {
+		fmt.Printf("  %s\n", guidance.StepDescription)
+	}
+	if guidance.EvidenceID != "" {
+		fmt.Printf("  Evidence to collect: %s\n", guidance.EvidenceID)
+	}
+	for _, ep := range guidance.EntryPoints {
+		fmt.Printf("  • %s\n", ep)
+	}
+}
+
+func printVerificationResults(results []playbook.VerificationResult) {
+	for _, r := range results {
+		detail := r.Detail
+		if r.Error != "" {
+			detail = r.Error
+		}
+		fmt.Printf("  %s %-20s %s\n", verificationIcon(r.Status), r.Name, detail)
+	}
+}
+
+func verificationIcon(status string) string {
+	switch status {
+	case "passed":
+		return "✅"
+	case "failed":
+		return "❌"
+	default:
+		return "⏭️"
+	}
+}
+
+func playbookRunIcon(status string) string {
+	switch status {
+	case "running":
+		return "🔄"
+	case "completed":
+		return "✅"
+	case "failed":
+		return "❌"
+	default:
+		return "⏸️"
+	}
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+func TestRunPlaybookNoArgs(t *testing.T) {
+	if err := RunPlaybook([]string{}); err == nil {
+		t.Error("expected error for missing subcommand")
+	}
+}
+
+func TestRunPlaybookUnknownSubcommand(t *testing.T) {
+	if err := RunPlaybook([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown subcommand")
+	}
+}
+
+func TestRunPlaybookMissingArgs(t *testing.T) {
+	for name, run := range map[string]func([]string) error{
+		"start":    RunPlaybookStart,
+		"evidence": RunPlaybookEvidence,
+	} {
+		if err := run([]string{}); err == nil {
+			t.Errorf("%s: expected error for missing argument", name)
+		}
+	}
+}
+
+func TestExecutePlaybookRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verification commands use a POSIX shell")
+	}
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	files := map[string]string{
+		".palace/rooms/core.jsonc":       `{"name":"core","summary":"Core","entryPoints":["main.go"],"steps":[{"name":"Read"}]}`,
+		".palace/playbooks/review.jsonc": `{"name":"review","summary":"Review","rooms":["core"],"verification":[{"name":"tests","expectation":"pass","capability":"tests.run"}]}`,
+		".palace/project-profile.json":   `{"capabilities":{"tests.run":{"command":"echo ok"}}}`,
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(root, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	store := playbook.NewStore(mem.DB())
+
+	// An MCP client without a session owns runs under the empty session ID.
+	mcpRun := &playbook.ExecutionState{ID: "exec_mcp", PlaybookName: "review", Status: "running", StartedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := store.Save(mcpRun); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExecutePlaybookStart(PlaybookStartOptions{Root: root, Name: "review"}); err != nil {
+		t.Fatalf("ExecutePlaybookStart() error: %v", err)
+	}
+	if err := ExecutePlaybookStart(PlaybookStartOptions{Root: root, Name: "review"}); err == nil {
+		t.Error("expected error starting a second run in the same session")
+	}
+	if err := ExecutePlaybookStart(PlaybookStartOptions{Root: root, Name: "review", Session: "sess_other"}); err != nil {
+		t.Fatalf("ExecutePlaybookStart() in another session error: %v", err)
+	}
+
+	run := PlaybookRunOptions{Root: root}
+	if err := ExecutePlaybookEvidence(PlaybookEvidenceOptions{PlaybookRunOptions: run, EvidenceID: "notes", Value: "ok"}); err != nil {
+		t.Fatalf("ExecutePlaybookEvidence() error: %v", err)
+	}
+	if err := ExecutePlaybookAdvance(run); err != nil {
+		t.Fatalf("ExecutePlaybookAdvance() error: %v", err)
+	}
+	if err := ExecutePlaybookAdvance(run); err == nil {
+		t.Error("expected error advancing a completed run")
+	}
+	if err := ExecutePlaybookVerify(PlaybookVerifyOptions{PlaybookRunOptions: run}); err != nil {
+		t.Fatalf("ExecutePlaybookVerify() error: %v", err)
+	}
+	if err := ExecutePlaybookStatus(run); err != nil {
+		t.Fatalf("ExecutePlaybookStatus() error: %v", err)
+	}
+	if err := ExecutePlaybookList(PlaybookListOptions{Root: root, Status: "all", Limit: 10}); err != nil {
+		t.Fatalf("ExecutePlaybookList() error: %v", err)
+	}
+
+	state, err := store.LatestForSession(playbook.CLISession)
+	if err != nil || state == nil {
+		t.Fatalf("LatestForSession() = %v, %v", state, err)
+	}
+	if state.Status != "completed" || state.Evidence["notes"] != "ok" {
+		t.Errorf("stored run = %+v", state)
+	}
+	if len(state.Verification) != 1 || state.Verification[0].Status != "passed" {
+		t.Errorf("verification = %+v, want tests passed", state.Verification)
+	}
+	if mcp, err := store.Get("exec_mcp"); err != nil || mcp.Status != "running" || len(mcp.Evidence) != 0 {
+		t.Errorf("sessionless MCP run = %+v, %v; want it untouched by CLI commands", mcp, err)
+	}
+}
@@ -51,7 +51,7 @@ func runProposalsList(args []string) error {
 	fs := flag.NewFlagSet("proposals", flag.ContinueOnError)
 	root := flags.AddRootFlag(fs)
 	status := fs.String("status", "pending", "filter by status: pending, approved, rejected, expired, all")
-	proposedAs := fs.String("type", "", "filter by type: decision, learning")
+	proposedAs := fs.String("type", "", "filter by type: decision, learning, postmortem")
 	limit := fs.Int("limit", 20, "maximum number of proposals to show")
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -118,8 +118,11 @@ func ExecuteProposals(opts ProposalsOptions) error {
 		}
 
 		typeIcon := "D"
-		if p.ProposedAs == memory.ProposedAsLearning {
+		switch p.ProposedAs {
+		case memory.ProposedAsLearning:
 			typeIcon = "L"
+		case memory.ProposedAsPostmortem:
+			typeIcon = "P"
 		}
 
 		fmt.Printf("\n[%s] %s %s\n", statusIcon, typeIcon, p.ID)
@@ -3,8 +3,10 @@ package commands
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -13,10 +15,13 @@ import (
 	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/architecture"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/butler"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/logger"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/ownership"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/scan"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/watch"
 )
@@ -40,6 +45,8 @@ type ScanOptions struct {
 	Workers     int           // Number of parallel workers (0 = auto-detect)
 	Watch       bool          // Enable watch mode for continuous indexing
 	Debounce    time.Duration // Debounce delay for watch mode
+	NoOwnership bool          // Skip the blame/CODEOWNERS ownership pass
+	NoEmbed     bool          // Skip embedding code chunks and symbol docs
 }
 
 // RunScan executes the scan command with parsed arguments.
@@ -55,6 +62,8 @@ func RunScan(args []string) error {
 	fs.IntVar(workers, "j", 0, "parallel workers (shorthand for --workers)")
 	watch := fs.Bool("watch", false, "watch for file changes and auto-rescan")
 	debounce := fs.Duration("debounce", 500*time.Millisecond, "debounce delay for watch mode")
+	noOwnership := fs.Bool("no-ownership", false, "skip the git blame and CODEOWNERS ownership pass")
+	noEmbed := fs.Bool("no-embed", false, "skip embedding code for semantic search")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -69,6 +78,8 @@ func RunScan(args []string) error {
 		Workers:     *workers,
 		Watch:       *watch,
 		Debounce:    *debounce,
+		NoOwnership: *noOwnership,
+		NoEmbed:     *noEmbed,
 	})
 }
 
@@ -102,6 +113,14 @@ func ExecuteScan(opts ScanOptions) error {
 		return err
 	}
 
+	if !opts.NoEmbed {
+		embedCodeIndex(opts.Root)
+	}
+	if !opts.NoOwnership {
+		analyzeOwnership(opts.Root, os.Stdout)
+	}
+	analyzeArchitecture(opts.Root, os.Stdout)
+
 	// Auto-detect Dart/Flutter projects and run deep analysis
 	// unless explicitly disabled with --deep=false
 	rootPath, _ := filepath.Abs(opts.Root)
@@ -132,6 +151,14 @@ func executeWatchMode(opts ScanOptions) error {
 		}
 	}
 
+	if !opts.NoEmbed {
+		embedCodeIndex(opts.Root)
+	}
+	if !opts.NoOwnership {
+		analyzeOwnership(opts.Root, os.Stdout)
+	}
+	analyzeArchitecture(opts.Root, os.Stdout)
+
 	// Load guardrails for watch filtering
 	guardrails := config.LoadGuardrails(rootPath)
 
@@ -161,6 +188,13 @@ func executeWatchMode(opts ScanOptions) error {
 			fmt.Fprintf(os.Stderr, "\nrescan error: %v\n", err)
 			return nil // Don't stop watching on scan errors
 		}
+		if !opts.NoEmbed {
+			embedCodeIndex(opts.Root)
+		}
+		if !opts.NoOwnership {
+			analyzeOwnership(opts.Root, io.Discard)
+		}
+		analyzeArchitecture(opts.Root, io.Discard)
 
 		if !opts.Verbose {
 			fmt.Printf("\r[%s] Scan #%d: complete                    \n",
@@ -195,6 +229,135 @@ func executeWatchMode(opts ScanOptions) error {
 	return watcher.Start(ctx)
 }
 
+// embedCodeIndex embeds new and changed code chunks and symbol doc comments
+// when an embedding backend is configured. Only the embedder is built; the
+// memory embedding pipeline is not started. Failures are reported but do not
+// fail the scan; full-text search keeps working without embeddings.
+func embedCodeIndex(root string) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return
+	}
+	cfg, err := config.LoadPalaceConfig(rootPath)
+	if err != nil || cfg.EmbeddingBackend == "" || cfg.EmbeddingBackend == "disabled" {
+		return
+	}
+
+	db, err := index.Open(filepath.Join(rootPath, ".palace", "index", "palace.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "code embeddings skipped: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	embedder := butler.NewEmbedder(db, rootPath, cfg)
+	if embedder == nil {
+		fmt.Fprintf(os.Stderr, "code embeddings skipped: embedding backend %q unavailable\n", cfg.EmbeddingBackend)
+		return
+	}
+
+	embedCode(db, embedder, os.Stdout)
+}
+
+// embedCode brings the code embeddings in db up to date and reports the
+// outcome to out; errors always go to stderr.
+func embedCode(db *sql.DB, embedder index.Embedder, out io.Writer) {
+	summary, err := index.EmbedCode(db, embedder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "code embeddings incomplete (%d embedded): %v\n", summary.Embedded, err)
+		return
+	}
+	if summary.Embedded > 0 || summary.Removed > 0 {
+		fmt.Fprintf(out, "code embeddings (%s): %d embedded, %d unchanged, %d removed\n",
+			summary.Model, summary.Embedded, summary.Reused, summary.Removed)
+	}
+}
+
+// analyzeOwnership refreshes blame-based ownership and CODEOWNERS entries
+// in the index and reports the outcome to out. Workspaces outside git are
+// skipped silently; other failures are reported but do not fail the scan.
+func analyzeOwnership(root string, out io.Writer) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return
+	}
+	db, err := index.Open(filepath.Join(rootPath, ".palace", "index", "palace.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ownership skipped: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	summary, err := ownership.Analyze(rootPath, db, ownership.Options{})
+	if errors.Is(err, ownership.ErrNotGitRepo) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ownership skipped: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "ownership: %d files (%d blamed, %d unchanged), %d symbols", summary.Files, summary.Blamed, summary.Reused, summary.Symbols)
+	if summary.Codeowners != "" {
+		fmt.Fprintf(out, ", owners from %s", summary.Codeowners)
+	}
+	fmt.Fprintln(out)
+}
+
+// maxArchitectureChanges caps the new and resolved violations a scan lists.
+const maxArchitectureChanges = 10
+
+// analyzeArchitecture evaluates the architecture rules of palace.jsonc
+// against the fresh import graph, stores the violations and reports what
+// changed since the previous scan. Workspaces without rules are skipped
+// silently once earlier violations are resolved; failures are reported
+// but do not fail the scan.
+func analyzeArchitecture(root string, out io.Writer) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return
+	}
+	db, err := index.Open(filepath.Join(rootPath, ".palace", "index", "palace.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "architecture check skipped: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	checker, violations, err := architecture.Check(rootPath, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "architecture check skipped: %v\n", err)
+		return
+	}
+	scan, err := index.LatestScan(db)
+	if err != nil || scan.ID == 0 {
+		return
+	}
+	diff, err := index.ReplaceArchitectureViolations(db, scan.ID, violations)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "architecture check skipped: %v\n", err)
+		return
+	}
+	if checker == nil && len(diff.Resolved) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "architecture: %d violations (%d new, %d resolved since the last scan)\n",
+		diff.Open, len(diff.New), len(diff.Resolved))
+	printArchitectureChanges(out, "+", diff.New)
+	printArchitectureChanges(out, "-", diff.Resolved)
+}
+
+func printArchitectureChanges(out io.Writer, mark string, list []index.ArchitectureViolation) {
+	for i := range list {
+		if i == maxArchitectureChanges {
+			fmt.Fprintf(out, "  %s ... and %d more\n", mark, len(list)-i)
+			return
+		}
+		v := &list[i]
+		fmt.Fprintf(out, "  %s %s:%d %s (imports %s) [%s]\n", mark, v.SourceFile, v.Line, v.Message, v.TargetFile, v.Rule)
+	}
+}
+
 // isDartFlutterProject checks if the workspace is a Dart/Flutter project
 func isDartFlutterProject(rootPath string) bool {
 	// Check for pubspec.yaml at root
@@ -1,11 +1,15 @@
 package commands
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/butler"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/cli/flags"
@@ -20,10 +24,23 @@ func init() {
 	})
 }
 
+// Supported MCP transports.
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// DefaultServeAddr is the listen address used by the HTTP transport.
+const DefaultServeAddr = "127.0.0.1:7337"
+
 // ServeOptions contains the configuration for the serve command.
 type ServeOptions struct {
-	Root string
-	Mode string // MCP mode: "agent" or "human"
+	Root      string
+	Mode      string        // MCP mode: "agent" or "human"
+	Transport string        // "stdio" (default) or "http"
+	Addr      string        // Listen address for the HTTP transport
+	Watch     bool          // Re-index changed files and notify clients while serving
+	Debounce  time.Duration // Debounce delay for watch mode
 }
 
 // RunServe executes the serve command with parsed arguments.
@@ -31,11 +48,22 @@ func RunServe(args []string) error {
 	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
 	root := flags.AddRootFlag(fs)
 	mode := fs.String("mode", "agent", "MCP mode: 'agent' (restricted, default) or 'human' (full access)")
+	transport := fs.String("transport", TransportStdio, "MCP transport: 'stdio' (default) or 'http' (Streamable HTTP)")
+	addr := fs.String("addr", DefaultServeAddr, "listen address for the http transport")
+	watch := fs.Bool("watch", false, "watch for file changes, keep the index current and notify clients")
+	debounce := fs.Duration("debounce", 500*time.Millisecond, "debounce delay for watch mode")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	return ExecuteServe(ServeOptions{Root: *root, Mode: *mode})
+	return ExecuteServe(ServeOptions{
+		Root:      *root,
+		Mode:      *mode,
+		Transport: *transport,
+		Addr:      *addr,
+		Watch:     *watch,
+		Debounce:  *debounce,
+	})
 }
 
 // ExecuteServe starts the MCP server with the given options.
@@ -51,6 +79,18 @@ func ExecuteServe(opts ServeOptions) error {
 	}
 	mcpMode := butler.MCPMode(opts.Mode)
 
+	transport := opts.Transport
+	if transport == "" {
+		transport = TransportStdio
+	}
+	if transport != TransportStdio && transport != TransportHTTP {
+		return fmt.Errorf("invalid transport %q; must be 'stdio' or 'http'", opts.Transport)
+	}
+	addr := opts.Addr
+	if addr == "" {
+		addr = DefaultServeAddr
+	}
+
 	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
@@ -66,13 +106,15 @@ func ExecuteServe(opts ServeOptions) error {
 		return fmt.Errorf("initialize butler: %w", err)
 	}
 
-	server := butler.NewMCPServerWithMode(b, mcpMode)
-
 	modeDesc := "agent (restricted)"
 	if mcpMode == butler.MCPModeHuman {
 		modeDesc = "human (full access)"
 	}
-	fmt.Fprintf(os.Stderr, "Mind Palace MCP server started in %s mode. Reading JSON-RPC from stdin...\n", modeDesc)
+	if transport == TransportHTTP {
+		fmt.Fprintf(os.Stderr, "Mind Palace MCP server started in %s mode. Listening on http://%s%s\n", modeDesc, addr, butler.DefaultMCPHTTPPath)
+	} else {
+		fmt.Fprintf(os.Stderr, "Mind Palace MCP server started in %s mode. Reading JSON-RPC from stdin...\n", modeDesc)
+	}
 	if mcpMode == butler.MCPModeHuman {
 		fmt.Fprintln(os.Stderr, "WARNING: Human mode enables direct-write and governance bypass tools.")
 	}
@@ -88,5 +130,25 @@ func ExecuteServe(opts ServeOptions) error {
 		}
 	}
 
-	return server.Serve()
+	if opts.Watch {
+		watcher, err := startServeWatcher(rootPath, db, b, opts.Debounce)
+		if err != nil {
+			return err
+		}
+		defer watcher.Stop()
+	}
+
+	if transport == TransportHTTP {
+		return serveHTTP(b, mcpMode, addr)
+	}
+	return butler.NewMCPServerWithMode(b, mcpMode).Serve()
+}
+
+// serveHTTP runs the Streamable HTTP transport until interrupted.
+func serveHTTP(b *butler.Butler, mode butler.MCPMode, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := butler.NewMCPHTTPServer(b, mode)
+	return server.ListenAndServe(ctx, addr)
 }
@@ -1,7 +1,15 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/butler"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/watch"
 )
 
 func TestRunServeInvalidFlag(t *testing.T) {
@@ -26,5 +34,185 @@ func TestExecuteServeMissingIndex(t *testing.T) {
 	}
 }
 
+func TestExecuteServeInvalidTransport(t *testing.T) {
+	root := t.TempDir()
+
+	err := ExecuteServe(ServeOptions{Root: root, Mode: "agent", Transport: "websocket"})
+	if err == nil {
+		t.Error("expected error for invalid transport")
+	}
+}
+
+func TestServeWatcherReindexesChangedFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\nfunc main() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "old.go"), []byte("package main\nfunc Old() {}\n"), 0o644)
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+
+	db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	b, err := butler.New(db, root)
+	if err != nil {
+		t.Fatalf("butler.New() error: %v", err)
+	}
+	defer b.Close()
+
+	w := &serveWatcher{root: root, db: db, butler: b, guardrails: config.LoadGuardrails(root)}
+
+	os.WriteFile(filepath.Join(root, "util.go"), []byte("package main\nfunc Helper() {}\n"), 0o644)
+	os.Remove(filepath.Join(root, "old.go"))
+	err = w.handleChanges([]watch.FileChange{
+		{Path: "util.go", Action: "create"},
+		{Path: "old.go", Action: "delete"},
+		{Path: "main.go", Action: "modify"}, // touched but unchanged
+	})
+	if err != nil {
+		t.Fatalf("handleChanges() error: %v", err)
+	}
+
+	count := func(name string) int {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM symbols WHERE name = ?", name).Scan(&n); err != nil {
+			t.Fatalf("count symbols: %v", err)
+		}
+		return n
+	}
+	if count("Helper") != 1 || count("Old") != 0 || count("main") != 1 {
+		t.Errorf("symbols after change: Helper=%d Old=%d main=%d", count("Helper"), count("Old"), count("main"))
+	}
+}
+
+func TestServeWatcherRemovesContractsForDeletedEndpoints(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	server := "const app = require('express')();\napp.get('/api/users', listUsers);\napp.get('/api/orders', listOrders);\n"
+	os.WriteFile(filepath.Join(root, "server.js"), []byte(server), 0o644)
+	os.WriteFile(filepath.Join(root, "app.ts"), []byte("fetch('/api/users');\nfetch('/api/orders');\n"), 0o644)
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+
+	db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	b, err := butler.New(db, root)
+	if err != nil {
+		t.Fatalf("butler.New() error: %v", err)
+	}
+	defer b.Close()
+	w := &serveWatcher{root: root, db: db, butler: b, guardrails: config.LoadGuardrails(root)}
+
+	endpoints := func() map[string]bool {
+		list, err := contracts.NewStore(b.Memory().DB()).ListContracts(contracts.ContractFilter{})
+		if err != nil {
+			t.Fatalf("ListContracts() error: %v", err)
+		}
+		out := map[string]bool{}
+		for _, c := range list {
+			out[c.Endpoint] = true
+		}
+		return out
+	}
+
+	w.analyzeContracts([]string{"server.js"})
+	if got := endpoints(); !got["/api/users"] || !got["/api/orders"] {
+		t.Fatalf("contracts before edit = %v", got)
+	}
+
+	os.WriteFile(filepath.Join(root, "server.js"), []byte("const app = require('express')();\napp.get('/api/users', listUsers);\n"), 0o644)
+	if err := w.handleChanges([]watch.FileChange{{Path: "server.js", Action: "modify"}}); err != nil {
+		t.Fatalf("handleChanges() error: %v", err)
+	}
+	if got := endpoints(); !got["/api/users"] || got["/api/orders"] {
+		t.Errorf("contracts after removing /api/orders = %v", got)
+	}
+}
+
+func TestServeWatcherKeepsSpecContracts(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	spec := `openapi: 3.0.3
+paths:
+  /api/users:
+    get:
+      operationId: listUsers
+  /api/users/{id}:
+    delete:
+      operationId: deleteUser
+`
+	os.WriteFile(filepath.Join(root, "openapi.yaml"), []byte(spec), 0o644)
+	os.WriteFile(filepath.Join(root, "server.js"), []byte("const app = require('express')();\napp.get('/api/users', listUsers);\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "app.ts"), []byte("fetch('/api/users');\n"), 0o644)
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+	if err := ExecuteContractsScan(ContractsOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteContractsScan() error: %v", err)
+	}
+
+	db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	b, err := butler.New(db, root)
+	if err != nil {
+		t.Fatalf("butler.New() error: %v", err)
+	}
+	defer b.Close()
+	w := &serveWatcher{root: root, db: db, butler: b, guardrails: config.LoadGuardrails(root)}
+
+	specs := func() map[string]string {
+		list, err := contracts.NewStore(b.Memory().DB()).ListContracts(contracts.ContractFilter{})
+		if err != nil {
+			t.Fatalf("ListContracts() error: %v", err)
+		}
+		out := map[string]string{}
+		for _, c := range list {
+			out[c.Method+" "+c.Endpoint] = ""
+			if c.Spec != nil {
+				out[c.Method+" "+c.Endpoint] = filepath.Base(c.Spec.File)
+			}
+		}
+		return out
+	}
+	if got := specs(); got["GET /api/users"] != "openapi.yaml" || got["DELETE /api/users/{id}"] != "openapi.yaml" {
+		t.Fatalf("contracts after scan = %v", got)
+	}
+
+	// A code edit keeps the spec side of every contract, including the
+	// endpoint only the spec declares.
+	os.WriteFile(filepath.Join(root, "server.js"), []byte("const app = require('express')();\napp.get('/api/users', listUsers);\n// v2\n"), 0o644)
+	if err := w.handleChanges([]watch.FileChange{{Path: "server.js", Action: "modify"}}); err != nil {
+		t.Fatalf("handleChanges() error: %v", err)
+	}
+	if got := specs(); got["GET /api/users"] != "openapi.yaml" || got["DELETE /api/users/{id}"] != "openapi.yaml" {
+		t.Errorf("contracts after code edit = %v", got)
+	}
+
+	// Editing the spec re-runs the analysis.
+	os.WriteFile(filepath.Join(root, "openapi.yaml"), []byte(spec+"  /api/orders:\n    get:\n      operationId: listOrders\n"), 0o644)
+	if err := w.handleChanges([]watch.FileChange{{Path: "openapi.yaml", Action: "modify"}}); err != nil {
+		t.Fatalf("handleChanges() error: %v", err)
+	}
+	if got := specs(); got["GET /api/orders"] != "openapi.yaml" {
+		t.Errorf("contracts after spec edit = %v", got)
+	}
+}
+
 // Note: Full serve test would require mocking stdin/stdout
 // which is complex. For now, we test flag parsing and error cases.
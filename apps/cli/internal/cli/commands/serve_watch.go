@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/butler"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/patterns"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/watch"
+)
+
+// serveWatcher keeps the index, patterns and contracts current while the MCP
+// server runs and notifies connected clients about the files that changed.
+// All output goes to stderr; stdout carries the stdio transport.
+type serveWatcher struct {
+	root       string
+	db         *sql.DB
+	butler     *butler.Butler
+	guardrails config.Guardrails
+	patterns   *patterns.Engine // nil when session memory is unavailable
+
+	mu      sync.Mutex // serializes change batches
+	stopped bool
+	done    chan struct{}
+	cancel  context.CancelFunc
+}
+
+// startServeWatcher starts watching rootPath in the background.
+func startServeWatcher(rootPath string, db *sql.DB, b *butler.Butler, debounce time.Duration) (*serveWatcher, error) {
+	w := &serveWatcher{
+		root:       rootPath,
+		db:         db,
+		butler:     b,
+		guardrails: config.LoadGuardrails(rootPath),
+		done:       make(chan struct{}),
+	}
+	if mem := b.Memory(); mem != nil {
+		registry, err := patterns.WorkspaceRegistry(rootPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: custom detectors: %v\n", err)
+		}
+		w.patterns = patterns.NewEngine(registry, mem, rootPath)
+	}
+
+	cfg := watch.DefaultConfig()
+	if debounce > 0 {
+		cfg.Debounce = debounce
+	}
+	watcher, err := watch.New(rootPath, w.guardrails, w.handleChanges, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go func() {
+		defer close(w.done)
+		if err := watcher.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Watching %s for changes (debounce: %v)\n", rootPath, cfg.Debounce)
+	return w, nil
+}
+
+// Stop stops watching and waits for a batch in progress to finish.
+func (w *serveWatcher) Stop() {
+	w.cancel()
+	<-w.done
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+}
+
+// handleChanges re-indexes a batch of changed files, re-runs pattern and
+// contract analysis on them and notifies clients. Errors are reported but
+// never stop the watcher.
+func (w *serveWatcher) handleChanges(changes []watch.FileChange) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return nil
+	}
+
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	fileChanges, err := index.DetectPathChanges(w.db, w.root, w.guardrails, paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "detect changes: %v\n", err)
+		return nil
+	}
+	if len(fileChanges) == 0 {
+		return nil
+	}
+
+	summary, err := index.IncrementalScan(w.db, w.root, fileChanges)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rescan error: %v\n", err)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "[%s] reindexed: +%d added, ~%d modified, -%d deleted\n",
+		time.Now().Format("15:04:05"), summary.FilesAdded, summary.FilesModified, summary.FilesDeleted)
+
+	if embedder := w.butler.GetEmbedder(); embedder != nil {
+		embedCode(w.db, embedder, os.Stderr)
+	}
+
+	changed := make([]string, len(fileChanges))
+	for i, c := range fileChanges {
+		changed[i] = c.Path
+	}
+	w.analyzePatterns(changed)
+	w.analyzeContracts(changed)
+
+	if sent := w.butler.NotifyFilesChanged(changed); sent > 0 {
+		fmt.Fprintf(os.Stderr, "[%s] sent %d resource update(s)\n", time.Now().Format("15:04:05"), sent)
+	}
+	return nil
+}
+
+// analyzePatterns re-runs pattern detection on the changed files.
+func (w *serveWatcher) analyzePatterns(changed []string) {
+	if w.patterns == nil {
+		return
+	}
+	// Pattern locations are stored with absolute paths, as in 'palace patterns scan'.
+	files := make([]string, len(changed))
+	for i, p := range changed {
+		files[i] = filepath.Join(w.root, filepath.FromSlash(p))
+	}
+
+	ctx := context.Background()
+	result, err := w.patterns.ScanChanged(ctx, files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pattern scan: %v\n", err)
+		return
+	}
+	if err := w.patterns.SaveFileResults(ctx, result.Patterns, files); err != nil {
+		fmt.Fprintf(os.Stderr, "save patterns: %v\n", err)
+	}
+}
+
+// analyzeContracts re-runs contract analysis when a changed file can declare
+// endpoints, make API calls or be an OpenAPI document. Contracts match calls
+// across files, so the whole workspace is analyzed again, spec included, and
+// contracts whose endpoint was removed or renamed are dropped as in
+// 'palace contracts scan'.
+func (w *serveWatcher) analyzeContracts(changed []string) {
+	mem := w.butler.Memory()
+	if mem == nil {
+		return
+	}
+	relevant := false
+	for _, p := range changed {
+		switch filepath.Ext(p) {
+		case ".go", ".py", ".js", ".jsx", ".ts", ".tsx", ".yaml", ".yml", ".json":
+			relevant = true
+		}
+	}
+	if !relevant {
+		return
+	}
+
+	store := contracts.NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		fmt.Fprintf(os.Stderr, "contract analysis: %v\n", err)
+		return
+	}
+	backendFiles, err := collectBackendFiles(w.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "contract analysis: %v\n", err)
+		return
+	}
+	frontendFiles, err := collectFrontendFiles(w.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "contract analysis: %v\n", err)
+		return
+	}
+	specFiles, err := w.specFiles(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "contract analysis: %v\n", err)
+		return
+	}
+	endpoints, _ := extractEndpoints(backendFiles)
+	calls, _ := extractCalls(frontendFiles)
+	specEndpoints, err := extractSpecEndpoints(specFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "contract analysis: %v\n", err)
+		return
+	}
+
+	result := contracts.NewAnalyzer().Analyze(&contracts.AnalysisInput{
+		Endpoints: endpoints,
+		Calls:     calls,
+		Spec:      specEndpoints,
+	})
+	for _, contract := range result.Contracts {
+		if err := store.UpsertContract(contract); err != nil {
+			fmt.Fprintf(os.Stderr, "save contract: %v\n", err)
+			return
+		}
+	}
+	if _, err := store.RemoveMissing(result.Contracts, w.root); err != nil {
+		fmt.Fprintf(os.Stderr, "remove stale contracts: %v\n", err)
+	}
+}
+
+// specFiles returns the OpenAPI documents found by name in the workspace and
+// those stored contracts were read from, which covers documents passed to
+// 'palace contracts scan --spec'. Documents that no longer exist are skipped.
+func (w *serveWatcher) specFiles(store *contracts.Store) ([]string, error) {
+	files, err := collectSpecFiles(w.root)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := store.ListContracts(contracts.ContractFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range stored {
+		if c.Spec == nil || c.Spec.File == "" || slices.Contains(files, c.Spec.File) {
+			continue
+		}
+		if _, err := os.Stat(c.Spec.File); err == nil {
+			files = append(files, c.Spec.File)
+		}
+	}
+	return files, nil
+}
@@ -35,7 +35,7 @@ func TestRunSessionEndInvalidFlag(t *testing.T) {
 }
 
 func TestRunSessionEndMissingID(t *testing.T) {
-	err := RunSessionEnd([]string{})
+	err := RunSessionEnd([]string{"--root", t.TempDir()})
 	if err == nil {
 		t.Error("expected error for missing session ID")
 	}
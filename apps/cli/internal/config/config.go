@@ -68,6 +68,34 @@ func DefaultMonorepoConfig() *MonorepoConfig {
 	}
 }
 
+// ArchitectureConfig declares the intended dependency structure of the
+// workspace. Components in rules are layer names, "room:<name>",
+// "project:<name>" (a monorepo project) or workspace-relative path globs.
+type ArchitectureConfig struct {
+	// Layers name groups of components for use in rules
+	Layers map[string][]string `json:"layers,omitempty"`
+	Rules  []ArchitectureRule  `json:"rules,omitempty"`
+}
+
+// ArchitectureRule is one constraint on imports. A rule sets From with Deny
+// or Allow, To with OnlyFrom, or NoCycles.
+type ArchitectureRule struct {
+	ID   string `json:"id,omitempty"`
+	From string `json:"from,omitempty"`
+	// Deny lists components From must not import
+	Deny []string `json:"deny,omitempty"`
+	// Allow lists the only components From may import besides itself
+	Allow []string `json:"allow,omitempty"`
+	To    string   `json:"to,omitempty"`
+	// OnlyFrom lists the only components that may import To
+	OnlyFrom []string `json:"onlyFrom,omitempty"`
+	// NoCycles bans import cycles between the listed components, or between
+	// the files of a single listed component
+	NoCycles []string `json:"noCycles,omitempty"`
+	Severity string   `json:"severity,omitempty"` // "error" (default) or "warning"
+	Message  string   `json:"message,omitempty"`
+}
+
 type PalaceConfig struct {
 	SchemaVersion string `json:"schemaVersion"`
 	Kind          string `json:"kind"`
@@ -84,8 +112,11 @@ type PalaceConfig struct {
 	Provenance  any                       `json:"provenance"`
 	Dashboard   *DashboardConfig          `json:"dashboard,omitempty"`
 
+	// Architecture rules checked against the import graph
+	Architecture *ArchitectureConfig `json:"architecture,omitempty"`
+
 	// Embedding configuration for semantic search
-	EmbeddingBackend string `json:"embeddingBackend,omitempty"` // "ollama", "openai", or "disabled"
+	EmbeddingBackend string `json:"embeddingBackend,omitempty"` // "ollama", "openai", "local", or "disabled"
 	EmbeddingModel   string `json:"embeddingModel,omitempty"`   // e.g., "nomic-embed-text", "text-embedding-3-small"
 	EmbeddingURL     string `json:"embeddingUrl,omitempty"`     // Base URL for Ollama API
 	EmbeddingAPIKey  string `json:"embeddingApiKey,omitempty"`  // API key for OpenAI
@@ -22,8 +22,13 @@ type AnalysisResult struct {
 	Contracts         []*Contract
 	UnmatchedBackend  []UnmatchedEndpoint
 	UnmatchedFrontend []UnmatchedCall
-	TotalMismatches   int
-	AnalyzedAt        time.Time
+	// Undocumented are backend endpoints the spec does not declare and
+	// Unimplemented are spec endpoints with no backend code. Both stay empty
+	// when no spec was given.
+	Undocumented    []UnmatchedEndpoint
+	Unimplemented   []UnmatchedEndpoint
+	TotalMismatches int
+	AnalyzedAt      time.Time
 }
 
 // UnmatchedEndpoint represents a backend endpoint with no frontend calls.
@@ -47,6 +52,9 @@ type UnmatchedCall struct {
 type AnalysisInput struct {
 	Endpoints []EndpointInput
 	Calls     []CallInput
+	// Spec holds endpoints declared in OpenAPI documents. When set, the spec
+	// is compared with both the backend code and the frontend calls.
+	Spec []EndpointInput
 }
 
 // EndpointInput represents a backend endpoint for analysis.
@@ -85,10 +93,25 @@ func (a *Analyzer) Analyze(input *AnalysisInput) *AnalysisResult {
 
 	// Create endpoint lookup map
 	endpointMap := make(map[string]*EndpointInput)
+	codeSignatures := make(map[string]*EndpointInput)
 	for i := range input.Endpoints {
 		ep := &input.Endpoints[i]
 		key := ep.Method + ":" + NormalizePath(ep.Path)
 		endpointMap[key] = ep
+		codeSignatures[signature(ep.Method, ep.Path)] = ep
+	}
+
+	// Spec endpoints are matched by signature, since the spec and the code
+	// may name path parameters differently. Endpoints only the spec declares
+	// are matchable too, so calls to them are still checked against the spec.
+	specMap := make(map[string]*EndpointInput)
+	for i := range input.Spec {
+		ep := &input.Spec[i]
+		sig := signature(ep.Method, ep.Path)
+		specMap[sig] = ep
+		if codeSignatures[sig] == nil {
+			a.matcher.AddEndpoint(ep.Method, ep.Path)
+		}
 	}
 
 	// Track matched endpoints
@@ -116,30 +139,10 @@ func (a *Analyzer) Analyze(input *AnalysisInput) *AnalysisResult {
 		// Get or create contract
 		contract, exists := contractMap[key]
 		if !exists {
-			ep := endpointMap[key]
-			if ep == nil {
+			contract = newContract(endpointMap[key], specMap[signature(match.Method, match.BackendEndpoint)])
+			if contract == nil {
 				continue
 			}
-
-			contract = &Contract{
-				ID:              GenerateID("ct"),
-				Method:          ep.Method,
-				Endpoint:        ep.Path,
-				EndpointPattern: PathToPattern(ep.Path),
-				Backend: BackendEndpoint{
-					File:           ep.File,
-					Line:           ep.Line,
-					Framework:      ep.Framework,
-					Handler:        ep.Handler,
-					RequestSchema:  ep.RequestSchema,
-					ResponseSchema: ep.ResponseSchema,
-				},
-				FrontendCalls: make([]FrontendCall, 0),
-				Mismatches:    make([]FieldMismatch, 0),
-				Status:        ContractDiscovered,
-				FirstSeen:     time.Now(),
-				LastSeen:      time.Now(),
-			}
 			contractMap[key] = contract
 		}
 
@@ -156,11 +159,21 @@ func (a *Analyzer) Analyze(input *AnalysisInput) *AnalysisResult {
 			mismatches := contract.Backend.ResponseSchema.Compare(call.ExpectedSchema, "")
 			contract.Mismatches = append(contract.Mismatches, mismatches...)
 		}
+		if call.ExpectedSchema != nil && contract.Spec != nil {
+			contract.Mismatches = append(contract.Mismatches, compareScoped(contract.Spec.ResponseSchema,
+				call.ExpectedSchema, "$", ScopeSpecFrontend, "spec", "frontend")...)
+		}
 
 		contract.FrontendCalls = append(contract.FrontendCalls, frontendCall)
 		contract.LastSeen = time.Now()
 	}
 
+	// Every spec endpoint is a contract, with or without frontend calls.
+	// The spec is compared with the backend code wherever both exist.
+	if len(input.Spec) > 0 {
+		a.checkSpec(input, contractMap, codeSignatures, specMap, result)
+	}
+
 	// Find unmatched backend endpoints
 	for _, ep := range input.Endpoints {
 		key := ep.Method + ":" + NormalizePath(ep.Path)
@@ -192,6 +205,82 @@ func (a *Analyzer) Analyze(input *AnalysisInput) *AnalysisResult {
 	return result
 }
 
+// newContract starts a contract for a code endpoint, a spec endpoint, or
+// both. It returns nil when there is neither.
+func newContract(ep, spec *EndpointInput) *Contract {
+	if ep == nil && spec == nil {
+		return nil
+	}
+	contract := &Contract{
+		ID:            GenerateID("ct"),
+		FrontendCalls: make([]FrontendCall, 0),
+		Mismatches:    make([]FieldMismatch, 0),
+		Status:        ContractDiscovered,
+		FirstSeen:     time.Now(),
+		LastSeen:      time.Now(),
+	}
+	source := ep
+	if ep == nil {
+		source = spec
+	} else {
+		contract.Backend = BackendEndpoint{
+			File:           ep.File,
+			Line:           ep.Line,
+			Framework:      ep.Framework,
+			Handler:        ep.Handler,
+			RequestSchema:  ep.RequestSchema,
+			ResponseSchema: ep.ResponseSchema,
+		}
+	}
+	contract.Method = source.Method
+	contract.Endpoint = source.Path
+	contract.EndpointPattern = PathToPattern(source.Path)
+	if spec != nil {
+		contract.Spec = specEndpoint(spec)
+	}
+	return contract
+}
+
+// checkSpec adds a contract for every spec endpoint no frontend call
+// reached, compares spec and backend code, and lists the endpoints only one
+// of them has.
+func (a *Analyzer) checkSpec(input *AnalysisInput, contractMap map[string]*Contract,
+	codeSignatures, specMap map[string]*EndpointInput, result *AnalysisResult) {
+	bySignature := make(map[string]*Contract, len(contractMap))
+	for _, c := range contractMap {
+		bySignature[signature(c.Method, c.Endpoint)] = c
+	}
+
+	for i := range input.Spec {
+		spec := &input.Spec[i]
+		sig := signature(spec.Method, spec.Path)
+		code := codeSignatures[sig]
+		if code == nil {
+			result.Unimplemented = append(result.Unimplemented, UnmatchedEndpoint{
+				Method: spec.Method, Path: spec.Path, File: spec.File, Line: spec.Line, Handler: spec.Handler,
+			})
+		}
+		contract := bySignature[sig]
+		if contract == nil {
+			contract = newContract(code, spec)
+			bySignature[sig] = contract
+			contractMap[spec.Method+":"+NormalizePath(contract.Endpoint)] = contract
+		}
+		if code != nil {
+			contract.Mismatches = append(contract.Mismatches, compareSpecBackend(contract.Spec, &contract.Backend)...)
+		}
+	}
+
+	for i := range input.Endpoints {
+		ep := &input.Endpoints[i]
+		if specMap[signature(ep.Method, ep.Path)] == nil {
+			result.Undocumented = append(result.Undocumented, UnmatchedEndpoint{
+				Method: ep.Method, Path: ep.Path, File: ep.File, Line: ep.Line, Handler: ep.Handler,
+			})
+		}
+	}
+}
+
 func (a *Analyzer) calculateContractConfidence(contract *Contract) float64 {
 	confidence := 0.5 // Base confidence
 
@@ -302,3 +302,69 @@ func TestGetMismatchSeverity(t *testing.T) {
 		}
 	}
 }
+
+func TestAnalyzer_Spec(t *testing.T) {
+	analyzer := NewAnalyzer()
+
+	specUser := NewObjectSchema()
+	specUser.AddProperty("id", NewPrimitiveSchema(SchemaTypeString), true)
+	specUser.AddProperty("email", NewPrimitiveSchema(SchemaTypeString), true)
+
+	backendUser := NewObjectSchema()
+	backendUser.AddProperty("id", NewPrimitiveSchema(SchemaTypeInteger), true)
+	backendUser.AddProperty("email", NewPrimitiveSchema(SchemaTypeString), true)
+
+	frontendUser := NewObjectSchema()
+	frontendUser.AddProperty("id", NewPrimitiveSchema(SchemaTypeString), true)
+	frontendUser.AddProperty("name", NewPrimitiveSchema(SchemaTypeString), true)
+
+	input := &AnalysisInput{
+		Endpoints: []EndpointInput{
+			{Method: "GET", Path: "/api/users/:id", File: "handler.go", Line: 10, ResponseSchema: backendUser},
+			{Method: "GET", Path: "/api/health", File: "handler.go", Line: 30},
+		},
+		Calls: []CallInput{
+			{Method: "GET", URL: "/api/users/42", File: "api.ts", Line: 5, ExpectedSchema: frontendUser},
+		},
+		Spec: []EndpointInput{
+			{Method: "GET", Path: "/api/users/{userId}", File: "openapi.yaml", Line: 8, Handler: "getUser", ResponseSchema: specUser},
+			{Method: "DELETE", Path: "/api/users/{userId}", File: "openapi.yaml", Line: 20, Handler: "deleteUser"},
+		},
+	}
+
+	result := analyzer.Analyze(input)
+
+	if len(result.Contracts) != 2 {
+		t.Fatalf("expected 2 contracts, got %d", len(result.Contracts))
+	}
+	if len(result.Unimplemented) != 1 || result.Unimplemented[0].Method != "DELETE" {
+		t.Errorf("expected DELETE to be unimplemented, got %+v", result.Unimplemented)
+	}
+	if len(result.Undocumented) != 1 || result.Undocumented[0].Path != "/api/health" {
+		t.Errorf("expected /api/health to be undocumented, got %+v", result.Undocumented)
+	}
+
+	var get *Contract
+	for _, c := range result.Contracts {
+		if c.Method == "GET" {
+			get = c
+		}
+	}
+	if get == nil || get.Spec == nil || get.Spec.OperationID != "getUser" {
+		t.Fatalf("expected GET contract with spec, got %+v", get)
+	}
+
+	scopes := make(map[MismatchScope]int)
+	for _, m := range get.Mismatches {
+		scopes[m.Scope]++
+	}
+	if scopes[ScopeSpecBackend] != 1 {
+		t.Errorf("expected 1 spec/backend mismatch (id type), got %d", scopes[ScopeSpecBackend])
+	}
+	if scopes[ScopeSpecFrontend] == 0 {
+		t.Error("expected spec/frontend mismatches for name")
+	}
+	if scopes[ScopeBackendFrontend] == 0 {
+		t.Error("expected backend/frontend mismatches")
+	}
+}
@@ -14,6 +14,7 @@ type Contract struct {
 	EndpointPattern string          `json:"endpoint_pattern"` // Regex for matching: /api/users/[^/]+
 
 	Backend       BackendEndpoint `json:"backend"`
+	Spec          *SpecEndpoint   `json:"spec,omitempty"` // Set when an OpenAPI spec declares the endpoint
 	FrontendCalls []FrontendCall  `json:"frontend_calls"`
 	Mismatches    []FieldMismatch `json:"mismatches"`
 
@@ -37,6 +38,15 @@ type BackendEndpoint struct {
 	ResponseSchema *TypeSchema `json:"response_schema,omitempty"`
 }
 
+// SpecEndpoint represents an API endpoint declared in an OpenAPI document.
+type SpecEndpoint struct {
+	File           string      `json:"file"`
+	Line           int         `json:"line"`
+	OperationID    string      `json:"operation_id,omitempty"`
+	RequestSchema  *TypeSchema `json:"request_schema,omitempty"`
+	ResponseSchema *TypeSchema `json:"response_schema,omitempty"`
+}
+
 // FrontendCall represents an API call from frontend code.
 type FrontendCall struct {
 	ID             string      `json:"id"`
@@ -0,0 +1,342 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// OpenAPIVersion is the OpenAPI version ExportOpenAPI produces.
+const OpenAPIVersion = "3.1.0"
+
+// OpenAPIDocument is an OpenAPI 3.1 document generated from contracts.
+// Schemas are kept as plain maps so they marshal to JSON and YAML alike.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi" yaml:"openapi"`
+	Info       OpenAPIInfo                             `json:"info" yaml:"info"`
+	Tags       []OpenAPITag                            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths" yaml:"paths"`
+	Components OpenAPIComponents                       `json:"components" yaml:"components"`
+}
+
+// OpenAPIInfo is the document's info object.
+type OpenAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// OpenAPITag groups the operations served by one framework.
+type OpenAPITag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// OpenAPIComponents holds the deduplicated schemas.
+type OpenAPIComponents struct {
+	Schemas map[string]map[string]any `json:"schemas" yaml:"schemas"`
+}
+
+// OpenAPIOperation is a single method on a path.
+type OpenAPIOperation struct {
+	Tags        []string                   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	OperationID string                     `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *OpenAPIBody               `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses" yaml:"responses"`
+	Mismatches  []OpenAPIMismatch          `json:"x-palace-mismatch,omitempty" yaml:"x-palace-mismatch,omitempty"`
+}
+
+// OpenAPIParameter is a path parameter.
+type OpenAPIParameter struct {
+	Name     string         `json:"name" yaml:"name"`
+	In       string         `json:"in" yaml:"in"`
+	Required bool           `json:"required" yaml:"required"`
+	Schema   map[string]any `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIBody is a JSON request body.
+type OpenAPIBody struct {
+	Required bool                        `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content" yaml:"content"`
+}
+
+// OpenAPIResponse is a response with an optional JSON body.
+type OpenAPIResponse struct {
+	Description string                      `json:"description" yaml:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// OpenAPIMediaType wraps a schema for one content type.
+type OpenAPIMediaType struct {
+	Schema map[string]any `json:"schema" yaml:"schema"`
+}
+
+// OpenAPIMismatch is an open contract mismatch, exported as the
+// x-palace-mismatch extension of the operation.
+type OpenAPIMismatch struct {
+	ContractID  string           `json:"contractId" yaml:"contractId"`
+	Field       string           `json:"field" yaml:"field"`
+	Type        MismatchType     `json:"type" yaml:"type"`
+	Severity    MismatchSeverity `json:"severity" yaml:"severity"`
+	Scope       MismatchScope    `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+const jsonMediaType = "application/json"
+
+// ExportOpenAPI builds an OpenAPI document from contracts. Operations are
+// tagged with their backend framework and use the backend schemas, or the
+// spec's when there is no backend code. Identical object schemas share one
+// component. Ignored contracts are left out, and mismatches are exported
+// unless the contract was verified.
+func ExportOpenAPI(list []*Contract, info OpenAPIInfo) *OpenAPIDocument {
+	sorted := make([]*Contract, 0, len(list))
+	for _, c := range list {
+		if c.Status != ContractIgnored {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Backend.Framework != b.Backend.Framework {
+			return a.Backend.Framework < b.Backend.Framework
+		}
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		return a.Method < b.Method
+	})
+
+	doc := &OpenAPIDocument{
+		OpenAPI:    OpenAPIVersion,
+		Info:       info,
+		Paths:      make(map[string]map[string]*OpenAPIOperation),
+		Components: OpenAPIComponents{Schemas: make(map[string]map[string]any)},
+	}
+	reg := &schemaRegistry{components: doc.Components.Schemas, byShape: make(map[string]string)}
+	operationIDs := make(map[string]bool)
+	frameworks := make(map[string]bool)
+
+	for _, c := range sorted {
+		path := openAPIPath(c.Endpoint)
+		method := strings.ToLower(c.Method)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		// The first framework to serve a route wins.
+		if doc.Paths[path][method] != nil {
+			continue
+		}
+
+		request, response := c.Backend.RequestSchema, c.Backend.ResponseSchema
+		operationID := c.Backend.Handler
+		if c.Backend.File == "" && c.Spec != nil {
+			request, response = c.Spec.RequestSchema, c.Spec.ResponseSchema
+			operationID = c.Spec.OperationID
+		}
+		name := schemaName(operationID)
+		if name == "" {
+			name = operationName(c.Method, c.Endpoint)
+		}
+		if operationID == "" || operationIDs[operationID] {
+			operationID = lowerFirst(operationName(c.Method, c.Endpoint))
+		}
+		operationIDs[operationID] = true
+
+		op := &OpenAPIOperation{
+			OperationID: operationID,
+			Responses:   map[string]OpenAPIResponse{"200": {Description: "Successful response"}},
+		}
+		if fw := c.Backend.Framework; fw != "" {
+			op.Tags = []string{fw}
+			frameworks[fw] = true
+		}
+		for _, param := range ExtractPathParams(path) {
+			op.Parameters = append(op.Parameters, OpenAPIParameter{
+				Name: param, In: "path", Required: true, Schema: map[string]any{"type": "string"},
+			})
+		}
+		if request != nil {
+			op.RequestBody = &OpenAPIBody{
+				Required: true,
+				Content:  map[string]OpenAPIMediaType{jsonMediaType: {Schema: reg.schema(request, name+"Request", true)}},
+			}
+		}
+		if response != nil {
+			op.Responses["200"] = OpenAPIResponse{
+				Description: "Successful response",
+				Content:     map[string]OpenAPIMediaType{jsonMediaType: {Schema: reg.schema(response, name+"Response", true)}},
+			}
+		}
+		if c.Status != ContractVerified {
+			for i := range c.Mismatches {
+				m := &c.Mismatches[i]
+				op.Mismatches = append(op.Mismatches, OpenAPIMismatch{
+					ContractID:  c.ID,
+					Field:       m.FieldPath,
+					Type:        m.Type,
+					Severity:    m.Severity,
+					Scope:       m.Scope,
+					Description: m.Description,
+				})
+			}
+		}
+		doc.Paths[path][method] = op
+	}
+
+	for fw := range frameworks {
+		doc.Tags = append(doc.Tags, OpenAPITag{Name: fw, Description: "Endpoints served by " + fw})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// schemaRegistry collects component schemas, keyed by their JSON encoding
+// so that identical shapes are stored once.
+type schemaRegistry struct {
+	components map[string]map[string]any
+	byShape    map[string]string
+}
+
+// schema converts s to an OpenAPI schema. Object schemas become components
+// when they are top-level bodies or were referenced by name; other objects
+// are inlined.
+func (r *schemaRegistry) schema(s *TypeSchema, name string, top bool) map[string]any {
+	if s == nil {
+		return map[string]any{}
+	}
+
+	var out map[string]any
+	switch s.Type {
+	case SchemaTypeObject:
+		// Properties are visited in order so component names are stable.
+		names := make([]string, 0, len(s.Properties))
+		for prop := range s.Properties {
+			names = append(names, prop)
+		}
+		sort.Strings(names)
+		props := make(map[string]any, len(names))
+		for _, prop := range names {
+			props[prop] = r.schema(s.Properties[prop], name+schemaName(prop), false)
+		}
+		out = map[string]any{"type": "object"}
+		if len(props) > 0 {
+			out["properties"] = props
+		}
+		if len(s.Required) > 0 {
+			required := append([]string(nil), s.Required...)
+			sort.Strings(required)
+			out["required"] = required
+		}
+		if top || s.Ref != "" {
+			if ref := refName(s.Ref); ref != "" {
+				name = ref
+			}
+			out = map[string]any{"$ref": "#/components/schemas/" + r.add(out, name)}
+		}
+	case SchemaTypeArray:
+		out = map[string]any{"type": "array"}
+		if s.Items != nil {
+			out["items"] = r.schema(s.Items, name+"Item", top)
+		}
+	case SchemaTypeAny, SchemaTypeUnknown, "":
+		return map[string]any{}
+	default:
+		out = map[string]any{"type": string(s.Type)}
+		if s.Format != "" {
+			out["format"] = s.Format
+		}
+		if len(s.Enum) > 0 {
+			out["enum"] = append([]string(nil), s.Enum...)
+		}
+	}
+
+	if !s.Nullable || s.Type == SchemaTypeNull {
+		return out
+	}
+	if t, ok := out["type"].(string); ok {
+		out["type"] = []string{t, "null"}
+		return out
+	}
+	return map[string]any{"anyOf": []any{out, map[string]any{"type": "null"}}}
+}
+
+// add stores a component under name, or returns the name of an identical
+// component already stored. Names are made unique with a numeric suffix.
+func (r *schemaRegistry) add(s map[string]any, name string) string {
+	shape, err := json.Marshal(s)
+	if err == nil {
+		if existing, ok := r.byShape[string(shape)]; ok {
+			return existing
+		}
+	}
+	unique := name
+	for i := 2; r.components[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	r.components[unique] = s
+	if err == nil {
+		r.byShape[string(shape)] = unique
+	}
+	return unique
+}
+
+var pathParamNameRe = regexp.MustCompile(`:([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// openAPIPath writes path parameters in OpenAPI's {param} form.
+func openAPIPath(path string) string {
+	return pathParamNameRe.ReplaceAllString(NormalizePath(path), "{$1}")
+}
+
+// operationName derives a name such as GetApiUsersById from a route.
+func operationName(method, path string) string {
+	var b strings.Builder
+	b.WriteString(schemaName(strings.ToLower(method)))
+	for _, segment := range strings.Split(NormalizePath(path), "/") {
+		if param, ok := strings.CutPrefix(segment, ":"); ok {
+			b.WriteString("By")
+			segment = param
+		}
+		b.WriteString(schemaName(segment))
+	}
+	return b.String()
+}
+
+// refName is the last segment of a schema reference such as
+// "#/components/schemas/User".
+func refName(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	return schemaName(ref[strings.LastIndex(ref, "/")+1:])
+}
+
+// schemaName turns s into a PascalCase component name, dropping characters
+// OpenAPI does not allow in component keys.
+func schemaName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
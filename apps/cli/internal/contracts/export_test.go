@@ -0,0 +1,111 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportOpenAPI(t *testing.T) {
+	user := func() *TypeSchema {
+		s := NewObjectSchema()
+		s.AddProperty("id", NewPrimitiveSchema(SchemaTypeString), true)
+		email := NewPrimitiveSchema(SchemaTypeString)
+		email.Nullable = true
+		s.AddProperty("email", email, false)
+		return s
+	}
+
+	list := []*Contract{
+		{
+			ID: "ct_get", Method: "GET", Endpoint: "/api/users/:id", Status: ContractMismatch,
+			Backend: BackendEndpoint{File: "users.go", Framework: "gin", Handler: "getUser", ResponseSchema: user()},
+			Mismatches: []FieldMismatch{
+				{FieldPath: "email", Type: MismatchNullabilityMismatch, Severity: SeverityWarning},
+			},
+		},
+		{
+			ID: "ct_list", Method: "GET", Endpoint: "/api/users", Status: ContractVerified,
+			Backend: BackendEndpoint{File: "users.go", Framework: "gin", Handler: "listUsers",
+				ResponseSchema: NewArraySchema(user())},
+			Mismatches: []FieldMismatch{{FieldPath: "id", Type: MismatchTypeMismatch, Severity: SeverityError}},
+		},
+		{
+			ID: "ct_post", Method: "POST", Endpoint: "/api/orders", Status: ContractDiscovered,
+			Backend: BackendEndpoint{File: "app.py", Framework: "fastapi", RequestSchema: NewPrimitiveSchema(SchemaTypeString)},
+		},
+		{
+			ID: "ct_ignored", Method: "DELETE", Endpoint: "/api/users/:id", Status: ContractIgnored,
+			Backend: BackendEndpoint{File: "users.go", Framework: "gin"},
+		},
+	}
+
+	doc := ExportOpenAPI(list, OpenAPIInfo{Title: "test", Version: "1.0.0"})
+
+	if doc.OpenAPI != OpenAPIVersion {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	if len(doc.Tags) != 2 || doc.Tags[0].Name != "fastapi" || doc.Tags[1].Name != "gin" {
+		t.Errorf("tags = %+v", doc.Tags)
+	}
+	if len(doc.Paths) != 3 {
+		t.Fatalf("paths = %d, want 3", len(doc.Paths))
+	}
+	if doc.Paths["/api/users/{id}"]["delete"] != nil {
+		t.Error("ignored contract was exported")
+	}
+
+	get := doc.Paths["/api/users/{id}"]["get"]
+	if get == nil || get.OperationID != "getUser" || len(get.Parameters) != 1 || get.Parameters[0].Name != "id" {
+		t.Fatalf("get operation = %+v", get)
+	}
+	if len(get.Mismatches) != 1 || get.Mismatches[0].ContractID != "ct_get" {
+		t.Errorf("get mismatches = %+v", get.Mismatches)
+	}
+	// GET /api/users sorts first, so the shared user component is named
+	// after its items.
+	if ref := get.Responses["200"].Content[jsonMediaType].Schema["$ref"]; ref != "#/components/schemas/ListUsersResponseItem" {
+		t.Errorf("get response ref = %v", ref)
+	}
+
+	// The list items have the same shape as the single user and share
+	// its component; verified contracts carry no mismatches.
+	list200 := doc.Paths["/api/users"]["get"].Responses["200"].Content[jsonMediaType].Schema
+	if items, _ := list200["items"].(map[string]any); items["$ref"] != "#/components/schemas/ListUsersResponseItem" {
+		t.Errorf("list response = %v", list200)
+	}
+	if len(doc.Paths["/api/users"]["get"].Mismatches) != 0 {
+		t.Error("verified contract exported mismatches")
+	}
+	if len(doc.Components.Schemas) != 1 {
+		t.Errorf("components = %v", doc.Components.Schemas)
+	}
+
+	component := doc.Components.Schemas["ListUsersResponseItem"]
+	props, _ := component["properties"].(map[string]any)
+	email, _ := props["email"].(map[string]any)
+	if types, _ := email["type"].([]string); len(types) != 2 || types[1] != "null" {
+		t.Errorf("email = %v", email)
+	}
+
+	post := doc.Paths["/api/orders"]["post"]
+	if post.OperationID != "postApiOrders" || post.RequestBody == nil || len(post.Tags) != 1 || post.Tags[0] != "fastapi" {
+		t.Errorf("post operation = %+v", post)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		"/api/users/:id":            "GetApiUsersById",
+		"/api/users/{userId}/posts": "GetApiUsersByUserIdPosts",
+		"/v1/order-items":           "GetV1OrderItems",
+	}
+	for path, want := range tests {
+		if got := operationName("GET", path); got != want {
+			t.Errorf("operationName(GET, %q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+package extractors
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+)
+
+// OpenAPIExtractor extracts API endpoints from OpenAPI 3 and Swagger 2
+// documents, in YAML or JSON. Unlike the code extractors its endpoints are
+// declarations, which contract analysis treats as the source of truth.
+type OpenAPIExtractor struct{}
+
+// NewOpenAPIExtractor creates a new OpenAPI endpoint extractor.
+func NewOpenAPIExtractor() *OpenAPIExtractor {
+	return &OpenAPIExtractor{}
+}
+
+// ID returns the unique identifier for this extractor.
+func (e *OpenAPIExtractor) ID() string {
+	return "openapi"
+}
+
+// Framework returns the framework name.
+func (e *OpenAPIExtractor) Framework() string {
+	return "openapi"
+}
+
+// Languages returns the languages this extractor supports.
+func (e *OpenAPIExtractor) Languages() []string {
+	return []string{"yaml", "json"}
+}
+
+// CanExtract returns true if this extractor can handle the given file.
+func (e *OpenAPIExtractor) CanExtract(file *analysis.FileAnalysis) bool {
+	return IsOpenAPIFileName(file.Path)
+}
+
+// ExtractEndpoints extracts API endpoints from an OpenAPI document on disk.
+func (e *OpenAPIExtractor) ExtractEndpoints(file *analysis.FileAnalysis) ([]ExtractedEndpoint, error) {
+	content, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExtractEndpointsFromContent(content, file.Path)
+}
+
+// IsOpenAPIFileName reports whether path is named like an API spec:
+// a YAML or JSON file whose name mentions openapi or swagger.
+func IsOpenAPIFileName(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return false
+	}
+	name := strings.ToLower(filepath.Base(path))
+	return strings.Contains(name, "openapi") || strings.Contains(name, "swagger")
+}
+
+// ExtractEndpointsFromContent extracts endpoints directly from document
+// content. Documents without an "openapi" or "swagger" version field are
+// not specs and yield no endpoints.
+func (e *OpenAPIExtractor) ExtractEndpointsFromContent(content []byte, filePath string) ([]ExtractedEndpoint, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filePath, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	doc := &openAPIDoc{root: deref(root.Content[0])}
+	switch {
+	case mapValue(doc.root, "swagger") != nil:
+		doc.swagger2 = true
+	case mapValue(doc.root, "openapi") == nil:
+		return nil, nil
+	}
+
+	base := doc.basePath()
+	var endpoints []ExtractedEndpoint
+	paths := mapValue(doc.root, "paths")
+	forEachPair(paths, func(pathKey, item *yaml.Node) {
+		forEachPair(item, func(methodKey, op *yaml.Node) {
+			method := strings.ToUpper(methodKey.Value)
+			if !contracts.IsValidMethod(method) {
+				return
+			}
+			path := joinPaths(base, pathKey.Value)
+			endpoints = append(endpoints, ExtractedEndpoint{
+				Method:         method,
+				Path:           path,
+				PathParams:     contracts.ExtractPathParams(path),
+				Handler:        scalarValue(op, "operationId"),
+				File:           filePath,
+				Line:           methodKey.Line,
+				Framework:      e.Framework(),
+				RequestSchema:  doc.requestSchema(op),
+				ResponseSchema: doc.responseSchema(op),
+			})
+		})
+	})
+	return endpoints, nil
+}
+
+// openAPIDoc is a parsed spec. Swagger 2 keeps schemas under "definitions"
+// and bodies in parameters; OpenAPI 3 uses "components" and media types.
+type openAPIDoc struct {
+	root     *yaml.Node
+	swagger2 bool
+}
+
+// basePath is the path prefix shared by every operation: Swagger's
+// basePath, or the path of OpenAPI's first server URL.
+func (d *openAPIDoc) basePath() string {
+	if d.swagger2 {
+		return scalarValue(d.root, "basePath")
+	}
+	servers := mapValue(d.root, "servers")
+	if servers == nil || servers.Kind != yaml.SequenceNode || len(servers.Content) == 0 {
+		return ""
+	}
+	u, err := url.Parse(scalarValue(deref(servers.Content[0]), "url"))
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+func joinPaths(base, path string) string {
+	base = strings.TrimSuffix(base, "/")
+	if base == "" {
+		return path
+	}
+	return base + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (d *openAPIDoc) requestSchema(op *yaml.Node) *contracts.TypeSchema {
+	if d.swagger2 {
+		params := mapValue(op, "parameters")
+		if params == nil || params.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for _, p := range params.Content {
+			p = d.resolve(p)
+			if scalarValue(p, "in") == "body" {
+				return d.schema(mapValue(p, "schema"), map[string]bool{})
+			}
+		}
+		return nil
+	}
+	body := d.resolve(mapValue(op, "requestBody"))
+	return d.schema(jsonMediaSchema(mapValue(body, "content")), map[string]bool{})
+}
+
+// responseSchema is the body of the lowest 2xx response, falling back to
+// the default response.
+func (d *openAPIDoc) responseSchema(op *yaml.Node) *contracts.TypeSchema {
+	responses := mapValue(op, "responses")
+	var codes []string
+	forEachPair(responses, func(code, _ *yaml.Node) {
+		if n, err := strconv.Atoi(code.Value); err == nil && n >= 200 && n < 300 {
+			codes = append(codes, code.Value)
+		}
+	})
+	sort.Strings(codes)
+	codes = append(codes, "default")
+
+	for _, code := range codes {
+		resp := d.resolve(mapValue(responses, code))
+		if resp == nil {
+			continue
+		}
+		if d.swagger2 {
+			return d.schema(mapValue(resp, "schema"), map[string]bool{})
+		}
+		return d.schema(jsonMediaSchema(mapValue(resp, "content")), map[string]bool{})
+	}
+	return nil
+}
+
+// jsonMediaSchema picks the schema of application/json, then of any JSON
+// media type, then of the first media type.
+func jsonMediaSchema(content *yaml.Node) *yaml.Node {
+	var exact, anyJSON, first *yaml.Node
+	forEachPair(content, func(mediaType, media *yaml.Node) {
+		schema := mapValue(media, "schema")
+		if first == nil {
+			first = schema
+		}
+		switch {
+		case mediaType.Value == "application/json":
+			exact = schema
+		case anyJSON == nil && strings.Contains(mediaType.Value, "json"):
+			anyJSON = schema
+		}
+	})
+	switch {
+	case exact != nil:
+		return exact
+	case anyJSON != nil:
+		return anyJSON
+	}
+	return first
+}
+
+// resolve follows a $ref on a non-schema object such as a response or a
+// parameter.
+func (d *openAPIDoc) resolve(n *yaml.Node) *yaml.Node {
+	for i := 0; i < 8 && n != nil; i++ {
+		ref := scalarValue(n, "$ref")
+		if ref == "" {
+			return n
+		}
+		n = d.lookupRef(ref)
+	}
+	return n
+}
+
+// lookupRef resolves a local JSON pointer such as #/components/schemas/User.
+func (d *openAPIDoc) lookupRef(ref string) *yaml.Node {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	n := d.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if n = mapValue(n, part); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// schema converts a schema object into a TypeSchema. References are
+// inlined and keep their name in Ref; a reference back into a schema being
+// converted becomes "any" so recursive types terminate.
+func (d *openAPIDoc) schema(n *yaml.Node, seen map[string]bool) *contracts.TypeSchema {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	if ref := scalarValue(n, "$ref"); ref != "" {
+		target := d.lookupRef(ref)
+		if target == nil || seen[ref] {
+			return &contracts.TypeSchema{Type: contracts.SchemaTypeAny, Ref: ref}
+		}
+		seen[ref] = true
+		s := d.schema(target, seen)
+		delete(seen, ref)
+		if s == nil {
+			s = &contracts.TypeSchema{Type: contracts.SchemaTypeAny}
+		}
+		s.Ref = ref
+		return s
+	}
+
+	if all := mapValue(n, "allOf"); all != nil {
+		return d.mergeAllOf(all, seen)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if variants := mapValue(n, key); variants != nil {
+			return d.union(variants, seen)
+		}
+	}
+
+	s := &contracts.TypeSchema{Format: scalarValue(n, "format")}
+	switch t := deref(mapValue(n, "type")); {
+	case t == nil:
+		switch {
+		case mapValue(n, "properties") != nil:
+			s.Type = contracts.SchemaTypeObject
+		case mapValue(n, "items") != nil:
+			s.Type = contracts.SchemaTypeArray
+		default:
+			s.Type = contracts.SchemaTypeAny
+		}
+	case t.Kind == yaml.SequenceNode:
+		// OpenAPI 3.1: type: [string, "null"]
+		s.Type = contracts.SchemaTypeAny
+		for _, v := range t.Content {
+			if v.Value == "null" {
+				s.Nullable = true
+			} else {
+				s.Type = schemaType(v.Value)
+			}
+		}
+	default:
+		s.Type = schemaType(t.Value)
+	}
+	if scalarValue(n, "nullable") == "true" || scalarValue(n, "x-nullable") == "true" {
+		s.Nullable = true
+	}
+
+	if enum := mapValue(n, "enum"); enum != nil && enum.Kind == yaml.SequenceNode {
+		for _, v := range enum.Content {
+			if v.Kind == yaml.ScalarNode && v.Tag != "!!null" {
+				s.Enum = append(s.Enum, v.Value)
+			}
+		}
+	}
+
+	switch s.Type {
+	case contracts.SchemaTypeObject:
+		s.Properties = map[string]*contracts.TypeSchema{}
+		forEachPair(mapValue(n, "properties"), func(name, prop *yaml.Node) {
+			if ps := d.schema(prop, seen); ps != nil {
+				s.Properties[name.Value] = ps
+			} else {
+				s.Properties[name.Value] = &contracts.TypeSchema{Type: contracts.SchemaTypeAny}
+			}
+		})
+		if req := mapValue(n, "required"); req != nil && req.Kind == yaml.SequenceNode {
+			for _, r := range req.Content {
+				s.Required = append(s.Required, r.Value)
+			}
+		}
+	case contracts.SchemaTypeArray:
+		s.Items = d.schema(mapValue(n, "items"), seen)
+	}
+	return s
+}
+
+// mergeAllOf combines the properties of every member into one object.
+func (d *openAPIDoc) mergeAllOf(all *yaml.Node, seen map[string]bool) *contracts.TypeSchema {
+	if all.Kind != yaml.SequenceNode {
+		return nil
+	}
+	if len(all.Content) == 1 {
+		return d.schema(all.Content[0], seen)
+	}
+	merged := contracts.NewObjectSchema()
+	for _, member := range all.Content {
+		s := d.schema(member, seen)
+		if s == nil || s.Type != contracts.SchemaTypeObject {
+			continue
+		}
+		for name, prop := range s.Properties {
+			merged.Properties[name] = prop
+		}
+		merged.Required = append(merged.Required, s.Required...)
+		merged.Nullable = merged.Nullable || s.Nullable
+	}
+	return merged
+}
+
+// union converts oneOf/anyOf. A single variant besides null is that variant,
+// nullable; anything wider is "any".
+func (d *openAPIDoc) union(variants *yaml.Node, seen map[string]bool) *contracts.TypeSchema {
+	if variants.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var kept []*contracts.TypeSchema
+	nullable := false
+	for _, v := range variants.Content {
+		s := d.schema(v, seen)
+		switch {
+		case s == nil:
+		case s.Type == contracts.SchemaTypeNull:
+			nullable = true
+		default:
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) != 1 {
+		return &contracts.TypeSchema{Type: contracts.SchemaTypeAny, Nullable: nullable}
+	}
+	kept[0].Nullable = kept[0].Nullable || nullable
+	return kept[0]
+}
+
+func schemaType(t string) contracts.SchemaType {
+	switch t {
+	case "object":
+		return contracts.SchemaTypeObject
+	case "array":
+		return contracts.SchemaTypeArray
+	case "string":
+		return contracts.SchemaTypeString
+	case "integer":
+		return contracts.SchemaTypeInteger
+	case "number":
+		return contracts.SchemaTypeNumber
+	case "boolean":
+		return contracts.SchemaTypeBoolean
+	case "null":
+		return contracts.SchemaTypeNull
+	}
+	return contracts.SchemaTypeUnknown
+}
+
+// deref follows YAML aliases.
+func deref(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// mapValue returns the value of key in a mapping node, or nil.
+func mapValue(n *yaml.Node, key string) *yaml.Node {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return deref(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func scalarValue(n *yaml.Node, key string) string {
+	if v := mapValue(n, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+// forEachPair calls fn for every key/value pair of a mapping node, in
+// document order.
+func forEachPair(n *yaml.Node, fn func(key, value *yaml.Node)) {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i], deref(n.Content[i+1]))
+	}
+}
@@ -0,0 +1,174 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+)
+
+func TestOpenAPIExtractor_OpenAPI3(t *testing.T) {
+	spec := []byte(`openapi: 3.0.3
+info:
+  title: Users
+  version: "1"
+servers:
+  - url: https://api.example.com/api
+paths:
+  /users/{userId}:
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+    put:
+      operationId: updateUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/User"
+      responses:
+        "204":
+          description: updated
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, role]
+      properties:
+        id:
+          type: string
+          format: uuid
+        role:
+          type: string
+          enum: [admin, member]
+        bio:
+          type: string
+          nullable: true
+`)
+
+	endpoints, err := NewOpenAPIExtractor().ExtractEndpointsFromContent(spec, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("failed to extract endpoints: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(endpoints))
+	}
+
+	get := endpoints[0]
+	if get.Method != "GET" || get.Path != "/api/users/{userId}" || get.Handler != "getUser" {
+		t.Errorf("unexpected endpoint: %s %s (%s)", get.Method, get.Path, get.Handler)
+	}
+	if get.Line != 9 {
+		t.Errorf("expected line 9, got %d", get.Line)
+	}
+	if len(get.PathParams) != 1 || get.PathParams[0] != "userId" {
+		t.Errorf("expected path param userId, got %v", get.PathParams)
+	}
+
+	user := get.ResponseSchema
+	if user == nil || user.Type != contracts.SchemaTypeObject {
+		t.Fatalf("expected object response schema, got %+v", user)
+	}
+	if user.Ref != "#/components/schemas/User" {
+		t.Errorf("expected ref to be kept, got %q", user.Ref)
+	}
+	if id := user.Properties["id"]; id == nil || id.Format != "uuid" || !user.IsRequired("id") {
+		t.Errorf("unexpected id property: %+v", id)
+	}
+	if role := user.Properties["role"]; role == nil || len(role.Enum) != 2 {
+		t.Errorf("unexpected role property: %+v", role)
+	}
+	if bio := user.Properties["bio"]; bio == nil || !bio.Nullable || user.IsRequired("bio") {
+		t.Errorf("unexpected bio property: %+v", bio)
+	}
+
+	put := endpoints[1]
+	if put.RequestSchema == nil || put.RequestSchema.Properties["role"] == nil {
+		t.Errorf("expected request body schema, got %+v", put.RequestSchema)
+	}
+	if put.ResponseSchema != nil {
+		t.Errorf("expected no response schema for 204, got %+v", put.ResponseSchema)
+	}
+}
+
+func TestOpenAPIExtractor_Swagger2(t *testing.T) {
+	spec := []byte(`{
+  "swagger": "2.0",
+  "basePath": "/v1",
+  "paths": {
+    "/orders": {
+      "post": {
+        "parameters": [
+          {"in": "body", "name": "order", "schema": {"$ref": "#/definitions/Order"}}
+        ],
+        "responses": {
+          "201": {"schema": {"type": "array", "items": {"$ref": "#/definitions/Order"}}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Order": {
+      "type": "object",
+      "properties": {
+        "total": {"type": "number", "x-nullable": true}
+      }
+    }
+  }
+}`)
+
+	endpoints, err := NewOpenAPIExtractor().ExtractEndpointsFromContent(spec, "swagger.json")
+	if err != nil {
+		t.Fatalf("failed to extract endpoints: %v", err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(endpoints))
+	}
+
+	ep := endpoints[0]
+	if ep.Method != "POST" || ep.Path != "/v1/orders" {
+		t.Errorf("unexpected endpoint: %s %s", ep.Method, ep.Path)
+	}
+	if ep.RequestSchema == nil || ep.RequestSchema.Properties["total"] == nil {
+		t.Fatalf("expected body parameter schema, got %+v", ep.RequestSchema)
+	}
+	if !ep.RequestSchema.Properties["total"].Nullable {
+		t.Error("expected x-nullable to mark total nullable")
+	}
+	if ep.ResponseSchema == nil || ep.ResponseSchema.Type != contracts.SchemaTypeArray || ep.ResponseSchema.Items == nil {
+		t.Errorf("expected array response schema, got %+v", ep.ResponseSchema)
+	}
+}
+
+func TestOpenAPIExtractor_NotASpec(t *testing.T) {
+	endpoints, err := NewOpenAPIExtractor().ExtractEndpointsFromContent([]byte("name: app\nversion: 1\n"), "openapi-config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(endpoints) != 0 {
+		t.Errorf("expected no endpoints, got %d", len(endpoints))
+	}
+
+	if _, err := NewOpenAPIExtractor().ExtractEndpointsFromContent([]byte("openapi: [\n"), "openapi.yaml"); err == nil {
+		t.Error("expected error for malformed document")
+	}
+}
+
+func TestIsOpenAPIFileName(t *testing.T) {
+	tests := map[string]bool{
+		"api/openapi.yaml":      true,
+		"petstore.swagger.json": true,
+		"docs/Swagger.yml":      true,
+		"openapi.ts":            false,
+		"config.yaml":           false,
+	}
+	for path, want := range tests {
+		if got := IsOpenAPIFileName(path); got != want {
+			t.Errorf("IsOpenAPIFileName(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package contracts
+
+import (
+	"fmt"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+)
+
+// MismatchFindings reports each field mismatch of c under its MismatchType.
+// The finding sits on the backend handler, or on the spec operation when
+// there is no backend code, with the spec and the frontend calls as related
+// locations. Paths are made relative to root. Ignored contracts report
+// nothing.
+func MismatchFindings(root string, c *Contract) []findings.Finding {
+	if c.Status == ContractIgnored {
+		return nil
+	}
+
+	location := findings.Location{Path: RelPath(root, c.Backend.File), LineStart: c.Backend.Line}
+	related := make([]findings.Location, 0, len(c.FrontendCalls)+1)
+	if c.Spec != nil {
+		specLocation := findings.Location{Path: RelPath(root, c.Spec.File), LineStart: c.Spec.Line}
+		if location.Path == "" {
+			location = specLocation
+		} else {
+			related = append(related, specLocation)
+		}
+	}
+	for i := range c.FrontendCalls {
+		call := &c.FrontendCalls[i]
+		related = append(related, findings.Location{Path: RelPath(root, call.File), LineStart: call.Line})
+	}
+
+	list := make([]findings.Finding, 0, len(c.Mismatches))
+	for i := range c.Mismatches {
+		m := &c.Mismatches[i]
+		properties := map[string]any{
+			"contractId": c.ID,
+			"field":      m.FieldPath,
+		}
+		if m.Scope != ScopeBackendFrontend {
+			properties["scope"] = string(m.Scope)
+		}
+		list = append(list, findings.Finding{
+			RuleID:      string(m.Type),
+			RuleName:    mismatchRuleName(m.Type),
+			Description: mismatchRuleDescription(m.Type),
+			Level:       findingLevel(m.Severity),
+			Message:     fmt.Sprintf("%s %s: %s", c.Method, c.Endpoint, m.Description),
+			Location:    location,
+			Related:     related,
+			Properties:  properties,
+		})
+	}
+	return list
+}
+
+// MismatchRuleIDs lists the rule IDs MismatchFindings can report.
+func MismatchRuleIDs() []string {
+	return []string{
+		string(MismatchMissingInFrontend),
+		string(MismatchMissingInBackend),
+		string(MismatchTypeMismatch),
+		string(MismatchOptionalityMismatch),
+		string(MismatchNullabilityMismatch),
+	}
+}
+
+func findingLevel(s MismatchSeverity) string {
+	switch s {
+	case SeverityError:
+		return findings.LevelError
+	case SeverityWarning:
+		return findings.LevelWarning
+	}
+	return findings.LevelNote
+}
+
+func mismatchRuleName(t MismatchType) string {
+	switch t {
+	case MismatchMissingInFrontend:
+		return "FieldMissingInFrontend"
+	case MismatchMissingInBackend:
+		return "FieldMissingInBackend"
+	case MismatchTypeMismatch:
+		return "FieldTypeMismatch"
+	case MismatchOptionalityMismatch:
+		return "FieldOptionalityMismatch"
+	case MismatchNullabilityMismatch:
+		return "FieldNullabilityMismatch"
+	}
+	return string(t)
+}
+
+func mismatchRuleDescription(t MismatchType) string {
+	switch t {
+	case MismatchMissingInFrontend:
+		return "Backend returns a field the frontend never reads"
+	case MismatchMissingInBackend:
+		return "Frontend reads a field the backend does not return"
+	case MismatchTypeMismatch:
+		return "Frontend and backend disagree on a field's type"
+	case MismatchOptionalityMismatch:
+		return "Frontend and backend disagree on whether a field is required"
+	case MismatchNullabilityMismatch:
+		return "Frontend and backend disagree on whether a field can be null"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package contracts
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+)
+
+func TestMismatchFindings(t *testing.T) {
+	root := t.TempDir()
+	c := &Contract{
+		ID:       "ctr_1",
+		Method:   "GET",
+		Endpoint: "/api/users",
+		Backend:  BackendEndpoint{File: filepath.Join(root, "api", "handler.go"), Line: 12},
+		FrontendCalls: []FrontendCall{
+			{File: filepath.Join(root, "web", "app.ts"), Line: 3},
+			{File: "admin.ts", Line: 8},
+		},
+		Mismatches: []FieldMismatch{
+			{FieldPath: "id", Type: MismatchTypeMismatch, Severity: SeverityError, Description: "id differs"},
+			{FieldPath: "bio", Type: MismatchMissingInFrontend, Severity: SeverityInfo, Description: "bio unused"},
+		},
+		Status: ContractMismatch,
+	}
+
+	got := MismatchFindings(root, c)
+	if len(got) != 2 {
+		t.Fatalf("got %d findings, want 2", len(got))
+	}
+	first := got[0]
+	if first.RuleID != "type_mismatch" || first.Level != findings.LevelError {
+		t.Errorf("first finding = %+v", first)
+	}
+	if first.Location.Path != "api/handler.go" || first.Location.LineStart != 12 || len(first.Related) != 2 {
+		t.Errorf("first finding locations = %+v, related %+v", first.Location, first.Related)
+	}
+	if first.Related[0].Path != "web/app.ts" || first.Related[1].Path != "admin.ts" {
+		t.Errorf("related paths = %+v, want workspace-relative", first.Related)
+	}
+	if first.Message != "GET /api/users: id differs" {
+		t.Errorf("message = %q", first.Message)
+	}
+	if got[1].RuleID != "missing_in_frontend" || got[1].Level != findings.LevelNote {
+		t.Errorf("second finding = %+v", got[1])
+	}
+
+	c.Status = ContractIgnored
+	if got := MismatchFindings(root, c); len(got) != 0 {
+		t.Errorf("ignored contract reported %d findings", len(got))
+	}
+}
@@ -0,0 +1,67 @@
+package contracts
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var pathParamRe = regexp.MustCompile(`:[^/]+`)
+
+// signature identifies an endpoint regardless of how its path parameters are
+// named, so /users/{userId} in a spec matches /users/:id in code.
+func signature(method, path string) string {
+	return method + ":" + pathParamRe.ReplaceAllString(NormalizePath(path), ":")
+}
+
+// compareScoped compares a providing schema with a consuming one like
+// Compare, then labels the mismatches with their scope and describes them
+// in terms of the two sources.
+func compareScoped(provider, consumer *TypeSchema, path string, scope MismatchScope, providerName, consumerName string) []FieldMismatch {
+	if provider == nil || consumer == nil {
+		return nil
+	}
+	mismatches := provider.Compare(consumer, path)
+	for i := range mismatches {
+		mismatches[i].Scope = scope
+		mismatches[i].Description = describeMismatch(&mismatches[i], providerName, consumerName)
+	}
+	return mismatches
+}
+
+// compareSpecBackend checks backend code against its spec. For responses
+// the backend provides what the spec promises; for request bodies the spec
+// defines what the backend may read.
+func compareSpecBackend(spec *SpecEndpoint, backend *BackendEndpoint) []FieldMismatch {
+	mismatches := compareScoped(backend.ResponseSchema, spec.ResponseSchema, "$", ScopeSpecBackend, "backend", "spec")
+	return append(mismatches,
+		compareScoped(spec.RequestSchema, backend.RequestSchema, "$request", ScopeSpecBackend, "spec", "backend")...)
+}
+
+// describeMismatch words a mismatch between a providing and a consuming
+// source, e.g. "spec" and "frontend".
+func describeMismatch(m *FieldMismatch, provider, consumer string) string {
+	switch m.Type {
+	case MismatchMissingInFrontend:
+		return fmt.Sprintf("Field %q is in the %s but not the %s", m.FieldPath, provider, consumer)
+	case MismatchMissingInBackend:
+		return fmt.Sprintf("Field %q is in the %s but not the %s", m.FieldPath, consumer, provider)
+	case MismatchTypeMismatch:
+		return fmt.Sprintf("Type mismatch at %q: %s has %s, %s has %s",
+			m.FieldPath, provider, m.BackendType, consumer, m.FrontendType)
+	case MismatchNullabilityMismatch:
+		return fmt.Sprintf("Nullability mismatch at %q: %s allows null, %s doesn't", m.FieldPath, provider, consumer)
+	case MismatchOptionalityMismatch:
+		return fmt.Sprintf("Optionality mismatch at %q: optional in %s, required in %s", m.FieldPath, provider, consumer)
+	}
+	return m.Description
+}
+
+func specEndpoint(ep *EndpointInput) *SpecEndpoint {
+	return &SpecEndpoint{
+		File:           ep.File,
+		Line:           ep.Line,
+		OperationID:    ep.Handler,
+		RequestSchema:  ep.RequestSchema,
+		ResponseSchema: ep.ResponseSchema,
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -19,6 +21,19 @@ func NewStore(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
+// HasTables reports whether the contract tables exist. Read-only callers
+// use it to skip workspaces where contracts were never scanned rather than
+// creating the tables.
+func (s *Store) HasTables() (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(context.Background(),
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'contracts'`).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check contract tables: %w", err)
+	}
+	return n > 0, nil
+}
+
 // CreateTables creates the necessary database tables for contracts.
 func (s *Store) CreateTables() error {
 	queries := []string{
@@ -35,6 +50,12 @@ func (s *Store) CreateTables() error {
 			backend_request_schema TEXT,
 			backend_response_schema TEXT,
 
+			spec_file TEXT DEFAULT '',
+			spec_line INTEGER DEFAULT 0,
+			spec_operation_id TEXT DEFAULT '',
+			spec_request_schema TEXT DEFAULT '',
+			spec_response_schema TEXT DEFAULT '',
+
 			frontend_call_count INTEGER DEFAULT 0,
 
 			status TEXT DEFAULT 'discovered',
@@ -64,6 +85,7 @@ func (s *Store) CreateTables() error {
 			description TEXT,
 			backend_type TEXT,
 			frontend_type TEXT,
+			scope TEXT DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_contracts_endpoint ON contracts(endpoint)`,
@@ -88,18 +110,27 @@ func (s *Store) SaveContract(contract *Contract) error {
 	requestSchema, _ := json.Marshal(contract.Backend.RequestSchema)
 	responseSchema, _ := json.Marshal(contract.Backend.ResponseSchema)
 
+	spec := contract.Spec
+	if spec == nil {
+		spec = &SpecEndpoint{}
+	}
+	specRequestSchema, _ := json.Marshal(spec.RequestSchema)
+	specResponseSchema, _ := json.Marshal(spec.ResponseSchema)
+
 	_, err := s.db.ExecContext(context.Background(), `
 		INSERT OR REPLACE INTO contracts (
 			id, method, endpoint, endpoint_pattern,
 			backend_file, backend_line, backend_framework, backend_handler,
 			backend_request_schema, backend_response_schema,
+			spec_file, spec_line, spec_operation_id, spec_request_schema, spec_response_schema,
 			frontend_call_count, status, authority, confidence,
 			first_seen, last_seen, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		contract.ID, contract.Method, contract.Endpoint, contract.EndpointPattern,
 		contract.Backend.File, contract.Backend.Line, contract.Backend.Framework, contract.Backend.Handler,
 		string(requestSchema), string(responseSchema),
+		spec.File, spec.Line, spec.OperationID, string(specRequestSchema), string(specResponseSchema),
 		len(contract.FrontendCalls), string(contract.Status), contract.Authority, contract.Confidence,
 		contract.FirstSeen, contract.LastSeen, time.Now(), time.Now(),
 	)
@@ -124,6 +155,105 @@ func (s *Store) SaveContract(contract *Contract) error {
 	return nil
 }
 
+// UpsertContract saves a freshly analyzed contract. If a contract for the same
+// method and endpoint is already stored, it keeps that contract's ID, status,
+// authority and first-seen time and replaces its frontend calls and mismatches,
+// so re-running analysis does not duplicate contracts.
+func (s *Store) UpsertContract(contract *Contract) error {
+	var id, status, authority string
+	var firstSeen time.Time
+	err := s.db.QueryRowContext(context.Background(), `
+		SELECT id, status, authority, first_seen FROM contracts
+		WHERE method = ? AND endpoint = ?
+		ORDER BY first_seen LIMIT 1
+	`, contract.Method, contract.Endpoint).Scan(&id, &status, &authority, scanTime{&firstSeen})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find contract: %w", err)
+	}
+
+	if err == nil {
+		contract.ID = id
+		contract.Status = ContractStatus(status)
+		contract.Authority = authority
+		contract.FirstSeen = firstSeen
+		for _, table := range []string{"contract_frontend_calls", "contract_mismatches"} {
+			if _, err := s.db.ExecContext(context.Background(),
+				`DELETE FROM `+table+` WHERE contract_id = ?`, id); err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+	}
+
+	return s.SaveContract(contract)
+}
+
+// RemoveMissing deletes stored contracts whose backend file lies under dir
+// but which are not among current, the contracts of a fresh analysis of dir.
+// Their endpoint was removed or renamed since the last analysis. Contracts
+// only a spec declares are deleted when the spec file lies under dir or was
+// read by the analysis, since the operation is then gone from the spec. An
+// empty dir covers every contract. It returns the number of contracts removed.
+func (s *Store) RemoveMissing(current []*Contract, dir string) (int, error) {
+	seen := make(map[string]bool, len(current))
+	specs := make(map[string]bool)
+	for _, c := range current {
+		seen[c.Method+" "+c.Endpoint] = true
+		if c.Spec != nil && c.Spec.File != "" {
+			specs[c.Spec.File] = true
+		}
+	}
+
+	rows, err := s.db.QueryContext(context.Background(),
+		`SELECT id, method, endpoint, COALESCE(backend_file, ''), COALESCE(spec_file, '') FROM contracts`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list contracts: %w", err)
+	}
+	var missing []string
+	for rows.Next() {
+		var id, method, endpoint, file, specFile string
+		if err := rows.Scan(&id, &method, &endpoint, &file, &specFile); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if seen[method+" "+endpoint] {
+			continue
+		}
+		if file != "" && !underDir(file, dir) {
+			continue
+		}
+		if file == "" && !underDir(specFile, dir) && !specs[specFile] {
+			continue
+		}
+		missing = append(missing, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range missing {
+		for _, table := range []string{"contract_frontend_calls", "contract_mismatches"} {
+			if _, err := s.db.ExecContext(context.Background(),
+				`DELETE FROM `+table+` WHERE contract_id = ?`, id); err != nil {
+				return 0, fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+		if err := s.DeleteContract(id); err != nil {
+			return 0, fmt.Errorf("failed to delete contract: %w", err)
+		}
+	}
+	return len(missing), nil
+}
+
+// underDir reports whether file is dir or lies beneath it.
+func underDir(file, dir string) bool {
+	if dir == "" {
+		return true
+	}
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 func (s *Store) saveFrontendCall(contractID string, call *FrontendCall) error {
 	expectedSchema, _ := json.Marshal(call.ExpectedSchema)
 
@@ -142,11 +272,11 @@ func (s *Store) saveMismatch(contractID string, mismatch *FieldMismatch) error {
 
 	_, err := s.db.ExecContext(context.Background(), `
 		INSERT OR REPLACE INTO contract_mismatches (
-			id, contract_id, field_path, mismatch_type, severity, description, backend_type, frontend_type
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, contract_id, field_path, mismatch_type, severity, description, backend_type, frontend_type, scope
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		id, contractID, mismatch.FieldPath, string(mismatch.Type), mismatch.Severity,
-		mismatch.Description, mismatch.BackendType, mismatch.FrontendType,
+		mismatch.Description, mismatch.BackendType, mismatch.FrontendType, string(mismatch.Scope),
 	)
 	return err
 }
@@ -157,19 +287,23 @@ func (s *Store) GetContract(id string) (*Contract, error) {
 		SELECT id, method, endpoint, endpoint_pattern,
 			backend_file, backend_line, backend_framework, backend_handler,
 			backend_request_schema, backend_response_schema,
+			spec_file, spec_line, spec_operation_id, spec_request_schema, spec_response_schema,
 			status, authority, confidence, first_seen, last_seen
 		FROM contracts WHERE id = ?
 	`, id)
 
 	contract := &Contract{}
 	var requestSchema, responseSchema string
+	var spec SpecEndpoint
+	var specRequestSchema, specResponseSchema string
 	var status string
 
 	err := row.Scan(
 		&contract.ID, &contract.Method, &contract.Endpoint, &contract.EndpointPattern,
 		&contract.Backend.File, &contract.Backend.Line, &contract.Backend.Framework, &contract.Backend.Handler,
 		&requestSchema, &responseSchema,
-		&status, &contract.Authority, &contract.Confidence, &contract.FirstSeen, &contract.LastSeen,
+		&spec.File, &spec.Line, &spec.OperationID, &specRequestSchema, &specResponseSchema,
+		&status, &contract.Authority, &contract.Confidence, scanTime{&contract.FirstSeen}, scanTime{&contract.LastSeen},
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -187,6 +321,15 @@ func (s *Store) GetContract(id string) (*Contract, error) {
 	if responseSchema != "" {
 		_ = json.Unmarshal([]byte(responseSchema), &contract.Backend.ResponseSchema)
 	}
+	if spec.File != "" {
+		if specRequestSchema != "" {
+			_ = json.Unmarshal([]byte(specRequestSchema), &spec.RequestSchema)
+		}
+		if specResponseSchema != "" {
+			_ = json.Unmarshal([]byte(specResponseSchema), &spec.ResponseSchema)
+		}
+		contract.Spec = &spec
+	}
 
 	// Load frontend calls
 	calls, err := s.getFrontendCalls(id)
@@ -236,7 +379,7 @@ func (s *Store) getFrontendCalls(contractID string) ([]FrontendCall, error) {
 
 func (s *Store) getMismatches(contractID string) ([]FieldMismatch, error) {
 	rows, err := s.db.QueryContext(context.Background(), `
-		SELECT field_path, mismatch_type, severity, description, backend_type, frontend_type
+		SELECT field_path, mismatch_type, severity, description, backend_type, frontend_type, scope
 		FROM contract_mismatches WHERE contract_id = ?
 	`, contractID)
 	if err != nil {
@@ -247,11 +390,12 @@ func (s *Store) getMismatches(contractID string) ([]FieldMismatch, error) {
 	var mismatches []FieldMismatch
 	for rows.Next() {
 		var m FieldMismatch
-		var mType string
-		if err := rows.Scan(&m.FieldPath, &mType, &m.Severity, &m.Description, &m.BackendType, &m.FrontendType); err != nil {
+		var mType, scope string
+		if err := rows.Scan(&m.FieldPath, &mType, &m.Severity, &m.Description, &m.BackendType, &m.FrontendType, &scope); err != nil {
 			return nil, err
 		}
 		m.Type = MismatchType(mType)
+		m.Scope = MismatchScope(scope)
 		mismatches = append(mismatches, m)
 	}
 	if err := rows.Err(); err != nil {
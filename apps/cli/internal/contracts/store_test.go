@@ -0,0 +1,288 @@
+package contracts
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	_ "modernc.org/sqlite"
+)
+
+func TestStore_UpsertContractKeepsIdentity(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "contracts.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := NewStore(db)
+	if err := store.CreateTables(); err != nil {
+		t.Fatalf("CreateTables() error = %v", err)
+	}
+
+	analyze := func(calls int) *Contract {
+		input := &AnalysisInput{
+			Endpoints: []EndpointInput{{Method: "GET", Path: "/api/users", File: "handler.go", Line: 10}},
+		}
+		for i := 0; i < calls; i++ {
+			input.Calls = append(input.Calls, CallInput{Method: "GET", URL: "/api/users", File: "app.ts", Line: i + 1})
+		}
+		result := NewAnalyzer().Analyze(input)
+		if len(result.Contracts) != 1 {
+			t.Fatalf("Analyze() contracts = %d, want 1", len(result.Contracts))
+		}
+		return result.Contracts[0]
+	}
+
+	first := analyze(2)
+	if err := store.UpsertContract(first); err != nil {
+		t.Fatalf("UpsertContract() error = %v", err)
+	}
+	if err := store.UpdateStatus(first.ID, ContractVerified); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	// Re-analysis produces a new ID; the stored contract is updated in place.
+	second := analyze(1)
+	if err := store.UpsertContract(second); err != nil {
+		t.Fatalf("UpsertContract() error = %v", err)
+	}
+
+	all, err := store.ListContracts(ContractFilter{})
+	if err != nil {
+		t.Fatalf("ListContracts() error = %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("ListContracts() = %d contracts, want 1", len(all))
+	}
+	got := all[0]
+	if got.ID != first.ID || got.Status != ContractVerified {
+		t.Errorf("contract = %s (%s), want %s (verified)", got.ID, got.Status, first.ID)
+	}
+	if len(got.FrontendCalls) != 1 {
+		t.Errorf("frontend calls = %d, want 1", len(got.FrontendCalls))
+	}
+}
+
+func TestStore_RemoveMissing(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "contracts.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	store := NewStore(db)
+	if err := store.CreateTables(); err != nil {
+		t.Fatalf("CreateTables() error = %v", err)
+	}
+
+	api := filepath.Join("/ws", "api")
+	analyze := func(paths ...string) []*Contract {
+		input := &AnalysisInput{}
+		for i, p := range paths {
+			input.Endpoints = append(input.Endpoints, EndpointInput{Method: "GET", Path: p, File: filepath.Join(api, "server.js"), Line: i + 1})
+			input.Calls = append(input.Calls, CallInput{Method: "GET", URL: p, File: "app.ts", Line: i + 1})
+		}
+		return NewAnalyzer().Analyze(input).Contracts
+	}
+	for _, c := range analyze("/api/users", "/api/orders") {
+		if err := store.UpsertContract(c); err != nil {
+			t.Fatalf("UpsertContract() error = %v", err)
+		}
+	}
+
+	// A contract from another directory is out of scope.
+	other := analyze("/admin/stats")[0]
+	other.Backend.File = filepath.Join("/ws", "admin", "server.js")
+	if err := store.UpsertContract(other); err != nil {
+		t.Fatalf("UpsertContract() error = %v", err)
+	}
+
+	removed, err := store.RemoveMissing(analyze("/api/users"), api)
+	if err != nil {
+		t.Fatalf("RemoveMissing() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("RemoveMissing() = %d, want 1", removed)
+	}
+	all, err := store.ListContracts(ContractFilter{})
+	if err != nil {
+		t.Fatalf("ListContracts() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, c := range all {
+		got[c.Endpoint] = true
+	}
+	if len(got) != 2 || !got["/api/users"] || !got["/admin/stats"] {
+		t.Errorf("remaining contracts = %v, want /api/users and /admin/stats", got)
+	}
+}
+
+func TestStore_RemoveMissingSpecOnly(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "contracts.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	store := NewStore(db)
+	if err := store.CreateTables(); err != nil {
+		t.Fatalf("CreateTables() error = %v", err)
+	}
+
+	api := filepath.Join("/ws", "api")
+	orders := filepath.Join(api, "openapi.yaml")
+	admin := filepath.Join("/ws", "docs", "admin.yaml")
+	other := filepath.Join("/ws", "other", "other.yaml")
+	analyze := func(spec ...EndpointInput) []*Contract {
+		return NewAnalyzer().Analyze(&AnalysisInput{Spec: spec}).Contracts
+	}
+	stored := analyze(
+		EndpointInput{Method: "GET", Path: "/api/orders", File: orders, Line: 4},
+		EndpointInput{Method: "GET", Path: "/admin/report", File: admin, Line: 4},
+		EndpointInput{Method: "GET", Path: "/admin/users", File: admin, Line: 12},
+		EndpointInput{Method: "GET", Path: "/other", File: other, Line: 4},
+	)
+	for _, c := range stored {
+		if c.Backend.File != "" {
+			t.Fatalf("spec-only contract has backend file %q", c.Backend.File)
+		}
+		if err := store.UpsertContract(c); err != nil {
+			t.Fatalf("UpsertContract() error = %v", err)
+		}
+	}
+
+	// openapi.yaml under dir was deleted and admin.yaml dropped /admin/report.
+	// other.yaml was not read, so its operations are left alone.
+	removed, err := store.RemoveMissing(analyze(EndpointInput{Method: "GET", Path: "/admin/users", File: admin, Line: 4}), api)
+	if err != nil {
+		t.Fatalf("RemoveMissing() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("RemoveMissing() = %d, want 2", removed)
+	}
+	all, err := store.ListContracts(ContractFilter{})
+	if err != nil {
+		t.Fatalf("ListContracts() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, c := range all {
+		got[c.Endpoint] = true
+	}
+	if len(got) != 2 || !got["/admin/users"] || !got["/other"] {
+		t.Errorf("remaining contracts = %v, want /admin/users and /other", got)
+	}
+}
+
+func TestStore_UpsertContractInMemoryDB(t *testing.T) {
+	// The memory database declares contract timestamps TEXT, so the second
+	// upsert reads first_seen back as a string.
+	mem, err := memory.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("memory.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = mem.Close() })
+
+	store := NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		t.Fatalf("CreateTables() error = %v", err)
+	}
+
+	input := &AnalysisInput{
+		Endpoints: []EndpointInput{{Method: "GET", Path: "/api/users", File: "handler.go", Line: 10}},
+		Calls:     []CallInput{{Method: "GET", URL: "/api/users", File: "app.ts", Line: 1}},
+	}
+	var ids []string
+	for i := 0; i < 2; i++ {
+		contract := NewAnalyzer().Analyze(input).Contracts[0]
+		if err := store.UpsertContract(contract); err != nil {
+			t.Fatalf("UpsertContract() #%d error = %v", i+1, err)
+		}
+		ids = append(ids, contract.ID)
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("second upsert ID = %s, want %s", ids[1], ids[0])
+	}
+
+	got, err := store.GetContract(ids[0])
+	if err != nil {
+		t.Fatalf("GetContract() error = %v", err)
+	}
+	if got == nil || got.FirstSeen.IsZero() {
+		t.Errorf("GetContract() = %+v, want a first-seen time", got)
+	}
+}
+
+func TestScanTime(t *testing.T) {
+	want := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, v := range []any{
+		want,
+		"2026-03-04 05:06:07",
+		"2026-03-04T05:06:07Z",
+		"2026-03-04 05:06:07+00:00",
+		"2026-03-04 05:06:07 +0000 UTC m=+0.012",
+	} {
+		var got time.Time
+		if err := (scanTime{&got}).Scan(v); err != nil || !got.Equal(want) {
+			t.Errorf("Scan(%v) = %v, %v", v, got, err)
+		}
+	}
+	var got time.Time
+	if err := (scanTime{&got}).Scan("yesterday"); err == nil {
+		t.Error("expected error for unparseable timestamp")
+	}
+}
+
+func TestStore_SpecRoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "contracts.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := NewStore(db)
+	if err := store.CreateTables(); err != nil {
+		t.Fatalf("CreateTables() error = %v", err)
+	}
+
+	schema := NewObjectSchema()
+	schema.AddProperty("id", NewPrimitiveSchema(SchemaTypeString), true)
+	result := NewAnalyzer().Analyze(&AnalysisInput{
+		Endpoints: []EndpointInput{{Method: "GET", Path: "/api/users", File: "handler.go", Line: 10}},
+		Spec: []EndpointInput{
+			{Method: "GET", Path: "/api/users", File: "openapi.yaml", Line: 4, Handler: "listUsers", ResponseSchema: schema},
+			{Method: "POST", Path: "/api/users", File: "openapi.yaml", Line: 12},
+		},
+	})
+	for _, c := range result.Contracts {
+		c.Mismatches = append(c.Mismatches, FieldMismatch{
+			FieldPath: "id", Type: MismatchMissingInBackend, Severity: SeverityError, Scope: ScopeSpecBackend,
+		})
+		if err := store.SaveContract(c); err != nil {
+			t.Fatalf("SaveContract() error = %v", err)
+		}
+	}
+
+	all, err := store.ListContracts(ContractFilter{})
+	if err != nil {
+		t.Fatalf("ListContracts() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListContracts() = %d contracts, want 2", len(all))
+	}
+	for _, c := range all {
+		if c.Spec == nil || c.Spec.File != "openapi.yaml" {
+			t.Errorf("%s %s: spec = %+v", c.Method, c.Endpoint, c.Spec)
+			continue
+		}
+		if c.Method == "GET" && (c.Spec.OperationID != "listUsers" || c.Spec.ResponseSchema == nil) {
+			t.Errorf("GET spec = %+v", c.Spec)
+		}
+		if c.Method == "POST" && c.Backend.File != "" {
+			t.Errorf("POST backend = %+v, want none", c.Backend)
+		}
+		if len(c.Mismatches) == 0 || c.Mismatches[len(c.Mismatches)-1].Scope != ScopeSpecBackend {
+			t.Errorf("%s mismatches = %+v", c.Method, c.Mismatches)
+		}
+	}
+}
@@ -56,6 +56,15 @@ const (
 	SeverityInfo    MismatchSeverity = "info"
 )
 
+// MismatchScope names the two sources a mismatch was found between.
+type MismatchScope string
+
+const (
+	ScopeBackendFrontend MismatchScope = ""              // backend code vs frontend calls
+	ScopeSpecBackend     MismatchScope = "spec_backend"  // OpenAPI spec vs backend code
+	ScopeSpecFrontend    MismatchScope = "spec_frontend" // OpenAPI spec vs frontend calls
+)
+
 // FieldMismatch represents a mismatch between frontend and backend for a specific field.
 type FieldMismatch struct {
 	ID           string           `json:"id"`
@@ -65,6 +74,7 @@ type FieldMismatch struct {
 	Description  string           `json:"description"`
 	BackendType  string           `json:"backend_type,omitempty"`
 	FrontendType string           `json:"frontend_type,omitempty"`
+	Scope        MismatchScope    `json:"scope,omitempty"` // Which two sources disagree
 }
 
 // NewObjectSchema creates a new object schema.
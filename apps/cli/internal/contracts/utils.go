@@ -3,6 +3,10 @@ package contracts
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // GenerateID generates a unique ID with the given prefix.
@@ -11,3 +15,61 @@ func GenerateID(prefix string) string {
 	_, _ = rand.Read(b)
 	return prefix + "_" + hex.EncodeToString(b)
 }
+
+// RelPath returns file relative to root with forward slashes, the form the
+// index and git diffs use. Contracts store the absolute paths the extractors
+// saw; files outside root are returned as they are.
+func RelPath(root, file string) string {
+	if file == "" || !filepath.IsAbs(file) {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// timeLayouts are the text forms timestamps are stored in: the SQLite
+// driver's own format, SQLite's datetime('now') and RFC 3339.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+}
+
+// scanTime scans a timestamp column into t. Columns declared DATETIME come
+// back as time.Time, but the memory database declares them TEXT.
+type scanTime struct{ t *time.Time }
+
+// Scan implements sql.Scanner.
+func (s scanTime) Scan(v any) error {
+	switch v := v.(type) {
+	case nil:
+		*s.t = time.Time{}
+		return nil
+	case time.Time:
+		*s.t = v
+		return nil
+	case string:
+		return s.parse(v)
+	case []byte:
+		return s.parse(string(v))
+	}
+	return fmt.Errorf("unsupported timestamp type %T", v)
+}
+
+func (s scanTime) parse(v string) error {
+	// time.Time.String appends the monotonic clock reading.
+	if i := strings.Index(v, " m="); i >= 0 {
+		v = v[:i]
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			*s.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unrecognized timestamp %q", v)
+}
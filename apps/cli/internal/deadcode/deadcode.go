@@ -0,0 +1,612 @@
+// Package deadcode finds symbols and files nothing uses, from the indexed
+// symbols, their call and reference relationships, and the import graph.
+// Test files, room entry points, main and init functions and the HTTP
+// handlers found by contract extraction are roots: they are never reported,
+// and whatever they use is live.
+package deadcode
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/depgraph"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+// Confidence is how sure the analysis is that code is unused.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"   // the name appears nowhere but its definition
+	ConfidenceMedium Confidence = "medium" // public API, a method, or named elsewhere in its file
+	ConfidenceLow    Confidence = "low"    // the name appears in other files
+)
+
+// ParseConfidence parses a --min-confidence value; empty means low.
+func ParseConfidence(s string) (Confidence, error) {
+	switch c := Confidence(strings.ToLower(s)); c {
+	case "":
+		return ConfidenceLow, nil
+	case ConfidenceHigh, ConfidenceMedium, ConfidenceLow:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown confidence %q (want high, medium or low)", s)
+}
+
+// AtLeast reports whether c is other or more confident.
+func (c Confidence) AtLeast(other Confidence) bool {
+	return c.rank() >= other.rank()
+}
+
+func (c Confidence) rank() int {
+	switch c {
+	case ConfidenceHigh:
+		return 2
+	case ConfidenceMedium:
+		return 1
+	}
+	return 0
+}
+
+// Score maps the confidence to 0-1 for proposals.
+func (c Confidence) Score() float64 {
+	switch c {
+	case ConfidenceHigh:
+		return 0.9
+	case ConfidenceMedium:
+		return 0.6
+	}
+	return 0.3
+}
+
+// Symbol is an unused symbol.
+type Symbol struct {
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	File       string     `json:"file"`
+	LineStart  int        `json:"lineStart"`
+	LineEnd    int        `json:"lineEnd"`
+	Exported   bool       `json:"exported"`
+	Confidence Confidence `json:"confidence"`
+	Reason     string     `json:"reason"`
+}
+
+// File is an unused file.
+type File struct {
+	Path       string     `json:"path"`
+	Symbols    int        `json:"symbols"` // analyzed symbols it defines
+	Confidence Confidence `json:"confidence"`
+	Reason     string     `json:"reason"`
+}
+
+// Counts tallies symbols and files by confidence.
+type Counts struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+func (c *Counts) add(conf Confidence) {
+	switch conf {
+	case ConfidenceHigh:
+		c.High++
+	case ConfidenceMedium:
+		c.Medium++
+	default:
+		c.Low++
+	}
+}
+
+// Report is the unused code of a workspace, most confident first.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Symbols     []Symbol  `json:"symbols"`
+	Files       []File    `json:"files"`
+	Counts      Counts    `json:"counts"`
+}
+
+// Filter returns the entries at or above min. A non-empty file keeps only
+// that file and its symbols.
+func (r *Report) Filter(min Confidence, file string) *Report {
+	out := &Report{GeneratedAt: r.GeneratedAt, Symbols: []Symbol{}, Files: []File{}}
+	for _, s := range r.Symbols {
+		if s.Confidence.AtLeast(min) && (file == "" || s.File == file) {
+			out.Symbols = append(out.Symbols, s)
+			out.Counts.add(s.Confidence)
+		}
+	}
+	for _, f := range r.Files {
+		if f.Confidence.AtLeast(min) && (file == "" || f.Path == file) {
+			out.Files = append(out.Files, f)
+			out.Counts.add(f.Confidence)
+		}
+	}
+	return out
+}
+
+// Input is what Build analyzes.
+type Input struct {
+	Symbols    []index.SymbolDefinition
+	References []index.SymbolReference
+	Languages  map[string]string // file -> language
+	// Unimported lists the files nothing imports that are not entry points.
+	Unimported []string
+	// Roots reports files whose symbols are all roots.
+	Roots func(file string) bool
+	// Handlers maps files to the names of the HTTP handlers they define.
+	Handlers map[string]map[string]bool
+	// Chunks walks the indexed text of every file.
+	Chunks func(fn func(path string, startLine int, content string) error) error
+}
+
+// Analyze reports the unused code of the index of the workspace at root.
+// mem may be nil, in which case contract handlers are not roots.
+func Analyze(root string, db *sql.DB, mem *memory.Memory) (*Report, error) {
+	symbols, err := index.ListSymbolDefinitions(db)
+	if err != nil {
+		return nil, fmt.Errorf("list symbols: %w", err)
+	}
+	references, err := index.ListSymbolReferences(db)
+	if err != nil {
+		return nil, fmt.Errorf("list references: %w", err)
+	}
+	languages, err := index.LoadFileLanguages(db)
+	if err != nil {
+		return nil, fmt.Errorf("load files: %w", err)
+	}
+	graph, err := depgraph.Analyze(root, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var roomEntries []string
+	for _, room := range playbook.LoadRooms(root) {
+		roomEntries = append(roomEntries, room.EntryPoints...)
+	}
+	handlers := make(map[string]map[string]bool)
+	if mem != nil {
+		list, err := listContracts(mem)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range list {
+			name := lastIdentifier(c.Backend.Handler)
+			if c.Backend.File == "" || name == "" {
+				continue
+			}
+			// Contracts store absolute paths; the index is workspace-relative.
+			file := contracts.RelPath(root, c.Backend.File)
+			if handlers[file] == nil {
+				handlers[file] = make(map[string]bool)
+			}
+			handlers[file][name] = true
+		}
+	}
+
+	return Build(&Input{
+		Symbols:    symbols,
+		References: references,
+		Languages:  languages,
+		Unimported: graph.DeadFiles,
+		Roots: func(file string) bool {
+			if depgraph.IsTestFile(file) {
+				return true
+			}
+			for _, e := range roomEntries {
+				e = strings.TrimSuffix(e, "/")
+				if file == e || strings.HasPrefix(file, e+"/") {
+					return true
+				}
+			}
+			return false
+		},
+		Handlers: handlers,
+		Chunks: func(fn func(path string, startLine int, content string) error) error {
+			return index.ForEachChunk(db, fn)
+		},
+	}), nil
+}
+
+// analyzedKinds are the symbol kinds that can be reported. Properties and
+// constructors are used through their type.
+var analyzedKinds = map[string]bool{
+	"function": true, "method": true, "class": true, "interface": true,
+	"type": true, "enum": true, "constant": true, "variable": true,
+}
+
+// callableKinds are used through calls, which every analyzed language
+// records. Other kinds are only seen as references in languages that
+// record those; elsewhere a mention of the name decides.
+var callableKinds = map[string]bool{"function": true, "method": true}
+
+// containerKinds are the kinds that can enclose a reference.
+var containerKinds = map[string]bool{
+	"function": true, "method": true, "constructor": true, "class": true,
+	"interface": true, "type": true, "enum": true,
+}
+
+// rootNames are run by the runtime or a framework rather than called.
+var rootNames = map[string]bool{"main": true, "init": true, "constructor": true}
+
+var identifier = regexp.MustCompile(`[A-Za-z_$][A-Za-z0-9_$]*`)
+
+// analysis is the state of one Build.
+type analysis struct {
+	in       *Input
+	symbols  []index.SymbolDefinition
+	byID     map[int64]int
+	byName   map[string][]int
+	byFile   map[string][]int
+	analyzed []bool
+	root     []bool
+	textual  []bool // used unless its name appears nowhere else
+}
+
+// Build analyzes in.
+func Build(in *Input) *Report {
+	a := &analysis{
+		in:      in,
+		symbols: in.Symbols,
+		byID:    make(map[int64]int, len(in.Symbols)),
+		byName:  make(map[string][]int),
+		byFile:  make(map[string][]int),
+	}
+	for i, s := range a.symbols {
+		a.byID[s.ID] = i
+		a.byName[s.Name] = append(a.byName[s.Name], i)
+		a.byFile[s.FilePath] = append(a.byFile[s.FilePath], i)
+	}
+
+	// Only languages with call tracking say anything about use.
+	tracked, referenced := make(map[string]bool), make(map[string]bool)
+	for _, r := range in.References {
+		if lang := in.Languages[r.SourceFile]; lang != "" {
+			tracked[lang] = true
+			referenced[lang] = referenced[lang] || r.Kind == "reference"
+		}
+	}
+	a.analyzed = make([]bool, len(a.symbols))
+	a.root = make([]bool, len(a.symbols))
+	a.textual = make([]bool, len(a.symbols))
+	for i, s := range a.symbols {
+		if !analyzedKinds[s.Kind] || !tracked[in.Languages[s.FilePath]] {
+			continue
+		}
+		if (s.Kind == "variable" || s.Kind == "constant") && a.enclosing(s.FilePath, s.LineStart, i) >= 0 {
+			continue // a local
+		}
+		a.analyzed[i] = true
+		a.root[i] = a.isRoot(&s)
+		a.textual[i] = !callableKinds[s.Kind] && !referenced[in.Languages[s.FilePath]]
+	}
+
+	dead, transitive := a.unused()
+	names := make(map[string]bool)
+	for i := range a.symbols {
+		if dead[i] || (a.textual[i] && !a.root[i]) {
+			names[a.symbols[i].Name] = true
+		}
+	}
+	mentions := a.mentions(names)
+	unmentioned := make(map[int]bool)
+	for i := range a.symbols {
+		if a.textual[i] && !a.root[i] && !a.mentionedOutside(i, mentions[a.symbols[i].Name]) {
+			dead[i], unmentioned[i] = true, true
+		}
+	}
+
+	report := &Report{GeneratedAt: time.Now().UTC(), Symbols: []Symbol{}, Files: []File{}}
+	confidence := make(map[int]Confidence, len(dead))
+	// Some symbols are indexed twice, e.g. decorated Python functions, and
+	// are reported once.
+	reported := make(map[string]bool)
+	for i := range a.symbols {
+		if !dead[i] {
+			continue
+		}
+		s := &a.symbols[i]
+		var conf Confidence
+		var why, reason string
+		switch {
+		case unmentioned[i]:
+			conf, reason = ConfidenceHigh, "the name appears nowhere but its definition"
+			if s.Exported {
+				conf, why = ConfidenceMedium, "exported, so it may be used outside the workspace"
+			}
+		case transitive[i]:
+			conf, why = a.confidence(i, mentions[s.Name])
+			reason = "only used by unused code"
+		default:
+			conf, why = a.confidence(i, mentions[s.Name])
+			reason = "never called or referenced"
+		}
+		if why != "" {
+			reason += "; " + why
+		}
+		confidence[i] = conf
+		key := s.FilePath + "\x00" + strconv.Itoa(s.LineStart) + "\x00" + s.Name
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		report.Symbols = append(report.Symbols, Symbol{
+			Name: s.Name, Kind: s.Kind, File: s.FilePath, LineStart: s.LineStart, LineEnd: s.LineEnd,
+			Exported: s.Exported, Confidence: conf, Reason: reason,
+		})
+		report.Counts.add(conf)
+	}
+	report.Files = a.files(dead, confidence)
+	for _, f := range report.Files {
+		report.Counts.add(f.Confidence)
+	}
+
+	sort.SliceStable(report.Symbols, func(i, j int) bool {
+		si, sj := &report.Symbols[i], &report.Symbols[j]
+		if si.Confidence != sj.Confidence {
+			return si.Confidence.rank() > sj.Confidence.rank()
+		}
+		if si.File != sj.File {
+			return si.File < sj.File
+		}
+		return si.LineStart < sj.LineStart
+	})
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		fi, fj := &report.Files[i], &report.Files[j]
+		if fi.Confidence != fj.Confidence {
+			return fi.Confidence.rank() > fj.Confidence.rank()
+		}
+		return fi.Path < fj.Path
+	})
+	return report
+}
+
+func (a *analysis) isRoot(s *index.SymbolDefinition) bool {
+	if a.in.Roots != nil && a.in.Roots(s.FilePath) {
+		return true
+	}
+	if rootNames[s.Name] || (strings.HasPrefix(s.Name, "__") && strings.HasSuffix(s.Name, "__")) {
+		return true
+	}
+	return a.in.Handlers[s.FilePath][s.Name]
+}
+
+// enclosing returns the innermost container symbol around line in file,
+// other than skip, or -1.
+func (a *analysis) enclosing(file string, line, skip int) int {
+	best := -1
+	for _, i := range a.byFile[file] {
+		s := &a.symbols[i]
+		if i == skip || !containerKinds[s.Kind] || line < s.LineStart || line > s.LineEnd {
+			continue
+		}
+		if best < 0 || s.LineEnd-s.LineStart < a.symbols[best].LineEnd-a.symbols[best].LineStart {
+			best = i
+		}
+	}
+	return best
+}
+
+// unused returns the analyzed symbols nothing live calls or references,
+// leaving out textual ones. Referrers are
+// the enclosing symbols of calls and references; references outside any
+// symbol, such as in a top-level initializer, keep their target live.
+// Unresolved calls count for every symbol of the called name. Symbols whose
+// only referrers are unused become unused too, which transitive records.
+func (a *analysis) unused() (dead, transitive map[int]bool) {
+	const outside = -1
+	referrers := make(map[int]map[int]bool)
+	for _, r := range a.in.References {
+		source, ok := outside, false
+		if r.SourceSymbolID != 0 {
+			source, ok = a.byID[r.SourceSymbolID]
+		}
+		if !ok {
+			source = a.enclosing(r.SourceFile, r.Line, -1)
+		}
+
+		var targets []int
+		if t, ok := a.byID[r.TargetSymbolID]; ok && r.TargetSymbolID != 0 {
+			targets = []int{t}
+		} else {
+			targets = a.byName[lastIdentifier(r.Target)]
+		}
+		for _, t := range targets {
+			if t == source || !a.analyzed[t] || a.textual[t] {
+				continue
+			}
+			if referrers[t] == nil {
+				referrers[t] = make(map[int]bool)
+			}
+			referrers[t][source] = true
+		}
+	}
+
+	dead = make(map[int]bool)
+	for i := range a.symbols {
+		if a.analyzed[i] && !a.root[i] && !a.textual[i] && len(referrers[i]) == 0 {
+			dead[i] = true
+		}
+	}
+	transitive = make(map[int]bool)
+	for changed := true; changed; {
+		changed = false
+		for i := range a.symbols {
+			if !a.analyzed[i] || a.root[i] || a.textual[i] || dead[i] {
+				continue
+			}
+			live := false
+			for source := range referrers[i] {
+				if source == outside || !dead[source] {
+					live = true
+					break
+				}
+			}
+			if !live {
+				dead[i], transitive[i], changed = true, true, true
+			}
+		}
+	}
+	return dead, transitive
+}
+
+// mentions finds the lines that contain names, per name and file.
+func (a *analysis) mentions(names map[string]bool) map[string]map[string][]int {
+	out := make(map[string]map[string][]int)
+	if a.in.Chunks == nil || len(names) == 0 {
+		return out
+	}
+	_ = a.in.Chunks(func(file string, start int, content string) error {
+		for n, line := range strings.Split(content, "\n") {
+			for _, word := range identifier.FindAllString(line, -1) {
+				if !names[word] {
+					continue
+				}
+				if out[word] == nil {
+					out[word] = make(map[string][]int)
+				}
+				out[word][file] = append(out[word][file], start+n)
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// confidence grades an unused symbol by where else its name appears: the
+// index cannot see every use, such as functions passed as values.
+func (a *analysis) confidence(i int, mentions map[string][]int) (Confidence, string) {
+	s := &a.symbols[i]
+	others := 0
+	for file := range mentions {
+		if file != s.FilePath {
+			others++
+		}
+	}
+	if others > 0 {
+		return ConfidenceLow, fmt.Sprintf("the name appears in %d other file(s)", others)
+	}
+	if s.Exported {
+		return ConfidenceMedium, "exported, so it may be used outside the workspace"
+	}
+	if s.Kind == "method" {
+		return ConfidenceMedium, "methods may be called through an interface or override"
+	}
+	for _, line := range mentions[s.FilePath] {
+		if !a.definesAt(s.FilePath, s.Name, line) {
+			return ConfidenceMedium, "the name appears elsewhere in its file"
+		}
+	}
+	return ConfidenceHigh, ""
+}
+
+// mentionedOutside reports whether the name of symbol i appears anywhere
+// but in a definition of that name.
+func (a *analysis) mentionedOutside(i int, mentions map[string][]int) bool {
+	s := &a.symbols[i]
+	for file, lines := range mentions {
+		for _, line := range lines {
+			if !a.definesAt(file, s.Name, line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// definesAt reports whether line is part of a definition of name in file,
+// doc comment included.
+func (a *analysis) definesAt(file, name string, line int) bool {
+	for _, i := range a.byFile[file] {
+		s := &a.symbols[i]
+		if s.Name == name && line >= s.LineStart-s.DocLines && line <= s.LineEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// files reports files nothing imports whose symbols are all unused, and
+// imported files whose symbols are all unused; the latter are at most of
+// medium confidence, as importing a file can have side effects.
+func (a *analysis) files(dead map[int]bool, confidence map[int]Confidence) []File {
+	unimported := make(map[string]bool, len(a.in.Unimported))
+	for _, f := range a.in.Unimported {
+		unimported[f] = true
+	}
+
+	candidates := make(map[string]bool, len(unimported))
+	for f := range unimported {
+		candidates[f] = true
+	}
+	for f := range a.byFile {
+		candidates[f] = true
+	}
+
+	out := []File{}
+	for f := range candidates {
+		if a.in.Roots != nil && a.in.Roots(f) {
+			continue
+		}
+		total, unused := 0, 0
+		conf := ConfidenceHigh
+		for _, i := range a.byFile[f] {
+			if !a.analyzed[i] {
+				continue
+			}
+			total++
+			if dead[i] {
+				unused++
+				if !confidence[i].AtLeast(conf) {
+					conf = confidence[i]
+				}
+			}
+		}
+		switch {
+		case unimported[f] && total == 0:
+			out = append(out, File{Path: f, Confidence: ConfidenceMedium, Reason: "nothing imports it"})
+		case unimported[f] && unused == total:
+			out = append(out, File{Path: f, Symbols: total, Confidence: conf,
+				Reason: fmt.Sprintf("nothing imports it and none of its %d symbol(s) are used", total)})
+		case total > 0 && unused == total:
+			if conf == ConfidenceHigh {
+				conf = ConfidenceMedium
+			}
+			out = append(out, File{Path: f, Symbols: total, Confidence: conf,
+				Reason: fmt.Sprintf("none of its %d symbol(s) are used", total)})
+		}
+	}
+	return out
+}
+
+// listContracts returns the stored contracts without creating the contract
+// tables: a workspace that was never scanned for contracts has none.
+func listContracts(mem *memory.Memory) ([]*contracts.Contract, error) {
+	store := contracts.NewStore(mem.DB())
+	ok, err := store.HasTables()
+	if err != nil || !ok {
+		return nil, err
+	}
+	list, err := store.ListContracts(contracts.ContractFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list contracts: %w", err)
+	}
+	return list, nil
+}
+
+// lastIdentifier returns the called name of a call target such as
+// "repo.Find" or "Foo::bar".
+func lastIdentifier(target string) string {
+	words := identifier.FindAllString(target, -1)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
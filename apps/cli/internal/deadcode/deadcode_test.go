@@ -0,0 +1,267 @@
+package deadcode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func def(id int64, name, kind, file string, start, end int, exported bool) index.SymbolDefinition {
+	return index.SymbolDefinition{ID: id, Name: name, Kind: kind, FilePath: file, LineStart: start, LineEnd: end, Exported: exported}
+}
+
+func testInput() *Input {
+	return &Input{
+		Symbols: []index.SymbolDefinition{
+			def(1, "main", "function", "cmd/main.go", 1, 10, false),
+			def(2, "Run", "function", "svc/svc.go", 1, 10, true),
+			def(3, "helper", "function", "svc/svc.go", 12, 20, false),
+			def(4, "orphan", "function", "svc/svc.go", 22, 25, false),
+			def(5, "chain1", "function", "svc/svc.go", 27, 30, false),
+			def(6, "chain2", "function", "svc/svc.go", 32, 34, false),
+			def(7, "recurse", "function", "svc/svc.go", 40, 45, false),
+			def(8, "Unused", "function", "svc/svc.go", 50, 52, true),
+			def(9, "Close", "method", "svc/svc.go", 60, 62, true),
+			def(10, "named", "function", "svc/mention.go", 1, 3, false),
+			def(11, "TestRun", "function", "svc/svc_test.go", 1, 5, true),
+			def(12, "helperForTest", "function", "svc/svc_test.go", 7, 9, false),
+			def(13, "f", "function", "svc/local.go", 1, 10, false),
+			def(14, "x", "variable", "svc/local.go", 3, 3, false),
+			def(15, "limit", "constant", "svc/local.go", 12, 12, false),
+			def(16, "Old", "function", "old/old.go", 1, 4, true),
+			def(17, "ServeUsers", "function", "api/h.go", 1, 4, true),
+			def(18, "render", "function", "web/x.ts", 1, 4, false),
+			def(19, "maxUsers", "constant", "api/h.go", 6, 6, false),
+			def(20, "orphan", "function", "svc/svc.go", 22, 25, false), // indexed twice, reported once
+		},
+		References: []index.SymbolReference{
+			{SourceFile: "cmd/main.go", SourceSymbolID: 1, Line: 3, Target: "svc.Run"},
+			{SourceFile: "svc/svc.go", SourceSymbolID: 2, Line: 2, Target: "helper", TargetSymbolID: 3},
+			{SourceFile: "svc/svc.go", Line: 28, Target: "chain2"},
+			{SourceFile: "svc/svc.go", SourceSymbolID: 7, Line: 42, Target: "recurse", TargetSymbolID: 7},
+			{SourceFile: "svc/local.go", Line: 5, Target: "limit"},
+		},
+		Languages: map[string]string{
+			"cmd/main.go": "go", "svc/svc.go": "go", "svc/mention.go": "go", "svc/svc_test.go": "go",
+			"svc/local.go": "go", "old/old.go": "go", "api/h.go": "go", "svc/other.go": "go",
+			"web/x.ts": "typescript",
+		},
+		Unimported: []string{"old/old.go"},
+		Roots:      func(file string) bool { return strings.HasSuffix(file, "_test.go") },
+		Handlers:   map[string]map[string]bool{"api/h.go": {"ServeUsers": true}},
+		Chunks: func(fn func(path string, startLine int, content string) error) error {
+			if err := fn("svc/svc.go", 22, "func orphan() {}"); err != nil {
+				return err
+			}
+			return fn("svc/other.go", 1, "var cb, n = named, maxUsers")
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	report := Build(testInput())
+
+	got := make(map[string]Confidence)
+	for _, s := range report.Symbols {
+		got[s.Name] = s.Confidence
+	}
+	want := map[string]Confidence{
+		"orphan":  ConfidenceHigh,
+		"chain1":  ConfidenceHigh,
+		"chain2":  ConfidenceHigh,
+		"recurse": ConfidenceHigh,
+		"f":       ConfidenceHigh,
+		"limit":   ConfidenceHigh,
+		"Unused":  ConfidenceMedium,
+		"Close":   ConfidenceMedium,
+		"Old":     ConfidenceMedium,
+		"named":   ConfidenceLow,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols = %v, want %v", got, want)
+	}
+	for _, s := range report.Symbols {
+		if s.Name == "chain2" && !strings.HasPrefix(s.Reason, "only used by unused code") {
+			t.Errorf("chain2 reason = %q", s.Reason)
+		}
+	}
+	if report.Symbols[0].Confidence != ConfidenceHigh || report.Symbols[len(report.Symbols)-1].Name != "named" {
+		t.Errorf("symbols not sorted by confidence: %+v", report.Symbols)
+	}
+
+	files := make(map[string]Confidence)
+	for _, f := range report.Files {
+		files[f.Path] = f.Confidence
+	}
+	wantFiles := map[string]Confidence{
+		"old/old.go":     ConfidenceMedium,
+		"svc/local.go":   ConfidenceMedium,
+		"svc/mention.go": ConfidenceLow,
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files = %v, want %v", files, wantFiles)
+	}
+	if report.Counts != (Counts{High: 6, Medium: 5, Low: 2}) {
+		t.Errorf("counts = %+v", report.Counts)
+	}
+
+	filtered := report.Filter(ConfidenceHigh, "svc/local.go")
+	if len(filtered.Symbols) != 2 || len(filtered.Files) != 0 || filtered.Counts.High != 2 {
+		t.Errorf("filtered = %+v", filtered)
+	}
+}
+
+func TestLastIdentifier(t *testing.T) {
+	for target, want := range map[string]string{
+		"svc.Run":    "Run",
+		"Foo::bar":   "bar",
+		"this.$emit": "$emit",
+		"()":         "",
+	} {
+		if got := lastIdentifier(target); got != want {
+			t.Errorf("lastIdentifier(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestPropose(t *testing.T) {
+	mem, err := memory.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	report := Build(testInput())
+	ids, duplicates, err := Propose(mem, report, ConfidenceMedium)
+	if err != nil {
+		t.Fatalf("Propose() error: %v", err)
+	}
+	// svc/svc.go, svc/local.go and old/old.go; svc/mention.go is low.
+	if len(ids) != 3 || duplicates != 0 {
+		t.Fatalf("ids = %v, duplicates = %d", ids, duplicates)
+	}
+
+	p, err := mem.GetProposal(ids[0])
+	if err != nil {
+		t.Fatalf("GetProposal() error: %v", err)
+	}
+	if p.ProposedAs != memory.ProposedAsLearning || p.Scope != "file" || p.ScopePath != "old/old.go" || p.Source != "dead-code" {
+		t.Errorf("proposal = %+v", p)
+	}
+	if !strings.Contains(p.Content, "old/old.go appears to be unused") || p.ClassificationConfidence != 0.6 {
+		t.Errorf("content = %q, confidence = %v", p.Content, p.ClassificationConfidence)
+	}
+
+	if ids, duplicates, _ := Propose(mem, report, ConfidenceMedium); len(ids) != 0 || duplicates != 3 {
+		t.Errorf("second Propose: ids = %v, duplicates = %d", ids, duplicates)
+	}
+
+	// Code that only moved is still the same proposal.
+	for i := range report.Symbols {
+		report.Symbols[i].LineStart += 5
+		report.Symbols[i].LineEnd += 5
+	}
+	if ids, duplicates, _ := Propose(mem, report, ConfidenceMedium); len(ids) != 0 || duplicates != 3 {
+		t.Errorf("Propose after lines moved: ids = %v, duplicates = %d", ids, duplicates)
+	}
+}
+
+func TestAnalyzeWithoutContractTables(t *testing.T) {
+	root := t.TempDir()
+	db, err := index.Open(filepath.Join(root, "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	// Memory databases from before the contract migration have no tables.
+	for _, table := range []string{"contract_mismatches", "contract_frontend_calls", "contracts"} {
+		if _, err := mem.DB().ExecContext(context.Background(), "DROP TABLE "+table); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Analyze(root, db, mem); err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if ok, err := contracts.NewStore(mem.DB()).HasTables(); err != nil || ok {
+		t.Errorf("HasTables() = %v, %v; want the contract tables left uncreated", ok, err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":  "package main\n\nfunc main() {\n\thelper()\n}\n\nfunc helper() {}\n",
+		"api/h.go": "package api\n\nfunc serveUsers() {}\n\nfunc unusedGo() {}\n",
+		"app.py":   "def deco(f):\n    return f\n\n\n@deco\ndef unused_py():\n    pass\n\n\ndeco(None)\n",
+	}
+	for name, content := range files {
+		full := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db, err := index.Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	records, err := index.BuildFileRecords(root, config.Guardrails{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.WriteScan(db, root, records, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	// Contract scans store the handler's absolute path.
+	result := contracts.NewAnalyzer().Analyze(&contracts.AnalysisInput{
+		Endpoints: []contracts.EndpointInput{{Method: "GET", Path: "/users", File: filepath.Join(root, "api", "h.go"), Line: 3, Handler: "serveUsers"}},
+		Calls:     []contracts.CallInput{{Method: "GET", URL: "/users", File: filepath.Join(root, "web", "app.ts"), Line: 1}},
+	})
+	for _, c := range result.Contracts {
+		if err := contracts.NewStore(mem.DB()).UpsertContract(c); err != nil {
+			t.Fatalf("UpsertContract() error: %v", err)
+		}
+	}
+
+	report, err := Analyze(root, db, mem)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	got := make(map[string]int)
+	for _, s := range report.Symbols {
+		got[s.Name]++
+	}
+	if got["serveUsers"] != 0 {
+		t.Errorf("contract handler reported: %+v", report.Symbols)
+	}
+	if got["unusedGo"] != 1 {
+		t.Errorf("unusedGo reported %d times: %+v", got["unusedGo"], report.Symbols)
+	}
+	if got["unused_py"] != 1 {
+		t.Errorf("decorated unused_py reported %d times, want once: %+v", got["unused_py"], report.Symbols)
+	}
+}
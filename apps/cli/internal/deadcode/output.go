@@ -0,0 +1,162 @@
+package deadcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// Finding rule IDs.
+const (
+	RuleSymbol = "deadcode/symbol"
+	RuleFile   = "deadcode/file"
+
+	// proposalSource marks proposals created from dead-code analysis.
+	proposalSource = "dead-code"
+)
+
+// Findings reports the unused files and symbols. High-confidence entries
+// are warnings, the rest notes.
+func (r *Report) Findings() []findings.Finding {
+	list := make([]findings.Finding, 0, len(r.Files)+len(r.Symbols))
+	for i := range r.Files {
+		f := &r.Files[i]
+		list = append(list, findings.Finding{
+			RuleID:      RuleFile,
+			RuleName:    "UnusedFile",
+			Description: "File that nothing imports or whose symbols are all unused",
+			Level:       findingLevel(f.Confidence),
+			Message:     fmt.Sprintf("unused file (%s confidence): %s", f.Confidence, f.Reason),
+			Location:    findings.Location{Path: f.Path},
+			Properties:  map[string]any{"confidence": string(f.Confidence)},
+		})
+	}
+	for i := range r.Symbols {
+		s := &r.Symbols[i]
+		list = append(list, findings.Finding{
+			RuleID:      RuleSymbol,
+			RuleName:    "UnusedSymbol",
+			Description: "Symbol that nothing live calls or references",
+			Level:       findingLevel(s.Confidence),
+			Message:     fmt.Sprintf("unused %s %s (%s confidence): %s", s.Kind, s.Name, s.Confidence, s.Reason),
+			Location:    findings.Location{Path: s.File, LineStart: s.LineStart, LineEnd: s.LineEnd},
+			Properties:  map[string]any{"confidence": string(s.Confidence), "exported": s.Exported},
+		})
+	}
+	return list
+}
+
+func findingLevel(c Confidence) string {
+	if c == ConfidenceHigh {
+		return findings.LevelWarning
+	}
+	return findings.LevelNote
+}
+
+// Propose files a learning proposal per file with unused code at or above
+// min, for a human to confirm before deleting it. It returns the IDs of
+// the new proposals and how many already existed.
+func Propose(mem *memory.Memory, r *Report, min Confidence) (ids []string, duplicates int, err error) {
+	filtered := r.Filter(min, "")
+	unusedFiles := make(map[string]*File)
+	for i := range filtered.Files {
+		unusedFiles[filtered.Files[i].Path] = &filtered.Files[i]
+	}
+	bySymbolFile := make(map[string][]Symbol)
+	for _, s := range filtered.Symbols {
+		bySymbolFile[s.File] = append(bySymbolFile[s.File], s)
+	}
+	paths := make([]string, 0, len(bySymbolFile)+len(unusedFiles))
+	for p := range bySymbolFile {
+		paths = append(paths, p)
+	}
+	for p := range unusedFiles {
+		if _, ok := bySymbolFile[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		content, rationale, conf := proposalText(p, unusedFiles[p], bySymbolFile[p])
+		dedupeKey := memory.GenerateDedupeKey(memory.ProposedAsLearning,
+			proposalKey(unusedFiles[p] != nil, bySymbolFile[p]), "file", p)
+		if existing, _ := mem.CheckDuplicateProposal(dedupeKey); existing != nil {
+			duplicates++
+			continue
+		}
+		evidence, err := json.Marshal(memory.EvidenceRef{
+			Extractor:   proposalSource,
+			Files:       []string{p},
+			Confidence:  conf.Score(),
+			Explanation: rationale,
+		})
+		if err != nil {
+			return ids, duplicates, err
+		}
+		id, err := mem.AddProposal(memory.Proposal{
+			ProposedAs:               memory.ProposedAsLearning,
+			Content:                  content,
+			Context:                  "Found by dead-code analysis of indexed symbols, calls and imports; confirm before deleting.",
+			Rationale:                rationale,
+			Scope:                    "file",
+			ScopePath:                p,
+			Source:                   proposalSource,
+			EvidenceRefs:             string(evidence),
+			ClassificationConfidence: conf.Score(),
+			DedupeKey:                dedupeKey,
+		})
+		if err != nil {
+			return ids, duplicates, fmt.Errorf("propose cleanup of %s: %w", p, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, duplicates, nil
+}
+
+// proposalKey names the unused code of one file without line numbers, so
+// edits that only move the code don't make the proposal look new.
+func proposalKey(fileUnused bool, symbols []Symbol) string {
+	names := make([]string, 0, len(symbols)+1)
+	if fileUnused {
+		names = append(names, "file")
+	}
+	for _, s := range symbols {
+		names = append(names, s.Kind+" "+s.Name)
+	}
+	sort.Strings(names)
+	return proposalSource + ": " + strings.Join(names, ", ")
+}
+
+// proposalText describes the unused code of one file and returns the
+// lowest confidence among it.
+func proposalText(path string, file *File, symbols []Symbol) (content, rationale string, conf Confidence) {
+	conf = ConfidenceHigh
+	var reasons []string
+	if file != nil {
+		conf = file.Confidence
+		reasons = append(reasons, fmt.Sprintf("%s: %s (%s confidence)", path, file.Reason, file.Confidence))
+	}
+	names := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		names = append(names, fmt.Sprintf("%s %s (line %d)", s.Kind, s.Name, s.LineStart))
+		reasons = append(reasons, fmt.Sprintf("%s: %s (%s confidence)", s.Name, s.Reason, s.Confidence))
+		if !s.Confidence.AtLeast(conf) {
+			conf = s.Confidence
+		}
+	}
+
+	switch {
+	case file != nil && len(names) == 0:
+		content = fmt.Sprintf("%s appears to be unused and can likely be deleted.", path)
+	case file != nil:
+		content = fmt.Sprintf("%s appears to be unused and can likely be deleted, including %s.", path, strings.Join(names, ", "))
+	default:
+		content = fmt.Sprintf("%s has unused code that can likely be removed: %s.", path, strings.Join(names, ", "))
+	}
+	return content, strings.Join(reasons, "\n"), conf
+}
@@ -0,0 +1,492 @@
+// Package depgraph analyzes the resolved import graph: import cycles,
+// coupling and instability per package, files nothing imports, and the
+// files the graph falls apart without.
+package depgraph
+
+import (
+	"database/sql"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/playbook"
+)
+
+// maxBridges bounds how many bridges a report lists.
+const maxBridges = 20
+
+// Package is a directory of source files and its coupling to the others.
+type Package struct {
+	Name        string  `json:"name"` // directory, "." for the workspace root
+	Files       int     `json:"files"`
+	Afferent    int     `json:"afferent"`    // packages that import this one
+	Efferent    int     `json:"efferent"`    // packages this one imports
+	Instability float64 `json:"instability"` // efferent / (afferent + efferent)
+}
+
+// Dependency counts the imports from one package to another.
+type Dependency struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Imports int    `json:"imports"`
+}
+
+// Cycle is a strongly connected set of files: each one reaches all the
+// others through imports. Go packages appear as their directory.
+type Cycle struct {
+	Files    []string `json:"files"`
+	Packages []string `json:"packages"`
+}
+
+// Bridge is a file, or a Go package, that splits the import graph if
+// removed.
+type Bridge struct {
+	Path    string `json:"path"`
+	Package string `json:"package"`
+	Cuts    int    `json:"cuts"` // files cut off from the rest of the graph
+}
+
+// Report is the analysis of a workspace's import graph.
+type Report struct {
+	GeneratedAt   time.Time    `json:"generatedAt"`
+	Files         int          `json:"files"`   // source files in the graph
+	Imports       int          `json:"imports"` // resolved imports between them
+	Cycles        []Cycle      `json:"cycles"`  // largest first
+	PackageCycles [][]string   `json:"packageCycles"`
+	Packages      []Package    `json:"packages"` // most coupled first
+	Dependencies  []Dependency `json:"dependencies"`
+	DeadFiles     []string     `json:"deadFiles"`
+	Bridges       []Bridge     `json:"bridges"` // most cuts first
+}
+
+// Analyze builds the report for the index of the workspace at root. Room
+// entry points, files defining main, tests and conventional entry files
+// such as index.ts or __main__.py are never reported as dead.
+func Analyze(root string, db *sql.DB) (*Report, error) {
+	edges, err := index.LoadImportGraph(db, root)
+	if err != nil {
+		return nil, fmt.Errorf("load import graph: %w", err)
+	}
+	languages, err := index.LoadFileLanguages(db)
+	if err != nil {
+		return nil, fmt.Errorf("load files: %w", err)
+	}
+	mains, err := index.GetSymbolsByName(db, "main", 10000)
+	if err != nil {
+		return nil, fmt.Errorf("find main functions: %w", err)
+	}
+
+	entries := make(map[string]bool)
+	mainPackages := make(map[string]bool)
+	for _, s := range mains {
+		if s.Kind != "function" {
+			continue
+		}
+		entries[s.FilePath] = true
+		if languages[s.FilePath] == "go" {
+			mainPackages[path.Dir(s.FilePath)] = true
+		}
+	}
+	var roomEntries []string
+	for _, room := range playbook.LoadRooms(root) {
+		roomEntries = append(roomEntries, room.EntryPoints...)
+	}
+
+	return Build(edges, languages, func(file string) bool {
+		if entries[file] || IsEntryFile(file) {
+			return true
+		}
+		if languages[file] == "go" && mainPackages[path.Dir(file)] {
+			return true
+		}
+		for _, e := range roomEntries {
+			e = strings.TrimSuffix(e, "/")
+			if file == e || strings.HasPrefix(file, e+"/") {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// Build analyzes the given edges. languages maps files to their language;
+// the graph covers the files of every language that has a resolved import.
+// entry reports files that are used without being imported.
+func Build(edges []index.ImportEdge, languages map[string]string, entry func(file string) bool) *Report {
+	graphLanguages := make(map[string]bool)
+	for _, e := range edges {
+		graphLanguages[languages[e.Source]] = true
+	}
+	var files []string
+	for f, lang := range languages {
+		if lang != "" && graphLanguages[lang] {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+
+	// Duplicate imports of the same file count once, and an import of a Go
+	// package counts once however many of its files it has edges to.
+	inGraph := make(map[string]bool, len(files))
+	for _, f := range files {
+		inGraph[f] = true
+	}
+	seen := make(map[[2]string]bool)
+	statements := make(map[[2]string]bool)
+	var unique []index.ImportEdge
+	for _, e := range edges {
+		key := [2]string{e.Source, e.Target}
+		if !inGraph[e.Source] || !inGraph[e.Target] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		statements[[2]string{e.Source, e.Import}] = true
+		unique = append(unique, e)
+	}
+
+	report := &Report{
+		GeneratedAt: time.Now().UTC(),
+		Files:       len(files),
+		Imports:     len(statements),
+	}
+	nodes, weights := nodeGraph(files, unique, languages)
+	report.Cycles = fileCycles(nodes)
+	report.Packages, report.Dependencies, report.PackageCycles = packages(files, unique)
+	report.DeadFiles = deadFiles(files, unique, languages, entry)
+	report.Bridges = bridges(nodes, weights, inGraph)
+	return report
+}
+
+// nodeGraph is the file graph with every Go package collapsed into one node
+// named after its directory: Go imports name a package, not a file. The
+// weights count the files of each node.
+func nodeGraph(files []string, edges []index.ImportEdge, languages map[string]string) (map[string][]string, map[string]int) {
+	node := func(file string) string {
+		if languages[file] == "go" {
+			return path.Dir(file)
+		}
+		return file
+	}
+	graph := make(map[string][]string)
+	weights := make(map[string]int)
+	for _, f := range files {
+		n := node(f)
+		if _, ok := graph[n]; !ok {
+			graph[n] = nil
+		}
+		weights[n]++
+	}
+	linked := make(map[[2]string]bool)
+	for _, e := range edges {
+		from, to := node(e.Source), node(e.Target)
+		key := [2]string{from, to}
+		if from == to || linked[key] {
+			continue
+		}
+		linked[key] = true
+		graph[from] = append(graph[from], to)
+	}
+	return graph, weights
+}
+
+func fileCycles(graph map[string][]string) []Cycle {
+	_, sccs := StronglyConnected(graph)
+	cycles := make([]Cycle, 0, len(sccs))
+	for _, scc := range sccs {
+		pkgs := make(map[string]bool)
+		for _, f := range scc {
+			pkgs[path.Dir(f)] = true
+		}
+		cycles = append(cycles, Cycle{Files: scc, Packages: sortedKeys(pkgs)})
+	}
+	sort.SliceStable(cycles, func(i, j int) bool { return len(cycles[i].Files) > len(cycles[j].Files) })
+	return cycles
+}
+
+func packages(files []string, edges []index.ImportEdge) ([]Package, []Dependency, [][]string) {
+	sizes := make(map[string]int)
+	for _, f := range files {
+		sizes[path.Dir(f)]++
+	}
+
+	imports := make(map[[2]string]int)
+	counted := make(map[[2]string]bool)
+	afferent := make(map[string]map[string]bool)
+	efferent := make(map[string]map[string]bool)
+	graph := make(map[string][]string, len(sizes))
+	for _, e := range edges {
+		from, to := path.Dir(e.Source), path.Dir(e.Target)
+		if from == to {
+			continue
+		}
+		key := [2]string{from, to}
+		if imports[key] == 0 {
+			graph[from] = append(graph[from], to)
+		}
+		if statement := [2]string{e.Source, e.Import}; !counted[statement] {
+			counted[statement] = true
+			imports[key]++
+		}
+		if afferent[to] == nil {
+			afferent[to] = make(map[string]bool)
+		}
+		afferent[to][from] = true
+		if efferent[from] == nil {
+			efferent[from] = make(map[string]bool)
+		}
+		efferent[from][to] = true
+	}
+
+	pkgs := make([]Package, 0, len(sizes))
+	for name, n := range sizes {
+		p := Package{Name: name, Files: n, Afferent: len(afferent[name]), Efferent: len(efferent[name])}
+		if total := p.Afferent + p.Efferent; total > 0 {
+			p.Instability = round(float64(p.Efferent) / float64(total))
+		}
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		ci, cj := pkgs[i].Afferent+pkgs[i].Efferent, pkgs[j].Afferent+pkgs[j].Efferent
+		if ci != cj {
+			return ci > cj
+		}
+		return pkgs[i].Name < pkgs[j].Name
+	})
+
+	deps := make([]Dependency, 0, len(imports))
+	for k, n := range imports {
+		deps = append(deps, Dependency{From: k[0], To: k[1], Imports: n})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].From != deps[j].From {
+			return deps[i].From < deps[j].From
+		}
+		return deps[i].To < deps[j].To
+	})
+
+	_, cycles := StronglyConnected(graph)
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+	return pkgs, deps, cycles
+}
+
+// deadFiles lists the files nothing imports. Go imports name a package, so
+// importing one file of a Go package counts for all of its files.
+func deadFiles(files []string, edges []index.ImportEdge, languages map[string]string, entry func(string) bool) []string {
+	imported := make(map[string]bool)
+	goPackages := make(map[string]bool)
+	for _, e := range edges {
+		imported[e.Target] = true
+		if languages[e.Target] == "go" {
+			goPackages[path.Dir(e.Target)] = true
+		}
+	}
+	dead := []string{}
+	for _, f := range files {
+		if imported[f] || (languages[f] == "go" && goPackages[path.Dir(f)]) || entry(f) {
+			continue
+		}
+		dead = append(dead, f)
+	}
+	return dead
+}
+
+// bridges finds the articulation points of the import graph taken as
+// undirected, ranked by how many files removing them cuts off from the
+// largest remaining part.
+func bridges(graph map[string][]string, weights map[string]int, files map[string]bool) []Bridge {
+	nodes := make([]string, 0, len(graph))
+	adjacent := make(map[string]map[string]bool, len(graph))
+	link := func(a, b string) {
+		if adjacent[a] == nil {
+			adjacent[a] = make(map[string]bool)
+		}
+		adjacent[a][b] = true
+	}
+	for n, targets := range graph {
+		nodes = append(nodes, n)
+		for _, m := range targets {
+			link(n, m)
+			link(m, n)
+		}
+	}
+	sort.Strings(nodes)
+	neighbours := make(map[string][]string, len(adjacent))
+	for n, set := range adjacent {
+		neighbours[n] = sortedKeys(set)
+	}
+
+	var (
+		counter int
+		disc    = make(map[string]int)
+		low     = make(map[string]int)
+		size    = make(map[string]int)
+		pieces  = make(map[string][]int) // subtrees each node separates
+		found   = []Bridge{}
+	)
+	var visit func(n, parent string)
+	visit = func(n, parent string) {
+		counter++
+		disc[n], low[n], size[n] = counter, counter, weights[n]
+		for _, m := range neighbours[n] {
+			if disc[m] == 0 {
+				visit(m, n)
+				size[n] += size[m]
+				low[n] = min(low[n], low[m])
+				if low[m] >= disc[n] {
+					pieces[n] = append(pieces[n], size[m])
+				}
+			} else if m != parent {
+				low[n] = min(low[n], disc[m])
+			}
+		}
+	}
+	for _, root := range nodes {
+		if disc[root] != 0 || len(neighbours[root]) == 0 {
+			continue
+		}
+		visit(root, "")
+		component := size[root]
+		for n, sizes := range pieces {
+			if disc[n] < disc[root] {
+				continue
+			}
+			// The remainder is whatever is left above n; the root has none.
+			rest, largest := component-weights[n], 0
+			for _, s := range sizes {
+				rest -= s
+				largest = max(largest, s)
+			}
+			largest = max(largest, rest)
+			if cuts := component - weights[n] - largest; cuts > 0 {
+				pkg := n
+				if files[n] {
+					pkg = path.Dir(n)
+				}
+				found = append(found, Bridge{Path: n, Package: pkg, Cuts: cuts})
+			}
+			delete(pieces, n)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Cuts != found[j].Cuts {
+			return found[i].Cuts > found[j].Cuts
+		}
+		return found[i].Path < found[j].Path
+	})
+	if len(found) > maxBridges {
+		found = found[:maxBridges]
+	}
+	return found
+}
+
+// IsEntryFile reports files that run or are loaded without being imported:
+// tests, main and index files, package markers and tool configuration.
+func IsEntryFile(file string) bool {
+	base := path.Base(file)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	switch stem {
+	case "main", "index", "__main__", "__init__", "setup", "manage":
+		return true
+	}
+	if strings.HasSuffix(stem, ".config") || strings.HasSuffix(base, ".d.ts") {
+		return true
+	}
+	return IsTestFile(file)
+}
+
+// IsTestFile reports test files and files under test directories.
+func IsTestFile(file string) bool {
+	base := path.Base(file)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	if stem == "conftest" || strings.HasPrefix(base, "test_") || strings.HasSuffix(stem, "_test") ||
+		strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
+
+// StronglyConnected runs Tarjan's algorithm. It maps every node that is
+// part of a cycle to the index of its component and returns the components
+// of more than one node, each sorted.
+func StronglyConnected(graph map[string][]string) (map[string]int, [][]string) {
+	nodes := make([]string, 0, len(graph))
+	for n := range graph {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	var (
+		counter int
+		stack   []string
+		members [][]string
+		onStack = make(map[string]bool)
+		indices = make(map[string]int)
+		lowlink = make(map[string]int)
+		cycleOf = make(map[string]int)
+	)
+	var visit func(n string)
+	visit = func(n string) {
+		indices[n], lowlink[n] = counter, counter
+		counter++
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range graph[n] {
+			if _, seen := indices[m]; !seen {
+				visit(m)
+				lowlink[n] = min(lowlink[n], lowlink[m])
+			} else if onStack[m] {
+				lowlink[n] = min(lowlink[n], indices[m])
+			}
+		}
+		if lowlink[n] != indices[n] {
+			return
+		}
+		var scc []string
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			scc = append(scc, m)
+			if m == n {
+				break
+			}
+		}
+		if len(scc) > 1 {
+			sort.Strings(scc)
+			for _, m := range scc {
+				cycleOf[m] = len(members)
+			}
+			members = append(members, scc)
+		}
+	}
+	for _, n := range nodes {
+		if _, seen := indices[n]; !seen {
+			visit(n)
+		}
+	}
+	return cycleOf, members
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func round(x float64) float64 {
+	return float64(int(x*100+0.5)) / 100
+}
@@ -0,0 +1,140 @@
+package depgraph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+func edge(source, target string) index.ImportEdge {
+	return index.ImportEdge{Source: source, Target: target, Import: target, Line: 1}
+}
+
+func TestBuild(t *testing.T) {
+	languages := map[string]string{
+		"web/index.ts":    "typescript",
+		"web/app.ts":      "typescript",
+		"web/old.ts":      "typescript",
+		"web/lib/a.ts":    "typescript",
+		"web/lib/b.ts":    "typescript",
+		"web/util/fmt.ts": "typescript",
+		"cmd/main.go":     "go",
+		"svc/a.go":        "go",
+		"svc/b.go":        "go",
+		"tool/x.go":       "go",
+		"README.md":       "markdown",
+	}
+	report := Build([]index.ImportEdge{
+		edge("web/index.ts", "web/app.ts"),
+		edge("web/app.ts", "web/lib/a.ts"),
+		edge("web/app.ts", "web/lib/a.ts"), // duplicate
+		edge("web/lib/a.ts", "web/lib/b.ts"),
+		edge("web/lib/b.ts", "web/lib/a.ts"),
+		edge("web/lib/b.ts", "web/util/fmt.ts"),
+		edge("cmd/main.go", "svc/a.go"),
+	}, languages, IsEntryFile)
+
+	if report.Files != 10 || report.Imports != 6 {
+		t.Errorf("files = %d, imports = %d", report.Files, report.Imports)
+	}
+	if len(report.Cycles) != 1 || !reflect.DeepEqual(report.Cycles[0].Files, []string{"web/lib/a.ts", "web/lib/b.ts"}) {
+		t.Errorf("cycles = %+v", report.Cycles)
+	}
+	if len(report.PackageCycles) != 0 {
+		t.Errorf("package cycles = %v", report.PackageCycles)
+	}
+
+	coupling := make(map[string]Package)
+	for _, p := range report.Packages {
+		coupling[p.Name] = p
+	}
+	if p := coupling["web/lib"]; p.Files != 2 || p.Afferent != 1 || p.Efferent != 1 || p.Instability != 0.5 {
+		t.Errorf("web/lib = %+v", p)
+	}
+	if p := coupling["web/util"]; p.Instability != 0 || p.Afferent != 1 {
+		t.Errorf("web/util = %+v", p)
+	}
+	if p := coupling["web"]; p.Instability != 1 {
+		t.Errorf("web = %+v", p)
+	}
+
+	// svc/b.go is part of an imported Go package, cmd/main.go an entry point.
+	if want := []string{"tool/x.go", "web/old.ts"}; !reflect.DeepEqual(report.DeadFiles, want) {
+		t.Errorf("dead files = %v, want %v", report.DeadFiles, want)
+	}
+
+	want := []Bridge{
+		{Path: "web/lib/a.ts", Package: "web/lib", Cuts: 2},
+		{Path: "web/app.ts", Package: "web", Cuts: 1},
+		{Path: "web/lib/b.ts", Package: "web/lib", Cuts: 1},
+	}
+	if !reflect.DeepEqual(report.Bridges, want) {
+		t.Errorf("bridges = %+v, want %+v", report.Bridges, want)
+	}
+}
+
+func TestBuild_GoPackageBridge(t *testing.T) {
+	languages := map[string]string{
+		"cmd/main.go": "go", "api/a.go": "go", "api/b.go": "go", "store/db.go": "go",
+	}
+	// The import of the api package has an edge to each of its files.
+	report := Build([]index.ImportEdge{
+		{Source: "cmd/main.go", Target: "api/a.go", Import: "example.com/app/api", Line: 3},
+		{Source: "cmd/main.go", Target: "api/b.go", Import: "example.com/app/api", Line: 3},
+		edge("api/b.go", "store/db.go"),
+	}, languages, IsEntryFile)
+
+	if len(report.Bridges) != 1 || report.Bridges[0].Path != "api" || report.Bridges[0].Package != "api" || report.Bridges[0].Cuts != 1 {
+		t.Errorf("bridges = %+v", report.Bridges)
+	}
+	if report.Imports != 2 || report.Dependencies[0] != (Dependency{From: "api", To: "store", Imports: 1}) ||
+		report.Dependencies[1] != (Dependency{From: "cmd", To: "api", Imports: 1}) {
+		t.Errorf("imports = %d, dependencies = %+v", report.Imports, report.Dependencies)
+	}
+}
+
+func TestRender(t *testing.T) {
+	report := Build([]index.ImportEdge{
+		edge("x/a.ts", "y/b.ts"),
+		edge("y/b.ts", "x/a.ts"),
+		edge("x/a.ts", "z/c.ts"),
+	}, map[string]string{"x/a.ts": "typescript", "y/b.ts": "typescript", "z/c.ts": "typescript"}, IsEntryFile)
+
+	if !reflect.DeepEqual(report.PackageCycles, [][]string{{"x", "y"}}) {
+		t.Fatalf("package cycles = %v", report.PackageCycles)
+	}
+
+	dot := report.DOT()
+	for _, want := range []string{"digraph palace {", `"x" -> "y" [label="1", color="#cc0000"];`, `"x" -> "z" [label="1"];`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := report.Mermaid()
+	for _, want := range []string{"graph LR\n", "-->|1|", "classDef cycle", "linkStyle 0,2 stroke:#cc0000"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestIsEntryFile(t *testing.T) {
+	for file, want := range map[string]bool{
+		"src/index.ts":           true,
+		"pkg/__main__.py":        true,
+		"pkg/store_test.go":      true,
+		"src/app.spec.ts":        true,
+		"tests/helpers.py":       true,
+		"vite.config.ts":         true,
+		"src/types.d.ts":         true,
+		"src/app.ts":             false,
+		"internal/testutil/x.go": false,
+	} {
+		if got := IsEntryFile(file); got != want {
+			t.Errorf("IsEntryFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
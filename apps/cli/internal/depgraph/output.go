@@ -0,0 +1,90 @@
+package depgraph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DOT renders the package dependency graph for Graphviz. Nodes show the
+// file count and instability; packages and dependencies in a cycle are red.
+func (r *Report) DOT() string {
+	inCycle := r.packageCycleOf()
+
+	var b strings.Builder
+	b.WriteString("digraph palace {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	for i := range r.Packages {
+		p := &r.Packages[i]
+		attrs := fmt.Sprintf("label=%s", strconv.Quote(fmt.Sprintf("%s\n%d files, I=%.2f", p.Name, p.Files, p.Instability)))
+		if _, ok := inCycle[p.Name]; ok {
+			attrs += `, color="#cc0000", style="rounded,filled", fillcolor="#ffdddd"`
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", strconv.Quote(p.Name), attrs)
+	}
+	for _, d := range r.Dependencies {
+		attrs := fmt.Sprintf("label=\"%d\"", d.Imports)
+		if cycleEdge(inCycle, d) {
+			attrs += `, color="#cc0000"`
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", strconv.Quote(d.From), strconv.Quote(d.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the package dependency graph as a Mermaid flowchart.
+func (r *Report) Mermaid() string {
+	inCycle := r.packageCycleOf()
+	ids := make(map[string]string, len(r.Packages))
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	var cyclic []string
+	for i := range r.Packages {
+		p := &r.Packages[i]
+		id := "p" + strconv.Itoa(i)
+		ids[p.Name] = id
+		label := strings.ReplaceAll(p.Name, `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%d files, I=%.2f\"]\n", id, label, p.Files, p.Instability)
+		if _, ok := inCycle[p.Name]; ok {
+			cyclic = append(cyclic, id)
+		}
+	}
+	var cycleLinks []string
+	for i, d := range r.Dependencies {
+		fmt.Fprintf(&b, "  %s -->|%d| %s\n", ids[d.From], d.Imports, ids[d.To])
+		if cycleEdge(inCycle, d) {
+			cycleLinks = append(cycleLinks, strconv.Itoa(i))
+		}
+	}
+	if len(cyclic) > 0 {
+		b.WriteString("  classDef cycle fill:#ffdddd,stroke:#cc0000\n")
+		fmt.Fprintf(&b, "  class %s cycle\n", strings.Join(cyclic, ","))
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#cc0000\n", strings.Join(cycleLinks, ","))
+	}
+	return b.String()
+}
+
+// packageCycleOf maps packages in a cycle to the index of their cycle.
+func (r *Report) packageCycleOf() map[string]int {
+	cycleOf := make(map[string]int)
+	for i, cycle := range r.PackageCycles {
+		for _, p := range cycle {
+			cycleOf[p] = i
+		}
+	}
+	return cycleOf
+}
+
+func cycleEdge(cycleOf map[string]int, d Dependency) bool {
+	from, ok := cycleOf[d.From]
+	if !ok {
+		return false
+	}
+	to, ok := cycleOf[d.To]
+	return ok && from == to
+}
@@ -0,0 +1,154 @@
+// Package findings is the common shape of problems that CI should see —
+// pattern outliers, contract mismatches, stale index entries, risky
+// changes — and renders them as JSON, SARIF or JUnit XML.
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/sarif"
+)
+
+// Format is an output format for findings.
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+	FormatJUnit Format = "junit"
+)
+
+// ParseFormat parses a --format value; empty means text.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatSARIF, FormatJUnit:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want text, json, sarif or junit)", s)
+}
+
+// Levels, shared with SARIF.
+const (
+	LevelError   = sarif.LevelError
+	LevelWarning = sarif.LevelWarning
+	LevelNote    = sarif.LevelNote
+)
+
+// Location is a file region; lines are 1-based and zero when unknown.
+type Location struct {
+	Path      string `json:"path"`
+	LineStart int    `json:"lineStart,omitempty"`
+	LineEnd   int    `json:"lineEnd,omitempty"`
+}
+
+// Finding is one problem.
+type Finding struct {
+	RuleID      string         `json:"ruleId"`
+	RuleName    string         `json:"ruleName,omitempty"`
+	Description string         `json:"description,omitempty"` // what the rule checks
+	Level       string         `json:"level"`
+	Message     string         `json:"message"`
+	Location    Location       `json:"location"`
+	Related     []Location     `json:"related,omitempty"`
+	Properties  map[string]any `json:"properties,omitempty"`
+}
+
+// Report is the findings of one command run.
+type Report struct {
+	Tool     string    `json:"tool"` // e.g. "palace check"
+	Findings []Finding `json:"findings"`
+	// Checks are named checks that ran; in JUnit each becomes a test case
+	// that passes unless a finding shares its rule ID.
+	Checks []string `json:"checks,omitempty"`
+}
+
+// Counts tallies findings by level.
+func (r *Report) Counts() map[string]int {
+	counts := map[string]int{LevelError: 0, LevelWarning: 0, LevelNote: 0}
+	for i := range r.Findings {
+		counts[r.Findings[i].Level]++
+	}
+	return counts
+}
+
+// Write renders the report in format. Text is not a structured format and
+// is rejected; commands print their own text output.
+func (r *Report) Write(w io.Writer, format Format, toolVersion string) error {
+	switch format {
+	case FormatJSON:
+		return r.writeJSON(w)
+	case FormatSARIF:
+		data, err := r.SARIF(toolVersion).Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	}
+	return fmt.Errorf("format %q is not a report format", format)
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	out := struct {
+		Tool     string         `json:"tool"`
+		Counts   map[string]int `json:"counts"`
+		Findings []Finding      `json:"findings"`
+		Checks   []string       `json:"checks,omitempty"`
+	}{r.Tool, r.Counts(), r.Findings, r.Checks}
+	if out.Findings == nil {
+		out.Findings = []Finding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// SARIF converts the findings into a SARIF log. Each distinct rule ID
+// becomes a rule; the first finding of a rule supplies its name and
+// description.
+func (r *Report) SARIF(toolVersion string) *sarif.Log {
+	log := sarif.NewLog(toolVersion)
+	for i := range r.Findings {
+		f := &r.Findings[i]
+		rule := sarif.Rule{ID: f.RuleID, Name: f.RuleName}
+		if f.Description != "" {
+			rule.ShortDescription = &sarif.Message{Text: f.Description}
+		}
+		log.AddRule(rule)
+
+		result := sarif.Result{
+			RuleID:     f.RuleID,
+			Level:      f.Level,
+			Message:    sarif.Message{Text: f.Message},
+			Locations:  []sarif.Location{sarif.FileLocation(f.Location.Path, f.Location.LineStart, f.Location.LineEnd)},
+			Properties: f.Properties,
+		}
+		for _, rel := range f.Related {
+			result.RelatedLocations = append(result.RelatedLocations, sarif.FileLocation(rel.Path, rel.LineStart, rel.LineEnd))
+		}
+		log.AddResult(result)
+	}
+	return log
+}
+
+// Sort orders findings by path, line and rule for stable output.
+func Sort(list []Finding) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Location.Path != b.Location.Path {
+			return a.Location.Path < b.Location.Path
+		}
+		if a.Location.LineStart != b.Location.LineStart {
+			return a.Location.LineStart < b.Location.LineStart
+		}
+		return a.RuleID < b.RuleID
+	})
+}
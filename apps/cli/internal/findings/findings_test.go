@@ -0,0 +1,133 @@
+package findings
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func testReport() *Report {
+	return &Report{
+		Tool: "palace test",
+		Findings: []Finding{
+			{RuleID: "naming", RuleName: "Naming", Level: LevelWarning, Message: "bad name",
+				Location: Location{Path: "b.go", LineStart: 3, LineEnd: 5}},
+			{RuleID: "type_mismatch", Description: "types differ", Level: LevelError, Message: "string vs number",
+				Location: Location{Path: "a.go", LineStart: 10}, Related: []Location{{Path: "app.ts", LineStart: 2}}},
+			{RuleID: "naming", Level: LevelNote, Message: "odd name", Location: Location{Path: "a.go"}},
+		},
+		Checks: []string{"naming", "stale"},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatText {
+		t.Errorf("ParseFormat(\"\") = %q, %v", f, err)
+	}
+	if f, err := ParseFormat("sarif"); err != nil || f != FormatSARIF {
+		t.Errorf("ParseFormat(sarif) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestSort(t *testing.T) {
+	r := testReport()
+	Sort(r.Findings)
+	got := []string{}
+	for _, f := range r.Findings {
+		got = append(got, f.Location.Path+":"+f.RuleID)
+	}
+	want := []string{"a.go:naming", "a.go:type_mismatch", "b.go:naming"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Sort() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatJSON, "1.0"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Tool     string         `json:"tool"`
+		Counts   map[string]int `json:"counts"`
+		Findings []Finding      `json:"findings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Tool != "palace test" || len(decoded.Findings) != 3 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if decoded.Counts[LevelError] != 1 || decoded.Counts[LevelWarning] != 1 || decoded.Counts[LevelNote] != 1 {
+		t.Errorf("counts = %v", decoded.Counts)
+	}
+
+	buf.Reset()
+	empty := &Report{Tool: "palace test"}
+	if err := empty.Write(&buf, FormatJSON, "1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"findings": []`)) {
+		t.Errorf("empty report should have an empty findings array:\n%s", buf.String())
+	}
+
+	if err := empty.Write(&buf, FormatText, "1.0"); err == nil {
+		t.Error("expected error for text format")
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	log := testReport().SARIF("1.0")
+	rules := log.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "naming" || rules[0].Name != "Naming" {
+		t.Fatalf("rules = %+v", rules)
+	}
+	if rules[1].ShortDescription == nil || rules[1].ShortDescription.Text != "types differ" {
+		t.Errorf("rule description = %+v", rules[1].ShortDescription)
+	}
+
+	results := log.Results()
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	mismatch := results[1]
+	if mismatch.Level != LevelError || mismatch.Locations[0].PhysicalLocation.Region.StartLine != 10 {
+		t.Errorf("mismatch result = %+v", mismatch)
+	}
+	if len(mismatch.RelatedLocations) != 1 || mismatch.RelatedLocations[0].PhysicalLocation.ArtifactLocation.URI != "app.ts" {
+		t.Errorf("related locations = %+v", mismatch.RelatedLocations)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatJUnit, "1.0"); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	// naming: one failure (warning) and one passing note; stale: one
+	// passing check; type_mismatch: one failure.
+	if suites.Tests != 4 || suites.Failures != 2 || len(suites.Suites) != 3 {
+		t.Fatalf("suites = %d tests, %d failures, %d suites", suites.Tests, suites.Failures, len(suites.Suites))
+	}
+	naming := suites.Suites[0]
+	if naming.Name != "naming" || naming.Tests != 2 || naming.Failures != 1 {
+		t.Errorf("naming suite = %+v", naming)
+	}
+	if naming.Cases[0].Name != "b.go:3" || naming.Cases[1].SystemOut != "odd name" {
+		t.Errorf("naming cases = %+v", naming.Cases)
+	}
+	if stale := suites.Suites[1]; stale.Name != "stale" || stale.Failures != 0 || stale.Tests != 1 {
+		t.Errorf("stale suite = %+v", stale)
+	}
+}
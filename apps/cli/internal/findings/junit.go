@@ -0,0 +1,95 @@
+package findings
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit renders one test suite per rule. Errors and warnings are
+// failed test cases; notes pass with the message as output, so CI shows
+// them without failing. Checks without findings are passing test cases.
+func (r *Report) writeJUnit(w io.Writer) error {
+	byRule := map[string][]junitCase{}
+	for i := range r.Findings {
+		f := &r.Findings[i]
+		c := junitCase{ClassName: f.RuleID, Name: locationName(f.Location)}
+		if f.Level == LevelNote {
+			c.SystemOut = f.Message
+		} else {
+			c.Failure = &junitFailure{Message: f.Message, Type: f.Level, Text: f.Message}
+		}
+		byRule[f.RuleID] = append(byRule[f.RuleID], c)
+	}
+	for _, check := range r.Checks {
+		if _, failed := byRule[check]; !failed {
+			byRule[check] = []junitCase{{ClassName: check, Name: check}}
+		}
+	}
+
+	rules := make([]string, 0, len(byRule))
+	for rule := range byRule {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	suites := junitSuites{Name: r.Tool}
+	for _, rule := range rules {
+		suite := junitSuite{Name: rule, Cases: byRule[rule], Tests: len(byRule[rule])}
+		for _, c := range suite.Cases {
+			if c.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func locationName(loc Location) string {
+	if loc.LineStart > 0 {
+		return fmt.Sprintf("%s:%d", loc.Path, loc.LineStart)
+	}
+	return loc.Path
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,10 +16,12 @@ import (
 )
 
 type Chunk struct {
-	Index     int
-	StartLine int
-	EndLine   int
-	Content   string
+	Index      int
+	StartLine  int
+	EndLine    int
+	Content    string
+	Symbol     string // owning symbol, e.g. "Store.Close"; empty when none
+	SymbolKind string
 }
 
 // MatchesGuardrail returns true if the path matches any guardrail glob.
@@ -163,10 +166,20 @@ type SymbolBoundary struct {
 	Kind      string
 	StartLine int
 	EndLine   int
+	Children  []SymbolBoundary // nested symbols, such as methods of a class
 }
 
+// smallSymbolLines is the size up to which consecutive symbols, such as a
+// run of constants, may share a chunk.
+const smallSymbolLines = 3
+
 // ChunkContentSmart creates chunks that respect symbol boundaries (AST-aware).
-// It never splits in the middle of a function, class, or method.
+// Each top-level symbol gets its own chunk together with the comment lines
+// directly above it; runs of small symbols and the lines between symbols
+// are grouped up to the limits. A symbol over the limits is split at the
+// boundaries of its children, recursively, but a symbol without children
+// is never split in the middle. Chunks record the innermost symbol that
+// covers all of their code as Symbol, qualified by its parents.
 // Falls back to line-based chunking if no symbols are provided.
 func ChunkContentSmart(content string, symbols []SymbolBoundary, maxLines, maxBytes int) []Chunk {
 	if len(symbols) == 0 {
@@ -181,204 +194,175 @@ func ChunkContentSmart(content string, symbols []SymbolBoundary, maxLines, maxBy
 		maxBytes = 8 * 1024
 	}
 
-	lines := strings.Split(content, "\n")
-	totalLines := len(lines)
-
-	// Sort symbols by start line
-	sortedSymbols := make([]SymbolBoundary, len(symbols))
-	copy(sortedSymbols, symbols)
-	sortSymbolsByLine(sortedSymbols)
+	c := &symbolChunker{lines: strings.Split(content, "\n"), maxLines: maxLines, maxBytes: maxBytes}
+	c.offsets = make([]int, len(c.lines)+1)
+	for i, line := range c.lines {
+		c.offsets[i+1] = c.offsets[i] + len(line) + 1
+	}
+	c.chunkRange(1, len(c.lines), symbols, "", "")
+	for i := range c.chunks {
+		c.chunks[i].Index = i
+	}
+	return c.chunks
+}
 
-	// Find natural break points (lines between symbols)
-	breakPoints := findBreakPoints(sortedSymbols, totalLines)
+// symbolChunker holds the state of one ChunkContentSmart call.
+type symbolChunker struct {
+	lines    []string
+	offsets  []int // offsets[i] is the byte length of the first i lines, newlines included
+	maxLines int
+	maxBytes int
+	chunks   []Chunk
+}
 
-	var chunks []Chunk
-	currentStart := 1
-
-	for _, bp := range breakPoints {
-		// Calculate chunk from currentStart to bp
-		chunkLines := bp - currentStart + 1
-		chunkContent := extractLines(lines, currentStart, bp)
-		chunkBytes := len(chunkContent)
-
-		// If this chunk is too large, we need to split it
-		if chunkLines > maxLines || chunkBytes > maxBytes {
-			// Split at symbol boundaries within this range
-			subChunks := splitLargeChunk(lines, currentStart, bp, sortedSymbols, maxLines, maxBytes)
-			for _, sc := range subChunks {
-				sc.Index = len(chunks)
-				chunks = append(chunks, sc)
-			}
-		} else if chunkLines > 0 {
-			chunks = append(chunks, Chunk{
-				Index:     len(chunks),
-				StartLine: currentStart,
-				EndLine:   bp,
-				Content:   chunkContent,
-			})
-		}
+// segment is a symbol with its leading comments, or a run of lines
+// between symbols (sym is nil).
+type segment struct {
+	start, end int
+	sym        *SymbolBoundary
+	name       string // qualified symbol name
+}
 
-		currentStart = bp + 1
-	}
+func (s *segment) large() bool {
+	return s.sym != nil && s.sym.EndLine-s.sym.StartLine+1 > smallSymbolLines
+}
 
-	// Handle remaining content
-	if currentStart <= totalLines {
-		chunkContent := extractLines(lines, currentStart, totalLines)
-		if chunkContent != "" {
-			chunks = append(chunks, Chunk{
-				Index:     len(chunks),
-				StartLine: currentStart,
-				EndLine:   totalLines,
-				Content:   chunkContent,
-			})
+// chunkRange chunks lines start..end (1-based, inclusive), which contain
+// symbols and belong to the symbol owner, if any.
+func (c *symbolChunker) chunkRange(start, end int, symbols []SymbolBoundary, owner, ownerKind string) {
+	var group []segment
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+		name, kind := owner, ownerKind
+		if len(group) == 1 && group[0].sym != nil {
+			name, kind = group[0].name, group[0].sym.Kind
 		}
+		c.emit(group[0].start, group[len(group)-1].end, name, kind)
+		group = nil
 	}
 
-	return chunks
-}
-
-// sortSymbolsByLine sorts symbols by their start line (simple insertion sort)
-func sortSymbolsByLine(symbols []SymbolBoundary) {
-	for i := 1; i < len(symbols); i++ {
-		j := i
-		for j > 0 && symbols[j-1].StartLine > symbols[j].StartLine {
-			symbols[j-1], symbols[j] = symbols[j], symbols[j-1]
-			j--
+	for _, seg := range c.segments(start, end, symbols, owner) {
+		if c.oversized(seg.start, seg.end) {
+			flush()
+			switch {
+			case seg.sym != nil && len(seg.sym.Children) > 0:
+				c.chunkRange(seg.start, seg.end, seg.sym.Children, seg.name, seg.sym.Kind)
+			case seg.sym != nil:
+				// Don't split functions
+				c.emit(seg.start, seg.end, seg.name, seg.sym.Kind)
+			default:
+				c.splitLines(seg.start, seg.end, owner, ownerKind)
+			}
+			continue
 		}
+		if len(group) > 0 && (seg.large() || group[0].large() || c.oversized(group[0].start, seg.end)) {
+			flush()
+		}
+		group = append(group, seg)
 	}
+	flush()
 }
 
-// findBreakPoints identifies safe lines to split between symbols
-func findBreakPoints(symbols []SymbolBoundary, totalLines int) []int {
-	if len(symbols) == 0 {
-		return []int{totalLines}
+// segments splits lines start..end into symbols and the runs between them.
+// Blank runs are added to the segment before them.
+func (c *symbolChunker) segments(start, end int, symbols []SymbolBoundary, owner string) []segment {
+	sorted := make([]SymbolBoundary, len(symbols))
+	copy(sorted, symbols)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartLine < sorted[j].StartLine })
+
+	var segs []segment
+	add := func(seg segment) {
+		if seg.sym == nil && c.blank(seg.start, seg.end) && len(segs) > 0 {
+			segs[len(segs)-1].end = seg.end
+			return
+		}
+		segs = append(segs, seg)
 	}
 
-	var breakPoints []int
-	for i := 0; i < len(symbols)-1; i++ {
-		// Break point is the line just before the next symbol starts
-		// (i.e., after current symbol ends)
-		endOfCurrent := symbols[i].EndLine
-		startOfNext := symbols[i+1].StartLine
-
-		if startOfNext > endOfCurrent+1 {
-			// There's a gap between symbols - break in the gap
-			breakPoints = append(breakPoints, startOfNext-1)
-		} else {
-			// Symbols are adjacent or overlapping - break at end of current
-			breakPoints = append(breakPoints, endOfCurrent)
+	pos := start
+	for i := range sorted {
+		sym := &sorted[i]
+		if sym.EndLine < pos || sym.StartLine > end {
+			continue // nested in or overlapping an earlier symbol, or out of range
 		}
+		symStart := max(sym.StartLine, pos)
+		docStart := c.docStart(symStart, pos)
+		if docStart > pos {
+			add(segment{start: pos, end: docStart - 1})
+		}
+		name := sym.Name
+		if owner != "" {
+			name = owner + "." + sym.Name
+		}
+		add(segment{start: docStart, end: min(sym.EndLine, end), sym: sym, name: name})
+		pos = min(sym.EndLine, end) + 1
 	}
-
-	// Add final break point at end of last symbol or file
-	lastSymbol := symbols[len(symbols)-1]
-	if lastSymbol.EndLine < totalLines {
-		breakPoints = append(breakPoints, totalLines)
-	} else {
-		breakPoints = append(breakPoints, lastSymbol.EndLine)
+	if pos <= end {
+		add(segment{start: pos, end: end})
 	}
 
-	return breakPoints
+	// Leading blank lines go with the first segment.
+	if len(segs) > 1 && segs[0].sym == nil && c.blank(segs[0].start, segs[0].end) {
+		segs[1].start = segs[0].start
+		segs = segs[1:]
+	}
+	return segs
 }
 
-// extractLines extracts lines from start to end (1-indexed, inclusive)
-func extractLines(lines []string, start, end int) string {
-	if start < 1 {
-		start = 1
-	}
-	if end > len(lines) {
-		end = len(lines)
+// docStart returns the first line of the comments and annotations directly
+// above line, not going above floor.
+func (c *symbolChunker) docStart(line, floor int) int {
+	for line > floor && isLeadingLine(c.lines[line-2]) {
+		line--
 	}
-	if start > end || start > len(lines) {
-		return ""
-	}
-	return strings.Join(lines[start-1:end], "\n")
+	return line
 }
 
-// splitLargeChunk splits a chunk that's too large, respecting symbol boundaries
-func splitLargeChunk(lines []string, start, end int, symbols []SymbolBoundary, maxLines, maxBytes int) []Chunk {
-	var chunks []Chunk
-
-	// Find symbols within this range
-	var relevantSymbols []SymbolBoundary
-	for _, sym := range symbols {
-		if sym.StartLine >= start && sym.EndLine <= end {
-			relevantSymbols = append(relevantSymbols, sym)
-		}
+// isLeadingLine reports whether a line is a comment, doc comment,
+// decorator or attribute that belongs to the symbol below it.
+func isLeadingLine(line string) bool {
+	t := strings.TrimSpace(line)
+	if t == "#" {
+		return true
 	}
-
-	if len(relevantSymbols) == 0 {
-		// No symbols in range - fall back to line-based chunking for this section
-		content := extractLines(lines, start, end)
-		lineChunks := ChunkContent(content, maxLines, maxBytes)
-		for _, lc := range lineChunks {
-			lc.StartLine += start - 1
-			lc.EndLine += start - 1
-			chunks = append(chunks, lc)
+	for _, prefix := range []string{"//", "/*", "*", "--", "@", "# ", "##", "#["} {
+		if strings.HasPrefix(t, prefix) {
+			return true
 		}
-		return chunks
 	}
+	return false
+}
 
-	// Group consecutive symbols that fit within limits
-	currentStart := start
-	var currentSymbols []SymbolBoundary
-	currentBytes := 0
-
-	flushGroup := func() {
-		if len(currentSymbols) == 0 {
-			return
+func (c *symbolChunker) blank(start, end int) bool {
+	for i := start; i <= end; i++ {
+		if strings.TrimSpace(c.lines[i-1]) != "" {
+			return false
 		}
-		groupEnd := currentSymbols[len(currentSymbols)-1].EndLine
-		content := extractLines(lines, currentStart, groupEnd)
-		chunks = append(chunks, Chunk{
-			StartLine: currentStart,
-			EndLine:   groupEnd,
-			Content:   content,
-		})
-		currentStart = groupEnd + 1
-		currentSymbols = nil
-		currentBytes = 0
 	}
+	return true
+}
 
-	for _, sym := range relevantSymbols {
-		symContent := extractLines(lines, sym.StartLine, sym.EndLine)
-		symBytes := len(symContent)
-		symLines := sym.EndLine - sym.StartLine + 1
-
-		// Check if adding this symbol would exceed limits
-		var groupLines int
-		if len(currentSymbols) > 0 {
-			groupLines = sym.EndLine - currentStart + 1
-		} else {
-			groupLines = symLines
-		}
-
-		if len(currentSymbols) > 0 && (groupLines > maxLines || currentBytes+symBytes > maxBytes) {
-			flushGroup()
-		}
+func (c *symbolChunker) oversized(start, end int) bool {
+	return end-start+1 > c.maxLines || c.offsets[end]-c.offsets[start-1]-1 > c.maxBytes
+}
 
-		// Handle symbols that are individually too large
-		if symLines > maxLines || symBytes > maxBytes {
-			if len(currentSymbols) > 0 {
-				flushGroup()
-			}
-			// Add the large symbol as its own chunk (don't split functions)
-			chunks = append(chunks, Chunk{
-				StartLine: sym.StartLine,
-				EndLine:   sym.EndLine,
-				Content:   symContent,
-			})
-			currentStart = sym.EndLine + 1
-			continue
-		}
+func (c *symbolChunker) emit(start, end int, symbol, kind string) {
+	c.chunks = append(c.chunks, Chunk{
+		StartLine:  start,
+		EndLine:    end,
+		Content:    strings.Join(c.lines[start-1:end], "\n"),
+		Symbol:     symbol,
+		SymbolKind: kind,
+	})
+}
 
-		currentSymbols = append(currentSymbols, sym)
-		currentBytes += symBytes
+// splitLines chunks lines start..end by line and byte counts.
+func (c *symbolChunker) splitLines(start, end int, symbol, kind string) {
+	for _, lc := range ChunkContent(strings.Join(c.lines[start-1:end], "\n"), c.maxLines, c.maxBytes) {
+		c.emit(lc.StartLine+start-1, lc.EndLine+start-1, symbol, kind)
 	}
-
-	flushGroup()
-
-	return chunks
 }
 
 var ErrNotFound = os.ErrNotExist
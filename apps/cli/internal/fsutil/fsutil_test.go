@@ -3,6 +3,7 @@ package fsutil_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -260,6 +261,68 @@ func another() {
 	}
 }
 
+func TestChunkContentSmartSymbolBoundaries(t *testing.T) {
+	content := `import os
+
+# foo does things.
+def foo():
+    x = 1
+    y = 2
+    return x + y
+
+A = 1
+B = 2
+
+class Store:
+    """Holds rows."""
+
+    # get returns a row.
+    def get(self):
+        return 1
+
+    def put(self):
+        x = 1
+        return x`
+
+	symbols := []fsutil.SymbolBoundary{
+		{Name: "foo", Kind: "function", StartLine: 4, EndLine: 7},
+		{Name: "A", Kind: "constant", StartLine: 9, EndLine: 9},
+		{Name: "B", Kind: "constant", StartLine: 10, EndLine: 10},
+		{Name: "Store", Kind: "class", StartLine: 12, EndLine: 21, Children: []fsutil.SymbolBoundary{
+			{Name: "get", Kind: "method", StartLine: 16, EndLine: 17},
+			{Name: "put", Kind: "method", StartLine: 19, EndLine: 21},
+		}},
+	}
+
+	type span struct {
+		start, end   int
+		symbol, kind string
+	}
+	want := []span{
+		{1, 2, "", ""},
+		{3, 8, "foo", "function"}, // with its comment
+		{9, 11, "", ""},           // small symbols share a chunk
+		{12, 14, "Store", "class"},
+		{15, 18, "Store.get", "method"},
+		{19, 21, "Store.put", "method"},
+	}
+
+	chunks := fsutil.ChunkContentSmart(content, symbols, 6, 10000)
+	var got []span
+	for i, c := range chunks {
+		if c.Index != i {
+			t.Errorf("chunk %d has index %d", i, c.Index)
+		}
+		got = append(got, span{c.StartLine, c.EndLine, c.Symbol, c.SymbolKind})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunks = %v, want %v", got, want)
+	}
+	if !strings.HasPrefix(chunks[1].Content, "# foo does things.\ndef foo():") {
+		t.Errorf("foo chunk = %q", chunks[1].Content)
+	}
+}
+
 func TestChunkContentSmartNoSymbols(t *testing.T) {
 	content := "line1\nline2\nline3\nline4\nline5"
 
@@ -3,9 +3,12 @@ package gitutil
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // IsGitRepo checks if the given path is inside a git repository.
@@ -251,3 +254,218 @@ func IsDirtyWorkingTree(root string) bool {
 	}
 	return strings.TrimSpace(string(out)) != ""
 }
+
+// Commit is a commit read from git log.
+type Commit struct {
+	SHA     string
+	Author  string
+	Email   string
+	Date    time.Time
+	Parents []string
+	Subject string
+	Body    string
+	Files   []string // paths changed, relative to the repository root; empty for merges
+}
+
+// Message returns the full commit message.
+func (c *Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// LogOptions narrows the commits returned by Log.
+type LogOptions struct {
+	Rev   string // revision or range, e.g. "v1.0..HEAD"; HEAD when empty
+	Since string // only commits after this date (anything git's --since accepts)
+	Limit int    // maximum commits; all when zero
+	// Relative limits commits and file names to the directory Log runs in,
+	// with names relative to it rather than to the repository root.
+	Relative bool
+}
+
+// Log returns commits reachable from opts.Rev, newest first. A repository
+// without commits yields no commits.
+func Log(root string, opts LogOptions) ([]Commit, error) {
+	if _, err := GetHeadCommit(root); err != nil && opts.Rev == "" {
+		return nil, nil
+	}
+
+	// Records start with \x1e and fields end with \x1f; the changed file
+	// names from --name-only follow the last field.
+	args := []string{"-C", root, "-c", "core.quotePath=false", "log", "--name-only", "--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%P%x1f%s%x1f%b%x1f"}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", opts.Limit))
+	}
+	if opts.Relative {
+		args = append(args, "--relative")
+	}
+	rev := opts.Rev
+	if rev == "" {
+		rev = "HEAD"
+	}
+	args = append(args, rev, "--")
+
+	out, err := exec.CommandContext(context.Background(), "git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 8 {
+			continue
+		}
+		c := Commit{
+			SHA:     fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Parents: strings.Fields(fields[4]),
+			Subject: strings.TrimSpace(fields[5]),
+			Body:    strings.TrimSpace(fields[6]),
+		}
+		c.Date, _ = time.Parse(time.RFC3339, fields[3])
+		for _, line := range strings.Split(fields[7], "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				c.Files = append(c.Files, line)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// BlameLine attributes one line of a file to the commit that last changed it.
+type BlameLine struct {
+	Line   int // 1-based line number in the working tree file
+	SHA    string
+	Author string
+	Email  string
+	Time   time.Time
+}
+
+// Blame attributes each line of path, relative to root, in the working tree.
+// Lines that are not committed yet are left out.
+func Blame(root, path string) ([]BlameLine, error) {
+	out, err := exec.CommandContext(context.Background(), "git", "-C", root, "blame", "--line-porcelain", "--", path).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git blame: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git blame: %w", err)
+	}
+
+	// Each line is a header "<sha> <orig-line> <final-line> [<count>]",
+	// "key value" lines, then the content prefixed with a tab.
+	var lines []BlameLine
+	var cur BlameLine
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if strings.Trim(cur.SHA, "0") != "" {
+				lines = append(lines, cur)
+			}
+			cur = BlameLine{}
+		case cur.SHA == "":
+			fields := strings.Fields(line)
+			if len(fields) >= 3 {
+				cur.SHA = fields[0]
+				fmt.Sscan(fields[2], &cur.Line)
+			}
+		default:
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "author":
+				cur.Author = value
+			case "author-mail":
+				cur.Email = strings.Trim(value, "<>")
+			case "author-time":
+				var secs int64
+				fmt.Sscan(value, &secs)
+				cur.Time = time.Unix(secs, 0).UTC()
+			}
+		}
+	}
+	return lines, nil
+}
+
+// LineRange is an inclusive range of 1-based line numbers.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Contains reports whether the ranges overlap lines start through end.
+func Contains(ranges []LineRange, start, end int) bool {
+	for _, r := range ranges {
+		if r.Start <= end && start <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffLines returns the lines each file changed in diffRange, on the new
+// side of the diff, with paths relative to root. A deletion inside a file
+// counts as a change to the line after it; deleted files map to nil.
+func DiffLines(root, diffRange string) (map[string][]LineRange, error) {
+	out, err := exec.CommandContext(context.Background(), "git", "-C", root, "-c", "core.quotePath=false",
+		"diff", "-U0", "--no-color", "--no-ext-diff", "--relative", diffRange).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	changes := map[string][]LineRange{}
+	var oldPath, path string
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(line, "+++ ")
+			if path == "/dev/null" {
+				changes[oldPath] = nil
+				path = ""
+				continue
+			}
+			path = strings.TrimPrefix(path, "b/")
+			if _, ok := changes[path]; !ok {
+				changes[path] = []LineRange{}
+			}
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			// "@@ -<old>[,<n>] +<start>[,<count>] @@"
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			start, count := 0, 1
+			newSide := strings.TrimPrefix(fields[2], "+")
+			if s, c, ok := strings.Cut(newSide, ","); ok {
+				fmt.Sscan(s, &start)
+				fmt.Sscan(c, &count)
+			} else {
+				fmt.Sscan(newSide, &start)
+			}
+			if count == 0 {
+				// Pure deletion: git reports the line before the gap.
+				start, count = start+1, 1
+			}
+			changes[path] = append(changes[path], LineRange{Start: start, End: start + count - 1})
+		}
+	}
+	return changes, nil
+}
@@ -212,3 +212,132 @@ func TestIsDirtyWorkingTree(t *testing.T) {
 		t.Error("expected dirty working tree")
 	}
 }
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	exec.CommandContext(context.Background(), "git", "-C", dir, "config", "user.email", "test@test.com").Run()
+	exec.CommandContext(context.Background(), "git", "-C", dir, "config", "user.name", "Test").Run()
+
+	// No commits yet is not an error
+	commits, err := Log(dir, LogOptions{})
+	if err != nil || len(commits) != 0 {
+		t.Fatalf("Log() on empty repo = %v, %v", commits, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644)
+	exec.CommandContext(context.Background(), "git", "-C", dir, "add", ".").Run()
+	exec.CommandContext(context.Background(), "git", "-C", dir, "commit", "-m", "first").Run()
+
+	os.MkdirAll(filepath.Join(dir, "sub dir"), 0o755)
+	os.WriteFile(filepath.Join(dir, "sub dir", "b.txt"), []byte("b"), 0o644)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a2"), 0o644)
+	exec.CommandContext(context.Background(), "git", "-C", dir, "add", ".").Run()
+	exec.CommandContext(context.Background(), "git", "-C", dir, "commit", "-m", "Switch to b\n\nBecause a was slow.\n\nSecond paragraph.").Run()
+
+	commits, err = Log(dir, LogOptions{})
+	if err != nil {
+		t.Fatalf("Log() error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+
+	c := commits[0]
+	if c.Subject != "Switch to b" || c.Body != "Because a was slow.\n\nSecond paragraph." {
+		t.Errorf("unexpected message: subject %q body %q", c.Subject, c.Body)
+	}
+	if c.Author != "Test" || c.Email != "test@test.com" || c.Date.IsZero() || len(c.Parents) != 1 {
+		t.Errorf("unexpected metadata: %+v", c)
+	}
+	if len(c.Files) != 2 || c.Files[0] != "a.txt" || c.Files[1] != "sub dir/b.txt" {
+		t.Errorf("unexpected files: %q", c.Files)
+	}
+	if commits[1].Subject != "first" || len(commits[1].Parents) != 0 {
+		t.Errorf("unexpected first commit: %+v", commits[1])
+	}
+
+	limited, err := Log(dir, LogOptions{Limit: 1})
+	if err != nil || len(limited) != 1 || limited[0].SHA != c.SHA {
+		t.Errorf("Log(Limit: 1) = %v, %v", limited, err)
+	}
+}
+
+func TestBlame(t *testing.T) {
+	dir := t.TempDir()
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	commit := func(name, email, content string) {
+		os.WriteFile(filepath.Join(dir, "a.txt"), []byte(content), 0o644)
+		exec.CommandContext(context.Background(), "git", "-C", dir, "add", ".").Run()
+		cmd := exec.CommandContext(context.Background(), "git", "-C", dir, "-c", "user.name="+name, "-c", "user.email="+email, "commit", "-m", "edit")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+	commit("Ann", "ann@example.com", "one\ntwo\n")
+	commit("Bob", "bob@example.com", "one\ntwo\nthree\n")
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\nthree\nfour\n"), 0o644)
+
+	lines, err := Blame(dir, "a.txt")
+	if err != nil {
+		t.Fatalf("Blame() error: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 committed lines: %+v", len(lines), lines)
+	}
+	if lines[0].Line != 1 || lines[0].Author != "Ann" || lines[0].Email != "ann@example.com" || lines[0].Time.IsZero() {
+		t.Errorf("line 1 = %+v", lines[0])
+	}
+	if lines[2].Line != 3 || lines[2].Author != "Bob" || len(lines[2].SHA) != 40 {
+		t.Errorf("line 3 = %+v", lines[2])
+	}
+
+	if _, err := Blame(dir, "missing.txt"); err == nil {
+		t.Error("expected error for an untracked file")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	dir := t.TempDir()
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			if content == "" {
+				os.Remove(filepath.Join(dir, name))
+				continue
+			}
+			os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		}
+		exec.CommandContext(context.Background(), "git", "-C", dir, "add", "-A").Run()
+		cmd := exec.CommandContext(context.Background(), "git", "-C", dir, "-c", "user.name=T", "-c", "user.email=t@example.com", "commit", "-m", "edit")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+	commit(map[string]string{"a.txt": "1\n2\n3\n4\n5\n6\n", "gone.txt": "x\n"})
+	commit(map[string]string{"a.txt": "1\nTWO\n3\n5\n6\n7\n8\n", "gone.txt": "", "new.txt": "n\n"})
+
+	changes, err := DiffLines(dir, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("DiffLines() error: %v", err)
+	}
+	want := []LineRange{{2, 2}, {4, 4}, {6, 7}}
+	if got := changes["a.txt"]; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("a.txt ranges = %v, want %v", got, want)
+	}
+	if got, ok := changes["gone.txt"]; !ok || got != nil {
+		t.Errorf("deleted file = %v, %v; want nil ranges", got, ok)
+	}
+	if got := changes["new.txt"]; len(got) != 1 || got[0] != (LineRange{1, 1}) {
+		t.Errorf("new.txt ranges = %v", got)
+	}
+	if !Contains(changes["a.txt"], 3, 4) || Contains(changes["a.txt"], 5, 5) {
+		t.Error("Contains() mismatch")
+	}
+}
@@ -0,0 +1,208 @@
+package history
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/gitutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// Candidate is a commit that looks like it records a decision, a learning
+// or a failure worth a postmortem.
+type Candidate struct {
+	Commit     gitutil.Commit
+	Kind       string // memory.ProposedAsDecision, ProposedAsLearning or ProposedAsPostmortem
+	Content    string
+	Context    string
+	Rationale  string
+	Confidence float64
+	Signals    []string
+	Extractor  string // "heuristic" or "llm"
+}
+
+var (
+	revertSubjectRe = regexp.MustCompile(`^Revert "(.+)"$`)
+	revertBodyRe    = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-f]{7,40})\.?\s*$`)
+	fixSubjectRe    = regexp.MustCompile(`(?i)^(fix(e[sd])?|bug ?fix|hot ?fix)\b|\bfix(e[sd])? #\d+`)
+	// conventionalRe matches Conventional Commits prefixes such as "feat(api)!: ".
+	conventionalRe = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?:\s*`)
+)
+
+// commitSignals are phrasings common in commit messages that memory.Classify,
+// tuned for notes typed by people and agents, does not weigh.
+var commitSignals = map[string][]*regexp.Regexp{
+	memory.ProposedAsDecision: {
+		regexp.MustCompile(`(?i)\b(switch(ed|es|ing)?|migrat(e|ed|es|ing)|mov(e|ed|ing)) (from \S+ )?to\b`),
+		regexp.MustCompile(`(?i)\breplac(e|ed|es|ing) .+ with\b`),
+		regexp.MustCompile(`(?i)\binstead of\b`),
+		regexp.MustCompile(`(?i)\bdeprecat(e|ed|es|ing)\b`),
+		regexp.MustCompile(`(?i)\bdrop(ped|s)? support\b`),
+		regexp.MustCompile(`(?i)^breaking[ -]change`),
+		regexp.MustCompile(`(?i)\badr[- ]?\d+`),
+	},
+	memory.ProposedAsLearning: {
+		regexp.MustCompile(`(?i)\broot cause\b`),
+		regexp.MustCompile(`(?i)\bthe (issue|problem|bug) (was|is)\b`),
+		regexp.MustCompile(`(?i)\bcaused by\b`),
+	},
+}
+
+// commitSignalWeight is the score one commit signal adds; a single match is
+// enough to pass DefaultMinConfidence.
+const commitSignalWeight = 0.6
+
+// weakSignals are memory.Classify signals that fire on most commit subjects
+// ("Add ...", "Use ...") and so are not enough on their own.
+var weakSignals = map[string]bool{
+	"use ": true, "using ": true, "implement ": true, "add ": true, "create ": true, "build ": true,
+	"adopt ": true, "integrate ": true, "configure ": true, "set up ": true, "enable ": true,
+	"with ": true, "for ": true, "via ": true, "through ": true, "pattern": true,
+	"try ": true, "test ": true, "must ": true, "always ": true, "never ": true,
+}
+
+// IsRevert reports whether a commit reverts another and returns the reverted
+// subject and SHA when the message names them.
+func IsRevert(c *gitutil.Commit) (subject, sha string, ok bool) {
+	if m := revertSubjectRe.FindStringSubmatch(c.Subject); m != nil {
+		subject, ok = m[1], true
+	}
+	if m := revertBodyRe.FindStringSubmatch(c.Body); m != nil {
+		sha, ok = m[1], true
+	}
+	return subject, sha, ok
+}
+
+// IsFix reports whether a commit message describes a bug fix.
+func IsFix(c *gitutil.Commit) bool {
+	return fixSubjectRe.MatchString(c.Subject)
+}
+
+// Classify returns the candidate a commit represents, or nil when the
+// message records nothing worth proposing.
+func Classify(c *gitutil.Commit, minConfidence float64) *Candidate {
+	if subject, sha, ok := IsRevert(c); ok {
+		return revertCandidate(c, subject, sha)
+	}
+
+	subject := conventionalRe.ReplaceAllString(c.Subject, "")
+	paragraphs := splitParagraphs(c.Body)
+
+	// A fix whose message explains the cause is a learning.
+	if IsFix(c) {
+		for _, p := range paragraphs {
+			kind, confidence, signals := classifyText(p)
+			if kind == memory.ProposedAsLearning && confidence >= minConfidence {
+				return &Candidate{
+					Commit: *c, Kind: kind, Content: p, Context: subject,
+					Confidence: confidence, Signals: signals, Extractor: "heuristic",
+				}
+			}
+		}
+	}
+
+	// Decisions are stated in the subject; the body, if any, is the rationale.
+	for _, text := range append([]string{subject}, paragraphs...) {
+		kind, confidence, signals := classifyText(text)
+		if kind == memory.ProposedAsDecision && confidence >= minConfidence {
+			return &Candidate{
+				Commit: *c, Kind: kind, Content: subject, Rationale: c.Body,
+				Confidence: confidence, Signals: signals, Extractor: "heuristic",
+			}
+		}
+	}
+	return nil
+}
+
+func revertCandidate(c *gitutil.Commit, subject, sha string) *Candidate {
+	if subject == "" {
+		subject = c.Subject
+	}
+	reverted := "a previous commit"
+	if sha != "" {
+		reverted = shortSHA(sha)
+	}
+
+	// Whatever the author wrote besides git's boilerplate is the reason.
+	reason := strings.TrimSpace(revertBodyRe.ReplaceAllString(c.Body, ""))
+	return &Candidate{
+		Commit:     *c,
+		Kind:       memory.ProposedAsPostmortem,
+		Content:    "Reverted: " + subject,
+		Context:    "Commit " + shortSHA(c.SHA) + " reverted " + reverted + " (\"" + subject + "\").",
+		Rationale:  reason,
+		Confidence: 0.9,
+		Signals:    []string{"revert"},
+		Extractor:  "heuristic",
+	}
+}
+
+// classifyText scores text with memory.Classify plus commit phrasing and
+// returns the decision or learning kind it most resembles. Kinds supported
+// only by weak signals score zero.
+func classifyText(text string) (kind string, confidence float64, signals []string) {
+	scores := map[string]float64{}
+	strong := map[string][]string{}
+
+	cls := memory.Classify(text)
+	if k := string(cls.Kind); k == memory.ProposedAsDecision || k == memory.ProposedAsLearning {
+		scores[k] = cls.Confidence
+		for _, s := range cls.Signals {
+			if !weakSignals[s] {
+				strong[k] = append(strong[k], s)
+			}
+		}
+	}
+	for k, patterns := range commitSignals {
+		for _, re := range patterns {
+			if m := re.FindString(text); m != "" {
+				scores[k] += commitSignalWeight
+				strong[k] = append(strong[k], strings.ToLower(m))
+			}
+		}
+	}
+
+	for _, k := range []string{memory.ProposedAsLearning, memory.ProposedAsDecision} {
+		if len(strong[k]) > 0 && scores[k] > confidence {
+			kind, confidence, signals = k, min(0.95, scores[k]), strong[k]
+		}
+	}
+	return kind, confidence, signals
+}
+
+// splitParagraphs returns the blank-line separated paragraphs of a commit
+// body, skipping trailers such as "Signed-off-by:".
+func splitParagraphs(body string) []string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(p, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || isTrailer(line) {
+				continue
+			}
+			lines = append(lines, strings.TrimLeft(line, "-* "))
+		}
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, " "))
+		}
+	}
+	return paragraphs
+}
+
+var trailerRe = regexp.MustCompile(`^[A-Z][A-Za-z-]+: \S`)
+
+func isTrailer(line string) bool {
+	if !trailerRe.MatchString(line) {
+		return false
+	}
+	key := line[:strings.Index(line, ":")]
+	return strings.Contains(key, "-") || key == "Fixes" || key == "Closes" || key == "Refs"
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/gitutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		kind          string // "" when nothing should be proposed
+		content       string // prefix of the expected content
+	}{
+		{"Switch from Redis to Postgres for session storage", "", memory.ProposedAsDecision, "Switch from Redis"},
+		{"feat(auth): use JWT instead of server-side sessions", "Sessions did not scale.", memory.ProposedAsDecision, "use JWT"},
+		{"Replace logrus with slog", "", memory.ProposedAsDecision, "Replace logrus"},
+		{"We decided to vendor the sqlite driver", "", memory.ProposedAsDecision, "We decided"},
+		{"Deprecate the v1 API", "", memory.ProposedAsDecision, "Deprecate"},
+		{"fix: nil map panic in cache warmup", "The root cause was that warmup ran before init.", memory.ProposedAsLearning, "The root cause"},
+		{"Fix flaky test", "Turns out the test depended on\nmap iteration order.\n\nSigned-off-by: A <a@example.com>", memory.ProposedAsLearning, "Turns out the test depended on map iteration order."},
+		{"Revert \"Enable HTTP/2\"", "This reverts commit 0123456789abcdef0123456789abcdef01234567.\n\nBroke proxies.", memory.ProposedAsPostmortem, "Reverted: Enable HTTP/2"},
+		{"Add logging to the server", "", "", ""},
+		{"Implement caching for auth tokens", "", "", ""},
+		{"Update README", "", "", ""},
+		{"Fix typo", "", "", ""},
+		{"Bump deps", "Signed-off-by: A <a@example.com>", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			got := Classify(&gitutil.Commit{SHA: "abc", Subject: tt.subject, Body: tt.body}, DefaultMinConfidence)
+			if tt.kind == "" {
+				if got != nil {
+					t.Errorf("Classify() = %s %q, want nothing", got.Kind, got.Content)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("Classify() = nil, want %s", tt.kind)
+			}
+			if got.Kind != tt.kind || !strings.HasPrefix(got.Content, tt.content) {
+				t.Errorf("Classify() = %s %q, want %s %q...", got.Kind, got.Content, tt.kind, tt.content)
+			}
+			if len(got.Signals) == 0 {
+				t.Error("Classify() returned no signals")
+			}
+		})
+	}
+}
+
+func TestRevertCandidate(t *testing.T) {
+	c := &gitutil.Commit{
+		SHA:     "fedcba9876543210",
+		Subject: "Revert \"Enable HTTP/2\"",
+		Body:    "This reverts commit 0123456789abcdef.\n\nBroke corporate proxies.",
+	}
+	subject, sha, ok := IsRevert(c)
+	if !ok || subject != "Enable HTTP/2" || sha != "0123456789abcdef" {
+		t.Fatalf("IsRevert() = %q, %q, %v", subject, sha, ok)
+	}
+	got := Classify(c, DefaultMinConfidence)
+	if got.Rationale != "Broke corporate proxies." {
+		t.Errorf("Rationale = %q", got.Rationale)
+	}
+	if !strings.Contains(got.Context, "fedcba98") || !strings.Contains(got.Context, "01234567") {
+		t.Errorf("Context = %q, want both commits", got.Context)
+	}
+}
+
+func TestIsFix(t *testing.T) {
+	for subject, want := range map[string]bool{
+		"Fix crash on empty input":   true,
+		"fix(parser): handle EOF":    true,
+		"Hotfix for login":           true,
+		"Handle EOF, fixes #12":      true,
+		"Add prefix option":          false,
+		"Refactor fixture loading":   false,
+		"Document the fixup process": false,
+	} {
+		if got := IsFix(&gitutil.Commit{Subject: subject}); got != want {
+			t.Errorf("IsFix(%q) = %v, want %v", subject, got, want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Package history mines git history for decisions, learnings and failures
+// worth a postmortem, and seeds file intelligence from it.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/fsutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/gitutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/llm"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+const (
+	// DefaultLimit is how many recent commits Mine examines.
+	DefaultLimit = 1000
+	// DefaultMinConfidence is the classification confidence a commit needs
+	// to become a proposal.
+	DefaultMinConfidence = 0.6
+
+	// proposalSource marks proposals created from git history.
+	proposalSource = "git-history"
+	// fileEditor is recorded as the last editor of files seeded from history.
+	fileEditor = "git"
+)
+
+// Options controls Mine.
+type Options struct {
+	Since         string     // only commits after this date (git --since syntax)
+	Limit         int        // commits to examine; DefaultLimit when zero
+	MinConfidence float64    // DefaultMinConfidence when zero
+	LLM           llm.Client // optional; reviews and rewrites heuristic candidates
+	DryRun        bool       // classify without writing to memory
+}
+
+// Result summarizes a mining run.
+type Result struct {
+	Examined   int         // commits not mined before
+	Skipped    int         // commits mined by an earlier run
+	Fixes      int         // fix commits
+	Reverts    int         // revert commits
+	Candidates []Candidate // commits classified as decision, learning or postmortem
+	Proposals  []string    // proposal IDs created
+	Duplicates int         // candidates matching an existing proposal
+	Unreviewed int         // candidates the LLM failed to review, left for the next run
+	Files      int         // files whose edit and failure counts were seeded
+}
+
+// Mine walks recent git history, proposes the decisions, learnings and
+// postmortems commit messages record, and adds commit counts to file_intel.
+// Commits are remembered, so mining again only examines new ones.
+func Mine(root string, mem *memory.Memory, opts Options) (*Result, error) {
+	if !gitutil.IsGitRepo(root) {
+		return nil, fmt.Errorf("%s is not a git repository", root)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultMinConfidence
+	}
+
+	commits, err := gitutil.Log(root, gitutil.LogOptions{Since: opts.Since, Limit: opts.Limit})
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, err := gitutil.GetRepoRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	guardrails := config.LoadGuardrails(root)
+
+	result := &Result{}
+	files := map[string]bool{}
+
+	// Oldest first, so proposals are listed in the order decisions were made.
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := &commits[i]
+		mined, err := mem.IsCommitMined(c.SHA)
+		if err != nil {
+			return nil, err
+		}
+		if mined {
+			result.Skipped++
+			continue
+		}
+		result.Examined++
+
+		_, _, revert := IsRevert(c)
+		fix := IsFix(c)
+		if revert {
+			result.Reverts++
+		} else if fix {
+			result.Fixes++
+		}
+		candidate := Classify(c, opts.MinConfidence)
+		if candidate != nil && opts.LLM != nil {
+			reviewed, err := review(opts.LLM, candidate)
+			if err != nil {
+				// Leave the commit unmined so the next run reviews it again.
+				log.Printf("history: %v", err)
+				result.Unreviewed++
+				continue
+			}
+			candidate = reviewed
+		}
+
+		changed := workspaceFiles(c.Files, repoRoot, root, guardrails)
+		for _, f := range changed {
+			files[f] = true
+		}
+
+		kind, proposalID := "", ""
+		if candidate != nil {
+			result.Candidates = append(result.Candidates, *candidate)
+			kind = candidate.Kind
+			if !opts.DryRun {
+				id, duplicate, err := propose(mem, candidate, changed)
+				if err != nil {
+					return nil, err
+				}
+				if duplicate {
+					result.Duplicates++
+				} else {
+					result.Proposals = append(result.Proposals, id)
+				}
+				proposalID = id
+			}
+		}
+		if !opts.DryRun {
+			err := mem.MarkCommitMined(memory.MinedCommit{
+				SHA:        c.SHA,
+				Kind:       kind,
+				ProposalID: proposalID,
+				Files:      changed,
+				Failure:    revert || fix,
+				Date:       c.Date,
+				Editor:     fileEditor,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Files = len(files)
+	return result, nil
+}
+
+// propose files a candidate as a pending proposal. It reports duplicate when
+// an identical proposal already exists.
+func propose(mem *memory.Memory, c *Candidate, files []string) (id string, duplicate bool, err error) {
+	scope, scopePath := "palace", ""
+	if len(files) == 1 && c.Kind != memory.ProposedAsPostmortem {
+		scope, scopePath = "file", files[0]
+	}
+
+	dedupeKey := memory.GenerateDedupeKey(c.Kind, c.Content, scope, scopePath)
+	if existing, _ := mem.CheckDuplicateProposal(dedupeKey); existing != nil {
+		return existing.ID, true, nil
+	}
+
+	evidence, err := json.Marshal(memory.EvidenceRef{
+		Commit:      c.Commit.SHA,
+		Files:       files,
+		Extractor:   c.Extractor,
+		Confidence:  c.Confidence,
+		Explanation: fmt.Sprintf("%s by %s on %s", shortSHA(c.Commit.SHA), c.Commit.Author, c.Commit.Date.Format("2006-01-02")),
+	})
+	if err != nil {
+		return "", false, err
+	}
+	signals, err := json.Marshal(c.Signals)
+	if err != nil {
+		return "", false, err
+	}
+
+	id, err = mem.AddProposal(memory.Proposal{
+		ProposedAs:               c.Kind,
+		Content:                  c.Content,
+		Context:                  c.Context,
+		Rationale:                c.Rationale,
+		Scope:                    scope,
+		ScopePath:                scopePath,
+		Source:                   proposalSource,
+		EvidenceRefs:             string(evidence),
+		ClassificationConfidence: c.Confidence,
+		ClassificationSignals:    string(signals),
+		DedupeKey:                dedupeKey,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("propose %s from %s: %w", c.Kind, shortSHA(c.Commit.SHA), err)
+	}
+	return id, false, nil
+}
+
+// workspaceFiles converts repository-relative paths to workspace-relative
+// ones, dropping files outside the workspace, deleted since, or guarded.
+func workspaceFiles(paths []string, repoRoot, root string, guardrails config.Guardrails) []string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	// Resolve symlinks so paths compare with git's resolved top level.
+	if resolved, err := filepath.EvalSymlinks(absRoot); err == nil {
+		absRoot = resolved
+	}
+
+	var files []string
+	for _, p := range paths {
+		abs := filepath.Join(repoRoot, filepath.FromSlash(p))
+		rel, err := filepath.Rel(absRoot, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if fsutil.MatchesGuardrail(rel, guardrails) {
+			continue
+		}
+		if _, err := os.Stat(abs); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		files = append(files, rel)
+	}
+	return files
+}
+
+const reviewPrompt = `You maintain a project knowledge base. Review this git commit, which a heuristic flagged as a possible %s.
+
+Decide what it records:
+- "decision": a technical choice or commitment the team made
+- "learning": an insight about the code, e.g. why a bug happened
+- "postmortem": a failure, e.g. a change that had to be reverted
+- "none": routine work with nothing worth remembering
+
+Respond with JSON: {"kind": "...", "content": "one self-contained sentence", "rationale": "why, from the commit message, or empty"}
+
+COMMIT %s
+%s`
+
+// review asks the LLM to confirm and rewrite a heuristic candidate. It
+// returns nil when the LLM judges the commit routine.
+func review(client llm.Client, c *Candidate) (*Candidate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := llm.DefaultCompletionOptions()
+	opts.SystemPrompt = "You extract durable project knowledge from commit history. Always respond with valid JSON."
+
+	var reply struct {
+		Kind      string `json:"kind"`
+		Content   string `json:"content"`
+		Rationale string `json:"rationale"`
+	}
+	prompt := fmt.Sprintf(reviewPrompt, c.Kind, shortSHA(c.Commit.SHA), c.Commit.Message())
+	if err := client.CompleteJSON(ctx, prompt, opts, &reply); err != nil {
+		return nil, fmt.Errorf("review commit %s: %w", shortSHA(c.Commit.SHA), err)
+	}
+
+	switch reply.Kind {
+	case memory.ProposedAsDecision, memory.ProposedAsLearning, memory.ProposedAsPostmortem:
+	default:
+		return nil, nil
+	}
+	reviewed := *c
+	reviewed.Kind = reply.Kind
+	reviewed.Extractor = "llm"
+	if content := strings.TrimSpace(reply.Content); content != "" {
+		reviewed.Content = content
+	}
+	if rationale := strings.TrimSpace(reply.Rationale); rationale != "" {
+		reviewed.Rationale = rationale
+	}
+	return &reviewed, nil
+}
@@ -0,0 +1,209 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/llm"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// gitRepo creates a repository in a temp dir. Each commit writes files
+// (path -> content, "" deletes) and commits them with message.
+func gitRepo(t *testing.T) (dir string, commit func(message string, files map[string]string)) {
+	t.Helper()
+	dir = t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "Dev")
+	git("config", "commit.gpgsign", "false")
+
+	return dir, func(message string, files map[string]string) {
+		t.Helper()
+		for path, content := range files {
+			full := filepath.Join(dir, path)
+			if content == "" {
+				git("rm", "-q", path)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", message)
+	}
+}
+
+func TestMine(t *testing.T) {
+	dir, commit := gitRepo(t)
+	commit("Initial import", map[string]string{"cache.go": "v1", "old.go": "v1"})
+	commit("Switch from memcached to Redis for the cache", map[string]string{"cache.go": "v2"})
+	commit("Fix stale entries\n\nThe root cause was a missing TTL on refresh.", map[string]string{"cache.go": "v3"})
+	commit("Enable compression", map[string]string{"cache.go": "v4", "old.go": "v2"})
+	commit("Revert \"Enable compression\"\n\nThis reverts commit 1234567.\n\nBroke older clients.", map[string]string{"cache.go": "v3", "old.go": ""})
+
+	mem, err := memory.Open(dir)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	dry, err := Mine(dir, mem, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Mine(DryRun) error: %v", err)
+	}
+	if len(dry.Candidates) != 3 || len(dry.Proposals) != 0 {
+		t.Fatalf("dry run: %d candidates, %d proposals; want 3 and 0", len(dry.Candidates), len(dry.Proposals))
+	}
+	if pending, _ := mem.GetProposals(memory.ProposalStatusPending, "", 10); len(pending) != 0 {
+		t.Fatalf("dry run stored %d proposals", len(pending))
+	}
+
+	result, err := Mine(dir, mem, Options{})
+	if err != nil {
+		t.Fatalf("Mine() error: %v", err)
+	}
+	if result.Examined != 5 || result.Fixes != 1 || result.Reverts != 1 || len(result.Proposals) != 3 {
+		t.Errorf("result = %+v", result)
+	}
+
+	kinds := map[string]*memory.Proposal{}
+	proposals, _ := mem.GetProposals(memory.ProposalStatusPending, "", 10)
+	for i := range proposals {
+		kinds[proposals[i].ProposedAs] = &proposals[i]
+	}
+	for _, kind := range []string{memory.ProposedAsDecision, memory.ProposedAsLearning, memory.ProposedAsPostmortem} {
+		p := kinds[kind]
+		if p == nil {
+			t.Errorf("no %s proposal", kind)
+			continue
+		}
+		var ref memory.EvidenceRef
+		if err := json.Unmarshal([]byte(p.EvidenceRefs), &ref); err != nil || len(ref.Commit) != 40 {
+			t.Errorf("%s evidence = %s (%v), want a commit SHA", kind, p.EvidenceRefs, err)
+		}
+		if p.Source != "git-history" {
+			t.Errorf("%s source = %q", kind, p.Source)
+		}
+	}
+	if p := kinds[memory.ProposedAsDecision]; p != nil && (p.Scope != "file" || p.ScopePath != "cache.go") {
+		t.Errorf("decision scope = %s:%s, want file:cache.go", p.Scope, p.ScopePath)
+	}
+
+	// cache.go: 5 edits, failures from the fix and the revert. old.go was deleted.
+	fi, _ := mem.GetFileIntel("cache.go")
+	if fi.EditCount != 5 || fi.FailureCount != 2 || fi.LastEditor != "git" || fi.LastEdited.IsZero() {
+		t.Errorf("cache.go intel = %+v", fi)
+	}
+	if fi, _ := mem.GetFileIntel("old.go"); fi.EditCount != 0 {
+		t.Errorf("deleted file seeded: %+v", fi)
+	}
+
+	// Approving the postmortem candidate creates a postmortem.
+	if p := kinds[memory.ProposedAsPostmortem]; p != nil {
+		id, err := mem.ApproveProposal(p.ID, "test", "")
+		if err != nil {
+			t.Fatalf("ApproveProposal() error: %v", err)
+		}
+		pm, err := mem.GetPostmortem(id)
+		if err != nil {
+			t.Fatalf("GetPostmortem() error: %v", err)
+		}
+		if pm.RootCause != "Broke older clients." || len(pm.AffectedFiles) != 1 {
+			t.Errorf("postmortem = %+v", pm)
+		}
+	}
+
+	// Mining again only looks at new commits.
+	commit("Use slog instead of log", map[string]string{"cache.go": "v5"})
+	again, err := Mine(dir, mem, Options{})
+	if err != nil {
+		t.Fatalf("second Mine() error: %v", err)
+	}
+	if again.Examined != 1 || again.Skipped != 5 || len(again.Proposals) != 1 {
+		t.Errorf("second run = %+v", again)
+	}
+	if fi, _ := mem.GetFileIntel("cache.go"); fi.EditCount != 6 || fi.FailureCount != 2 {
+		t.Errorf("cache.go intel after second run = %+v", fi)
+	}
+}
+
+// reviewLLM confirms every candidate as a decision, except that it fails
+// for prompts containing failOn.
+type reviewLLM struct{ failOn string }
+
+func (f reviewLLM) Complete(context.Context, string, llm.CompletionOptions) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (f reviewLLM) CompleteJSON(_ context.Context, prompt string, _ llm.CompletionOptions, result interface{}) error {
+	if f.failOn != "" && strings.Contains(prompt, f.failOn) {
+		return errors.New("backend unavailable")
+	}
+	return json.Unmarshal([]byte(`{"kind":"decision"}`), result)
+}
+
+func (f reviewLLM) Model() string   { return "fake" }
+func (f reviewLLM) Backend() string { return "fake" }
+
+func TestMineReviewFailure(t *testing.T) {
+	dir, commit := gitRepo(t)
+	commit("Initial import", map[string]string{"cache.go": "v1"})
+	commit("Switch from memcached to Redis for the cache", map[string]string{"cache.go": "v2"})
+	commit("Fix stale entries\n\nThe root cause was a missing TTL on refresh.", map[string]string{"cache.go": "v3"})
+
+	mem, err := memory.Open(dir)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	// A failed review skips that commit instead of aborting the run.
+	result, err := Mine(dir, mem, Options{LLM: reviewLLM{failOn: "Redis"}})
+	if err != nil {
+		t.Fatalf("Mine() error: %v", err)
+	}
+	if result.Examined != 3 || result.Unreviewed != 1 || len(result.Proposals) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The unreviewed commit was not marked mined, so the next run retries it.
+	again, err := Mine(dir, mem, Options{LLM: reviewLLM{}})
+	if err != nil {
+		t.Fatalf("second Mine() error: %v", err)
+	}
+	if again.Examined != 1 || again.Skipped != 2 || again.Unreviewed != 0 || len(again.Proposals) != 1 {
+		t.Errorf("second run = %+v", again)
+	}
+}
+
+func TestMineNotARepo(t *testing.T) {
+	dir := t.TempDir()
+	mem, err := memory.Open(dir)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	if _, err := Mine(dir, mem, Options{}); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
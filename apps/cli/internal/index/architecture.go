@@ -0,0 +1,183 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// ArchitectureViolation is an import that breaks an architecture rule.
+type ArchitectureViolation struct {
+	Rule            string `json:"rule"` // rule ID from palace.jsonc
+	Kind            string `json:"kind"` // forbidden, not-allowed, restricted or cycle
+	SourceFile      string `json:"sourceFile"`
+	TargetFile      string `json:"targetFile"`
+	Import          string `json:"import,omitempty"`
+	Line            int    `json:"line,omitempty"`
+	SourceComponent string `json:"sourceComponent,omitempty"`
+	TargetComponent string `json:"targetComponent,omitempty"`
+	Severity        string `json:"severity"` // error or warning
+	Message         string `json:"message"`
+	FirstSeenScan   int64  `json:"firstSeenScan,omitempty"`
+	ResolvedScan    int64  `json:"resolvedScan,omitempty"`
+}
+
+// Key identifies a violation across scans.
+func (v *ArchitectureViolation) Key() string {
+	return v.Rule + "\x00" + v.SourceFile + "\x00" + v.TargetFile
+}
+
+// ArchitectureDiff compares the violations of one scan with the scan before.
+type ArchitectureDiff struct {
+	ScanID   int64                   `json:"scanId"`
+	Open     int                     `json:"open"`
+	New      []ArchitectureViolation `json:"new"`
+	Resolved []ArchitectureViolation `json:"resolved"`
+}
+
+const architectureColumns = `rule, kind, source_file, target_file, import_path, line, source_component, target_component,
+	severity, message, first_seen_scan, COALESCE(resolved_scan, 0)`
+
+// ReplaceArchitectureViolations stores the violations found for scanID.
+// Violations that were already open keep the scan they were first seen in;
+// open violations missing from list are marked resolved by scanID, and
+// those resolved by earlier scans are dropped.
+func ReplaceArchitectureViolations(db *sql.DB, scanID int64, list []ArchitectureViolation) (*ArchitectureDiff, error) {
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM architecture_violations WHERE resolved_scan IS NOT NULL;`); err != nil {
+		return nil, fmt.Errorf("drop resolved violations: %w", err)
+	}
+	previous, err := queryArchitectureViolations(ctx, tx, `resolved_scan IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]*ArchitectureViolation, len(previous))
+	for i := range previous {
+		open[previous[i].Key()] = &previous[i]
+	}
+
+	diff := &ArchitectureDiff{ScanID: scanID}
+	seen := make(map[string]bool, len(list))
+	for i := range list {
+		v := list[i]
+		key := v.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		diff.Open++
+
+		v.FirstSeenScan, v.ResolvedScan = scanID, 0
+		if old, ok := open[key]; ok {
+			v.FirstSeenScan = old.FirstSeenScan
+			delete(open, key)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO architecture_violations(`+
+			`rule, kind, source_file, target_file, import_path, line, source_component, target_component, severity, message, first_seen_scan)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+			v.Rule, v.Kind, v.SourceFile, v.TargetFile, v.Import, v.Line, v.SourceComponent, v.TargetComponent,
+			v.Severity, v.Message, v.FirstSeenScan); err != nil {
+			return nil, fmt.Errorf("insert violation %s: %w", v.SourceFile, err)
+		}
+		if v.FirstSeenScan == scanID {
+			diff.New = append(diff.New, v)
+		}
+	}
+
+	for _, v := range open {
+		if _, err := tx.ExecContext(ctx, `UPDATE architecture_violations SET resolved_scan = ?
+			WHERE rule = ? AND source_file = ? AND target_file = ?;`, scanID, v.Rule, v.SourceFile, v.TargetFile); err != nil {
+			return nil, fmt.Errorf("resolve violation %s: %w", v.SourceFile, err)
+		}
+		v.ResolvedScan = scanID
+		diff.Resolved = append(diff.Resolved, *v)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	SortArchitectureViolations(diff.New)
+	SortArchitectureViolations(diff.Resolved)
+	return diff, nil
+}
+
+// GetArchitectureViolations returns the open violations whose importing file
+// is path, or every open violation when path is empty.
+func GetArchitectureViolations(db *sql.DB, path string) ([]ArchitectureViolation, error) {
+	where, args := `resolved_scan IS NULL`, []any{}
+	if path != "" {
+		where, args = where+` AND source_file = ?`, append(args, path)
+	}
+	list, err := queryArchitectureViolations(context.Background(), db, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	SortArchitectureViolations(list)
+	return list, nil
+}
+
+// GetArchitectureDiff returns what the architecture pass of scanID changed:
+// violations first seen in it and violations it resolved.
+func GetArchitectureDiff(db *sql.DB, scanID int64) (*ArchitectureDiff, error) {
+	ctx := context.Background()
+	diff := &ArchitectureDiff{ScanID: scanID}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM architecture_violations WHERE resolved_scan IS NULL;`).Scan(&diff.Open); err != nil {
+		return nil, err
+	}
+	var err error
+	if diff.New, err = queryArchitectureViolations(ctx, db, `resolved_scan IS NULL AND first_seen_scan = ?`, scanID); err != nil {
+		return nil, err
+	}
+	if diff.Resolved, err = queryArchitectureViolations(ctx, db, `resolved_scan = ?`, scanID); err != nil {
+		return nil, err
+	}
+	SortArchitectureViolations(diff.New)
+	SortArchitectureViolations(diff.Resolved)
+	return diff, nil
+}
+
+// SortArchitectureViolations orders violations by file, line and rule.
+func SortArchitectureViolations(list []ArchitectureViolation) {
+	sort.Slice(list, func(i, j int) bool {
+		a, b := &list[i], &list[j]
+		if a.SourceFile != b.SourceFile {
+			return a.SourceFile < b.SourceFile
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.TargetFile < b.TargetFile
+	})
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryArchitectureViolations(ctx context.Context, q queryer, where string, args ...any) ([]ArchitectureViolation, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+architectureColumns+` FROM architecture_violations WHERE `+where+`;`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query architecture violations: %w", err)
+	}
+	defer rows.Close()
+
+	var list []ArchitectureViolation
+	for rows.Next() {
+		var v ArchitectureViolation
+		if err := rows.Scan(&v.Rule, &v.Kind, &v.SourceFile, &v.TargetFile, &v.Import, &v.Line, &v.SourceComponent,
+			&v.TargetComponent, &v.Severity, &v.Message, &v.FirstSeenScan, &v.ResolvedScan); err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, rows.Err()
+}
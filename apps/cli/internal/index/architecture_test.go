@@ -0,0 +1,138 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadImportGraph(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "web", "shared"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "web", "shared", "package.json"), []byte(`{"name": "@acme/shared"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	for _, f := range []string{
+		"cmd/main.go", "internal/store/db.go", "internal/store/query.go", "internal/store/db_test.go",
+		"web/app.ts", "web/lib/format.ts", "web/shared/index.ts", "web/shared/button.tsx",
+		"py/pkg/__init__.py", "py/pkg/models.py", "py/pkg/views.py",
+	} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO files(path, hash, size, mod_time, indexed_at) VALUES (?, 'h', 1, '', '');`, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	imports := [][3]any{
+		{"cmd/main.go", "example.com/app/internal/store", 3},
+		{"cmd/main.go", "fmt", 4},
+		{"web/app.ts", "./lib/format", 1},
+		{"web/app.ts", "@acme/shared/button", 2},
+		{"web/app.ts", "react", 3},
+		{"web/lib/format.ts", "../shared", 1},
+		{"py/pkg/views.py", ".models", 1},
+		{"py/pkg/views.py", "py.pkg", 2},
+	}
+	for _, imp := range imports {
+		if _, err := db.ExecContext(ctx, `INSERT INTO relationships(source_file, target_file, kind, line) VALUES (?, ?, 'import', ?);`,
+			imp[0], imp[1], imp[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edges, err := LoadImportGraph(db, root)
+	if err != nil {
+		t.Fatalf("LoadImportGraph() error = %v", err)
+	}
+	got := make(map[string][]string)
+	for _, e := range edges {
+		got[e.Source+" "+e.Import] = append(got[e.Source+" "+e.Import], e.Target)
+	}
+	// A Go import names a package: every file of it but the tests.
+	want := map[string][]string{
+		"cmd/main.go example.com/app/internal/store": {"internal/store/db.go", "internal/store/query.go"},
+		"web/app.ts ./lib/format":                    {"web/lib/format.ts"},
+		"web/app.ts @acme/shared/button":             {"web/shared/button.tsx"},
+		"web/lib/format.ts ../shared":                {"web/shared/index.ts"},
+		"py/pkg/views.py .models":                    {"py/pkg/models.py"},
+		"py/pkg/views.py py.pkg":                     {"py/pkg/__init__.py"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("edges = %v", got)
+	}
+	for k, v := range want {
+		if !reflect.DeepEqual(got[k], v) {
+			t.Errorf("%s resolved to %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestReplaceArchitectureViolations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	v := func(source, target string) ArchitectureViolation {
+		return ArchitectureViolation{Rule: "no-ui", Kind: "forbidden", SourceFile: source, TargetFile: target,
+			Severity: "error", Message: "domain must not import ui"}
+	}
+
+	diff, err := ReplaceArchitectureViolations(db, 1, []ArchitectureViolation{v("a.go", "ui.go"), v("b.go", "ui.go")})
+	if err != nil {
+		t.Fatalf("ReplaceArchitectureViolations() error = %v", err)
+	}
+	if diff.Open != 2 || len(diff.New) != 2 || len(diff.Resolved) != 0 {
+		t.Errorf("first diff = %+v", diff)
+	}
+
+	diff, err = ReplaceArchitectureViolations(db, 2, []ArchitectureViolation{v("b.go", "ui.go"), v("c.go", "ui.go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Open != 2 || len(diff.New) != 1 || diff.New[0].SourceFile != "c.go" ||
+		len(diff.Resolved) != 1 || diff.Resolved[0].SourceFile != "a.go" {
+		t.Errorf("second diff = %+v", diff)
+	}
+
+	stored, err := GetArchitectureDiff(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Open != 2 || len(stored.New) != 1 || len(stored.Resolved) != 1 {
+		t.Errorf("stored diff = %+v", stored)
+	}
+
+	open, err := GetArchitectureViolations(db, "b.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].FirstSeenScan != 1 {
+		t.Errorf("open violations of b.go = %+v", open)
+	}
+
+	// The next pass drops what scan 2 resolved.
+	if _, err := ReplaceArchitectureViolations(db, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	var rows int
+	if err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM architecture_violations;`).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Errorf("rows = %d, want the 2 resolved by scan 3", rows)
+	}
+}
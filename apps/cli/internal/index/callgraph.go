@@ -5,14 +5,19 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"path"
+	"strings"
 )
 
 // CallSite represents a location where a function is called.
 type CallSite struct {
-	FilePath     string `json:"filePath"`
-	Line         int    `json:"line"`
-	CallerSymbol string `json:"callerSymbol,omitempty"` // The function that contains this call
-	CalleeSymbol string `json:"calleeSymbol"`           // The function being called
+	FilePath       string `json:"filePath"`
+	Line           int    `json:"line"`
+	CallerSymbol   string `json:"callerSymbol,omitempty"`   // The function that contains this call
+	CalleeSymbol   string `json:"calleeSymbol"`             // The function being called
+	CallerSymbolID int64  `json:"callerSymbolId,omitempty"` // symbols.id of the caller, if known
+	CalleeSymbolID int64  `json:"calleeSymbolId,omitempty"` // symbols.id of the callee when resolved
+	Resolution     string `json:"resolution"`               // "resolved" or "heuristic"
 }
 
 // CallGraph represents the complete call graph for a scope.
@@ -22,26 +27,146 @@ type CallGraph struct {
 	OutgoingCalls []CallSite `json:"outgoingCalls"` // What does this call
 }
 
+// heuristicCallMatch matches calls by target name. It is only applied to
+// calls the resolver could not link to a symbol.
+// Also handles Dart patterns like "get foo", "set foo".
+const heuristicCallMatch = `(r.target_symbol_id IS NULL
+	AND (r.target_symbol = ?
+	     OR r.target_symbol LIKE ?
+	     OR r.target_symbol LIKE ?
+	     OR r.target_symbol LIKE ?
+	     OR r.target_symbol LIKE ?
+	     OR r.target_symbol LIKE ?))`
+
+func heuristicCallArgs(symbolName string) []any {
+	return []any{symbolName, "%." + symbolName, "%::" + symbolName, "% " + symbolName, "%/" + symbolName + ".dart", symbolName + "%"}
+}
+
+// AmbiguousSymbolError is returned when a symbol name matches several
+// callable definitions and callers cannot be attributed to one of them.
+type AmbiguousSymbolError struct {
+	Name       string
+	Candidates []string // "file:line Owner.name"
+}
+
+func (e *AmbiguousSymbolError) Error() string {
+	return fmt.Sprintf("%q has %d definitions (%s); qualify it with its type or package, e.g. \"Type.%s\", or give its file",
+		e.Name, len(e.Candidates), strings.Join(e.Candidates, ", "), e.Name)
+}
+
+// callTarget is a callable definition a symbol name can refer to.
+type callTarget struct {
+	id    int64
+	file  string
+	start int
+	end   int
+	owner string // enclosing type name (parent symbol or Go receiver type)
+}
+
+// findCallTarget returns the callable definition symbolName refers to, or
+// nil when it names none. A qualifier ("Store.Close", "Logger::info",
+// "config.Load") must match the definition's type or package directory;
+// filePath and line, when set, must match its file and fall in its body.
+// A name matching several definitions is an *AmbiguousSymbolError.
+func findCallTarget(db *sql.DB, symbolName, filePath string, line int) (*callTarget, error) {
+	qualifier, name := splitCallTarget(symbolName)
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT s.id, s.file_path, s.line_start, s.line_end, s.kind, COALESCE(s.signature, ''), COALESCE(p.name, '')
+		FROM symbols s
+		LEFT JOIN symbols p ON p.id = s.parent_id
+		WHERE s.name = ? AND (s.file_path = ? OR ? = '')
+		ORDER BY s.file_path, s.line_start;
+	`, name, filePath, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var all []callTarget
+	for rows.Next() {
+		var t callTarget
+		var kind, signature string
+		if err := rows.Scan(&t.id, &t.file, &t.start, &t.end, &kind, &signature, &t.owner); err != nil {
+			return nil, err
+		}
+		if !callableKinds[kind] || (line > 0 && (line < t.start || line > t.end)) {
+			continue
+		}
+		if t.owner == "" && kind == "method" {
+			_, t.owner = goReceiver(signature)
+		}
+		all = append(all, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	targets := all
+	if qualifier != "" {
+		targets = nil
+		for _, t := range all {
+			if strings.EqualFold(t.owner, qualifier) || (t.owner == "" && strings.EqualFold(path.Base(path.Dir(t.file)), qualifier)) {
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	switch len(targets) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &targets[0], nil
+	}
+	ambiguous := &AmbiguousSymbolError{Name: name}
+	for _, t := range targets {
+		label := name
+		if t.owner != "" {
+			label = t.owner + "." + name
+		}
+		ambiguous.Candidates = append(ambiguous.Candidates, fmt.Sprintf("%s:%d %s", t.file, t.start, label))
+	}
+	return nil, ambiguous
+}
+
 // GetIncomingCalls returns all locations that call the given symbol.
+// Calls resolved to its definition are returned first-class; unresolved
+// calls are matched by name and marked heuristic.
 // symbolName can be:
 //   - Simple name: "parseConfig"
-//   - Qualified name: "config.Parse"
+//   - Qualified by type or package: "Store.Close", "config.Parse"
 //   - Dart getter: "get userId" (searches for "userId")
+//
+// A simple name with several definitions is an *AmbiguousSymbolError;
+// GetIncomingCallsIn narrows it down by file.
 func GetIncomingCalls(db *sql.DB, symbolName string) ([]CallSite, error) {
-	// Search for calls where target_symbol matches or ends with the symbol name
-	// Also handles Dart patterns like "get foo", "set foo"
+	return GetIncomingCallsIn(db, symbolName, "")
+}
+
+// GetIncomingCallsIn is GetIncomingCalls for the definition in filePath.
+func GetIncomingCallsIn(db *sql.DB, symbolName, filePath string) ([]CallSite, error) {
+	target, err := findCallTarget(db, symbolName, filePath, 0)
+	if err != nil {
+		return nil, err
+	}
+	_, name := splitCallTarget(symbolName)
+	if target == nil {
+		return incomingCalls(db, 0, name)
+	}
+	return incomingCalls(db, target.id, name)
+}
+
+// incomingCalls returns calls resolved to symbolID plus heuristic matches
+// by name. A symbolID of 0 only returns the heuristic matches.
+func incomingCalls(db *sql.DB, symbolID int64, symbolName string) ([]CallSite, error) {
+	args := append([]any{symbolID}, heuristicCallArgs(symbolName)...)
 	rows, err := db.QueryContext(context.Background(), `
-		SELECT r.source_file, r.line, r.target_symbol
+		SELECT r.source_file, r.line, r.target_symbol, r.target_symbol_id, r.source_symbol_id, COALESCE(src.name, '')
 		FROM relationships r
+		LEFT JOIN symbols src ON src.id = r.source_symbol_id
 		WHERE r.kind = 'call'
-		AND (r.target_symbol = ?
-		     OR r.target_symbol LIKE ?
-		     OR r.target_symbol LIKE ?
-		     OR r.target_symbol LIKE ?
-		     OR r.target_symbol LIKE ?
-		     OR r.target_symbol LIKE ?)
+		AND (r.target_symbol_id = ? OR `+heuristicCallMatch+`)
 		ORDER BY r.source_file, r.line;
-	`, symbolName, "%."+symbolName, "%::"+symbolName, "% "+symbolName, "%/"+symbolName+".dart", symbolName+"%")
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query incoming calls: %w", err)
 	}
@@ -50,42 +175,133 @@ func GetIncomingCalls(db *sql.DB, symbolName string) ([]CallSite, error) {
 	var calls []CallSite
 	for rows.Next() {
 		var cs CallSite
-		if err := rows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol); err != nil {
+		var calleeID, callerID sql.NullInt64
+		if err := rows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol, &calleeID, &callerID, &cs.CallerSymbol); err != nil {
 			return nil, err
 		}
+		setResolution(&cs, calleeID)
 
-		// Try to find the enclosing function for this call
-		cs.CallerSymbol = findEnclosingSymbol(db, cs.FilePath, cs.Line)
+		if callerID.Valid && cs.CallerSymbol != "" {
+			cs.CallerSymbolID = callerID.Int64
+		} else {
+			// Try to find the enclosing function for this call
+			cs.CallerSymbolID, cs.CallerSymbol = findEnclosingSymbol(db, cs.FilePath, cs.Line)
+		}
 		calls = append(calls, cs)
 	}
 
 	return calls, rows.Err()
 }
 
+// setResolution marks a call site resolved when it links to a symbol.
+func setResolution(cs *CallSite, calleeID sql.NullInt64) {
+	if calleeID.Valid {
+		cs.CalleeSymbolID = calleeID.Int64
+		cs.Resolution = ResolutionResolved
+		return
+	}
+	cs.Resolution = ResolutionHeuristic
+}
+
+// Reference is a location that refers to a symbol by name.
+type Reference struct {
+	FilePath     string `json:"filePath"`
+	Line         int    `json:"line"`
+	Kind         string `json:"kind"`         // "call" or "reference"
+	TargetSymbol string `json:"targetSymbol"` // Name as written at the site, possibly qualified
+}
+
+// GetReferences returns call and reference sites that target symbolName.
+// Unlike GetIncomingCalls it matches the name exactly, either bare or as the
+// last segment of a qualified name ("pkg.Name", "Type::Name"), so that
+// "Parse" does not match calls to "ParseConfig".
+func GetReferences(db *sql.DB, symbolName string) ([]Reference, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT r.source_file, r.line, r.kind, r.target_symbol
+		FROM relationships r
+		WHERE r.kind IN ('call', 'reference')
+		AND (r.target_symbol = ?
+		     OR r.target_symbol LIKE ?
+		     OR r.target_symbol LIKE ?)
+		ORDER BY r.source_file, r.line;
+	`, symbolName, "%."+symbolName, "%::"+symbolName)
+	if err != nil {
+		return nil, fmt.Errorf("query references: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []Reference
+	for rows.Next() {
+		var ref Reference
+		if err := rows.Scan(&ref.FilePath, &ref.Line, &ref.Kind, &ref.TargetSymbol); err != nil {
+			return nil, err
+		}
+		// LIKE treats '_' as a wildcard; re-check the suffix exactly.
+		if ref.TargetSymbol != symbolName &&
+			!strings.HasSuffix(ref.TargetSymbol, "."+symbolName) &&
+			!strings.HasSuffix(ref.TargetSymbol, "::"+symbolName) {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// callScope is a symbol definition whose body is searched for calls.
+type callScope struct {
+	id        int64
+	name      string
+	filePath  string
+	lineStart int
+	lineEnd   int
+}
+
+// lookupCallScope finds a symbol by ID, or by name within an optional file.
+func lookupCallScope(db *sql.DB, symbolID int64, symbolName, filePath string) (*callScope, error) {
+	var row *sql.Row
+	if symbolID != 0 {
+		row = db.QueryRowContext(context.Background(), `
+			SELECT id, name, file_path, line_start, line_end
+			FROM symbols
+			WHERE id = ?;
+		`, symbolID)
+	} else {
+		row = db.QueryRowContext(context.Background(), `
+			SELECT id, name, file_path, line_start, line_end
+			FROM symbols
+			WHERE name = ? AND (file_path = ? OR ? = '')
+			LIMIT 1;
+		`, symbolName, filePath, filePath)
+	}
+
+	var s callScope
+	if err := row.Scan(&s.id, &s.name, &s.filePath, &s.lineStart, &s.lineEnd); err != nil {
+		return nil, fmt.Errorf("symbol not found: %s", symbolName)
+	}
+	return &s, nil
+}
+
 // GetOutgoingCalls returns all functions/methods called by the given symbol.
 func GetOutgoingCalls(db *sql.DB, symbolName, filePath string) ([]CallSite, error) {
 	// First, find the symbol to get its line range
-	var startLine, endLine int
-	err := db.QueryRowContext(context.Background(), `
-		SELECT line_start, line_end
-		FROM symbols
-		WHERE name = ? AND (file_path = ? OR ? = '')
-		LIMIT 1;
-	`, symbolName, filePath, filePath).Scan(&startLine, &endLine)
+	scope, err := lookupCallScope(db, 0, symbolName, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("symbol not found: %s", symbolName)
+		return nil, err
 	}
+	return outgoingCalls(db, scope)
+}
 
-	// Find all calls within that line range
-	query := `
-		SELECT r.source_file, r.line, r.target_symbol
+// outgoingCalls returns the calls made within a symbol's line range.
+func outgoingCalls(db *sql.DB, scope *callScope) ([]CallSite, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT r.source_file, r.line, r.target_symbol, r.target_symbol_id
 		FROM relationships r
 		WHERE r.kind = 'call'
 		AND r.source_file = ?
 		AND r.line >= ? AND r.line <= ?
 		ORDER BY r.line;
-	`
-	rows, err := db.QueryContext(context.Background(), query, filePath, startLine, endLine)
+	`, scope.filePath, scope.lineStart, scope.lineEnd)
 	if err != nil {
 		return nil, fmt.Errorf("query outgoing calls: %w", err)
 	}
@@ -93,11 +309,12 @@ func GetOutgoingCalls(db *sql.DB, symbolName, filePath string) ([]CallSite, erro
 
 	var calls []CallSite
 	for rows.Next() {
-		var cs CallSite
-		cs.CallerSymbol = symbolName
-		if err := rows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol); err != nil {
+		cs := CallSite{CallerSymbol: scope.name, CallerSymbolID: scope.id}
+		var calleeID sql.NullInt64
+		if err := rows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol, &calleeID); err != nil {
 			return nil, err
 		}
+		setResolution(&cs, calleeID)
 		calls = append(calls, cs)
 	}
 
@@ -112,7 +329,7 @@ func GetCallGraph(db *sql.DB, filePath string) (*CallGraph, error) {
 
 	// Get all calls made from this file
 	outRows, err := db.QueryContext(context.Background(), `
-		SELECT source_file, line, target_symbol
+		SELECT source_file, line, target_symbol, target_symbol_id
 		FROM relationships
 		WHERE kind = 'call' AND source_file = ?
 		ORDER BY line;
@@ -124,33 +341,39 @@ func GetCallGraph(db *sql.DB, filePath string) (*CallGraph, error) {
 
 	for outRows.Next() {
 		var cs CallSite
-		if err := outRows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol); err != nil {
+		var calleeID sql.NullInt64
+		if err := outRows.Scan(&cs.FilePath, &cs.Line, &cs.CalleeSymbol, &calleeID); err != nil {
 			return nil, err
 		}
-		cs.CallerSymbol = findEnclosingSymbol(db, cs.FilePath, cs.Line)
+		setResolution(&cs, calleeID)
+		cs.CallerSymbolID, cs.CallerSymbol = findEnclosingSymbol(db, cs.FilePath, cs.Line)
 		result.OutgoingCalls = append(result.OutgoingCalls, cs)
 	}
 
 	// Get all calls to symbols defined in this file
 	// First get all symbols in the file
-	symRows, err := db.QueryContext(context.Background(), `SELECT name FROM symbols WHERE file_path = ?;`, filePath)
+	symRows, err := db.QueryContext(context.Background(), `SELECT id, name FROM symbols WHERE file_path = ?;`, filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer symRows.Close()
 
-	var symbols []string
+	type fileSymbol struct {
+		id   int64
+		name string
+	}
+	var symbols []fileSymbol
 	for symRows.Next() {
-		var name string
-		if err := symRows.Scan(&name); err != nil {
+		var sym fileSymbol
+		if err := symRows.Scan(&sym.id, &sym.name); err != nil {
 			return nil, err
 		}
-		symbols = append(symbols, name)
+		symbols = append(symbols, sym)
 	}
 
 	// Find calls to each symbol
 	for _, sym := range symbols {
-		inCalls, err := GetIncomingCalls(db, sym)
+		inCalls, err := incomingCalls(db, sym.id, sym.name)
 		if err != nil {
 			continue
 		}
@@ -166,20 +389,21 @@ func GetCallGraph(db *sql.DB, filePath string) (*CallGraph, error) {
 }
 
 // findEnclosingSymbol finds the function/method that contains the given line
-func findEnclosingSymbol(db *sql.DB, filePath string, line int) string {
+func findEnclosingSymbol(db *sql.DB, filePath string, line int) (int64, string) {
+	var id int64
 	var name string
 	err := db.QueryRowContext(context.Background(), `
-		SELECT name FROM symbols
+		SELECT id, name FROM symbols
 		WHERE file_path = ?
 		AND line_start <= ? AND line_end >= ?
 		AND kind IN ('function', 'method')
 		ORDER BY (line_end - line_start) ASC
 		LIMIT 1;
-	`, filePath, line, line).Scan(&name)
+	`, filePath, line, line).Scan(&id, &name)
 	if err != nil {
-		return ""
+		return 0, ""
 	}
-	return name
+	return id, name
 }
 
 // GetCallersCount returns the number of places a symbol is called.
@@ -188,8 +412,10 @@ func GetCallersCount(db *sql.DB, symbolName string) (int, error) {
 	err := db.QueryRowContext(context.Background(), `
 		SELECT COUNT(*) FROM relationships
 		WHERE kind = 'call'
-		AND (target_symbol = ? OR target_symbol LIKE ? OR target_symbol LIKE ?)
-	`, symbolName, "%."+symbolName, "%::"+symbolName).Scan(&count)
+		AND (target_symbol_id IN (SELECT id FROM symbols WHERE name = ?)
+		     OR (target_symbol_id IS NULL
+		         AND (target_symbol = ? OR target_symbol LIKE ? OR target_symbol LIKE ?)))
+	`, symbolName, symbolName, "%."+symbolName, "%::"+symbolName).Scan(&count)
 	return count, err
 }
 
@@ -235,11 +461,12 @@ func GetMostCalledSymbols(db *sql.DB, limit int) ([]struct {
 
 // CallChainNode represents a node in the call chain tree.
 type CallChainNode struct {
-	Symbol   string           `json:"symbol"`
-	FilePath string           `json:"filePath,omitempty"`
-	Line     int              `json:"line,omitempty"`
-	Depth    int              `json:"depth"`
-	Children []*CallChainNode `json:"children,omitempty"`
+	Symbol     string           `json:"symbol"`
+	FilePath   string           `json:"filePath,omitempty"`
+	Line       int              `json:"line,omitempty"`
+	Depth      int              `json:"depth"`
+	Resolution string           `json:"resolution,omitempty"` // "resolved" or "heuristic"
+	Children   []*CallChainNode `json:"children,omitempty"`
 }
 
 // CallChainResult represents the result of a call chain trace.
@@ -252,9 +479,24 @@ type CallChainResult struct {
 	Truncated  bool             `json:"truncated,omitempty"`
 }
 
+// callChainKey identifies a symbol in a chain: by ID once resolved, by name otherwise.
+func callChainKey(id int64, name string) string {
+	if id != 0 {
+		return fmt.Sprintf("#%d", id)
+	}
+	return name
+}
+
 // GetCallChainUp traces callers recursively up to maxDepth.
 // Returns all paths from entry points down to the target symbol.
 func GetCallChainUp(db *sql.DB, symbolName string, maxDepth int) (*CallChainResult, error) {
+	return GetCallChainAt(db, symbolName, "", 0, "up", maxDepth)
+}
+
+// callChainUp traces callers of symbolID, or only the heuristic matches of
+// symbolName when symbolID is 0. Resolved callers are followed by ID so that
+// unrelated symbols sharing a name do not join the chain.
+func callChainUp(db *sql.DB, symbolID int64, symbolName string, maxDepth int) (*CallChainResult, error) {
 	if maxDepth <= 0 {
 		maxDepth = 3
 	}
@@ -274,8 +516,8 @@ func GetCallChainUp(db *sql.DB, symbolName string, maxDepth int) (*CallChainResu
 	maxPaths := 100 // Limit total paths to prevent explosion
 
 	// Build chains recursively
-	var buildChainUp func(symbol string, depth int) []*CallChainNode
-	buildChainUp = func(symbol string, depth int) []*CallChainNode {
+	var buildChainUp func(id int64, symbol string, depth int) []*CallChainNode
+	buildChainUp = func(id int64, symbol string, depth int) []*CallChainNode {
 		if depth > maxDepth || totalPaths >= maxPaths {
 			if totalPaths >= maxPaths {
 				result.Truncated = true
@@ -284,44 +526,54 @@ func GetCallChainUp(db *sql.DB, symbolName string, maxDepth int) (*CallChainResu
 		}
 
 		// Check for cycles
-		if visited[symbol] {
+		key := callChainKey(id, symbol)
+		if visited[key] {
 			return nil
 		}
-		visited[symbol] = true
-		defer func() { visited[symbol] = false }()
+		visited[key] = true
+		defer func() { visited[key] = false }()
 
 		// Get direct callers
-		callers, err := GetIncomingCalls(db, symbol)
+		callers, err := incomingCalls(db, id, symbol)
 		if err != nil || len(callers) == 0 {
 			return nil
 		}
 
-		// Deduplicate callers by symbol name
+		// Deduplicate callers, keeping the first call site of each
 		seenCallers := make(map[string]CallSite)
+		var order []string
 		for _, c := range callers {
-			if c.CallerSymbol != "" {
-				if existing, ok := seenCallers[c.CallerSymbol]; !ok || c.Line < existing.Line {
-					seenCallers[c.CallerSymbol] = c
-				}
+			if c.CallerSymbol == "" {
+				continue
+			}
+			callerKey := callChainKey(c.CallerSymbolID, c.CallerSymbol)
+			existing, ok := seenCallers[callerKey]
+			if !ok {
+				order = append(order, callerKey)
+			}
+			if !ok || c.Line < existing.Line {
+				seenCallers[callerKey] = c
 			}
 		}
 
 		var nodes []*CallChainNode
-		for callerSym, caller := range seenCallers {
+		for _, callerKey := range order {
 			if totalPaths >= maxPaths {
 				result.Truncated = true
 				break
 			}
+			caller := seenCallers[callerKey]
 
 			node := &CallChainNode{
-				Symbol:   callerSym,
-				FilePath: caller.FilePath,
-				Line:     caller.Line,
-				Depth:    depth,
+				Symbol:     caller.CallerSymbol,
+				FilePath:   caller.FilePath,
+				Line:       caller.Line,
+				Depth:      depth,
+				Resolution: caller.Resolution,
 			}
 
 			// Recursively get callers of this caller
-			node.Children = buildChainUp(callerSym, depth+1)
+			node.Children = buildChainUp(caller.CallerSymbolID, caller.CallerSymbol, depth+1)
 
 			// If no more callers, this is a root (entry point)
 			if len(node.Children) == 0 {
@@ -334,7 +586,8 @@ func GetCallChainUp(db *sql.DB, symbolName string, maxDepth int) (*CallChainResu
 		return nodes
 	}
 
-	result.Chains = buildChainUp(symbolName, 1)
+	_, name := splitCallTarget(symbolName)
+	result.Chains = buildChainUp(symbolID, name, 1)
 	result.TotalPaths = totalPaths
 
 	return result, nil
@@ -356,12 +609,12 @@ func GetCallChainDown(db *sql.DB, symbolName, filePath string, maxDepth int) (*C
 		MaxDepth:  maxDepth,
 	}
 
-	visited := make(map[string]bool)
+	visited := make(map[int64]bool)
 	totalPaths := 0
 	maxPaths := 100
 
-	var buildChainDown func(symbol string, file string, depth int) []*CallChainNode
-	buildChainDown = func(symbol string, file string, depth int) []*CallChainNode {
+	var buildChainDown func(scope *callScope, depth int) []*CallChainNode
+	buildChainDown = func(scope *callScope, depth int) []*CallChainNode {
 		if depth > maxDepth || totalPaths >= maxPaths {
 			if totalPaths >= maxPaths {
 				result.Truncated = true
@@ -369,47 +622,49 @@ func GetCallChainDown(db *sql.DB, symbolName, filePath string, maxDepth int) (*C
 			return nil
 		}
 
-		key := symbol + ":" + file
-		if visited[key] {
+		if visited[scope.id] {
 			return nil
 		}
-		visited[key] = true
-		defer func() { visited[key] = false }()
+		visited[scope.id] = true
+		defer func() { visited[scope.id] = false }()
 
 		// Get direct callees
-		callees, err := GetOutgoingCalls(db, symbol, file)
+		callees, err := outgoingCalls(db, scope)
 		if err != nil || len(callees) == 0 {
 			return nil
 		}
 
-		// Deduplicate callees by symbol name
-		seenCallees := make(map[string]CallSite)
-		for _, c := range callees {
-			if c.CalleeSymbol != "" {
-				if _, ok := seenCallees[c.CalleeSymbol]; !ok {
-					seenCallees[c.CalleeSymbol] = c
-				}
+		// Deduplicate callees, keeping the first call site of each
+		seenCallees := make(map[string]bool)
+		var nodes []*CallChainNode
+		for _, callee := range callees {
+			if callee.CalleeSymbol == "" {
+				continue
 			}
-		}
+			calleeKey := callChainKey(callee.CalleeSymbolID, callee.CalleeSymbol)
+			if seenCallees[calleeKey] {
+				continue
+			}
+			seenCallees[calleeKey] = true
 
-		var nodes []*CallChainNode
-		for calleeSym, callee := range seenCallees {
 			if totalPaths >= maxPaths {
 				result.Truncated = true
 				break
 			}
 
 			node := &CallChainNode{
-				Symbol:   calleeSym,
-				FilePath: callee.FilePath,
-				Line:     callee.Line,
-				Depth:    depth,
+				Symbol:     callee.CalleeSymbol,
+				FilePath:   callee.FilePath,
+				Line:       callee.Line,
+				Depth:      depth,
+				Resolution: callee.Resolution,
 			}
 
-			// Find file where callee is defined to continue tracing
-			calleeFile := findSymbolFile(db, calleeSym)
-			if calleeFile != "" {
-				node.Children = buildChainDown(calleeSym, calleeFile, depth+1)
+			// Continue tracing from the callee's definition: the resolved
+			// symbol if known, otherwise the first symbol with that name.
+			calleeScope, err := lookupCallScope(db, callee.CalleeSymbolID, callee.CalleeSymbol, "")
+			if err == nil {
+				node.Children = buildChainDown(calleeScope, depth+1)
 			}
 
 			if len(node.Children) == 0 {
@@ -422,43 +677,43 @@ func GetCallChainDown(db *sql.DB, symbolName, filePath string, maxDepth int) (*C
 		return nodes
 	}
 
-	// If no file provided, try to find it
-	if filePath == "" {
-		filePath = findSymbolFile(db, symbolName)
-	}
-
-	if filePath != "" {
-		result.Chains = buildChainDown(symbolName, filePath, 1)
+	// If no file provided, the first definition with that name is used
+	if scope, err := lookupCallScope(db, 0, symbolName, filePath); err == nil {
+		result.Chains = buildChainDown(scope, 1)
 	}
 	result.TotalPaths = totalPaths
 
 	return result, nil
 }
 
-// findSymbolFile finds the file where a symbol is defined
-func findSymbolFile(db *sql.DB, symbolName string) string {
-	var filePath string
-	err := db.QueryRowContext(context.Background(), `
-		SELECT file_path FROM symbols
-		WHERE name = ?
-		LIMIT 1;
-	`, symbolName).Scan(&filePath)
-	if err != nil {
-		return ""
-	}
-	return filePath
+// GetCallChain traces calls in the specified direction. When filePath is
+// given, upstream tracing starts from the definition in that file.
+func GetCallChain(db *sql.DB, symbolName, filePath, direction string, maxDepth int) (*CallChainResult, error) {
+	return GetCallChainAt(db, symbolName, filePath, 0, direction, maxDepth)
 }
 
-// GetCallChain traces calls in the specified direction.
-func GetCallChain(db *sql.DB, symbolName, filePath, direction string, maxDepth int) (*CallChainResult, error) {
+// GetCallChainAt is GetCallChain for the definition of symbolName whose body
+// contains line, which tells apart same-named methods in one file.
+func GetCallChainAt(db *sql.DB, symbolName, filePath string, line int, direction string, maxDepth int) (*CallChainResult, error) {
+	var symbolID int64
+	if direction != "down" {
+		target, err := findCallTarget(db, symbolName, filePath, line)
+		if err != nil {
+			return nil, err
+		}
+		if target != nil {
+			symbolID = target.id
+		}
+	}
+
 	switch direction {
 	case "up":
-		return GetCallChainUp(db, symbolName, maxDepth)
+		return callChainUp(db, symbolID, symbolName, maxDepth)
 	case "down":
 		return GetCallChainDown(db, symbolName, filePath, maxDepth)
 	case "both":
 		// Get both directions and merge
-		upResult, err := GetCallChainUp(db, symbolName, maxDepth)
+		upResult, err := callChainUp(db, symbolID, symbolName, maxDepth)
 		if err != nil {
 			return nil, err
 		}
@@ -476,7 +731,7 @@ func GetCallChain(db *sql.DB, symbolName, filePath, direction string, maxDepth i
 			Truncated:  upResult.Truncated || downResult.Truncated,
 		}, nil
 	default:
-		return GetCallChainUp(db, symbolName, maxDepth)
+		return callChainUp(db, symbolID, symbolName, maxDepth)
 	}
 }
 
@@ -490,10 +745,11 @@ func FlattenCallChain(result *CallChainResult) [][]CallChainNode {
 		for _, node := range nodes {
 			newPath := append([]CallChainNode{}, currentPath...)
 			newPath = append(newPath, CallChainNode{
-				Symbol:   node.Symbol,
-				FilePath: node.FilePath,
-				Line:     node.Line,
-				Depth:    node.Depth,
+				Symbol:     node.Symbol,
+				FilePath:   node.FilePath,
+				Line:       node.Line,
+				Depth:      node.Depth,
+				Resolution: node.Resolution,
 			})
 
 			if len(node.Children) == 0 {
@@ -76,6 +76,20 @@ func TestCallGraph(t *testing.T) {
 		}
 	})
 
+	t.Run("GetReferences", func(t *testing.T) {
+		db.ExecContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_symbol, kind, line, column) VALUES (?, ?, ?, ?, ?, ?);`, "main.go", 1, "helperFn", "call", 6, 1)
+		db.ExecContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_symbol, kind, line, column) VALUES (?, ?, ?, ?, ?, ?);`, "main.go", 1, "pkg.helper", "call", 7, 1)
+
+		refs, err := GetReferences(db, "helper")
+		if err != nil {
+			t.Fatalf("GetReferences failed: %v", err)
+		}
+		// Exact and qualified matches only; "helperFn" is a different symbol.
+		if len(refs) != 2 || refs[0].Line != 5 || refs[1].TargetSymbol != "pkg.helper" {
+			t.Errorf("GetReferences = %+v", refs)
+		}
+	})
+
 	t.Run("GetMostCalledSymbols", func(t *testing.T) {
 		res, err := GetMostCalledSymbols(db, 10)
 		if err != nil {
@@ -0,0 +1,256 @@
+package index
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ImportEdge is an import resolved to a file in the workspace.
+type ImportEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Import string `json:"import"` // as written in the source file
+	Line   int    `json:"line,omitempty"`
+}
+
+// LoadImportGraph resolves the indexed imports to workspace files. Imports
+// store the path as written, so they are matched against the indexed files:
+// relative paths, Go module paths from go.mod, package names from
+// package.json, Python dotted modules and "@/" aliases for src/. An import
+// of a directory or Go package yields an edge to each of its files in the
+// importer's language, leaving out Go tests. Imports of external packages
+// and imports that cannot be resolved are left out.
+func LoadImportGraph(db *sql.DB, root string) ([]ImportEdge, error) {
+	ctx := context.Background()
+	files, err := queryStrings(ctx, db, `SELECT path FROM files ORDER BY path;`)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	r := newImportResolver(root, files)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT source_file, target_file, line
+		FROM relationships
+		WHERE kind = 'import' AND COALESCE(target_file, '') != ''
+		ORDER BY source_file, line;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []ImportEdge
+	for rows.Next() {
+		var e ImportEdge
+		if err := rows.Scan(&e.Source, &e.Import, &e.Line); err != nil {
+			return nil, err
+		}
+		for _, target := range r.resolve(e.Source, e.Import) {
+			if target != e.Source {
+				e.Target = target
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges, rows.Err()
+}
+
+// LoadFileLanguages maps every indexed file to its language, "" when it
+// was not recognized.
+func LoadFileLanguages(db *sql.DB) (map[string]string, error) {
+	rows, err := db.QueryContext(context.Background(), `SELECT path, COALESCE(language, '') FROM files;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]string)
+	for rows.Next() {
+		var path, lang string
+		if err := rows.Scan(&path, &lang); err != nil {
+			return nil, err
+		}
+		out[path] = lang
+	}
+	return out, rows.Err()
+}
+
+// importResolver maps import paths to indexed files.
+type importResolver struct {
+	files   map[string]bool
+	stems   map[string]string   // path without extension -> first file
+	dirs    map[string][]string // directory -> files, sorted
+	modules map[string]string   // module or package name -> directory
+}
+
+func newImportResolver(root string, files []string) *importResolver {
+	r := &importResolver{
+		files:   make(map[string]bool, len(files)),
+		stems:   make(map[string]string),
+		dirs:    make(map[string][]string),
+		modules: make(map[string]string),
+	}
+	for _, p := range files {
+		p = filepath.ToSlash(p)
+		r.files[p] = true
+		stem := strings.TrimSuffix(p, path.Ext(p))
+		if _, ok := r.stems[stem]; !ok {
+			r.stems[stem] = p
+		}
+		dir := path.Dir(p)
+		r.dirs[dir] = append(r.dirs[dir], p)
+	}
+
+	// Manifests may sit above every indexed file, so ancestors are checked too.
+	manifestDirs := map[string]bool{".": true}
+	for dir := range r.dirs {
+		for ; dir != "." && dir != "/" && !manifestDirs[dir]; dir = path.Dir(dir) {
+			manifestDirs[dir] = true
+		}
+	}
+	for dir := range manifestDirs {
+		if name := goModulePath(filepath.Join(root, filepath.FromSlash(dir), "go.mod")); name != "" {
+			r.modules[name] = dir
+		}
+		if name := packageJSONName(filepath.Join(root, filepath.FromSlash(dir), "package.json")); name != "" {
+			r.modules[name] = dir
+		}
+	}
+	return r
+}
+
+// resolve returns the files that spec, imported from source, refers to, or
+// nil when it is not part of the workspace.
+func (r *importResolver) resolve(source, spec string) []string {
+	dir := path.Dir(source)
+	for _, candidate := range r.candidates(dir, spec) {
+		if files := r.lookup(candidate, path.Ext(source)); len(files) > 0 {
+			return files
+		}
+	}
+	return nil
+}
+
+func (r *importResolver) candidates(dir, spec string) []string {
+	switch {
+	case strings.HasPrefix(spec, "./"), strings.HasPrefix(spec, "../"):
+		return []string{path.Join(dir, spec)}
+	case strings.HasPrefix(spec, "."):
+		// Python relative import: one dot is the current package.
+		rest := strings.TrimLeft(spec, ".")
+		base := dir
+		for i := 1; i < len(spec)-len(rest); i++ {
+			base = path.Dir(base)
+		}
+		return []string{path.Join(base, strings.ReplaceAll(rest, ".", "/"))}
+	case strings.HasPrefix(spec, "@/"), strings.HasPrefix(spec, "~/"):
+		return []string{path.Join("src", spec[2:])}
+	}
+
+	var candidates []string
+	if module, moduleDir := r.module(spec); module != "" {
+		candidates = append(candidates, path.Join(moduleDir, strings.TrimPrefix(spec[len(module):], "/")))
+	}
+	candidates = append(candidates, path.Clean(spec), path.Join(dir, spec))
+	if !strings.Contains(spec, "/") && strings.Contains(spec, ".") {
+		candidates = append(candidates, strings.ReplaceAll(spec, ".", "/"))
+	}
+	return candidates
+}
+
+// module returns the longest module or package name spec starts with.
+func (r *importResolver) module(spec string) (string, string) {
+	var best string
+	for name := range r.modules {
+		if (spec == name || strings.HasPrefix(spec, name+"/")) && len(name) > len(best) {
+			best = name
+		}
+	}
+	return best, r.modules[best]
+}
+
+// lookup finds the indexed files for a workspace-relative path, trying it
+// as a file, without an extension, as a package index and as a directory.
+// A directory stands for its files with extension ext other than Go tests,
+// or for its first file when none has ext.
+func (r *importResolver) lookup(p, ext string) []string {
+	if p == "" || p == "." || strings.HasPrefix(p, "../") {
+		return nil
+	}
+	if r.files[p] {
+		return []string{p}
+	}
+	for _, stem := range []string{p, p + "/index", p + "/__init__"} {
+		if f, ok := r.stems[stem]; ok {
+			return []string{f}
+		}
+	}
+	files := r.dirs[p]
+	if len(files) == 0 {
+		return nil
+	}
+	var pkg []string
+	for _, f := range files {
+		if path.Ext(f) == ext && !strings.HasSuffix(f, "_test.go") {
+			pkg = append(pkg, f)
+		}
+	}
+	if len(pkg) == 0 {
+		return files[:1]
+	}
+	return pkg
+}
+
+// goModulePath reads the module path from a go.mod file.
+func goModulePath(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// packageJSONName reads the package name from a package.json file.
+func packageJSONName(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Name
+}
+
+func queryStrings(ctx context.Context, db *sql.DB, query string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -42,6 +42,7 @@ type ScanSummary struct {
 	ChunkCount        int
 	SymbolCount       int
 	RelationshipCount int
+	ResolvedCallCount int // Calls linked to a concrete symbol
 	StartedAt         time.Time
 	CompletedAt       time.Time
 }
@@ -90,6 +91,16 @@ var indexMigrations = []func(*sql.Tx) error{
 	indexMigrateV0,
 	// Migration 1: Add git commit hash tracking to scans
 	indexMigrateV1,
+	// Migration 2: Add resolved call targets to relationships
+	indexMigrateV2,
+	// Migration 3: Add embeddings for code chunks and symbol doc comments
+	indexMigrateV3,
+	// Migration 4: Add code ownership from git blame, git log and CODEOWNERS
+	indexMigrateV4,
+	// Migration 5: Add architecture rule violations, tracked across scans
+	indexMigrateV5,
+	// Migration 6: Add the owning symbol of each chunk
+	indexMigrateV6,
 }
 
 // indexMigrateV0 creates the initial index schema (version 0)
@@ -229,6 +240,151 @@ func indexMigrateV1(tx *sql.Tx) error {
 	return nil
 }
 
+// indexMigrateV2 adds resolved call targets to relationships
+func indexMigrateV2(tx *sql.Tx) error {
+	stmts := []string{
+		`ALTER TABLE relationships ADD COLUMN target_symbol_id INTEGER DEFAULT NULL REFERENCES symbols(id) ON DELETE SET NULL;`,
+		`ALTER TABLE relationships ADD COLUMN resolution TEXT DEFAULT '';`,
+		`CREATE INDEX IF NOT EXISTS idx_rel_target_symbol_id ON relationships(target_symbol_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_rel_source_symbol_id ON relationships(source_symbol_id);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			if strings.Contains(err.Error(), "duplicate column") {
+				continue
+			}
+			return fmt.Errorf("add call resolution: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV3 adds vector embeddings for code chunks and documented symbols.
+// Rows are keyed by file and content hash rather than by chunk row, so a full
+// rescan reuses embeddings for content that did not change.
+func indexMigrateV3(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS code_embeddings (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            path TEXT NOT NULL,
+            kind TEXT NOT NULL,
+            chunk_index INTEGER NOT NULL,
+            symbol TEXT DEFAULT '',
+            start_line INTEGER NOT NULL,
+            end_line INTEGER NOT NULL,
+            content_hash TEXT NOT NULL,
+            embedding BLOB NOT NULL,
+            model TEXT NOT NULL
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_code_embeddings_path ON code_embeddings(path);`,
+		`CREATE INDEX IF NOT EXISTS idx_code_embeddings_model ON code_embeddings(model);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create code embeddings: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV4 adds code ownership. Like code embeddings, these tables are
+// not cleared by a rescan; the ownership pass replaces them and reuses blame
+// results for files whose hash did not change.
+func indexMigrateV4(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS file_ownership (
+            path TEXT PRIMARY KEY,
+            file_hash TEXT NOT NULL,
+            blamed_lines INTEGER NOT NULL DEFAULT 0,
+            last_author TEXT DEFAULT '',
+            last_email TEXT DEFAULT '',
+            last_commit TEXT DEFAULT '',
+            last_touched TEXT DEFAULT '',
+            churn_30d INTEGER NOT NULL DEFAULT 0,
+            churn_90d INTEGER NOT NULL DEFAULT 0,
+            churn_365d INTEGER NOT NULL DEFAULT 0,
+            codeowners TEXT NOT NULL DEFAULT '[]',
+            analyzed_at TEXT NOT NULL
+        );`,
+		`CREATE TABLE IF NOT EXISTS file_authors (
+            path TEXT NOT NULL,
+            author TEXT NOT NULL,
+            email TEXT NOT NULL,
+            lines INTEGER NOT NULL,
+            commits INTEGER NOT NULL,
+            share REAL NOT NULL,
+            PRIMARY KEY (path, email)
+        );`,
+		`CREATE TABLE IF NOT EXISTS symbol_owners (
+            file_path TEXT NOT NULL,
+            symbol TEXT NOT NULL,
+            kind TEXT NOT NULL,
+            line_start INTEGER NOT NULL,
+            line_end INTEGER NOT NULL,
+            author TEXT NOT NULL,
+            email TEXT NOT NULL,
+            lines INTEGER NOT NULL,
+            share REAL NOT NULL
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_symbol_owners_file ON symbol_owners(file_path);`,
+		`CREATE INDEX IF NOT EXISTS idx_symbol_owners_symbol ON symbol_owners(symbol);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create ownership tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV5 adds architecture rule violations. Rows survive rescans so
+// that each architecture pass can report what is new and what was resolved
+// since the previous one.
+func indexMigrateV5(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS architecture_violations (
+            rule TEXT NOT NULL,
+            kind TEXT NOT NULL,
+            source_file TEXT NOT NULL,
+            target_file TEXT NOT NULL,
+            import_path TEXT DEFAULT '',
+            line INTEGER DEFAULT 0,
+            source_component TEXT DEFAULT '',
+            target_component TEXT DEFAULT '',
+            severity TEXT NOT NULL,
+            message TEXT NOT NULL,
+            first_seen_scan INTEGER NOT NULL,
+            resolved_scan INTEGER DEFAULT NULL,
+            PRIMARY KEY (rule, source_file, target_file)
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_arch_violations_source ON architecture_violations(source_file);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create architecture tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV6 records which symbol each chunk belongs to, so search
+// results can name the function or class they are in.
+func indexMigrateV6(tx *sql.Tx) error {
+	stmts := []string{
+		`ALTER TABLE chunks ADD COLUMN symbol TEXT DEFAULT '';`,
+		`ALTER TABLE chunks ADD COLUMN symbol_kind TEXT DEFAULT '';`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			if strings.Contains(err.Error(), "duplicate column") {
+				continue
+			}
+			return fmt.Errorf("add chunk symbols: %w", err)
+		}
+	}
+	return nil
+}
+
 func ensureSchema(db *sql.DB) error {
 	// Create schema version table first
 	if _, err := db.ExecContext(context.Background(), indexSchemaVersionTable); err != nil {
@@ -464,7 +620,6 @@ func processFileWithRegistry(root, rel string, registry *analysis.ParserRegistry
 	}
 
 	h := sha256.Sum256(data)
-	chunks := fsutil.ChunkContent(string(data), 120, 8*1024)
 
 	// Perform language analysis using the provided registry
 	lang := analysis.DetectLanguage(rel)
@@ -476,6 +631,7 @@ func processFileWithRegistry(root, rel string, registry *analysis.ParserRegistry
 		}
 		// Note: We intentionally ignore parse errors - some files may not parse cleanly
 	}
+	chunks := chunkFile(string(data), fileAnalysis)
 
 	return FileRecord{
 		Path:     rel,
@@ -488,6 +644,31 @@ func processFileWithRegistry(root, rel string, registry *analysis.ParserRegistry
 	}, nil
 }
 
+// chunkFile splits a file into chunks along its symbols, or by lines when
+// the parser found none.
+func chunkFile(content string, fa *analysis.FileAnalysis) []fsutil.Chunk {
+	var symbols []fsutil.SymbolBoundary
+	if fa != nil {
+		symbols = symbolBoundaries(fa.Symbols)
+	}
+	return fsutil.ChunkContentSmart(content, symbols, 120, 8*1024)
+}
+
+func symbolBoundaries(symbols []analysis.Symbol) []fsutil.SymbolBoundary {
+	out := make([]fsutil.SymbolBoundary, 0, len(symbols))
+	for i := range symbols {
+		s := &symbols[i]
+		out = append(out, fsutil.SymbolBoundary{
+			Name:      s.Name,
+			Kind:      string(s.Kind),
+			StartLine: s.LineStart,
+			EndLine:   s.LineEnd,
+			Children:  symbolBoundaries(s.Children),
+		})
+	}
+	return out
+}
+
 // WriteScanOptions provides options for WriteScan.
 type WriteScanOptions struct {
 	CommitHash string // Git commit hash (optional)
@@ -531,7 +712,7 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 	}
 	defer fileStmt.Close()
 
-	chunkStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO chunks(path, chunk_index, start_line, end_line, content) VALUES(?, ?, ?, ?, ?);`)
+	chunkStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO chunks(path, chunk_index, start_line, end_line, content, symbol, symbol_kind) VALUES(?, ?, ?, ?, ?, ?, ?);`)
 	if err != nil {
 		return ScanSummary{}, err
 	}
@@ -568,7 +749,7 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 
 		for _, c := range r.Chunks {
 			chunkCount++
-			if _, err := chunkStmt.ExecContext(context.Background(), r.Path, c.Index, c.StartLine, c.EndLine, c.Content); err != nil {
+			if _, err := chunkStmt.ExecContext(context.Background(), r.Path, c.Index, c.StartLine, c.EndLine, c.Content, c.Symbol, c.SymbolKind); err != nil {
 				return ScanSummary{}, fmt.Errorf("insert chunk %s:%d: %w", r.Path, c.Index, err)
 			}
 			if _, err := ftsStmt.ExecContext(context.Background(), r.Path, c.Content, c.Index); err != nil {
@@ -593,6 +774,11 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 		}
 	}
 
+	resolvedCalls, err := resolveCalls(context.Background(), tx, nil)
+	if err != nil {
+		return ScanSummary{}, fmt.Errorf("resolve calls: %w", err)
+	}
+
 	scanHash := computeScanHash(records)
 	res, err := tx.ExecContext(context.Background(), `INSERT INTO scans(root, scan_hash, started_at, completed_at, commit_hash) VALUES(?, ?, ?, ?, ?);`, root, scanHash, startedAt.UTC().Format(time.RFC3339), now.Format(time.RFC3339), opts.CommitHash)
 	if err != nil {
@@ -613,6 +799,7 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 		ChunkCount:        chunkCount,
 		SymbolCount:       symbolCount,
 		RelationshipCount: relationshipCount,
+		ResolvedCallCount: resolvedCalls,
 		StartedAt:         startedAt.UTC(),
 		CompletedAt:       now,
 	}, nil
@@ -716,6 +903,8 @@ type ChunkHit struct {
 	StartLine  int
 	EndLine    int
 	Content    string
+	Symbol     string // owning symbol, e.g. "Store.Close"; empty when none
+	SymbolKind string
 }
 
 // ChunkRow represents a raw row from the chunks table.
@@ -724,6 +913,17 @@ type ChunkRow struct {
 	StartLine  int
 	EndLine    int
 	Content    string
+	Symbol     string
+	SymbolKind string
+}
+
+// SymbolLabel describes the symbol a chunk belongs to, e.g.
+// "function Foo", or returns "" when it has none.
+func SymbolLabel(kind, name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(kind + " " + name)
 }
 
 // SearchChunks performs a full-text search across indexed code chunks.
@@ -736,7 +936,7 @@ func SearchChunks(db *sql.DB, query string, limit int) ([]ChunkHit, error) {
 	}
 	escaped := sanitizeFTSQuery(query)
 	rows, err := db.QueryContext(context.Background(), `
-        SELECT c.path, c.chunk_index, c.start_line, c.end_line, c.content
+        SELECT c.path, c.chunk_index, c.start_line, c.end_line, c.content, COALESCE(c.symbol, ''), COALESCE(c.symbol_kind, '')
         FROM chunks_fts
         JOIN chunks c ON c.path = chunks_fts.path AND c.chunk_index = chunks_fts.chunk_index
         WHERE chunks_fts MATCH ?
@@ -750,7 +950,7 @@ func SearchChunks(db *sql.DB, query string, limit int) ([]ChunkHit, error) {
 	var hits []ChunkHit
 	for rows.Next() {
 		var h ChunkHit
-		if err := rows.Scan(&h.Path, &h.ChunkIndex, &h.StartLine, &h.EndLine, &h.Content); err != nil {
+		if err := rows.Scan(&h.Path, &h.ChunkIndex, &h.StartLine, &h.EndLine, &h.Content, &h.Symbol, &h.SymbolKind); err != nil {
 			return nil, err
 		}
 		hits = append(hits, h)
@@ -766,7 +966,7 @@ func sanitizeFTSQuery(q string) string {
 
 // GetChunksForFile retrieves all chunks for a given file path.
 func GetChunksForFile(db *sql.DB, path string) ([]ChunkRow, error) {
-	rows, err := db.QueryContext(context.Background(), `SELECT chunk_index, start_line, end_line, content FROM chunks WHERE path = ? ORDER BY chunk_index ASC;`, path)
+	rows, err := db.QueryContext(context.Background(), `SELECT chunk_index, start_line, end_line, content, COALESCE(symbol, ''), COALESCE(symbol_kind, '') FROM chunks WHERE path = ? ORDER BY chunk_index ASC;`, path)
 	if err != nil {
 		return nil, err
 	}
@@ -774,7 +974,7 @@ func GetChunksForFile(db *sql.DB, path string) ([]ChunkRow, error) {
 	var out []ChunkRow
 	for rows.Next() {
 		var row ChunkRow
-		if err := rows.Scan(&row.ChunkIndex, &row.StartLine, &row.EndLine, &row.Content); err != nil {
+		if err := rows.Scan(&row.ChunkIndex, &row.StartLine, &row.EndLine, &row.Content, &row.Symbol, &row.SymbolKind); err != nil {
 			return nil, err
 		}
 		out = append(out, row)
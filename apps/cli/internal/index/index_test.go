@@ -497,6 +497,65 @@ func TestDetectChanges(t *testing.T) {
 	})
 }
 
+func TestDetectPathChanges(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(filepath.Join(dir, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	files := map[string]string{
+		"keep.go":       "package main\n",
+		"edit.go":       "package main\n",
+		"pkg/a/a.go":    "package a\n",
+		"pkg/a/b.go":    "package a\n",
+		"untouched.go":  "package main\n",
+		"secret/key.go": "package secret\n",
+	}
+	var records []FileRecord
+	for path, content := range files {
+		abs := filepath.Join(dir, path)
+		os.MkdirAll(filepath.Dir(abs), 0o755)
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		sum := sha256.Sum256([]byte(content))
+		records = append(records, FileRecord{
+			Path:    path,
+			Hash:    fmt.Sprintf("%x", sum[:]),
+			Size:    int64(len(content)),
+			ModTime: fsutil.NormalizeModTime(time.Now()),
+			Chunks:  fsutil.ChunkContent(content, 120, 8*1024),
+		})
+	}
+	if _, err := WriteScan(db, dir, records, time.Now()); err != nil {
+		t.Fatalf("WriteScan failed: %v", err)
+	}
+
+	// Edit one file, add one, remove a directory and touch keep.go without
+	// changing it. untouched.go changes but is not reported by the watcher.
+	os.WriteFile(filepath.Join(dir, "edit.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "untouched.go"), []byte("package other\n"), 0o644)
+	os.RemoveAll(filepath.Join(dir, "pkg"))
+
+	guardrails := config.Guardrails{DoNotTouchGlobs: []string{"secret/**"}}
+	changes, err := DetectPathChanges(db, dir, guardrails, []string{"keep.go", "edit.go", "new.go", "pkg/a", "secret/key.go"})
+	if err != nil {
+		t.Fatalf("DetectPathChanges failed: %v", err)
+	}
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Action+" "+c.Path)
+	}
+	want := []string{"modified edit.go", "added new.go", "deleted pkg/a/a.go", "deleted pkg/a/b.go"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+}
+
 // IncrementalScan tests
 func TestIncrementalScan(t *testing.T) {
 	t.Run("processes empty changes", func(t *testing.T) {
@@ -951,9 +1010,12 @@ func TestGetIndexSchemaVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetIndexSchemaVersion() error = %v", err)
 	}
-	// Version 0: Initial schema, Version 1: Added commit_hash column
-	if version != 1 {
-		t.Fatalf("schema version = %d, want 1", version)
+	// Version 0: Initial schema, Version 1: Added commit_hash column,
+	// Version 2: Added call resolution columns, Version 3: Added code embeddings,
+	// Version 4: Added code ownership, Version 5: Added architecture violations,
+	// Version 6: Added chunk symbols
+	if version != 6 {
+		t.Fatalf("schema version = %d, want 6", version)
 	}
 }
 
@@ -972,7 +1034,7 @@ func TestWriteScanWithSymbols(t *testing.T) {
 			Hash:     fmt.Sprintf("%x", sha256.Sum256([]byte("package main"))),
 			Size:     12,
 			ModTime:  time.Now().UTC(),
-			Chunks:   []fsutil.Chunk{{Index: 0, StartLine: 1, EndLine: 2, Content: "package main\nfunc DoWork() {}"}},
+			Chunks:   []fsutil.Chunk{{Index: 0, StartLine: 1, EndLine: 2, Content: "package main\nfunc DoWork() {}", Symbol: "DoWork", SymbolKind: "function"}},
 			Language: "go",
 			Analysis: &analysis.FileAnalysis{
 				Symbols: []analysis.Symbol{
@@ -1011,4 +1073,12 @@ func TestWriteScanWithSymbols(t *testing.T) {
 	if summary.SymbolCount == 0 || summary.RelationshipCount == 0 {
 		t.Fatalf("expected symbols and relationships, got %+v", summary)
 	}
+
+	chunks, err := GetChunksForFile(db, "main.go")
+	if err != nil {
+		t.Fatalf("GetChunksForFile() error = %v", err)
+	}
+	if len(chunks) != 1 || SymbolLabel(chunks[0].SymbolKind, chunks[0].Symbol) != "function DoWork" {
+		t.Errorf("chunks = %+v, want one chunk owned by function DoWork", chunks)
+	}
 }
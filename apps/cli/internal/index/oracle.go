@@ -39,6 +39,8 @@ type FileContext struct {
 	ChunkStart int          `json:"chunkStart,omitempty"`
 	ChunkEnd   int          `json:"chunkEnd,omitempty"`
 	Snippet    string       `json:"snippet,omitempty"`
+	// ChunkSymbol is the symbol the snippet belongs to, e.g. "function Foo"
+	ChunkSymbol string `json:"chunkSymbol,omitempty"`
 }
 
 // SymbolInfo represents a symbol with its metadata
@@ -84,6 +86,10 @@ type ContextOptions struct {
 	// Smart context options
 	SmartContext *SmartContextOptions     `json:"smartContext,omitempty"` // Enable smart context expansion
 	EditHistory  map[string]*FileEditInfo `json:"editHistory,omitempty"`  // File edit history for recency scoring
+
+	// Embedder enables hybrid chunk retrieval (BM25 fused with vector
+	// similarity over code embeddings). Nil keeps phrase search only.
+	Embedder Embedder `json:"-"`
 }
 
 // DefaultExcludePatterns returns patterns for files typically excluded from AI context
@@ -207,9 +213,25 @@ func GetContextForTaskWithOptions(db *sql.DB, query string, limit int, opts *Con
 	}
 
 	// Also search content for query terms
-	chunks, err := SearchChunks(db, query, limit*2) // Fetch more to account for filtering
-	if err != nil {
-		return nil, fmt.Errorf("search chunks: %w", err)
+	var chunks []ChunkHit
+	relevance := make(map[string]float64) // fused score per file, relative to the best hit
+	if opts != nil && opts.Embedder != nil {
+		keep := func(path string) bool { return !shouldExcludeFile(path, excludePatterns) }
+		hits, err := SearchChunksHybrid(db, query, opts.Embedder, limit*2, keep)
+		if err != nil {
+			return nil, fmt.Errorf("search chunks: %w", err)
+		}
+		for _, h := range hits {
+			chunks = append(chunks, h.ChunkHit)
+			if _, ok := relevance[h.Path]; !ok {
+				relevance[h.Path] = h.Score / hits[0].Score
+			}
+		}
+	} else {
+		chunks, err = SearchChunks(db, query, limit*2) // Fetch more to account for filtering
+		if err != nil {
+			return nil, fmt.Errorf("search chunks: %w", err)
+		}
 	}
 
 	// Build file contexts (with filtering)
@@ -227,13 +249,18 @@ func GetContextForTaskWithOptions(db *sql.DB, query string, limit int, opts *Con
 				continue
 			}
 			lang, _ := getFileLanguage(db, chunk.Path)
+			fileRelevance := 1.0
+			if r, ok := relevance[chunk.Path]; ok {
+				fileRelevance = r
+			}
 			fc = &FileContext{
-				Path:       chunk.Path,
-				Language:   lang,
-				Relevance:  1.0,
-				ChunkStart: chunk.StartLine,
-				ChunkEnd:   chunk.EndLine,
-				Snippet:    truncateSnippet(chunk.Content, 500),
+				Path:        chunk.Path,
+				Language:    lang,
+				Relevance:   fileRelevance,
+				ChunkStart:  chunk.StartLine,
+				ChunkEnd:    chunk.EndLine,
+				Snippet:     truncateSnippet(chunk.Content, 500),
+				ChunkSymbol: SymbolLabel(chunk.SymbolKind, chunk.Symbol),
 			}
 			fileContexts[chunk.Path] = fc
 			chunkCount++
@@ -616,8 +643,16 @@ type ImpactResult struct {
 	Dependents   []string     `json:"dependents"`
 	Dependencies []string     `json:"dependencies"`
 	Symbols      []SymbolInfo `json:"symbols"`
+	// Ownership is set when the target is a file the ownership pass analyzed;
+	// SymbolOwners when it is a symbol name.
+	Ownership    *FileOwnership    `json:"ownership,omitempty"`
+	SymbolOwners []SymbolOwnership `json:"symbolOwners,omitempty"`
+	Reviewers    []string          `json:"reviewers,omitempty"`
 }
 
+// impactReviewers is how many reviewers GetImpact suggests.
+const impactReviewers = 3
+
 // GetImpact analyzes what would be affected by changing a file or symbol
 func GetImpact(db *sql.DB, target string) (*ImpactResult, error) {
 	result := &ImpactResult{
@@ -668,9 +703,41 @@ func GetImpact(db *sql.DB, target string) (*ImpactResult, error) {
 		result.Symbols = symbols
 	}
 
+	// Who should review a change, when ownership has been analyzed
+	if own, err := GetFileOwnership(db, target); err == nil && own != nil {
+		result.Ownership = own
+		result.Reviewers = own.Reviewers(impactReviewers)
+	} else if owners, err := GetSymbolOwnership(db, target); err == nil && len(owners) > 0 {
+		result.SymbolOwners = owners
+		result.Reviewers = symbolReviewers(owners, impactReviewers)
+	}
+
 	return result, nil
 }
 
+// symbolReviewers suggests the authors with a sizeable share of any of the
+// symbols, largest share first.
+func symbolReviewers(owners []SymbolOwnership, limit int) []string {
+	var shares []AuthorShare
+	for _, s := range owners {
+		for _, a := range s.Authors {
+			if a.Share >= reviewerMinShare {
+				shares = append(shares, a)
+			}
+		}
+	}
+	SortAuthorShares(shares)
+	var reviewers []string
+	seen := map[string]bool{}
+	for _, a := range shares {
+		if !seen[a.Author] && len(reviewers) < limit {
+			seen[a.Author] = true
+			reviewers = append(reviewers, a.Author)
+		}
+	}
+	return reviewers
+}
+
 // SearchSymbolsByKind searches for symbols of a specific kind
 func SearchSymbolsByKind(db *sql.DB, kind string, limit int) ([]SymbolInfo, error) {
 	if limit <= 0 {
@@ -727,6 +794,67 @@ func GetSymbol(db *sql.DB, name, filePath string) (*SymbolInfo, error) {
 	return &sym, nil
 }
 
+// GetSymbolsByName returns every symbol with the exact given name.
+func GetSymbolsByName(db *sql.DB, name string, limit int) ([]SymbolInfo, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+		FROM symbols
+		WHERE name = ?
+		ORDER BY exported DESC, file_path, line_start
+		LIMIT ?;
+	`, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSymbolInfos(rows)
+}
+
+// SearchSymbolsByName returns symbols whose name starts with query, using
+// symbols_fts prefix matching. An empty query lists exported symbols.
+func SearchSymbolsByName(db *sql.DB, query string, limit int) ([]SymbolInfo, error) {
+	query = strings.TrimSpace(query)
+	var rows *sql.Rows
+	var err error
+	if query == "" {
+		rows, err = db.QueryContext(context.Background(), `
+			SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+			FROM symbols
+			WHERE exported = 1
+			ORDER BY name
+			LIMIT ?;
+		`, limit)
+	} else {
+		rows, err = db.QueryContext(context.Background(), `
+			SELECT s.name, s.kind, s.file_path, s.line_start, s.line_end, s.signature, s.doc_comment, s.exported
+			FROM symbols_fts
+			JOIN symbols s ON s.name = symbols_fts.name AND s.file_path = symbols_fts.file_path
+			WHERE symbols_fts MATCH ?
+			ORDER BY length(s.name), s.exported DESC, s.name
+			LIMIT ?;
+		`, "name : "+sanitizeFTSQuery(query)+" *", limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSymbolInfos(rows)
+}
+
+func scanSymbolInfos(rows *sql.Rows) ([]SymbolInfo, error) {
+	var symbols []SymbolInfo
+	for rows.Next() {
+		var sym SymbolInfo
+		var exported int
+		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported); err != nil {
+			return nil, err
+		}
+		sym.Exported = exported == 1
+		symbols = append(symbols, sym)
+	}
+	return symbols, rows.Err()
+}
+
 // ListExportedSymbols returns all exported symbols for a file
 func ListExportedSymbols(db *sql.DB, filePath string) ([]SymbolInfo, error) {
 	rows, err := db.QueryContext(context.Background(), `
@@ -42,7 +42,7 @@ func TestOracleIntegrated(t *testing.T) {
 	// Initialize schema
 	stmts := []string{
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
-		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
+		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT, symbol TEXT DEFAULT '', symbol_kind TEXT DEFAULT '');`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index);`,
 		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment);`,
@@ -113,7 +113,7 @@ func TestOracleIntegrated(t *testing.T) {
 
 	t.Run("GetContextForTaskWithOptions", func(t *testing.T) {
 		// Insert chunk data
-		db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?);`, 2, "main.go", 0, 1, 5, "package main\nimport \"auth\"")
+		db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?, ?, ?);`, 2, "main.go", 0, 1, 5, "package main\nimport \"auth\"", "", "")
 		db.ExecContext(context.Background(), `INSERT INTO chunks_fts VALUES (?, ?, ?);`, "main.go", "package main import auth", 0)
 
 		ctx, err := GetContextForTaskWithOptions(db, "Login", 10, &ContextOptions{
@@ -0,0 +1,277 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuthorShare is one author's part of a file or symbol.
+type AuthorShare struct {
+	Author  string  `json:"author"`
+	Email   string  `json:"email"`
+	Lines   int     `json:"lines"`             // lines attributed by git blame
+	Commits int     `json:"commits,omitempty"` // commits touching the file
+	Share   float64 `json:"share"`             // Lines / blamed lines
+}
+
+// ChurnWindows counts the commits that touched a file in recent windows.
+type ChurnWindows struct {
+	Days30  int `json:"days30"`
+	Days90  int `json:"days90"`
+	Days365 int `json:"days365"`
+}
+
+// SymbolOwnership is the blame breakdown for one symbol's lines.
+type SymbolOwnership struct {
+	FilePath  string        `json:"filePath"`
+	Symbol    string        `json:"symbol"`
+	Kind      string        `json:"kind"`
+	LineStart int           `json:"lineStart"`
+	LineEnd   int           `json:"lineEnd"`
+	Authors   []AuthorShare `json:"authors"`
+}
+
+// FileOwnership answers "who knows this file?" from git blame, git log and
+// CODEOWNERS.
+type FileOwnership struct {
+	Path        string            `json:"path"`
+	FileHash    string            `json:"-"`
+	BlamedLines int               `json:"blamedLines"`
+	Authors     []AuthorShare     `json:"authors,omitempty"` // by share, largest first
+	LastAuthor  string            `json:"lastAuthor,omitempty"`
+	LastEmail   string            `json:"lastEmail,omitempty"`
+	LastCommit  string            `json:"lastCommit,omitempty"`
+	LastTouched time.Time         `json:"lastTouched,omitempty"`
+	Churn       ChurnWindows      `json:"churn"`
+	CodeOwners  []string          `json:"codeOwners,omitempty"`
+	Symbols     []SymbolOwnership `json:"symbols,omitempty"`
+	AnalyzedAt  time.Time         `json:"analyzedAt"`
+}
+
+// reviewerMinShare is the blame share that makes an author a suggested reviewer.
+const reviewerMinShare = 0.2
+
+// Reviewers suggests who should review a change to the file: CODEOWNERS
+// entries first, then authors owning a sizeable share of its lines, then
+// the last author.
+func (o *FileOwnership) Reviewers(limit int) []string {
+	var reviewers []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !seen[strings.ToLower(name)] && (limit <= 0 || len(reviewers) < limit) {
+			seen[strings.ToLower(name)] = true
+			reviewers = append(reviewers, name)
+		}
+	}
+	for _, owner := range o.CodeOwners {
+		add(owner)
+	}
+	for _, a := range o.Authors {
+		if a.Share >= reviewerMinShare {
+			add(a.Author)
+		}
+	}
+	add(o.LastAuthor)
+	return reviewers
+}
+
+// ReplaceOwnership swaps the stored ownership for owners in one transaction.
+// Files missing from owners lose their ownership.
+func ReplaceOwnership(db *sql.DB, owners []FileOwnership) error {
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range []string{"DELETE FROM file_ownership;", "DELETE FROM file_authors;", "DELETE FROM symbol_owners;"} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("reset ownership: %w", err)
+		}
+	}
+
+	fileStmt, err := tx.PrepareContext(ctx, `INSERT INTO file_ownership(path, file_hash, blamed_lines, last_author, last_email, last_commit, last_touched,
+		churn_30d, churn_90d, churn_365d, codeowners, analyzed_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	defer fileStmt.Close()
+	authorStmt, err := tx.PrepareContext(ctx, `INSERT INTO file_authors(path, author, email, lines, commits, share) VALUES(?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	defer authorStmt.Close()
+	symbolStmt, err := tx.PrepareContext(ctx, `INSERT INTO symbol_owners(file_path, symbol, kind, line_start, line_end, author, email, lines, share)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	defer symbolStmt.Close()
+
+	for i := range owners {
+		o := &owners[i]
+		codeOwners, _ := json.Marshal(o.CodeOwners)
+		if o.CodeOwners == nil {
+			codeOwners = []byte("[]")
+		}
+		lastTouched := ""
+		if !o.LastTouched.IsZero() {
+			lastTouched = o.LastTouched.UTC().Format(time.RFC3339)
+		}
+		if _, err := fileStmt.ExecContext(ctx, o.Path, o.FileHash, o.BlamedLines, o.LastAuthor, o.LastEmail, o.LastCommit, lastTouched,
+			o.Churn.Days30, o.Churn.Days90, o.Churn.Days365, string(codeOwners), o.AnalyzedAt.UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("insert ownership %s: %w", o.Path, err)
+		}
+		for _, a := range o.Authors {
+			if _, err := authorStmt.ExecContext(ctx, o.Path, a.Author, a.Email, a.Lines, a.Commits, a.Share); err != nil {
+				return fmt.Errorf("insert author %s: %w", o.Path, err)
+			}
+		}
+		for _, s := range o.Symbols {
+			for _, a := range s.Authors {
+				if _, err := symbolStmt.ExecContext(ctx, o.Path, s.Symbol, s.Kind, s.LineStart, s.LineEnd, a.Author, a.Email, a.Lines, a.Share); err != nil {
+					return fmt.Errorf("insert symbol owner %s: %w", o.Path, err)
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// GetFileOwnership returns the stored ownership for a file, or nil when the
+// file has not been analyzed.
+func GetFileOwnership(db *sql.DB, path string) (*FileOwnership, error) {
+	ctx := context.Background()
+	o := &FileOwnership{Path: path}
+	var lastTouched, codeOwners, analyzedAt string
+	err := db.QueryRowContext(ctx, `
+		SELECT file_hash, blamed_lines, last_author, last_email, last_commit, last_touched, churn_30d, churn_90d, churn_365d, codeowners, analyzed_at
+		FROM file_ownership WHERE path = ?;
+	`, path).Scan(&o.FileHash, &o.BlamedLines, &o.LastAuthor, &o.LastEmail, &o.LastCommit, &lastTouched,
+		&o.Churn.Days30, &o.Churn.Days90, &o.Churn.Days365, &codeOwners, &analyzedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	o.LastTouched, _ = time.Parse(time.RFC3339, lastTouched)
+	o.AnalyzedAt, _ = time.Parse(time.RFC3339, analyzedAt)
+	_ = json.Unmarshal([]byte(codeOwners), &o.CodeOwners)
+
+	rows, err := db.QueryContext(ctx, `SELECT author, email, lines, commits, share FROM file_authors WHERE path = ? ORDER BY share DESC, commits DESC, author;`, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a AuthorShare
+		if err := rows.Scan(&a.Author, &a.Email, &a.Lines, &a.Commits, &a.Share); err != nil {
+			return nil, err
+		}
+		o.Authors = append(o.Authors, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	o.Symbols, err = querySymbolOwners(db, `file_path = ?`, path)
+	return o, err
+}
+
+// GetSymbolOwnership returns the blame breakdown for every symbol with the
+// given name.
+func GetSymbolOwnership(db *sql.DB, name string) ([]SymbolOwnership, error) {
+	return querySymbolOwners(db, `symbol = ?`, name)
+}
+
+func querySymbolOwners(db *sql.DB, where string, arg any) ([]SymbolOwnership, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, symbol, kind, line_start, line_end, author, email, lines, share
+		FROM symbol_owners WHERE `+where+`
+		ORDER BY file_path, line_start, symbol, share DESC, author;
+	`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []SymbolOwnership
+	for rows.Next() {
+		var s SymbolOwnership
+		var a AuthorShare
+		if err := rows.Scan(&s.FilePath, &s.Symbol, &s.Kind, &s.LineStart, &s.LineEnd, &a.Author, &a.Email, &a.Lines, &a.Share); err != nil {
+			return nil, err
+		}
+		if n := len(symbols); n > 0 && symbols[n-1].FilePath == s.FilePath && symbols[n-1].Symbol == s.Symbol && symbols[n-1].LineStart == s.LineStart {
+			symbols[n-1].Authors = append(symbols[n-1].Authors, a)
+			continue
+		}
+		s.Authors = []AuthorShare{a}
+		symbols = append(symbols, s)
+	}
+	return symbols, rows.Err()
+}
+
+// LoadOwnership returns all stored file ownership keyed by path, without
+// symbols, so an ownership pass can reuse blame results.
+func LoadOwnership(db *sql.DB) (map[string]*FileOwnership, error) {
+	rows, err := db.QueryContext(context.Background(), `SELECT path FROM file_ownership;`)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]*FileOwnership, len(paths))
+	for _, p := range paths {
+		o, err := GetFileOwnership(db, p)
+		if err != nil {
+			return nil, err
+		}
+		owners[p] = o
+	}
+	return owners, nil
+}
+
+// ListSymbolRanges returns every symbol in a file, nested ones included,
+// ordered by position.
+func ListSymbolRanges(db *sql.DB, path string) ([]SymbolInfo, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+		FROM symbols WHERE file_path = ? ORDER BY line_start, name;
+	`, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSymbolInfos(rows)
+}
+
+// SortAuthorShares orders shares largest first, breaking ties by name.
+func SortAuthorShares(shares []AuthorShare) {
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Share != shares[j].Share {
+			return shares[i].Share > shares[j].Share
+		}
+		return shares[i].Author < shares[j].Author
+	})
+}
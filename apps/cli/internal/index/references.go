@@ -0,0 +1,103 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// SymbolDefinition is an indexed symbol with its row ID.
+type SymbolDefinition struct {
+	ID        int64
+	Name      string
+	Kind      string
+	FilePath  string
+	LineStart int
+	LineEnd   int
+	Exported  bool
+	DocLines  int // lines of doc comment above LineStart
+}
+
+// ListSymbolDefinitions returns every indexed symbol, ordered by file and
+// line.
+func ListSymbolDefinitions(db *sql.DB) ([]SymbolDefinition, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT id, name, kind, file_path, line_start, line_end, exported, COALESCE(doc_comment, '')
+		FROM symbols
+		ORDER BY file_path, line_start;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SymbolDefinition
+	for rows.Next() {
+		var s SymbolDefinition
+		var exported int
+		var doc string
+		if err := rows.Scan(&s.ID, &s.Name, &s.Kind, &s.FilePath, &s.LineStart, &s.LineEnd, &exported, &doc); err != nil {
+			return nil, err
+		}
+		s.Exported = exported == 1
+		if doc = strings.TrimSpace(doc); doc != "" {
+			s.DocLines = strings.Count(doc, "\n") + 1
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// SymbolReference is a call or reference relationship.
+type SymbolReference struct {
+	Kind           string // "call" or "reference"
+	SourceFile     string
+	SourceSymbolID int64 // 0 when the enclosing symbol was not recorded
+	Line           int
+	Target         string // as written, e.g. "repo.Find"
+	TargetSymbolID int64  // 0 when the call was not resolved
+}
+
+// ListSymbolReferences returns every call and reference relationship.
+func ListSymbolReferences(db *sql.DB) ([]SymbolReference, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT kind, source_file, COALESCE(source_symbol_id, 0), line, COALESCE(target_symbol, ''), COALESCE(target_symbol_id, 0)
+		FROM relationships
+		WHERE kind IN ('call', 'reference');
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SymbolReference
+	for rows.Next() {
+		var r SymbolReference
+		if err := rows.Scan(&r.Kind, &r.SourceFile, &r.SourceSymbolID, &r.Line, &r.Target, &r.TargetSymbolID); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ForEachChunk calls fn with every indexed chunk, in file order. It stops
+// at the first error fn returns.
+func ForEachChunk(db *sql.DB, fn func(path string, startLine int, content string) error) error {
+	rows, err := db.QueryContext(context.Background(), `SELECT path, start_line, content FROM chunks ORDER BY path, chunk_index;`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var path, content string
+		var start int
+		if err := rows.Scan(&path, &start, &content); err != nil {
+			return err
+		}
+		if err := fn(path, start, content); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,376 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Call resolution states stored in relationships.resolution.
+const (
+	// ResolutionResolved marks a call linked to a concrete symbols.id.
+	ResolutionResolved = "resolved"
+	// ResolutionHeuristic marks a call matched by name only.
+	ResolutionHeuristic = "heuristic"
+)
+
+// Candidate scores used to pick a call target. Only a single candidate at the
+// best non-zero score is accepted; ties stay heuristic.
+const (
+	scoreUnique     = 1 // only definition of a bare name in the workspace
+	scorePackage    = 2 // same directory (Go package, Python package)
+	scoreImported   = 3 // defined in a file the caller imports
+	scoreSameFile   = 3 // defined in the caller's file
+	scoreOwnerMatch = 4 // method of the receiver's type or the caller's own type
+)
+
+// minReceiverMatchLen keeps short variable names ("s", "db") from matching
+// arbitrary type names by suffix.
+const minReceiverMatchLen = 4
+
+// callableKinds are symbol kinds a call can target.
+var callableKinds = map[string]bool{
+	"function":    true,
+	"method":      true,
+	"constructor": true,
+	"class":       true,
+	"type":        true,
+}
+
+// selfReceivers refer to the enclosing type.
+var selfReceivers = map[string]bool{
+	"this":   true,
+	"self":   true,
+	"super":  true,
+	"Self":   true,
+	"static": true,
+	"parent": true,
+}
+
+type resolverSymbol struct {
+	id       int64
+	file     string
+	name     string
+	kind     string
+	start    int
+	end      int
+	owner    string // enclosing type name (parent symbol or Go receiver type)
+	receiver string // Go receiver variable name ("s" in "(s *Server)")
+}
+
+type callResolver struct {
+	byName  map[string][]*resolverSymbol
+	byFile  map[string][]*resolverSymbol
+	imports map[string][]string
+}
+
+// resolveScope limits call resolution to the calls a set of re-indexed files
+// can affect: calls made in those files, and calls elsewhere to a name
+// those files define or used to define.
+type resolveScope struct {
+	files map[string]bool
+	names map[string]bool
+}
+
+// includes reports whether a call in file targeting target may resolve
+// differently after the scope's files changed.
+func (sc *resolveScope) includes(file, target string) bool {
+	if sc == nil || sc.files[file] {
+		return true
+	}
+	_, name := splitCallTarget(target)
+	return sc.names[name]
+}
+
+// resolveCalls links call relationships to their enclosing symbol and,
+// where the target can be determined from scope, to the called symbol. A
+// nil scope resolves every call. It returns the number of calls resolved to
+// a concrete symbol.
+func resolveCalls(ctx context.Context, tx *sql.Tx, scope *resolveScope) (int, error) {
+	r, err := loadCallResolver(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, source_file, COALESCE(target_symbol, ''), line FROM relationships WHERE kind = 'call';`)
+	if err != nil {
+		return 0, fmt.Errorf("query calls: %w", err)
+	}
+	type callRow struct {
+		id     int64
+		file   string
+		target string
+		line   int
+	}
+	var calls []callRow
+	for rows.Next() {
+		var c callRow
+		if err := rows.Scan(&c.id, &c.file, &c.target, &c.line); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if scope.includes(c.file, c.target) {
+			calls = append(calls, c)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE relationships SET source_symbol_id = ?, target_symbol_id = ?, resolution = ? WHERE id = ?;`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	resolved := 0
+	for _, c := range calls {
+		var sourceID, targetID any
+		caller := r.enclosing(c.file, c.line)
+		if caller != nil {
+			sourceID = caller.id
+		}
+
+		resolution := ResolutionHeuristic
+		if target := r.resolve(c.file, c.target, caller); target != nil {
+			targetID = target.id
+			resolution = ResolutionResolved
+			resolved++
+		}
+
+		if _, err := stmt.ExecContext(ctx, sourceID, targetID, resolution, c.id); err != nil {
+			return resolved, fmt.Errorf("update call %d: %w", c.id, err)
+		}
+	}
+	return resolved, nil
+}
+
+func loadCallResolver(ctx context.Context, tx *sql.Tx) (*callResolver, error) {
+	r := &callResolver{
+		byName:  make(map[string][]*resolverSymbol),
+		byFile:  make(map[string][]*resolverSymbol),
+		imports: make(map[string][]string),
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.id, s.file_path, s.name, s.kind, s.line_start, s.line_end, COALESCE(s.signature, ''), COALESCE(p.name, '')
+		FROM symbols s
+		LEFT JOIN symbols p ON p.id = s.parent_id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	for rows.Next() {
+		var s resolverSymbol
+		var signature string
+		if err := rows.Scan(&s.id, &s.file, &s.name, &s.kind, &s.start, &s.end, &signature, &s.owner); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if s.owner == "" && s.kind == "method" {
+			s.receiver, s.owner = goReceiver(signature)
+		}
+		r.byName[s.name] = append(r.byName[s.name], &s)
+		r.byFile[s.file] = append(r.byFile[s.file], &s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `SELECT source_file, target_file FROM relationships WHERE kind = 'import' AND COALESCE(target_file, '') != '';`)
+	if err != nil {
+		return nil, fmt.Errorf("query imports: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var source, target string
+		if err := rows.Scan(&source, &target); err != nil {
+			return nil, err
+		}
+		r.imports[source] = append(r.imports[source], target)
+	}
+	return r, rows.Err()
+}
+
+// enclosing returns the innermost function or method containing line.
+func (r *callResolver) enclosing(file string, line int) *resolverSymbol {
+	var best *resolverSymbol
+	for _, s := range r.byFile[file] {
+		if s.kind != "function" && s.kind != "method" && s.kind != "constructor" {
+			continue
+		}
+		if s.start > line || s.end < line {
+			continue
+		}
+		if best == nil || s.end-s.start < best.end-best.start {
+			best = s
+		}
+	}
+	return best
+}
+
+// resolve picks the symbol a call targets, or nil if it cannot be determined.
+func (r *callResolver) resolve(file, target string, caller *resolverSymbol) *resolverSymbol {
+	qualifier, name := splitCallTarget(target)
+	var candidates []*resolverSymbol
+	for _, s := range r.byName[name] {
+		if callableKinds[s.kind] {
+			candidates = append(candidates, s)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Receivers like "this.repo" or "svc.ops" are typed by their last segment.
+	receiver := qualifier
+	if i := strings.LastIndexAny(receiver, ".:"); i >= 0 {
+		receiver = receiver[i+1:]
+	}
+	if selfReceivers[qualifier] || (caller != nil && caller.receiver != "" && qualifier == caller.receiver) {
+		qualifier = ""
+		receiver = ""
+	}
+
+	var best *resolverSymbol
+	bestScore, ties := 0, 0
+	for _, s := range candidates {
+		score := r.score(file, qualifier, receiver, s, caller)
+		switch {
+		case score > bestScore:
+			best, bestScore, ties = s, score, 1
+		case score == bestScore && score > 0:
+			ties++
+		}
+	}
+
+	if bestScore == 0 && qualifier == "" && len(candidates) == 1 {
+		best, bestScore, ties = candidates[0], scoreUnique, 1
+	}
+	if bestScore == 0 || ties > 1 {
+		return nil
+	}
+	return best
+}
+
+func (r *callResolver) score(file, qualifier, receiver string, s, caller *resolverSymbol) int {
+	if qualifier == "" {
+		switch {
+		case caller != nil && caller.owner != "" && s.owner == caller.owner && path.Dir(s.file) == path.Dir(caller.file):
+			return scoreOwnerMatch
+		case s.file == file:
+			return scoreSameFile
+		case r.importsFile(file, s.file, ""):
+			return scoreImported
+		case path.Dir(s.file) == path.Dir(file):
+			return scorePackage
+		}
+		return 0
+	}
+
+	switch {
+	case s.owner != "" && strings.EqualFold(s.owner, qualifier):
+		// Static or scoped call: Logger::info, UserService.create
+		return scoreOwnerMatch
+	case s.owner != "" && len(receiver) >= minReceiverMatchLen && strings.HasSuffix(strings.ToLower(s.owner), strings.ToLower(receiver)):
+		// Field named after its type: repository.findById -> UserRepository
+		return scoreOwnerMatch - 1
+	case s.owner == "" && r.importsFile(file, s.file, qualifier):
+		// Package call: config.Load
+		return scoreImported
+	}
+	return 0
+}
+
+// importsFile reports whether source imports the file or package containing
+// target. When alias is set, the import's last segment must also match it.
+func (r *callResolver) importsFile(source, target, alias string) bool {
+	targetDir := path.Dir(target)
+	targetStem := strings.TrimSuffix(target, path.Ext(target))
+
+	for _, imp := range r.imports[source] {
+		if alias != "" && !strings.EqualFold(importBase(imp), alias) {
+			continue
+		}
+
+		if strings.HasPrefix(imp, ".") {
+			// Relative import: ./util, ../lib/helpers.js
+			resolved := path.Join(path.Dir(source), imp)
+			resolved = strings.TrimSuffix(resolved, path.Ext(resolved))
+			if resolved == targetStem || resolved == targetDir || path.Join(resolved, "index") == targetStem {
+				return true
+			}
+			continue
+		}
+
+		// Module paths end in the package directory; dotted imports
+		// (com.acme.Util, pkg.module) map to file paths.
+		slashed := strings.ReplaceAll(imp, "::", "/")
+		if !strings.Contains(slashed, "/") {
+			slashed = strings.ReplaceAll(slashed, ".", "/")
+		}
+		if hasPathSuffix(slashed, targetDir) || hasPathSuffix(slashed, targetStem) || hasPathSuffix(targetStem, slashed) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCallTarget splits "pkg.Func" or "Type::method" into qualifier and name.
+func splitCallTarget(target string) (qualifier, name string) {
+	dot := strings.LastIndex(target, ".")
+	colons := strings.LastIndex(target, "::")
+	switch {
+	case colons > dot:
+		return target[:colons], target[colons+2:]
+	case dot >= 0:
+		return target[:dot], target[dot+1:]
+	}
+	return "", target
+}
+
+// goReceiver extracts the receiver variable and type from a Go method
+// signature such as "(s *Server) Start(ctx context.Context) error".
+func goReceiver(signature string) (variable, typeName string) {
+	if !strings.HasPrefix(signature, "(") {
+		return "", ""
+	}
+	end := strings.Index(signature, ")")
+	if end < 0 {
+		return "", ""
+	}
+	fields := strings.Fields(signature[1:end])
+	if len(fields) == 0 {
+		return "", ""
+	}
+	typeName = strings.TrimLeft(fields[len(fields)-1], "*")
+	if i := strings.Index(typeName, "["); i >= 0 {
+		typeName = typeName[:i]
+	}
+	if len(fields) > 1 {
+		variable = fields[0]
+	}
+	return variable, typeName
+}
+
+// importBase returns the name an import is usually referred to by.
+func importBase(imp string) string {
+	if strings.Contains(imp, "/") {
+		imp = strings.TrimSuffix(imp, path.Ext(imp))
+	}
+	if i := strings.LastIndexAny(imp, "/.:"); i >= 0 {
+		return imp[i+1:]
+	}
+	return imp
+}
+
+func hasPathSuffix(p, suffix string) bool {
+	if suffix == "" || suffix == "." {
+		return false
+	}
+	return p == suffix || strings.HasSuffix(p, "/"+suffix)
+}
@@ -0,0 +1,266 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+)
+
+var resolveTestFiles = map[string]string{
+	"a/store.go": `package a
+
+type Store struct{}
+
+func New() *Store { return &Store{} }
+
+func (s *Store) Close() error { return nil }
+
+func (s *Store) Flush() {
+	s.Close()
+}
+`,
+	"b/conn.go": `package b
+
+import "example.com/app/a"
+
+type Conn struct{}
+
+func (c *Conn) Close() error { return nil }
+
+func Open(x closer) *Conn {
+	a.New()
+	helper()
+	x.Close()
+	return &Conn{}
+}
+
+func helper() {}
+`,
+}
+
+func writeResolveTestScan(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	root := t.TempDir()
+	db, err := Open(filepath.Join(root, "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	var records []FileRecord
+	for path, src := range resolveTestFiles {
+		fa, err := analysis.Analyze([]byte(src), path)
+		if err != nil {
+			t.Fatalf("Analyze(%s) error = %v", path, err)
+		}
+		records = append(records, FileRecord{Path: path, Hash: path, Size: int64(len(src)), ModTime: time.Now(), Language: "go", Analysis: fa})
+	}
+
+	summary, err := WriteScan(db, root, records, time.Now())
+	if err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+	if summary.ResolvedCallCount != 3 {
+		t.Errorf("ResolvedCallCount = %d, want 3", summary.ResolvedCallCount)
+	}
+	return db, root
+}
+
+func callResolution(t *testing.T, db *sql.DB, file, target string) (string, string) {
+	t.Helper()
+	var resolution, owner string
+	err := db.QueryRowContext(context.Background(), `
+		SELECT r.resolution, COALESCE(s.file_path || ':' || s.name, '')
+		FROM relationships r LEFT JOIN symbols s ON s.id = r.target_symbol_id
+		WHERE r.kind = 'call' AND r.source_file = ? AND r.target_symbol = ?;
+	`, file, target).Scan(&resolution, &owner)
+	if err != nil {
+		t.Fatalf("query %s in %s: %v", target, file, err)
+	}
+	return resolution, owner
+}
+
+func TestResolveCalls(t *testing.T) {
+	db, _ := writeResolveTestScan(t)
+
+	tests := []struct {
+		file, target   string
+		wantResolution string
+		wantTarget     string
+	}{
+		{"a/store.go", "s.Close", ResolutionResolved, "a/store.go:Close"}, // receiver type
+		{"b/conn.go", "a.New", ResolutionResolved, "a/store.go:New"},      // import
+		{"b/conn.go", "helper", ResolutionResolved, "b/conn.go:helper"},   // same file
+		{"b/conn.go", "x.Close", ResolutionHeuristic, ""},                 // unknown receiver type
+	}
+	for _, tt := range tests {
+		resolution, target := callResolution(t, db, tt.file, tt.target)
+		if resolution != tt.wantResolution || target != tt.wantTarget {
+			t.Errorf("%s in %s = %s %q, want %s %q", tt.target, tt.file, resolution, target, tt.wantResolution, tt.wantTarget)
+		}
+	}
+}
+
+func TestCallChainUsesResolvedTargets(t *testing.T) {
+	db, _ := writeResolveTestScan(t)
+
+	// Conn.Close is only reachable through the heuristic x.Close() call;
+	// Store.Flush resolves to Store.Close and must not appear.
+	result, err := GetCallChain(db, "Close", "b/conn.go", "up", 2)
+	if err != nil {
+		t.Fatalf("GetCallChain() error = %v", err)
+	}
+	if len(result.Chains) != 1 || result.Chains[0].Symbol != "Open" || result.Chains[0].Resolution != ResolutionHeuristic {
+		t.Errorf("callers of Conn.Close = %+v", result.Chains)
+	}
+
+	result, err = GetCallChain(db, "Close", "a/store.go", "up", 2)
+	if err != nil {
+		t.Fatalf("GetCallChain() error = %v", err)
+	}
+	callers := map[string]string{}
+	for _, node := range result.Chains {
+		callers[node.Symbol] = node.Resolution
+	}
+	if callers["Flush"] != ResolutionResolved || callers["Open"] != ResolutionHeuristic || len(callers) != 2 {
+		t.Errorf("callers of Store.Close = %v", callers)
+	}
+
+	calls, err := GetOutgoingCalls(db, "Open", "b/conn.go")
+	if err != nil {
+		t.Fatalf("GetOutgoingCalls() error = %v", err)
+	}
+	for _, call := range calls {
+		if call.CalleeSymbol == "a.New" && (call.Resolution != ResolutionResolved || call.CalleeSymbolID == 0) {
+			t.Errorf("a.New call = %+v, want resolved", call)
+		}
+	}
+}
+
+func TestIncrementalScanResolvesCalls(t *testing.T) {
+	root := t.TempDir()
+	for path, src := range resolveTestFiles {
+		abs := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db, err := Open(filepath.Join(root, "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	summary, err := IncrementalScan(db, root, []FileChange{
+		{Path: "a/store.go", Action: "added"},
+		{Path: "b/conn.go", Action: "added"},
+	})
+	if err != nil {
+		t.Fatalf("IncrementalScan() error = %v", err)
+	}
+	if summary.CallsResolved != 3 {
+		t.Errorf("CallsResolved = %d, want 3", summary.CallsResolved)
+	}
+
+	// Calls that cannot target a/store.go are left alone when it changes.
+	if _, err := db.ExecContext(context.Background(), `UPDATE relationships SET resolution = 'untouched' WHERE target_symbol = 'helper'`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-indexing a/store.go gives its symbols new IDs; calls into it from
+	// b/conn.go must follow.
+	if _, err := IncrementalScan(db, root, []FileChange{{Path: "a/store.go", Action: "modified"}}); err != nil {
+		t.Fatalf("IncrementalScan() error = %v", err)
+	}
+	if resolution, target := callResolution(t, db, "b/conn.go", "a.New"); resolution != ResolutionResolved || target != "a/store.go:New" {
+		t.Errorf("a.New after re-index = %s %q", resolution, target)
+	}
+	if resolution, _ := callResolution(t, db, "b/conn.go", "helper"); resolution != "untouched" {
+		t.Errorf("helper call was re-resolved: %s", resolution)
+	}
+}
+
+func TestIncomingCallsDisambiguatesDefinitions(t *testing.T) {
+	db, _ := writeResolveTestScan(t)
+
+	var ambiguous *AmbiguousSymbolError
+	if _, err := GetIncomingCalls(db, "Close"); !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("GetIncomingCalls(Close) error = %v, want two candidates", err)
+	}
+
+	callers := func(calls []CallSite) map[string]string {
+		got := map[string]string{}
+		for _, c := range calls {
+			got[c.CallerSymbol] = c.Resolution
+		}
+		return got
+	}
+	for _, tt := range []struct {
+		symbol, file string
+		want         map[string]string
+	}{
+		// Store.Flush resolves to Store.Close; x.Close in Open is unresolved.
+		{"Store.Close", "", map[string]string{"Flush": ResolutionResolved, "Open": ResolutionHeuristic}},
+		{"Close", "a/store.go", map[string]string{"Flush": ResolutionResolved, "Open": ResolutionHeuristic}},
+		{"Conn.Close", "", map[string]string{"Open": ResolutionHeuristic}},
+		{"a.New", "", map[string]string{"Open": ResolutionResolved}},
+	} {
+		calls, err := GetIncomingCallsIn(db, tt.symbol, tt.file)
+		if err != nil {
+			t.Fatalf("GetIncomingCallsIn(%s, %q) error = %v", tt.symbol, tt.file, err)
+		}
+		if got := callers(calls); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("callers of %s in %q = %v, want %v", tt.symbol, tt.file, got, tt.want)
+		}
+	}
+
+	// A line inside Conn.Close picks it within its file.
+	result, err := GetCallChainAt(db, "Close", "b/conn.go", 7, "up", 1)
+	if err != nil || len(result.Chains) != 1 || result.Chains[0].Symbol != "Open" {
+		t.Errorf("GetCallChainAt(Conn.Close) = %+v, %v", result, err)
+	}
+}
+
+func TestSymbolCentralityUsesResolvedTargets(t *testing.T) {
+	db, _ := writeResolveTestScan(t)
+
+	// s.Close resolves to Store.Close; x.Close is unresolved and counts for both.
+	store, err := GetSymbolCentrality(db, "Close", "a/store.go")
+	if err != nil {
+		t.Fatalf("GetSymbolCentrality(a/store.go) error = %v", err)
+	}
+	conn, err := GetSymbolCentrality(db, "Close", "b/conn.go")
+	if err != nil {
+		t.Fatalf("GetSymbolCentrality(b/conn.go) error = %v", err)
+	}
+	if store <= conn || conn == 0 {
+		t.Errorf("centrality Store.Close = %v, Conn.Close = %v; want Store.Close higher and both called", store, conn)
+	}
+}
+
+func TestGoReceiver(t *testing.T) {
+	tests := []struct {
+		signature, wantVar, wantType string
+	}{
+		{"(s *Server) Start() error", "s", "Server"},
+		{"(Server) Name() string", "", "Server"},
+		{"(l *List[T]) Len() int", "l", "List"},
+		{"func Start()", "", ""},
+	}
+	for _, tt := range tests {
+		variable, typeName := goReceiver(tt.signature)
+		if variable != tt.wantVar || typeName != tt.wantType {
+			t.Errorf("goReceiver(%q) = %q, %q; want %q, %q", tt.signature, variable, typeName, tt.wantVar, tt.wantType)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -29,6 +30,7 @@ type IncrementalScanSummary struct {
 	FilesModified  int
 	FilesDeleted   int
 	FilesUnchanged int
+	CallsResolved  int // Calls the changes affected that are linked to a concrete symbol
 	Duration       time.Duration
 }
 
@@ -111,6 +113,101 @@ func DetectChanges(db *sql.DB, root string, guardrails config.Guardrails) ([]Fil
 	return changes, nil
 }
 
+// DetectPathChanges is DetectChanges limited to the given paths, as reported
+// by a file watcher. A path naming a directory covers every file below it, so
+// renamed or removed directories are picked up too. Files whose content
+// matches the index are not reported.
+func DetectPathChanges(db *sql.DB, root string, guardrails config.Guardrails, paths []string) ([]FileChange, error) {
+	seen := make(map[string]bool)
+	var changes []FileChange
+
+	check := func(relPath string) error {
+		if seen[relPath] || fsutil.MatchesGuardrail(relPath, guardrails) {
+			return nil
+		}
+		seen[relPath] = true
+
+		var oldHash string
+		err := db.QueryRowContext(context.Background(), "SELECT hash FROM files WHERE path = ?", relPath).Scan(&oldHash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("query indexed file: %w", err)
+		}
+		indexed := err == nil
+
+		data, readErr := os.ReadFile(filepath.Join(root, relPath))
+		if readErr != nil {
+			if indexed {
+				changes = append(changes, FileChange{Path: relPath, Action: "deleted", OldHash: oldHash})
+			}
+			return nil
+		}
+
+		h := sha256.Sum256(data)
+		newHash := fmt.Sprintf("%x", h[:])
+		switch {
+		case !indexed:
+			changes = append(changes, FileChange{Path: relPath, Action: "added", NewHash: newHash})
+		case oldHash != newHash:
+			changes = append(changes, FileChange{Path: relPath, Action: "modified", OldHash: oldHash, NewHash: newHash})
+		}
+		return nil
+	}
+
+	for _, p := range paths {
+		relPath := filepath.ToSlash(filepath.Clean(p))
+
+		info, statErr := os.Stat(filepath.Join(root, relPath))
+		isDir := statErr == nil && info.IsDir()
+		if isDir {
+			files, err := fsutil.ListFiles(filepath.Join(root, relPath), guardrails)
+			if err != nil {
+				return nil, fmt.Errorf("list files: %w", err)
+			}
+			for _, f := range files {
+				if err := check(relPath + "/" + f); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// Indexed files at or below the path; this also covers a path that
+		// no longer exists on disk.
+		rows, err := db.QueryContext(context.Background(),
+			"SELECT path FROM files WHERE path = ? OR substr(path, 1, ?) = ?",
+			relPath, len(relPath)+1, relPath+"/")
+		if err != nil {
+			return nil, fmt.Errorf("query indexed files: %w", err)
+		}
+		var indexed []string
+		for rows.Next() {
+			var path string
+			if err := rows.Scan(&path); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan indexed file: %w", err)
+			}
+			indexed = append(indexed, path)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("iterate indexed files: %w", err)
+		}
+		for _, path := range indexed {
+			if err := check(path); err != nil {
+				return nil, err
+			}
+		}
+
+		if !isDir {
+			if err := check(relPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
 // IncrementalScan only processes files that have changed since the last scan.
 // It's much faster than a full scan for large codebases with few changes.
 func IncrementalScan(db *sql.DB, root string, changes []FileChange) (IncrementalScanSummary, error) {
@@ -128,7 +225,15 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 	}
 	defer tx.Rollback()
 
+	scope := &resolveScope{files: make(map[string]bool), names: make(map[string]bool)}
 	for _, change := range changes {
+		// Calls to the names a file defined before the change may now
+		// resolve elsewhere.
+		if err := addSymbolNames(tx, change.Path, scope.names); err != nil {
+			return summary, err
+		}
+		scope.files[change.Path] = true
+
 		switch change.Action {
 		case "deleted":
 			if err := deleteFileFromIndex(tx, change.Path); err != nil {
@@ -148,6 +253,10 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 				return summary, fmt.Errorf("index %s: %w", change.Path, err)
 			}
 
+			if err := addSymbolNames(tx, change.Path, scope.names); err != nil {
+				return summary, err
+			}
+
 			if change.Action == "added" {
 				summary.FilesAdded++
 			} else {
@@ -156,6 +265,14 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 		}
 	}
 
+	// Symbols in changed files have new IDs, so calls that may target them
+	// are re-resolved.
+	resolved, err := resolveCalls(context.Background(), tx, scope)
+	if err != nil {
+		return summary, fmt.Errorf("resolve calls: %w", err)
+	}
+	summary.CallsResolved = resolved
+
 	if err := tx.Commit(); err != nil {
 		return summary, fmt.Errorf("commit: %w", err)
 	}
@@ -164,6 +281,23 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 	return summary, nil
 }
 
+// addSymbolNames adds the names of the symbols indexed for relPath to names.
+func addSymbolNames(tx *sql.Tx, relPath string, names map[string]bool) error {
+	rows, err := tx.QueryContext(context.Background(), `SELECT DISTINCT name FROM symbols WHERE file_path = ?`, relPath)
+	if err != nil {
+		return fmt.Errorf("query symbols of %s: %w", relPath, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		names[name] = true
+	}
+	return rows.Err()
+}
+
 // deleteFileFromIndex removes a file and all associated data from the index
 func deleteFileFromIndex(tx *sql.Tx, relPath string) error {
 	// FTS tables need to be cleaned first
@@ -209,6 +343,8 @@ func indexSingleFile(tx *sql.Tx, relPath, absPath string) error {
 		}
 	}
 
+	chunks := chunkFile(string(data), fileAnalysis)
+
 	// Insert file record
 	_, err = tx.ExecContext(context.Background(), `INSERT INTO files(path, hash, size, mod_time, indexed_at, language) VALUES(?, ?, ?, ?, ?, ?);`,
 		relPath, hash, info.Size(), fsutil.NormalizeModTime(info.ModTime()).Format(time.RFC3339), now, string(lang))
@@ -217,10 +353,9 @@ func indexSingleFile(tx *sql.Tx, relPath, absPath string) error {
 	}
 
 	// Insert chunks
-	chunks := fsutil.ChunkContent(string(data), 120, 8*1024)
 	for i, chunk := range chunks {
-		_, err = tx.ExecContext(context.Background(), `INSERT INTO chunks(path, chunk_index, start_line, end_line, content) VALUES(?, ?, ?, ?, ?);`,
-			relPath, i, chunk.StartLine, chunk.EndLine, chunk.Content)
+		_, err = tx.ExecContext(context.Background(), `INSERT INTO chunks(path, chunk_index, start_line, end_line, content, symbol, symbol_kind) VALUES(?, ?, ?, ?, ?, ?, ?);`,
+			relPath, i, chunk.StartLine, chunk.EndLine, chunk.Content, chunk.Symbol, chunk.SymbolKind)
 		if err != nil {
 			return fmt.Errorf("insert chunk: %w", err)
 		}
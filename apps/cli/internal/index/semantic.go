@@ -0,0 +1,585 @@
+package index
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
+)
+
+// Embedder generates vector embeddings for text. memory.Embedder satisfies it.
+type Embedder interface {
+	Embed(text string) ([]float32, error)
+	Model() string
+}
+
+// Kinds of rows stored in code_embeddings.
+const (
+	CodeEmbeddingChunk  = "chunk"
+	CodeEmbeddingSymbol = "symbol"
+)
+
+// RRFK is the rank offset used by reciprocal rank fusion. Larger values
+// flatten the difference between top and lower ranks.
+const RRFK = 60
+
+// codeEmbeddingBatch is how many embeddings are written per transaction, so
+// an embedder failing part way through keeps the work already done.
+const codeEmbeddingBatch = 100
+
+// maxEmbedTextLen bounds the text sent to the embedder for one chunk.
+const maxEmbedTextLen = 8000
+
+// CodeEmbeddingSummary reports the work done by EmbedCode.
+type CodeEmbeddingSummary struct {
+	Model    string `json:"model"`
+	Embedded int    `json:"embedded"` // Chunks and symbols embedded in this run
+	Reused   int    `json:"reused"`   // Unchanged content kept from a previous run
+	Removed  int    `json:"removed"`  // Embeddings for content no longer indexed
+}
+
+// VectorHit is a code embedding matched by similarity to a query.
+type VectorHit struct {
+	Path       string  `json:"path"`
+	Kind       string  `json:"kind"` // "chunk" or "symbol"
+	ChunkIndex int     `json:"chunkIndex"`
+	Symbol     string  `json:"symbol,omitempty"`
+	StartLine  int     `json:"startLine"`
+	EndLine    int     `json:"endLine"`
+	Similarity float32 `json:"similarity"`
+}
+
+// HybridChunkHit is a chunk ranked by reciprocal rank fusion of BM25 and
+// vector similarity.
+type HybridChunkHit struct {
+	ChunkHit
+	Score       float64 // Fused score
+	LexicalRank int     // 1-based BM25 rank, 0 if not matched
+	VectorRank  int     // 1-based similarity rank, 0 if not matched
+	Similarity  float32 // Best similarity of the chunk or a symbol in it
+}
+
+// codeItem is a chunk or documented symbol to embed.
+type codeItem struct {
+	path       string
+	kind       string
+	symbol     string
+	chunkIndex int
+	start      int
+	end        int
+	text       string
+	hash       string
+}
+
+// EmbedCode brings code_embeddings in line with the indexed chunks and symbol
+// doc comments. Content already embedded with the same model is kept, so after
+// an incremental scan only changed files are sent to the embedder.
+func EmbedCode(db *sql.DB, embedder Embedder) (CodeEmbeddingSummary, error) {
+	ctx := context.Background()
+	model := embedder.Model()
+	summary := CodeEmbeddingSummary{Model: model}
+
+	items, err := loadCodeItems(ctx, db)
+	if err != nil {
+		return summary, err
+	}
+
+	type storedRow struct {
+		id                     int64
+		chunkIndex, start, end int
+	}
+	stored := make(map[string][]storedRow)
+	var stale []int64
+	rows, err := db.QueryContext(ctx, `SELECT id, path, kind, content_hash, model, chunk_index, start_line, end_line FROM code_embeddings;`)
+	if err != nil {
+		return summary, fmt.Errorf("query code embeddings: %w", err)
+	}
+	for rows.Next() {
+		var r storedRow
+		var path, kind, hash, rowModel string
+		if err := rows.Scan(&r.id, &path, &kind, &hash, &rowModel, &r.chunkIndex, &r.start, &r.end); err != nil {
+			rows.Close()
+			return summary, err
+		}
+		if rowModel != model {
+			stale = append(stale, r.id)
+			continue
+		}
+		key := path + "\x00" + kind + "\x00" + hash
+		stored[key] = append(stored[key], r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return summary, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return summary, err
+	}
+	defer tx.Rollback()
+
+	var pending []codeItem
+	for _, item := range items {
+		key := item.path + "\x00" + item.kind + "\x00" + item.hash
+		pool := stored[key]
+		if len(pool) == 0 {
+			pending = append(pending, item)
+			continue
+		}
+		r := pool[0]
+		stored[key] = pool[1:]
+		summary.Reused++
+		if r.chunkIndex != item.chunkIndex || r.start != item.start || r.end != item.end {
+			if _, err := tx.ExecContext(ctx, `UPDATE code_embeddings SET chunk_index = ?, start_line = ?, end_line = ? WHERE id = ?;`,
+				item.chunkIndex, item.start, item.end, r.id); err != nil {
+				return summary, fmt.Errorf("update code embedding: %w", err)
+			}
+		}
+	}
+	for _, pool := range stored {
+		for _, r := range pool {
+			stale = append(stale, r.id)
+		}
+	}
+	for _, id := range stale {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM code_embeddings WHERE id = ?;`, id); err != nil {
+			return summary, fmt.Errorf("delete code embedding: %w", err)
+		}
+	}
+	summary.Removed = len(stale)
+	if err := tx.Commit(); err != nil {
+		return summary, err
+	}
+
+	for start := 0; start < len(pending); start += codeEmbeddingBatch {
+		batch := pending[start:min(start+codeEmbeddingBatch, len(pending))]
+		n, err := storeCodeEmbeddings(ctx, db, embedder, batch)
+		summary.Embedded += n
+		if err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// storeCodeEmbeddings embeds a batch of items and writes them in one
+// transaction. Items embedded before an embedder error are still written.
+func storeCodeEmbeddings(ctx context.Context, db *sql.DB, embedder Embedder, batch []codeItem) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO code_embeddings (path, kind, chunk_index, symbol, start_line, end_line, content_hash, embedding, model)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	written := 0
+	var embedErr error
+	for _, item := range batch {
+		vec, err := embedder.Embed(item.text)
+		if err != nil {
+			embedErr = fmt.Errorf("embed %s: %w", item.path, err)
+			break
+		}
+		if len(vec) == 0 {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, item.path, item.kind, item.chunkIndex, item.symbol, item.start, item.end,
+			item.hash, vector.Encode(vec), embedder.Model()); err != nil {
+			return 0, fmt.Errorf("insert code embedding: %w", err)
+		}
+		written++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return written, embedErr
+}
+
+// loadCodeItems returns every chunk and every symbol with a doc comment.
+func loadCodeItems(ctx context.Context, db *sql.DB) ([]codeItem, error) {
+	type span struct{ index, start, end int }
+	spans := make(map[string][]span)
+	var items []codeItem
+
+	rows, err := db.QueryContext(ctx, `SELECT path, chunk_index, start_line, end_line, content FROM chunks ORDER BY path, chunk_index;`)
+	if err != nil {
+		return nil, fmt.Errorf("query chunks: %w", err)
+	}
+	for rows.Next() {
+		item := codeItem{kind: CodeEmbeddingChunk}
+		var content string
+		if err := rows.Scan(&item.path, &item.chunkIndex, &item.start, &item.end, &content); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		item.text = item.path + "\n" + content
+		items = append(items, item)
+		spans[item.path] = append(spans[item.path], span{item.chunkIndex, item.start, item.end})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT file_path, name, kind, line_start, line_end, COALESCE(signature, ''), doc_comment
+		FROM symbols
+		WHERE COALESCE(doc_comment, '') != '';
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := codeItem{kind: CodeEmbeddingSymbol}
+		var symbolKind, signature, doc string
+		if err := rows.Scan(&item.path, &item.symbol, &symbolKind, &item.start, &item.end, &signature, &doc); err != nil {
+			return nil, err
+		}
+		for _, s := range spans[item.path] {
+			if s.start <= item.start && item.start <= s.end {
+				item.chunkIndex = s.index
+				break
+			}
+		}
+		item.text = strings.TrimSpace(symbolKind + " " + item.symbol + "\n" + signature + "\n" + doc)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].text = truncateUTF8(items[i].text, maxEmbedTextLen)
+		sum := sha256.Sum256([]byte(items[i].text))
+		items[i].hash = fmt.Sprintf("%x", sum[:16])
+	}
+	return items, nil
+}
+
+// HasCodeEmbeddings reports whether any code embeddings exist for model.
+func HasCodeEmbeddings(db *sql.DB, model string) bool {
+	var exists int
+	err := db.QueryRowContext(context.Background(), `SELECT 1 FROM code_embeddings WHERE model = ? LIMIT 1;`, model).Scan(&exists)
+	return err == nil
+}
+
+// SearchCodeVectors returns the chunks most similar to query among embeddings
+// made with model, skipping files keep rejects (a nil keep accepts all).
+// Symbol matches are reported against the chunk containing them; each chunk
+// appears once with its best similarity. Large indexes are searched through
+// an HNSW graph stored next to the database; small ones compare against
+// every embedding.
+func SearchCodeVectors(db *sql.DB, query []float32, model string, limit int, keep func(path string) bool) ([]VectorHit, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if keep == nil {
+		keep = func(string) bool { return true }
+	}
+
+	var count int
+	if err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM code_embeddings WHERE model = ?;`, model).Scan(&count); err != nil {
+		return nil, fmt.Errorf("count code embeddings: %w", err)
+	}
+	if count >= codeVectorIndexMinRows {
+		if idx := loadCodeVectorIndex(db); idx != nil {
+			hits, err := searchCodeVectorIndex(db, idx, query, model, limit, count, keep)
+			if err == nil {
+				return hits, nil
+			}
+			log.Printf("code vector index unavailable, using brute force: %v", err)
+		}
+	}
+	return searchCodeVectorsBruteForce(db, query, model, limit, keep)
+}
+
+// searchCodeVectorIndex queries the HNSW graph, widening the search until
+// enough chunks pass keep or every embedding has been considered.
+func searchCodeVectorIndex(db *sql.DB, idx *codeVectorIndex, query []float32, model string, limit, count int, keep func(string) bool) ([]VectorHit, error) {
+	best := make(map[string]VectorHit)
+	for k := limit * 4; ; k *= 4 {
+		matches, err := idx.search(db, query, model, k)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.ID
+		}
+		rows, err := codeEmbeddingRows(db, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			h, ok := rows[m.ID]
+			if !ok || !keep(h.Path) {
+				continue
+			}
+			h.Similarity = m.Similarity
+			addBestHit(best, h)
+		}
+		if len(best) >= limit || len(matches) < k || k >= count {
+			break
+		}
+	}
+	return rankVectorHits(best, limit), nil
+}
+
+// searchCodeVectorsBruteForce compares the query against every embedding.
+func searchCodeVectorsBruteForce(db *sql.DB, query []float32, model string, limit int, keep func(string) bool) ([]VectorHit, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT path, kind, chunk_index, COALESCE(symbol, ''), start_line, end_line, embedding
+		FROM code_embeddings
+		WHERE model = ?;
+	`, model)
+	if err != nil {
+		return nil, fmt.Errorf("query code embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	best := make(map[string]VectorHit)
+	for rows.Next() {
+		var h VectorHit
+		var blob []byte
+		if err := rows.Scan(&h.Path, &h.Kind, &h.ChunkIndex, &h.Symbol, &h.StartLine, &h.EndLine, &blob); err != nil {
+			return nil, err
+		}
+		if !keep(h.Path) {
+			continue
+		}
+		h.Similarity = vector.Cosine(query, vector.Decode(blob))
+		addBestHit(best, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rankVectorHits(best, limit), nil
+}
+
+// addBestHit records h unless its chunk already has a more similar hit.
+func addBestHit(best map[string]VectorHit, h VectorHit) {
+	key := chunkKey(h.Path, h.ChunkIndex)
+	if prev, ok := best[key]; !ok || h.Similarity > prev.Similarity {
+		best[key] = h
+	}
+}
+
+// rankVectorHits orders hits by descending similarity and keeps the first limit.
+func rankVectorHits(best map[string]VectorHit, limit int) []VectorHit {
+	hits := make([]VectorHit, 0, len(best))
+	for _, h := range best {
+		hits = append(hits, h)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Similarity != hits[j].Similarity {
+			return hits[i].Similarity > hits[j].Similarity
+		}
+		return chunkKey(hits[i].Path, hits[i].ChunkIndex) < chunkKey(hits[j].Path, hits[j].ChunkIndex)
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// ReciprocalRankFusion scores keys by the sum of 1/(RRFK+rank) over every
+// ranked list they appear in.
+func ReciprocalRankFusion(lists ...[]string) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, list := range lists {
+		for i, key := range list {
+			scores[key] += 1 / float64(RRFK+i+1)
+		}
+	}
+	return scores
+}
+
+// SearchChunksHybrid ranks chunks by fusing BM25 matches of any query term
+// with vector similarity over code embeddings. Chunks of files keep rejects
+// are left out of both rankings (a nil keep accepts all). Without an
+// embedder, or when the index has no embeddings for its model, it ranks by
+// BM25 alone.
+func SearchChunksHybrid(db *sql.DB, query string, embedder Embedder, limit int, keep func(path string) bool) ([]HybridChunkHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	lexical, err := searchChunksKept(db, ftsAnyTermQuery(query), limit*3, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	var similar []VectorHit
+	if embedder != nil && HasCodeEmbeddings(db, embedder.Model()) {
+		// An unreachable embedder degrades to lexical search.
+		if vec, err := embedder.Embed(query); err == nil {
+			similar, err = SearchCodeVectors(db, vec, embedder.Model(), limit*3, keep)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	hits := make(map[string]*HybridChunkHit)
+	lexicalKeys := make([]string, len(lexical))
+	for i, c := range lexical {
+		key := chunkKey(c.Path, c.ChunkIndex)
+		lexicalKeys[i] = key
+		hits[key] = &HybridChunkHit{ChunkHit: c, LexicalRank: i + 1}
+	}
+	vectorKeys := make([]string, 0, len(similar))
+	for i, v := range similar {
+		key := chunkKey(v.Path, v.ChunkIndex)
+		h := hits[key]
+		if h == nil {
+			row, err := GetChunk(db, v.Path, v.ChunkIndex)
+			if err != nil {
+				continue // embedding outlived its chunk
+			}
+			h = &HybridChunkHit{ChunkHit: ChunkHit{
+				Path:       v.Path,
+				ChunkIndex: row.ChunkIndex,
+				StartLine:  row.StartLine,
+				EndLine:    row.EndLine,
+				Content:    row.Content,
+				Symbol:     row.Symbol,
+				SymbolKind: row.SymbolKind,
+			}}
+			hits[key] = h
+		}
+		h.VectorRank = i + 1
+		h.Similarity = v.Similarity
+		vectorKeys = append(vectorKeys, key)
+	}
+
+	scores := ReciprocalRankFusion(lexicalKeys, vectorKeys)
+	results := make([]HybridChunkHit, 0, len(hits))
+	for key, h := range hits {
+		h.Score = scores[key]
+		results = append(results, *h)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return chunkKey(results[i].Path, results[i].ChunkIndex) < chunkKey(results[j].Path, results[j].ChunkIndex)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// GetChunk retrieves a single chunk of a file.
+func GetChunk(db *sql.DB, path string, chunkIndex int) (ChunkRow, error) {
+	var row ChunkRow
+	err := db.QueryRowContext(context.Background(), `SELECT chunk_index, start_line, end_line, content, COALESCE(symbol, ''), COALESCE(symbol_kind, '') FROM chunks WHERE path = ? AND chunk_index = ?;`,
+		path, chunkIndex).Scan(&row.ChunkIndex, &row.StartLine, &row.EndLine, &row.Content, &row.Symbol, &row.SymbolKind)
+	return row, err
+}
+
+// searchChunksRanked runs an FTS query over chunks ordered by BM25.
+// searchChunksKept returns up to limit ranked chunks that pass keep (a nil
+// keep accepts all), widening the search until enough pass or every match
+// has been considered.
+func searchChunksKept(db *sql.DB, ftsQuery string, limit int, keep func(path string) bool) ([]ChunkHit, error) {
+	for k := limit; ; k *= 4 {
+		ranked, err := searchChunksRanked(db, ftsQuery, k)
+		if err != nil {
+			return nil, err
+		}
+		kept := ranked[:0]
+		for _, c := range ranked {
+			if keep == nil || keep(c.Path) {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) >= limit || len(ranked) < k {
+			if len(kept) > limit {
+				kept = kept[:limit]
+			}
+			return kept, nil
+		}
+	}
+}
+
+func searchChunksRanked(db *sql.DB, ftsQuery string, limit int) ([]ChunkHit, error) {
+	if ftsQuery == "" {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(context.Background(), `
+        SELECT c.path, c.chunk_index, c.start_line, c.end_line, c.content, COALESCE(c.symbol, ''), COALESCE(c.symbol_kind, '')
+        FROM chunks_fts
+        JOIN chunks c ON c.path = chunks_fts.path AND c.chunk_index = chunks_fts.chunk_index
+        WHERE chunks_fts MATCH ?
+        ORDER BY bm25(chunks_fts)
+        LIMIT ?;
+    `, ftsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hits []ChunkHit
+	for rows.Next() {
+		var h ChunkHit
+		if err := rows.Scan(&h.Path, &h.ChunkIndex, &h.StartLine, &h.EndLine, &h.Content, &h.Symbol, &h.SymbolKind); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// ftsAnyTermQuery builds an FTS5 query matching any word of a natural
+// language query, so BM25 can rank partial matches.
+func ftsAnyTermQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range words {
+		w = strings.ToLower(w)
+		if len(w) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, "\""+w+"\"")
+	}
+	return strings.Join(terms, " OR ")
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func chunkKey(path string, chunkIndex int) string {
+	return fmt.Sprintf("%s#%d", path, chunkIndex)
+}
@@ -0,0 +1,322 @@
+package index
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/fsutil"
+)
+
+// conceptEmbedder maps words onto a few concept dimensions so that related
+// words embed close together without sharing any text.
+type conceptEmbedder struct {
+	model string
+	calls int
+}
+
+var testConcepts = []map[string]bool{
+	{"login": true, "credentials": true, "password": true, "authenticate": true},
+	{"database": true, "query": true, "rows": true, "persist": true},
+	{"render": true, "template": true, "html": true},
+}
+
+func (e *conceptEmbedder) Embed(text string) ([]float32, error) {
+	e.calls++
+	vec := make([]float32, len(testConcepts)+1)
+	vec[len(testConcepts)] = 0.1 // keep unrelated text from being a zero vector
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return r < 'a' || r > 'z' }) {
+		for i, c := range testConcepts {
+			if c[w] {
+				vec[i]++
+			}
+		}
+	}
+	return vec, nil
+}
+
+func (e *conceptEmbedder) Model() string { return e.model }
+
+var semanticTestFiles = map[string]string{
+	"auth/check.go": `package auth
+
+// Verify compares the stored password hash with the supplied credentials.
+func Verify(user, password string) bool {
+	return user != "" && password != ""
+}
+`,
+	"store/rows.go": `package store
+
+// Load runs the query and scans rows into records.
+func Load() error {
+	return nil
+}
+`,
+	"web/page.go": `package web
+
+func Render() string {
+	return "<html></html>"
+}
+`,
+}
+
+func writeSemanticTestScan(t *testing.T, db *sql.DB, files map[string]string) {
+	t.Helper()
+	var records []FileRecord
+	for path, src := range files {
+		fa, err := analysis.Analyze([]byte(src), path)
+		if err != nil {
+			t.Fatalf("Analyze(%s) error = %v", path, err)
+		}
+		records = append(records, FileRecord{
+			Path:     path,
+			Hash:     path + src,
+			Size:     int64(len(src)),
+			ModTime:  time.Now(),
+			Language: "go",
+			Chunks:   fsutil.ChunkContent(src, 120, 8000),
+			Analysis: fa,
+		})
+	}
+	if _, err := WriteScan(db, filepath.Dir(t.TempDir()), records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+}
+
+func openSemanticTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	writeSemanticTestScan(t, db, semanticTestFiles)
+	return db
+}
+
+func TestEmbedCodeReusesUnchangedContent(t *testing.T) {
+	db := openSemanticTestDB(t)
+	embedder := &conceptEmbedder{model: "concepts"}
+
+	// Three chunks plus the two documented functions.
+	summary, err := EmbedCode(db, embedder)
+	if err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+	if summary.Embedded != 5 || summary.Reused != 0 || embedder.calls != 5 {
+		t.Fatalf("first run = %+v (%d calls), want 5 embedded", summary, embedder.calls)
+	}
+
+	// A full rescan rewrites every chunk row but only changed content is
+	// sent to the embedder.
+	changed := map[string]string{}
+	for path, src := range semanticTestFiles {
+		changed[path] = src
+	}
+	changed["web/page.go"] = strings.Replace(semanticTestFiles["web/page.go"], "<html>", "<html lang=en>", 1)
+	writeSemanticTestScan(t, db, changed)
+
+	embedder.calls = 0
+	summary, err = EmbedCode(db, embedder)
+	if err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+	if summary.Embedded != 1 || summary.Reused != 4 || summary.Removed != 1 || embedder.calls != 1 {
+		t.Errorf("after change = %+v (%d calls), want 1 embedded, 4 reused, 1 removed", summary, embedder.calls)
+	}
+
+	// Switching models replaces every embedding.
+	summary, err = EmbedCode(db, &conceptEmbedder{model: "other"})
+	if err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+	if summary.Embedded != 5 || summary.Removed != 5 || HasCodeEmbeddings(db, "concepts") {
+		t.Errorf("after model change = %+v", summary)
+	}
+}
+
+func TestSearchChunksHybrid(t *testing.T) {
+	db := openSemanticTestDB(t)
+	embedder := &conceptEmbedder{model: "concepts"}
+	if _, err := EmbedCode(db, embedder); err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+
+	// "login" appears nowhere in the code; only the vector side finds it.
+	hits, err := SearchChunksHybrid(db, "login", embedder, 5, nil)
+	if err != nil {
+		t.Fatalf("SearchChunksHybrid() error = %v", err)
+	}
+	if len(hits) == 0 || hits[0].Path != "auth/check.go" || hits[0].VectorRank != 1 || hits[0].LexicalRank != 0 {
+		t.Fatalf("hits = %+v, want auth/check.go from vectors", hits)
+	}
+
+	// A chunk found by both BM25 and vectors outranks vector-only matches.
+	hits, err = SearchChunksHybrid(db, "scan database rows", embedder, 5, nil)
+	if err != nil {
+		t.Fatalf("SearchChunksHybrid() error = %v", err)
+	}
+	if len(hits) == 0 || hits[0].Path != "store/rows.go" || hits[0].LexicalRank == 0 || hits[0].VectorRank == 0 {
+		t.Errorf("hits = %+v, want store/rows.go from both", hits)
+	}
+
+	// Without an embedder the search is BM25 only.
+	hits, err = SearchChunksHybrid(db, "login", nil, 5, nil)
+	if err != nil {
+		t.Fatalf("SearchChunksHybrid() error = %v", err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("lexical-only hits = %+v, want none", hits)
+	}
+
+	// Rejected files are dropped from the vector side too.
+	notAuth := func(path string) bool { return !strings.HasPrefix(path, "auth/") }
+	hits, err = SearchChunksHybrid(db, "login", embedder, 5, notAuth)
+	if err != nil {
+		t.Fatalf("SearchChunksHybrid() error = %v", err)
+	}
+	for _, h := range hits {
+		if h.Path == "auth/check.go" {
+			t.Errorf("filtered hits = %+v, want no auth/check.go", hits)
+		}
+	}
+}
+
+func TestSearchChunksHybridFiltersBeforeLimit(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Generated files rank first for "widget"; the only kept match ranks last.
+	files := map[string]string{
+		"app/render.go": "package app\n\n// render draws the widget next to the sidebar, header and footer.\nfunc render() {}\n",
+	}
+	for i := 0; i < 10; i++ {
+		files[fmt.Sprintf("gen/widget%d.go", i)] = "package gen\n\n// widget widget widget\nfunc widget() {}\n"
+	}
+	writeSemanticTestScan(t, db, files)
+
+	notGen := func(path string) bool { return !strings.HasPrefix(path, "gen/") }
+	hits, err := SearchChunksHybrid(db, "widget", nil, 1, notGen)
+	if err != nil {
+		t.Fatalf("SearchChunksHybrid() error = %v", err)
+	}
+	if len(hits) != 1 || hits[0].Path != "app/render.go" {
+		t.Errorf("hits = %+v, want app/render.go", hits)
+	}
+}
+
+func TestSearchCodeVectorsUsesGraph(t *testing.T) {
+	old := codeVectorIndexMinRows
+	codeVectorIndexMinRows = 1
+	t.Cleanup(func() { codeVectorIndexMinRows = old })
+
+	db := openSemanticTestDB(t)
+	embedder := &conceptEmbedder{model: "concepts"}
+	if _, err := EmbedCode(db, embedder); err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+
+	query, _ := embedder.Embed("password")
+	hits, err := SearchCodeVectors(db, query, "concepts", 2, nil)
+	if err != nil {
+		t.Fatalf("SearchCodeVectors() error = %v", err)
+	}
+	want, _ := searchCodeVectorsBruteForce(db, query, "concepts", 2, func(string) bool { return true })
+	if len(hits) != len(want) || hits[0].Path != "auth/check.go" || hits[0].Similarity != want[0].Similarity {
+		t.Fatalf("graph hits = %+v, want %+v", hits, want)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(mainDatabaseFile(db)), codeVectorIndexFile)); err != nil {
+		t.Errorf("graph not saved: %v", err)
+	}
+
+	// New content is picked up by the next search.
+	changed := map[string]string{}
+	for path, src := range semanticTestFiles {
+		changed[path] = src
+	}
+	changed["web/page.go"] = "package web\n\n// Render draws the login template.\nfunc Render() string {\n\treturn \"\"\n}\n"
+	writeSemanticTestScan(t, db, changed)
+	if _, err := EmbedCode(db, embedder); err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+	query, _ = embedder.Embed("template")
+	hits, err = SearchCodeVectors(db, query, "concepts", 1, nil)
+	if err != nil {
+		t.Fatalf("SearchCodeVectors() error = %v", err)
+	}
+	if len(hits) != 1 || hits[0].Path != "web/page.go" || hits[0].Kind != CodeEmbeddingSymbol {
+		t.Errorf("hits after change = %+v, want the new web/page.go doc comment", hits)
+	}
+
+	// Filtering widens the search past rejected files.
+	hits, err = SearchCodeVectors(db, query, "concepts", 1, func(path string) bool { return path != "web/page.go" })
+	if err != nil {
+		t.Fatalf("SearchCodeVectors() error = %v", err)
+	}
+	if len(hits) != 1 || hits[0].Path == "web/page.go" {
+		t.Errorf("filtered hits = %+v", hits)
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	if got := truncateUTF8("héllo", 2); got != "h" {
+		t.Errorf("truncateUTF8 split a rune: %q", got)
+	}
+	if got := truncateUTF8("héllo", 3); got != "hé" {
+		t.Errorf("truncateUTF8(3) = %q, want %q", got, "hé")
+	}
+	if got := truncateUTF8("abc", 8); got != "abc" {
+		t.Errorf("truncateUTF8 changed a short string: %q", got)
+	}
+}
+
+func TestGetContextForTaskUsesEmbedder(t *testing.T) {
+	db := openSemanticTestDB(t)
+	embedder := &conceptEmbedder{model: "concepts"}
+	if _, err := EmbedCode(db, embedder); err != nil {
+		t.Fatalf("EmbedCode() error = %v", err)
+	}
+
+	hasFile := func(result *ContextResult, path string) bool {
+		for _, f := range result.Files {
+			if f.Path == path {
+				return true
+			}
+		}
+		return false
+	}
+
+	result, err := GetContextForTaskWithOptions(db, "login", 5, nil)
+	if err != nil {
+		t.Fatalf("GetContextForTaskWithOptions() error = %v", err)
+	}
+	if hasFile(result, "auth/check.go") {
+		t.Fatalf("phrase search unexpectedly matched: %+v", result.Files)
+	}
+
+	result, err = GetContextForTaskWithOptions(db, "login", 5, &ContextOptions{Embedder: embedder})
+	if err != nil {
+		t.Fatalf("GetContextForTaskWithOptions() error = %v", err)
+	}
+	if !hasFile(result, "auth/check.go") {
+		t.Errorf("hybrid context files = %+v, want auth/check.go", result.Files)
+	}
+}
+
+func TestReciprocalRankFusion(t *testing.T) {
+	scores := ReciprocalRankFusion([]string{"a", "b", "c"}, []string{"c", "d"})
+	if scores["c"] <= scores["a"] {
+		t.Errorf("item in both lists scored %.4f, below top of one list %.4f", scores["c"], scores["a"])
+	}
+	if want := 1.0 / (RRFK + 1); scores["a"] != want {
+		t.Errorf("score(a) = %f, want %f", scores["a"], want)
+	}
+}
@@ -226,38 +226,55 @@ func GetMostConnectedFiles(db *sql.DB, limit int) ([]FileUsageScore, error) {
 
 // GetSymbolCentrality computes how "central" a symbol is based on call relationships.
 // Symbols with high centrality are called from many places and/or call many other symbols.
+// Calls the resolver linked to a symbol count only for that symbol; unresolved
+// calls are matched by name.
 func GetSymbolCentrality(db *sql.DB, symbolName, filePath string) (float64, error) {
+	ctx := context.Background()
 	var inCount, outCount int
 
-	// Count incoming calls to this symbol
-	err := db.QueryRowContext(context.Background(), `
+	// Find the definition in filePath, if we have one
+	var symbolID int64
+	var startLine, endLine int
+	if filePath != "" {
+		err := db.QueryRowContext(ctx, `
+			SELECT id, line_start, line_end FROM symbols
+			WHERE name = ? AND file_path = ?
+			LIMIT 1
+		`, symbolName, filePath).Scan(&symbolID, &startLine, &endLine)
+		if err != nil {
+			symbolID = 0
+		}
+	}
+
+	// Count incoming calls to this symbol. Without a definition, calls
+	// resolved to any symbol of that name count.
+	resolved := `target_symbol_id = ?`
+	resolvedArg := any(symbolID)
+	if symbolID == 0 {
+		resolved = `target_symbol_id IN (SELECT id FROM symbols WHERE name = ?)`
+		resolvedArg = symbolName
+	}
+	err := db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM relationships
 		WHERE kind = 'call'
-		AND (target_symbol = ? OR target_symbol LIKE ? OR target_symbol LIKE ?)
-	`, symbolName, "%."+symbolName, "%::"+symbolName).Scan(&inCount)
+		AND (`+resolved+`
+		     OR (target_symbol_id IS NULL
+		         AND (target_symbol = ? OR target_symbol LIKE ? OR target_symbol LIKE ?)))
+	`, resolvedArg, symbolName, "%."+symbolName, "%::"+symbolName).Scan(&inCount)
 	if err != nil && err != sql.ErrNoRows {
 		inCount = 0
 	}
 
-	// Count outgoing calls from this symbol (if we have file path)
-	if filePath != "" {
-		// Get symbol's line range
-		var startLine, endLine int
-		err := db.QueryRowContext(context.Background(), `
-			SELECT line_start, line_end FROM symbols
-			WHERE name = ? AND file_path = ?
-			LIMIT 1
-		`, symbolName, filePath).Scan(&startLine, &endLine)
-		if err == nil {
-			// Count calls within that range
-			err = db.QueryRowContext(context.Background(), `
-				SELECT COUNT(*) FROM relationships
-				WHERE kind = 'call' AND source_file = ?
-				AND line >= ? AND line <= ?
-			`, filePath, startLine, endLine).Scan(&outCount)
-			if err != nil && err != sql.ErrNoRows {
-				outCount = 0
-			}
+	// Count outgoing calls from this symbol (if we found its definition)
+	if symbolID != 0 {
+		err = db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM relationships
+			WHERE kind = 'call'
+			AND (source_symbol_id = ?
+			     OR (source_symbol_id IS NULL AND source_file = ? AND line >= ? AND line <= ?))
+		`, symbolID, filePath, startLine, endLine).Scan(&outCount)
+		if err != nil && err != sql.ErrNoRows {
+			outCount = 0
 		}
 	}
 
@@ -0,0 +1,219 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
+)
+
+// codeVectorIndexMinRows is the number of embeddings below which
+// SearchCodeVectors compares the query against every embedding; the HNSW
+// graph only pays off for larger indexes.
+var codeVectorIndexMinRows = 2000
+
+// codeVectorIndexFile is the HNSW graph over code_embeddings, stored next to
+// the index database.
+const codeVectorIndexFile = "code-vectors.hnsw"
+
+// codeVectorIndex is an HNSW graph over the code embeddings of one model,
+// keyed by code_embeddings.id. Embedding rows are never updated in place, so
+// comparing the graph's IDs with the table keeps it exact. The graph is
+// reconciled before a search whenever the table changed and saved after.
+type codeVectorIndex struct {
+	mu    sync.Mutex
+	path  string
+	model string
+	stamp codeEmbeddingsStamp
+	graph *vector.Graph
+}
+
+// codeEmbeddingsStamp cheaply detects changes to a model's embeddings.
+type codeEmbeddingsStamp struct {
+	count int64
+	maxID int64
+}
+
+var (
+	codeVectorIndexesMu sync.Mutex
+	codeVectorIndexes   = make(map[string]*codeVectorIndex) // by graph file path
+)
+
+// loadCodeVectorIndex returns the graph for db's file, loading it from disk
+// on first use. It returns nil for in-memory databases.
+func loadCodeVectorIndex(db *sql.DB) *codeVectorIndex {
+	dbPath := mainDatabaseFile(db)
+	if dbPath == "" {
+		return nil
+	}
+	path := filepath.Join(filepath.Dir(dbPath), codeVectorIndexFile)
+
+	codeVectorIndexesMu.Lock()
+	defer codeVectorIndexesMu.Unlock()
+	if idx := codeVectorIndexes[path]; idx != nil {
+		return idx
+	}
+	idx := &codeVectorIndex{path: path, stamp: codeEmbeddingsStamp{count: -1}}
+	g, err := vector.LoadGraph(path)
+	switch {
+	case err == nil:
+		idx.graph = g
+	case !os.IsNotExist(err):
+		log.Printf("code vector index unreadable, rebuilding: %v", err)
+	}
+	if idx.graph == nil {
+		idx.graph = vector.NewGraph()
+	}
+	codeVectorIndexes[path] = idx
+	return idx
+}
+
+// mainDatabaseFile returns the file backing db's main schema, or "" when it
+// is in memory.
+func mainDatabaseFile(db *sql.DB) string {
+	rows, err := db.QueryContext(context.Background(), `PRAGMA database_list;`)
+	if err != nil {
+		return ""
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return ""
+		}
+		if name == "main" {
+			return file
+		}
+	}
+	return ""
+}
+
+// search returns the IDs of up to k embeddings most similar to query, after
+// bringing the graph in line with the embeddings of model.
+func (v *codeVectorIndex) search(db *sql.DB, query []float32, model string, k int) ([]vector.Match, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.reconcile(db, model); err != nil {
+		return nil, err
+	}
+	return v.graph.Search(query, k), nil
+}
+
+// reconcile adds embeddings of model missing from the graph and removes
+// vectors whose rows were deleted or belong to another model. The graph is
+// saved when it changed. Callers hold v.mu.
+func (v *codeVectorIndex) reconcile(db *sql.DB, model string) error {
+	ctx := context.Background()
+	var stamp codeEmbeddingsStamp
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(MAX(id), 0) FROM code_embeddings WHERE model = ?;`, model).
+		Scan(&stamp.count, &stamp.maxID); err != nil {
+		return fmt.Errorf("query code embeddings: %w", err)
+	}
+	if model == v.model && stamp == v.stamp {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id FROM code_embeddings WHERE model = ?;`, model)
+	if err != nil {
+		return fmt.Errorf("query code embeddings: %w", err)
+	}
+	stored := make(map[string]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		stored[strconv.FormatInt(id, 10)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	changed := false
+	for _, id := range v.graph.IDs() {
+		if !stored[id] {
+			v.graph.Remove(id)
+			changed = true
+		}
+	}
+	missing := 0
+	for id := range stored {
+		if !v.graph.Has(id) {
+			missing++
+		}
+	}
+	if missing > 0 {
+		rows, err := db.QueryContext(ctx, `SELECT id, embedding FROM code_embeddings WHERE model = ?;`, model)
+		if err != nil {
+			return fmt.Errorf("query code embeddings: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			var blob []byte
+			if err := rows.Scan(&id, &blob); err != nil {
+				return err
+			}
+			key := strconv.FormatInt(id, 10)
+			if !v.graph.Has(key) {
+				v.graph.Add(key, vector.Decode(blob))
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	v.model, v.stamp = model, stamp
+	if !changed {
+		return nil
+	}
+	if v.graph.Fragmented() {
+		v.graph = v.graph.Compact()
+	}
+	if err := v.graph.Save(v.path); err != nil {
+		log.Printf("save code vector index: %v", err)
+	}
+	return nil
+}
+
+// codeEmbeddingRows loads the rows with the given IDs, keyed by ID.
+func codeEmbeddingRows(db *sql.DB, ids []string) (map[string]VectorHit, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT id, path, kind, chunk_index, COALESCE(symbol, ''), start_line, end_line
+		FROM code_embeddings
+		WHERE id IN (?`+strings.Repeat(",?", len(ids)-1)+`);
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query code embeddings: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[string]VectorHit, len(ids))
+	for rows.Next() {
+		var id int64
+		var h VectorHit
+		if err := rows.Scan(&id, &h.Path, &h.Kind, &h.ChunkIndex, &h.Symbol, &h.StartLine, &h.EndLine); err != nil {
+			return nil, err
+		}
+		out[strconv.FormatInt(id, 10)] = h
+	}
+	return out, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/patterns"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/validate"
 )
 
@@ -30,6 +31,13 @@ func Run(rootPath string) error {
 		}
 	}
 
+	// Custom detectors are loaded the way a scan loads them, so every file a
+	// scan would skip is reported: schema errors, queries that don't compile,
+	// invalid globs and IDs that are already taken.
+	if _, err := patterns.WorkspaceRegistry(rootPath); err != nil {
+		problems = append(problems, err.Error())
+	}
+
 	profilePath := filepath.Join(rootPath, ".palace", "project-profile.json")
 	if err := validate.JSON(profilePath, "project-profile"); err != nil {
 		problems = append(problems, err.Error())
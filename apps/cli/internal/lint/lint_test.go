@@ -269,4 +269,50 @@ func TestRun(t *testing.T) {
 			t.Error("error should mention palace.jsonc")
 		}
 	})
+
+	t.Run("reports custom detectors a scan would skip", func(t *testing.T) {
+		dir := t.TempDir()
+		detectorsDir := filepath.Join(dir, ".palace", "patterns")
+		if err := os.MkdirAll(detectorsDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		detectors := map[string]string{
+			"ok.jsonc": `{
+				"schemaVersion": "1.0.0",
+				"kind": "palace/pattern-detector",
+				"id": "custom/todo",
+				"name": "TODOs",
+				"category": "documentation",
+				"match": {"regex": "TODO"}
+			}`,
+			// Valid against the schema, but the query doesn't compile.
+			"bad-query.jsonc": `{
+				"schemaVersion": "1.0.0",
+				"kind": "palace/pattern-detector",
+				"id": "custom/bad-query",
+				"name": "Bad query",
+				"category": "documentation",
+				"languages": ["go"],
+				"match": {"query": "(function_declaration"}
+			}`,
+		}
+		for name, content := range detectors {
+			if err := os.WriteFile(filepath.Join(detectorsDir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := Run(dir)
+		if err == nil {
+			t.Fatal("Run() expected error")
+		}
+		errStr := err.Error()
+		if !strings.Contains(errStr, "bad-query.jsonc") {
+			t.Errorf("error should report bad-query.jsonc: %v", err)
+		}
+		if strings.Contains(errStr, "ok.jsonc") {
+			t.Errorf("error should not mention the valid detector: %v", err)
+		}
+	})
 }
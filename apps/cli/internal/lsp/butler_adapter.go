@@ -1,14 +1,21 @@
 package lsp
 
 import (
+	"database/sql"
+	"path/filepath"
+
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
 )
 
-// ButlerAdapter adapts Memory and Contracts to the DiagnosticsProvider interface.
+// ButlerAdapter adapts Memory, Contracts and the code index to the
+// DiagnosticsProvider interface.
 type ButlerAdapter struct {
 	memory    *memory.Memory
 	contracts *contracts.Store
+	index     *sql.DB
+	root      string
 }
 
 // NewButlerAdapter creates a new adapter for the diagnostics provider.
@@ -124,3 +131,41 @@ func (a *ButlerAdapter) GetContractMismatchesForFile(filePath string) ([]Contrac
 
 	return mismatches, nil
 }
+
+// SetIndex enables architecture diagnostics from the code index of the
+// workspace at root. The index stores workspace-relative paths.
+func (a *ButlerAdapter) SetIndex(db *sql.DB, root string) {
+	a.index = db
+	a.root = root
+}
+
+// GetArchitectureViolationsForFile returns the architecture violations the
+// last scan recorded for a file's imports.
+func (a *ButlerAdapter) GetArchitectureViolationsForFile(filePath string) ([]ArchitectureViolation, error) {
+	if a.index == nil {
+		return nil, nil
+	}
+	rel := filePath
+	if r, err := filepath.Rel(a.root, filePath); err == nil {
+		rel = r
+	}
+
+	stored, err := index.GetArchitectureViolations(a.index, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]ArchitectureViolation, 0, len(stored))
+	for i := range stored {
+		v := &stored[i]
+		violations = append(violations, ArchitectureViolation{
+			Rule:       v.Rule,
+			Kind:       v.Kind,
+			Severity:   v.Severity,
+			Message:    v.Message,
+			FilePath:   filePath,
+			Line:       v.Line,
+			TargetFile: v.TargetFile,
+		})
+	}
+	return violations, nil
+}
@@ -14,6 +14,9 @@ type DiagnosticsProvider interface {
 	GetPatternOutliersForFile(filePath string) ([]PatternOutlier, error)
 	// GetContractMismatchesForFile returns contract mismatches for a file.
 	GetContractMismatchesForFile(filePath string) ([]ContractMismatch, error)
+	// GetArchitectureViolationsForFile returns the architecture rule
+	// violations of a file's imports.
+	GetArchitectureViolationsForFile(filePath string) ([]ArchitectureViolation, error)
 }
 
 // PatternOutlier represents a pattern outlier at a specific location.
@@ -44,6 +47,17 @@ type ContractMismatch struct {
 	Line         int
 }
 
+// ArchitectureViolation represents an import that breaks an architecture rule.
+type ArchitectureViolation struct {
+	Rule       string
+	Kind       string
+	Severity   string
+	Message    string
+	FilePath   string
+	Line       int
+	TargetFile string
+}
+
 // SetDiagnosticsProvider sets the diagnostics provider for the server.
 func (s *Server) SetDiagnosticsProvider(provider DiagnosticsProvider) {
 	s.docMu.Lock()
@@ -80,6 +94,16 @@ func (s *Server) computeDiagnostics(doc *TextDocument) []Diagnostic {
 				diagnostics = append(diagnostics, diag)
 			}
 		}
+
+		// Get architecture violations
+		violations, err := s.diagnosticsProvider.GetArchitectureViolationsForFile(filePath)
+		if err != nil {
+			s.logger.Printf("Error getting architecture violations: %v", err)
+		} else {
+			for i := range violations {
+				diagnostics = append(diagnostics, s.architectureViolationToDiagnostic(violations[i]))
+			}
+		}
 	}
 
 	return diagnostics
@@ -160,6 +184,36 @@ func (s *Server) contractMismatchToDiagnostic(mismatch ContractMismatch) Diagnos
 	}
 }
 
+// architectureViolationToDiagnostic converts an architecture violation to an LSP diagnostic.
+func (s *Server) architectureViolationToDiagnostic(v ArchitectureViolation) Diagnostic {
+	// Convert 1-based line number to 0-based
+	line := v.Line - 1
+	if line < 0 {
+		line = 0
+	}
+
+	severity := DiagnosticSeverityError
+	if v.Severity == "warning" {
+		severity = DiagnosticSeverityWarning
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line, Character: 0},
+			End:   Position{Line: line, Character: 0},
+		},
+		Severity: severity,
+		Code:     "architecture/" + v.Kind,
+		Source:   "mind-palace",
+		Message:  v.Message + " (imports " + v.TargetFile + ")",
+		Data: map[string]interface{}{
+			"type":       "architecture",
+			"rule":       v.Rule,
+			"targetFile": v.TargetFile,
+		},
+	}
+}
+
 // URI/Path conversion utilities
 
 // uriToPath converts a file:// URI to a file path.
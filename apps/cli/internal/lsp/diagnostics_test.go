@@ -12,6 +12,7 @@ import (
 type MockDiagnosticsProvider struct {
 	Outliers   map[string][]PatternOutlier
 	Mismatches map[string][]ContractMismatch
+	Violations map[string][]ArchitectureViolation
 }
 
 func (m *MockDiagnosticsProvider) GetPatternOutliersForFile(filePath string) ([]PatternOutlier, error) {
@@ -38,6 +39,10 @@ func (m *MockDiagnosticsProvider) GetContractMismatchesForFile(filePath string)
 	return m.Mismatches[filePath], nil
 }
 
+func (m *MockDiagnosticsProvider) GetArchitectureViolationsForFile(filePath string) ([]ArchitectureViolation, error) {
+	return m.Violations[filepath.ToSlash(filePath)], nil
+}
+
 func TestDiagnosticsWithPatternOutliers(t *testing.T) {
 	var output bytes.Buffer
 	server := NewServerWithIO(strings.NewReader(""), &output)
@@ -151,6 +156,55 @@ func TestDiagnosticsWithContractMismatches(t *testing.T) {
 	}
 }
 
+func TestDiagnosticsWithArchitectureViolations(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServerWithIO(strings.NewReader(""), &output)
+	server.initialized = true
+
+	provider := &MockDiagnosticsProvider{
+		Violations: map[string][]ArchitectureViolation{
+			"/test/domain/user.go": {
+				{
+					Rule:       "domain-is-pure",
+					Kind:       "forbidden",
+					Severity:   "warning",
+					Message:    "domain must not import ui",
+					FilePath:   "/test/domain/user.go",
+					Line:       4,
+					TargetFile: "web/format.ts",
+				},
+			},
+		},
+	}
+	server.SetDiagnosticsProvider(provider)
+
+	doc := &TextDocument{
+		URI:        "file:///test/domain/user.go",
+		LanguageID: "go",
+		Version:    1,
+		Content:    "package domain\n",
+	}
+	server.setDocument(doc)
+
+	diagnostics := server.computeDiagnostics(doc)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	diag := diagnostics[0]
+	if diag.Code != "architecture/forbidden" {
+		t.Errorf("expected code 'architecture/forbidden', got '%v'", diag.Code)
+	}
+	if diag.Range.Start.Line != 3 {
+		t.Errorf("expected line 3 (0-based), got %d", diag.Range.Start.Line)
+	}
+	if diag.Severity != DiagnosticSeverityWarning {
+		t.Errorf("expected warning severity, got %d", diag.Severity)
+	}
+	if diag.Message != "domain must not import ui (imports web/format.ts)" {
+		t.Errorf("unexpected message %q", diag.Message)
+	}
+}
+
 func TestDiagnosticsPublished(t *testing.T) {
 	var output bytes.Buffer
 	server := NewServerWithIO(strings.NewReader(""), &output)
@@ -0,0 +1,158 @@
+package lsp
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+// maxSymbolMatches caps definitions returned for a single name.
+const maxSymbolMatches = 50
+
+// IndexAdapter adapts the code index to the NavigationProvider interface.
+// The index stores workspace-relative paths; the adapter converts them to
+// and from the absolute paths the LSP server works with.
+type IndexAdapter struct {
+	db   *sql.DB
+	root string
+}
+
+// NewIndexAdapter creates a navigation provider for the index at db.
+func NewIndexAdapter(db *sql.DB, root string) *IndexAdapter {
+	return &IndexAdapter{db: db, root: root}
+}
+
+// FindSymbols returns symbol definitions with the exact given name.
+func (a *IndexAdapter) FindSymbols(name string) ([]IndexSymbol, error) {
+	symbols, err := index.GetSymbolsByName(a.db, name, maxSymbolMatches)
+	if err != nil {
+		return nil, err
+	}
+	return a.toIndexSymbols(symbols), nil
+}
+
+// SearchSymbols returns symbols whose name starts with query.
+func (a *IndexAdapter) SearchSymbols(query string, limit int) ([]IndexSymbol, error) {
+	symbols, err := index.SearchSymbolsByName(a.db, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return a.toIndexSymbols(symbols), nil
+}
+
+// FindReferences returns locations that call or reference name.
+func (a *IndexAdapter) FindReferences(name string) ([]IndexReference, error) {
+	refs, err := index.GetReferences(a.db, name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]IndexReference, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, IndexReference{FilePath: a.absPath(ref.FilePath), Line: ref.Line})
+	}
+	return result, nil
+}
+
+// IncomingCalls returns the direct callers of a symbol.
+func (a *IndexAdapter) IncomingCalls(symbol IndexSymbol) ([]IndexCall, error) {
+	chain, err := index.GetCallChainAt(a.db, symbol.Name, a.relPath(symbol.FilePath), symbol.LineStart, "up", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]IndexCall, 0, len(chain.Chains))
+	for _, node := range chain.Chains {
+		// The caller is defined in the file that contains the call site.
+		caller := a.lookupSymbol(node.Symbol, node.FilePath, node.Line)
+		calls = append(calls, IndexCall{Symbol: caller, CallLines: []int{node.Line}})
+	}
+	return calls, nil
+}
+
+// OutgoingCalls returns the direct callees of a symbol.
+func (a *IndexAdapter) OutgoingCalls(symbol IndexSymbol) ([]IndexCall, error) {
+	chain, err := index.GetCallChain(a.db, symbol.Name, a.relPath(symbol.FilePath), "down", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]IndexCall, 0, len(chain.Chains))
+	for _, node := range chain.Chains {
+		callee := a.lookupSymbol(unqualified(node.Symbol), "", 0)
+		if callee.FilePath == "" {
+			// Callee is not in the index (stdlib, third-party); point at the call site.
+			callee = IndexSymbol{
+				Name:      node.Symbol,
+				Kind:      "function",
+				FilePath:  a.absPath(node.FilePath),
+				LineStart: node.Line,
+				LineEnd:   node.Line,
+			}
+		}
+		calls = append(calls, IndexCall{Symbol: callee, CallLines: []int{node.Line}})
+	}
+	return calls, nil
+}
+
+// lookupSymbol finds a symbol definition by name, optionally restricted to a
+// file. If it is not indexed, a placeholder at the fallback line is returned
+// (with an empty FilePath when no file was given).
+func (a *IndexAdapter) lookupSymbol(name, relPath string, fallbackLine int) IndexSymbol {
+	if sym, err := index.GetSymbol(a.db, name, relPath); err == nil {
+		return a.toIndexSymbol(sym)
+	}
+	if relPath == "" {
+		return IndexSymbol{Name: name}
+	}
+	return IndexSymbol{
+		Name:      name,
+		Kind:      "function",
+		FilePath:  a.absPath(relPath),
+		LineStart: fallbackLine,
+		LineEnd:   fallbackLine,
+	}
+}
+
+func (a *IndexAdapter) toIndexSymbols(symbols []index.SymbolInfo) []IndexSymbol {
+	result := make([]IndexSymbol, 0, len(symbols))
+	for i := range symbols {
+		result = append(result, a.toIndexSymbol(&symbols[i]))
+	}
+	return result
+}
+
+func (a *IndexAdapter) toIndexSymbol(sym *index.SymbolInfo) IndexSymbol {
+	return IndexSymbol{
+		Name:      sym.Name,
+		Kind:      sym.Kind,
+		Detail:    sym.Signature,
+		FilePath:  a.absPath(sym.FilePath),
+		LineStart: sym.LineStart,
+		LineEnd:   sym.LineEnd,
+	}
+}
+
+func (a *IndexAdapter) absPath(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	return filepath.Join(a.root, filepath.FromSlash(rel))
+}
+
+func (a *IndexAdapter) relPath(abs string) string {
+	rel, err := filepath.Rel(a.root, abs)
+	if err != nil {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// unqualified strips a receiver or package qualifier: "pkg.Func" -> "Func".
+func unqualified(name string) string {
+	if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -39,6 +39,9 @@ func (s *Server) handleInitialize(req Request) *Response {
 				InterFileDependencies: false,
 				WorkspaceDiagnostics:  false,
 			},
+			ReferencesProvider:      s.navigationProvider != nil,
+			WorkspaceSymbolProvider: s.navigationProvider != nil,
+			CallHierarchyProvider:   s.navigationProvider != nil,
 		},
 		ServerInfo: &ServerInfo{
 			Name:    "mind-palace-lsp",
@@ -0,0 +1,392 @@
+package lsp
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// NavigationProvider provides code-index navigation data to the LSP server.
+// File paths are absolute and line numbers are 1-based.
+type NavigationProvider interface {
+	// FindSymbols returns symbol definitions with the exact given name.
+	FindSymbols(name string) ([]IndexSymbol, error)
+	// SearchSymbols returns symbols whose name starts with query.
+	SearchSymbols(query string, limit int) ([]IndexSymbol, error)
+	// FindReferences returns locations that call or reference name.
+	FindReferences(name string) ([]IndexReference, error)
+	// IncomingCalls returns the direct callers of a symbol.
+	IncomingCalls(symbol IndexSymbol) ([]IndexCall, error)
+	// OutgoingCalls returns the direct callees of a symbol.
+	OutgoingCalls(symbol IndexSymbol) ([]IndexCall, error)
+}
+
+// IndexSymbol is a symbol definition from the code index.
+type IndexSymbol struct {
+	Name      string
+	Kind      string // Index kind: "function", "method", "class", ...
+	Detail    string // Signature, if known
+	FilePath  string
+	LineStart int
+	LineEnd   int
+}
+
+// IndexReference is a location that refers to a symbol.
+type IndexReference struct {
+	FilePath string
+	Line     int
+}
+
+// IndexCall is one side of a call relationship. For incoming calls Symbol is
+// the caller; for outgoing calls it is the callee. CallLines are the lines of
+// the call sites, which are always in the caller's file.
+type IndexCall struct {
+	Symbol    IndexSymbol
+	CallLines []int
+}
+
+// maxWorkspaceSymbols caps workspace/symbol results.
+const maxWorkspaceSymbols = 100
+
+// SetNavigationProvider sets the index-backed navigation provider.
+// It must be called before initialize so the capabilities are advertised.
+func (s *Server) SetNavigationProvider(provider NavigationProvider) {
+	s.docMu.Lock()
+	defer s.docMu.Unlock()
+	s.navigationProvider = provider
+}
+
+// handleReferences handles textDocument/references request.
+func (s *Server) handleReferences(req Request) *Response {
+	var params ReferenceParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponse(req.ID, ErrCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	locations := []Location{}
+	if s.navigationProvider == nil {
+		return s.successResponse(req.ID, locations)
+	}
+
+	lines := newLineCache(s)
+	name := wordAt(lines.line(uriToPath(params.TextDocument.URI), params.Position.Line+1), params.Position.Character)
+	if name == "" {
+		return s.successResponse(req.ID, locations)
+	}
+
+	if params.Context.IncludeDeclaration {
+		defs, _ := s.navigationProvider.FindSymbols(name)
+		for i := range defs {
+			locations = append(locations, Location{
+				URI:   pathToURI(defs[i].FilePath),
+				Range: lines.nameRange(defs[i].FilePath, defs[i].LineStart, name),
+			})
+		}
+	}
+
+	refs, err := s.navigationProvider.FindReferences(name)
+	if err != nil {
+		s.logger.Printf("references for %s: %v", name, err)
+	}
+	for _, ref := range refs {
+		locations = append(locations, Location{
+			URI:   pathToURI(ref.FilePath),
+			Range: lines.nameRange(ref.FilePath, ref.Line, name),
+		})
+	}
+
+	return s.successResponse(req.ID, locations)
+}
+
+// handleWorkspaceSymbol handles workspace/symbol request.
+func (s *Server) handleWorkspaceSymbol(req Request) *Response {
+	var params WorkspaceSymbolParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponse(req.ID, ErrCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	symbols := []SymbolInformation{}
+	if s.navigationProvider == nil {
+		return s.successResponse(req.ID, symbols)
+	}
+
+	found, err := s.navigationProvider.SearchSymbols(params.Query, maxWorkspaceSymbols)
+	if err != nil {
+		s.logger.Printf("workspace symbol %q: %v", params.Query, err)
+	}
+	for i := range found {
+		sym := &found[i]
+		symbols = append(symbols, SymbolInformation{
+			Name: sym.Name,
+			Kind: symbolKindFor(sym.Kind),
+			Location: Location{
+				URI:   pathToURI(sym.FilePath),
+				Range: symbolRange(sym),
+			},
+		})
+	}
+
+	return s.successResponse(req.ID, symbols)
+}
+
+// handlePrepareCallHierarchy handles textDocument/prepareCallHierarchy request.
+func (s *Server) handlePrepareCallHierarchy(req Request) *Response {
+	var params CallHierarchyPrepareParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponse(req.ID, ErrCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	if s.navigationProvider == nil {
+		return s.successResponse(req.ID, nil)
+	}
+
+	filePath := uriToPath(params.TextDocument.URI)
+	lines := newLineCache(s)
+	name := wordAt(lines.line(filePath, params.Position.Line+1), params.Position.Character)
+	if name == "" {
+		return s.successResponse(req.ID, nil)
+	}
+
+	defs, err := s.navigationProvider.FindSymbols(name)
+	if err != nil || len(defs) == 0 {
+		return s.successResponse(req.ID, nil)
+	}
+
+	// If the cursor is on a definition, that is the only candidate.
+	line := params.Position.Line + 1
+	for i := range defs {
+		if defs[i].FilePath == filePath && defs[i].LineStart <= line && line <= defs[i].LineEnd {
+			return s.successResponse(req.ID, []CallHierarchyItem{lines.callHierarchyItem(&defs[i])})
+		}
+	}
+
+	items := make([]CallHierarchyItem, 0, len(defs))
+	for i := range defs {
+		items = append(items, lines.callHierarchyItem(&defs[i]))
+	}
+	return s.successResponse(req.ID, items)
+}
+
+// handleIncomingCalls handles callHierarchy/incomingCalls request.
+func (s *Server) handleIncomingCalls(req Request) *Response {
+	var params CallHierarchyIncomingCallsParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponse(req.ID, ErrCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	result := []CallHierarchyIncomingCall{}
+	if s.navigationProvider == nil {
+		return s.successResponse(req.ID, result)
+	}
+
+	target := itemSymbol(params.Item)
+	calls, err := s.navigationProvider.IncomingCalls(target)
+	if err != nil {
+		s.logger.Printf("incoming calls for %s: %v", target.Name, err)
+	}
+
+	lines := newLineCache(s)
+	for i := range calls {
+		caller := &calls[i].Symbol
+		result = append(result, CallHierarchyIncomingCall{
+			From:       lines.callHierarchyItem(caller),
+			FromRanges: lines.nameRanges(caller.FilePath, calls[i].CallLines, target.Name),
+		})
+	}
+
+	return s.successResponse(req.ID, result)
+}
+
+// handleOutgoingCalls handles callHierarchy/outgoingCalls request.
+func (s *Server) handleOutgoingCalls(req Request) *Response {
+	var params CallHierarchyOutgoingCallsParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponse(req.ID, ErrCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	result := []CallHierarchyOutgoingCall{}
+	if s.navigationProvider == nil {
+		return s.successResponse(req.ID, result)
+	}
+
+	caller := itemSymbol(params.Item)
+	calls, err := s.navigationProvider.OutgoingCalls(caller)
+	if err != nil {
+		s.logger.Printf("outgoing calls for %s: %v", caller.Name, err)
+	}
+
+	lines := newLineCache(s)
+	for i := range calls {
+		callee := &calls[i].Symbol
+		result = append(result, CallHierarchyOutgoingCall{
+			To:         lines.callHierarchyItem(callee),
+			FromRanges: lines.nameRanges(caller.FilePath, calls[i].CallLines, callee.Name),
+		})
+	}
+
+	return s.successResponse(req.ID, result)
+}
+
+// itemSymbol converts a call hierarchy item sent back by the client into an IndexSymbol.
+func itemSymbol(item CallHierarchyItem) IndexSymbol {
+	return IndexSymbol{
+		Name:      item.Name,
+		Detail:    item.Detail,
+		FilePath:  uriToPath(item.URI),
+		LineStart: item.Range.Start.Line + 1,
+		LineEnd:   item.Range.End.Line + 1,
+	}
+}
+
+// symbolRange returns the full line range of a symbol.
+func symbolRange(sym *IndexSymbol) Range {
+	end := sym.LineEnd
+	if end < sym.LineStart {
+		end = sym.LineStart
+	}
+	return Range{
+		Start: Position{Line: sym.LineStart - 1, Character: 0},
+		End:   Position{Line: end - 1, Character: 0},
+	}
+}
+
+// symbolKindFor maps index symbol kinds to LSP symbol kinds.
+func symbolKindFor(kind string) SymbolKind {
+	switch kind {
+	case "class":
+		return SymbolKindClass
+	case "interface":
+		return SymbolKindInterface
+	case "method":
+		return SymbolKindMethod
+	case "variable":
+		return SymbolKindVariable
+	case "constant":
+		return SymbolKindConstant
+	case "type":
+		return SymbolKindStruct
+	case "enum":
+		return SymbolKindEnum
+	case "property":
+		return SymbolKindProperty
+	case "constructor":
+		return SymbolKindConstructor
+	default:
+		return SymbolKindFunction
+	}
+}
+
+// wordAt returns the identifier that contains the given character offset.
+func wordAt(line string, character int) string {
+	runes := []rune(line)
+	if character < 0 || len(runes) == 0 {
+		return ""
+	}
+	if character >= len(runes) {
+		character = len(runes) - 1
+	}
+	// Allow the cursor to sit just after the identifier.
+	if !isIdentRune(runes[character]) && character > 0 && isIdentRune(runes[character-1]) {
+		character--
+	}
+	if !isIdentRune(runes[character]) {
+		return ""
+	}
+
+	start, end := character, character
+	for start > 0 && isIdentRune(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && isIdentRune(runes[end]) {
+		end++
+	}
+	return string(runes[start:end])
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lineCache reads file lines for a single request, preferring open documents
+// over the file on disk.
+type lineCache struct {
+	server *Server
+	files  map[string][]string
+}
+
+func newLineCache(s *Server) *lineCache {
+	return &lineCache{server: s, files: make(map[string][]string)}
+}
+
+// line returns the 1-based line of a file, or "" if it cannot be read.
+func (c *lineCache) line(path string, line int) string {
+	lines, ok := c.files[path]
+	if !ok {
+		if doc := c.server.getDocument(pathToURI(path)); doc != nil {
+			lines = strings.Split(doc.Content, "\n")
+		} else if data, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		c.files[path] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSuffix(lines[line-1], "\r")
+}
+
+// nameRange returns the range of name on a 1-based line, falling back to the
+// whole line when the name does not appear on it.
+func (c *lineCache) nameRange(path string, line int, name string) Range {
+	text := []rune(c.line(path, line))
+	if col := indexIdent(text, []rune(name)); col >= 0 {
+		return Range{
+			Start: Position{Line: line - 1, Character: col},
+			End:   Position{Line: line - 1, Character: col + len([]rune(name))},
+		}
+	}
+	return Range{
+		Start: Position{Line: line - 1, Character: 0},
+		End:   Position{Line: line - 1, Character: len(text)},
+	}
+}
+
+func (c *lineCache) nameRanges(path string, lines []int, name string) []Range {
+	ranges := make([]Range, 0, len(lines))
+	for _, line := range lines {
+		ranges = append(ranges, c.nameRange(path, line, name))
+	}
+	return ranges
+}
+
+func (c *lineCache) callHierarchyItem(sym *IndexSymbol) CallHierarchyItem {
+	return CallHierarchyItem{
+		Name:           sym.Name,
+		Kind:           symbolKindFor(sym.Kind),
+		Detail:         sym.Detail,
+		URI:            pathToURI(sym.FilePath),
+		Range:          symbolRange(sym),
+		SelectionRange: c.nameRange(sym.FilePath, sym.LineStart, sym.Name),
+	}
+}
+
+// indexIdent returns the rune offset of name as a whole identifier in text, or -1.
+func indexIdent(text, name []rune) int {
+	if len(name) == 0 {
+		return -1
+	}
+	for i := 0; i+len(name) <= len(text); i++ {
+		if string(text[i:i+len(name)]) != string(name) {
+			continue
+		}
+		if i > 0 && isIdentRune(text[i-1]) {
+			continue
+		}
+		if end := i + len(name); end < len(text) && isIdentRune(text[end]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
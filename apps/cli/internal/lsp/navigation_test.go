@@ -0,0 +1,180 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+const navTestSource = `package main
+
+func main() {
+	helper()
+}
+
+func helper() {
+	fmt.Println("hi")
+}
+`
+
+// setupNavigationServer creates a workspace with one indexed file and a server
+// using the index adapter.
+func setupNavigationServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(navTestSource), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	db, err := index.Open(filepath.Join(root, "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	now := time.Now().UTC().Format(time.RFC3339)
+	exec := func(query string, args ...any) {
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			t.Fatalf("exec %q failed: %v", query, err)
+		}
+	}
+	exec(`INSERT INTO files(path, hash, size, mod_time, indexed_at, language) VALUES (?, ?, ?, ?, ?, ?);`, "main.go", "h", len(navTestSource), now, now, "go")
+	for _, sym := range []struct {
+		id         int
+		name       string
+		start, end int
+	}{{1, "main", 3, 5}, {2, "helper", 7, 9}} {
+		exec(`INSERT INTO symbols(id, file_path, name, kind, line_start, line_end, signature, exported) VALUES (?, ?, ?, 'function', ?, ?, ?, 0);`,
+			sym.id, "main.go", sym.name, sym.start, sym.end, "func "+sym.name+"()")
+		exec(`INSERT INTO symbols_fts(name, file_path, kind, doc_comment) VALUES (?, ?, 'function', '');`, sym.name, "main.go")
+	}
+	exec(`INSERT INTO relationships(source_file, target_symbol, kind, line) VALUES ('main.go', 'helper', 'call', 4);`)
+	exec(`INSERT INTO relationships(source_file, target_symbol, kind, line) VALUES ('main.go', 'fmt.Println', 'call', 8);`)
+
+	server := NewServerWithIO(strings.NewReader(""), &bytes.Buffer{})
+	server.SetNavigationProvider(NewIndexAdapter(db, root))
+	server.initialized = true
+	return server, root
+}
+
+func callServer(t *testing.T, s *Server, method string, params any) *Response {
+	t.Helper()
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	resp := s.handleMessage(mustJSON(t, Request{JSONRPC: "2.0", ID: 1, Method: method, Params: data}))
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("%s failed: %+v", method, resp)
+	}
+	return resp
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	return data
+}
+
+func TestNavigationCapabilities(t *testing.T) {
+	server, _ := setupNavigationServer(t)
+	resp := server.handleInitialize(Request{ID: 1, Params: mustJSON(t, InitializeParams{})})
+	caps := resp.Result.(InitializeResult).Capabilities
+	if !caps.ReferencesProvider || !caps.WorkspaceSymbolProvider || !caps.CallHierarchyProvider {
+		t.Errorf("navigation capabilities not advertised: %+v", caps)
+	}
+
+	plain := NewServerWithIO(strings.NewReader(""), &bytes.Buffer{})
+	resp = plain.handleInitialize(Request{ID: 1, Params: mustJSON(t, InitializeParams{})})
+	if resp.Result.(InitializeResult).Capabilities.ReferencesProvider {
+		t.Error("references should not be advertised without an index")
+	}
+}
+
+func TestHandleReferences(t *testing.T) {
+	server, root := setupNavigationServer(t)
+	uri := pathToURI(filepath.Join(root, "main.go"))
+
+	resp := callServer(t, server, "textDocument/references", ReferenceParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 6, Character: 7}, // on "helper" in its declaration
+		Context:      ReferenceContext{IncludeDeclaration: true},
+	})
+	locations := resp.Result.([]Location)
+	if len(locations) != 2 {
+		t.Fatalf("expected declaration + 1 reference, got %+v", locations)
+	}
+	call := locations[1]
+	if call.Range.Start.Line != 3 || call.Range.Start.Character != 1 || call.Range.End.Character != 7 {
+		t.Errorf("call site range = %+v, want line 3 chars 1-7", call.Range)
+	}
+}
+
+func TestHandleWorkspaceSymbol(t *testing.T) {
+	server, _ := setupNavigationServer(t)
+
+	resp := callServer(t, server, "workspace/symbol", WorkspaceSymbolParams{Query: "hel"})
+	symbols := resp.Result.([]SymbolInformation)
+	if len(symbols) != 1 || symbols[0].Name != "helper" || symbols[0].Kind != SymbolKindFunction {
+		t.Fatalf("workspace/symbol = %+v", symbols)
+	}
+	if symbols[0].Location.Range.Start.Line != 6 {
+		t.Errorf("symbol line = %d, want 6", symbols[0].Location.Range.Start.Line)
+	}
+}
+
+func TestCallHierarchy(t *testing.T) {
+	server, root := setupNavigationServer(t)
+	uri := pathToURI(filepath.Join(root, "main.go"))
+
+	resp := callServer(t, server, "textDocument/prepareCallHierarchy", CallHierarchyPrepareParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 3, Character: 3}, // on the call to helper
+	})
+	items := resp.Result.([]CallHierarchyItem)
+	if len(items) != 1 || items[0].Name != "helper" || items[0].SelectionRange.Start.Character != 5 {
+		t.Fatalf("prepareCallHierarchy = %+v", items)
+	}
+
+	resp = callServer(t, server, "callHierarchy/incomingCalls", CallHierarchyIncomingCallsParams{Item: items[0]})
+	incoming := resp.Result.([]CallHierarchyIncomingCall)
+	if len(incoming) != 1 || incoming[0].From.Name != "main" || incoming[0].FromRanges[0].Start.Line != 3 {
+		t.Errorf("incomingCalls = %+v", incoming)
+	}
+
+	resp = callServer(t, server, "callHierarchy/outgoingCalls", CallHierarchyOutgoingCallsParams{Item: items[0]})
+	outgoing := resp.Result.([]CallHierarchyOutgoingCall)
+	if len(outgoing) != 1 || outgoing[0].To.Name != "fmt.Println" || outgoing[0].FromRanges[0].Start.Line != 7 {
+		t.Errorf("outgoingCalls = %+v", outgoing)
+	}
+}
+
+func TestWordAt(t *testing.T) {
+	tests := []struct {
+		line string
+		char int
+		want string
+	}{
+		{"\thelper()", 3, "helper"},
+		{"\thelper()", 7, "helper"}, // just after the identifier
+		{"a := b.c_d", 8, "c_d"},
+		{"   ", 1, ""},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := wordAt(tt.line, tt.char); got != tt.want {
+			t.Errorf("wordAt(%q, %d) = %q, want %q", tt.line, tt.char, got, tt.want)
+		}
+	}
+}
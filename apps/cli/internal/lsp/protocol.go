@@ -142,13 +142,16 @@ type ServerInfo struct {
 
 // ServerCapabilities describes server capabilities.
 type ServerCapabilities struct {
-	TextDocumentSync       *TextDocumentSyncOptions `json:"textDocumentSync,omitempty"`
-	HoverProvider          bool                     `json:"hoverProvider,omitempty"`
-	CodeActionProvider     *CodeActionOptions       `json:"codeActionProvider,omitempty"`
-	CodeLensProvider       *CodeLensOptions         `json:"codeLensProvider,omitempty"`
-	DefinitionProvider     bool                     `json:"definitionProvider,omitempty"`
-	DocumentSymbolProvider bool                     `json:"documentSymbolProvider,omitempty"`
-	DiagnosticProvider     *DiagnosticOptions       `json:"diagnosticProvider,omitempty"`
+	TextDocumentSync        *TextDocumentSyncOptions `json:"textDocumentSync,omitempty"`
+	HoverProvider           bool                     `json:"hoverProvider,omitempty"`
+	CodeActionProvider      *CodeActionOptions       `json:"codeActionProvider,omitempty"`
+	CodeLensProvider        *CodeLensOptions         `json:"codeLensProvider,omitempty"`
+	DefinitionProvider      bool                     `json:"definitionProvider,omitempty"`
+	DocumentSymbolProvider  bool                     `json:"documentSymbolProvider,omitempty"`
+	DiagnosticProvider      *DiagnosticOptions       `json:"diagnosticProvider,omitempty"`
+	ReferencesProvider      bool                     `json:"referencesProvider,omitempty"`
+	WorkspaceSymbolProvider bool                     `json:"workspaceSymbolProvider,omitempty"`
+	CallHierarchyProvider   bool                     `json:"callHierarchyProvider,omitempty"`
 }
 
 // TextDocumentSyncOptions describes text document sync options.
@@ -414,6 +417,70 @@ type DocumentSymbol struct {
 	Children       []DocumentSymbol `json:"children,omitempty"`
 }
 
+// ReferenceParams contains the parameters for references request.
+type ReferenceParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	Context      ReferenceContext       `json:"context"`
+}
+
+// ReferenceContext controls which locations a references request returns.
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+// WorkspaceSymbolParams contains the parameters for workspace symbol request.
+type WorkspaceSymbolParams struct {
+	Query string `json:"query"`
+}
+
+// SymbolInformation represents a symbol found by a workspace symbol search.
+type SymbolInformation struct {
+	Name          string     `json:"name"`
+	Kind          SymbolKind `json:"kind"`
+	Location      Location   `json:"location"`
+	ContainerName string     `json:"containerName,omitempty"`
+}
+
+// CallHierarchyPrepareParams contains the parameters for prepare call hierarchy request.
+type CallHierarchyPrepareParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// CallHierarchyItem represents a symbol in a call hierarchy.
+type CallHierarchyItem struct {
+	Name           string     `json:"name"`
+	Kind           SymbolKind `json:"kind"`
+	Detail         string     `json:"detail,omitempty"`
+	URI            string     `json:"uri"`
+	Range          Range      `json:"range"`
+	SelectionRange Range      `json:"selectionRange"`
+	Data           any        `json:"data,omitempty"`
+}
+
+// CallHierarchyIncomingCallsParams contains the parameters for incoming calls request.
+type CallHierarchyIncomingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall represents a caller of a call hierarchy item.
+type CallHierarchyIncomingCall struct {
+	From       CallHierarchyItem `json:"from"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCallsParams contains the parameters for outgoing calls request.
+type CallHierarchyOutgoingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyOutgoingCall represents a callee of a call hierarchy item.
+type CallHierarchyOutgoingCall struct {
+	To         CallHierarchyItem `json:"to"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
 // SymbolKind defines the kind of symbol.
 type SymbolKind int
 
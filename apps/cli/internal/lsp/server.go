@@ -37,6 +37,9 @@ type Server struct {
 	// Diagnostics provider (set via SetDiagnosticsProvider)
 	diagnosticsProvider DiagnosticsProvider
 
+	// Navigation provider backed by the code index (set via SetNavigationProvider)
+	navigationProvider NavigationProvider
+
 	// Performance: debouncing
 	debounceTimers map[string]*time.Timer
 	debounceMu     sync.Mutex
@@ -252,6 +255,16 @@ func (s *Server) handleMessage(msg []byte) *Response {
 		return s.handleDocumentSymbol(req)
 	case "textDocument/diagnostic":
 		return s.handleDiagnostic(req)
+	case "textDocument/references":
+		return s.handleReferences(req)
+	case "workspace/symbol":
+		return s.handleWorkspaceSymbol(req)
+	case "textDocument/prepareCallHierarchy":
+		return s.handlePrepareCallHierarchy(req)
+	case "callHierarchy/incomingCalls":
+		return s.handleIncomingCalls(req)
+	case "callHierarchy/outgoingCalls":
+		return s.handleOutgoingCalls(req)
 
 	// Notifications (no response)
 	case "$/cancelRequest":
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ADRRecord maps a decision to the Architecture Decision Record file it is
+// synced with.
+type ADRRecord struct {
+	DecisionID   string    `json:"decisionId"`
+	Path         string    `json:"path"`   // Relative to the workspace root
+	Number       int       `json:"number"` // ADR number, e.g. 7 for 0007-use-sqlite.md
+	FileHash     string    `json:"fileHash"`
+	DecisionHash string    `json:"decisionHash"`
+	SyncedAt     time.Time `json:"syncedAt"`
+}
+
+// GetADRRecords returns all decision-to-ADR mappings ordered by ADR number.
+func (m *Memory) GetADRRecords() ([]ADRRecord, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT decision_id, path, number, file_hash, decision_hash, synced_at
+		FROM adr_records ORDER BY number
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query adr records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []ADRRecord
+	for rows.Next() {
+		var r ADRRecord
+		var syncedAt string
+		if err := rows.Scan(&r.DecisionID, &r.Path, &r.Number, &r.FileHash, &r.DecisionHash, &syncedAt); err != nil {
+			return nil, fmt.Errorf("scan adr record: %w", err)
+		}
+		r.SyncedAt = parseTimeOrZero(syncedAt)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// SaveADRRecord stores a decision-to-ADR mapping, replacing any mapping for
+// the same decision or path.
+func (m *Memory) SaveADRRecord(r ADRRecord) error {
+	if r.SyncedAt.IsZero() {
+		r.SyncedAt = time.Now().UTC()
+	}
+	_, err := m.db.ExecContext(context.Background(), `
+		INSERT OR REPLACE INTO adr_records (decision_id, path, number, file_hash, decision_hash, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, r.DecisionID, r.Path, r.Number, r.FileHash, r.DecisionHash, r.SyncedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("save adr record: %w", err)
+	}
+	return nil
+}
+
+// DeleteADRRecord removes the ADR mapping for a decision.
+func (m *Memory) DeleteADRRecord(decisionID string) error {
+	_, err := m.db.ExecContext(context.Background(), `DELETE FROM adr_records WHERE decision_id = ?`, decisionID)
+	if err != nil {
+		return fmt.Errorf("delete adr record: %w", err)
+	}
+	return nil
+}
+
+// UpdateDecisionFromADR overwrites the fields an ADR file carries: content,
+// rationale, context, status, authority, outcome and scope. The outcome
+// timestamp only moves when the outcome itself changes.
+func (m *Memory) UpdateDecisionFromADR(dec Decision) error {
+	existing, err := m.GetDecision(dec.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	outcomeAt := existing.OutcomeAt
+	switch {
+	case dec.Outcome == DecisionOutcomeUnknown:
+		outcomeAt = time.Time{}
+	case dec.Outcome != existing.Outcome:
+		outcomeAt = now
+	}
+	outcomeAtStr := ""
+	if !outcomeAt.IsZero() {
+		outcomeAtStr = outcomeAt.Format(time.RFC3339)
+	}
+
+	_, err = m.db.ExecContext(context.Background(), `
+		UPDATE decisions
+		SET content = ?, rationale = ?, context = ?, status = ?, authority = ?, outcome = ?, outcome_note = ?, outcome_at = ?,
+			scope = ?, scope_path = ?, updated_at = ?
+		WHERE id = ?
+	`, dec.Content, dec.Rationale, dec.Context, dec.Status, dec.Authority, dec.Outcome, dec.OutcomeNote, outcomeAtStr,
+		dec.Scope, dec.ScopePath, now.Format(time.RFC3339), dec.ID)
+	if err != nil {
+		return fmt.Errorf("update decision from adr: %w", err)
+	}
+	return nil
+}
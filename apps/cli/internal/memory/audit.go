@@ -20,6 +20,18 @@ const (
 
 	// AuditActionReject is logged when a proposal is rejected.
 	AuditActionReject AuditAction = "reject"
+
+	// AuditActionHandoffCreate is logged when an agent handoff is created.
+	AuditActionHandoffCreate AuditAction = "handoff_create"
+
+	// AuditActionHandoffAccept is logged when a handoff is accepted.
+	AuditActionHandoffAccept AuditAction = "handoff_accept"
+
+	// AuditActionHandoffComplete is logged when a handoff is completed.
+	AuditActionHandoffComplete AuditAction = "handoff_complete"
+
+	// AuditActionHandoffExpire is logged when a pending handoff expires.
+	AuditActionHandoffExpire AuditAction = "handoff_expire"
 )
 
 // AuditActorType represents who performed the action.
@@ -33,6 +45,9 @@ const (
 	// Note: Agents should not be able to perform audited actions in practice,
 	// but this type exists for completeness and debugging.
 	AuditActorAgent AuditActorType = "agent"
+
+	// AuditActorSystem indicates an automatic state change (e.g., expiry).
+	AuditActorSystem AuditActorType = "system"
 )
 
 // AuditLog represents an entry in the audit log.
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConversationImportStatus describes what ImportConversation did.
+type ConversationImportStatus string
+
+// Conversation import outcomes.
+const (
+	ConversationImportAdded     ConversationImportStatus = "added"     // new transcript
+	ConversationImportUpdated   ConversationImportStatus = "updated"   // transcript from the same source grew
+	ConversationImportDuplicate ConversationImportStatus = "duplicate" // identical messages already stored
+)
+
+// DefaultSessionMatchWindow is how far outside a session's lifetime an
+// imported transcript may start or end and still be linked to it.
+const DefaultSessionMatchWindow = 10 * time.Minute
+
+// ConversationContentHash returns a stable hash of a transcript's agent type
+// and messages. Timestamps are ignored so re-exports of the same transcript match.
+func ConversationContentHash(agentType string, messages []Message) string {
+	h := sha256.New()
+	h.Write([]byte(agentType))
+	for _, msg := range messages {
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ConversationImport is the outcome of ImportConversation.
+type ConversationImport struct {
+	ID     string
+	Status ConversationImportStatus
+	// NewMessages are the messages not stored before: all of them for an
+	// added transcript, those past the earlier copy for an updated one, and
+	// none for a duplicate.
+	NewMessages []Message
+}
+
+// ImportConversation stores a transcript imported from an agent's logs.
+// source identifies the transcript (e.g. "claude-code:<session-id>"). A
+// transcript whose messages are already stored is skipped; one whose source
+// was imported before replaces the earlier copy, since agent logs only grow.
+// The earlier copy keeps its session link when c has none.
+func (m *Memory) ImportConversation(c Conversation, source string) (ConversationImport, error) {
+	ctx := context.Background()
+	hash := ConversationContentHash(c.AgentType, c.Messages)
+
+	var existingID string
+	err := m.db.QueryRowContext(ctx, `SELECT id FROM conversations WHERE content_hash = ? LIMIT 1`, hash).Scan(&existingID)
+	if err == nil {
+		return ConversationImport{ID: existingID, Status: ConversationImportDuplicate}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return ConversationImport{}, fmt.Errorf("check duplicate conversation: %w", err)
+	}
+
+	if source != "" {
+		var storedJSON string
+		err = m.db.QueryRowContext(ctx, `SELECT id, messages FROM conversations WHERE source = ? LIMIT 1`, source).
+			Scan(&existingID, &storedJSON)
+		if err == nil {
+			var stored []Message
+			if err := json.Unmarshal([]byte(storedJSON), &stored); err != nil {
+				return ConversationImport{}, fmt.Errorf("parse stored conversation: %w", err)
+			}
+			messagesJSON, err := json.Marshal(c.Messages)
+			if err != nil {
+				return ConversationImport{}, err
+			}
+			_, err = m.db.ExecContext(ctx, `
+				UPDATE conversations SET summary = ?, messages = ?, content_hash = ?,
+					session_id = COALESCE(NULLIF(?, ''), session_id)
+				WHERE id = ?`,
+				c.Summary, string(messagesJSON), hash, c.SessionID, existingID)
+			if err != nil {
+				return ConversationImport{}, fmt.Errorf("update conversation: %w", err)
+			}
+			return ConversationImport{
+				ID:          existingID,
+				Status:      ConversationImportUpdated,
+				NewMessages: c.Messages[sharedMessagePrefix(stored, c.Messages):],
+			}, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return ConversationImport{}, fmt.Errorf("find conversation source: %w", err)
+		}
+	}
+
+	id, err := m.AddConversation(c)
+	if err != nil {
+		return ConversationImport{}, fmt.Errorf("add conversation: %w", err)
+	}
+	if _, err := m.db.ExecContext(ctx, `UPDATE conversations SET source = ?, content_hash = ? WHERE id = ?`,
+		source, hash, id); err != nil {
+		return ConversationImport{}, fmt.Errorf("tag conversation: %w", err)
+	}
+	return ConversationImport{ID: id, Status: ConversationImportAdded, NewMessages: c.Messages}, nil
+}
+
+// sharedMessagePrefix returns how many leading messages a and b have in
+// common, comparing roles and content.
+func sharedMessagePrefix(a, b []Message) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n].Role == b[n].Role && a[n].Content == b[n].Content {
+		n++
+	}
+	return n
+}
+
+// FindSessionForWindow returns the session whose lifetime overlaps the
+// interval [start, end] the most, allowing slack on both sides. Sessions of
+// agentType are preferred. Returns nil when no session overlaps.
+func (m *Memory) FindSessionForWindow(agentType string, start, end time.Time, slack time.Duration) (*Session, error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, nil
+	}
+	lo := start.Add(-slack).UTC().Format(time.RFC3339)
+	hi := end.Add(slack).UTC().Format(time.RFC3339)
+
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT id, agent_type, agent_id, goal, started_at, last_activity, state, summary
+		FROM sessions WHERE started_at <= ? AND last_activity >= ?`, hi, lo)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var best *Session
+	var bestMatch bool
+	var bestOverlap time.Duration
+	for rows.Next() {
+		var s Session
+		var startedAt, lastActivity string
+		if err := rows.Scan(&s.ID, &s.AgentType, &s.AgentID, &s.Goal, &startedAt, &lastActivity, &s.State, &s.Summary); err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		s.StartedAt = parseTimeOrZero(startedAt)
+		s.LastActivity = parseTimeOrZero(lastActivity)
+
+		from, to := s.StartedAt, s.LastActivity
+		if start.After(from) {
+			from = start
+		}
+		if end.Before(to) {
+			to = end
+		}
+		overlap := to.Sub(from)
+		match := s.AgentType == agentType
+		if best == nil || (match && !bestMatch) || (match == bestMatch && overlap > bestOverlap) {
+			best, bestMatch, bestOverlap = &s, match, overlap
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sessions: %w", err)
+	}
+	return best, nil
+}
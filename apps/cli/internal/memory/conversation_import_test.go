@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestImportConversationDedupe(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	conv := Conversation{
+		AgentType: "claude-code",
+		Summary:   "Fix flaky test",
+		Messages: []Message{
+			{Role: "user", Content: "The cache test is flaky", Timestamp: time.Now()},
+			{Role: "assistant", Content: "It depends on map iteration order"},
+		},
+	}
+
+	conv.SessionID = "ses_1"
+	res, err := mem.ImportConversation(conv, "claude-code:abc")
+	if err != nil {
+		t.Fatalf("ImportConversation() error: %v", err)
+	}
+	if res.Status != ConversationImportAdded || len(res.NewMessages) != 2 {
+		t.Errorf("first import = (%s, %d new), want (added, 2 new)", res.Status, len(res.NewMessages))
+	}
+	id := res.ID
+
+	// Same messages with different timestamps are a duplicate, whatever the source.
+	conv.Messages[0].Timestamp = time.Now().Add(time.Hour)
+	res, err = mem.ImportConversation(conv, "claude-code:other")
+	if err != nil {
+		t.Fatalf("ImportConversation() error: %v", err)
+	}
+	if res.Status != ConversationImportDuplicate || res.ID != id || len(res.NewMessages) != 0 {
+		t.Errorf("re-import = %+v, want (%s, duplicate, no new messages)", res, id)
+	}
+
+	// A transcript that grew replaces the copy from the same source. Only the
+	// appended message is new, and the session link survives an import that
+	// matched no session.
+	conv.Messages = append(conv.Messages, Message{Role: "user", Content: "Sort the keys then"})
+	conv.SessionID = ""
+	res, err = mem.ImportConversation(conv, "claude-code:abc")
+	if err != nil {
+		t.Fatalf("ImportConversation() error: %v", err)
+	}
+	if res.Status != ConversationImportUpdated || res.ID != id {
+		t.Errorf("grown import = (%s, %s), want (%s, updated)", res.ID, res.Status, id)
+	}
+	if len(res.NewMessages) != 1 || res.NewMessages[0].Content != "Sort the keys then" {
+		t.Errorf("grown import new messages = %+v, want the appended message", res.NewMessages)
+	}
+	got, err := mem.GetConversation(id)
+	if err != nil {
+		t.Fatalf("GetConversation() error: %v", err)
+	}
+	if len(got.Messages) != 3 {
+		t.Errorf("stored messages = %d, want 3", len(got.Messages))
+	}
+	if got.SessionID != "ses_1" {
+		t.Errorf("session after update = %q, want ses_1", got.SessionID)
+	}
+
+	count, _ := mem.CountConversations()
+	if count != 1 {
+		t.Errorf("CountConversations() = %d, want 1", count)
+	}
+}
+
+func TestFindSessionForWindow(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer mem.Close()
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	setWindow := func(s *Session, start, end time.Time) {
+		t.Helper()
+		_, err := mem.db.ExecContext(context.Background(), `UPDATE sessions SET started_at = ?, last_activity = ? WHERE id = ?`,
+			start.Format(time.RFC3339), end.Format(time.RFC3339), s.ID)
+		if err != nil {
+			t.Fatalf("update session: %v", err)
+		}
+	}
+
+	claude, _ := mem.StartSession("claude-code", "", "refactor")
+	setWindow(claude, base, base.Add(time.Hour))
+	cursor, _ := mem.StartSession("cursor", "", "review")
+	setWindow(cursor, base, base.Add(2*time.Hour))
+
+	tests := []struct {
+		name       string
+		agent      string
+		start, end time.Time
+		want       string
+	}{
+		{"same agent preferred", "claude-code", base.Add(10 * time.Minute), base.Add(20 * time.Minute), claude.ID},
+		{"other agent when only overlap", "codex", base.Add(90 * time.Minute), base.Add(100 * time.Minute), cursor.ID},
+		{"within slack", "cursor", base.Add(-15 * time.Minute), base.Add(-5 * time.Minute), cursor.ID},
+		{"no overlap", "claude-code", base.Add(5 * time.Hour), base.Add(6 * time.Hour), ""},
+		{"unknown time", "claude-code", time.Time{}, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mem.FindSessionForWindow(tt.agent, tt.start, tt.end, DefaultSessionMatchWindow)
+			if err != nil {
+				t.Fatalf("FindSessionForWindow() error: %v", err)
+			}
+			gotID := ""
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("FindSessionForWindow() = %q, want %q", gotID, tt.want)
+			}
+		})
+	}
+}
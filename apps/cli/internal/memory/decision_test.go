@@ -521,12 +521,12 @@ func TestGetSchemaVersion(t *testing.T) {
 	mem, _ := Open(tmpDir)
 	defer mem.Close()
 
-	// After opening, schema version should be 9 (v0-v7 + v8 for patterns + v9 for contracts)
+	// After opening, schema version should be 13 (v0-v7 + v8 for patterns + v9 for contracts + v10 for handoffs + v11 for playbook runs + v12 for conversation imports + v13 for mined commits + v14 for ADR records)
 	version, err := mem.GetSchemaVersion()
 	if err != nil {
 		t.Fatalf("GetSchemaVersion failed: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("Expected schema version 9, got %d", version)
+	if version != 15 {
+		t.Errorf("Expected schema version 15, got %d", version)
 	}
 }
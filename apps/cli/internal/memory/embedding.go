@@ -3,14 +3,15 @@ package memory
 import (
 	"bytes"
 	"context"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
+	"log"
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
 )
 
 // Embedder defines the interface for generating text embeddings.
@@ -23,7 +24,7 @@ type Embedder interface {
 
 // EmbeddingConfig holds configuration for the embedding backend.
 type EmbeddingConfig struct {
-	Backend string `json:"backend"` // "ollama", "openai", or "disabled"
+	Backend string `json:"backend"` // "ollama", "openai", "local", or "disabled"
 	Model   string `json:"model"`   // e.g., "nomic-embed-text", "text-embedding-3-small"
 	URL     string `json:"url"`     // Base URL for the API
 	APIKey  string `json:"apiKey"`  // API key (for OpenAI)
@@ -70,6 +71,10 @@ func NewEmbedder(cfg EmbeddingConfig) (Embedder, error) {
 			apiKey: apiKey,
 			model:  model,
 		}, nil
+	case "local":
+		// Unfitted; callers with a workspace load or fit one instead
+		// (see LoadLocalEmbedder and FitLocalEmbedder).
+		return NewLocalEmbedder(), nil
 	case "disabled", "":
 		return nil, nil // No embedder
 	default:
@@ -206,12 +211,20 @@ func (e *OpenAIEmbedder) Model() string {
 
 // StoreEmbedding stores an embedding for a record.
 func (m *Memory) StoreEmbedding(recordID, recordKind string, embedding []float32, model string) error {
-	blob := float32sToBytes(embedding)
-	_, err := m.db.ExecContext(context.Background(), `
+	blob := vector.Encode(embedding)
+	res, err := m.db.ExecContext(context.Background(), `
 		INSERT OR REPLACE INTO embeddings (record_id, record_kind, embedding, model, created_at)
 		VALUES (?, ?, ?, ?, ?)`,
 		recordID, recordKind, blob, model, time.Now().UTC().Format(time.RFC3339))
-	return err
+	if err != nil {
+		return err
+	}
+	rowid, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	m.updateVectorIndex(recordID, recordKind, embedding, rowid)
+	return nil
 }
 
 // GetEmbedding retrieves the embedding for a record.
@@ -221,13 +234,17 @@ func (m *Memory) GetEmbedding(recordID string) ([]float32, error) {
 	if err != nil {
 		return nil, err
 	}
-	return bytesToFloat32s(blob), nil
+	return vector.Decode(blob), nil
 }
 
 // DeleteEmbedding removes the embedding for a record.
 func (m *Memory) DeleteEmbedding(recordID string) error {
 	_, err := m.db.ExecContext(context.Background(), `DELETE FROM embeddings WHERE record_id = ?`, recordID)
-	return err
+	if err != nil {
+		return err
+	}
+	m.removeFromVectorIndex(recordID)
+	return nil
 }
 
 // GetAllEmbeddings returns all embeddings of a specific kind.
@@ -245,7 +262,7 @@ func (m *Memory) GetAllEmbeddings(recordKind string) (map[string][]float32, erro
 		if err := rows.Scan(&id, &blob); err != nil {
 			return nil, err
 		}
-		embeddings[id] = bytesToFloat32s(blob)
+		embeddings[id] = vector.Decode(blob)
 	}
 
 	return embeddings, nil
@@ -257,22 +274,7 @@ func (m *Memory) GetAllEmbeddings(recordKind string) (map[string][]float32, erro
 
 // CosineSimilarity computes the cosine similarity between two vectors.
 func CosineSimilarity(a, b []float32) float32 {
-	if len(a) != len(b) || len(a) == 0 {
-		return 0
-	}
-
-	var dotProduct, normA, normB float32
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
-	}
-
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-
-	return dotProduct / float32(math.Sqrt(float64(normA))*math.Sqrt(float64(normB)))
+	return vector.Cosine(a, b)
 }
 
 // SimilarityResult represents a record with its similarity score to a query.
@@ -283,7 +285,23 @@ type SimilarityResult struct {
 }
 
 // FindSimilarEmbeddings finds records with embeddings similar to the query.
+// Large stores are searched through the on-disk HNSW index; small stores, or
+// searches without a limit, compare against every embedding.
 func (m *Memory) FindSimilarEmbeddings(queryEmbedding []float32, recordKind string, limit int, minSimilarity float32) ([]SimilarityResult, error) {
+	if limit > 0 {
+		if count, err := m.countEmbeddingsOfKind(recordKind); err == nil && count >= vectorIndexMinRecords {
+			idx, err := m.vectorIndex()
+			if err == nil {
+				return idx.search(queryEmbedding, recordKind, limit, minSimilarity), nil
+			}
+			log.Printf("vector index unavailable, using brute force: %v", err)
+		}
+	}
+	return m.findSimilarBruteForce(queryEmbedding, recordKind, limit, minSimilarity)
+}
+
+// findSimilarBruteForce compares the query against every stored embedding.
+func (m *Memory) findSimilarBruteForce(queryEmbedding []float32, recordKind string, limit int, minSimilarity float32) ([]SimilarityResult, error) {
 	query := `SELECT record_id, record_kind, embedding FROM embeddings`
 	args := []interface{}{}
 
@@ -306,7 +324,7 @@ func (m *Memory) FindSimilarEmbeddings(queryEmbedding []float32, recordKind stri
 			continue
 		}
 
-		embedding := bytesToFloat32s(blob)
+		embedding := vector.Decode(blob)
 		similarity := CosineSimilarity(queryEmbedding, embedding)
 
 		if similarity >= minSimilarity {
@@ -335,42 +353,19 @@ func (m *Memory) FindSimilarEmbeddings(queryEmbedding []float32, recordKind stri
 	return results, nil
 }
 
+// countEmbeddingsOfKind counts embeddings of one kind, or all when kind is empty.
+func (m *Memory) countEmbeddingsOfKind(recordKind string) (int, error) {
+	if recordKind == "" {
+		return m.CountEmbeddings()
+	}
+	var count int
+	err := m.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM embeddings WHERE record_kind = ?`, recordKind).Scan(&count)
+	return count, err
+}
+
 // CountEmbeddings returns the total number of embeddings.
 func (m *Memory) CountEmbeddings() (int, error) {
 	var count int
 	err := m.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM embeddings`).Scan(&count)
 	return count, err
 }
-
-// ============================================================================
-// Helper Functions
-// ============================================================================
-
-// float32sToBytes converts a slice of float32 to bytes for storage.
-func float32sToBytes(floats []float32) []byte {
-	buf := new(bytes.Buffer)
-	for _, f := range floats {
-		binary.Write(buf, binary.LittleEndian, f)
-	}
-	return buf.Bytes()
-}
-
-// bytesToFloat32s converts bytes back to a slice of float32.
-func bytesToFloat32s(data []byte) []float32 {
-	if len(data) == 0 {
-		return nil
-	}
-	// Ensure data length is a multiple of 4 (size of float32)
-	if len(data)%4 != 0 {
-		return nil
-	}
-	floats := make([]float32, len(data)/4)
-	buf := bytes.NewReader(data)
-	for i := range floats {
-		if err := binary.Read(buf, binary.LittleEndian, &floats[i]); err != nil {
-			// Return partial results on error (shouldn't happen with valid data)
-			return floats[:i]
-		}
-	}
-	return floats
-}
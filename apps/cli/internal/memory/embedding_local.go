@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
+)
+
+// Local embedder parameters. Features are hashed into localEmbeddingDims
+// signed buckets; document frequencies are counted in localIDFBuckets.
+const (
+	localEmbeddingDims    = 512
+	localIDFBuckets       = 1 << 18
+	localEmbedderVersion  = 1
+	localEmbedderFileName = "local-embedder.gob"
+
+	// localRefitGrowth is how much larger the corpus must grow, relative to
+	// the fitted document count, before the embedder is refitted.
+	localRefitGrowth = 1.5
+)
+
+// Relative weights of the feature types. Words carry most of the signal;
+// bigrams reward matching phrases and character trigrams let related word
+// forms ("auth", "authentication") overlap.
+const (
+	localWordWeight    = 1.0
+	localBigramWeight  = 0.5
+	localTrigramWeight = 0.25
+)
+
+// ============================================================================
+// Local Embedder (in-process, no network)
+// ============================================================================
+
+// LocalEmbedder generates embeddings in-process by projecting TF-IDF weighted
+// word, bigram and character trigram features onto a fixed number of
+// dimensions with signed feature hashing. Document frequencies are fitted on
+// the workspace's own text; an unfitted embedder weights all features equally.
+// Each fit has its own model name, so vectors from an earlier fit are
+// recognised as stale.
+type LocalEmbedder struct {
+	mu          sync.RWMutex
+	docs        int
+	df          []uint32
+	fingerprint string // Cached hash of docs and df; empty until computed
+}
+
+// NewLocalEmbedder creates an unfitted local embedder.
+func NewLocalEmbedder() *LocalEmbedder {
+	return &LocalEmbedder{df: make([]uint32, localIDFBuckets)}
+}
+
+// LocalEmbedderPath returns where the fitted local embedder is stored.
+func LocalEmbedderPath(root string) string {
+	return filepath.Join(root, ".palace", vectorIndexDir, localEmbedderFileName)
+}
+
+// Fit adds documents to the embedder's document frequencies.
+func (e *LocalEmbedder) Fit(docs ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, doc := range docs {
+		seen := make(map[uint32]bool)
+		for h := range localFeatures(doc) {
+			b := idfBucket(h)
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+			if e.df[b] < math.MaxUint32 {
+				e.df[b]++
+			}
+		}
+		e.docs++
+	}
+	e.fingerprint = ""
+}
+
+// Documents returns the number of documents the embedder was fitted on.
+func (e *LocalEmbedder) Documents() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.docs
+}
+
+// NeedsRefit reports whether a corpus of the given size has grown enough
+// since the embedder was fitted that its document frequencies are outdated.
+func (e *LocalEmbedder) NeedsRefit(corpus int) bool {
+	docs := e.Documents()
+	if docs == 0 {
+		return corpus > 0
+	}
+	return float64(corpus) >= float64(docs)*localRefitGrowth
+}
+
+// Embed generates an embedding for text.
+func (e *LocalEmbedder) Embed(text string) ([]float32, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	vec := make([]float32, localEmbeddingDims)
+	for h, f := range localFeatures(text) {
+		w := (1 + math.Log(f.count)) * f.weight * e.idf(h)
+		if h>>63 == 1 {
+			w = -w
+		}
+		vec[h%localEmbeddingDims] += float32(w)
+	}
+	if n := vector.Normalize(vec); n != nil {
+		return n, nil
+	}
+	return vec, nil
+}
+
+// Model returns the local model name. A fitted embedder's name ends with a
+// fingerprint of its document frequencies.
+func (e *LocalEmbedder) Model() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.docs == 0 {
+		return fmt.Sprintf("local-hash-%d", localEmbeddingDims)
+	}
+	if e.fingerprint == "" {
+		h := fnv.New64a()
+		_ = binary.Write(h, binary.LittleEndian, uint64(e.docs))
+		_ = binary.Write(h, binary.LittleEndian, e.df)
+		e.fingerprint = fmt.Sprintf("%08x", h.Sum64()>>32)
+	}
+	return fmt.Sprintf("local-hash-%d-%s", localEmbeddingDims, e.fingerprint)
+}
+
+// idf returns the smoothed inverse document frequency of a feature.
+func (e *LocalEmbedder) idf(h uint64) float64 {
+	if e.docs == 0 {
+		return 1
+	}
+	return math.Log(float64(e.docs+1)/float64(e.df[idfBucket(h)]+1)) + 1
+}
+
+// localEmbedderFile is the on-disk form of a fitted embedder.
+type localEmbedderFile struct {
+	Version int
+	Dims    int
+	Docs    int
+	DF      []uint32
+}
+
+// Save writes the fitted embedder to path atomically.
+func (e *LocalEmbedder) Save(path string) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	data := localEmbedderFile{Version: localEmbedderVersion, Dims: localEmbeddingDims, Docs: e.docs, DF: e.df}
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("encode local embedder: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadLocalEmbedder reads an embedder written by Save.
+func LoadLocalEmbedder(path string) (*LocalEmbedder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var data localEmbedderFile
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode local embedder: %w", err)
+	}
+	if data.Version != localEmbedderVersion || data.Dims != localEmbeddingDims || len(data.DF) != localIDFBuckets {
+		return nil, fmt.Errorf("unsupported local embedder version %d", data.Version)
+	}
+	return &LocalEmbedder{docs: data.Docs, df: data.DF}, nil
+}
+
+// LocalCorpusSize returns the number of records FitLocalEmbedder fits on.
+func (m *Memory) LocalCorpusSize() (int, error) {
+	var n int
+	err := m.db.QueryRowContext(context.Background(), `
+		SELECT (SELECT COUNT(*) FROM ideas) + (SELECT COUNT(*) FROM decisions) + (SELECT COUNT(*) FROM learnings)`).Scan(&n)
+	return n, err
+}
+
+// FitLocalEmbedder fits e on the content of all ideas, decisions and learnings.
+func (m *Memory) FitLocalEmbedder(e *LocalEmbedder) error {
+	for _, table := range []string{"ideas", "decisions", "learnings"} {
+		rows, err := m.db.QueryContext(context.Background(), `SELECT content FROM `+table)
+		if err != nil {
+			return fmt.Errorf("query %s: %w", table, err)
+		}
+		for rows.Next() {
+			var content string
+			if err := rows.Scan(&content); err != nil {
+				rows.Close()
+				return err
+			}
+			e.Fit(content)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localFeature is a hashed feature's weighted count within a text.
+type localFeature struct {
+	count  float64
+	weight float64
+}
+
+// localFeatures extracts hashed word, bigram and character trigram features.
+func localFeatures(text string) map[uint64]*localFeature {
+	features := make(map[uint64]*localFeature)
+	add := func(kind byte, s string, weight float64) {
+		h := featureHash(kind, s)
+		if f := features[h]; f != nil {
+			f.count++
+			return
+		}
+		features[h] = &localFeature{count: 1, weight: weight}
+	}
+
+	words := localTokens(text)
+	for i, w := range words {
+		add('w', w, localWordWeight)
+		if i > 0 {
+			add('b', words[i-1]+" "+w, localBigramWeight)
+		}
+		padded := []rune("<" + w + ">")
+		for j := 0; j+3 <= len(padded); j++ {
+			add('c', string(padded[j:j+3]), localTrigramWeight)
+		}
+	}
+	return features
+}
+
+// localTokens lowercases text and splits it into words, breaking identifiers
+// at camelCase and snake_case boundaries. Single characters are dropped.
+func localTokens(text string) []string {
+	var tokens []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 1 {
+			tokens = append(tokens, strings.ToLower(string(cur)))
+		}
+		cur = cur[:0]
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return tokens
+}
+
+func featureHash(kind byte, s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte{kind})
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// idfBucket maps a feature to its document frequency counter, using hash bits
+// independent of the ones that pick the embedding dimension and sign.
+func idfBucket(h uint64) uint32 {
+	return uint32(h>>32) % localIDFBuckets
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLocalTokens(t *testing.T) {
+	got := localTokens("parseHTTPRequest reads user_id, a JSON body")
+	want := []string{"parse", "http", "request", "reads", "user", "id", "json", "body"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("localTokens() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalEmbedderSimilarity(t *testing.T) {
+	e := NewLocalEmbedder()
+	e.Fit(
+		"Use JWT tokens for authentication in the API gateway",
+		"Store sessions in PostgreSQL with a connection pool",
+		"Cache rendered pages in Redis for five minutes",
+		"The API returns JSON errors with a status code",
+	)
+
+	embed := func(text string) []float32 {
+		v, err := e.Embed(text)
+		if err != nil {
+			t.Fatalf("Embed(%q) error = %v", text, err)
+		}
+		if len(v) != localEmbeddingDims {
+			t.Fatalf("len(Embed(%q)) = %d, want %d", text, len(v), localEmbeddingDims)
+		}
+		return v
+	}
+
+	query := embed("authenticate API requests with JWT")
+	related := CosineSimilarity(query, embed("JWT authentication for the gateway"))
+	unrelated := CosineSimilarity(query, embed("Redis page cache expiry"))
+	if related <= unrelated {
+		t.Errorf("related similarity %.3f <= unrelated %.3f", related, unrelated)
+	}
+
+	// Embeddings are deterministic.
+	if !reflect.DeepEqual(query, embed("authenticate API requests with JWT")) {
+		t.Error("Embed() is not deterministic")
+	}
+}
+
+func TestLocalEmbedderIDF(t *testing.T) {
+	e := NewLocalEmbedder()
+	for range 20 {
+		e.Fit("the service handles requests")
+	}
+	e.Fit("the retry budget")
+
+	common := e.idf(featureHash('w', "the"))
+	rare := e.idf(featureHash('w', "retry"))
+	if rare <= common {
+		t.Errorf("idf(retry) = %.3f, want > idf(the) = %.3f", rare, common)
+	}
+}
+
+func TestLocalEmbedderSaveLoad(t *testing.T) {
+	e := NewLocalEmbedder()
+	e.Fit("database migrations run on startup", "migrations are versioned")
+
+	path := filepath.Join(t.TempDir(), "vectors", localEmbedderFileName)
+	if err := e.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadLocalEmbedder(path)
+	if err != nil {
+		t.Fatalf("LoadLocalEmbedder() error = %v", err)
+	}
+	if loaded.Documents() != 2 {
+		t.Errorf("Documents() = %d, want 2", loaded.Documents())
+	}
+
+	a, _ := e.Embed("run migrations")
+	b, _ := loaded.Embed("run migrations")
+	if !reflect.DeepEqual(a, b) {
+		t.Error("embedding differs after reload")
+	}
+}
+
+func TestLocalEmbedderModelFingerprint(t *testing.T) {
+	e := NewLocalEmbedder()
+	unfitted := e.Model()
+	if !e.NeedsRefit(1) || e.NeedsRefit(0) {
+		t.Errorf("unfitted NeedsRefit(1) = %v, NeedsRefit(0) = %v", e.NeedsRefit(1), e.NeedsRefit(0))
+	}
+
+	e.Fit("database migrations run on startup", "migrations are versioned")
+	fitted := e.Model()
+	if fitted == unfitted {
+		t.Fatalf("fitted model name %q equals unfitted", fitted)
+	}
+	if e.NeedsRefit(2) || !e.NeedsRefit(3) {
+		t.Errorf("NeedsRefit(2) = %v, NeedsRefit(3) = %v, want false, true", e.NeedsRefit(2), e.NeedsRefit(3))
+	}
+
+	path := filepath.Join(t.TempDir(), localEmbedderFileName)
+	if err := e.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadLocalEmbedder(path)
+	if err != nil {
+		t.Fatalf("LoadLocalEmbedder() error = %v", err)
+	}
+	if loaded.Model() != fitted {
+		t.Errorf("reloaded model = %q, want %q", loaded.Model(), fitted)
+	}
+
+	e.Fit("a third document")
+	if e.Model() == fitted {
+		t.Errorf("model name unchanged after further fitting: %q", fitted)
+	}
+}
+
+func TestGetRecordsWithoutEmbeddingsChecksModel(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer mem.Close()
+
+	for _, id := range []string{"l_current", "l_old", "l_none"} {
+		if _, err := mem.AddLearning(Learning{ID: id, Content: id, Scope: "palace"}); err != nil {
+			t.Fatalf("AddLearning() error = %v", err)
+		}
+	}
+	vec := []float32{1, 0}
+	if err := mem.StoreEmbedding("l_current", "learning", vec, "local-hash-512-b"); err != nil {
+		t.Fatalf("StoreEmbedding() error = %v", err)
+	}
+	if err := mem.StoreEmbedding("l_old", "learning", vec, "local-hash-512-a"); err != nil {
+		t.Fatalf("StoreEmbedding() error = %v", err)
+	}
+
+	records, err := mem.GetRecordsWithoutEmbeddings("learning", "local-hash-512-b", 10)
+	if err != nil {
+		t.Fatalf("GetRecordsWithoutEmbeddings() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, r := range records {
+		got[r.ID] = true
+	}
+	if len(got) != 2 || !got["l_old"] || !got["l_none"] {
+		t.Errorf("pending = %v, want l_old and l_none", got)
+	}
+}
+
+func TestLocalEmbedderSemanticSearch(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer mem.Close()
+
+	embedder, err := NewEmbedder(EmbeddingConfig{Backend: "local"})
+	if err != nil {
+		t.Fatalf("NewEmbedder() error = %v", err)
+	}
+	local := embedder.(*LocalEmbedder)
+
+	records := map[string]string{
+		"l_auth":  "Validate JWT tokens before handling authenticated requests",
+		"l_db":    "Wrap PostgreSQL migrations in a transaction",
+		"l_cache": "Invalidate the Redis cache when settings change",
+	}
+	for id, content := range records {
+		if _, err := mem.AddLearning(Learning{ID: id, Content: content, Scope: "palace"}); err != nil {
+			t.Fatalf("AddLearning() error = %v", err)
+		}
+	}
+	if err := mem.FitLocalEmbedder(local); err != nil {
+		t.Fatalf("FitLocalEmbedder() error = %v", err)
+	}
+	if local.Documents() != len(records) {
+		t.Errorf("Documents() = %d, want %d", local.Documents(), len(records))
+	}
+	for id, content := range records {
+		emb, _ := local.Embed(content)
+		if err := mem.StoreEmbedding(id, "learning", emb, local.Model()); err != nil {
+			t.Fatalf("StoreEmbedding() error = %v", err)
+		}
+	}
+
+	opts := DefaultSemanticSearchOptions()
+	opts.MinSimilarity = 0.05
+	results, err := mem.SemanticSearch(local, "JWT token validation", opts)
+	if err != nil {
+		t.Fatalf("SemanticSearch() error = %v", err)
+	}
+	if len(results) == 0 || results[0].ID != "l_auth" {
+		t.Errorf("SemanticSearch() = %+v, want l_auth first", results)
+	}
+}
@@ -262,32 +262,3 @@ func TestCountEmbeddings(t *testing.T) {
 		t.Errorf("Expected 2 embeddings, got %d", count)
 	}
 }
-
-func TestFloat32Conversion(t *testing.T) {
-	original := []float32{0.123456, -0.789012, 1.234567, -2.345678}
-
-	bytes := float32sToBytes(original)
-	recovered := bytesToFloat32s(bytes)
-
-	if len(recovered) != len(original) {
-		t.Fatalf("Length mismatch: %d vs %d", len(recovered), len(original))
-	}
-
-	for i := range original {
-		if math.Abs(float64(original[i]-recovered[i])) > 0.000001 {
-			t.Errorf("Index %d: expected %f, got %f", i, original[i], recovered[i])
-		}
-	}
-}
-
-func TestBytesToFloat32sEmpty(t *testing.T) {
-	result := bytesToFloat32s(nil)
-	if result != nil {
-		t.Error("Expected nil for empty input")
-	}
-
-	result = bytesToFloat32s([]byte{})
-	if result != nil {
-		t.Error("Expected nil for empty slice")
-	}
-}
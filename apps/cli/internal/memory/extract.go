@@ -195,9 +195,9 @@ func (e *LLMExtractor) ExtractFromConversation(conv Conversation) ([]string, err
 		}
 	}
 
-	// Update conversation with extracted record IDs
+	// Update conversation with extracted record IDs, keeping earlier ones
 	if len(recordIDs) > 0 && conv.ID != "" {
-		_ = e.memory.UpdateConversationExtracted(conv.ID, recordIDs)
+		_ = e.memory.UpdateConversationExtracted(conv.ID, append(conv.Extracted, recordIDs...))
 	}
 
 	return recordIDs, nil
@@ -0,0 +1,353 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HandoffStatus constants
+const (
+	HandoffStatusPending   = "pending"
+	HandoffStatusAccepted  = "accepted"
+	HandoffStatusCompleted = "completed"
+	HandoffStatusExpired   = "expired"
+)
+
+// DefaultHandoffTTL is how long a handoff waits to be picked up.
+const DefaultHandoffTTL = 24 * time.Hour
+
+// ErrHandoffNotFound is returned when a handoff ID does not exist.
+var ErrHandoffNotFound = errors.New("handoff not found")
+
+// Handoff represents a task handoff between agents.
+type Handoff struct {
+	ID            string    `json:"id"`                    // Prefix: "hoff_"
+	FromAgent     string    `json:"fromAgent"`             // Agent creating the handoff
+	FromSessionID string    `json:"fromSessionId"`         // Session creating the handoff
+	ToAgentType   string    `json:"toAgentType"`           // Target agent type (or "any")
+	Task          string    `json:"task"`                  // Task description
+	Context       string    `json:"context"`               // Relevant context
+	PendingWork   []string  `json:"pendingWork"`           // List of pending items
+	PinnedRecords []string  `json:"pinnedRecords"`         // Record IDs to include
+	Priority      string    `json:"priority"`              // "low", "normal", "high", "urgent"
+	Status        string    `json:"status"`                // "pending", "accepted", "completed", "expired"
+	AcceptedBy    string    `json:"acceptedBy"`            // Session (or human) that accepted
+	Summary       string    `json:"summary"`               // Completion summary
+	CreatedAt     time.Time `json:"createdAt"`             // When the handoff was created
+	ExpiresAt     time.Time `json:"expiresAt"`             // Pending handoffs expire after this
+	AcceptedAt    time.Time `json:"acceptedAt,omitempty"`  // When the handoff was accepted
+	CompletedAt   time.Time `json:"completedAt,omitempty"` // When the handoff was completed
+}
+
+// HandoffActor identifies who changed a handoff, for the audit log.
+type HandoffActor struct {
+	Type AuditActorType
+	ID   string // Session ID for agents, username for humans
+}
+
+// handoffPriorityRank ranks priorities for sorting (lower is more urgent).
+// Unknown priorities rank as normal.
+const handoffPriorityRank = `CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'low' THEN 3 ELSE 2 END`
+
+const handoffColumns = `id, from_agent, from_session_id, to_agent_type, task, context, pending_work, pinned_records,
+	priority, status, accepted_by, summary, created_at, expires_at, accepted_at, completed_at`
+
+// CreateHandoff stores a new pending handoff and records it in the audit log.
+func (m *Memory) CreateHandoff(h Handoff, actor HandoffActor) (*Handoff, error) {
+	if h.Task == "" {
+		return nil, fmt.Errorf("task is required")
+	}
+	if h.ID == "" {
+		h.ID = generateID("hoff")
+	}
+	if h.ToAgentType == "" {
+		h.ToAgentType = "any"
+	}
+	if h.Priority == "" {
+		h.Priority = "normal"
+	}
+	if h.FromAgent == "" {
+		h.FromAgent = "unknown"
+	}
+	h.Status = HandoffStatusPending
+	now := time.Now().UTC()
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = now
+	}
+	if h.ExpiresAt.IsZero() {
+		h.ExpiresAt = h.CreatedAt.Add(DefaultHandoffTTL)
+	}
+
+	pendingJSON, err := json.Marshal(nonNilStrings(h.PendingWork))
+	if err != nil {
+		return nil, fmt.Errorf("marshal pending work: %w", err)
+	}
+	pinnedJSON, err := json.Marshal(nonNilStrings(h.PinnedRecords))
+	if err != nil {
+		return nil, fmt.Errorf("marshal pinned records: %w", err)
+	}
+
+	_, err = m.db.ExecContext(context.Background(), `
+		INSERT INTO handoffs (id, from_agent, from_session_id, to_agent_type, task, context, pending_work, pinned_records,
+			priority, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, h.ID, h.FromAgent, h.FromSessionID, h.ToAgentType, h.Task, h.Context, string(pendingJSON), string(pinnedJSON),
+		h.Priority, h.Status, h.CreatedAt.UTC().Format(time.RFC3339), h.ExpiresAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("insert handoff: %w", err)
+	}
+
+	m.auditHandoff(AuditActionHandoffCreate, h.ID, actor, map[string]any{
+		"to":       h.ToAgentType,
+		"priority": h.Priority,
+		"expires":  h.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+
+	return &h, nil
+}
+
+// GetHandoff retrieves a handoff by ID. Pending handoffs past their expiry are
+// marked expired before being returned.
+func (m *Memory) GetHandoff(id string) (*Handoff, error) {
+	if _, err := m.ExpireHandoffs(); err != nil {
+		return nil, err
+	}
+
+	row := m.db.QueryRowContext(context.Background(), `SELECT `+handoffColumns+` FROM handoffs WHERE id = ?`, id)
+	h, err := scanHandoff(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrHandoffNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get handoff: %w", err)
+	}
+	return h, nil
+}
+
+// GetHandoffs lists handoffs, optionally filtered by status and target agent type.
+// An agentType filter matches handoffs addressed to that type or to "any".
+// Results are sorted by priority, then newest first.
+func (m *Memory) GetHandoffs(status, agentType string, limit int) ([]Handoff, error) {
+	if _, err := m.ExpireHandoffs(); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + handoffColumns + ` FROM handoffs WHERE 1=1`
+	args := []any{}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if agentType != "" {
+		query += ` AND (to_agent_type = 'any' OR to_agent_type = ?)`
+		args = append(args, agentType)
+	}
+	// Sort before LIMIT so an older urgent handoff is not cut off by newer ones.
+	query += ` ORDER BY ` + handoffPriorityRank + `, created_at DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query handoffs: %w", err)
+	}
+	defer rows.Close()
+
+	var handoffs []Handoff
+	for rows.Next() {
+		h, err := scanHandoff(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan handoff: %w", err)
+		}
+		handoffs = append(handoffs, *h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate handoffs: %w", err)
+	}
+	return handoffs, nil
+}
+
+// GetPendingHandoffsForAgent returns pending, unexpired handoffs for the given agent type.
+func (m *Memory) GetPendingHandoffsForAgent(agentType string) ([]Handoff, error) {
+	return m.GetHandoffs(HandoffStatusPending, agentType, 0)
+}
+
+// GetAcceptedHandoffForSession returns the handoff currently accepted by a session, or nil.
+func (m *Memory) GetAcceptedHandoffForSession(sessionID string) (*Handoff, error) {
+	if sessionID == "" {
+		return nil, nil
+	}
+	row := m.db.QueryRowContext(context.Background(), `
+		SELECT `+handoffColumns+` FROM handoffs
+		WHERE accepted_by = ? AND status = ?
+		ORDER BY accepted_at DESC LIMIT 1
+	`, sessionID, HandoffStatusAccepted)
+	h, err := scanHandoff(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get accepted handoff: %w", err)
+	}
+	return h, nil
+}
+
+// AcceptHandoff claims a pending handoff. The update is conditional so only one
+// agent (in any process) can accept a given handoff; expired handoffs cannot be accepted.
+func (m *Memory) AcceptHandoff(id, acceptedBy string, actor HandoffActor) (*Handoff, error) {
+	h, err := m.GetHandoff(id)
+	if err != nil {
+		return nil, err
+	}
+	if h.Status != HandoffStatusPending {
+		return nil, fmt.Errorf("handoff %s is already %s", id, h.Status)
+	}
+
+	now := time.Now().UTC()
+	result, err := m.db.ExecContext(context.Background(), `
+		UPDATE handoffs SET status = ?, accepted_by = ?, accepted_at = ?
+		WHERE id = ? AND status = ? AND expires_at > ?
+	`, HandoffStatusAccepted, acceptedBy, now.Format(time.RFC3339), id, HandoffStatusPending, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("accept handoff: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("handoff %s was accepted by someone else or has expired", id)
+	}
+
+	m.auditHandoff(AuditActionHandoffAccept, id, actor, map[string]any{"acceptedBy": acceptedBy})
+
+	h.Status = HandoffStatusAccepted
+	h.AcceptedBy = acceptedBy
+	h.AcceptedAt = now
+	return h, nil
+}
+
+// CompleteHandoff marks a pending or accepted handoff as completed.
+func (m *Memory) CompleteHandoff(id, summary string, actor HandoffActor) (*Handoff, error) {
+	h, err := m.GetHandoff(id)
+	if err != nil {
+		return nil, err
+	}
+	if h.Status != HandoffStatusPending && h.Status != HandoffStatusAccepted {
+		return nil, fmt.Errorf("handoff %s is already %s", id, h.Status)
+	}
+
+	now := time.Now().UTC()
+	result, err := m.db.ExecContext(context.Background(), `
+		UPDATE handoffs SET status = ?, summary = ?, completed_at = ?
+		WHERE id = ? AND status IN (?, ?)
+	`, HandoffStatusCompleted, summary, now.Format(time.RFC3339), id, HandoffStatusPending, HandoffStatusAccepted)
+	if err != nil {
+		return nil, fmt.Errorf("complete handoff: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("handoff %s changed state concurrently", id)
+	}
+
+	m.auditHandoff(AuditActionHandoffComplete, id, actor, map[string]any{"summary": summary})
+
+	h.Status = HandoffStatusCompleted
+	h.Summary = summary
+	h.CompletedAt = now
+	return h, nil
+}
+
+// ExpireHandoffs marks pending handoffs past their expiry as expired and
+// writes an audit entry for each. Returns the number of expired handoffs.
+func (m *Memory) ExpireHandoffs() (int, error) {
+	ctx := context.Background()
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	rows, err := m.db.QueryContext(ctx, `SELECT id FROM handoffs WHERE status = ? AND expires_at <= ?`, HandoffStatusPending, now)
+	if err != nil {
+		return 0, fmt.Errorf("query expired handoffs: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan handoff id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate expired handoffs: %w", err)
+	}
+
+	expired := 0
+	for _, id := range ids {
+		result, err := m.db.ExecContext(ctx, `UPDATE handoffs SET status = ? WHERE id = ? AND status = ?`,
+			HandoffStatusExpired, id, HandoffStatusPending)
+		if err != nil {
+			return expired, fmt.Errorf("expire handoff: %w", err)
+		}
+		// Another process may have expired (or accepted) it first; only audit our own change.
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+		expired++
+		m.auditHandoff(AuditActionHandoffExpire, id, HandoffActor{Type: AuditActorSystem}, nil)
+	}
+	return expired, nil
+}
+
+// auditHandoff writes an audit log entry for a handoff state change.
+// Audit failures are not fatal to the handoff operation.
+func (m *Memory) auditHandoff(action AuditAction, id string, actor HandoffActor, details map[string]any) {
+	detailsJSON := "{}"
+	if len(details) > 0 {
+		if data, err := json.Marshal(details); err == nil {
+			detailsJSON = string(data)
+		}
+	}
+	actorType := actor.Type
+	if actorType == "" {
+		actorType = AuditActorAgent
+	}
+	_, _ = m.AddAuditLog(AuditLogEntry{
+		Action:     action,
+		ActorType:  actorType,
+		ActorID:    actor.ID,
+		TargetID:   id,
+		TargetKind: "handoff",
+		Details:    detailsJSON,
+	})
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanHandoff(row rowScanner) (*Handoff, error) {
+	var h Handoff
+	var pendingJSON, pinnedJSON, createdAt, expiresAt, acceptedAt, completedAt string
+	if err := row.Scan(&h.ID, &h.FromAgent, &h.FromSessionID, &h.ToAgentType, &h.Task, &h.Context,
+		&pendingJSON, &pinnedJSON, &h.Priority, &h.Status, &h.AcceptedBy, &h.Summary,
+		&createdAt, &expiresAt, &acceptedAt, &completedAt); err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal([]byte(pendingJSON), &h.PendingWork)
+	_ = json.Unmarshal([]byte(pinnedJSON), &h.PinnedRecords)
+	h.CreatedAt = parseTimeOrZero(createdAt)
+	h.ExpiresAt = parseTimeOrZero(expiresAt)
+	h.AcceptedAt = parseTimeOrZero(acceptedAt)
+	h.CompletedAt = parseTimeOrZero(completedAt)
+	return &h, nil
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
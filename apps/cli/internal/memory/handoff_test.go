@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openHandoffTestMemory(t *testing.T) *Memory {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "handoff-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	mem, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	t.Cleanup(func() { mem.Close() })
+	return mem
+}
+
+func TestHandoffLifecycle(t *testing.T) {
+	mem := openHandoffTestMemory(t)
+	agent := HandoffActor{Type: AuditActorAgent, ID: "ses_a"}
+
+	h, err := mem.CreateHandoff(Handoff{
+		FromAgent:     "claude",
+		FromSessionID: "ses_a",
+		ToAgentType:   "cursor",
+		Task:          "Finish the auth migration",
+		PendingWork:   []string{"update tests", "remove old middleware"},
+		PinnedRecords: []string{"d_1"},
+	}, agent)
+	if err != nil {
+		t.Fatalf("CreateHandoff() error = %v", err)
+	}
+	if !strings.HasPrefix(h.ID, "hoff_") {
+		t.Errorf("ID = %q, want hoff_ prefix", h.ID)
+	}
+
+	got, err := mem.GetHandoff(h.ID)
+	if err != nil {
+		t.Fatalf("GetHandoff() error = %v", err)
+	}
+	if got.Status != HandoffStatusPending || got.Priority != "normal" || len(got.PendingWork) != 2 || got.PinnedRecords[0] != "d_1" {
+		t.Errorf("GetHandoff() = %+v", got)
+	}
+	if d := got.ExpiresAt.Sub(got.CreatedAt); d != DefaultHandoffTTL {
+		t.Errorf("default TTL = %v, want %v", d, DefaultHandoffTTL)
+	}
+
+	if _, err := mem.AcceptHandoff(h.ID, "ses_b", HandoffActor{Type: AuditActorAgent, ID: "ses_b"}); err != nil {
+		t.Fatalf("AcceptHandoff() error = %v", err)
+	}
+	if _, err := mem.AcceptHandoff(h.ID, "ses_c", HandoffActor{Type: AuditActorAgent, ID: "ses_c"}); err == nil {
+		t.Error("expected second accept to fail")
+	}
+
+	active, err := mem.GetAcceptedHandoffForSession("ses_b")
+	if err != nil || active == nil || active.ID != h.ID {
+		t.Fatalf("GetAcceptedHandoffForSession() = %v, %v", active, err)
+	}
+
+	done, err := mem.CompleteHandoff(h.ID, "all done", HandoffActor{Type: AuditActorHuman, ID: "alice"})
+	if err != nil {
+		t.Fatalf("CompleteHandoff() error = %v", err)
+	}
+	if done.Status != HandoffStatusCompleted || done.CompletedAt.IsZero() {
+		t.Errorf("CompleteHandoff() = %+v", done)
+	}
+	if _, err := mem.CompleteHandoff(h.ID, "again", agent); err == nil {
+		t.Error("expected completing a completed handoff to fail")
+	}
+	if active, _ := mem.GetAcceptedHandoffForSession("ses_b"); active != nil {
+		t.Errorf("completed handoff still active for session: %+v", active)
+	}
+
+	logs, err := mem.GetAuditLogs("", h.ID, 10)
+	if err != nil {
+		t.Fatalf("GetAuditLogs() error = %v", err)
+	}
+	actions := map[AuditAction]bool{}
+	for _, l := range logs {
+		actions[l.Action] = true
+		if l.TargetKind != "handoff" {
+			t.Errorf("TargetKind = %q, want handoff", l.TargetKind)
+		}
+	}
+	for _, want := range []AuditAction{AuditActionHandoffCreate, AuditActionHandoffAccept, AuditActionHandoffComplete} {
+		if !actions[want] {
+			t.Errorf("missing audit action %s in %v", want, logs)
+		}
+	}
+}
+
+func TestHandoffExpiry(t *testing.T) {
+	mem := openHandoffTestMemory(t)
+	agent := HandoffActor{Type: AuditActorAgent, ID: "ses_a"}
+
+	past := time.Now().Add(-2 * time.Hour)
+	stale, err := mem.CreateHandoff(Handoff{Task: "stale", CreatedAt: past, ExpiresAt: past.Add(time.Hour)}, agent)
+	if err != nil {
+		t.Fatalf("CreateHandoff() error = %v", err)
+	}
+	fresh, err := mem.CreateHandoff(Handoff{Task: "fresh", Priority: "urgent"}, agent)
+	if err != nil {
+		t.Fatalf("CreateHandoff() error = %v", err)
+	}
+
+	pending, err := mem.GetPendingHandoffsForAgent("claude")
+	if err != nil {
+		t.Fatalf("GetPendingHandoffsForAgent() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != fresh.ID {
+		t.Fatalf("pending = %+v, want only %s", pending, fresh.ID)
+	}
+
+	got, err := mem.GetHandoff(stale.ID)
+	if err != nil {
+		t.Fatalf("GetHandoff() error = %v", err)
+	}
+	if got.Status != HandoffStatusExpired {
+		t.Errorf("stale status = %q, want expired", got.Status)
+	}
+	if _, err := mem.AcceptHandoff(stale.ID, "ses_b", agent); err == nil {
+		t.Error("expected accepting an expired handoff to fail")
+	}
+
+	logs, _ := mem.GetAuditLogs(string(AuditActionHandoffExpire), stale.ID, 10)
+	if len(logs) != 1 || logs[0].ActorType != AuditActorSystem {
+		t.Errorf("expire audit logs = %+v", logs)
+	}
+
+	// Expiry is idempotent.
+	if n, err := mem.ExpireHandoffs(); err != nil || n != 0 {
+		t.Errorf("ExpireHandoffs() = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestHandoffFiltersAndOrdering(t *testing.T) {
+	mem := openHandoffTestMemory(t)
+	agent := HandoffActor{Type: AuditActorAgent}
+
+	for _, h := range []Handoff{
+		{Task: "urgent", Priority: "urgent", CreatedAt: time.Now().Add(-time.Hour)},
+		{Task: "low", Priority: "low"},
+		{Task: "for cursor", ToAgentType: "cursor", Priority: "high"},
+	} {
+		if _, err := mem.CreateHandoff(h, agent); err != nil {
+			t.Fatalf("CreateHandoff() error = %v", err)
+		}
+	}
+
+	all, err := mem.GetHandoffs("", "", 0)
+	if err != nil {
+		t.Fatalf("GetHandoffs() error = %v", err)
+	}
+	if len(all) != 3 || all[0].Task != "urgent" || all[2].Task != "low" {
+		t.Errorf("ordering = %v", handoffTasks(all))
+	}
+
+	// The oldest handoff is the most urgent; LIMIT must not cut it off.
+	top, err := mem.GetHandoffs("", "", 1)
+	if err != nil {
+		t.Fatalf("GetHandoffs(limit 1) error = %v", err)
+	}
+	if len(top) != 1 || top[0].Task != "urgent" {
+		t.Errorf("GetHandoffs(limit 1) = %v, want urgent", handoffTasks(top))
+	}
+
+	claude, _ := mem.GetHandoffs(HandoffStatusPending, "claude", 0)
+	if len(claude) != 2 {
+		t.Errorf("claude handoffs = %v, want 2 addressed to any", handoffTasks(claude))
+	}
+
+	if _, err := mem.GetHandoff("hoff_missing"); !errors.Is(err, ErrHandoffNotFound) {
+		t.Errorf("GetHandoff(missing) error = %v, want ErrHandoffNotFound", err)
+	}
+	if _, err := mem.CreateHandoff(Handoff{}, agent); err == nil {
+		t.Error("expected error for missing task")
+	}
+}
+
+func handoffTasks(hs []Handoff) []string {
+	tasks := make([]string, len(hs))
+	for i := range hs {
+		tasks[i] = hs[i].Task
+	}
+	return tasks
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IsCommitMined reports whether history mining already examined a commit.
+func (m *Memory) IsCommitMined(sha string) (bool, error) {
+	var found string
+	err := m.db.QueryRowContext(context.Background(), `SELECT sha FROM mined_commits WHERE sha = ?`, sha).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check mined commit: %w", err)
+	}
+	return true, nil
+}
+
+// MinedCommit is what history mining records about one commit.
+type MinedCommit struct {
+	SHA        string
+	Kind       string    // proposal kind, empty when the commit produced none
+	ProposalID string    // empty when the commit produced no proposal
+	Files      []string  // workspace files the commit changed
+	Failure    bool      // a fix or revert, counted against each file
+	Date       time.Time // commit date
+	Editor     string    // recorded as the files' last editor
+}
+
+// MarkCommitMined records that history mining examined a commit and adds
+// the commit to the file_intel counts of the files it changed. Both happen
+// in one transaction, so an interrupted run neither loses a commit's file
+// history nor counts it twice when mining resumes. A file's last_edited
+// only moves forward.
+func (m *Memory) MarkCommitMined(c MinedCommit) error {
+	ctx := context.Background()
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	failures := 0
+	if c.Failure {
+		failures = 1
+	}
+	lastEdited := c.Date.UTC().Format(time.RFC3339)
+	for _, path := range c.Files {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO file_intel (path, edit_count, last_edited, last_editor, failure_count)
+			VALUES (?, 1, ?, ?, ?)
+			ON CONFLICT(path) DO UPDATE SET
+				edit_count = edit_count + excluded.edit_count,
+				failure_count = failure_count + excluded.failure_count,
+				last_editor = CASE WHEN COALESCE(last_edited, '') < excluded.last_edited THEN excluded.last_editor ELSE last_editor END,
+				last_edited = CASE WHEN COALESCE(last_edited, '') < excluded.last_edited THEN excluded.last_edited ELSE last_edited END
+		`, path, lastEdited, c.Editor, failures)
+		if err != nil {
+			return fmt.Errorf("add file history for %s: %w", path, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO mined_commits (sha, kind, proposal_id, mined_at) VALUES (?, ?, ?, ?)
+	`, c.SHA, c.Kind, c.ProposalID, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("mark commit mined: %w", err)
+	}
+	return tx.Commit()
+}
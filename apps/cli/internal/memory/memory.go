@@ -6,8 +6,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -18,6 +20,9 @@ type Memory struct {
 	db       *sql.DB
 	root     string
 	pipeline *EmbeddingPipeline // optional, may be nil
+
+	vectorsMu sync.Mutex
+	vectors   *vectorIndex // ANN index, loaded on first large search
 }
 
 // Session represents an agent work session in the workspace.
@@ -111,6 +116,9 @@ func (m *Memory) Close() error {
 	if m.pipeline != nil {
 		m.pipeline.Stop()
 	}
+	if err := m.SaveVectorIndex(); err != nil {
+		log.Printf("save vector index: %v", err)
+	}
 	return m.db.Close()
 }
 
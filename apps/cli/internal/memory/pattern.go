@@ -505,6 +505,17 @@ func (m *Memory) DeletePatternLocations(patternID string) error {
 	return err
 }
 
+// DeletePatternLocationsInFiles removes the locations of all patterns in the given files.
+func (m *Memory) DeletePatternLocationsInFiles(filePaths []string) error {
+	for _, path := range filePaths {
+		if _, err := m.db.ExecContext(context.Background(),
+			"DELETE FROM pattern_locations WHERE file_path = ?", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CountPatterns returns the number of patterns, optionally filtered by status.
 func (m *Memory) CountPatterns(status string) (int, error) {
 	query := "SELECT COUNT(*) FROM patterns"
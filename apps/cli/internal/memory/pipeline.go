@@ -141,8 +141,9 @@ func (p *EmbeddingPipeline) processJob(job embeddingJob) {
 	}
 }
 
-// ProcessPending generates embeddings for records that don't have them.
-// This is useful for backfilling embeddings after enabling the feature.
+// ProcessPending generates embeddings for records that don't have them, or
+// whose embedding came from a different model. This is useful for backfilling
+// embeddings after enabling the feature or changing (or refitting) the model.
 func (p *EmbeddingPipeline) ProcessPending(kinds []string, limit int) (int, error) {
 	if limit <= 0 {
 		limit = 100
@@ -155,7 +156,7 @@ func (p *EmbeddingPipeline) ProcessPending(kinds []string, limit int) (int, erro
 	processed := 0
 
 	for _, kind := range kinds {
-		records, err := p.memory.GetRecordsWithoutEmbeddings(kind, limit-processed)
+		records, err := p.memory.GetRecordsWithoutEmbeddings(kind, p.embedder.Model(), limit-processed)
 		if err != nil {
 			return processed, err
 		}
@@ -207,33 +208,34 @@ type RecordWithContent struct {
 	Content string
 }
 
-// GetRecordsWithoutEmbeddings returns records that don't have embeddings.
-func (m *Memory) GetRecordsWithoutEmbeddings(kind string, limit int) ([]RecordWithContent, error) {
+// GetRecordsWithoutEmbeddings returns records that don't have an embedding
+// from model.
+func (m *Memory) GetRecordsWithoutEmbeddings(kind, model string, limit int) ([]RecordWithContent, error) {
 	var query string
 	switch kind {
 	case "idea":
 		query = `
 			SELECT i.id, i.content FROM ideas i
 			LEFT JOIN embeddings e ON i.id = e.record_id
-			WHERE e.record_id IS NULL
+			WHERE e.record_id IS NULL OR e.model != ?
 			LIMIT ?`
 	case "decision":
 		query = `
 			SELECT d.id, d.content FROM decisions d
 			LEFT JOIN embeddings e ON d.id = e.record_id
-			WHERE e.record_id IS NULL
+			WHERE e.record_id IS NULL OR e.model != ?
 			LIMIT ?`
 	case "learning":
 		query = `
 			SELECT l.id, l.content FROM learnings l
 			LEFT JOIN embeddings e ON l.id = e.record_id
-			WHERE e.record_id IS NULL
+			WHERE e.record_id IS NULL OR e.model != ?
 			LIMIT ?`
 	default:
 		return nil, nil
 	}
 
-	rows, err := m.db.QueryContext(context.Background(), query, limit)
+	rows, err := m.db.QueryContext(context.Background(), query, model, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -20,14 +20,15 @@ const (
 
 // ProposedAs constants - what type of record this proposal would become
 const (
-	ProposedAsDecision = "decision"
-	ProposedAsLearning = "learning"
+	ProposedAsDecision   = "decision"
+	ProposedAsLearning   = "learning"
+	ProposedAsPostmortem = "postmortem"
 )
 
 // Proposal represents a proposed decision or learning awaiting human approval.
 type Proposal struct {
 	ID                       string    `json:"id"`
-	ProposedAs               string    `json:"proposedAs"`               // "decision" or "learning"
+	ProposedAs               string    `json:"proposedAs"`               // "decision", "learning" or "postmortem"
 	Content                  string    `json:"content"`                  // The proposed content
 	Context                  string    `json:"context,omitempty"`        // Additional context
 	Rationale                string    `json:"rationale,omitempty"`      // For decisions
@@ -52,13 +53,15 @@ type Proposal struct {
 
 // EvidenceRef represents evidence supporting a proposal.
 type EvidenceRef struct {
-	SessionID      string  `json:"sessionId,omitempty"`
-	ConversationID string  `json:"conversationId,omitempty"`
-	Extractor      string  `json:"extractor,omitempty"`
-	SourceRecord   string  `json:"sourceRecord,omitempty"`
-	TargetRecord   string  `json:"targetRecord,omitempty"`
-	Confidence     float64 `json:"confidence,omitempty"`
-	Explanation    string  `json:"explanation,omitempty"`
+	SessionID      string   `json:"sessionId,omitempty"`
+	ConversationID string   `json:"conversationId,omitempty"`
+	Extractor      string   `json:"extractor,omitempty"`
+	SourceRecord   string   `json:"sourceRecord,omitempty"`
+	TargetRecord   string   `json:"targetRecord,omitempty"`
+	Confidence     float64  `json:"confidence,omitempty"`
+	Explanation    string   `json:"explanation,omitempty"`
+	Commit         string   `json:"commit,omitempty"` // git commit the proposal was mined from
+	Files          []string `json:"files,omitempty"`  // files the commit changed
 }
 
 // GenerateDedupeKey creates a deterministic key for duplicate detection.
@@ -343,7 +346,8 @@ func (m *Memory) GetProposalsBySession(sessionID string) ([]Proposal, error) {
 	return proposals, nil
 }
 
-// ApproveProposal approves a proposal and creates the corresponding decision/learning.
+// ApproveProposal approves a proposal and creates the corresponding decision,
+// learning or postmortem.
 // Returns the ID of the promoted record.
 func (m *Memory) ApproveProposal(proposalID, reviewedBy, reviewNote string) (string, error) {
 	// Get the proposal
@@ -413,6 +417,21 @@ func (m *Memory) ApproveProposal(proposalID, reviewedBy, reviewNote string) (str
 			return "", fmt.Errorf("create learning: %w", err)
 		}
 
+	case ProposedAsPostmortem:
+		var evidence EvidenceRef
+		_ = json.Unmarshal([]byte(proposal.EvidenceRefs), &evidence)
+		pm, err := m.StorePostmortem(PostmortemInput{
+			Title:          proposal.Content,
+			WhatHappened:   proposal.Context,
+			RootCause:      proposal.Rationale,
+			AffectedFiles:  evidence.Files,
+			RelatedSession: proposal.SessionID,
+		})
+		if err != nil {
+			return "", fmt.Errorf("create postmortem: %w", err)
+		}
+		promotedID = pm.ID
+
 	default:
 		return "", fmt.Errorf("unknown proposedAs: %s", proposal.ProposedAs)
 	}
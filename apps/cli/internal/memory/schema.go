@@ -40,6 +40,18 @@ var migrations = []func(*sql.Tx) error{
 	migrateV8,
 	// Migration 9: Contracts tables for FE-BE contract detection
 	migrateV9,
+	// Migration 10: Agent handoffs
+	migrateV10,
+	// Migration 11: Playbook runs
+	migrateV11,
+	// Migration 12: Source and content hash for imported conversations
+	migrateV12,
+	// Migration 13: Commits mined from git history
+	migrateV13,
+	// Migration 14: Decisions synced with ADR files
+	migrateV14,
+	// Migration 15: OpenAPI spec side of contracts
+	migrateV15,
 }
 
 // migrateV0 creates the initial database schema (version 0)
@@ -456,7 +468,7 @@ func migrateV5(tx *sql.Tx) error {
 -- Agent/LLM writes go to proposals first, requiring human approval to become authoritative records
 CREATE TABLE IF NOT EXISTS proposals (
     id TEXT PRIMARY KEY,
-    proposed_as TEXT NOT NULL,                    -- 'decision' or 'learning'
+    proposed_as TEXT NOT NULL,                    -- 'decision', 'learning' or 'postmortem'
     content TEXT NOT NULL,
     context TEXT DEFAULT '',
     rationale TEXT DEFAULT '',                    -- For decisions
@@ -793,3 +805,141 @@ CREATE INDEX IF NOT EXISTS idx_contract_mismatches_type ON contract_mismatches(m
 	_, err := tx.ExecContext(context.Background(), schema)
 	return err
 }
+
+// migrateV10 adds the handoffs table so agent handoffs survive restarts and
+// are shared between MCP server processes.
+func migrateV10(tx *sql.Tx) error {
+	schema := `
+-- Handoffs: tasks passed from one agent session to another
+CREATE TABLE IF NOT EXISTS handoffs (
+    id TEXT PRIMARY KEY,
+    from_agent TEXT NOT NULL DEFAULT '',
+    from_session_id TEXT NOT NULL DEFAULT '',
+    to_agent_type TEXT NOT NULL DEFAULT 'any',
+    task TEXT NOT NULL,
+    context TEXT NOT NULL DEFAULT '',
+    pending_work TEXT NOT NULL DEFAULT '[]',     -- JSON array
+    pinned_records TEXT NOT NULL DEFAULT '[]',   -- JSON array of record IDs
+    priority TEXT NOT NULL DEFAULT 'normal',     -- 'low', 'normal', 'high', 'urgent'
+    status TEXT NOT NULL DEFAULT 'pending',      -- 'pending', 'accepted', 'completed', 'expired'
+    accepted_by TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    accepted_at TEXT NOT NULL DEFAULT '',
+    completed_at TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_handoffs_status ON handoffs(status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_handoffs_to_agent ON handoffs(to_agent_type);
+CREATE INDEX IF NOT EXISTS idx_handoffs_accepted_by ON handoffs(accepted_by);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV11 adds the playbook_runs table so playbook executions survive
+// restarts and several sessions can run playbooks at once.
+func migrateV11(tx *sql.Tx) error {
+	schema := `
+-- Playbook runs: one row per playbook execution
+CREATE TABLE IF NOT EXISTS playbook_runs (
+    id TEXT PRIMARY KEY,
+    playbook_name TEXT NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',       -- owning session ('cli' for CLI runs)
+    status TEXT NOT NULL DEFAULT 'running',    -- 'running', 'paused', 'completed', 'failed'
+    playbook TEXT NOT NULL DEFAULT '{}',       -- JSON snapshot of the playbook definition
+    current_room_idx INTEGER NOT NULL DEFAULT 0,
+    current_step_idx INTEGER NOT NULL DEFAULT 0,
+    completed_rooms TEXT NOT NULL DEFAULT '[]', -- JSON array of room names
+    completed_steps TEXT NOT NULL DEFAULT '{}', -- JSON object: room -> step indices
+    evidence TEXT NOT NULL DEFAULT '{}',        -- JSON object: evidence ID -> data
+    verification TEXT NOT NULL DEFAULT '[]',    -- JSON array of verification results
+    started_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_playbook_runs_session ON playbook_runs(session_id, status);
+CREATE INDEX IF NOT EXISTS idx_playbook_runs_updated ON playbook_runs(updated_at);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV12 records where imported conversations came from and a hash of
+// their messages, so re-importing agent transcripts does not duplicate them.
+func migrateV12(tx *sql.Tx) error {
+	alterStatements := []string{
+		// Where the transcript came from, e.g. "claude-code:<session-id>"
+		`ALTER TABLE conversations ADD COLUMN source TEXT DEFAULT ''`,
+		// sha256 of the agent type and messages
+		`ALTER TABLE conversations ADD COLUMN content_hash TEXT DEFAULT ''`,
+	}
+	for _, stmt := range alterStatements {
+		// Ignore errors if the column already exists
+		_, _ = tx.ExecContext(context.Background(), stmt)
+	}
+
+	schema := `
+CREATE INDEX IF NOT EXISTS idx_conversations_source ON conversations(source);
+CREATE INDEX IF NOT EXISTS idx_conversations_hash ON conversations(content_hash);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV13 adds the mined_commits table so mining git history again only
+// looks at new commits and does not count file edits twice.
+func migrateV13(tx *sql.Tx) error {
+	schema := `
+-- Commits already examined by history mining
+CREATE TABLE IF NOT EXISTS mined_commits (
+    sha TEXT PRIMARY KEY,
+    kind TEXT DEFAULT '',                         -- decision, learning, postmortem, or '' when nothing was proposed
+    proposal_id TEXT DEFAULT '',
+    mined_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV14 adds the adr_records table that maps decisions to ADR files.
+// The hashes record both sides as of the last sync, so a sync only touches
+// the side that changed.
+func migrateV14(tx *sql.Tx) error {
+	schema := `
+-- Decisions synced with Architecture Decision Record files
+CREATE TABLE IF NOT EXISTS adr_records (
+    decision_id TEXT PRIMARY KEY,
+    path TEXT NOT NULL UNIQUE,                    -- relative to the workspace root
+    number INTEGER NOT NULL,
+    file_hash TEXT NOT NULL,                      -- hash of the file at the last sync
+    decision_hash TEXT NOT NULL,                  -- hash of the decision at the last sync
+    synced_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV15 records where an OpenAPI spec declares a contract's endpoint,
+// and which two sources (spec, backend, frontend) each mismatch is between.
+func migrateV15(tx *sql.Tx) error {
+	// SQLite doesn't support IF NOT EXISTS for ALTER TABLE, so we ignore errors
+	// if the column already exists
+	alterStatements := []string{
+		`ALTER TABLE contracts ADD COLUMN spec_file TEXT DEFAULT ''`,
+		`ALTER TABLE contracts ADD COLUMN spec_line INTEGER DEFAULT 0`,
+		`ALTER TABLE contracts ADD COLUMN spec_operation_id TEXT DEFAULT ''`,
+		`ALTER TABLE contracts ADD COLUMN spec_request_schema TEXT DEFAULT ''`,
+		`ALTER TABLE contracts ADD COLUMN spec_response_schema TEXT DEFAULT ''`,
+		// '', spec_backend or spec_frontend
+		`ALTER TABLE contract_mismatches ADD COLUMN scope TEXT DEFAULT ''`,
+	}
+
+	for _, stmt := range alterStatements {
+		_, _ = tx.ExecContext(context.Background(), stmt)
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
+)
+
+// vectorIndexMinRecords is the store size below which similarity search
+// scans embeddings directly; the ANN index only pays off for larger stores.
+var vectorIndexMinRecords = 2000
+
+// vectorIndexDir is where per-kind HNSW graphs are stored, relative to .palace.
+const vectorIndexDir = "vectors"
+
+var vectorKindPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// vectorIndex holds one HNSW graph per record kind, persisted under
+// .palace/vectors/<kind>.hnsw. Graphs are loaded on first use and reconciled
+// with the embeddings table whenever its row count or highest rowid changes,
+// so vectors written or replaced by another process are picked up without a
+// full rebuild. The files are a cache: they are written on Close, and a stale
+// or missing file is repaired by the next reconciliation.
+type vectorIndex struct {
+	mu       sync.RWMutex
+	dir      string
+	graphs   map[string]*vector.Graph
+	dirty    map[string]bool
+	loadedAt map[string]time.Time // Modification time of each graph file read by load
+	rowids   map[string]int64     // Embeddings rowid each indexed vector was read from
+	stamp    embeddingsStamp      // Table state at the last reconciliation
+}
+
+// embeddingsStamp cheaply detects writes to the embeddings table. INSERT OR
+// REPLACE assigns a new rowid, so replaced vectors move the maximum too.
+type embeddingsStamp struct {
+	count    int64
+	maxRowID int64
+}
+
+// VectorIndexStats describes the ANN index.
+type VectorIndexStats struct {
+	Indexed map[string]int `json:"indexed"` // Live vectors per kind
+	Path    string         `json:"path"`
+}
+
+// vectorIndex returns the loaded index, loading it on first use and
+// reconciling it whenever the embeddings table changed since the last search.
+func (m *Memory) vectorIndex() (*vectorIndex, error) {
+	m.vectorsMu.Lock()
+	defer m.vectorsMu.Unlock()
+	if m.vectors != nil {
+		stamp, err := m.embeddingsStamp()
+		if err != nil {
+			return nil, err
+		}
+		if stamp != m.vectors.stamp {
+			if err := m.reconcileVectorIndex(m.vectors); err != nil {
+				return nil, err
+			}
+		}
+		return m.vectors, nil
+	}
+
+	idx := newVectorIndex(filepath.Join(m.root, ".palace", vectorIndexDir))
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+	if err := m.reconcileVectorIndex(idx); err != nil {
+		return nil, err
+	}
+	m.vectors = idx
+	return idx, nil
+}
+
+func newVectorIndex(dir string) *vectorIndex {
+	return &vectorIndex{
+		dir:      dir,
+		graphs:   make(map[string]*vector.Graph),
+		dirty:    make(map[string]bool),
+		loadedAt: make(map[string]time.Time),
+		rowids:   make(map[string]int64),
+	}
+}
+
+// embeddingsStamp returns the current row count and highest rowid.
+func (m *Memory) embeddingsStamp() (embeddingsStamp, error) {
+	var s embeddingsStamp
+	err := m.db.QueryRowContext(context.Background(),
+		`SELECT COUNT(*), COALESCE(MAX(rowid), 0) FROM embeddings`).Scan(&s.count, &s.maxRowID)
+	return s, err
+}
+
+// load reads all graphs in the index directory. Unreadable graphs are
+// skipped and rebuilt by reconciliation.
+func (v *vectorIndex) load() error {
+	entries, err := os.ReadDir(v.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read vector index: %w", err)
+	}
+	for _, e := range entries {
+		kind, ok := strings.CutSuffix(e.Name(), ".hnsw")
+		if !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		g, err := vector.LoadGraph(filepath.Join(v.dir, e.Name()))
+		if err != nil {
+			log.Printf("vector index %s unreadable, rebuilding: %v", kind, err)
+			continue
+		}
+		v.graphs[kind] = g
+		v.loadedAt[kind] = info.ModTime().Truncate(time.Second)
+	}
+	return nil
+}
+
+// reconcileVectorIndex adds embeddings missing from the graphs, re-reads
+// embeddings replaced since they were indexed, and removes vectors whose
+// embeddings were deleted.
+func (m *Memory) reconcileVectorIndex(v *vectorIndex) error {
+	stamp, err := m.embeddingsStamp()
+	if err != nil {
+		return err
+	}
+	rows, err := m.db.QueryContext(context.Background(), `SELECT rowid, record_id, record_kind, created_at FROM embeddings`)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	stored := make(map[string]map[string]bool)
+	stale := make(map[string]bool)
+	for rows.Next() {
+		var rowid int64
+		var id, kind, createdAt string
+		if err := rows.Scan(&rowid, &id, &kind, &createdAt); err != nil {
+			rows.Close()
+			return err
+		}
+		if stored[kind] == nil {
+			stored[kind] = make(map[string]bool)
+		}
+		stored[kind][id] = true
+		if v.staleVector(id, kind, rowid, createdAt) {
+			stale[id] = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for kind, g := range v.graphs {
+		for _, id := range g.IDs() {
+			if !stored[kind][id] {
+				g.Remove(id)
+				delete(v.rowids, id)
+				v.dirty[kind] = true
+			}
+		}
+	}
+
+	if len(stale) == 0 {
+		v.stamp = stamp
+		return nil
+	}
+
+	rows, err = m.db.QueryContext(context.Background(), `SELECT rowid, record_id, record_kind, embedding FROM embeddings`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rowid int64
+		var id, kind string
+		var blob []byte
+		if err := rows.Scan(&rowid, &id, &kind, &blob); err != nil {
+			return err
+		}
+		if !stale[id] {
+			continue
+		}
+		g := v.graphs[kind]
+		if g == nil {
+			g = vector.NewGraph()
+			v.graphs[kind] = g
+		}
+		g.Add(id, vector.Decode(blob))
+		v.rowids[id] = rowid
+		v.dirty[kind] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	v.stamp = stamp
+	return nil
+}
+
+// staleVector reports whether an embeddings row must be (re)read into the
+// graph for kind. Vectors indexed by this process are compared by rowid;
+// vectors read from a graph file have no rowid, so rows written after the
+// file was saved are re-read. Callers hold v.mu.
+func (v *vectorIndex) staleVector(id, kind string, rowid int64, createdAt string) bool {
+	g := v.graphs[kind]
+	if g == nil || !g.Has(id) {
+		return true
+	}
+	if known, ok := v.rowids[id]; ok {
+		return known != rowid
+	}
+	v.rowids[id] = rowid
+	if saved, ok := v.loadedAt[kind]; ok {
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil && !t.Before(saved) {
+			return true
+		}
+	}
+	return false
+}
+
+// add inserts or replaces a vector read from the given embeddings rowid.
+func (v *vectorIndex) add(id, kind string, vec []float32, rowid int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// A record has a single embedding; drop it from any other kind's graph.
+	for k, g := range v.graphs {
+		if k != kind && g.Has(id) {
+			g.Remove(id)
+			v.dirty[k] = true
+		}
+	}
+	g := v.graphs[kind]
+	if g == nil {
+		g = vector.NewGraph()
+		v.graphs[kind] = g
+	}
+	g.Add(id, vec)
+	v.rowids[id] = rowid
+	v.dirty[kind] = true
+}
+
+// remove deletes a vector from every graph.
+func (v *vectorIndex) remove(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.rowids, id)
+	for kind, g := range v.graphs {
+		if g.Has(id) {
+			g.Remove(id)
+			v.dirty[kind] = true
+		}
+	}
+}
+
+// search queries the graph for kind, or all graphs when kind is empty.
+func (v *vectorIndex) search(query []float32, kind string, limit int, minSimilarity float32) []SimilarityResult {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var results []SimilarityResult
+	for k, g := range v.graphs {
+		if kind != "" && k != kind {
+			continue
+		}
+		for _, m := range g.Search(query, limit) {
+			if m.Similarity < minSimilarity {
+				continue
+			}
+			results = append(results, SimilarityResult{
+				RecordID:   m.ID,
+				RecordKind: k,
+				Similarity: m.Similarity,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Similarity > results[j].Similarity })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// save writes modified graphs to disk, compacting fragmented ones.
+func (v *vectorIndex) save() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for kind := range v.dirty {
+		g := v.graphs[kind]
+		if g == nil || !vectorKindPattern.MatchString(kind) {
+			delete(v.dirty, kind)
+			continue
+		}
+		if g.Fragmented() {
+			g = g.Compact()
+			v.graphs[kind] = g
+		}
+		if err := g.Save(filepath.Join(v.dir, kind+".hnsw")); err != nil {
+			return fmt.Errorf("save vector index %s: %w", kind, err)
+		}
+		delete(v.dirty, kind)
+	}
+	return nil
+}
+
+func (v *vectorIndex) stats() VectorIndexStats {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	stats := VectorIndexStats{Indexed: make(map[string]int), Path: v.dir}
+	for kind, g := range v.graphs {
+		stats.Indexed[kind] = g.Len()
+	}
+	return stats
+}
+
+// RebuildVectorIndex rebuilds the ANN index from the embeddings table and
+// writes it to disk.
+func (m *Memory) RebuildVectorIndex() (VectorIndexStats, error) {
+	m.vectorsMu.Lock()
+	idx := newVectorIndex(filepath.Join(m.root, ".palace", vectorIndexDir))
+	if err := m.reconcileVectorIndex(idx); err != nil {
+		m.vectorsMu.Unlock()
+		return VectorIndexStats{}, err
+	}
+	m.vectors = idx
+	m.vectorsMu.Unlock()
+
+	// Remove graphs for kinds that no longer have embeddings.
+	if entries, err := os.ReadDir(idx.dir); err == nil {
+		for _, e := range entries {
+			kind, ok := strings.CutSuffix(e.Name(), ".hnsw")
+			if ok && idx.graphs[kind] == nil {
+				_ = os.Remove(filepath.Join(idx.dir, e.Name()))
+			}
+		}
+	}
+
+	if err := idx.save(); err != nil {
+		return VectorIndexStats{}, err
+	}
+	return idx.stats(), nil
+}
+
+// SaveVectorIndex writes pending index changes to disk.
+func (m *Memory) SaveVectorIndex() error {
+	m.vectorsMu.Lock()
+	idx := m.vectors
+	m.vectorsMu.Unlock()
+	if idx == nil {
+		return nil
+	}
+	return idx.save()
+}
+
+// updateVectorIndex applies a stored embedding to the index if it is loaded.
+func (m *Memory) updateVectorIndex(recordID, recordKind string, embedding []float32, rowid int64) {
+	m.vectorsMu.Lock()
+	idx := m.vectors
+	m.vectorsMu.Unlock()
+	if idx != nil {
+		idx.add(recordID, recordKind, embedding, rowid)
+	}
+}
+
+// removeFromVectorIndex drops a deleted embedding from the index if it is loaded.
+func (m *Memory) removeFromVectorIndex(recordID string) {
+	m.vectorsMu.Lock()
+	idx := m.vectors
+	m.vectorsMu.Unlock()
+	if idx != nil {
+		idx.remove(recordID)
+	}
+}
@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/vector"
+)
+
+func randomVectors(n, dim int, seed int64) [][]float32 {
+	rng := rand.New(rand.NewSource(seed))
+	vecs := make([][]float32, n)
+	for i := range vecs {
+		vecs[i] = make([]float32, dim)
+		for j := range vecs[i] {
+			vecs[i][j] = rng.Float32()*2 - 1
+		}
+	}
+	return vecs
+}
+
+func vectorID(i int) string {
+	return "l_" + string(rune('a'+i%26)) + string(rune('a'+i/26%26)) + string(rune('a'+i/676))
+}
+
+func TestFindSimilarEmbeddingsUsesVectorIndex(t *testing.T) {
+	old := vectorIndexMinRecords
+	vectorIndexMinRecords = 10
+	t.Cleanup(func() { vectorIndexMinRecords = old })
+
+	tmpDir := t.TempDir()
+	mem, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	vecs := randomVectors(60, 16, 5)
+	for i, v := range vecs {
+		kind := "learning"
+		if i%2 == 1 {
+			kind = "idea"
+		}
+		if err := mem.StoreEmbedding(vectorID(i), kind, v, "model"); err != nil {
+			t.Fatalf("StoreEmbedding() error = %v", err)
+		}
+	}
+
+	results, err := mem.FindSimilarEmbeddings(vecs[0], "learning", 5, 0)
+	if err != nil {
+		t.Fatalf("FindSimilarEmbeddings() error = %v", err)
+	}
+	if len(results) != 5 || results[0].RecordID != vectorID(0) {
+		t.Fatalf("results = %+v, want %s first", results, vectorID(0))
+	}
+	for _, r := range results {
+		if r.RecordKind != "learning" {
+			t.Errorf("kind filter leaked %+v", r)
+		}
+	}
+
+	// Updates after the index is loaded are applied in place.
+	if err := mem.DeleteEmbedding(vectorID(0)); err != nil {
+		t.Fatalf("DeleteEmbedding() error = %v", err)
+	}
+	results, _ = mem.FindSimilarEmbeddings(vecs[0], "", 3, 0)
+	for _, r := range results {
+		if r.RecordID == vectorID(0) {
+			t.Errorf("deleted embedding still returned: %+v", results)
+		}
+	}
+
+	// Close persists the index; reopening picks up embeddings stored by
+	// another handle without a rebuild.
+	mem.Close()
+	if _, err := os.Stat(filepath.Join(tmpDir, ".palace", "vectors", "learning.hnsw")); err != nil {
+		t.Fatalf("index not saved: %v", err)
+	}
+
+	mem, err = Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer mem.Close()
+	extra := []float32{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := mem.DB().Exec(`INSERT INTO embeddings (record_id, record_kind, embedding, model, created_at) VALUES ('l_new', 'learning', ?, 'model', '')`, vector.Encode(extra)); err != nil {
+		t.Fatalf("insert embedding: %v", err)
+	}
+	results, _ = mem.FindSimilarEmbeddings(extra, "learning", 1, 0)
+	if len(results) != 1 || results[0].RecordID != "l_new" {
+		t.Errorf("reconciled search = %+v, want l_new", results)
+	}
+
+	stats, err := mem.RebuildVectorIndex()
+	if err != nil {
+		t.Fatalf("RebuildVectorIndex() error = %v", err)
+	}
+	if stats.Indexed["learning"] != 30 || stats.Indexed["idea"] != 30 {
+		t.Errorf("rebuild stats = %+v", stats.Indexed)
+	}
+}
+
+func TestVectorIndexReconcilesWritesFromAnotherHandle(t *testing.T) {
+	old := vectorIndexMinRecords
+	vectorIndexMinRecords = 10
+	t.Cleanup(func() { vectorIndexMinRecords = old })
+
+	tmpDir := t.TempDir()
+	mem, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer mem.Close()
+	other, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer other.Close()
+
+	vecs := randomVectors(20, 16, 7)
+	for i, v := range vecs {
+		if err := mem.StoreEmbedding(vectorID(i), "learning", v, "model"); err != nil {
+			t.Fatalf("StoreEmbedding() error = %v", err)
+		}
+	}
+	// Load the index before the other handle writes.
+	if results, _ := mem.FindSimilarEmbeddings(vecs[0], "learning", 1, 0); len(results) != 1 || results[0].RecordID != vectorID(0) {
+		t.Fatalf("initial search = %+v", results)
+	}
+
+	added := []float32{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if err := other.StoreEmbedding("l_added", "learning", added, "model"); err != nil {
+		t.Fatalf("StoreEmbedding() error = %v", err)
+	}
+	results, _ := mem.FindSimilarEmbeddings(added, "learning", 1, 0)
+	if len(results) != 1 || results[0].RecordID != "l_added" {
+		t.Errorf("search after external insert = %+v, want l_added", results)
+	}
+
+	// Replacing a vector elsewhere must move it in this handle's graph.
+	replaced := []float32{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	if err := other.StoreEmbedding(vectorID(3), "learning", replaced, "model"); err != nil {
+		t.Fatalf("StoreEmbedding() error = %v", err)
+	}
+	results, _ = mem.FindSimilarEmbeddings(replaced, "learning", 1, 0)
+	if len(results) != 1 || results[0].RecordID != vectorID(3) || results[0].Similarity < 0.999 {
+		t.Errorf("search after external replace = %+v, want %s", results, vectorID(3))
+	}
+
+	if err := other.DeleteEmbedding("l_added"); err != nil {
+		t.Fatalf("DeleteEmbedding() error = %v", err)
+	}
+	results, _ = mem.FindSimilarEmbeddings(added, "learning", 3, 0)
+	for _, r := range results {
+		if r.RecordID == "l_added" {
+			t.Errorf("externally deleted embedding still returned: %+v", results)
+		}
+	}
+}
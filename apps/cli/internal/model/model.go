@@ -30,6 +30,15 @@ type ProjectProfile struct {
 	Provenance    map[string]string     `json:"provenance"`
 }
 
+// LoadProjectProfile loads a project profile from a JSON file.
+func LoadProjectProfile(path string) (ProjectProfile, error) {
+	var profile ProjectProfile
+	if err := jsonc.DecodeFile(path, &profile); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
 // ScopeInfo tracks the analysis scope (full scan vs incremental diff).
 type ScopeInfo struct {
 	Mode      string `json:"mode"`                // "full" | "diff"
@@ -0,0 +1,124 @@
+package ownership
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeownersLocations are where GitHub and GitLab look for a CODEOWNERS
+// file, relative to the repository root, in the order they are tried.
+var CodeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// Codeowners is a parsed CODEOWNERS file.
+type Codeowners struct {
+	Path  string // relative to the repository root
+	rules []codeownersRule
+}
+
+type codeownersRule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// LoadCodeowners reads the first CODEOWNERS file found in repoRoot. It
+// returns nil when the repository has none.
+func LoadCodeowners(repoRoot string) (*Codeowners, error) {
+	for _, loc := range CodeownersLocations {
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(loc)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		co := ParseCodeowners(string(data))
+		co.Path = loc
+		return co, nil
+	}
+	return nil, nil
+}
+
+// ParseCodeowners parses CODEOWNERS text: one "pattern owner..." rule per
+// line, gitignore-style patterns, later rules overriding earlier ones.
+// GitLab section headers are skipped.
+func ParseCodeowners(text string) *Codeowners {
+	co := &Codeowners{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(strings.ReplaceAll(line, `\#`, "#"))
+		co.rules = append(co.rules, codeownersRule{
+			pattern: fields[0],
+			re:      compilePattern(fields[0]),
+			owners:  fields[1:],
+		})
+	}
+	return co
+}
+
+// Owners returns the owners of a repository-relative path. A matching rule
+// without owners means the path is deliberately unowned.
+func (co *Codeowners) Owners(path string) []string {
+	if co == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if co.rules[i].re.MatchString(path) {
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compilePattern turns a gitignore-style pattern into a regexp over
+// slash-separated paths. A pattern matches a path or anything below it.
+func compilePattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	p := strings.Trim(pattern, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	if dirOnly {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,44 @@
+package ownership
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodeownersOwners(t *testing.T) {
+	co := ParseCodeowners(`# Default owners
+*                   @org/core
+*.md                @docs-team
+/build/             @org/infra
+apps/**/api/        @api-team # API surfaces
+docs/generated.md
+[Section]
+internal/auth/ @alice @bob@example.com
+`)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"README.md", []string{"@docs-team"}},
+		{"pkg/guide.md", []string{"@docs-team"}},
+		{"build/ci.yml", []string{"@org/infra"}},
+		{"tools/build/ci.yml", []string{"@org/core"}},
+		{"apps/cli/api/server.go", []string{"@api-team"}},
+		{"apps/api/handler.go", []string{"@api-team"}},
+		{"docs/generated.md", []string{}},
+		{"internal/auth/token.go", []string{"@alice", "@bob@example.com"}},
+	}
+	for _, tt := range tests {
+		got := co.Owners(tt.path)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *Codeowners
+	if got := none.Owners("main.go"); got != nil {
+		t.Errorf("nil Codeowners returned %v", got)
+	}
+}
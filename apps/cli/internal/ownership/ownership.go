@@ -0,0 +1,282 @@
+// Package ownership works out who knows which code: blame shares per file
+// and per symbol, the last author to touch each file, recent churn, and the
+// owners named in CODEOWNERS. Results are stored in the index.
+package ownership
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/gitutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+// DefaultMaxCommits is how much history is read for churn, last-touch and
+// commit counts.
+const DefaultMaxCommits = 5000
+
+// maxSymbolAuthors is how many authors are kept per symbol.
+const maxSymbolAuthors = 3
+
+// ErrNotGitRepo is returned when the workspace is not in a git repository.
+var ErrNotGitRepo = errors.New("not a git repository")
+
+// Options configures an ownership pass.
+type Options struct {
+	MaxCommits int       // DefaultMaxCommits when zero
+	Now        time.Time // end of the churn windows; time.Now when zero
+}
+
+// Summary reports the work done by Analyze.
+type Summary struct {
+	Files      int    // files with ownership stored
+	Blamed     int    // files blamed in this pass
+	Reused     int    // files whose earlier blame was kept because they did not change
+	Symbols    int    // symbols with ownership stored
+	Codeowners string // CODEOWNERS file used, relative to the repository root
+}
+
+// fileHistory is what git log says about one file.
+type fileHistory struct {
+	commits map[string]int  // by email
+	last    *gitutil.Commit // newest commit
+	churn   index.ChurnWindows
+}
+
+// Analyze computes ownership for every indexed file and replaces the
+// ownership stored in db. Files whose content hash matches the previous
+// pass keep their blame results; everything else is recomputed.
+func Analyze(root string, db *sql.DB, opts Options) (Summary, error) {
+	var summary Summary
+	if !gitutil.IsGitRepo(root) {
+		return summary, ErrNotGitRepo
+	}
+	if opts.MaxCommits == 0 {
+		opts.MaxCommits = DefaultMaxCommits
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	files, err := index.LoadFileMetadata(db)
+	if err != nil {
+		return summary, err
+	}
+	previous, err := index.LoadOwnership(db)
+	if err != nil {
+		return summary, err
+	}
+
+	commits, err := gitutil.Log(root, gitutil.LogOptions{Limit: opts.MaxCommits, Relative: true})
+	if err != nil {
+		return summary, err
+	}
+	histories := buildHistories(commits, opts.Now)
+
+	// CODEOWNERS paths are relative to the repository root, which may sit
+	// above the workspace.
+	repoRoot, err := gitutil.GetRepoRoot(root)
+	if err != nil {
+		return summary, err
+	}
+	codeowners, err := LoadCodeowners(repoRoot)
+	if err != nil {
+		return summary, err
+	}
+	if codeowners != nil {
+		summary.Codeowners = codeowners.Path
+	}
+	prefix := workspacePrefix(repoRoot, root)
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var toBlame []string
+	for _, p := range paths {
+		if prev := previous[p]; prev == nil || prev.FileHash != files[p].Hash {
+			toBlame = append(toBlame, p)
+		}
+	}
+	blamed := blameAll(root, toBlame)
+
+	now := time.Now().UTC()
+	owners := make([]index.FileOwnership, 0, len(paths))
+	for _, p := range paths {
+		o := index.FileOwnership{Path: p, FileHash: files[p].Hash, AnalyzedAt: now}
+		if lines, ok := blamed[p]; ok {
+			symbols, err := index.ListSymbolRanges(db, p)
+			if err != nil {
+				return summary, err
+			}
+			o.BlamedLines = len(lines)
+			o.Authors = shares(lines, 0, 0)
+			o.Symbols = symbolOwnership(p, symbols, lines)
+			summary.Blamed++
+		} else if prev := previous[p]; prev != nil && prev.FileHash == o.FileHash {
+			o.BlamedLines, o.Authors, o.Symbols = prev.BlamedLines, prev.Authors, prev.Symbols
+			summary.Reused++
+		}
+
+		if h := histories[p]; h != nil {
+			for i := range o.Authors {
+				o.Authors[i].Commits = h.commits[strings.ToLower(o.Authors[i].Email)]
+			}
+			o.LastAuthor, o.LastEmail = h.last.Author, h.last.Email
+			o.LastCommit, o.LastTouched = h.last.SHA, h.last.Date
+			o.Churn = h.churn
+		}
+		o.CodeOwners = codeowners.Owners(prefix + p)
+
+		if o.BlamedLines == 0 && o.LastCommit == "" && len(o.CodeOwners) == 0 {
+			continue // untracked and unowned
+		}
+		summary.Symbols += len(o.Symbols)
+		owners = append(owners, o)
+	}
+
+	if err := index.ReplaceOwnership(db, owners); err != nil {
+		return summary, err
+	}
+	summary.Files = len(owners)
+	return summary, nil
+}
+
+// buildHistories groups commits (newest first) by the files they touched.
+func buildHistories(commits []gitutil.Commit, now time.Time) map[string]*fileHistory {
+	histories := map[string]*fileHistory{}
+	for i := range commits {
+		c := &commits[i]
+		age := now.Sub(c.Date)
+		for _, f := range c.Files {
+			h := histories[f]
+			if h == nil {
+				h = &fileHistory{commits: map[string]int{}, last: c}
+				histories[f] = h
+			}
+			h.commits[strings.ToLower(c.Email)]++
+			switch {
+			case age <= 30*24*time.Hour:
+				h.churn.Days30++
+				fallthrough
+			case age <= 90*24*time.Hour:
+				h.churn.Days90++
+				fallthrough
+			case age <= 365*24*time.Hour:
+				h.churn.Days365++
+			}
+		}
+	}
+	return histories
+}
+
+// blameAll blames files in parallel. Files git cannot blame, such as
+// untracked ones, are left out.
+func blameAll(root string, paths []string) map[string][]gitutil.BlameLine {
+	results := make(map[string][]gitutil.BlameLine, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan string)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range work {
+				lines, err := gitutil.Blame(root, p)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				results[p] = lines
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, p := range paths {
+		work <- p
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
+
+// shares attributes blamed lines in [start, end] to authors; zero bounds
+// cover every line.
+func shares(lines []gitutil.BlameLine, start, end int) []index.AuthorShare {
+	counts := map[string]*index.AuthorShare{}
+	total := 0
+	for _, l := range lines {
+		if start > 0 && (l.Line < start || l.Line > end) {
+			continue
+		}
+		total++
+		email := strings.ToLower(l.Email)
+		a := counts[email]
+		if a == nil {
+			a = &index.AuthorShare{Author: l.Author, Email: email}
+			counts[email] = a
+		}
+		a.Lines++
+	}
+	result := make([]index.AuthorShare, 0, len(counts))
+	for _, a := range counts {
+		a.Share = float64(a.Lines) / float64(total)
+		result = append(result, *a)
+	}
+	index.SortAuthorShares(result)
+	return result
+}
+
+func symbolOwnership(path string, symbols []index.SymbolInfo, lines []gitutil.BlameLine) []index.SymbolOwnership {
+	var result []index.SymbolOwnership
+	for i := range symbols {
+		s := &symbols[i]
+		if s.LineStart <= 0 || s.LineEnd < s.LineStart {
+			continue
+		}
+		authors := shares(lines, s.LineStart, s.LineEnd)
+		if len(authors) == 0 {
+			continue
+		}
+		if len(authors) > maxSymbolAuthors {
+			authors = authors[:maxSymbolAuthors]
+		}
+		result = append(result, index.SymbolOwnership{
+			FilePath:  path,
+			Symbol:    s.Name,
+			Kind:      s.Kind,
+			LineStart: s.LineStart,
+			LineEnd:   s.LineEnd,
+			Authors:   authors,
+		})
+	}
+	return result
+}
+
+// workspacePrefix returns the workspace's path inside the repository with a
+// trailing slash, or "" when the workspace is the repository root.
+func workspacePrefix(repoRoot, root string) string {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(repoRoot); err == nil {
+		repoRoot = resolved
+	}
+	rel, err := filepath.Rel(repoRoot, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel) + "/"
+}
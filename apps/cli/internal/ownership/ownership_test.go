@@ -0,0 +1,133 @@
+package ownership
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+)
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	git := func(author string, args ...string) {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	git("alice", "config", "commit.gpgsign", "false")
+
+	write("calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	write(".github/CODEOWNERS", "*.go @calc-team\n")
+	git("alice", "add", "-A")
+	git("alice", "commit", "-q", "-m", "Add calc")
+
+	write("calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\tresult := a\n\tresult -= b\n\treturn result\n}\n")
+	git("bob", "add", "-A")
+	git("bob", "commit", "-q", "-m", "Add Sub")
+	write("notes.txt", "untracked\n")
+
+	db, err := index.Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	scan := func() {
+		t.Helper()
+		records, err := index.BuildFileRecords(dir, config.Guardrails{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := index.WriteScan(db, dir, records, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scan()
+
+	summary, err := Analyze(dir, db, Options{})
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if summary.Blamed == 0 || summary.Reused != 0 || summary.Codeowners != ".github/CODEOWNERS" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	o, err := index.GetFileOwnership(db, "calc.go")
+	if err != nil || o == nil {
+		t.Fatalf("GetFileOwnership() = %v, %v", o, err)
+	}
+	if o.BlamedLines != 11 || len(o.Authors) != 2 {
+		t.Fatalf("ownership = %+v", o)
+	}
+	if o.Authors[0].Author != "bob" || o.Authors[0].Lines != 6 || o.Authors[1].Commits != 1 {
+		t.Errorf("authors = %+v", o.Authors)
+	}
+	if o.LastAuthor != "bob" || o.Churn.Days30 != 2 || o.Churn.Days365 != 2 {
+		t.Errorf("last author %q, churn %+v", o.LastAuthor, o.Churn)
+	}
+	if len(o.CodeOwners) != 1 || o.CodeOwners[0] != "@calc-team" {
+		t.Errorf("code owners = %v", o.CodeOwners)
+	}
+	if got := o.Reviewers(3); len(got) != 3 || got[0] != "@calc-team" || got[1] != "bob" {
+		t.Errorf("Reviewers() = %v", got)
+	}
+
+	symbols, err := index.GetSymbolOwnership(db, "Sub")
+	if err != nil || len(symbols) != 1 {
+		t.Fatalf("GetSymbolOwnership(Sub) = %v, %v", symbols, err)
+	}
+	if a := symbols[0].Authors; len(a) != 1 || a[0].Author != "bob" || a[0].Share != 1 {
+		t.Errorf("Sub authors = %+v", a)
+	}
+
+	if o, _ := index.GetFileOwnership(db, "notes.txt"); o != nil {
+		t.Errorf("untracked file has ownership: %+v", o)
+	}
+
+	// Churn windows are measured from opts.Now.
+	scan()
+	summary, err = Analyze(dir, db, Options{Now: time.Now().AddDate(0, 2, 0)})
+	if err != nil {
+		t.Fatalf("second Analyze() error: %v", err)
+	}
+	if summary.Blamed != 0 || summary.Reused == 0 {
+		t.Errorf("unchanged files were blamed again: %+v", summary)
+	}
+	o, _ = index.GetFileOwnership(db, "calc.go")
+	if o == nil || o.Churn.Days30 != 0 || o.Churn.Days90 != 2 || o.Authors[0].Author != "bob" {
+		t.Errorf("after second pass = %+v", o)
+	}
+}
+
+func TestAnalyzeNotGitRepo(t *testing.T) {
+	db, err := index.Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := Analyze(t.TempDir(), db, Options{}); err != ErrNotGitRepo {
+		t.Errorf("Analyze() error = %v, want ErrNotGitRepo", err)
+	}
+}
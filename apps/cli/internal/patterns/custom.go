@@ -0,0 +1,363 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/jsonc"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/validate"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/schemas"
+)
+
+// CustomDetectorSpec is a user-defined detector as written in
+// .palace/patterns/*.jsonc. Match selects the code a convention applies to;
+// matches that fail Require, or every match when Forbid is set, are outliers.
+type CustomDetectorSpec struct {
+	SchemaVersion string       `json:"schemaVersion"`
+	Kind          string       `json:"kind"`
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description,omitempty"`
+	Category      string       `json:"category"`
+	Subcategory   string       `json:"subcategory,omitempty"`
+	Languages     []string     `json:"languages,omitempty"`
+	Paths         []string     `json:"paths,omitempty"`
+	Exclude       []string     `json:"exclude,omitempty"`
+	Match         MatcherSpec  `json:"match"`
+	Require       *MatcherSpec `json:"require,omitempty"`
+	Forbid        bool         `json:"forbid,omitempty"`
+	Message       string       `json:"message,omitempty"`
+}
+
+// MatcherSpec is a regex or a tree-sitter query. Capture names the query
+// capture to report; it defaults to @match, or the first capture.
+type MatcherSpec struct {
+	Regex   string `json:"regex,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Capture string `json:"capture,omitempty"`
+}
+
+// CustomDetector runs a CustomDetectorSpec. Queries are compiled once per
+// language when the detector is created.
+type CustomDetector struct {
+	BaseDetector
+	spec         CustomDetectorSpec
+	matchRegex   *regexp.Regexp
+	requireRegex *regexp.Regexp
+	matchQuery   map[string]*sitter.Query
+	requireQuery map[string]*sitter.Query
+}
+
+// NewCustomDetector compiles spec into a detector.
+func NewCustomDetector(spec CustomDetectorSpec) (*CustomDetector, error) {
+	if spec.ID == "" || spec.Name == "" {
+		return nil, errors.New("id and name are required")
+	}
+	if spec.Forbid && spec.Require != nil {
+		return nil, errors.New("forbid and require cannot be combined")
+	}
+	if spec.Require != nil && spec.Require.Query != "" && spec.Match.Query == "" {
+		return nil, errors.New("require.query needs match.query")
+	}
+	subcategory := spec.Subcategory
+	if subcategory == "" {
+		subcategory = spec.ID[strings.LastIndex(spec.ID, "/")+1:]
+	}
+
+	d := &CustomDetector{
+		BaseDetector: NewBaseDetector(spec.ID, PatternCategory(spec.Category), subcategory,
+			spec.Name, spec.Description, spec.Languages),
+		spec: spec,
+	}
+	for _, glob := range append(append([]string(nil), spec.Paths...), spec.Exclude...) {
+		if !doublestar.ValidatePattern(glob) {
+			return nil, fmt.Errorf("invalid glob %q", glob)
+		}
+	}
+
+	var err error
+	if d.matchRegex, d.matchQuery, err = compileMatcher(&spec.Match, spec.Languages); err != nil {
+		return nil, fmt.Errorf("match: %w", err)
+	}
+	if spec.Require != nil {
+		if d.requireRegex, d.requireQuery, err = compileMatcher(spec.Require, spec.Languages); err != nil {
+			return nil, fmt.Errorf("require: %w", err)
+		}
+	}
+	return d, nil
+}
+
+func compileMatcher(m *MatcherSpec, languages []string) (*regexp.Regexp, map[string]*sitter.Query, error) {
+	if m.Regex != "" {
+		re, err := regexp.Compile(m.Regex)
+		return re, nil, err
+	}
+	if m.Query == "" {
+		return nil, nil, errors.New("regex or query is required")
+	}
+	if len(languages) == 0 {
+		return nil, nil, errors.New("languages are required with a query")
+	}
+	queries := make(map[string]*sitter.Query, len(languages))
+	for _, lang := range languages {
+		grammar := analysis.TreeSitterLanguage(analysis.Language(lang))
+		if grammar == nil {
+			return nil, nil, fmt.Errorf("no tree-sitter grammar for %s", lang)
+		}
+		q, err := sitter.NewQuery([]byte(m.Query), grammar)
+		if err != nil {
+			return nil, nil, fmt.Errorf("query for %s: %w", lang, err)
+		}
+		queries[lang] = q
+	}
+	return nil, queries, nil
+}
+
+// customMatch is one piece of code selected by a detector's match.
+type customMatch struct {
+	lineStart, lineEnd int
+	text               string
+	node               *sitter.Node
+}
+
+// Detect implements the Detector interface.
+func (d *CustomDetector) Detect(ctx context.Context, dctx *DetectionContext) (*DetectionResult, error) {
+	if !d.inScope(dctx) {
+		return nil, nil
+	}
+
+	var matches []customMatch
+	var tree *sitter.Tree
+	if d.matchRegex != nil {
+		matches = d.regexMatches(dctx.FileContent)
+	} else {
+		query := d.matchQuery[dctx.File.Language]
+		if query == nil {
+			return nil, nil
+		}
+		parser := sitter.NewParser()
+		defer parser.Close()
+		parser.SetLanguage(analysis.TreeSitterLanguage(analysis.Language(dctx.File.Language)))
+		var err error
+		if tree, err = parser.ParseCtx(ctx, nil, dctx.FileContent); err != nil {
+			return nil, err
+		}
+		defer tree.Close()
+		matches = queryMatches(query, d.spec.Match.Capture, tree.RootNode(), dctx.FileContent)
+	}
+
+	var locations, outliers []Location
+	if d.spec.Forbid && len(matches) == 0 {
+		// A file free of forbidden code follows the convention.
+		lines := strings.Count(string(dctx.FileContent), "\n") + 1
+		locations = append(locations, Location{FilePath: dctx.File.Path, LineStart: 1, LineEnd: lines})
+	}
+	for i := range matches {
+		m := &matches[i]
+		loc := Location{
+			FilePath:  dctx.File.Path,
+			LineStart: m.lineStart,
+			LineEnd:   m.lineEnd,
+			Snippet:   snippet(m.text),
+		}
+		if d.spec.Forbid || !d.satisfied(m, dctx) {
+			loc.IsOutlier = true
+			loc.OutlierReason = d.outlierReason()
+			outliers = append(outliers, loc)
+			continue
+		}
+		locations = append(locations, loc)
+	}
+	if len(locations) == 0 && len(outliers) == 0 {
+		return nil, nil
+	}
+
+	total := len(matches)
+	if total == 0 {
+		total = len(locations)
+	}
+	return &DetectionResult{
+		Locations: locations,
+		Outliers:  outliers,
+		Confidence: ConfidenceFactors{
+			Frequency:   CalculateFrequencyScore(total, 10),
+			Consistency: float64(len(locations)) / float64(len(locations)+len(outliers)),
+			Spread:      0.5,
+			Age:         0.3,
+		},
+		Metadata: map[string]any{
+			"custom":  true,
+			"matches": len(matches),
+		},
+	}, nil
+}
+
+// inScope applies the path globs, which are matched against the
+// workspace-relative path.
+func (d *CustomDetector) inScope(dctx *DetectionContext) bool {
+	rel := dctx.File.Path
+	if dctx.WorkspaceRoot != "" {
+		if r, err := filepath.Rel(dctx.WorkspaceRoot, rel); err == nil {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, glob := range d.spec.Exclude {
+		if ok, _ := doublestar.Match(glob, rel); ok {
+			return false
+		}
+	}
+	if len(d.spec.Paths) == 0 {
+		return true
+	}
+	for _, glob := range d.spec.Paths {
+		if ok, _ := doublestar.Match(glob, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *CustomDetector) satisfied(m *customMatch, dctx *DetectionContext) bool {
+	switch {
+	case d.requireRegex != nil:
+		return d.requireRegex.MatchString(m.text)
+	case d.requireQuery != nil:
+		query := d.requireQuery[dctx.File.Language]
+		return query != nil && m.node != nil &&
+			len(queryMatches(query, d.spec.Require.Capture, m.node, dctx.FileContent)) > 0
+	}
+	return true
+}
+
+func (d *CustomDetector) outlierReason() string {
+	switch {
+	case d.spec.Message != "":
+		return d.spec.Message
+	case d.spec.Forbid:
+		return "Matches forbidden pattern of " + d.spec.Name
+	}
+	return "Does not follow " + d.spec.Name
+}
+
+func (d *CustomDetector) regexMatches(content []byte) []customMatch {
+	var matches []customMatch
+	for _, span := range d.matchRegex.FindAllIndex(content, -1) {
+		end := span[1]
+		if end > span[0] {
+			end--
+		}
+		matches = append(matches, customMatch{
+			lineStart: 1 + strings.Count(string(content[:span[0]]), "\n"),
+			lineEnd:   1 + strings.Count(string(content[:end]), "\n"),
+			text:      string(content[span[0]:span[1]]),
+		})
+	}
+	return matches
+}
+
+// queryMatches runs query on node and returns the captured nodes named
+// capture, or @match, or else the first capture of each match.
+func queryMatches(query *sitter.Query, capture string, node *sitter.Node, content []byte) []customMatch {
+	if capture == "" {
+		capture = "match"
+	}
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+	cursor.Exec(query, node)
+
+	var matches []customMatch
+	for {
+		m, ok := cursor.NextMatch()
+		if !ok {
+			break
+		}
+		m = cursor.FilterPredicates(m, content)
+		if len(m.Captures) == 0 {
+			continue
+		}
+		picked := m.Captures[0].Node
+		for _, c := range m.Captures {
+			if query.CaptureNameForId(c.Index) == capture {
+				picked = c.Node
+				break
+			}
+		}
+		matches = append(matches, customMatch{
+			lineStart: int(picked.StartPoint().Row) + 1,
+			lineEnd:   int(picked.EndPoint().Row) + 1,
+			text:      picked.Content(content),
+			node:      picked,
+		})
+	}
+	return matches
+}
+
+// snippet is the first line of text, trimmed to a readable length.
+func snippet(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	line = strings.TrimSpace(line)
+	if len(line) > 200 {
+		line = line[:200]
+	}
+	return line
+}
+
+// CustomDetectorsDir is where custom detectors live, relative to .palace.
+const CustomDetectorsDir = "patterns"
+
+// LoadCustomDetectors reads every .palace/patterns/*.jsonc file under root.
+// Files that fail schema validation or do not compile are reported in the
+// returned error and skipped; the remaining detectors are still returned.
+func LoadCustomDetectors(root string) ([]*CustomDetector, error) {
+	paths, err := filepath.Glob(filepath.Join(root, ".palace", CustomDetectorsDir, "*.jsonc"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var detectors []*CustomDetector
+	var problems []error
+	for _, path := range paths {
+		if err := validate.JSONC(path, schemas.PatternDetector); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		var spec CustomDetectorSpec
+		if err := jsonc.DecodeFile(path, &spec); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		d, err := NewCustomDetector(spec)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		detectors = append(detectors, d)
+	}
+	return detectors, errors.Join(problems...)
+}
+
+// WorkspaceRegistry returns a registry with the built-in detectors and the
+// custom detectors of the workspace at root. Detectors that fail to load or
+// clash with an existing ID are reported in the error; the registry is
+// usable either way.
+func WorkspaceRegistry(root string) (*Registry, error) {
+	reg := DefaultRegistry.Clone()
+	custom, err := LoadCustomDetectors(root)
+	problems := []error{err}
+	for _, d := range custom {
+		problems = append(problems, reg.Register(d))
+	}
+	return reg, errors.Join(problems...)
+}
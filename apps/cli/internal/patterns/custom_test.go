@@ -0,0 +1,173 @@
+package patterns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/analysis"
+)
+
+func detect(t *testing.T, d Detector, root, path, lang, content string) *DetectionResult {
+	t.Helper()
+	result, err := d.Detect(context.Background(), &DetectionContext{
+		File:          &analysis.FileAnalysis{Path: filepath.Join(root, path), Language: lang},
+		FileContent:   []byte(content),
+		WorkspaceRoot: root,
+	})
+	if err != nil {
+		t.Fatalf("Detect(%s) error = %v", path, err)
+	}
+	return result
+}
+
+func TestCustomDetector_ForbidRegex(t *testing.T) {
+	d, err := NewCustomDetector(CustomDetectorSpec{
+		ID:        "custom/no-console-log",
+		Name:      "No console.log",
+		Category:  string(CategoryLogging),
+		Languages: []string{"typescript"},
+		Paths:     []string{"src/**"},
+		Exclude:   []string{"**/*.test.ts"},
+		Match:     MatcherSpec{Regex: `console\.log\(`},
+		Forbid:    true,
+		Message:   "Use the logger instead of console.log",
+	})
+	if err != nil {
+		t.Fatalf("NewCustomDetector() error = %v", err)
+	}
+	if d.Subcategory() != "no-console-log" {
+		t.Errorf("subcategory = %q", d.Subcategory())
+	}
+
+	root := t.TempDir()
+	result := detect(t, d, root, "src/app.ts", "typescript", "const a = 1;\nconsole.log(a);\n")
+	if result == nil || len(result.Outliers) != 1 || len(result.Locations) != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	outlier := result.Outliers[0]
+	if outlier.LineStart != 2 || outlier.Snippet != "console.log(" || outlier.OutlierReason != "Use the logger instead of console.log" {
+		t.Errorf("outlier = %+v", outlier)
+	}
+
+	clean := detect(t, d, root, "src/util.ts", "typescript", "export const b = 2;\n")
+	if clean == nil || len(clean.Locations) != 1 || len(clean.Outliers) != 0 {
+		t.Errorf("clean file result = %+v", clean)
+	}
+
+	for _, path := range []string{"scripts/build.ts", "src/app.test.ts"} {
+		if r := detect(t, d, root, path, "typescript", "console.log(1)\n"); r != nil {
+			t.Errorf("%s is out of scope but got %+v", path, r)
+		}
+	}
+}
+
+func TestCustomDetector_RequireQuery(t *testing.T) {
+	d, err := NewCustomDetector(CustomDetectorSpec{
+		ID:        "custom/repo-context",
+		Name:      "Repository methods take a context",
+		Category:  string(CategoryDataAccess),
+		Languages: []string{"go"},
+		Match:     MatcherSpec{Query: `(method_declaration) @match`},
+		Require: &MatcherSpec{Query: `(method_declaration parameters: (parameter_list
+			(parameter_declaration type: (qualified_type) @t (#eq? @t "context.Context"))))`},
+	})
+	if err != nil {
+		t.Fatalf("NewCustomDetector() error = %v", err)
+	}
+
+	src := `package repo
+
+func (r *Repo) Get(ctx context.Context, id string) error {
+	return nil
+}
+
+func (r *Repo) List(limit int) error {
+	return nil
+}
+`
+	result := detect(t, d, t.TempDir(), "repo.go", "go", src)
+	if result == nil || len(result.Locations) != 1 || len(result.Outliers) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Locations[0].LineStart != 3 || result.Outliers[0].LineStart != 7 || result.Outliers[0].LineEnd != 9 {
+		t.Errorf("locations = %+v, outliers = %+v", result.Locations, result.Outliers)
+	}
+	if !strings.HasPrefix(result.Outliers[0].Snippet, "func (r *Repo) List") {
+		t.Errorf("snippet = %q", result.Outliers[0].Snippet)
+	}
+	if c := result.Confidence.Consistency; c != 0.5 {
+		t.Errorf("consistency = %v, want 0.5", c)
+	}
+}
+
+func TestNewCustomDetector_Invalid(t *testing.T) {
+	tests := map[string]CustomDetectorSpec{
+		"bad regex":              {ID: "x/a", Name: "a", Match: MatcherSpec{Regex: "("}},
+		"query without language": {ID: "x/b", Name: "b", Match: MatcherSpec{Query: "(identifier) @match"}},
+		"bad query":              {ID: "x/c", Name: "c", Languages: []string{"go"}, Match: MatcherSpec{Query: "(nope"}},
+		"forbid and require":     {ID: "x/d", Name: "d", Match: MatcherSpec{Regex: "a"}, Require: &MatcherSpec{Regex: "b"}, Forbid: true},
+	}
+	for name, spec := range tests {
+		if _, err := NewCustomDetector(spec); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestWorkspaceRegistry(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".palace", CustomDetectorsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"todo.jsonc": `{
+  // TODO comments must name an owner
+  "schemaVersion": "1.0.0",
+  "kind": "palace/pattern-detector",
+  "id": "custom/todo-owner",
+  "name": "TODOs have owners",
+  "category": "documentation",
+  "match": { "regex": "TODO[^\\n]*" },
+  "require": { "regex": "TODO\\(\\w+\\)" }
+}`,
+		// Loaded after todo.jsonc, so its ID is already taken.
+		"z-duplicate.jsonc": `{
+  "schemaVersion": "1.0.0",
+  "kind": "palace/pattern-detector",
+  "id": "custom/todo-owner",
+  "name": "Duplicate",
+  "category": "documentation",
+  "match": { "regex": "TODO" }
+}`,
+		"invalid.jsonc": `{"schemaVersion": "1.0.0", "kind": "palace/pattern-detector", "id": "custom/x", "name": "x", "category": "nope", "match": {}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reg, err := WorkspaceRegistry(root)
+	if err == nil {
+		t.Fatal("expected errors for the invalid and duplicate detectors")
+	}
+	if !strings.Contains(err.Error(), "invalid.jsonc") || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("error = %v", err)
+	}
+	d := reg.Get("custom/todo-owner")
+	if d == nil {
+		t.Fatal("custom detector not registered")
+	}
+	if DefaultRegistry.Has("custom/todo-owner") {
+		t.Error("custom detector leaked into the default registry")
+	}
+
+	result := detect(t, d, root, "main.go", "go", "// TODO(ana): fix\n// TODO fix\n")
+	if result == nil || len(result.Locations) != 1 || len(result.Outliers) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
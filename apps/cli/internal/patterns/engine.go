@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -111,7 +112,7 @@ func (e *Engine) Scan(ctx context.Context, files []string) (*ScanResult, error)
 	result.DetectorsRun = len(detectors)
 
 	// Run detection
-	patterns, errs := e.runDetectors(ctx, detectors)
+	patterns, errs := e.runDetectors(ctx, detectors, e.files)
 	result.Patterns = patterns
 	result.Errors = errs
 	result.Duration = time.Since(start)
@@ -119,6 +120,81 @@ func (e *Engine) Scan(ctx context.Context, files []string) (*ScanResult, error)
 	return result, nil
 }
 
+// ScanChanged re-parses the given files and runs detection on them only,
+// keeping the rest of the workspace from earlier scans as context for
+// detectors that compare files. Files that no longer exist are dropped. The
+// first call on a fresh engine parses the whole workspace.
+func (e *Engine) ScanChanged(ctx context.Context, changed []string) (*ScanResult, error) {
+	start := time.Now()
+	result := &ScanResult{}
+
+	e.mu.Lock()
+	loaded := len(e.fileMap) > 0
+	e.mu.Unlock()
+	if !loaded {
+		files, err := CollectFiles(e.workspaceRoot, nil)
+		if err != nil {
+			return nil, fmt.Errorf("collect files: %w", err)
+		}
+		if err := e.parseFiles(ctx, files); err != nil {
+			return nil, fmt.Errorf("parse files: %w", err)
+		}
+	}
+
+	targets := e.reparseFiles(changed)
+	result.FilesScanned = len(targets)
+
+	detectors := e.getDetectors()
+	result.DetectorsRun = len(detectors)
+
+	patterns, errs := e.runDetectors(ctx, detectors, targets)
+	result.Patterns = patterns
+	result.Errors = errs
+	result.Duration = time.Since(start)
+
+	return result, nil
+}
+
+// reparseFiles refreshes the parsed state of the given files and returns the
+// analyses of those that still exist.
+func (e *Engine) reparseFiles(files []string) []*analysis.FileAnalysis {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var targets []*analysis.FileAnalysis
+	for _, filePath := range files {
+		delete(e.fileMap, filePath)
+		delete(e.contents, filePath)
+
+		lang := analysis.DetectLanguage(filePath)
+		if lang == analysis.LangUnknown || (len(e.config.Languages) > 0 && !e.languageAllowed(string(lang))) {
+			continue
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		fa, err := e.parserReg.Parse(content, filePath)
+		if err != nil {
+			continue
+		}
+		e.fileMap[filePath] = fa
+		e.contents[filePath] = content
+		targets = append(targets, fa)
+	}
+
+	paths := make([]string, 0, len(e.fileMap))
+	for path := range e.fileMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	e.files = make([]*analysis.FileAnalysis, 0, len(paths))
+	for _, path := range paths {
+		e.files = append(e.files, e.fileMap[path])
+	}
+	return targets
+}
+
 // parseFiles parses all files and builds the context.
 func (e *Engine) parseFiles(ctx context.Context, files []string) error {
 	e.mu.Lock()
@@ -210,7 +286,7 @@ func (e *Engine) getDetectors() []Detector {
 // runDetectors executes all detectors and collects results.
 //
 //nolint:gocognit // concurrent detection logic is inherently complex
-func (e *Engine) runDetectors(ctx context.Context, detectors []Detector) ([]Pattern, []error) {
+func (e *Engine) runDetectors(ctx context.Context, detectors []Detector, targets []*analysis.FileAnalysis) ([]Pattern, []error) {
 	var allPatterns []Pattern
 	var allErrors []error
 	var mu sync.Mutex
@@ -221,7 +297,7 @@ func (e *Engine) runDetectors(ctx context.Context, detectors []Detector) ([]Patt
 		file     *analysis.FileAnalysis
 	}
 
-	jobs := make(chan work, len(detectors)*len(e.files))
+	jobs := make(chan work, len(detectors)*len(targets))
 	var wg sync.WaitGroup
 
 	// Start workers
@@ -286,7 +362,7 @@ func (e *Engine) runDetectors(ctx context.Context, detectors []Detector) ([]Patt
 
 	// Queue jobs
 	for _, detector := range detectors {
-		for _, file := range e.files {
+		for _, file := range targets {
 			jobs <- work{detector: detector, file: file}
 		}
 	}
@@ -469,6 +545,42 @@ func (e *Engine) SaveResults(_ context.Context, patterns []Pattern) error {
 	return nil
 }
 
+// SaveFileResults persists the results of ScanChanged. Locations in the
+// scanned files replace the ones stored for them, including locations of
+// patterns no longer found there; locations in other files and the
+// confidence of known patterns, which was computed over the whole workspace,
+// are left as they are.
+func (e *Engine) SaveFileResults(ctx context.Context, patterns []Pattern, files []string) error {
+	if err := e.memory.DeletePatternLocationsInFiles(files); err != nil {
+		return fmt.Errorf("delete pattern locations: %w", err)
+	}
+
+	for i := range patterns {
+		p := &patterns[i]
+		existing, err := e.findExistingPattern(p.DetectorID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := e.SaveResults(ctx, patterns[i:i+1]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		existing.LastSeen = time.Now().UTC()
+		existing.UpdatedAt = time.Now().UTC()
+		if err := e.memory.UpdatePattern(*existing); err != nil {
+			return fmt.Errorf("update pattern %s: %w", existing.ID, err)
+		}
+		if err := e.saveLocations(existing.ID, p.Locations, p.Outliers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // findExistingPattern looks up a pattern by detector ID.
 func (e *Engine) findExistingPattern(detectorID string) (*memory.Pattern, error) {
 	patterns, err := e.memory.GetPatterns(memory.PatternFilters{
@@ -0,0 +1,79 @@
+package patterns
+
+import (
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/findings"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// FromMemory rebuilds a pattern from its stored row and outlier locations.
+// Conforming locations are not loaded; reports only need the outliers.
+func FromMemory(p *memory.Pattern, outliers []memory.PatternLocation) Pattern {
+	pattern := Pattern{
+		ID:          p.ID,
+		Category:    p.Category,
+		Subcategory: p.Subcategory,
+		Name:        p.Name,
+		Description: p.Description,
+		DetectorID:  p.DetectorID,
+		Confidence:  p.Confidence,
+		Status:      PatternStatus(p.Status),
+		Authority:   p.Authority,
+		LearningID:  p.LearningID,
+		Metadata:    p.Metadata,
+		FirstSeen:   p.FirstSeen,
+		LastSeen:    p.LastSeen,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	for i := range outliers {
+		loc := &outliers[i]
+		pattern.Outliers = append(pattern.Outliers, Location{
+			ID:            loc.ID,
+			PatternID:     loc.PatternID,
+			FilePath:      loc.FilePath,
+			LineStart:     loc.LineStart,
+			LineEnd:       loc.LineEnd,
+			Snippet:       loc.Snippet,
+			IsOutlier:     true,
+			OutlierReason: loc.OutlierReason,
+			CreatedAt:     loc.CreatedAt,
+		})
+	}
+	return pattern
+}
+
+// OutlierFindings reports each outlier of p under the detector's rule ID.
+// Outliers of approved patterns are warnings, since the convention is
+// enforced; those of merely discovered patterns are notes. Ignored patterns
+// report nothing.
+func OutlierFindings(p *Pattern) []findings.Finding {
+	if p.Status == StatusIgnored {
+		return nil
+	}
+	level := findings.LevelNote
+	if p.Status == StatusApproved {
+		level = findings.LevelWarning
+	}
+
+	list := make([]findings.Finding, 0, len(p.Outliers))
+	for i := range p.Outliers {
+		loc := &p.Outliers[i]
+		message := loc.OutlierReason
+		if message == "" {
+			message = "Deviates from pattern: " + p.Name
+		}
+		list = append(list, findings.Finding{
+			RuleID:      p.DetectorID,
+			RuleName:    p.Name,
+			Description: p.Description,
+			Level:       level,
+			Message:     message,
+			Location:    findings.Location{Path: loc.FilePath, LineStart: loc.LineStart, LineEnd: loc.LineEnd},
+			Properties: map[string]any{
+				"patternId":  p.ID,
+				"confidence": p.Confidence,
+			},
+		})
+	}
+	return list
+}
@@ -41,6 +41,18 @@ func (r *Registry) MustRegister(d Detector) {
 	}
 }
 
+// Clone returns a new registry holding the same detectors.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clone := NewRegistry()
+	for id, d := range r.detectors {
+		clone.detectors[id] = d
+	}
+	return clone
+}
+
 // Get returns a detector by ID, or nil if not found.
 func (r *Registry) Get(id string) Detector {
 	r.mu.RLock()
@@ -2,12 +2,11 @@
 package playbook
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/jsonc"
 	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
 )
@@ -23,6 +22,7 @@ type ExecutionState struct {
 	CompletedRooms []string               `json:"completedRooms"`
 	CompletedSteps map[string][]int       `json:"completedSteps"` // room -> step indices
 	Evidence       map[string]interface{} `json:"evidence"`
+	Verification   []VerificationResult   `json:"verification,omitempty"`
 	StartedAt      time.Time              `json:"startedAt"`
 	UpdatedAt      time.Time              `json:"updatedAt"`
 	Status         string                 `json:"status"` // "running", "paused", "completed", "failed"
@@ -31,10 +31,12 @@ type ExecutionState struct {
 
 // VerificationResult holds the result of a verification check.
 type VerificationResult struct {
-	Name   string `json:"name"`
-	Status string `json:"status"` // "passed", "failed", "skipped", "pending"
-	Detail string `json:"detail,omitempty"`
-	Error  string `json:"error,omitempty"`
+	Name    string `json:"name"`
+	Status  string `json:"status"` // "passed", "failed", "skipped", "pending"
+	Detail  string `json:"detail,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Command string `json:"command,omitempty"`
+	Output  string `json:"output,omitempty"` // tail of the command output
 }
 
 // StepGuidance provides guidance for completing the current step.
@@ -69,6 +71,20 @@ func NewExecutor(rootPath string, rooms []model.Room) *Executor {
 	}
 }
 
+// LoadRooms reads the room manifests in .palace/rooms/, skipping invalid files.
+func LoadRooms(rootPath string) []model.Room {
+	entries, _ := filepath.Glob(filepath.Join(rootPath, ".palace", "rooms", "*.jsonc"))
+	rooms := make([]model.Room, 0, len(entries))
+	for _, path := range entries {
+		var room model.Room
+		if err := jsonc.DecodeFile(path, &room); err != nil {
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
 // ListPlaybooks returns all available playbooks.
 func (e *Executor) ListPlaybooks() ([]model.Playbook, error) {
 	playbooksDir := filepath.Join(e.rootPath, ".palace", "playbooks")
@@ -217,6 +233,13 @@ func (e *Executor) AdvanceRoom(state *ExecutionState) error {
 	return nil
 }
 
+// Finish marks a run as completed, whether or not every step was advanced.
+func (e *Executor) Finish(state *ExecutionState) {
+	state.Status = "completed"
+	state.CurrentRoom = nil
+	state.UpdatedAt = time.Now().UTC()
+}
+
 // CollectEvidence stores evidence for a required evidence ID.
 func (e *Executor) CollectEvidence(state *ExecutionState, evidenceID string, data interface{}) error {
 	state.Evidence[evidenceID] = data
@@ -224,12 +247,24 @@ func (e *Executor) CollectEvidence(state *ExecutionState, evidenceID string, dat
 	return nil
 }
 
-// RunVerification executes verification checks.
-func (e *Executor) RunVerification(state *ExecutionState) ([]VerificationResult, error) {
+// RunVerification executes verification checks. Checks backed by the
+// lint.run and tests.run capabilities run the project profile's command when
+// opts.Execute is set; every result is also stored as evidence on state.
+func (e *Executor) RunVerification(state *ExecutionState, opts VerifyOptions) ([]VerificationResult, error) {
 	if state.Playbook == nil {
 		return nil, fmt.Errorf("no playbook loaded")
 	}
 
+	var capabilities map[string]model.Capability
+	var guardrails config.Guardrails
+	if opts.Execute {
+		var err error
+		if capabilities, err = e.loadCapabilities(); err != nil {
+			return nil, err
+		}
+		guardrails = config.LoadGuardrails(e.rootPath)
+	}
+
 	results := make([]VerificationResult, 0, len(state.Playbook.Verification))
 
 	for _, check := range state.Playbook.Verification {
@@ -240,21 +275,32 @@ func (e *Executor) RunVerification(state *ExecutionState) ([]VerificationResult,
 
 		// Execute capability-based verification
 		switch check.Capability {
-		case "lint.run":
-			// TODO: Run palace lint
-			result.Status = "skipped"
-			result.Detail = "Run 'palace lint' to verify"
-		case "tests.run":
-			// TODO: Run tests
-			result.Status = "skipped"
-			result.Detail = "Run project tests to verify"
+		case "lint.run", "tests.run":
+			if !opts.Execute {
+				result.Status = "skipped"
+				result.Detail = fmt.Sprintf("Running %s requires human mode", check.Capability)
+				break
+			}
+			capability, ok := capabilities[check.Capability]
+			if !ok {
+				result.Status = "skipped"
+				result.Detail = fmt.Sprintf("Capability %s is not defined in project-profile.json", check.Capability)
+				break
+			}
+			e.runCapability(capability, guardrails, opts, &result)
 		default:
 			result.Status = "skipped"
 			result.Detail = fmt.Sprintf("Manual verification: %s", check.Expectation)
 		}
 
 		results = append(results, result)
+		if state.Evidence == nil {
+			state.Evidence = make(map[string]interface{})
+		}
+		state.Evidence[verificationEvidencePrefix+check.Name] = result
 	}
+	state.Verification = results
+	state.UpdatedAt = time.Now().UTC()
 
 	return results, nil
 }
@@ -292,47 +338,17 @@ func (e *Executor) GetProgress(state *ExecutionState) float64 {
 	return float64(completedSteps) / float64(totalSteps) * 100
 }
 
-// SaveState persists execution state to disk.
-func (e *Executor) SaveState(state *ExecutionState) error {
-	outputDir := filepath.Join(e.rootPath, ".palace", "outputs")
-	if err := os.MkdirAll(outputDir, 0o755); err != nil {
-		return err
-	}
-
-	path := filepath.Join(outputDir, "playbook-state.json")
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o600)
-}
-
-// LoadState loads persisted execution state.
-func (e *Executor) LoadState() (*ExecutionState, error) {
-	path := filepath.Join(e.rootPath, ".palace", "outputs", "playbook-state.json")
-	var state ExecutionState
-	if err := jsonc.DecodeFile(path, &state); err != nil {
-		return nil, err
+// Resume reattaches the current room to a state loaded from storage.
+func (e *Executor) Resume(state *ExecutionState) {
+	if state.Playbook == nil || state.Status == "completed" {
+		return
 	}
-
-	// Reload current room from rooms map
 	if state.CurrentRoomIdx < len(state.Playbook.Rooms) {
 		roomName := state.Playbook.Rooms[state.CurrentRoomIdx]
 		if room, ok := e.rooms[roomName]; ok {
 			state.CurrentRoom = &room
 		}
 	}
-
-	return &state, nil
-}
-
-// ClearState removes persisted execution state.
-func (e *Executor) ClearState() error {
-	path := filepath.Join(e.rootPath, ".palace", "outputs", "playbook-state.json")
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		return err
-	}
-	return nil
 }
 
 // generateExecutionID generates a unique execution ID.
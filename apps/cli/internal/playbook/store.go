@@ -0,0 +1,212 @@
+package playbook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+)
+
+// ErrRunNotFound is returned when a playbook run ID does not exist.
+var ErrRunNotFound = errors.New("playbook run not found")
+
+// CLISession owns runs started from the command line without --session, so
+// they never collide with MCP clients that have no session.
+const CLISession = "cli"
+
+// Store persists playbook runs in the playbook_runs table of memory.db.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a playbook run store. The table is created by the memory
+// schema migrations, so db must come from memory.Open.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// RunFilter narrows List results. Empty fields match everything.
+type RunFilter struct {
+	SessionID string
+	Status    string
+	Limit     int
+}
+
+// runTimeFormat has a fixed width so timestamps sort correctly as text.
+const runTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+const runColumns = `id, playbook_name, session_id, status, playbook, current_room_idx, current_step_idx,
+	completed_rooms, completed_steps, evidence, verification, started_at, updated_at`
+
+// Save inserts or updates a run.
+func (s *Store) Save(state *ExecutionState) error {
+	// Nil collections are stored as empty JSON values rather than null.
+	var rooms any = state.CompletedRooms
+	if state.CompletedRooms == nil {
+		rooms = []string{}
+	}
+	var steps any = state.CompletedSteps
+	if state.CompletedSteps == nil {
+		steps = map[string][]int{}
+	}
+	var evidence any = state.Evidence
+	if state.Evidence == nil {
+		evidence = map[string]interface{}{}
+	}
+	var verification any = state.Verification
+	if state.Verification == nil {
+		verification = []VerificationResult{}
+	}
+
+	encoded := make([]string, 0, 5)
+	for _, v := range []any{state.Playbook, rooms, steps, evidence, verification} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshal playbook run: %w", err)
+		}
+		encoded = append(encoded, string(data))
+	}
+
+	_, err := s.db.ExecContext(context.Background(), `
+		INSERT INTO playbook_runs (`+runColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			session_id = excluded.session_id,
+			status = excluded.status,
+			playbook = excluded.playbook,
+			current_room_idx = excluded.current_room_idx,
+			current_step_idx = excluded.current_step_idx,
+			completed_rooms = excluded.completed_rooms,
+			completed_steps = excluded.completed_steps,
+			evidence = excluded.evidence,
+			verification = excluded.verification,
+			updated_at = excluded.updated_at
+	`, state.ID, state.PlaybookName, state.SessionID, state.Status, encoded[0],
+		state.CurrentRoomIdx, state.CurrentStepIdx, encoded[1], encoded[2], encoded[3], encoded[4],
+		state.StartedAt.UTC().Format(runTimeFormat), state.UpdatedAt.UTC().Format(runTimeFormat))
+	if err != nil {
+		return fmt.Errorf("save playbook run: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a run by ID.
+func (s *Store) Get(id string) (*ExecutionState, error) {
+	row := s.db.QueryRowContext(context.Background(), `SELECT `+runColumns+` FROM playbook_runs WHERE id = ?`, id)
+	state, err := scanRun(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get playbook run: %w", err)
+	}
+	return state, nil
+}
+
+// List returns runs matching filter, most recently updated first.
+func (s *Store) List(filter RunFilter) ([]*ExecutionState, error) {
+	query := `SELECT ` + runColumns + ` FROM playbook_runs WHERE 1=1`
+	args := []any{}
+	if filter.SessionID != "" {
+		query += ` AND session_id = ?`
+		args = append(args, filter.SessionID)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY updated_at DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query playbook runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*ExecutionState
+	for rows.Next() {
+		state, err := scanRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan playbook run: %w", err)
+		}
+		runs = append(runs, state)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate playbook runs: %w", err)
+	}
+	return runs, nil
+}
+
+// ActiveForSession returns the session's most recent running run, or nil.
+func (s *Store) ActiveForSession(sessionID string) (*ExecutionState, error) {
+	return s.latest(sessionID, `AND status = 'running' ORDER BY updated_at DESC`)
+}
+
+// LatestForSession returns the session's running run if it has one, otherwise
+// its most recently updated run, or nil.
+func (s *Store) LatestForSession(sessionID string) (*ExecutionState, error) {
+	return s.latest(sessionID, `ORDER BY status = 'running' DESC, updated_at DESC`)
+}
+
+func (s *Store) latest(sessionID, clause string) (*ExecutionState, error) {
+	query := `SELECT ` + runColumns + ` FROM playbook_runs WHERE session_id = ? ` + clause + ` LIMIT 1`
+	state, err := scanRun(s.db.QueryRowContext(context.Background(), query, sessionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get playbook run: %w", err)
+	}
+	return state, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRun reads a run. CurrentRoom is not stored; call Executor.Resume to
+// attach it.
+func scanRun(row rowScanner) (*ExecutionState, error) {
+	var state ExecutionState
+	var playbookJSON, roomsJSON, stepsJSON, evidenceJSON, verificationJSON, startedAt, updatedAt string
+	if err := row.Scan(&state.ID, &state.PlaybookName, &state.SessionID, &state.Status, &playbookJSON,
+		&state.CurrentRoomIdx, &state.CurrentStepIdx, &roomsJSON, &stepsJSON, &evidenceJSON, &verificationJSON,
+		&startedAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	state.Playbook = &model.Playbook{}
+	for _, field := range []struct {
+		data string
+		dest any
+	}{
+		{playbookJSON, state.Playbook},
+		{roomsJSON, &state.CompletedRooms},
+		{stepsJSON, &state.CompletedSteps},
+		{evidenceJSON, &state.Evidence},
+		{verificationJSON, &state.Verification},
+	} {
+		if err := json.Unmarshal([]byte(field.data), field.dest); err != nil {
+			return nil, fmt.Errorf("decode playbook run %s: %w", state.ID, err)
+		}
+	}
+	if state.CompletedSteps == nil {
+		state.CompletedSteps = make(map[string][]int)
+	}
+	if state.Evidence == nil {
+		state.Evidence = make(map[string]interface{})
+	}
+
+	state.StartedAt, _ = time.Parse(runTimeFormat, startedAt)
+	state.UpdatedAt, _ = time.Parse(runTimeFormat, updatedAt)
+	return &state, nil
+}
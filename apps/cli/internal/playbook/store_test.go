@@ -0,0 +1,84 @@
+package playbook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+func TestStoreSaveAndLoadRuns(t *testing.T) {
+	root, executor := setupTestPlaybook(t)
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	store := NewStore(mem.DB())
+
+	// Two sessions run the same playbook concurrently.
+	first, _ := executor.Start("test-playbook")
+	first.SessionID = "sess_a"
+	second, _ := executor.Start("test-playbook")
+	second.ID = first.ID + "_b"
+	second.SessionID = "sess_b"
+	for _, state := range []*ExecutionState{first, second} {
+		if err := store.Save(state); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	executor.AdvanceStep(first)
+	executor.CollectEvidence(first, "artifact-a", "found it")
+	if _, err := executor.RunVerification(first, VerifyOptions{}); err != nil {
+		t.Fatalf("RunVerification() error: %v", err)
+	}
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save() update error: %v", err)
+	}
+
+	loaded, err := store.ActiveForSession("sess_a")
+	if err != nil || loaded == nil {
+		t.Fatalf("ActiveForSession() = %v, %v", loaded, err)
+	}
+	if loaded.ID != first.ID || loaded.CurrentStepIdx != 1 || len(loaded.CompletedSteps["room1"]) != 1 {
+		t.Errorf("loaded run = %+v, want step progress of %s", loaded, first.ID)
+	}
+	if loaded.Evidence["artifact-a"] != "found it" {
+		t.Errorf("evidence = %v", loaded.Evidence)
+	}
+	if len(loaded.Verification) != 1 || loaded.Verification[0].Status != "skipped" {
+		t.Errorf("verification = %+v", loaded.Verification)
+	}
+	if loaded.Playbook == nil || loaded.Playbook.Name != "test-playbook" {
+		t.Errorf("playbook = %+v", loaded.Playbook)
+	}
+	executor.Resume(loaded)
+	if loaded.CurrentRoom == nil || loaded.CurrentRoom.Name != "room1" {
+		t.Errorf("Resume() current room = %+v", loaded.CurrentRoom)
+	}
+
+	other, err := store.ActiveForSession("sess_b")
+	if err != nil || other == nil || other.ID != second.ID || other.CurrentStepIdx != 0 {
+		t.Errorf("ActiveForSession(sess_b) = %+v, %v", other, err)
+	}
+
+	executor.Finish(first)
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if active, _ := store.ActiveForSession("sess_a"); active != nil {
+		t.Errorf("ActiveForSession() after Finish = %+v, want nil", active)
+	}
+	if latest, _ := store.LatestForSession("sess_a"); latest == nil || latest.Status != "completed" {
+		t.Errorf("LatestForSession() = %+v, want completed run", latest)
+	}
+
+	runs, err := store.List(RunFilter{Status: "running"})
+	if err != nil || len(runs) != 1 || runs[0].ID != second.ID {
+		t.Errorf("List(running) = %v, %v", runs, err)
+	}
+	if _, err := store.Get("exec_missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrRunNotFound", err)
+	}
+}
@@ -0,0 +1,169 @@
+package playbook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/fsutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/model"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/project"
+)
+
+const (
+	// DefaultVerifyTimeout bounds a single verification command.
+	DefaultVerifyTimeout = 5 * time.Minute
+	// DefaultVerifyOutputLimit is how much command output is kept per check.
+	DefaultVerifyOutputLimit = 4096
+
+	// verificationEvidencePrefix namespaces verification results in ExecutionState.Evidence.
+	verificationEvidencePrefix = "verification:"
+)
+
+// VerifyOptions controls how RunVerification handles capability checks.
+type VerifyOptions struct {
+	// Execute runs capability commands from the project profile. When false,
+	// capability checks are reported as skipped.
+	Execute     bool
+	Timeout     time.Duration // per command; DefaultVerifyTimeout when zero
+	OutputLimit int           // bytes of output kept; DefaultVerifyOutputLimit when zero
+}
+
+// loadCapabilities returns the capabilities from .palace/project-profile.json,
+// falling back to detection when the workspace has no profile.
+func (e *Executor) loadCapabilities() (map[string]model.Capability, error) {
+	path := filepath.Join(e.rootPath, ".palace", "project-profile.json")
+	profile, err := model.LoadProjectProfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		profile = project.BuildProfile(e.rootPath)
+	} else if err != nil {
+		return nil, fmt.Errorf("load project profile: %w", err)
+	}
+	return profile.Capabilities, nil
+}
+
+// runCapability executes a capability command inside the workspace and
+// records the outcome on result.
+func (e *Executor) runCapability(capability model.Capability, guardrails config.Guardrails, opts VerifyOptions, result *VerificationResult) {
+	command := strings.TrimSpace(capability.Command)
+	if command == "" || strings.HasSuffix(command, "not configured") {
+		result.Status = "skipped"
+		result.Detail = "Capability is not configured in project-profile.json"
+		return
+	}
+	result.Command = command
+
+	dir, err := e.capabilityDir(capability.WorkingDirectory, guardrails)
+	if err != nil {
+		result.Status = "skipped"
+		result.Error = err.Error()
+		return
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultVerifyTimeout
+	}
+	limit := opts.OutputLimit
+	if limit <= 0 {
+		limit = DefaultVerifyOutputLimit
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range capability.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	out := &tailBuffer{limit: limit}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	killProcessGroup(cmd)
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start).Round(time.Millisecond)
+	result.Output = out.String()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("timed out after %v", timeout)
+	case err != nil:
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("`%s` failed after %v: %v", command, elapsed, err)
+	default:
+		result.Status = "passed"
+		result.Detail = fmt.Sprintf("`%s` passed in %v", command, elapsed)
+	}
+}
+
+// capabilityDir resolves a capability working directory. It must stay inside
+// the workspace and outside guarded paths.
+func (e *Executor) capabilityDir(workingDir string, guardrails config.Guardrails) (string, error) {
+	root, err := filepath.Abs(e.rootPath)
+	if err != nil {
+		return "", err
+	}
+	if workingDir == "" {
+		return root, nil
+	}
+	dir := workingDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	rel, err := filepath.Rel(root, filepath.Clean(dir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %q is outside the workspace", workingDir)
+	}
+	if rel != "." && fsutil.MatchesGuardrail(rel, guardrails) {
+		return "", fmt.Errorf("working directory %q is protected by guardrails", workingDir)
+	}
+	return dir, nil
+}
+
+// shellCommand runs command through the platform shell so profile commands
+// can use operators such as "||".
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) //nolint:gosec // G204: command comes from the project profile
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command comes from the project profile
+}
+
+// tailBuffer keeps the last limit bytes written to it, where test and lint
+// failures are usually reported.
+type tailBuffer struct {
+	limit     int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	t.buf.Write(p)
+	if over := t.buf.Len() - t.limit; over > 0 {
+		t.buf.Next(over)
+		t.truncated = true
+	}
+	return n, nil
+}
+
+func (t *tailBuffer) String() string {
+	if t.truncated {
+		return "... (truncated)\n" + t.buf.String()
+	}
+	return t.buf.String()
+}
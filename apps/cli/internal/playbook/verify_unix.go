@@ -0,0 +1,17 @@
+//go:build !windows
+
+package playbook
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup makes cancellation kill the whole process group, so
+// commands started by the shell do not outlive a timeout.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package playbook
+
+import "os/exec"
+
+// killProcessGroup is a no-op on Windows; cancellation kills the shell and
+// WaitDelay bounds how long its children can hold the output pipes.
+func killProcessGroup(_ *exec.Cmd) {}
@@ -0,0 +1,101 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/sarif"
+)
+
+// SARIF rule IDs.
+const (
+	RuleFileRisk   = "risk/file"
+	RuleSymbolRisk = "risk/symbol"
+)
+
+// WriteJSON writes the report to path.
+func (r *Report) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// SARIF converts the medium- and high-risk files and symbols into SARIF
+// results. High risk is reported as an error, medium as a warning.
+func (r *Report) SARIF(toolVersion string) *sarif.Log {
+	log := sarif.NewLog(toolVersion)
+	log.AddRule(sarif.Rule{
+		ID:                   RuleFileRisk,
+		Name:                 "RiskyFileChange",
+		ShortDescription:     &sarif.Message{Text: "Changed file with a high change-risk score"},
+		DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelWarning},
+	})
+	log.AddRule(sarif.Rule{
+		ID:                   RuleSymbolRisk,
+		Name:                 "RiskySymbolChange",
+		ShortDescription:     &sarif.Message{Text: "Changed symbol that many callers depend on"},
+		DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelWarning},
+	})
+
+	for i := range r.Files {
+		f := &r.Files[i]
+		if f.Level == LevelLow {
+			continue
+		}
+		log.AddResult(sarif.Result{
+			RuleID:    RuleFileRisk,
+			Level:     sarifLevel(f.Level),
+			Message:   sarif.Message{Text: fmt.Sprintf("%s change risk (%.0f/100): %s", f.Level, f.Score, f.Explain())},
+			Locations: []sarif.Location{sarif.FileLocation(f.Path, 0, 0)},
+			Properties: map[string]any{
+				"score":       f.Score,
+				"postmortems": f.Postmortems,
+			},
+		})
+		for _, s := range f.Symbols {
+			if s.Level == LevelLow {
+				continue
+			}
+			log.AddResult(sarif.Result{
+				RuleID: RuleSymbolRisk,
+				Level:  sarifLevel(s.Level),
+				Message: sarif.Message{Text: fmt.Sprintf("%s %s changed: centrality %.2f, %d pattern outlier(s)",
+					s.Kind, s.Name, s.Centrality, s.Outliers)},
+				Locations:  []sarif.Location{sarif.FileLocation(f.Path, s.LineStart, s.LineEnd)},
+				Properties: map[string]any{"score": s.Score},
+			})
+		}
+	}
+	return log
+}
+
+// Explain lists the factors that contributed to the score, largest first.
+func (f *FileRisk) Explain() string {
+	var parts []string
+	for _, factor := range f.Factors {
+		if factor.Score > 0 {
+			parts = append(parts, factor.Detail)
+		}
+	}
+	if len(parts) == 0 {
+		return "no risk signals"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func sarifLevel(l Level) string {
+	switch l {
+	case LevelHigh:
+		return sarif.LevelError
+	case LevelMedium:
+		return sarif.LevelWarning
+	}
+	return sarif.LevelNote
+}
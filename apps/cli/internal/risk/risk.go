@@ -0,0 +1,362 @@
+// Package risk scores how risky a diff is, file by file and symbol by
+// symbol, from what the index and memory already know: how central the
+// touched symbols are, how often the files failed, linked postmortems,
+// pattern outliers and contract mismatches in the changed code, and churn.
+package risk
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/fsutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/gitutil"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// Level buckets a score.
+type Level string
+
+const (
+	LevelLow    Level = "low"
+	LevelMedium Level = "medium"
+	LevelHigh   Level = "high"
+)
+
+// Score thresholds, out of 100.
+const (
+	mediumThreshold = 30
+	highThreshold   = 60
+)
+
+// Factor weights; they add up to 100.
+const (
+	weightFanIn       = 25
+	weightFailures    = 20
+	weightPostmortems = 20
+	weightOutliers    = 15
+	weightContracts   = 10
+	weightChurn       = 10
+)
+
+// postmortemLimit caps the postmortems read per file.
+const postmortemLimit = 20
+
+// ParseLevel parses "low", "medium" or "high".
+func ParseLevel(s string) (Level, error) {
+	switch l := Level(s); l {
+	case LevelLow, LevelMedium, LevelHigh:
+		return l, nil
+	}
+	return "", fmt.Errorf("unknown risk level %q (want low, medium or high)", s)
+}
+
+// AtLeast reports whether l is as severe as other.
+func (l Level) AtLeast(other Level) bool {
+	return l.rank() >= other.rank()
+}
+
+func (l Level) rank() int {
+	switch l {
+	case LevelHigh:
+		return 2
+	case LevelMedium:
+		return 1
+	}
+	return 0
+}
+
+func levelFor(score float64) Level {
+	switch {
+	case score >= highThreshold:
+		return LevelHigh
+	case score >= mediumThreshold:
+		return LevelMedium
+	}
+	return LevelLow
+}
+
+// Factor is one input to a file's score.
+type Factor struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`  // raw signal, e.g. a count or a centrality
+	Score  float64 `json:"score"`  // points contributed to the file's score
+	Detail string  `json:"detail"` // human-readable explanation
+}
+
+// SymbolRisk is the risk of one symbol the diff touches.
+type SymbolRisk struct {
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	LineStart  int     `json:"lineStart"`
+	LineEnd    int     `json:"lineEnd"`
+	Centrality float64 `json:"centrality"` // 0-1, from incoming and outgoing calls
+	Outliers   int     `json:"outliers"`
+	Score      float64 `json:"score"`
+	Level      Level   `json:"level"`
+}
+
+// FileRisk is the risk of one changed file.
+type FileRisk struct {
+	Path               string       `json:"path"`
+	Deleted            bool         `json:"deleted,omitempty"`
+	Score              float64      `json:"score"`
+	Level              Level        `json:"level"`
+	FanIn              float64      `json:"fanIn"`
+	Failures           int          `json:"failures"`
+	Edits              int          `json:"edits"`
+	Postmortems        []string     `json:"postmortems,omitempty"` // IDs
+	Outliers           int          `json:"outliers"`
+	ContractMismatches int          `json:"contractMismatches"`
+	Churn90            int          `json:"churn90"`
+	Factors            []Factor     `json:"factors"`
+	Symbols            []SymbolRisk `json:"symbols,omitempty"`
+}
+
+// Report is the risk of a whole diff.
+type Report struct {
+	DiffRange   string     `json:"diffRange"`
+	GeneratedAt time.Time  `json:"generatedAt"`
+	Level       Level      `json:"level"` // the highest file level
+	Counts      Counts     `json:"counts"`
+	Files       []FileRisk `json:"files"` // riskiest first
+}
+
+// Counts tallies files by level.
+type Counts struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+// Analyze scores every file changed in diffRange. mem may be nil, in which
+// case failures, postmortems, outliers and contracts are not considered.
+func Analyze(root string, db *sql.DB, mem *memory.Memory, diffRange string) (*Report, error) {
+	changes, err := gitutil.DiffLines(root, diffRange)
+	if err != nil {
+		return nil, err
+	}
+	guardrails := config.LoadGuardrails(root)
+
+	var mismatches map[string]int
+	if mem != nil {
+		if mismatches, err = contractMismatches(root, mem); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{DiffRange: diffRange, GeneratedAt: time.Now().UTC(), Level: LevelLow, Files: []FileRisk{}}
+	for path, ranges := range changes {
+		if fsutil.MatchesGuardrail(path, guardrails) {
+			continue
+		}
+		fr, err := analyzeFile(db, mem, path, ranges, mismatches)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		report.Files = append(report.Files, fr)
+		switch fr.Level {
+		case LevelHigh:
+			report.Counts.High++
+		case LevelMedium:
+			report.Counts.Medium++
+		default:
+			report.Counts.Low++
+		}
+		if fr.Level.AtLeast(report.Level) {
+			report.Level = fr.Level
+		}
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		if report.Files[i].Score != report.Files[j].Score {
+			return report.Files[i].Score > report.Files[j].Score
+		}
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report, nil
+}
+
+func analyzeFile(db *sql.DB, mem *memory.Memory, path string, ranges []gitutil.LineRange, mismatches map[string]int) (FileRisk, error) {
+	fr := FileRisk{Path: path, Deleted: ranges == nil}
+	touched := func(start, end int) bool {
+		return fr.Deleted || gitutil.Contains(ranges, start, end)
+	}
+
+	var outliers []memory.PatternLocation
+	postmortemScore := 0.0
+	if mem != nil {
+		intel, err := mem.GetFileIntel(path)
+		if err != nil {
+			return fr, err
+		}
+		fr.Failures, fr.Edits = intel.FailureCount, intel.EditCount
+
+		pms, err := mem.GetPostmortemsForFile(path, postmortemLimit)
+		if err != nil {
+			return fr, err
+		}
+		for i := range pms {
+			fr.Postmortems = append(fr.Postmortems, pms[i].ID)
+			postmortemScore += postmortemWeight(&pms[i])
+		}
+
+		all, err := mem.GetOutliersForFile(path)
+		if err != nil {
+			return fr, err
+		}
+		for _, o := range all {
+			if touched(o.LineStart, max(o.LineEnd, o.LineStart)) {
+				outliers = append(outliers, o)
+			}
+		}
+		fr.Outliers = len(outliers)
+		fr.ContractMismatches = mismatches[path]
+	}
+
+	symbols, err := touchedSymbols(db, path, touched)
+	if err != nil {
+		return fr, err
+	}
+	for i := range symbols {
+		s := &symbols[i]
+		if s.Centrality, err = index.GetSymbolCentrality(db, s.Name, path); err != nil {
+			return fr, err
+		}
+		for _, o := range outliers {
+			if o.LineStart <= s.LineEnd && s.LineStart <= max(o.LineEnd, o.LineStart) {
+				s.Outliers++
+			}
+		}
+		s.Score = round(100 * (0.7*s.Centrality + 0.3*saturate(float64(s.Outliers), 1)))
+		s.Level = levelFor(s.Score)
+		fr.FanIn = math.Max(fr.FanIn, s.Centrality)
+	}
+	sort.SliceStable(symbols, func(i, j int) bool { return symbols[i].Score > symbols[j].Score })
+	fr.Symbols = symbols
+
+	if own, err := index.GetFileOwnership(db, path); err != nil {
+		return fr, err
+	} else if own != nil {
+		fr.Churn90 = own.Churn.Days90
+	}
+
+	fr.Factors = append(fr.Factors,
+		Factor{Name: "fan-in", Value: fr.FanIn, Score: weightFanIn * fr.FanIn,
+			Detail: fmt.Sprintf("most central touched symbol %.2f", fr.FanIn)},
+		Factor{Name: "failures", Value: float64(fr.Failures), Score: weightFailures * saturate(float64(fr.Failures), 2),
+			Detail: fmt.Sprintf("%d failure(s) in %d edit(s)", fr.Failures, fr.Edits)},
+		Factor{Name: "postmortems", Value: float64(len(fr.Postmortems)), Score: weightPostmortems * math.Min(postmortemScore, 1),
+			Detail: fmt.Sprintf("%d linked postmortem(s)", len(fr.Postmortems))},
+		Factor{Name: "outliers", Value: float64(fr.Outliers), Score: weightOutliers * saturate(float64(fr.Outliers), 1),
+			Detail: fmt.Sprintf("%d pattern outlier(s) in changed lines", fr.Outliers)},
+		Factor{Name: "contracts", Value: float64(fr.ContractMismatches), Score: weightContracts * saturate(float64(fr.ContractMismatches), 2),
+			Detail: fmt.Sprintf("%d contract mismatch(es)", fr.ContractMismatches)},
+		Factor{Name: "churn", Value: float64(fr.Churn90), Score: weightChurn * saturate(float64(fr.Churn90), 5),
+			Detail: fmt.Sprintf("%d commit(s) in 90 days", fr.Churn90)},
+	)
+	for i := range fr.Factors {
+		fr.Factors[i].Score = round(fr.Factors[i].Score)
+		fr.Score += fr.Factors[i].Score
+	}
+	sort.SliceStable(fr.Factors, func(i, j int) bool { return fr.Factors[i].Score > fr.Factors[j].Score })
+	fr.Score = round(fr.Score)
+	fr.Level = levelFor(fr.Score)
+	return fr, nil
+}
+
+// touchedSymbols returns the innermost symbols overlapping the change: a
+// method is reported rather than the class around it.
+func touchedSymbols(db *sql.DB, path string, touched func(start, end int) bool) ([]SymbolRisk, error) {
+	all, err := index.ListSymbolRanges(db, path)
+	if err != nil {
+		return nil, err
+	}
+	var hits []index.SymbolInfo
+	for _, s := range all {
+		if s.LineStart > 0 && touched(s.LineStart, s.LineEnd) {
+			hits = append(hits, s)
+		}
+	}
+
+	var symbols []SymbolRisk
+	for i, s := range hits {
+		inner := false
+		for j, t := range hits {
+			if i != j && t.LineStart >= s.LineStart && t.LineEnd <= s.LineEnd &&
+				(t.LineStart != s.LineStart || t.LineEnd != s.LineEnd) {
+				inner = true
+				break
+			}
+		}
+		if !inner {
+			symbols = append(symbols, SymbolRisk{Name: s.Name, Kind: s.Kind, LineStart: s.LineStart, LineEnd: s.LineEnd})
+		}
+	}
+	return symbols, nil
+}
+
+// contractMismatches counts mismatches per file, relative to root, across
+// contracts that are not ignored. Both the backend handler's file and every
+// frontend caller's file are charged.
+func contractMismatches(root string, mem *memory.Memory) (map[string]int, error) {
+	store := contracts.NewStore(mem.DB())
+	if err := store.CreateTables(); err != nil {
+		return nil, fmt.Errorf("create contract tables: %w", err)
+	}
+	list, err := store.ListContracts(contracts.ContractFilter{HasMismatches: true})
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, c := range list {
+		if c.Status == contracts.ContractIgnored {
+			continue
+		}
+		files := map[string]bool{contracts.RelPath(root, c.Backend.File): true}
+		for _, call := range c.FrontendCalls {
+			files[contracts.RelPath(root, call.File)] = true
+		}
+		for f := range files {
+			if f != "" {
+				counts[f] += len(c.Mismatches)
+			}
+		}
+	}
+	return counts, nil
+}
+
+// postmortemWeight scores a postmortem by severity; resolved ones count half.
+func postmortemWeight(pm *memory.Postmortem) float64 {
+	var w float64
+	switch pm.Severity {
+	case "critical":
+		w = 1
+	case "high":
+		w = 0.75
+	case "medium":
+		w = 0.5
+	default:
+		w = 0.25
+	}
+	if pm.Status == "resolved" {
+		w /= 2
+	}
+	return w
+}
+
+// saturate maps a count onto 0-1: half at k, approaching 1 as it grows.
+func saturate(x, k float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return x / (x + k)
+}
+
+func round(x float64) float64 {
+	return math.Round(x*10) / 10
+}
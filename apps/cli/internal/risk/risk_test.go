@@ -0,0 +1,187 @@
+package risk
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/config"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/contracts"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/index"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/sarif"
+)
+
+const libV1 = `package app
+
+// Helper is called from everywhere.
+func Helper(n int) int {
+	return n + 1
+}
+
+func Unused() {}
+`
+
+const libV2 = `package app
+
+// Helper is called from everywhere.
+func Helper(n int) int {
+	return n + 2
+}
+
+func Unused() {}
+`
+
+const mainGo = `package app
+
+func Run() int {
+	return Helper(1) + Helper(2) + Helper(3) + Helper(4)
+}
+`
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", dir, "-c", "user.name=T", "-c", "user.email=t@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exec.CommandContext(context.Background(), "git", "-C", dir, "init").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	write("lib.go", libV1)
+	write("main.go", mainGo)
+	write("README.md", "# app\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	write("lib.go", libV2)
+	write("README.md", "# app\n\nDocs.\n")
+	git("commit", "-q", "-am", "change helper")
+
+	db, err := index.Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("index.Open() error: %v", err)
+	}
+	defer db.Close()
+	records, err := index.BuildFileRecords(dir, config.Guardrails{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.WriteScan(db, dir, records, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	mem, err := memory.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	defer mem.Close()
+	if err := mem.RecordFileEdit("lib.go", "cli"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := mem.RecordFileFailure("lib.go"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pm, err := mem.StorePostmortem(memory.PostmortemInput{
+		Title: "Helper overflow", WhatHappened: "Broke prod", Severity: "critical", AffectedFiles: []string{"lib.go"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Analyze(dir, db, mem, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if len(report.Files) != 2 {
+		t.Fatalf("got %d files, want lib.go and README.md: %+v", len(report.Files), report.Files)
+	}
+	lib := report.Files[0]
+	if lib.Path != "lib.go" || report.Files[1].Path != "README.md" {
+		t.Fatalf("files not ordered by risk: %s, %s", lib.Path, report.Files[1].Path)
+	}
+	if lib.Failures != 3 || len(lib.Postmortems) != 1 || lib.Postmortems[0] != pm.ID {
+		t.Errorf("lib.go signals = %+v", lib)
+	}
+	if len(lib.Symbols) != 1 || lib.Symbols[0].Name != "Helper" || lib.Symbols[0].Centrality == 0 {
+		t.Errorf("touched symbols = %+v", lib.Symbols)
+	}
+	if lib.FanIn != lib.Symbols[0].Centrality || lib.Level != LevelMedium {
+		t.Errorf("lib.go fan-in %.2f, score %.1f, level %s", lib.FanIn, lib.Score, lib.Level)
+	}
+	if report.Files[1].Level != LevelLow || report.Level != LevelMedium || report.Counts != (Counts{Medium: 1, Low: 1}) {
+		t.Errorf("report level %s, counts %+v", report.Level, report.Counts)
+	}
+	if !strings.Contains(lib.Explain(), "1 linked postmortem(s)") {
+		t.Errorf("Explain() = %q", lib.Explain())
+	}
+
+	log := report.SARIF("test")
+	results := log.Results()
+	if len(results) != 2 || results[0].RuleID != RuleFileRisk || results[0].Level != sarif.LevelWarning {
+		t.Fatalf("SARIF results = %+v", results)
+	}
+	if uri := results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "lib.go" {
+		t.Errorf("SARIF location = %q", uri)
+	}
+	if r := results[1]; r.RuleID != RuleSymbolRisk || r.Locations[0].PhysicalLocation.Region == nil || r.Locations[0].PhysicalLocation.Region.StartLine != 4 {
+		t.Errorf("SARIF symbol result = %+v", r)
+	}
+
+	// Contract scans store absolute paths; mismatches still reach lib.go.
+	store := contracts.NewStore(mem.DB())
+	result := contracts.NewAnalyzer().Analyze(&contracts.AnalysisInput{
+		Endpoints: []contracts.EndpointInput{{Method: "GET", Path: "/api/helper", File: filepath.Join(dir, "lib.go"), Line: 4}},
+		Calls:     []contracts.CallInput{{Method: "GET", URL: "/api/helper", File: filepath.Join(dir, "web", "api.ts"), Line: 1}},
+	})
+	for _, c := range result.Contracts {
+		c.Mismatches = append(c.Mismatches, contracts.FieldMismatch{
+			FieldPath: "n", Type: contracts.MismatchTypeMismatch, Severity: contracts.SeverityError,
+		})
+		if err := store.SaveContract(c); err != nil {
+			t.Fatalf("SaveContract() error: %v", err)
+		}
+	}
+	report, err = Analyze(dir, db, mem, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("Analyze() with contracts error: %v", err)
+	}
+	if report.Files[0].Path != "lib.go" || report.Files[0].ContractMismatches != 1 {
+		t.Errorf("contract mismatches = %+v", report.Files[0])
+	}
+
+	// Without memory only the index signals remain.
+	report, err = Analyze(dir, db, nil, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("Analyze(nil memory) error: %v", err)
+	}
+	if report.Files[0].Failures != 0 || report.Files[0].Score >= lib.Score {
+		t.Errorf("without memory = %+v", report.Files[0])
+	}
+}
+
+func TestLevel(t *testing.T) {
+	if _, err := ParseLevel("severe"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+	if l, _ := ParseLevel("medium"); !LevelHigh.AtLeast(l) || LevelLow.AtLeast(l) || !l.AtLeast(l) {
+		t.Error("AtLeast() ordering is wrong")
+	}
+	if levelFor(59.9) != LevelMedium || levelFor(60) != LevelHigh || levelFor(10) != LevelLow {
+		t.Error("levelFor() thresholds are wrong")
+	}
+}
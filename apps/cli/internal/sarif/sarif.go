@@ -0,0 +1,174 @@
+// Package sarif writes SARIF 2.1.0 logs, the format GitHub code scanning
+// and GitLab use to show findings inline on pull requests.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	schemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	version   = "2.1.0"
+
+	// ToolName is the driver name reported in every log.
+	ToolName = "mind-palace"
+	// InformationURI points code-scanning UIs at the project.
+	InformationURI = "https://github.com/mehmetkoksal-w/mind-palace"
+)
+
+// Result levels.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Log is a SARIF log with a single run.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is one tool invocation.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool component that produced the results.
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule describes a kind of result.
+type Rule struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name,omitempty"`
+	ShortDescription     *Message       `json:"shortDescription,omitempty"`
+	DefaultConfiguration *Configuration `json:"defaultConfiguration,omitempty"`
+}
+
+// Configuration holds a rule's default level.
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// Message is plain result or rule text.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is one finding.
+type Result struct {
+	RuleID           string         `json:"ruleId"`
+	Level            string         `json:"level"`
+	Message          Message        `json:"message"`
+	Locations        []Location     `json:"locations,omitempty"`
+	RelatedLocations []Location     `json:"relatedLocations,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+// Location points at a region of a file.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file and an optional region in it.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation is a path relative to the repository root.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is an inclusive line range.
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// NewLog returns a log for one run of the given tool version.
+func NewLog(toolVersion string) *Log {
+	return &Log{
+		Schema:  schemaURI,
+		Version: version,
+		Runs: []Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           ToolName,
+				Version:        toolVersion,
+				InformationURI: InformationURI,
+			}},
+			Results: []Result{},
+		}},
+	}
+}
+
+// AddRule registers a rule once; later calls with the same ID are ignored.
+func (l *Log) AddRule(rule Rule) {
+	driver := &l.Runs[0].Tool.Driver
+	for _, r := range driver.Rules {
+		if r.ID == rule.ID {
+			return
+		}
+	}
+	driver.Rules = append(driver.Rules, rule)
+	sort.Slice(driver.Rules, func(i, j int) bool { return driver.Rules[i].ID < driver.Rules[j].ID })
+}
+
+// AddResult appends a result to the run.
+func (l *Log) AddResult(result Result) {
+	l.Runs[0].Results = append(l.Runs[0].Results, result)
+}
+
+// Results returns the run's results.
+func (l *Log) Results() []Result {
+	return l.Runs[0].Results
+}
+
+// FileLocation builds a location for path, with a region when startLine is
+// positive. endLine may be zero for a single line.
+func FileLocation(path string, startLine, endLine int) Location {
+	loc := Location{PhysicalLocation: PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: filepath.ToSlash(path)},
+	}}
+	if startLine > 0 {
+		if endLine < startLine {
+			endLine = 0
+		}
+		loc.PhysicalLocation.Region = &Region{StartLine: startLine, EndLine: endLine}
+	}
+	return loc
+}
+
+// Marshal renders the log as indented JSON.
+func (l *Log) Marshal() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// WriteFile writes the log to path.
+func (l *Log) WriteFile(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
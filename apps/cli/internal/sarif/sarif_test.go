@@ -0,0 +1,39 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	log := NewLog("1.2.3")
+	log.AddRule(Rule{ID: "b"})
+	log.AddRule(Rule{ID: "a"})
+	log.AddRule(Rule{ID: "b", Name: "duplicate"})
+	log.AddResult(Result{RuleID: "a", Level: LevelError, Message: Message{Text: "bad"},
+		Locations: []Location{FileLocation(`dir\file.go`, 3, 1), FileLocation("other.go", 0, 0)}})
+
+	data, err := log.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["version"] != "2.1.0" || decoded["$schema"] == "" {
+		t.Errorf("header = %v, %v", decoded["version"], decoded["$schema"])
+	}
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "a" || rules[1].Name != "" {
+		t.Errorf("rules = %+v", rules)
+	}
+	locs := log.Results()[0].Locations
+	if r := locs[0].PhysicalLocation.Region; r == nil || r.StartLine != 3 || r.EndLine != 0 {
+		t.Errorf("region = %+v", r)
+	}
+	if locs[1].PhysicalLocation.Region != nil {
+		t.Error("expected no region for line 0")
+	}
+}
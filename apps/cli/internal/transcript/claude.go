@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// claudeParser reads Claude Code session logs: one JSONL file per session
+// under ~/.claude/projects/<encoded workspace path>/.
+type claudeParser struct{}
+
+type claudeLine struct {
+	Type        string `json:"type"`
+	SessionID   string `json:"sessionId"`
+	Timestamp   string `json:"timestamp"`
+	Cwd         string `json:"cwd"`
+	IsMeta      bool   `json:"isMeta"`
+	IsSidechain bool   `json:"isSidechain"`
+	Summary     string `json:"summary"`
+	Message     struct {
+		ID      string          `json:"id"`
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+func (claudeParser) Parse(path string) ([]Transcript, error) {
+	t := Transcript{
+		Agent: "claude-code",
+		ID:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:  path,
+	}
+
+	// Streamed assistant replies are logged as one line per content block,
+	// all sharing the API message ID; lastID merges them back together.
+	var lastID string
+	err := readJSONLines(path, func(data []byte) {
+		var line claudeLine
+		if json.Unmarshal(data, &line) != nil {
+			return
+		}
+		if line.SessionID != "" {
+			t.ID = line.SessionID
+		}
+		if line.Cwd != "" && t.Workspace == "" {
+			t.Workspace = line.Cwd
+		}
+
+		switch {
+		case line.Type == "summary":
+			if line.Summary != "" {
+				t.Summary = line.Summary
+			}
+			return
+		case line.Type != "user" && line.Type != "assistant", line.IsMeta, line.IsSidechain:
+			return
+		}
+
+		text := textBlocks(line.Message.Content, "text")
+		if text == "" || isClaudeCommandOutput(text) {
+			return
+		}
+		if line.Type == "assistant" && line.Message.ID != "" && line.Message.ID == lastID {
+			prev := &t.Messages[len(t.Messages)-1]
+			prev.Content += "\n\n" + text
+			return
+		}
+		lastID = line.Message.ID
+		t.Messages = append(t.Messages, memory.Message{
+			Role:      line.Type,
+			Content:   text,
+			Timestamp: parseTime(line.Timestamp),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []Transcript{t}, nil
+}
+
+// isClaudeCommandOutput reports whether a user entry is slash-command
+// bookkeeping rather than something the user typed.
+func isClaudeCommandOutput(text string) bool {
+	for _, prefix := range []string{"<command-name>", "<command-message>", "<local-command-stdout>", "<local-command-stderr>"} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var claudeProjectNameRe = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+func (claudeParser) Discover(root string) ([]string, error) {
+	base, err := homeDir("CLAUDE_CONFIG_DIR", ".claude")
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(base, "projects", claudeProjectNameRe.ReplaceAllString(abs, "-"))
+	return filepath.Glob(filepath.Join(dir, "*.jsonl"))
+}
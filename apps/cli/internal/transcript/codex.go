@@ -0,0 +1,110 @@
+package transcript
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// codexParser reads Codex CLI rollout files from $CODEX_HOME/sessions
+// (default ~/.codex/sessions), one JSONL file per session.
+type codexParser struct{}
+
+// codexItem is a response item. Current rollouts wrap it in a
+// {"type":"response_item","payload":...} line; older ones log it directly.
+type codexItem struct {
+	Type    string          `json:"type"`
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type codexLine struct {
+	codexItem
+	Timestamp string          `json:"timestamp"`
+	ID        string          `json:"id"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type codexMeta struct {
+	ID  string `json:"id"`
+	Cwd string `json:"cwd"`
+}
+
+func (codexParser) Parse(path string) ([]Transcript, error) {
+	t := Transcript{
+		Agent: "codex",
+		ID:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:  path,
+	}
+
+	first := true
+	err := readJSONLines(path, func(data []byte) {
+		var line codexLine
+		if json.Unmarshal(data, &line) != nil {
+			return
+		}
+		// Older rollouts start with a bare {"id", "timestamp", ...} header.
+		if first && line.ID != "" && line.Type == "" {
+			t.ID = line.ID
+		}
+		first = false
+
+		item := line.codexItem
+		switch line.Type {
+		case "session_meta":
+			var meta codexMeta
+			if json.Unmarshal(line.Payload, &meta) == nil {
+				if meta.ID != "" {
+					t.ID = meta.ID
+				}
+				t.Workspace = meta.Cwd
+			}
+			return
+		case "response_item":
+			if json.Unmarshal(line.Payload, &item) != nil {
+				return
+			}
+		}
+		if item.Type != "message" || (item.Role != "user" && item.Role != "assistant") {
+			return
+		}
+
+		text := textBlocks(item.Content, "input_text", "output_text")
+		if text == "" || isCodexContext(text) {
+			return
+		}
+		t.Messages = append(t.Messages, memory.Message{
+			Role:      item.Role,
+			Content:   text,
+			Timestamp: parseTime(line.Timestamp),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []Transcript{t}, nil
+}
+
+// isCodexContext reports whether a user message is context Codex injects
+// (AGENTS.md instructions, environment details) rather than a user prompt.
+func isCodexContext(text string) bool {
+	return strings.HasPrefix(text, "<user_instructions>") ||
+		strings.HasPrefix(text, "<environment_context>") ||
+		strings.HasPrefix(text, "# AGENTS.md instructions")
+}
+
+func (codexParser) Discover(_ string) ([]string, error) {
+	base, err := homeDir("CODEX_HOME", ".codex")
+	if err != nil {
+		return nil, err
+	}
+	files, err := listFiles(filepath.Join(base, "sessions"), ".jsonl")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return files, err
+}
@@ -0,0 +1,365 @@
+package transcript
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // SQLite driver
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// cursorParser reads Cursor chat history from its state.vscdb SQLite stores.
+// Each workspace has one under User/workspaceStorage/<hash>/; newer versions
+// keep composer conversations in User/globalStorage/state.vscdb and list the
+// workspace's composer IDs in the workspace store.
+type cursorParser struct{}
+
+const (
+	cursorChatKey     = "workbench.panel.aichat.view.aichat.chatdata"
+	cursorComposerKey = "composer.composerData"
+)
+
+type cursorChatData struct {
+	Tabs []struct {
+		TabID        string `json:"tabId"`
+		ChatTitle    string `json:"chatTitle"`
+		LastSendTime int64  `json:"lastSendTime"`
+		Bubbles      []struct {
+			Type    string `json:"type"`
+			Text    string `json:"text"`
+			RawText string `json:"rawText"`
+		} `json:"bubbles"`
+	} `json:"tabs"`
+}
+
+type cursorBubble struct {
+	BubbleID  string          `json:"bubbleId"`
+	Type      int             `json:"type"`
+	Text      string          `json:"text"`
+	CreatedAt json.RawMessage `json:"createdAt"`
+}
+
+type cursorComposer struct {
+	ComposerID    string         `json:"composerId"`
+	Name          string         `json:"name"`
+	CreatedAt     int64          `json:"createdAt"`
+	LastUpdatedAt int64          `json:"lastUpdatedAt"`
+	Conversation  []cursorBubble `json:"conversation"`
+	Headers       []cursorBubble `json:"fullConversationHeadersOnly"`
+}
+
+func (cursorParser) Parse(path string) ([]Transcript, error) {
+	db, err := openCursorDB(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	workspace := cursorWorkspaceFolder(filepath.Join(filepath.Dir(path), "workspace.json"))
+	var transcripts []Transcript
+
+	if raw, err := cursorItem(db, "ItemTable", cursorChatKey); err != nil {
+		return nil, err
+	} else if raw != nil {
+		var data cursorChatData
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+		for _, tab := range data.Tabs {
+			t := Transcript{Agent: "cursor", ID: tab.TabID, Path: path, Workspace: workspace, Summary: tab.ChatTitle}
+			for _, b := range tab.Bubbles {
+				text := strings.TrimSpace(b.Text)
+				if text == "" {
+					text = strings.TrimSpace(b.RawText)
+				}
+				role := "user"
+				if b.Type == "ai" {
+					role = "assistant"
+				}
+				if text != "" {
+					t.Messages = append(t.Messages, memory.Message{Role: role, Content: text})
+				}
+			}
+			if tab.LastSendTime > 0 {
+				t.EndedAt = time.UnixMilli(tab.LastSendTime).UTC()
+			}
+			transcripts = append(transcripts, t)
+		}
+	}
+
+	composers, err := cursorComposers(db, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range composers {
+		t := Transcript{Agent: "cursor", ID: c.composer.ComposerID, Path: path, Workspace: workspace, Summary: c.composer.Name}
+		bubbles := c.composer.Conversation
+		if len(bubbles) == 0 {
+			bubbles = c.bubbles
+		}
+		for _, b := range bubbles {
+			text := strings.TrimSpace(b.Text)
+			if text == "" || (b.Type != 1 && b.Type != 2) {
+				continue
+			}
+			role := "user"
+			if b.Type == 2 {
+				role = "assistant"
+			}
+			t.Messages = append(t.Messages, memory.Message{Role: role, Content: text, Timestamp: cursorTime(b.CreatedAt)})
+		}
+		if c.composer.CreatedAt > 0 {
+			t.StartedAt = time.UnixMilli(c.composer.CreatedAt).UTC()
+		}
+		if c.composer.LastUpdatedAt > 0 {
+			t.EndedAt = time.UnixMilli(c.composer.LastUpdatedAt).UTC()
+		}
+		transcripts = append(transcripts, t)
+	}
+	return transcripts, nil
+}
+
+type cursorComposerEntry struct {
+	composer cursorComposer
+	bubbles  []cursorBubble // looked up from bubbleId:* keys when not inline
+}
+
+// cursorComposers returns the composer conversations stored in db, plus those
+// the workspace lists that live in the sibling global store.
+func cursorComposers(db *sql.DB, path string) ([]cursorComposerEntry, error) {
+	var entries []cursorComposerEntry
+	seen := map[string]bool{}
+
+	if hasTable(db, "cursorDiskKV") {
+		local, err := readComposers(db, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range local {
+			seen[e.composer.ComposerID] = true
+		}
+		entries = append(entries, local...)
+	}
+
+	raw, err := cursorItem(db, "ItemTable", cursorComposerKey)
+	if err != nil || raw == nil {
+		return entries, err
+	}
+	var listed struct {
+		AllComposers []struct {
+			ComposerID string `json:"composerId"`
+		} `json:"allComposers"`
+	}
+	if json.Unmarshal(raw, &listed) != nil {
+		return entries, nil
+	}
+	var missing []string
+	for _, c := range listed.AllComposers {
+		if !seen[c.ComposerID] {
+			missing = append(missing, c.ComposerID)
+		}
+	}
+	if len(missing) == 0 {
+		return entries, nil
+	}
+
+	// workspaceStorage/<hash>/state.vscdb -> globalStorage/state.vscdb
+	globalPath := filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(path))), "globalStorage", "state.vscdb")
+	if _, err := os.Stat(globalPath); err != nil {
+		return entries, nil
+	}
+	global, err := openCursorDB(globalPath)
+	if err != nil {
+		return nil, err
+	}
+	defer global.Close()
+	if !hasTable(global, "cursorDiskKV") {
+		return entries, nil
+	}
+	remote, err := readComposers(global, missing)
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, remote...), nil
+}
+
+// readComposers loads composerData entries, all of them when ids is nil.
+func readComposers(db *sql.DB, ids []string) ([]cursorComposerEntry, error) {
+	ctx := context.Background()
+	var raws [][]byte
+	if ids == nil {
+		rows, err := db.QueryContext(ctx, `SELECT value FROM cursorDiskKV WHERE key LIKE 'composerData:%'`)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var raw []byte
+			if err := rows.Scan(&raw); err != nil {
+				return nil, err
+			}
+			raws = append(raws, raw)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, id := range ids {
+			raw, err := cursorItem(db, "cursorDiskKV", "composerData:"+id)
+			if err != nil {
+				return nil, err
+			}
+			if raw != nil {
+				raws = append(raws, raw)
+			}
+		}
+	}
+
+	var entries []cursorComposerEntry
+	for _, raw := range raws {
+		var c cursorComposer
+		if json.Unmarshal(raw, &c) != nil || c.ComposerID == "" {
+			continue
+		}
+		entry := cursorComposerEntry{composer: c}
+		if len(c.Conversation) == 0 {
+			for _, h := range c.Headers {
+				raw, err := cursorItem(db, "cursorDiskKV", "bubbleId:"+c.ComposerID+":"+h.BubbleID)
+				if err != nil {
+					return nil, err
+				}
+				var b cursorBubble
+				if raw != nil && json.Unmarshal(raw, &b) == nil {
+					entry.bubbles = append(entry.bubbles, b)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func openCursorDB(path string) (*sql.DB, error) {
+	// Cursor may be running, so open read-only and never write to its store.
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// cursorItem returns the value stored under key, or nil when it is absent.
+func cursorItem(db *sql.DB, table, key string) ([]byte, error) {
+	if !hasTable(db, table) {
+		return nil, nil
+	}
+	var raw []byte
+	err := db.QueryRowContext(context.Background(), `SELECT value FROM `+table+` WHERE key = ?`, key).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return raw, err
+}
+
+func hasTable(db *sql.DB, name string) bool {
+	var n int
+	err := db.QueryRowContext(context.Background(),
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	return err == nil && n > 0
+}
+
+// cursorTime parses a bubble timestamp, which is either epoch milliseconds or
+// an RFC 3339 string.
+func cursorTime(raw json.RawMessage) time.Time {
+	if len(raw) == 0 {
+		return time.Time{}
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return parseTime(s)
+	}
+	if ms, err := strconv.ParseInt(string(raw), 10, 64); err == nil && ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Time{}
+}
+
+// cursorWorkspaceFolder reads the folder a workspace store belongs to from
+// its workspace.json.
+func cursorWorkspaceFolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var ws struct {
+		Folder string `json:"folder"`
+	}
+	if json.Unmarshal(data, &ws) != nil || ws.Folder == "" {
+		return ""
+	}
+	u, err := url.Parse(ws.Folder)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	folder := u.Path
+	if runtime.GOOS == "windows" {
+		folder = strings.TrimPrefix(folder, "/")
+	}
+	return filepath.FromSlash(folder)
+}
+
+// cursorStorageDir returns Cursor's workspaceStorage directory for this OS.
+func cursorStorageDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Cursor", "User", "workspaceStorage"), nil
+	case "darwin":
+		return homeDir("", filepath.Join("Library", "Application Support", "Cursor", "User", "workspaceStorage"))
+	default:
+		return homeDir("", filepath.Join(".config", "Cursor", "User", "workspaceStorage"))
+	}
+}
+
+func (cursorParser) Discover(root string) ([]string, error) {
+	storage, err := cursorStorageDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(storage)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(storage, e.Name())
+		folder := cursorWorkspaceFolder(filepath.Join(dir, "workspace.json"))
+		if folder == "" || !samePath(folder, root) {
+			continue
+		}
+		db := filepath.Join(dir, "state.vscdb")
+		if _, err := os.Stat(db); err == nil {
+			files = append(files, db)
+		}
+	}
+	return files, nil
+}
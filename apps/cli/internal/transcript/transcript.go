@@ -0,0 +1,259 @@
+// Package transcript reads conversation transcripts that coding agents write
+// to disk (Claude Code, Codex, Cursor) so they can be imported into memory.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mehmetkoksal-w/mind-palace/apps/cli/internal/memory"
+)
+
+// Transcript is one agent conversation read from disk.
+type Transcript struct {
+	Agent     string           // agent type, e.g. "claude-code"
+	ID        string           // agent's own conversation ID
+	Path      string           // file the transcript was read from
+	Workspace string           // working directory recorded by the agent, if any
+	Summary   string           // title or first user message
+	Messages  []memory.Message // user and assistant text, in order
+	StartedAt time.Time        // first message time, if known
+	EndedAt   time.Time        // last message time, if known
+}
+
+// Source identifies the transcript across imports.
+func (t *Transcript) Source() string {
+	return t.Agent + ":" + t.ID
+}
+
+// Parser reads one agent's transcript format.
+type Parser interface {
+	// Parse reads the transcripts stored in a file.
+	Parse(path string) ([]Transcript, error)
+	// Discover returns the transcript files the agent wrote for a workspace.
+	Discover(root string) ([]string, error)
+}
+
+// parsers maps agent types to their transcript parsers.
+var parsers = map[string]Parser{
+	"claude-code": claudeParser{},
+	"codex":       codexParser{},
+	"cursor":      cursorParser{},
+}
+
+// Agents returns the supported agent types.
+func Agents() []string {
+	agents := make([]string, 0, len(parsers))
+	for name := range parsers {
+		agents = append(agents, name)
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// Lookup returns the parser for an agent type.
+func Lookup(agent string) (Parser, error) {
+	p, ok := parsers[agent]
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q: must be one of %s", agent, strings.Join(Agents(), ", "))
+	}
+	return p, nil
+}
+
+// Load reads transcripts for a workspace. path is a transcript file, a
+// directory of transcripts, or "auto" to use the agent's default location.
+// Auto-discovered transcripts recorded in another workspace are skipped.
+func Load(agent, root, path string) ([]Transcript, error) {
+	parser, err := Lookup(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	auto := path == "auto"
+	var files []string
+	switch {
+	case auto:
+		if files, err = parser.Discover(root); err != nil {
+			return nil, err
+		}
+	default:
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			files, err = listFiles(path, ".jsonl", ".vscdb")
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			files = []string{path}
+		}
+	}
+
+	var transcripts []Transcript
+	var errs []error
+	for _, file := range files {
+		parsed, err := parser.Parse(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		for i := range parsed {
+			t := &parsed[i]
+			if len(t.Messages) == 0 {
+				continue
+			}
+			if auto && t.Workspace != "" && !samePath(t.Workspace, root) {
+				continue
+			}
+			finish(t)
+			transcripts = append(transcripts, *t)
+		}
+	}
+	if len(transcripts) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.SliceStable(transcripts, func(i, j int) bool {
+		return transcripts[i].StartedAt.Before(transcripts[j].StartedAt)
+	})
+	return transcripts, nil
+}
+
+// finish fills in the time range and summary from the messages.
+func finish(t *Transcript) {
+	for _, msg := range t.Messages {
+		if msg.Timestamp.IsZero() {
+			continue
+		}
+		if t.StartedAt.IsZero() || msg.Timestamp.Before(t.StartedAt) {
+			t.StartedAt = msg.Timestamp
+		}
+		if msg.Timestamp.After(t.EndedAt) {
+			t.EndedAt = msg.Timestamp
+		}
+	}
+	if t.Summary == "" {
+		for _, msg := range t.Messages {
+			if msg.Role == "user" {
+				t.Summary = msg.Content
+				break
+			}
+		}
+	}
+	t.Summary = strings.Join(strings.Fields(t.Summary), " ")
+	if runes := []rune(t.Summary); len(runes) > 120 {
+		t.Summary = string(runes[:117]) + "..."
+	}
+}
+
+// readJSONLines calls fn for each non-empty line of a JSONL file. Lines
+// that are not valid JSON are skipped; agents append to these files while running.
+func readJSONLines(path string, fn func(line []byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Lines may hold large tool outputs, so read without a length limit.
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if trimmed := strings.TrimSpace(string(line)); trimmed != "" && json.Valid([]byte(trimmed)) {
+			fn([]byte(trimmed))
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// listFiles returns the files under dir with one of the given extensions.
+func listFiles(dir string, exts ...string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		for _, ext := range exts {
+			if filepath.Ext(path) == ext {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// homeDir returns the directory named by env, or fallback under the user's home.
+func homeDir(env, fallback string) (string, error) {
+	if dir := os.Getenv(env); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+func samePath(a, b string) bool {
+	ca, errA := filepath.Abs(a)
+	cb, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return filepath.Clean(ca) == filepath.Clean(cb)
+}
+
+// textBlocks joins the text of message content that is either a string or
+// a list of typed blocks. Blocks whose type is not in textTypes (tool calls,
+// tool results, images, reasoning) are dropped.
+func textBlocks(raw json.RawMessage, textTypes ...string) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		for _, tt := range textTypes {
+			if b.Type == tt && strings.TrimSpace(b.Text) != "" {
+				parts = append(parts, strings.TrimSpace(b.Text))
+				break
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
@@ -0,0 +1,226 @@
+package transcript
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	writeFile(t, path,
+		`{"type":"summary","summary":"Fix flaky cache test","leafUuid":"x"}`,
+		`{"type":"user","sessionId":"s-1","cwd":"/work/app","timestamp":"2025-06-01T12:00:00.000Z","isMeta":true,"message":{"role":"user","content":"Caveat: ignore"}}`,
+		`{"type":"user","sessionId":"s-1","cwd":"/work/app","timestamp":"2025-06-01T12:00:01.000Z","message":{"role":"user","content":"Why is the cache test flaky?"}}`,
+		`{"type":"assistant","sessionId":"s-1","timestamp":"2025-06-01T12:00:05.000Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Map iteration order."}]}}`,
+		`{"type":"assistant","sessionId":"s-1","timestamp":"2025-06-01T12:00:06.000Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{}}]}}`,
+		`{"type":"assistant","sessionId":"s-1","timestamp":"2025-06-01T12:00:07.000Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Sorting keys fixes it."}]}}`,
+		`{"type":"user","sessionId":"s-1","timestamp":"2025-06-01T12:00:08.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"file"}]}}`,
+		`{"type":"user","sessionId":"s-1","timestamp":"2025-06-01T12:00:09.000Z","message":{"role":"user","content":"<command-name>/clear</command-name>"}}`,
+		`{"type":"user","sessionId":"s-1","isSidechain":true,"timestamp":"2025-06-01T12:00:10.000Z","message":{"role":"user","content":"subagent prompt"}}`,
+		`{"type":"user","sessionId":"s-1","timestamp":"2025-06-01T12:01:00.000Z","message":{"role":"user","content":[{"type":"text","text":"Thanks"}]}}`,
+		`{"type":"assistant","sessionId":"s-1","timestamp":"2025-06-01T12:01:0`, // truncated by a live session
+	)
+
+	got, err := claudeParser{}.Parse(path)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Parse() returned %d transcripts, want 1", len(got))
+	}
+	tr := got[0]
+	if tr.ID != "s-1" || tr.Workspace != "/work/app" || tr.Summary != "Fix flaky cache test" {
+		t.Errorf("transcript = {ID:%q Workspace:%q Summary:%q}", tr.ID, tr.Workspace, tr.Summary)
+	}
+
+	want := []struct{ role, content string }{
+		{"user", "Why is the cache test flaky?"},
+		{"assistant", "Map iteration order.\n\nSorting keys fixes it."},
+		{"user", "Thanks"},
+	}
+	if len(tr.Messages) != len(want) {
+		t.Fatalf("got %d messages, want %d: %+v", len(tr.Messages), len(want), tr.Messages)
+	}
+	for i, w := range want {
+		if tr.Messages[i].Role != w.role || tr.Messages[i].Content != w.content {
+			t.Errorf("message %d = %s %q, want %s %q", i, tr.Messages[i].Role, tr.Messages[i].Content, w.role, w.content)
+		}
+	}
+}
+
+func TestCodexParse(t *testing.T) {
+	dir := t.TempDir()
+
+	current := filepath.Join(dir, "rollout-new.jsonl")
+	writeFile(t, current,
+		`{"timestamp":"2025-06-01T12:00:00Z","type":"session_meta","payload":{"id":"cx-1","cwd":"/work/app"}}`,
+		`{"timestamp":"2025-06-01T12:00:00Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>cwd</environment_context>"}]}}`,
+		`{"timestamp":"2025-06-01T12:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"Add a retry"}]}}`,
+		`{"timestamp":"2025-06-01T12:00:02Z","type":"event_msg","payload":{"type":"user_message","message":"Add a retry"}}`,
+		`{"timestamp":"2025-06-01T12:00:03Z","type":"response_item","payload":{"type":"reasoning","summary":[]}}`,
+		`{"timestamp":"2025-06-01T12:00:04Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{}"}}`,
+		`{"timestamp":"2025-06-01T12:00:05Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Added backoff."}]}}`,
+	)
+	legacy := filepath.Join(dir, "rollout-old.jsonl")
+	writeFile(t, legacy,
+		`{"id":"cx-0","timestamp":"2025-05-01T09:00:00Z","instructions":null}`,
+		`{"type":"message","role":"user","content":[{"type":"input_text","text":"Rename the flag"}]}`,
+		`{"record_type":"state"}`,
+		`{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Renamed."}]}`,
+	)
+
+	got, err := codexParser{}.Parse(current)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	tr := got[0]
+	if tr.ID != "cx-1" || tr.Workspace != "/work/app" {
+		t.Errorf("transcript = {ID:%q Workspace:%q}", tr.ID, tr.Workspace)
+	}
+	if len(tr.Messages) != 2 || tr.Messages[0].Content != "Add a retry" || tr.Messages[1].Content != "Added backoff." {
+		t.Errorf("messages = %+v", tr.Messages)
+	}
+	if !tr.Messages[0].Timestamp.Equal(time.Date(2025, 6, 1, 12, 0, 1, 0, time.UTC)) {
+		t.Errorf("timestamp = %v", tr.Messages[0].Timestamp)
+	}
+
+	got, err = codexParser{}.Parse(legacy)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got[0].ID != "cx-0" || len(got[0].Messages) != 2 {
+		t.Errorf("legacy transcript = {ID:%q Messages:%+v}", got[0].ID, got[0].Messages)
+	}
+}
+
+func TestCursorParse(t *testing.T) {
+	dir := t.TempDir()
+	wsDir := filepath.Join(dir, "workspaceStorage", "abc123")
+	globalDir := filepath.Join(dir, "globalStorage")
+	writeFile(t, filepath.Join(wsDir, "workspace.json"), `{"folder":"file:///work/app"}`)
+
+	ws := createCursorDB(t, filepath.Join(wsDir, "state.vscdb"))
+	mustExec(t, ws, `INSERT INTO ItemTable (key, value) VALUES (?, ?)`, cursorChatKey,
+		`{"tabs":[{"tabId":"tab-1","chatTitle":"Explain retry","lastSendTime":1748779200000,"bubbles":[
+			{"type":"user","text":"How does retry work?"},{"type":"ai","rawText":"It backs off."}]}]}`)
+	mustExec(t, ws, `INSERT INTO ItemTable (key, value) VALUES (?, ?)`, cursorComposerKey,
+		`{"allComposers":[{"composerId":"comp-1"},{"composerId":"comp-2"}]}`)
+	ws.Close()
+
+	global := createCursorDB(t, filepath.Join(globalDir, "state.vscdb"))
+	mustExec(t, global, `INSERT INTO cursorDiskKV (key, value) VALUES (?, ?)`, "composerData:comp-1",
+		`{"composerId":"comp-1","name":"Inline","createdAt":1748779200000,"lastUpdatedAt":1748782800000,
+			"conversation":[{"type":1,"text":"Add tests"},{"type":2,"text":"Done."}]}`)
+	mustExec(t, global, `INSERT INTO cursorDiskKV (key, value) VALUES (?, ?)`, "composerData:comp-2",
+		`{"composerId":"comp-2","name":"Bubbles","fullConversationHeadersOnly":[{"bubbleId":"b1","type":1},{"bubbleId":"b2","type":2}]}`)
+	mustExec(t, global, `INSERT INTO cursorDiskKV (key, value) VALUES (?, ?)`, "bubbleId:comp-2:b1",
+		`{"type":1,"text":"Rename it","createdAt":"2025-06-01T12:00:00Z"}`)
+	mustExec(t, global, `INSERT INTO cursorDiskKV (key, value) VALUES (?, ?)`, "bubbleId:comp-2:b2",
+		`{"type":2,"text":"Renamed."}`)
+	mustExec(t, global, `INSERT INTO cursorDiskKV (key, value) VALUES (?, ?)`, "composerData:other-workspace",
+		`{"composerId":"other-workspace","conversation":[{"type":1,"text":"not ours"}]}`)
+	global.Close()
+
+	got, err := cursorParser{}.Parse(filepath.Join(wsDir, "state.vscdb"))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	byID := map[string]Transcript{}
+	for _, tr := range got {
+		byID[tr.ID] = tr
+		if tr.Workspace != filepath.FromSlash("/work/app") {
+			t.Errorf("%s: workspace = %q", tr.ID, tr.Workspace)
+		}
+	}
+	if len(byID) != 3 {
+		t.Fatalf("got transcripts %v, want tab-1, comp-1 and comp-2", byID)
+	}
+	if m := byID["tab-1"].Messages; len(m) != 2 || m[1].Role != "assistant" || m[1].Content != "It backs off." {
+		t.Errorf("tab-1 messages = %+v", m)
+	}
+	if tr := byID["comp-1"]; len(tr.Messages) != 2 || tr.StartedAt.IsZero() || tr.EndedAt.Sub(tr.StartedAt) != time.Hour {
+		t.Errorf("comp-1 = %+v", tr)
+	}
+	if m := byID["comp-2"].Messages; len(m) != 2 || m[0].Content != "Rename it" || m[0].Timestamp.IsZero() {
+		t.Errorf("comp-2 messages = %+v", m)
+	}
+}
+
+func TestLoadAutoClaude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", home)
+	root := filepath.Join(t.TempDir(), "my.app")
+
+	abs, _ := filepath.Abs(root)
+	dir := filepath.Join(home, "projects", claudeProjectNameRe.ReplaceAllString(abs, "-"))
+	writeFile(t, filepath.Join(dir, "a.jsonl"),
+		`{"type":"user","sessionId":"a","cwd":"`+filepath.ToSlash(abs)+`","timestamp":"2025-06-01T12:00:00Z","message":{"role":"user","content":"`+strings.Repeat("long prompt ", 20)+`"}}`,
+		`{"type":"assistant","sessionId":"a","timestamp":"2025-06-01T12:05:00Z","message":{"id":"m","role":"assistant","content":[{"type":"text","text":"ok"}]}}`)
+	// Same encoded directory, different workspace ("my-app" encodes like "my.app").
+	writeFile(t, filepath.Join(dir, "b.jsonl"),
+		`{"type":"user","sessionId":"b","cwd":"/elsewhere","message":{"role":"user","content":"hi"}}`)
+	writeFile(t, filepath.Join(dir, "empty.jsonl"), `{"type":"summary","summary":"nothing"}`)
+
+	got, err := Load("claude-code", root, "auto")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Load() returned %d transcripts, want 1", len(got))
+	}
+	tr := got[0]
+	if tr.Source() != "claude-code:a" {
+		t.Errorf("Source() = %q", tr.Source())
+	}
+	if tr.EndedAt.Sub(tr.StartedAt) != 5*time.Minute {
+		t.Errorf("time range = %v - %v", tr.StartedAt, tr.EndedAt)
+	}
+	if len([]rune(tr.Summary)) != 120 || !strings.HasSuffix(tr.Summary, "...") {
+		t.Errorf("Summary = %q, want first prompt truncated to 120 runes", tr.Summary)
+	}
+}
+
+func TestLookupUnknownAgent(t *testing.T) {
+	if _, err := Lookup("copilot"); err == nil {
+		t.Error("expected error for unknown agent")
+	}
+	if _, err := Load("claude-code", t.TempDir(), filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func createCursorDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustExec(t, db, `CREATE TABLE ItemTable (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB)`)
+	mustExec(t, db, `CREATE TABLE cursorDiskKV (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB)`)
+	return db
+}
+
+func mustExec(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.ExecContext(context.Background(), query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
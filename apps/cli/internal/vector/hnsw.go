@@ -0,0 +1,384 @@
+package vector
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// HNSW parameters. M bounds the neighbours kept per node on upper layers
+// (2*M on layer 0); efConstruction and efSearch trade build and query time
+// for recall.
+const (
+	hnswM              = 16
+	hnswEfConstruction = 100
+	hnswEfSearch       = 64
+	hnswFormatVersion  = 1
+)
+
+// graphNode is a vector in the graph. Vectors are stored normalized so that
+// cosine similarity is a dot product.
+type graphNode struct {
+	ID      string
+	Vector  []float32
+	Friends [][]int32 // neighbour indices per layer, 0..level
+	Deleted bool
+}
+
+// Graph is a Hierarchical Navigable Small World graph for approximate
+// nearest-neighbour search by cosine similarity. It is not safe for
+// concurrent use; callers guard it with their own lock.
+type Graph struct {
+	nodes    []*graphNode
+	byID     map[string]int32
+	entry    int32
+	maxLevel int
+	deleted  int
+	levelMul float64
+	rng      *rand.Rand
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		byID:     make(map[string]int32),
+		entry:    -1,
+		levelMul: 1 / math.Log(hnswM),
+		rng:      rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of live vectors in the graph.
+func (g *Graph) Len() int {
+	return len(g.byID)
+}
+
+// Has reports whether a live vector with the given ID exists.
+func (g *Graph) Has(id string) bool {
+	_, ok := g.byID[id]
+	return ok
+}
+
+// Add inserts a vector, replacing any previous vector with the same ID.
+func (g *Graph) Add(id string, vector []float32) {
+	g.Remove(id)
+
+	vec := Normalize(vector)
+	if vec == nil {
+		return
+	}
+
+	level := int(math.Floor(-math.Log(1-g.rng.Float64()) * g.levelMul))
+	node := &graphNode{ID: id, Vector: vec, Friends: make([][]int32, level+1)}
+	idx := int32(len(g.nodes))
+	g.nodes = append(g.nodes, node)
+	g.byID[id] = idx
+
+	if g.entry < 0 {
+		g.entry = idx
+		g.maxLevel = level
+		return
+	}
+
+	// Greedy descent through layers above the new node's level.
+	cur := g.entry
+	for l := g.maxLevel; l > level; l-- {
+		cur = g.greedyClosest(vec, cur, l)
+	}
+
+	entries := []int32{cur}
+	for l := min(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(vec, entries, hnswEfConstruction, l)
+		neighbours := g.selectNeighbours(candidates, hnswM)
+		node.Friends[l] = neighbours
+
+		for _, n := range neighbours {
+			g.link(n, idx, l)
+		}
+
+		entries = entries[:0]
+		for _, c := range candidates {
+			entries = append(entries, c.index)
+		}
+	}
+
+	if level > g.maxLevel {
+		g.entry = idx
+		g.maxLevel = level
+	}
+}
+
+// Remove marks the vector with the given ID as deleted. Deleted nodes still
+// route searches but are never returned.
+func (g *Graph) Remove(id string) {
+	idx, ok := g.byID[id]
+	if !ok {
+		return
+	}
+	g.nodes[idx].Deleted = true
+	delete(g.byID, id)
+	g.deleted++
+}
+
+// Fragmented reports whether enough nodes are deleted that a rebuild pays off.
+func (g *Graph) Fragmented() bool {
+	return g.deleted > 0 && g.deleted*2 > len(g.nodes)
+}
+
+// Match is a vector found by Search.
+type Match struct {
+	ID         string
+	Similarity float32
+}
+
+// Search returns up to k live vectors most similar to query, most similar
+// first.
+func (g *Graph) Search(query []float32, k int) []Match {
+	vec := Normalize(query)
+	if vec == nil || g.entry < 0 || k <= 0 {
+		return nil
+	}
+
+	cur := g.entry
+	for l := g.maxLevel; l > 0; l-- {
+		cur = g.greedyClosest(vec, cur, l)
+	}
+
+	ef := max(hnswEfSearch, k)
+	candidates := g.searchLayer(vec, []int32{cur}, ef, 0)
+
+	results := make([]Match, 0, k)
+	for _, c := range candidates {
+		n := g.nodes[c.index]
+		if n.Deleted {
+			continue
+		}
+		results = append(results, Match{ID: n.ID, Similarity: c.similarity})
+		if len(results) == k {
+			break
+		}
+	}
+	return results
+}
+
+// IDs returns the IDs of all live vectors.
+func (g *Graph) IDs() []string {
+	ids := make([]string, 0, len(g.byID))
+	for id := range g.byID {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Compact returns a new graph built from the live vectors only.
+func (g *Graph) Compact() *Graph {
+	out := NewGraph()
+	for _, n := range g.nodes {
+		if !n.Deleted {
+			out.Add(n.ID, n.Vector)
+		}
+	}
+	return out
+}
+
+// candidate is a node index with its similarity to the query.
+type candidate struct {
+	index      int32
+	similarity float32
+}
+
+func (g *Graph) similarity(vec []float32, idx int32) float32 {
+	other := g.nodes[idx].Vector
+	if len(other) != len(vec) {
+		return -1
+	}
+	var dot float32
+	for i := range vec {
+		dot += vec[i] * other[i]
+	}
+	return dot
+}
+
+// greedyClosest walks layer l from entry towards the node closest to vec.
+func (g *Graph) greedyClosest(vec []float32, entry int32, l int) int32 {
+	cur := entry
+	best := g.similarity(vec, cur)
+	for changed := true; changed; {
+		changed = false
+		for _, n := range g.nodes[cur].Friends[l] {
+			if s := g.similarity(vec, n); s > best {
+				cur, best, changed = n, s, true
+			}
+		}
+	}
+	return cur
+}
+
+// searchLayer runs a best-first search on layer l and returns up to ef
+// candidates sorted by descending similarity.
+func (g *Graph) searchLayer(vec []float32, entries []int32, ef, l int) []candidate {
+	visited := make(map[int32]bool, ef*4)
+	frontier := &candidateHeap{max: true}
+	found := &candidateHeap{}
+
+	for _, e := range entries {
+		if visited[e] {
+			continue
+		}
+		visited[e] = true
+		c := candidate{index: e, similarity: g.similarity(vec, e)}
+		heap.Push(frontier, c)
+		heap.Push(found, c)
+	}
+
+	for frontier.Len() > 0 {
+		c := heap.Pop(frontier).(candidate)
+		if found.Len() >= ef && c.similarity < found.items[0].similarity {
+			break
+		}
+		node := g.nodes[c.index]
+		if l >= len(node.Friends) {
+			continue
+		}
+		for _, n := range node.Friends[l] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			s := g.similarity(vec, n)
+			if found.Len() < ef || s > found.items[0].similarity {
+				heap.Push(frontier, candidate{index: n, similarity: s})
+				heap.Push(found, candidate{index: n, similarity: s})
+				if found.Len() > ef {
+					heap.Pop(found)
+				}
+			}
+		}
+	}
+
+	result := found.items
+	sort.Slice(result, func(i, j int) bool { return result[i].similarity > result[j].similarity })
+	return result
+}
+
+// selectNeighbours keeps the m most similar candidates.
+func (g *Graph) selectNeighbours(candidates []candidate, m int) []int32 {
+	out := make([]int32, 0, m)
+	for _, c := range candidates {
+		if len(out) == m {
+			break
+		}
+		out = append(out, c.index)
+	}
+	return out
+}
+
+// link adds to as a neighbour of from on layer l, pruning from's neighbour
+// list back to its maximum size if needed.
+func (g *Graph) link(from, to int32, l int) {
+	node := g.nodes[from]
+	node.Friends[l] = append(node.Friends[l], to)
+
+	limit := hnswM
+	if l == 0 {
+		limit = 2 * hnswM
+	}
+	if len(node.Friends[l]) <= limit {
+		return
+	}
+
+	candidates := make([]candidate, len(node.Friends[l]))
+	for i, n := range node.Friends[l] {
+		candidates[i] = candidate{index: n, similarity: g.similarity(node.Vector, n)}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].similarity > candidates[j].similarity })
+	node.Friends[l] = g.selectNeighbours(candidates, limit)
+}
+
+// candidateHeap is a min-heap on similarity, or a max-heap when max is set.
+type candidateHeap struct {
+	items []candidate
+	max   bool
+}
+
+func (h *candidateHeap) Len() int { return len(h.items) }
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.max {
+		return h.items[i].similarity > h.items[j].similarity
+	}
+	return h.items[i].similarity < h.items[j].similarity
+}
+func (h *candidateHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *candidateHeap) Push(x interface{}) { h.items = append(h.items, x.(candidate)) }
+func (h *candidateHeap) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
+
+// graphFile is the on-disk form of a graph.
+type graphFile struct {
+	Version  int
+	Nodes    []*graphNode
+	Entry    int32
+	MaxLevel int
+}
+
+// Save writes the graph to path atomically.
+func (g *Graph) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	data := graphFile{Version: hnswFormatVersion, Nodes: g.nodes, Entry: g.entry, MaxLevel: g.maxLevel}
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("encode index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadGraph reads a graph written by Save.
+func LoadGraph(path string) (*Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var data graphFile
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode index: %w", err)
+	}
+	if data.Version != hnswFormatVersion {
+		return nil, fmt.Errorf("unsupported index version %d", data.Version)
+	}
+
+	g := NewGraph()
+	g.nodes = data.Nodes
+	g.entry = data.Entry
+	g.maxLevel = data.MaxLevel
+	for i, n := range g.nodes {
+		if n.Deleted {
+			g.deleted++
+			continue
+		}
+		g.byID[n.ID] = int32(i)
+	}
+	return g, nil
+}
@@ -0,0 +1,123 @@
+package vector
+
+import (
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func randomVectors(n, dim int, seed int64) [][]float32 {
+	rng := rand.New(rand.NewSource(seed))
+	vecs := make([][]float32, n)
+	for i := range vecs {
+		vecs[i] = make([]float32, dim)
+		for j := range vecs[i] {
+			vecs[i][j] = rng.Float32()*2 - 1
+		}
+	}
+	return vecs
+}
+
+func vectorID(i int) string {
+	return "l_" + string(rune('a'+i%26)) + string(rune('a'+i/26%26)) + string(rune('a'+i/676))
+}
+
+func TestHNSWRecall(t *testing.T) {
+	const n, dim, k = 2000, 32, 10
+	vecs := randomVectors(n, dim, 1)
+
+	g := NewGraph()
+	for i, v := range vecs {
+		g.Add(vectorID(i), v)
+	}
+	if g.Len() != n {
+		t.Fatalf("Len() = %d, want %d", g.Len(), n)
+	}
+
+	queries := randomVectors(50, dim, 2)
+	hits := 0
+	for _, q := range queries {
+		type scored struct {
+			id  string
+			sim float32
+		}
+		exact := make([]scored, n)
+		for i, v := range vecs {
+			exact[i] = scored{vectorID(i), Cosine(q, v)}
+		}
+		sort.Slice(exact, func(i, j int) bool { return exact[i].sim > exact[j].sim })
+
+		want := map[string]bool{}
+		for _, s := range exact[:k] {
+			want[s.id] = true
+		}
+		for _, m := range g.Search(q, k) {
+			if want[m.ID] {
+				hits++
+			}
+		}
+	}
+
+	recall := float64(hits) / float64(len(queries)*k)
+	if recall < 0.9 {
+		t.Errorf("recall@%d = %.2f, want >= 0.9", k, recall)
+	}
+}
+
+func TestHNSWRemoveAndReplace(t *testing.T) {
+	g := NewGraph()
+	g.Add("a", []float32{1, 0, 0})
+	g.Add("b", []float32{0, 1, 0})
+	g.Add("c", []float32{0.9, 0.1, 0})
+
+	g.Remove("a")
+	results := g.Search([]float32{1, 0, 0}, 3)
+	if len(results) != 2 || results[0].ID != "c" {
+		t.Errorf("after remove, results = %+v", results)
+	}
+
+	// Re-adding an ID replaces its vector.
+	g.Add("b", []float32{1, 0, 0})
+	results = g.Search([]float32{1, 0, 0}, 1)
+	if len(results) != 1 || results[0].ID != "b" {
+		t.Errorf("after replace, top result = %+v", results)
+	}
+	if g.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", g.Len())
+	}
+
+	// Zero vectors cannot be compared and are not indexed.
+	g.Add("zero", []float32{0, 0, 0})
+	if g.Has("zero") {
+		t.Error("zero vector should not be indexed")
+	}
+}
+
+func TestHNSWSaveLoad(t *testing.T) {
+	g := NewGraph()
+	for i, v := range randomVectors(200, 8, 3) {
+		g.Add(vectorID(i), v)
+	}
+	g.Remove(vectorID(0))
+
+	path := filepath.Join(t.TempDir(), "learning.hnsw")
+	if err := g.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadGraph(path)
+	if err != nil {
+		t.Fatalf("LoadGraph() error = %v", err)
+	}
+	if loaded.Len() != 199 || loaded.Has(vectorID(0)) {
+		t.Errorf("loaded Len() = %d, has removed = %v", loaded.Len(), loaded.Has(vectorID(0)))
+	}
+
+	q := randomVectors(1, 8, 4)[0]
+	a, b := g.Search(q, 5), loaded.Search(q, 5)
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			t.Fatalf("search differs after reload: %v vs %v", a, b)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package vector stores and compares embedding vectors: the on-disk encoding
+// shared by memory.db and the code index, cosine similarity, and an HNSW
+// graph for approximate nearest-neighbour search.
+package vector
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Encode converts a vector to little-endian float32 bytes for storage.
+func Encode(v []float32) []byte {
+	buf := make([]byte, len(v)*4)
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+// Decode converts bytes written by Encode back to a vector. It returns nil
+// for empty input or a length that is not a multiple of four.
+func Decode(b []byte) []float32 {
+	if len(b) == 0 || len(b)%4 != 0 {
+		return nil
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return v
+}
+
+// Cosine computes the cosine similarity between two vectors. Vectors of
+// different lengths, empty vectors and zero vectors have similarity 0.
+func Cosine(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// Normalize returns a unit-length copy of v, or nil for a zero vector.
+func Normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return nil
+	}
+	inv := float32(1 / math.Sqrt(norm))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x * inv
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat32Conversion(t *testing.T) {
+	original := []float32{0.123456, -0.789012, 1.234567, -2.345678}
+
+	bytes := Encode(original)
+	recovered := Decode(bytes)
+
+	if len(recovered) != len(original) {
+		t.Fatalf("Length mismatch: %d vs %d", len(recovered), len(original))
+	}
+
+	for i := range original {
+		if math.Abs(float64(original[i]-recovered[i])) > 0.000001 {
+			t.Errorf("Index %d: expected %f, got %f", i, original[i], recovered[i])
+		}
+	}
+}
+
+func TestBytesToFloat32sEmpty(t *testing.T) {
+	result := Decode(nil)
+	if result != nil {
+		t.Error("Expected nil for empty input")
+	}
+
+	result = Decode([]byte{})
+	if result != nil {
+		t.Error("Expected nil for empty slice")
+	}
+}
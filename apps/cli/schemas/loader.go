@@ -21,7 +21,7 @@ var (
 func getCompiler() (*jsonschema.Compiler, error) {
 	compileOnce.Do(func() {
 		c := jsonschema.NewCompiler()
-		for _, name := range []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary} {
+		for _, name := range []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary, PatternDetector} {
 			filePath := schemaPath(name)
 			data, err := schemaFS.ReadFile(filePath)
 			if err != nil {
@@ -44,13 +44,14 @@ func getCompiler() (*jsonschema.Compiler, error) {
 }
 
 const (
-	Palace         = "palace"
-	Room           = "room"
-	Playbook       = "playbook"
-	ContextPack    = "context-pack"
-	ChangeSignal   = "change-signal"
-	ProjectProfile = "project-profile"
-	ScanSummary    = "scan"
+	Palace          = "palace"
+	Room            = "room"
+	Playbook        = "playbook"
+	ContextPack     = "context-pack"
+	ChangeSignal    = "change-signal"
+	ProjectProfile  = "project-profile"
+	ScanSummary     = "scan"
+	PatternDetector = "pattern-detector"
 )
 
 func schemaPath(name string) string {
@@ -74,7 +75,7 @@ func Compile(name string) (*jsonschema.Schema, error) {
 }
 
 func List() (map[string][]byte, error) {
-	names := []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary}
+	names := []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary, PatternDetector}
 	out := make(map[string][]byte, len(names))
 	for _, n := range names {
 		path := schemaPath(n)
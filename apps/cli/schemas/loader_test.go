@@ -45,6 +45,11 @@ func TestCompile(t *testing.T) {
 			schemaName: ScanSummary,
 			wantErr:    false,
 		},
+		{
+			name:       "compile pattern-detector schema",
+			schemaName: PatternDetector,
+			wantErr:    false,
+		},
 		{
 			name:       "compile non-existent schema",
 			schemaName: "nonexistent",
@@ -77,7 +82,7 @@ func TestList(t *testing.T) {
 		t.Fatalf("List() error: %v", err)
 	}
 
-	expectedSchemas := []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary}
+	expectedSchemas := []string{Palace, Room, Playbook, ContextPack, ChangeSignal, ProjectProfile, ScanSummary, PatternDetector}
 	for _, name := range expectedSchemas {
 		data, ok := schemas[name]
 		if !ok {